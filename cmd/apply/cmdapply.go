@@ -10,11 +10,15 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"k8s.io/kubectl/pkg/util/i18n"
 	"sigs.k8s.io/cli-utils/cmd/flagutils"
 	"sigs.k8s.io/cli-utils/pkg/apply"
+	"sigs.k8s.io/cli-utils/pkg/apply/backup"
+	"sigs.k8s.io/cli-utils/pkg/backoff"
+	"sigs.k8s.io/cli-utils/pkg/capabilities"
 	"sigs.k8s.io/cli-utils/pkg/common"
 	"sigs.k8s.io/cli-utils/pkg/inventory"
 	"sigs.k8s.io/cli-utils/pkg/manifestreader"
@@ -40,6 +44,8 @@ func GetRunner(factory cmdutil.Factory, invFactory inventory.ClientFactory,
 		"If true, apply merge patch is calculated on API server instead of client.")
 	cmd.Flags().BoolVar(&r.serverSideOptions.ForceConflicts, "force-conflicts", false,
 		"If true, overwrite applied fields on server if field manager conflict.")
+	cmd.Flags().BoolVar(&r.serverSideOptions.ReportForcedConflicts, "report-forced-conflicts", false,
+		"If true, report the fields overwritten with --force-conflicts. Requires an extra dry-run apply of every object.")
 	cmd.Flags().StringVar(&r.serverSideOptions.FieldManager, "field-manager", common.DefaultFieldManager,
 		"The client owner of the fields being applied on the server-side.")
 
@@ -47,12 +53,32 @@ func GetRunner(factory cmdutil.Factory, invFactory inventory.ClientFactory,
 		fmt.Sprintf("Output format, must be one of %s", strings.Join(printers.SupportedPrinters(), ",")))
 	cmd.Flags().DurationVar(&r.reconcileTimeout, "reconcile-timeout", time.Duration(0),
 		"Timeout threshold for waiting for all resources to reach the Current status.")
+	cmd.Flags().BoolVar(&r.reconcileSuspended, "reconcile-suspended", false,
+		"If true, do not wait for suspended resources, like paused Deployments, to reach the Current status.")
+	cmd.Flags().BoolVar(&r.stopOnReconcileFailure, "stop-on-reconcile-failure", false,
+		"If true, stop after a wait phase in which resources failed to reconcile or timed out, instead of applying the remaining phases.")
 	cmd.Flags().BoolVar(&r.noPrune, "no-prune", r.noPrune,
 		"If true, do not prune previously applied objects.")
 	cmd.Flags().StringVar(&r.prunePropagationPolicy, "prune-propagation-policy",
 		"Background", "Propagation policy for pruning")
+	cmd.Flags().Int64Var(&r.pruneGracePeriod, "prune-grace-period", -1,
+		"Grace period for pruning, in seconds. If negative, the default grace period of each resource is used.")
 	cmd.Flags().DurationVar(&r.pruneTimeout, "prune-timeout", time.Duration(0),
 		"Timeout threshold for waiting for all pruned resources to be deleted")
+	cmd.Flags().StringSliceVar(&r.pruneNamespaces, "prune-namespace", nil,
+		"Namespace of the namespaced resources that can be pruned. May be repeated. If not set, resources in all namespaces can be pruned.")
+	cmd.Flags().StringSliceVar(&r.pruneKinds, "prune-kind", nil,
+		"Kind (Kind.group) of the resources that can be pruned. May be repeated. If not set, resources of all kinds can be pruned.")
+	cmd.Flags().BoolVar(&r.pruneNamespacedOnly, "prune-namespaced-only", false,
+		"If true, do not prune cluster-scoped resources.")
+	cmd.Flags().DurationVar(&r.pruneDelay, "prune-delay", time.Duration(0),
+		"If set, mark the resources removed from the package for deletion, and only prune them on a later run, once this delay has elapsed since the mark.")
+	cmd.Flags().DurationVar(&r.pruneStuckThreshold, "prune-stuck-threshold", time.Duration(0),
+		"How long to wait for pruned resources to be deleted, before reporting the remaining finalizers of the resources that are still terminating")
+	cmd.Flags().BoolVar(&r.pruneRemoveStuckFinalizers, "prune-remove-stuck-finalizers", false,
+		"If true, forcibly remove the finalizers of pruned resources still terminating after the prune-stuck-threshold.")
+	cmd.Flags().BoolVar(&r.skipPruneWait, "skip-prune-wait", false,
+		"If true, do not wait for the last pruned resources to be deleted. Resources pruned before their dependencies are still waited on.")
 	cmd.Flags().StringVar(&r.inventoryPolicy, flagutils.InventoryPolicyFlag, flagutils.InventoryPolicyStrict,
 		"It determines the behavior when the resources don't belong to current inventory. Available options "+
 			fmt.Sprintf("%q, %q and %q.", flagutils.InventoryPolicyStrict, flagutils.InventoryPolicyAdopt, flagutils.InventoryPolicyForceAdopt))
@@ -60,6 +86,53 @@ func GetRunner(factory cmdutil.Factory, invFactory inventory.ClientFactory,
 		"How long to wait before exiting")
 	cmd.Flags().BoolVar(&r.printStatusEvents, "status-events", false,
 		"Print status events (always enabled for table output)")
+	cmd.Flags().IntVar(&r.applyConcurrency, "apply-concurrency", 1,
+		"Maximum number of objects applied in parallel within each apply phase.")
+	cmd.Flags().IntVar(&r.applyRetries, "apply-retries", 0,
+		"Number of times the apply of an object is retried after a transient error, like a conflict or throttling.")
+	cmd.Flags().StringSliceVar(&r.typedKinds, "typed-kinds", nil,
+		"Built-in kinds (Kind.group) server-side applied as typed objects with protobuf encoding, to reduce CPU and bandwidth usage. May be repeated.")
+	cmd.Flags().BoolVar(&r.skipUnchanged, "skip-unchanged", false,
+		fmt.Sprintf("If true, skip the apply of resources whose configuration is unchanged since the last apply, "+
+			"recorded with the %s annotation.", common.AppliedHashAnnotation))
+	cmd.Flags().BoolVar(&r.skipUnchangedWait, "skip-unchanged-wait", false,
+		"If true, do not wait for the resources skipped by --skip-unchanged to reconcile, if they are already current.")
+	cmd.Flags().BoolVar(&r.recreate, "recreate", false,
+		fmt.Sprintf("If true, delete and re-create resources whose update changes an immutable field. "+
+			"Resources can also opt in with the %s annotation.", common.RecreateAnnotation))
+	cmd.Flags().StringVar(&r.runID, "run-id", "",
+		"ID of the run in the events and logs. If empty, a random UUID is generated.")
+	cmd.Flags().BoolVar(&r.annotateRunID, "annotate-run-id", false,
+		fmt.Sprintf("If true, annotate the applied resources with the run ID (%s).", common.RunIDAnnotation))
+	cmd.Flags().StringVar(&r.requirements.MinServerVersion, "min-server-version", "",
+		"If set, fail before applying anything if the server is older than this version.")
+	cmd.Flags().StringSliceVar(&r.requirements.APIVersions, "require-api-version", nil,
+		"API version (group/version) that must be served by the server. May be repeated.")
+	cmd.Flags().StringSliceVar(&r.requirements.APIGroups, "require-api-group", nil,
+		"API group that must be served by the server, in any version. May be repeated.")
+	cmd.Flags().StringSliceVar(&r.requiredKinds, "require-kind", nil,
+		"Kind (Kind.group) not included in the package that must be served by the server. May be repeated.")
+	cmd.Flags().DurationVar(&r.requirementsTimeout, "requirements-timeout", time.Duration(0),
+		"Timeout threshold for waiting for the server to meet the requirements.")
+	cmd.Flags().BoolVar(&r.validateNamespaces, "validate-namespaces", false,
+		"If true, fail before applying anything if the namespace of a resource neither exists nor is in the package.")
+	cmd.Flags().BoolVar(&r.validateSchema, "validate-schema", false,
+		"If true, validate the resources against the OpenAPI schema of the server before applying anything, "+
+			"and report all the unknown fields and invalid values at once.")
+	cmd.Flags().StringVar(&r.backupDir, "backup-dir", "",
+		"If set, back up the live state of the resources to modify or prune in this directory, "+
+			"in a file named after the run ID, before applying anything.")
+	cmd.Flags().StringVar(&r.profileDir, "profile-dir", "",
+		"If set, write the CPU and heap profiles of the run, and the time spent in each phase and task, "+
+			"to this directory, in files named after the run ID.")
+	cmd.Flags().BoolVar(&r.verifyRelease, "verify-release", false,
+		"If true, apply the test resources after all the other resources, wait for them to complete, "+
+			"and delete them afterwards.")
+	cmd.Flags().BoolVar(&r.deferUnknownKinds, "defer-unknown-kinds", false,
+		"If true, apply the resources whose kind is not served by the server, nor defined by a CRD in the package, "+
+			"after all the other resources, like custom resources whose CRD is installed by an operator.")
+	cmd.Flags().DurationVar(&r.unknownKindsTimeout, "unknown-kinds-timeout", time.Duration(0),
+		"Timeout threshold for waiting for the kinds of the deferred resources to be served by the server.")
 
 	r.Command = cmd
 	return r
@@ -77,15 +150,43 @@ type Runner struct {
 	invFactory inventory.ClientFactory
 	loader     manifestreader.ManifestLoader
 
-	serverSideOptions      common.ServerSideOptions
-	output                 string
-	reconcileTimeout       time.Duration
-	noPrune                bool
-	prunePropagationPolicy string
-	pruneTimeout           time.Duration
-	inventoryPolicy        string
-	timeout                time.Duration
-	printStatusEvents      bool
+	serverSideOptions          common.ServerSideOptions
+	output                     string
+	reconcileTimeout           time.Duration
+	reconcileSuspended         bool
+	stopOnReconcileFailure     bool
+	noPrune                    bool
+	prunePropagationPolicy     string
+	pruneGracePeriod           int64
+	pruneTimeout               time.Duration
+	skipPruneWait              bool
+	pruneStuckThreshold        time.Duration
+	pruneRemoveStuckFinalizers bool
+	pruneNamespaces            []string
+	pruneKinds                 []string
+	pruneNamespacedOnly        bool
+	pruneDelay                 time.Duration
+	inventoryPolicy            string
+	timeout                    time.Duration
+	printStatusEvents          bool
+	requirements               capabilities.Requirements
+	requiredKinds              []string
+	requirementsTimeout        time.Duration
+	validateNamespaces         bool
+	validateSchema             bool
+	verifyRelease              bool
+	backupDir                  string
+	profileDir                 string
+	deferUnknownKinds          bool
+	unknownKindsTimeout        time.Duration
+	applyConcurrency           int
+	applyRetries               int
+	recreate                   bool
+	skipUnchanged              bool
+	skipUnchangedWait          bool
+	typedKinds                 []string
+	runID                      string
+	annotateRunID              bool
 }
 
 func (r *Runner) RunE(cmd *cobra.Command, args []string) error {
@@ -131,6 +232,22 @@ func (r *Runner) RunE(cmd *cobra.Command, args []string) error {
 	}
 	inv := inventory.WrapInventoryInfoObj(invObj)
 
+	// The requirements declared by the package are merged with the flags.
+	requirements, err := capabilities.ReadRequirements(invObj)
+	if err != nil {
+		return err
+	}
+	flagRequirements := r.requirements
+	for _, kind := range r.requiredKinds {
+		flagRequirements.Kinds = append(flagRequirements.Kinds, schema.ParseGroupKind(kind))
+	}
+	requirements = requirements.Merge(flagRequirements)
+
+	var pruneKinds []schema.GroupKind
+	for _, kind := range r.pruneKinds {
+		pruneKinds = append(pruneKinds, schema.ParseGroupKind(kind))
+	}
+
 	invClient, err := r.invFactory.NewClient(r.factory)
 	if err != nil {
 		return err
@@ -138,9 +255,15 @@ func (r *Runner) RunE(cmd *cobra.Command, args []string) error {
 
 	// Run the applier. It will return a channel where we can receive updates
 	// to keep track of progress and any issues.
+	var typedKinds []schema.GroupKind
+	for _, kind := range r.typedKinds {
+		typedKinds = append(typedKinds, schema.ParseGroupKind(kind))
+	}
 	a, err := apply.NewApplierBuilder().
 		WithFactory(r.factory).
 		WithInventoryClient(invClient).
+		WithRetryPolicy(backoff.RetryPolicy{MaxAttempts: r.applyRetries + 1}).
+		WithTypedClientKinds(typedKinds...).
 		Build()
 	if err != nil {
 		return err
@@ -151,17 +274,47 @@ func (r *Runner) RunE(cmd *cobra.Command, args []string) error {
 		r.printStatusEvents = true
 	}
 
+	var backupStore backup.Store
+	if r.backupDir != "" {
+		backupStore = &backup.DirectoryStore{Dir: r.backupDir}
+	}
+
 	ch := a.Run(ctx, inv, objs, apply.ApplierOptions{
-		ServerSideOptions: r.serverSideOptions,
-		ReconcileTimeout:  r.reconcileTimeout,
+		ServerSideOptions:      r.serverSideOptions,
+		ReconcileTimeout:       r.reconcileTimeout,
+		ReconcileSuspended:     r.reconcileSuspended,
+		StopOnReconcileFailure: r.stopOnReconcileFailure,
 		// If we are not waiting for status, tell the applier to not
 		// emit the events.
-		EmitStatusEvents:       r.printStatusEvents,
-		NoPrune:                r.noPrune,
-		DryRunStrategy:         common.DryRunNone,
-		PrunePropagationPolicy: prunePropPolicy,
-		PruneTimeout:           r.pruneTimeout,
-		InventoryPolicy:        inventoryPolicy,
+		EmitStatusEvents:               r.printStatusEvents,
+		NoPrune:                        r.noPrune,
+		DryRunStrategy:                 common.DryRunNone,
+		PrunePropagationPolicy:         prunePropPolicy,
+		PruneGracePeriodSeconds:        flagutils.ConvertGracePeriod(r.pruneGracePeriod),
+		PruneTimeout:                   r.pruneTimeout,
+		SkipPruneWait:                  r.skipPruneWait,
+		PruneStuckDeletionThreshold:    r.pruneStuckThreshold,
+		PruneRemoveStuckFinalizers:     r.pruneRemoveStuckFinalizers,
+		PruneNamespaces:                r.pruneNamespaces,
+		PruneGroupKinds:                pruneKinds,
+		PruneNamespacedOnly:            r.pruneNamespacedOnly,
+		PruneDelay:                     r.pruneDelay,
+		InventoryPolicy:                inventoryPolicy,
+		Requirements:                   requirements,
+		RequirementsTimeout:            r.requirementsTimeout,
+		ValidateNamespaces:             r.validateNamespaces,
+		ValidateSchema:                 r.validateSchema,
+		VerifyRelease:                  r.verifyRelease,
+		BackupStore:                    backupStore,
+		ProfileDir:                     r.profileDir,
+		DeferUnknownKinds:              r.deferUnknownKinds,
+		UnknownKindsTimeout:            r.unknownKindsTimeout,
+		ApplyConcurrency:               r.applyConcurrency,
+		RecreateOnImmutableFieldChange: r.recreate,
+		SkipUnchanged:                  r.skipUnchanged,
+		SkipUnchangedWait:              r.skipUnchangedWait,
+		RunID:                          r.runID,
+		AnnotateRunID:                  r.annotateRunID,
 	})
 
 	// The printer will print updates from the channel. It will block
@@ -44,12 +44,26 @@ func GetRunner(factory cmdutil.Factory, invFactory inventory.ClientFactory,
 			fmt.Sprintf("%q, %q and %q.", flagutils.InventoryPolicyStrict, flagutils.InventoryPolicyAdopt, flagutils.InventoryPolicyForceAdopt))
 	cmd.Flags().DurationVar(&r.deleteTimeout, "delete-timeout", time.Duration(0),
 		"Timeout threshold for waiting for all deleted resources to complete deletion")
+	cmd.Flags().DurationVar(&r.deleteStuckThreshold, "delete-stuck-threshold", time.Duration(0),
+		"How long to wait for deleted resources to be removed, before reporting the remaining finalizers of the resources that are still terminating")
+	cmd.Flags().BoolVar(&r.deleteRemoveStuckFinalizers, "delete-remove-stuck-finalizers", false,
+		"If true, forcibly remove the finalizers of deleted resources still terminating after the delete-stuck-threshold.")
+	cmd.Flags().BoolVar(&r.skipDeleteWait, "skip-delete-wait", false,
+		"If true, do not wait for the last deleted resources to complete deletion. Resources deleted before their dependencies are still waited on.")
 	cmd.Flags().StringVar(&r.deletePropagationPolicy, "delete-propagation-policy",
 		"Background", "Propagation policy for deletion")
+	cmd.Flags().Int64Var(&r.deleteGracePeriod, "delete-grace-period", -1,
+		"Grace period for deletion, in seconds. If negative, the default grace period of each resource is used.")
 	cmd.Flags().DurationVar(&r.timeout, "timeout", 0,
 		"How long to wait before exiting")
 	cmd.Flags().BoolVar(&r.printStatusEvents, "status-events", false,
 		"Print status events (always enabled for table output)")
+	cmd.Flags().BoolVar(&r.keepInventory, "keep-inventory", false,
+		"If true, retain the inventory object and its namespace after all the resources have been deleted")
+	cmd.Flags().BoolVar(&r.keepInventoryNamespace, "keep-inventory-namespace", false,
+		"If true, retain the namespace of the inventory object, even if it was applied with the resources")
+	cmd.Flags().StringVar(&r.runID, "run-id", "",
+		"ID of the run in the events and logs. If empty, a random UUID is generated.")
 
 	r.Command = cmd
 	return r
@@ -69,12 +83,19 @@ type Runner struct {
 	invFactory inventory.ClientFactory
 	loader     manifestreader.ManifestLoader
 
-	output                  string
-	deleteTimeout           time.Duration
-	deletePropagationPolicy string
-	inventoryPolicy         string
-	timeout                 time.Duration
-	printStatusEvents       bool
+	output                      string
+	deleteTimeout               time.Duration
+	skipDeleteWait              bool
+	deleteStuckThreshold        time.Duration
+	deleteRemoveStuckFinalizers bool
+	deletePropagationPolicy     string
+	deleteGracePeriod           int64
+	inventoryPolicy             string
+	timeout                     time.Duration
+	printStatusEvents           bool
+	keepInventory               bool
+	keepInventoryNamespace      bool
+	runID                       string
 }
 
 func (r *Runner) RunE(cmd *cobra.Command, args []string) error {
@@ -134,10 +155,17 @@ func (r *Runner) RunE(cmd *cobra.Command, args []string) error {
 	// Run the destroyer. It will return a channel where we can receive updates
 	// to keep track of progress and any issues.
 	ch := d.Run(ctx, inv, apply.DestroyerOptions{
-		DeleteTimeout:           r.deleteTimeout,
-		DeletePropagationPolicy: deletePropPolicy,
-		InventoryPolicy:         inventoryPolicy,
-		EmitStatusEvents:        r.printStatusEvents,
+		DeleteTimeout:                r.deleteTimeout,
+		SkipDeleteWait:               r.skipDeleteWait,
+		DeleteStuckDeletionThreshold: r.deleteStuckThreshold,
+		DeleteRemoveStuckFinalizers:  r.deleteRemoveStuckFinalizers,
+		DeletePropagationPolicy:      deletePropPolicy,
+		DeleteGracePeriodSeconds:     flagutils.ConvertGracePeriod(r.deleteGracePeriod),
+		InventoryPolicy:              inventoryPolicy,
+		EmitStatusEvents:             r.printStatusEvents,
+		KeepInventory:                r.keepInventory,
+		KeepInventoryNamespace:       r.keepInventoryNamespace,
+		RunID:                        r.runID,
 	})
 
 	// The printer will print updates from the channel. It will block
@@ -4,45 +4,241 @@
 package diff
 
 import (
+	"errors"
 	"os"
 
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 	"k8s.io/klog/v2"
-	"k8s.io/kubectl/pkg/cmd/diff"
-	"k8s.io/kubectl/pkg/cmd/util"
+	kubectldiff "k8s.io/kubectl/pkg/cmd/diff"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/utils/exec"
+	"sigs.k8s.io/cli-utils/cmd/flagutils"
+	"sigs.k8s.io/cli-utils/pkg/clusterstate"
 	"sigs.k8s.io/cli-utils/pkg/common"
+	"sigs.k8s.io/cli-utils/pkg/diff"
+	"sigs.k8s.io/cli-utils/pkg/inventory"
+	"sigs.k8s.io/cli-utils/pkg/manifestreader"
 )
 
-const tmpDirPrefix = "diff-cmd"
-
-// NewCommand returns cobra command to implement client-side diff of package
-// directory. For each local config file, get the resource in the cluster
-// and diff the local config resource against the resource in the cluster.
-func NewCommand(f util.Factory, ioStreams genericiooptions.IOStreams) *cobra.Command {
-	options := diff.NewDiffOptions(ioStreams)
+// GetRunner creates and returns the Runner which stores the cobra command.
+func GetRunner(factory cmdutil.Factory, invFactory inventory.ClientFactory,
+	loader manifestreader.ManifestLoader, ioStreams genericiooptions.IOStreams) *Runner {
+	r := &Runner{
+		factory:    factory,
+		invFactory: invFactory,
+		loader:     loader,
+		ioStreams:  ioStreams,
+	}
 	cmd := &cobra.Command{
 		Use:                   "diff (DIRECTORY | STDIN)",
 		DisableFlagsInUseLine: true,
 		Short:                 i18n.T("Diff local config against cluster applied version"),
 		Args:                  cobra.MaximumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
-			cleanupFunc, err := Initialize(options, f, args)
-			defer cleanupFunc()
-			util.CheckErr(err)
-			util.CheckErr(options.Run())
+			// Like diff(1) and kubectl diff, exit with code 1 if there
+			// are differences, and with a greater code on errors.
+			err := r.RunE(cmd, args)
+			if errors.Is(err, cmdutil.ErrExit) {
+				cmdutil.CheckErr(err)
+			}
+			cmdutil.CheckDiffErr(err)
 		},
 	}
+	// Flag errors must not exit with code 1, which means that there are
+	// differences.
+	cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		cmdutil.CheckDiffErr(cmdutil.UsageErrorf(cmd, "%s", err.Error()))
+		return nil
+	})
+	cmd.Flags().StringVar(&r.snapshotDir, "snapshot-dir", "",
+		"If set, diff against the objects recorded in the YAML and JSON files of this directory, "+
+			"like the output of 'kubectl get -o yaml', instead of the cluster.")
+	cmd.Flags().BoolVar(&r.prune, "prune", false,
+		"If true, also diff the objects of the inventory that would be pruned.")
+
+	r.Command = cmd
+	return r
+}
+
+// Command creates the Runner, returning the cobra command associated with it.
+func Command(f cmdutil.Factory, invFactory inventory.ClientFactory, loader manifestreader.ManifestLoader,
+	ioStreams genericiooptions.IOStreams) *cobra.Command {
+	return GetRunner(f, invFactory, loader, ioStreams).Command
+}
+
+// NewCommand returns the cobra command of the diff command, reading the
+// inventory from the cluster without status.
+//
+// Deprecated: Use Command or GetRunner, which accept the inventory client
+// factory and the manifest loader.
+func NewCommand(f cmdutil.Factory, ioStreams genericiooptions.IOStreams) *cobra.Command {
+	invFactory := inventory.ClusterClientFactory{StatusPolicy: inventory.StatusPolicyNone}
+	return Command(f, invFactory, manifestreader.NewManifestLoader(f), ioStreams)
+}
+
+// Runner encapsulates data necessary to run the diff command.
+type Runner struct {
+	Command    *cobra.Command
+	factory    cmdutil.Factory
+	invFactory inventory.ClientFactory
+	loader     manifestreader.ManifestLoader
+	ioStreams  genericiooptions.IOStreams
+
+	snapshotDir string
+	prune       bool
+}
+
+// RunE is the function run from the cobra command. For each local config
+// object, except the inventory object, it gets the object in the cluster and
+// prints the diff between the object in the cluster and the result of
+// applying the local config object. With a snapshot directory, the objects
+// are read from the snapshot instead of the cluster. If the
+// KUBECTL_EXTERNAL_DIFF environment variable is set, the objects are
+// compared with that program instead, like kubectl diff does. Returns
+// cmdutil.ErrExit if there are differences.
+func (r *Runner) RunE(cmd *cobra.Command, args []string) error {
+	if _, err := common.DemandOneDirectory(args); err != nil {
+		return err
+	}
+	reader, err := r.loader.ManifestReader(cmd.InOrStdin(), flagutils.PathFromArgs(args))
+	if err != nil {
+		return err
+	}
+	objs, err := reader.Read()
+	if err != nil {
+		return err
+	}
+	// We do not want to diff the inventory object.
+	invObj, objs, err := inventory.SplitUnstructureds(objs)
+	if err != nil {
+		return err
+	}
+
+	differ, err := r.differ()
+	if err != nil {
+		return err
+	}
+	diffs, err := differ.Diff(cmd.Context(), objs)
+	if err != nil {
+		return err
+	}
+	if r.prune {
+		inv := inventory.WrapInventoryInfoObj(invObj)
+		pruneDiffs, err := differ.DiffPrune(cmd.Context(), inv, objs)
+		if err != nil {
+			return err
+		}
+		diffs = append(diffs, pruneDiffs...)
+	}
+	if os.Getenv(externalDiffEnv) != "" {
+		return r.runExternalDiff(diffs)
+	}
+	if err := diff.Render(r.ioStreams.Out, diffs); err != nil {
+		return err
+	}
+	for _, d := range diffs {
+		if d.HasChanges() {
+			return cmdutil.ErrExit
+		}
+	}
+	return nil
+}
+
+// externalDiffEnv is the environment variable that sets the program used to
+// compare the objects, like for kubectl diff.
+const externalDiffEnv = "KUBECTL_EXTERNAL_DIFF"
+
+// runExternalDiff prints the live and merged objects to the files of two
+// temporary directories, and compares the directories with the diff program
+// of kubectl diff. Returns cmdutil.ErrExit if the program reports
+// differences.
+func (r *Runner) runExternalDiff(diffs []diff.ObjectDiff) error {
+	differ, err := kubectldiff.NewDiffer("LIVE", "MERGED")
+	if err != nil {
+		return err
+	}
+	defer differ.TearDown()
+
+	printer := kubectldiff.Printer{}
+	for _, d := range diffs {
+		name := d.Identifier.String()
+		if err := differ.From.Print(name, runtimeObject(d.Live), printer); err != nil {
+			return err
+		}
+		if err := differ.To.Print(name, runtimeObject(d.Merged), printer); err != nil {
+			return err
+		}
+	}
+	err = differ.Run(&kubectldiff.DiffProgram{
+		Exec:      exec.New(),
+		IOStreams: r.ioStreams,
+	})
+	var exitErr exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitStatus() == 1 {
+		return cmdutil.ErrExit
+	}
+	return err
+}
+
+// runtimeObject returns the object as a runtime.Object, or nil if the
+// object is nil, so that it is printed as an empty file.
+func runtimeObject(obj *unstructured.Unstructured) runtime.Object {
+	if obj == nil {
+		return nil
+	}
+	return obj
+}
 
-	return cmd
+// differ returns a Differ reading the live objects from the snapshot, if
+// any, or from the cluster.
+func (r *Runner) differ() (*diff.Differ, error) {
+	if r.snapshotDir != "" {
+		source, err := clusterstate.LoadSnapshot(r.snapshotDir)
+		if err != nil {
+			return nil, err
+		}
+		return &diff.Differ{Source: source}, nil
+	}
+	client, err := r.factory.DynamicClient()
+	if err != nil {
+		return nil, err
+	}
+	mapper, err := r.factory.ToRESTMapper()
+	if err != nil {
+		return nil, err
+	}
+	differ := &diff.Differ{
+		Client: client,
+		Mapper: mapper,
+	}
+	if r.prune {
+		invClient, err := r.invFactory.NewClient(r.factory)
+		if err != nil {
+			return nil, err
+		}
+		differ.Source = &clusterstate.ClusterSource{
+			Client:    client,
+			Mapper:    mapper,
+			InvClient: invClient,
+		}
+	}
+	return differ, nil
 }
 
+const tmpDirPrefix = "diff-cmd"
+
 // Initialize fills in the DiffOptions in preparation for DiffOptions.Run().
 // Returns a cleanup function for removing temp files after expanding stdin, or
 // error if there is an error filling in the options or if there
 // is not one argument that is a directory.
-func Initialize(o *diff.DiffOptions, f util.Factory, args []string) (func(), error) {
+//
+// Deprecated: The diff command no longer uses the kubectl DiffOptions. Use
+// Command or GetRunner instead.
+func Initialize(o *kubectldiff.DiffOptions, f cmdutil.Factory, args []string) (func(), error) {
 	cleanupFunc := func() {}
 	// Validate the only argument is a (package) directory path.
 	filenameFlags, err := common.DemandOneDirectory(args)
@@ -51,7 +247,7 @@ func Initialize(o *diff.DiffOptions, f util.Factory, args []string) (func(), err
 	}
 	// Process input from stdin
 	if len(args) == 0 {
-		tmpDir, err := createTempDir()
+		tmpDir, err := os.MkdirTemp("", tmpDirPrefix)
 		if err != nil {
 			return cleanupFunc, err
 		}
@@ -93,13 +289,3 @@ func Initialize(o *diff.DiffOptions, f util.Factory, args []string) (func(), err
 
 	return cleanupFunc, nil
 }
-
-func createTempDir() (string, error) {
-	// Create a temporary file with the passed prefix in
-	// the default temporary directory.
-	tmpDir, err := os.MkdirTemp("", tmpDirPrefix)
-	if err != nil {
-		return "", err
-	}
-	return tmpDir, nil
-}
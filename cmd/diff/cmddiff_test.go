@@ -0,0 +1,115 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/cli-utils/pkg/inventory"
+	"sigs.k8s.io/cli-utils/pkg/manifestreader"
+)
+
+var (
+	inventoryTemplate = `
+kind: ConfigMap
+apiVersion: v1
+metadata:
+  labels:
+    cli-utils.sigs.k8s.io/inventory-id: test
+  name: inventory
+  namespace: default
+`
+	localConfigMap = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: foo
+  namespace: default
+data:
+  a: "2"
+`
+	liveConfigMap = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: foo
+  namespace: default
+data:
+  a: "1"
+`
+)
+
+func TestRunE(t *testing.T) {
+	testCases := map[string]struct {
+		live         string
+		externalDiff string
+		expectedErr  error
+		expectedOut  string
+	}{
+		"differences": {
+			live:        liveConfigMap,
+			expectedErr: cmdutil.ErrExit,
+			expectedOut: `--- live/default_foo__ConfigMap
++++ merged/default_foo__ConfigMap
+@@ -1,6 +1,6 @@
+ apiVersion: v1
+ data:
+-  a: "1"
++  a: "2"
+ kind: ConfigMap
+ metadata:
+   name: foo
+`,
+		},
+		"no differences": {
+			live: localConfigMap,
+		},
+		"differences reported by the external diff": {
+			live:         localConfigMap,
+			externalDiff: "false",
+			expectedErr:  cmdutil.ErrExit,
+		},
+		"no differences reported by the external diff": {
+			live:         liveConfigMap,
+			externalDiff: "true",
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			t.Setenv(externalDiffEnv, tc.externalDiff)
+
+			pkgDir := t.TempDir()
+			writeFile(t, filepath.Join(pkgDir, "inventory-template.yaml"), inventoryTemplate)
+			writeFile(t, filepath.Join(pkgDir, "configmap.yaml"), localConfigMap)
+			snapshotDir := t.TempDir()
+			writeFile(t, filepath.Join(snapshotDir, "configmap.yaml"), tc.live)
+
+			tf := cmdtesting.NewTestFactory().WithNamespace("default")
+			defer tf.Cleanup()
+
+			out := &bytes.Buffer{}
+			ioStreams := genericiooptions.IOStreams{In: &bytes.Buffer{}, Out: out, ErrOut: &bytes.Buffer{}}
+			invFactory := inventory.ClusterClientFactory{StatusPolicy: inventory.StatusPolicyNone}
+			r := GetRunner(tf, invFactory, manifestreader.NewManifestLoader(tf), ioStreams)
+			r.snapshotDir = snapshotDir
+
+			err := r.RunE(r.Command, []string{pkgDir})
+			assert.Equal(t, tc.expectedErr, err)
+			assert.Equal(t, tc.expectedOut, out.String())
+		})
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+}
@@ -36,6 +36,16 @@ func ConvertPropagationPolicy(propagationPolicy string) (metav1.DeletionPropagat
 	}
 }
 
+// ConvertGracePeriod converts a grace period flag, in seconds, to the grace
+// period passed into the Applier or Destroyer. A negative grace period means
+// the default grace period of each object.
+func ConvertGracePeriod(seconds int64) *int64 {
+	if seconds < 0 {
+		return nil
+	}
+	return &seconds
+}
+
 func ConvertInventoryPolicy(policy string) (inventory.Policy, error) {
 	switch policy {
 	case InventoryPolicyStrict:
@@ -50,3 +50,15 @@ func TestConvertInventoryPolicy(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertGracePeriod(t *testing.T) {
+	if gracePeriod := ConvertGracePeriod(-1); gracePeriod != nil {
+		t.Errorf("expected nil but got %d", *gracePeriod)
+	}
+	for _, seconds := range []int64{0, 30} {
+		gracePeriod := ConvertGracePeriod(seconds)
+		if gracePeriod == nil || *gracePeriod != seconds {
+			t.Errorf("expected %d but got %v", seconds, gracePeriod)
+		}
+	}
+}
@@ -70,7 +70,7 @@ func main() {
 		initcmd.NewCmdInit(f, ioStreams),
 		apply.Command(f, invFactory, loader, ioStreams),
 		destroy.Command(f, invFactory, loader, ioStreams),
-		diff.NewCommand(f, ioStreams),
+		diff.Command(f, invFactory, loader, ioStreams),
 		preview.Command(f, invFactory, loader, ioStreams),
 		status.Command(context.TODO(), f, invFactory, status.NewInventoryLoader(loader)),
 	}
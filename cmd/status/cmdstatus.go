@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"k8s.io/kubectl/pkg/util/slice"
@@ -22,6 +23,7 @@ import (
 	"sigs.k8s.io/cli-utils/pkg/kstatus/polling"
 	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/aggregator"
 	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/collector"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/engine"
 	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/event"
 	"sigs.k8s.io/cli-utils/pkg/kstatus/status"
 	"sigs.k8s.io/cli-utils/pkg/manifestreader"
@@ -49,12 +51,12 @@ var (
 func GetRunner(ctx context.Context, factory cmdutil.Factory,
 	invFactory inventory.ClientFactory, loader Loader) *Runner {
 	r := &Runner{
-		ctx:               ctx,
-		factory:           factory,
-		invFactory:        invFactory,
-		loader:            loader,
-		PollerFactoryFunc: pollerFactoryFunc,
+		ctx:        ctx,
+		factory:    factory,
+		invFactory: invFactory,
+		loader:     loader,
 	}
+	r.PollerFactoryFunc = r.pollerFactoryFunc
 	c := &cobra.Command{
 		Use:     "status (DIRECTORY | STDIN)",
 		PreRunE: r.preRunE,
@@ -62,6 +64,12 @@ func GetRunner(ctx context.Context, factory cmdutil.Factory,
 	}
 	c.Flags().DurationVar(&r.period, "poll-period", 2*time.Second,
 		"Polling period for resource statuses.")
+	c.Flags().IntVar(&r.periodObjects, "poll-period-objects", 0,
+		"If set, stretch the polling period linearly when polling more than this number of resources.")
+	c.Flags().DurationVar(&r.maxPeriod, "max-poll-period", 0,
+		"Maximum polling period when it is stretched by --poll-period-objects. Zero means no maximum.")
+	c.Flags().StringSliceVar(&r.typedKinds, "typed-kinds", nil,
+		"Built-in kinds (Kind.group) read as typed objects with protobuf encoding, to reduce CPU and bandwidth usage. May be repeated.")
 	c.Flags().StringVar(&r.pollUntil, "poll-until", "known",
 		"When to stop polling. Must be one of 'known', 'current', 'deleted', or 'forever'.")
 	c.Flags().StringVar(&r.output, "output", "events", "Output format.")
@@ -90,10 +98,13 @@ type Runner struct {
 	invFactory inventory.ClientFactory
 	loader     Loader
 
-	period    time.Duration
-	pollUntil string
-	timeout   time.Duration
-	output    string
+	period        time.Duration
+	periodObjects int
+	maxPeriod     time.Duration
+	typedKinds    []string
+	pollUntil     string
+	timeout       time.Duration
+	output        string
 
 	invType          string
 	inventoryNames   string
@@ -296,9 +307,13 @@ func (r *Runner) runE(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("unknown value for pollUntil: %q", r.pollUntil)
 	}
 
-	eventChannel := statusPoller.Poll(ctx, printData.Identifiers, polling.PollOptions{
+	pollOptions := polling.PollOptions{
 		PollInterval: r.period,
-	})
+	}
+	if r.periodObjects > 0 {
+		pollOptions.PollIntervalFunc = engine.ScaledPollInterval(r.periodObjects, r.maxPeriod)
+	}
+	eventChannel := statusPoller.Poll(ctx, printData.Identifiers, pollOptions)
 
 	return printer.Print(eventChannel, printData.Identifiers, cancelFunc)
 }
@@ -334,8 +349,12 @@ func allKnownNotifierFunc(cancelFunc context.CancelFunc) collector.ObserverFunc
 	}
 }
 
-func pollerFactoryFunc(f cmdutil.Factory) (poller.Poller, error) {
-	return polling.NewStatusPollerFromFactory(f, polling.Options{})
+func (r *Runner) pollerFactoryFunc(f cmdutil.Factory) (poller.Poller, error) {
+	var typedKinds []schema.GroupKind
+	for _, kind := range r.typedKinds {
+		typedKinds = append(typedKinds, schema.ParseGroupKind(kind))
+	}
+	return polling.NewStatusPollerFromFactory(f, polling.Options{TypedKinds: typedKinds})
 }
 
 type Loader interface {
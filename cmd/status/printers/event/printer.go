@@ -13,6 +13,7 @@ import (
 	"sigs.k8s.io/cli-utils/pkg/common"
 	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/collector"
 	pollevent "sigs.k8s.io/cli-utils/pkg/kstatus/polling/event"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/status"
 	"sigs.k8s.io/cli-utils/pkg/object"
 	"sigs.k8s.io/cli-utils/pkg/print/list"
 	"sigs.k8s.io/cli-utils/pkg/printers/events"
@@ -77,8 +78,9 @@ func (ep *Printer) printStatusEvent(se pollevent.Event) error {
 		if _, ok := ep.Data.StatusSet[strings.ToLower(statusString)]; len(ep.Data.StatusSet) != 0 && !ok {
 			return nil
 		}
+		message := status.MessageWithDeletionAge(se.Resource.Status, se.Resource.Message, se.Resource.Resource)
 		_, err := fmt.Fprintf(ep.IOStreams.Out, "%s/%s/%s/%s is %s: %s\n", invName,
-			strings.ToLower(id.GroupKind.String()), id.Namespace, id.Name, statusString, se.Resource.Message)
+			strings.ToLower(id.GroupKind.String()), id.Namespace, id.Name, statusString, message)
 		return err
 	case pollevent.ErrorEvent:
 		return ep.Formatter.FormatErrorEvent(event.ErrorEvent{
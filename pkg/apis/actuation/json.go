@@ -0,0 +1,33 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package actuation
+
+import "sigs.k8s.io/cli-utils/pkg/jsonenum"
+
+// The enums are encoded in JSON by name, so that consumers of the JSON do
+// not depend on the order of the constants.
+
+func (x ActuationStrategy) MarshalJSON() ([]byte, error) {
+	return jsonenum.Marshal(x)
+}
+
+func (x *ActuationStrategy) UnmarshalJSON(data []byte) error {
+	return jsonenum.Unmarshal(data, x)
+}
+
+func (x ActuationStatus) MarshalJSON() ([]byte, error) {
+	return jsonenum.Marshal(x)
+}
+
+func (x *ActuationStatus) UnmarshalJSON(data []byte) error {
+	return jsonenum.Unmarshal(data, x)
+}
+
+func (x ReconcileStatus) MarshalJSON() ([]byte, error) {
+	return jsonenum.Marshal(x)
+}
+
+func (x *ReconcileStatus) UnmarshalJSON(data []byte) error {
+	return jsonenum.Unmarshal(data, x)
+}
@@ -5,29 +5,45 @@ package apply
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/google/uuid"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
 	"k8s.io/apimachinery/pkg/util/sets"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/klog/v2"
+	"k8s.io/kubectl/pkg/util/openapi"
+	kubectlvalidation "k8s.io/kubectl/pkg/validation"
 	"sigs.k8s.io/cli-utils/pkg/apis/actuation"
+	"sigs.k8s.io/cli-utils/pkg/apply/backup"
 	"sigs.k8s.io/cli-utils/pkg/apply/cache"
 	"sigs.k8s.io/cli-utils/pkg/apply/event"
 	"sigs.k8s.io/cli-utils/pkg/apply/filter"
 	"sigs.k8s.io/cli-utils/pkg/apply/info"
 	"sigs.k8s.io/cli-utils/pkg/apply/mutator"
 	"sigs.k8s.io/cli-utils/pkg/apply/prune"
+	"sigs.k8s.io/cli-utils/pkg/apply/readiness"
 	"sigs.k8s.io/cli-utils/pkg/apply/solver"
 	"sigs.k8s.io/cli-utils/pkg/apply/taskrunner"
+	"sigs.k8s.io/cli-utils/pkg/backoff"
+	"sigs.k8s.io/cli-utils/pkg/capabilities"
 	"sigs.k8s.io/cli-utils/pkg/common"
 	"sigs.k8s.io/cli-utils/pkg/inventory"
 	"sigs.k8s.io/cli-utils/pkg/kstatus/watcher"
+	"sigs.k8s.io/cli-utils/pkg/multierror"
 	"sigs.k8s.io/cli-utils/pkg/object"
 	"sigs.k8s.io/cli-utils/pkg/object/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // Applier performs the step of applying a set of resources into a cluster,
@@ -46,8 +62,20 @@ type Applier struct {
 	invClient     inventory.Client
 	client        dynamic.Interface
 	openAPIGetter discovery.OpenAPISchemaInterface
+	discoClient   discovery.DiscoveryInterface
 	mapper        meta.RESTMapper
 	infoHelper    info.Helper
+	retryPolicy   backoff.RetryPolicy
+	typedClient   client.Client
+	typedKinds    []schema.GroupKind
+	applyFilters  []filter.ValidationFilter
+	applyMutators []mutator.Interface
+}
+
+// Capabilities probes the features supported by the cluster, so callers can
+// gate features on what the server supports.
+func (a *Applier) Capabilities() (*capabilities.Capabilities, error) {
+	return capabilities.Probe(a.discoClient)
 }
 
 // prepareObjects returns the set of objects to apply and to prune or
@@ -63,9 +91,13 @@ func (a *Applier) prepareObjects(localInv inventory.Info, localObjs object.Unstr
 	// Add the inventory annotation to the resources being applied.
 	for _, localObj := range localObjs {
 		inventory.AddInventoryIDAnnotation(localObj, localInv)
+		if o.AnnotateRunID {
+			addRunIDAnnotation(localObj, o.RunID)
+		}
 	}
 	pruneObjs, err := a.pruner.GetPruneObjs(localInv, localObjs, prune.Options{
-		DryRunStrategy: o.DryRunStrategy,
+		DryRunStrategy:   o.DryRunStrategy,
+		FetchConcurrency: o.PruneFetchConcurrency,
 	})
 	if err != nil {
 		return nil, nil, err
@@ -82,34 +114,129 @@ func (a *Applier) prepareObjects(localInv inventory.Info, localObjs object.Unstr
 // cancellation or timeout will only affect how long we Wait for the
 // resources to become current.
 func (a *Applier) Run(ctx context.Context, invInfo inventory.Info, objects object.UnstructuredSet, options ApplierOptions) <-chan event.Event {
-	klog.V(4).Infof("apply run for %d objects", len(objects))
-	eventChannel := make(chan event.Event)
 	setDefaults(&options)
+	klog.V(4).Infof("apply run %s for %d objects", options.RunID, len(objects))
+	eventChannel := make(chan event.Event)
+	var prof *runProfiler
+	if options.ProfileDir != "" {
+		prof = newRunProfiler(options.ProfileDir, options.RunID)
+	}
 	go func() {
 		defer close(eventChannel)
+		if err := prof.start(); err != nil {
+			handleError(eventChannel, err)
+			return
+		}
+		var deadline time.Time
+		if options.Timeout > 0 {
+			deadline = time.Now().Add(options.Timeout)
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithDeadline(ctx, deadline)
+			defer cancel()
+		}
+		// Make sure the cluster supports the requested strategies and meets
+		// the requirements before anything is applied.
+		prof.startPhase("capabilities")
+		if err := a.waitForCapabilities(ctx, options); err != nil {
+			handleError(eventChannel, err)
+			return
+		}
+
+		// Name the resources with a generateName before they are
+		// identified.
+		prof.startPhase("validate")
+		objects = generateNames(objects)
+
 		// Validate the resources to make sure we catch those problems early
 		// before anything has been updated in the cluster.
 		vCollector := &validation.Collector{}
 		validator := &validation.Validator{
-			Collector: vCollector,
-			Mapper:    a.mapper,
+			Collector:         vCollector,
+			Mapper:            a.mapper,
+			StrictAnnotations: options.StrictAnnotations,
+			AllowUnknownKinds: options.DeferUnknownKinds,
+		}
+		if options.ValidateNamespaces {
+			validator.NamespaceExists = func(namespace string) (bool, error) {
+				return a.namespaceExists(ctx, namespace)
+			}
+		}
+		if options.ValidateSchema {
+			validator.SchemaValidator = kubectlvalidation.NewSchemaValidation(
+				openAPIResourcesGetter{parser: openapi.NewOpenAPIParser(a.openAPIGetter)})
 		}
 		validator.Validate(objects)
 
+		// Test resources are only applied to verify the release. Otherwise,
+		// they are pruned if left over from an earlier run.
+		if !options.VerifyRelease {
+			objects = withoutTestObjects(objects)
+		}
+
 		// Decide which objects to apply and which to prune
+		prof.startPhase("prepare")
 		applyObjs, pruneObjs, err := a.prepareObjects(invInfo, objects, options)
 		if err != nil {
 			handleError(eventChannel, err)
 			return
 		}
-		klog.V(4).Infof("calculated %d apply objs; %d prune objs", len(applyObjs), len(pruneObjs))
+		klog.V(4).Infof("calculated %d apply objs; %d prune objs (run: %s)", len(applyObjs), len(pruneObjs), options.RunID)
+		// Apply the objects of unknown kinds last, if allowed.
+		var deferredObjs object.UnstructuredSet
+		if options.DeferUnknownKinds {
+			applyObjs, deferredObjs = splitUnknownKinds(applyObjs, a.mapper)
+			klog.V(4).Infof("deferred %d objs of unknown kinds (run: %s)", len(deferredObjs), options.RunID)
+		}
+		// Fetch the recorded UIDs before the inventory is updated
+		invUIDs, err := inventoryUIDs(a.invClient, invInfo)
+		if err != nil {
+			handleError(eventChannel, err)
+			return
+		}
+
+		// Fetch the objects marked for deletion by the previous run.
+		var pruneMarked object.ObjMetadataSet
+		if options.PruneDelay > 0 {
+			pruneMarked, err = pruneMarkedObjects(a.invClient, invInfo)
+			if err != nil {
+				handleError(eventChannel, err)
+				return
+			}
+		}
+
+		// Record the state of the cluster, to roll back a failed apply.
+		var snapshot *rollbackSnapshot
+		if options.RollbackOnFailure && !options.DryRunStrategy.ClientOrServerDryRun() {
+			prof.startPhase("snapshot")
+			snapshot, err = a.takeRollbackSnapshot(ctx, invInfo, applyObjs)
+			if err != nil {
+				handleError(eventChannel, err)
+				return
+			}
+		}
+
+		// Back up the objects to modify, to restore them manually.
+		if options.BackupStore != nil && !options.DryRunStrategy.ClientOrServerDryRun() {
+			prof.startPhase("backup")
+			err = a.backupObjects(ctx, options.BackupStore, options.RunID, applyObjs, pruneObjs, snapshot)
+			if err != nil {
+				handleError(eventChannel, err)
+				return
+			}
+		}
 
 		// Build a TaskContext for passing info between tasks
+		prof.startPhase("build")
 		resourceCache := cache.NewResourceCacheMap()
 		taskContext := taskrunner.NewTaskContext(eventChannel, resourceCache)
+		taskContext.SetDeadline(deadline)
+		if options.CircuitBreakerThreshold > 0 {
+			taskContext.SetCircuitBreaker(taskrunner.NewCircuitBreaker(options.CircuitBreakerThreshold))
+		}
+		taskContext.SetTaskInjector(options.TaskInjector)
 
 		// Fetch the queue (channel) of tasks that should be executed.
-		klog.V(4).Infoln("applier building task queue...")
+		klog.V(4).Infof("applier building task queue (run: %s)...", options.RunID)
 		// Build list of apply validation filters.
 		applyFilters := []filter.ValidationFilter{
 			filter.InventoryPolicyApplyFilter{
@@ -117,16 +244,32 @@ func (a *Applier) Run(ctx context.Context, invInfo inventory.Info, objects objec
 				Mapper:    a.mapper,
 				Inv:       invInfo,
 				InvPolicy: options.InventoryPolicy,
-			},
-			filter.DependencyFilter{
-				TaskContext:       taskContext,
-				ActuationStrategy: actuation.ActuationStrategyApply,
-				DryRunStrategy:    options.DryRunStrategy,
+				// Filters are evaluated while holding the inventory lock
+				// of the apply task, which guards the task context.
+				OnAdopt: taskContext.AddAdoptedObject,
 			},
 		}
+		// Custom filters are evaluated before the dependency filter, so that
+		// objects are skipped with the reason of the custom filter, rather
+		// than because a dependency is not ready.
+		applyFilters = append(applyFilters, a.applyFilters...)
+		applyFilters = append(applyFilters, filter.DependencyFilter{
+			TaskContext:       taskContext,
+			ActuationStrategy: actuation.ActuationStrategyApply,
+			DryRunStrategy:    options.DryRunStrategy,
+		})
 		// Build list of prune validation filters.
 		pruneFilters := []filter.ValidationFilter{
+			filter.ProtectedFilter{Key: options.ProtectedKey},
+			filter.PruneScopeFilter{
+				Namespaces:     options.PruneNamespaces,
+				GroupKinds:     options.PruneGroupKinds,
+				NamespacedOnly: options.PruneNamespacedOnly,
+			},
 			filter.PreventRemoveFilter{},
+			filter.InventoryUIDFilter{
+				ExpectedUIDs: invUIDs,
+			},
 			filter.InventoryPolicyPruneFilter{
 				Inv:       invInfo,
 				InvPolicy: options.InventoryPolicy,
@@ -134,20 +277,35 @@ func (a *Applier) Run(ctx context.Context, invInfo inventory.Info, objects objec
 			filter.LocalNamespacesFilter{
 				LocalNamespaces: localNamespaces(invInfo, object.UnstructuredSetToObjMetadataSet(objects)),
 			},
-			filter.DependencyFilter{
-				TaskContext:       taskContext,
-				ActuationStrategy: actuation.ActuationStrategyDelete,
-				DryRunStrategy:    options.DryRunStrategy,
-			},
 		}
-		// Build list of apply mutators.
-		applyMutators := []mutator.Interface{
-			&mutator.ApplyTimeMutator{
-				Client:        a.client,
-				Mapper:        a.mapper,
-				ResourceCache: resourceCache,
-			},
+		// The prune delay is evaluated after the other filters, so that only
+		// the objects that would be pruned are marked for deletion.
+		if options.PruneDelay > 0 {
+			pruneFilters = append(pruneFilters, filter.PruneDelayFilter{
+				Delay:  options.PruneDelay,
+				Marked: pruneMarked,
+			})
+		}
+		pruneFilters = append(pruneFilters, filter.DependencyFilter{
+			TaskContext:       taskContext,
+			ActuationStrategy: actuation.ActuationStrategyDelete,
+			DryRunStrategy:    options.DryRunStrategy,
+		})
+		// Keep the objects to prune if the apply is rolled back.
+		if snapshot != nil {
+			pruneFilters = append(pruneFilters, filter.ApplyFailureFilter{
+				TaskContext: taskContext,
+			})
 		}
+		// Build list of apply mutators.
+		// Custom mutators are evaluated before the apply-time mutations, so
+		// that the substituted values are not overwritten.
+		applyMutators := append([]mutator.Interface{}, a.applyMutators...)
+		applyMutators = append(applyMutators, &mutator.ApplyTimeMutator{
+			Client:        a.client,
+			Mapper:        a.mapper,
+			ResourceCache: resourceCache,
+		})
 		taskBuilder := &solver.TaskQueueBuilder{
 			Pruner:        a.pruner,
 			DynamicClient: a.client,
@@ -159,27 +317,46 @@ func (a *Applier) Run(ctx context.Context, invInfo inventory.Info, objects objec
 			ApplyFilters:  applyFilters,
 			ApplyMutators: applyMutators,
 			PruneFilters:  pruneFilters,
+			TypedClient:   a.typedClient,
+			TypedKinds:    a.typedKinds,
 		}
 		opts := solver.Options{
-			ServerSideOptions:      options.ServerSideOptions,
-			ReconcileTimeout:       options.ReconcileTimeout,
-			Destroy:                false,
-			Prune:                  !options.NoPrune,
-			DryRunStrategy:         options.DryRunStrategy,
-			PrunePropagationPolicy: options.PrunePropagationPolicy,
-			PruneTimeout:           options.PruneTimeout,
-			InventoryPolicy:        options.InventoryPolicy,
+			ServerSideOptions:              options.ServerSideOptions,
+			ReconcileTimeout:               options.ReconcileTimeout,
+			ReconcileSuspended:             options.ReconcileSuspended,
+			StopOnReconcileFailure:         options.StopOnReconcileFailure,
+			ReadinessGates:                 options.ReadinessGates,
+			Destroy:                        false,
+			Prune:                          !options.NoPrune,
+			DryRunStrategy:                 options.DryRunStrategy,
+			PrunePropagationPolicy:         options.PrunePropagationPolicy,
+			PruneGracePeriodSeconds:        options.PruneGracePeriodSeconds,
+			PruneTimeout:                   options.PruneTimeout,
+			PruneSkipWait:                  options.SkipPruneWait,
+			PruneStuckDeletionThreshold:    options.PruneStuckDeletionThreshold,
+			PruneRemoveStuckFinalizers:     options.PruneRemoveStuckFinalizers,
+			InventoryPolicy:                options.InventoryPolicy,
+			PruneDeleteCollectionThreshold: options.PruneDeleteCollectionThreshold,
+			PruneListDependents:            options.PruneListDependents,
+			PruneDependentKinds:            options.PruneDependentKinds,
+			ApplyConcurrency:               options.ApplyConcurrency,
+			ApplyRetryPolicy:               a.retryPolicy,
+			RecreateOnImmutableFieldChange: options.RecreateOnImmutableFieldChange,
+			ApplySkipUnchanged:             options.SkipUnchanged,
+			ApplySkipUnchangedWait:         options.SkipUnchangedWait,
+			UnknownKindsTimeout:            options.UnknownKindsTimeout,
 		}
 
 		// Build the ordered set of tasks to execute.
 		taskQueue := taskBuilder.
 			WithApplyObjects(applyObjs).
 			WithPruneObjects(pruneObjs).
+			WithDeferredObjects(deferredObjs).
 			WithInventory(invInfo).
 			Build(taskContext, opts)
 
-		klog.V(4).Infof("validation errors: %d", len(vCollector.Errors))
-		klog.V(4).Infof("invalid objects: %d", len(vCollector.InvalidIDs))
+		klog.V(4).Infof("validation errors: %d (run: %s)", len(vCollector.Errors), options.RunID)
+		klog.V(4).Infof("invalid objects: %d (run: %s)", len(vCollector.InvalidIDs), options.RunID)
 
 		// Handle validation errors
 		switch options.ValidationPolicy {
@@ -212,25 +389,54 @@ func (a *Applier) Run(ctx context.Context, invInfo inventory.Info, objects objec
 			},
 		}
 		// Create a new TaskStatusRunner to execute the taskQueue.
-		klog.V(4).Infoln("applier building TaskStatusRunner...")
+		klog.V(4).Infof("applier building TaskStatusRunner (run: %s)...", options.RunID)
 		allIDs := object.UnstructuredSetToObjMetadataSet(append(applyObjs, pruneObjs...))
+		allIDs = append(allIDs, object.UnstructuredSetToObjMetadataSet(deferredObjs)...)
 		statusWatcher := a.statusWatcher
 		// Disable watcher for dry runs
 		if opts.DryRunStrategy.ClientOrServerDryRun() {
 			statusWatcher = watcher.BlindStatusWatcher{}
 		}
 		runner := taskrunner.NewTaskStatusRunner(allIDs, statusWatcher)
-		klog.V(4).Infoln("applier running TaskStatusRunner...")
+		klog.V(4).Infof("applier running TaskStatusRunner (run: %s)...", options.RunID)
+		prof.startPhase("run")
 		err = runner.Run(ctx, taskContext, taskQueue.ToChannel(), taskrunner.Options{
 			EmitStatusEvents:         options.EmitStatusEvents,
 			WatcherRESTScopeStrategy: options.WatcherRESTScopeStrategy,
 		})
+		// Roll back if any object failed to apply, or the run failed, unless
+		// it was cancelled.
+		applyErrs := taskContext.ApplyErrors()
+		if snapshot != nil && (len(applyErrs) > 0 || (err != nil && ctx.Err() == nil)) {
+			if err == nil {
+				err = multierror.New(applyErrs...)
+			}
+			klog.V(4).Infof("applier rolling back (run: %s): %v", options.RunID, err)
+			err = fmt.Errorf("apply rolled back: %w", err)
+			prof.startPhase("rollback")
+			if rollbackErrs := a.rollback(ctx, taskContext, snapshot, invInfo); len(rollbackErrs) > 0 {
+				err = multierror.Wrap(err, fmt.Errorf("rollback failed: %w", multierror.New(rollbackErrs...)))
+			}
+			handleError(eventChannel, err)
+			return
+		}
 		if err != nil {
 			handleError(eventChannel, err)
 			return
 		}
+		// Report the apply and prune failures, if any, as the result of the
+		// run.
+		if options.AggregateApplyErrors {
+			errs := applyErrs
+			if pruneErr := taskContext.PruneError(); pruneErr != nil {
+				errs = append(errs, pruneErr)
+			}
+			if len(errs) > 0 {
+				handleError(eventChannel, multierror.New(errs...))
+			}
+		}
 	}()
-	return eventChannel
+	return event.Buffer(event.WithRunID(prof.forward(eventChannel), options.RunID), options.EventBuffer)
 }
 
 type ApplierOptions struct {
@@ -242,6 +448,34 @@ type ApplierOptions struct {
 	// how long to wait.
 	ReconcileTimeout time.Duration
 
+	// ReconcileSuspended defines whether the applied resources that are
+	// Suspended, e.g. paused Deployments or suspended Jobs, are considered
+	// reconciled. Otherwise, the applier waits for them to be resumed, until
+	// the ReconcileTimeout.
+	ReconcileSuspended bool
+
+	// StopOnReconcileFailure defines whether the run should stop after a
+	// wait phase in which applied resources failed to reconcile, or did not
+	// reconcile before their timeout. By default, the run proceeds with the
+	// remaining phases, skipping the resources that depend on the ones that
+	// did not reconcile, and the Finished ActionGroupEvent of each wait
+	// phase reports its partial success in a WaitResult. If set, the run
+	// ends with an ErrorEvent whose error wraps a taskrunner.ReconcileError.
+	StopOnReconcileFailure bool
+
+	// ReadinessGates are checks outside the cluster, e.g. smoke tests of the
+	// applied Services, that the applied resources must pass, in addition
+	// to being Current, before they are considered reconciled. They are
+	// checked again until they pass, or the ReconcileTimeout, if any. They
+	// are not checked in dry-run.
+	ReadinessGates []readiness.Gate
+
+	// Timeout defines the deadline of the run, if any. Objects that are
+	// still reconciling at the deadline are reported as timed out, and the
+	// remaining tasks are not run. A deadline of the context of the run
+	// instead cancels the run, without reporting timed out objects.
+	Timeout time.Duration
+
 	// EmitStatusEvents defines whether status events should be
 	// emitted on the eventChannel to the caller.
 	EmitStatusEvents bool
@@ -259,20 +493,238 @@ type ApplierOptions struct {
 	// default is to use the Background policy.
 	PrunePropagationPolicy metav1.DeletionPropagation
 
+	// PruneGracePeriodSeconds defines the grace period of the prune
+	// deletions, in seconds. If this is not provided, the default grace
+	// period of each object is used. The deletion-propagation and
+	// deletion-grace-period-seconds annotations override the propagation
+	// policy and grace period of individual objects.
+	PruneGracePeriodSeconds *int64
+
 	// PruneTimeout defines whether we should wait for all resources
 	// to be fully deleted after pruning, and if so, how long we should
 	// wait.
 	PruneTimeout time.Duration
 
+	// PruneNamespaces restricts pruning to the namespaced objects in these
+	// namespaces, e.g. the namespaces of a partial apply. All the namespaces
+	// if empty. The objects outside the prune scope are skipped and kept in
+	// the inventory.
+	PruneNamespaces []string
+
+	// PruneGroupKinds restricts pruning to the objects of these GroupKinds.
+	// All the GroupKinds if empty.
+	PruneGroupKinds []schema.GroupKind
+
+	// PruneNamespacedOnly prevents pruning cluster-scoped objects.
+	PruneNamespacedOnly bool
+
+	// ProtectedKey is the label or annotation key that protects objects from
+	// being pruned, when set to "true". Protected objects are skipped and
+	// kept in the inventory. Defaults to common.ProtectedKey.
+	ProtectedKey string
+
+	// PruneDelay delays the prune of the objects removed from the package.
+	// The first run that would prune an object marks it for deletion with
+	// the common.PruneMarkedAtAnnotation annotation, skips it with the
+	// PruneDelayed skip reason, and keeps it in the inventory. A later run
+	// prunes the object once the delay has elapsed since the mark, if it is
+	// still absent from the package. Objects applied again in the meantime
+	// are marked again when they are removed. This protects the objects
+	// from transient bugs in the generation of the package. Zero prunes the
+	// objects immediately.
+	//
+	// The mark requires an inventory client that records the status of the
+	// objects (inventory.StatusClient). Otherwise, objects are never pruned.
+	PruneDelay time.Duration
+
+	// SkipPruneWait defines whether the applier should not wait for the
+	// pruned objects to be deleted, e.g. until their finalizers have run,
+	// after the delete requests are accepted. The objects pruned before
+	// their dependencies are still waited on, so that the dependencies are
+	// not deleted first.
+	SkipPruneWait bool
+
+	// PruneStuckDeletionThreshold defines how long to wait for the pruned
+	// objects to be deleted, before sending a StuckDeletionEvent with the
+	// remaining finalizers of every object that is still terminating. Zero
+	// disables the report.
+	PruneStuckDeletionThreshold time.Duration
+
+	// PruneRemoveStuckFinalizers defines whether the finalizers of the
+	// objects reported by a StuckDeletionEvent are forcibly removed. This
+	// skips the cleanup of the controllers of the finalizers, and should
+	// only be used when these controllers are known to be gone.
+	PruneRemoveStuckFinalizers bool
+
+	// PruneFetchConcurrency is the maximum number of prune candidates
+	// retrieved in parallel from the cluster, when calculating the prune set.
+	// Zero uses prune.DefaultFetchConcurrency.
+	PruneFetchConcurrency int
+
+	// PruneDeleteCollectionThreshold is the minimum number of pruned objects
+	// with the same GroupKind and namespace that are deleted with a single
+	// DeleteCollection request, instead of one request per object. The
+	// request is only used if the objects share labels that select exactly
+	// these objects. Zero disables the batching.
+	//
+	// DeleteCollection has no UID precondition, so an object deleted and
+	// re-created with the same name and labels while the batch is deleted
+	// is deleted too, even if it is not in the inventory.
+	PruneDeleteCollectionThreshold int
+
+	// PruneListDependents defines whether the dependents of each pruned
+	// object are listed before it is deleted, and reported on its
+	// PruneEvent, and in the Change of a Preview. The dependents are the
+	// objects owned by the pruned object, directly or indirectly through
+	// their ownerReferences, like the ReplicaSets and Pods of a Deployment,
+	// which the garbage collector deletes with it.
+	PruneListDependents bool
+
+	// PruneDependentKinds are the kinds of the listed dependents. If empty,
+	// prune.DefaultDependentKinds is used.
+	PruneDependentKinds []schema.GroupKind
+
 	// InventoryPolicy defines the inventory policy of apply.
 	InventoryPolicy inventory.Policy
 
 	// ValidationPolicy defines how to handle invalid objects.
 	ValidationPolicy validation.Policy
 
+	// StrictAnnotations enables validation of the annotations that affect
+	// the behavior of cli-utils. Misspelled keys and unsupported values are
+	// handled according to the ValidationPolicy.
+	StrictAnnotations bool
+
+	// ValidateNamespaces enables validation of the namespaces of the
+	// namespaced objects. Objects whose namespace neither exists in the
+	// cluster nor is in the set are invalid, and handled according to the
+	// ValidationPolicy. Namespaces that the caller is not allowed to read
+	// are assumed to exist.
+	ValidateNamespaces bool
+
+	// ValidateSchema enables the validation of the objects against the
+	// OpenAPI schema of the cluster before they are applied, e.g. to reject
+	// unknown fields and values of the wrong type. Invalid objects are
+	// handled according to the ValidationPolicy, with all the violations of
+	// each object reported on a single validation event.
+	ValidateSchema bool
+
+	// BackupStore, if set, stores the live state of the objects to apply that
+	// exist, and of the objects to prune, before anything is modified, as
+	// the backup of the run (by RunID). The run fails if the backup can not
+	// be stored. Use backup.Restore to restore the objects of a backup.
+	BackupStore backup.Store
+
+	// ProfileDir, if set, is the directory to write the CPU and heap
+	// profiles of the run to, along with the time spent in each phase and
+	// task of the run, named after the RunID: <RunID>.cpu.pprof,
+	// <RunID>.heap.pprof and <RunID>.timings.json. Only one run can be
+	// profiled at a time in a process.
+	ProfileDir string
+
+	// VerifyRelease enables the test resources, with the "test" apply-hook
+	// annotation. They are applied after all the other resources, waited on
+	// until they complete, and then deleted, whether they succeeded or not.
+	VerifyRelease bool
+
 	// RESTScopeStrategy specifies which strategy to use when listing and
 	// watching resources. By default, the strategy is selected automatically.
 	WatcherRESTScopeStrategy watcher.RESTScopeStrategy
+
+	// CircuitBreakerThreshold is the number of consecutive server errors
+	// (5xx) after which the run is aborted. Zero disables the circuit breaker.
+	CircuitBreakerThreshold int
+
+	// TaskInjector is called with every event of the run. The returned
+	// tasks run after the current task, e.g. to remediate a failed apply.
+	TaskInjector taskrunner.TaskInjector
+
+	// ApplyConcurrency is the maximum number of objects applied in parallel
+	// within each apply phase. The phases are still applied in order. Zero
+	// or one applies the objects sequentially.
+	ApplyConcurrency int
+
+	// AggregateApplyErrors defines whether the run should end with an error
+	// aggregating the causes of all the failed applies and prunes. Objects
+	// that fail to apply or prune never stop the run: each failure is
+	// reported on an ApplyFailed or PruneFailed event, the remaining objects
+	// are still applied and pruned, and the objects that depend on a failed
+	// object are skipped. Objects that failed to prune are kept in the
+	// inventory, so the next run prunes them again. By default, the run ends
+	// without error, and the caller has to inspect the events. If set, the
+	// run ends with an ErrorEvent whose error is a MultiError, with an
+	// ObjectApplyError for each object that failed to apply, and a
+	// PruneError if any object failed to prune.
+	AggregateApplyErrors bool
+
+	// RollbackOnFailure defines whether the applier should roll back the run
+	// if any object fails to apply. Before applying, the applier records the
+	// live state of the objects to apply. Once an object failed to apply,
+	// nothing is pruned. After all tasks are done, the applied objects are
+	// restored to their recorded state, the objects created by the run are
+	// deleted, and the inventory is restored. A RollbackEvent is sent for
+	// each object, and the run ends with an ErrorEvent. Ignored for dry runs.
+	RollbackOnFailure bool
+
+	// RecreateOnImmutableFieldChange defines whether objects should be
+	// deleted and re-created when an update is rejected because it changes
+	// an immutable field, like the selector of a Job. By default, only the
+	// objects with the recreate annotation set to "true" are re-created.
+	// Ignored for dry runs.
+	RecreateOnImmutableFieldChange bool
+
+	// SkipUnchanged defines whether the applier should record the hash of
+	// each applied object in an annotation, and skip the apply of the
+	// objects whose live hash is unchanged. The live objects are still read,
+	// but not written. Changes made to the live objects by others are not
+	// reverted for skipped objects. Ignored for dry runs.
+	SkipUnchanged bool
+
+	// SkipUnchangedWait defines whether the applier should not wait for the
+	// objects skipped by SkipUnchanged to reconcile, if their live status
+	// is already Current. This shortens the wait of incremental updates,
+	// but does not verify that the unchanged objects stay reconciled.
+	// Ignored unless SkipUnchanged is set.
+	SkipUnchangedWait bool
+
+	// DeferUnknownKinds defines whether the objects whose kind is neither
+	// served by the cluster nor defined by a CRD in the set should be
+	// applied after all the other objects have been applied and pruned,
+	// instead of being invalid. This supports kinds whose CRDs are installed
+	// at runtime, like by an operator applied in the same run. The deferred
+	// objects are kept in the inventory, but not waited on.
+	DeferUnknownKinds bool
+
+	// UnknownKindsTimeout defines how long to wait for the kinds of the
+	// deferred objects to be served by the cluster, before applying them.
+	// Objects whose kind is still unknown fail to apply.
+	UnknownKindsTimeout time.Duration
+
+	// EventBuffer configures the buffering of the returned event channel,
+	// and what happens to events when the buffer is full. By default, the
+	// channel is unbuffered and the run blocks until each event is received.
+	EventBuffer event.BufferOptions
+
+	// Requirements are the minimum server version, the API versions and the
+	// kinds the objects require. If the cluster does not meet them, the run fails
+	// before anything is applied, with an UnmetRequirementsError listing
+	// the unmet requirements. The requirements declared by the annotations
+	// of the inventory object are not read by the Applier; use
+	// capabilities.ReadRequirements to add them.
+	Requirements capabilities.Requirements
+
+	// RequirementsTimeout defines whether the applier should wait for the
+	// cluster to meet the Requirements, like for an operator to install its
+	// CRDs, and if so, how long to wait.
+	RequirementsTimeout time.Duration
+
+	// RunID identifies the run in the events and logs. If this is not
+	// provided, a random UUID is generated.
+	RunID string
+
+	// AnnotateRunID defines whether the applied objects should be annotated
+	// with the RunID, to correlate changes in the cluster with the run.
+	AnnotateRunID bool
 }
 
 // setDefaults set the options to the default values if they
@@ -281,6 +733,193 @@ func setDefaults(o *ApplierOptions) {
 	if o.PrunePropagationPolicy == "" {
 		o.PrunePropagationPolicy = metav1.DeletePropagationBackground
 	}
+	if o.RunID == "" {
+		o.RunID = uuid.NewString()
+	}
+}
+
+// openAPIResourcesGetter lazily parses the OpenAPI schema of the cluster,
+// when the first object is validated.
+type openAPIResourcesGetter struct {
+	parser *openapi.CachedOpenAPIParser
+}
+
+// OpenAPISchema implements openapi.OpenAPIResourcesGetter.
+func (g openAPIResourcesGetter) OpenAPISchema() (openapi.Resources, error) {
+	return g.parser.Parse()
+}
+
+// namespaceExists returns true if the namespace exists in the cluster, or if
+// the caller is not allowed to read it.
+func (a *Applier) namespaceExists(ctx context.Context, namespace string) (bool, error) {
+	client, err := a.resourceClient(object.ObjMetadata{
+		GroupKind: schema.GroupKind{Kind: "Namespace"},
+		Name:      namespace,
+	})
+	if err != nil {
+		return false, err
+	}
+	_, err = client.Get(ctx, namespace, metav1.GetOptions{})
+	switch {
+	case err == nil:
+		return true, nil
+	case apierrors.IsNotFound(err):
+		return false, nil
+	case apierrors.IsForbidden(err):
+		klog.V(4).Infof("unable to check namespace %q: %v", namespace, err)
+		return true, nil
+	default:
+		return false, err
+	}
+}
+
+// maxGeneratedNameLength is the maximum length of the generateName prefix,
+// to leave room for the random suffix, like the API server.
+const maxGeneratedNameLength = utilvalidation.DNS1123LabelMaxLength - generatedNameSuffixLength
+
+// generatedNameSuffixLength is the length of the random suffix of the
+// generated names.
+const generatedNameSuffixLength = 5
+
+// generateNames returns the objects, with a name generated for the objects
+// that have a generateName but no name, like the API server does when they
+// are created, so that they are identified, sorted and recorded in the
+// inventory like the other objects. The names are set on copies of the
+// objects, so that a new object is created by every run, and the objects
+// created by earlier runs are pruned.
+func generateNames(objs object.UnstructuredSet) object.UnstructuredSet {
+	named := make(object.UnstructuredSet, 0, len(objs))
+	for _, obj := range objs {
+		if obj.GetName() != "" || obj.GetGenerateName() == "" {
+			named = append(named, obj)
+			continue
+		}
+		base := obj.GetGenerateName()
+		if len(base) > maxGeneratedNameLength {
+			base = base[:maxGeneratedNameLength]
+		}
+		obj = obj.DeepCopy()
+		obj.SetName(base + utilrand.String(generatedNameSuffixLength))
+		klog.V(4).Infof("generated name: %s", object.UnstructuredToObjMetadata(obj))
+		named = append(named, obj)
+	}
+	return named
+}
+
+// withoutTestObjects returns the objects that are not test resources.
+func withoutTestObjects(objs object.UnstructuredSet) object.UnstructuredSet {
+	var filtered object.UnstructuredSet
+	for _, obj := range objs {
+		if hook, err := common.GetApplyHook(obj); err == nil && hook == common.ApplyHookTest {
+			klog.V(4).Infof("skipping test resource: %s", object.UnstructuredToObjMetadata(obj))
+			continue
+		}
+		filtered = append(filtered, obj)
+	}
+	return filtered
+}
+
+// splitUnknownKinds returns the objects whose kind is either served by the
+// cluster or defined by a CRD in the set, and the objects of unknown kinds.
+func splitUnknownKinds(objs object.UnstructuredSet, mapper meta.RESTMapper) (object.UnstructuredSet, object.UnstructuredSet) {
+	var crds object.UnstructuredSet
+	for _, obj := range objs {
+		if object.IsCRD(obj) {
+			crds = append(crds, obj)
+		}
+	}
+	var knownObjs, unknownObjs object.UnstructuredSet
+	for _, obj := range objs {
+		_, err := object.LookupResourceScope(obj, crds, mapper)
+		var unknownTypeErr *object.UnknownTypeError
+		if errors.As(err, &unknownTypeErr) {
+			unknownObjs = append(unknownObjs, obj)
+			continue
+		}
+		knownObjs = append(knownObjs, obj)
+	}
+	return knownObjs, unknownObjs
+}
+
+// requirementsPollInterval is how often the cluster is probed again while
+// waiting for the requirements to be met.
+var requirementsPollInterval = 2 * time.Second
+
+// waitForCapabilities checks the capabilities of the cluster. If the
+// requirements are not met, it probes the cluster again until they are met,
+// the RequirementsTimeout expires, or the context is cancelled, and returns
+// the last UnmetRequirementsError.
+func (a *Applier) waitForCapabilities(ctx context.Context, o ApplierOptions) error {
+	deadline := time.Now().Add(o.RequirementsTimeout)
+	for {
+		// The capabilities are probed at most once per attempt.
+		prober := &capabilities.Prober{Discovery: a.discoClient}
+		err := checkCapabilities(prober, a.mapper, o)
+		var unmetErr *capabilities.UnmetRequirementsError
+		if !errors.As(err, &unmetErr) || !time.Now().Before(deadline) {
+			return err
+		}
+		klog.V(2).Infof("waiting for the cluster to meet the requirements: %v", err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(requirementsPollInterval):
+		}
+		// Forget the cached API groups and kinds, to find the new ones.
+		if cached, ok := a.discoClient.(discovery.CachedDiscoveryInterface); ok {
+			cached.Invalidate()
+		}
+		meta.MaybeResetRESTMapper(a.mapper)
+	}
+}
+
+// checkCapabilities returns an error if the options require a feature that
+// the cluster does not support, or if the cluster does not meet the
+// requirements. The cluster is only probed if the options require a feature
+// that is not supported by all clusters, or have requirements.
+func checkCapabilities(prober *capabilities.Prober, mapper meta.RESTMapper, o ApplierOptions) error {
+	if !o.ServerSideOptions.ServerSideApply && o.DryRunStrategy != common.DryRunServer &&
+		o.Requirements.IsEmpty() {
+		return nil
+	}
+	caps, err := prober.Capabilities()
+	if err != nil {
+		return err
+	}
+	if o.ServerSideOptions.ServerSideApply && !caps.ServerSideApply {
+		return fmt.Errorf("server-side apply is not supported by the server (version %s)", caps.ServerVersion)
+	}
+	if o.DryRunStrategy == common.DryRunServer && !caps.DryRun {
+		return fmt.Errorf("server-side dry-run is not supported by the server (version %s)", caps.ServerVersion)
+	}
+	return caps.Check(o.Requirements, mapper)
+}
+
+// inventoryUIDs returns the last known UIDs of the objects in the inventory,
+// if the inventory client records them.
+func inventoryUIDs(invClient inventory.Client, invInfo inventory.Info) (map[object.ObjMetadata]types.UID, error) {
+	uidClient, ok := invClient.(inventory.UIDClient)
+	if !ok {
+		return nil, nil
+	}
+	return uidClient.GetClusterObjUIDs(invInfo)
+}
+
+// pruneMarkedObjects returns the objects whose prune was skipped by the
+// previous run, as recorded in the inventory, if the inventory client records
+// the status of the objects.
+func pruneMarkedObjects(invClient inventory.Client, invInfo inventory.Info) (object.ObjMetadataSet, error) {
+	status, err := inventoryStatus(invClient, invInfo)
+	if err != nil {
+		return nil, err
+	}
+	var ids object.ObjMetadataSet
+	for _, s := range status {
+		if s.Strategy == actuation.ActuationStrategyDelete && s.Actuation == actuation.ActuationSkipped {
+			ids = append(ids, inventory.ObjMetadataFromObjectReference(s.ObjectReference))
+		}
+	}
+	return ids, nil
 }
 
 func handleError(eventChannel chan event.Event, err error) {
@@ -331,3 +970,13 @@ func handleValidationError(eventChannel chan<- event.Event, err error) {
 		}
 	}
 }
+
+// addRunIDAnnotation sets the run ID annotation on the object.
+func addRunIDAnnotation(obj *unstructured.Unstructured, runID string) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[common.RunIDAnnotation] = runID
+	obj.SetAnnotations(annotations)
+}
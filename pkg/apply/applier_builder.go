@@ -4,20 +4,34 @@
 package apply
 
 import (
+	"fmt"
+
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/cli-runtime/pkg/resource"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
 	"k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/scheme"
+	"sigs.k8s.io/cli-utils/pkg/apply/filter"
 	"sigs.k8s.io/cli-utils/pkg/apply/info"
-	"sigs.k8s.io/cli-utils/pkg/apply/prune"
+	"sigs.k8s.io/cli-utils/pkg/apply/mutator"
+	"sigs.k8s.io/cli-utils/pkg/backoff"
 	"sigs.k8s.io/cli-utils/pkg/inventory"
 	"sigs.k8s.io/cli-utils/pkg/kstatus/watcher"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type ApplierBuilder struct {
 	commonBuilder
+	retryPolicy backoff.RetryPolicy
+	typedClient client.Client
+	typedKinds  []schema.GroupKind
+	// applyFilters are the custom filters of the objects to apply.
+	applyFilters []filter.ValidationFilter
+	// applyMutators are the custom mutators of the objects to apply.
+	applyMutators []mutator.Interface
 }
 
 // NewApplierBuilder returns a new ApplierBuilder.
@@ -32,18 +46,31 @@ func (b *ApplierBuilder) Build() (*Applier, error) {
 	if err != nil {
 		return nil, err
 	}
+	retryPolicy := b.retryPolicy
+	if retryPolicy.Backoff == nil {
+		retryPolicy.Backoff = bx.backoffPolicy.StrategyFor(backoff.ApplyOperation)
+	}
+	typedClient := b.typedClient
+	if typedClient == nil && len(b.typedKinds) > 0 {
+		typedClient, err = client.New(bx.restConfig, client.Options{Scheme: scheme.Scheme, Mapper: bx.mapper})
+		if err != nil {
+			return nil, fmt.Errorf("error creating typed client: %w", err)
+		}
+	}
 	return &Applier{
-		pruner: &prune.Pruner{
-			InvClient: bx.invClient,
-			Client:    bx.client,
-			Mapper:    bx.mapper,
-		},
+		pruner:        bx.newPruner(),
 		statusWatcher: bx.statusWatcher,
 		invClient:     bx.invClient,
 		client:        bx.client,
 		openAPIGetter: bx.discoClient,
+		discoClient:   bx.discoClient,
 		mapper:        bx.mapper,
 		infoHelper:    info.NewHelper(bx.mapper, bx.unstructuredClientForMapping),
+		retryPolicy:   retryPolicy,
+		typedClient:   typedClient,
+		typedKinds:    b.typedKinds,
+		applyFilters:  b.applyFilters,
+		applyMutators: b.applyMutators,
 	}, nil
 }
 
@@ -91,3 +118,65 @@ func (b *ApplierBuilder) WithStatusWatcherFilters(filters *watcher.Filters) *App
 	b.statusWatcherFilters = filters
 	return b
 }
+
+// WithBackoffPolicy sets the backoff strategies used when retrying
+// operations against the cluster.
+func (b *ApplierBuilder) WithBackoffPolicy(policy backoff.Policy) *ApplierBuilder {
+	b.backoffPolicy = policy
+	return b
+}
+
+// WithDeleteRetryPolicy sets the policy used to retry the deletion of an
+// object that failed with a transient error, like throttling or a timeout.
+// If the policy has no Backoff, the delete strategy of the backoff policy is
+// used. By default, deletions are not retried.
+func (b *ApplierBuilder) WithDeleteRetryPolicy(policy backoff.RetryPolicy) *ApplierBuilder {
+	b.deleteRetryPolicy = policy
+	return b
+}
+
+// WithRetryPolicy sets the policy used to retry the apply of an object that
+// failed with a transient error, like a conflict, throttling, or a webhook
+// timeout. If the policy has no Backoff, the apply strategy of the backoff
+// policy is used. By default, objects are not retried.
+func (b *ApplierBuilder) WithRetryPolicy(policy backoff.RetryPolicy) *ApplierBuilder {
+	b.retryPolicy = policy
+	return b
+}
+
+// WithTypedClientKinds sets the built-in kinds that are applied as typed
+// objects, using protobuf encoding, when using server-side apply. Applying
+// typed objects reduces CPU and bandwidth usage for large applies dominated
+// by a few kinds. Kinds not registered in the kubectl scheme are applied
+// as unstructured objects.
+func (b *ApplierBuilder) WithTypedClientKinds(kinds ...schema.GroupKind) *ApplierBuilder {
+	b.typedKinds = kinds
+	return b
+}
+
+// WithTypedClient sets the client used to apply the kinds configured with
+// WithTypedClientKinds. By default, a client is created from the rest
+// config.
+func (b *ApplierBuilder) WithTypedClient(typedClient client.Client) *ApplierBuilder {
+	b.typedClient = typedClient
+	return b
+}
+
+// WithApplyFilters adds custom filters of the objects to apply, evaluated
+// after the inventory policy filter, like skipping the objects of protected
+// namespaces or the kinds not supported by the cluster. The apply of an
+// object is skipped if a filter returns an error, which is reported as the
+// reason on the ApplySkipped event. Objects that depend on a skipped object
+// are skipped too. Filters that return a FatalError fail the apply instead.
+func (b *ApplierBuilder) WithApplyFilters(filters ...filter.ValidationFilter) *ApplierBuilder {
+	b.applyFilters = append(b.applyFilters, filters...)
+	return b
+}
+
+// WithApplyMutators adds custom mutators of the objects to apply, like the
+// OverlayMutator, evaluated in order before the apply-time mutations. The
+// apply of an object fails if a mutator returns an error.
+func (b *ApplierBuilder) WithApplyMutators(mutators ...mutator.Interface) *ApplierBuilder {
+	b.applyMutators = append(b.applyMutators, mutators...)
+	return b
+}
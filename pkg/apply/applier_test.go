@@ -5,16 +5,32 @@ package apply
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta/testrestmapper"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
 	"k8s.io/kubectl/pkg/scheme"
 	"sigs.k8s.io/cli-utils/pkg/apis/actuation"
 	"sigs.k8s.io/cli-utils/pkg/apply/event"
+	"sigs.k8s.io/cli-utils/pkg/apply/filter"
+	"sigs.k8s.io/cli-utils/pkg/capabilities"
+	"sigs.k8s.io/cli-utils/pkg/common"
 	"sigs.k8s.io/cli-utils/pkg/inventory"
 	pollevent "sigs.k8s.io/cli-utils/pkg/kstatus/polling/event"
 	"sigs.k8s.io/cli-utils/pkg/kstatus/status"
@@ -509,6 +525,14 @@ func TestApplier(t *testing.T) {
 						Identifier: testutil.ToIdentifier(t, resources["deployment"]),
 					},
 				},
+				// Existing deployment without owning inventory is adopted
+				{
+					EventType: event.AdoptType,
+					AdoptEvent: &testutil.ExpAdoptEvent{
+						GroupName:  "apply-0",
+						Identifier: testutil.ToIdentifier(t, resources["deployment"]),
+					},
+				},
 				{
 					EventType: event.ActionGroupType,
 					ActionGroupEvent: &testutil.ExpActionGroupEvent{
@@ -962,10 +986,8 @@ func TestApplier(t *testing.T) {
 						GroupName:  "prune-0",
 						Status:     event.PruneSkipped,
 						Identifier: testutil.ToIdentifier(t, resources["deployment"]),
-						Error: &inventory.PolicyPreventedActuationError{
-							Strategy: actuation.ActuationStrategyDelete,
-							Policy:   inventory.PolicyMustMatch,
-							Status:   inventory.NoMatch,
+						Error: &inventory.OwnershipTransferredError{
+							Owner: "unmatched",
 						},
 					},
 				},
@@ -2067,3 +2089,440 @@ func TestReadAndPrepareObjects(t *testing.T) {
 		})
 	}
 }
+
+func TestPrepareObjectsAnnotateRunID(t *testing.T) {
+	inventoryObj := testutil.Unstructured(t, resources["inventory"])
+	inventory := inventory.WrapInventoryInfoObj(inventoryObj)
+	invInfo := inventoryInfo{
+		name:      inventory.Name(),
+		namespace: inventory.Namespace(),
+		id:        inventory.ID(),
+	}
+
+	testCases := map[string]struct {
+		options            ApplierOptions
+		expectedAnnotation bool
+	}{
+		"disabled": {
+			options: ApplierOptions{RunID: "run-1"},
+		},
+		"enabled": {
+			options:            ApplierOptions{RunID: "run-1", AnnotateRunID: true},
+			expectedAnnotation: true,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			obj1 := testutil.Unstructured(t, resources["obj1"])
+			applier := newTestApplier(t, invInfo, object.UnstructuredSet{obj1}, object.UnstructuredSet{},
+				watcher.BlindStatusWatcher{})
+
+			applyObjs, _, err := applier.prepareObjects(invInfo.toWrapped(), object.UnstructuredSet{obj1}, tc.options)
+			require.NoError(t, err)
+			require.Len(t, applyObjs, 1)
+			runID, found := applyObjs[0].GetAnnotations()[common.RunIDAnnotation]
+			assert.Equal(t, tc.expectedAnnotation, found)
+			if tc.expectedAnnotation {
+				assert.Equal(t, "run-1", runID)
+			}
+		})
+	}
+}
+
+func TestGenerateNames(t *testing.T) {
+	named := testutil.Unstructured(t, resources["obj1"])
+	generated := testutil.Unstructured(t, resources["obj1"])
+	generated.SetName("")
+	generated.SetGenerateName("obj1-")
+	longGenerated := testutil.Unstructured(t, resources["obj1"])
+	longGenerated.SetName("")
+	longGenerated.SetGenerateName(strings.Repeat("a", 70))
+
+	objs := generateNames(object.UnstructuredSet{named, generated, longGenerated})
+
+	require.Len(t, objs, 3)
+	assert.Same(t, named, objs[0])
+	assert.Regexp(t, "^obj1-[a-z0-9]{5}$", objs[1].GetName())
+	assert.Regexp(t, "^a{58}[a-z0-9]{5}$", objs[2].GetName())
+	// The names are generated on copies, so that the next run generates
+	// new names.
+	assert.Empty(t, generated.GetName())
+	assert.Empty(t, longGenerated.GetName())
+	assert.NotEqual(t, objs[1].GetName(), generateNames(object.UnstructuredSet{generated})[0].GetName())
+}
+
+func TestApplierRunID(t *testing.T) {
+	inventoryObj := testutil.Unstructured(t, resources["inventory"])
+	inventory := inventory.WrapInventoryInfoObj(inventoryObj)
+	invInfo := inventoryInfo{
+		name:      inventory.Name(),
+		namespace: inventory.Namespace(),
+		id:        inventory.ID(),
+	}
+
+	testCases := map[string]struct {
+		runID string
+	}{
+		"provided run ID": {
+			runID: "run-1",
+		},
+		"generated run ID": {},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			applier := newTestApplier(t, invInfo, object.UnstructuredSet{}, object.UnstructuredSet{},
+				watcher.BlindStatusWatcher{})
+
+			eventChannel := applier.Run(context.TODO(), invInfo.toWrapped(), object.UnstructuredSet{}, ApplierOptions{
+				RunID: tc.runID,
+			})
+			runIDs := sets.New[string]()
+			var events []event.Event
+			for e := range eventChannel {
+				events = append(events, e)
+				runIDs.Insert(e.RunID)
+			}
+			require.NotEmpty(t, events)
+			// All the events of a run have the same run ID.
+			require.Equal(t, 1, runIDs.Len())
+			runID := sets.List(runIDs)[0]
+			if tc.runID != "" {
+				assert.Equal(t, tc.runID, runID)
+			} else {
+				assert.NotEmpty(t, runID)
+			}
+		})
+	}
+}
+
+func TestApplierApplyFilters(t *testing.T) {
+	inventoryObj := testutil.Unstructured(t, resources["inventory"])
+	inventory := inventory.WrapInventoryInfoObj(inventoryObj)
+	invInfo := inventoryInfo{
+		name:      inventory.Name(),
+		namespace: inventory.Namespace(),
+		id:        inventory.ID(),
+	}
+	deployment := testutil.Unstructured(t, resources["deployment"])
+	errProtected := errors.New("namespace is protected")
+
+	testCases := map[string]struct {
+		protectedNamespace string
+		expectedStatus     event.ApplyEventStatus
+		expectedError      error
+	}{
+		"object skipped by filter": {
+			protectedNamespace: deployment.GetNamespace(),
+			expectedStatus:     event.ApplySkipped,
+			expectedError:      errProtected,
+		},
+		"object not skipped by filter": {
+			protectedNamespace: "kube-system",
+			expectedStatus:     event.ApplySuccessful,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			applier := newTestApplier(t, invInfo, object.UnstructuredSet{deployment}, object.UnstructuredSet{},
+				watcher.BlindStatusWatcher{})
+			applier.applyFilters = []filter.ValidationFilter{
+				filter.FuncFilter{
+					FilterName: "ProtectedNamespaceFilter",
+					Func: func(obj *unstructured.Unstructured) error {
+						if obj.GetNamespace() == tc.protectedNamespace {
+							return errProtected
+						}
+						return nil
+					},
+				},
+			}
+
+			eventChannel := applier.Run(context.TODO(), invInfo.toWrapped(), object.UnstructuredSet{deployment},
+				ApplierOptions{
+					DryRunStrategy: common.DryRunClient,
+				})
+			var applyEvents []event.ApplyEvent
+			for e := range eventChannel {
+				require.NotEqual(t, event.ErrorType, e.Type, "unexpected error: %v", e.ErrorEvent.Err)
+				if e.Type == event.ApplyType {
+					applyEvents = append(applyEvents, e.ApplyEvent)
+				}
+			}
+			require.Len(t, applyEvents, 1)
+			assert.Equal(t, tc.expectedStatus, applyEvents[0].Status)
+			assert.Equal(t, tc.expectedError, applyEvents[0].Error)
+		})
+	}
+}
+
+func TestApplierPruneScope(t *testing.T) {
+	deployment := testutil.Unstructured(t, resources["deployment"], testutil.AddOwningInv(t, "test"))
+	secret := testutil.Unstructured(t, resources["secret"], testutil.AddOwningInv(t, "test"))
+	deploymentID := object.UnstructuredToObjMetadata(deployment)
+	secretID := object.UnstructuredToObjMetadata(secret)
+	invInfo := inventoryInfo{
+		name:      "abc-123",
+		namespace: "test",
+		id:        "test",
+		set:       object.ObjMetadataSet{deploymentID, secretID},
+	}
+
+	testCases := map[string]struct {
+		options             ApplierOptions
+		expectedSkipReasons map[object.ObjMetadata]event.SkipReason
+	}{
+		"no scope": {
+			expectedSkipReasons: map[object.ObjMetadata]event.SkipReason{},
+		},
+		"kind not in scope": {
+			options: ApplierOptions{
+				PruneGroupKinds: []schema.GroupKind{secretID.GroupKind},
+			},
+			expectedSkipReasons: map[object.ObjMetadata]event.SkipReason{
+				deploymentID: event.SkipReasonOutOfScope,
+			},
+		},
+		"namespace not in scope": {
+			options: ApplierOptions{
+				PruneNamespaces: []string{"other"},
+			},
+			expectedSkipReasons: map[object.ObjMetadata]event.SkipReason{
+				deploymentID: event.SkipReasonOutOfScope,
+				secretID:     event.SkipReasonOutOfScope,
+			},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			applier := newTestApplier(t, invInfo, object.UnstructuredSet{},
+				object.UnstructuredSet{deployment.DeepCopy(), secret.DeepCopy()},
+				watcher.BlindStatusWatcher{})
+
+			// Dry-run to skip waiting for deletion
+			tc.options.DryRunStrategy = common.DryRunClient
+			skipReasons := map[object.ObjMetadata]event.SkipReason{}
+			pruned := object.ObjMetadataSet{}
+			for e := range applier.Run(context.TODO(), invInfo.toWrapped(), object.UnstructuredSet{}, tc.options) {
+				require.NotEqual(t, event.ErrorType, e.Type, "unexpected error: %v", e.ErrorEvent.Err)
+				if e.Type != event.PruneType {
+					continue
+				}
+				switch e.PruneEvent.Status {
+				case event.PruneSkipped:
+					skipReasons[e.PruneEvent.Identifier] = e.PruneEvent.SkipReason
+				case event.PruneSuccessful:
+					pruned = append(pruned, e.PruneEvent.Identifier)
+				}
+			}
+			assert.Equal(t, tc.expectedSkipReasons, skipReasons)
+			for _, id := range invInfo.set {
+				_, skipped := tc.expectedSkipReasons[id]
+				assert.Equal(t, !skipped, pruned.Contains(id), "pruned: %s", id)
+			}
+		})
+	}
+}
+
+func TestCheckCapabilities(t *testing.T) {
+	testCases := map[string]struct {
+		gitVersion    string
+		options       ApplierOptions
+		expectedError string
+	}{
+		"client-side apply is always supported": {
+			gitVersion: "v1.10.0",
+			options:    ApplierOptions{},
+		},
+		"server-side apply supported": {
+			gitVersion: "v1.22.0",
+			options: ApplierOptions{
+				ServerSideOptions: common.ServerSideOptions{ServerSideApply: true},
+			},
+		},
+		"server-side apply not supported": {
+			gitVersion: "v1.15.0",
+			options: ApplierOptions{
+				ServerSideOptions: common.ServerSideOptions{ServerSideApply: true},
+			},
+			expectedError: "server-side apply is not supported by the server (version v1.15.0)",
+		},
+		"server-side dry-run not supported": {
+			gitVersion: "v1.12.0",
+			options: ApplierOptions{
+				DryRunStrategy: common.DryRunServer,
+			},
+			expectedError: "server-side dry-run is not supported by the server (version v1.12.0)",
+		},
+		"requirements not met": {
+			gitVersion: "v1.22.0",
+			options: ApplierOptions{
+				Requirements: capabilities.Requirements{
+					MinServerVersion: "v1.25",
+				},
+			},
+			expectedError: "cluster does not meet the requirements:\n" +
+				"- server version v1.22.0 is older than the minimum version v1.25",
+		},
+		"required kind not served": {
+			gitVersion: "v1.22.0",
+			options: ApplierOptions{
+				Requirements: capabilities.Requirements{
+					Kinds: []schema.GroupKind{{Group: "cert-manager.io", Kind: "Certificate"}},
+				},
+			},
+			expectedError: "cluster does not meet the requirements:\n" +
+				"- kind Certificate.cert-manager.io is not served",
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			prober := &capabilities.Prober{
+				Discovery: &fakediscovery.FakeDiscovery{
+					Fake:               &clienttesting.Fake{},
+					FakedServerVersion: &version.Info{GitVersion: tc.gitVersion},
+				},
+			}
+			err := checkCapabilities(prober, testrestmapper.TestOnlyStaticRESTMapper(scheme.Scheme), tc.options)
+			if tc.expectedError != "" {
+				require.EqualError(t, err, tc.expectedError)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestWaitForCapabilities(t *testing.T) {
+	defer func(interval time.Duration) {
+		requirementsPollInterval = interval
+	}(requirementsPollInterval)
+	requirementsPollInterval = time.Millisecond
+
+	testCases := map[string]struct {
+		servedAfter   int
+		timeout       time.Duration
+		expectedCalls int
+		expectedError string
+	}{
+		"requirements met without waiting": {
+			servedAfter:   0,
+			expectedCalls: 1,
+		},
+		"no timeout fails immediately": {
+			servedAfter:   2,
+			expectedCalls: 1,
+			expectedError: "cluster does not meet the requirements:\n" +
+				"- API version cert-manager.io/v1 is not served",
+		},
+		"requirements met while waiting": {
+			servedAfter:   2,
+			timeout:       time.Minute,
+			expectedCalls: 3,
+		},
+		"timeout expires": {
+			servedAfter: -1,
+			timeout:     20 * time.Millisecond,
+			expectedError: "cluster does not meet the requirements:\n" +
+				"- API version cert-manager.io/v1 is not served",
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			disco := &fakediscovery.FakeDiscovery{
+				Fake:               &clienttesting.Fake{},
+				FakedServerVersion: &version.Info{GitVersion: "v1.28.0"},
+			}
+			disco.Resources = []*metav1.APIResourceList{{GroupVersion: "v1"}}
+			calls := 0
+			disco.AddReactor("get", "group", func(clienttesting.Action) (bool, runtime.Object, error) {
+				// Simulate an operator installing its CRDs.
+				if calls == tc.servedAfter {
+					disco.Resources = append(disco.Resources,
+						&metav1.APIResourceList{GroupVersion: "cert-manager.io/v1"})
+				}
+				calls++
+				return false, nil, nil
+			})
+			a := &Applier{
+				discoClient: disco,
+				mapper:      testrestmapper.TestOnlyStaticRESTMapper(scheme.Scheme),
+			}
+			err := a.waitForCapabilities(context.Background(), ApplierOptions{
+				Requirements: capabilities.Requirements{
+					APIVersions: []string{"cert-manager.io/v1"},
+				},
+				RequirementsTimeout: tc.timeout,
+			})
+			if tc.expectedError != "" {
+				require.EqualError(t, err, tc.expectedError)
+			} else {
+				require.NoError(t, err)
+			}
+			if tc.expectedCalls > 0 {
+				assert.Equal(t, tc.expectedCalls, calls)
+			}
+		})
+	}
+}
+
+func TestNamespaceExists(t *testing.T) {
+	namespace := &unstructured.Unstructured{}
+	namespace.SetAPIVersion("v1")
+	namespace.SetKind("Namespace")
+	namespace.SetName("existing")
+
+	testCases := map[string]struct {
+		namespace     string
+		getErr        error
+		expected      bool
+		expectedError string
+	}{
+		"existing namespace": {
+			namespace: "existing",
+			expected:  true,
+		},
+		"missing namespace": {
+			namespace: "missing",
+			expected:  false,
+		},
+		"forbidden namespace is assumed to exist": {
+			namespace: "forbidden",
+			getErr: apierrors.NewForbidden(schema.GroupResource{Resource: "namespaces"},
+				"forbidden", errors.New("not allowed")),
+			expected: true,
+		},
+		"unexpected error": {
+			namespace:     "existing",
+			getErr:        apierrors.NewInternalError(errors.New("boom")),
+			expectedError: "Internal error occurred: boom",
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			client := dynamicfake.NewSimpleDynamicClient(scheme.Scheme, namespace)
+			if tc.getErr != nil {
+				client.PrependReactor("get", "namespaces", func(clienttesting.Action) (bool, runtime.Object, error) {
+					return true, nil, tc.getErr
+				})
+			}
+			a := &Applier{
+				client: client,
+				mapper: testrestmapper.TestOnlyStaticRESTMapper(scheme.Scheme),
+			}
+			exists, err := a.namespaceExists(context.Background(), tc.namespace)
+			if tc.expectedError != "" {
+				require.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, exists)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package backup stores the live state of the objects modified by an apply
+// run, before they are modified, so that they can be restored manually after
+// a bad apply.
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/cli-utils/pkg/multierror"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/yaml"
+)
+
+// Store stores the backups of the runs, by run ID.
+type Store interface {
+	// Save stores the objects as the backup of the run.
+	Save(ctx context.Context, runID string, objs object.UnstructuredSet) error
+	// Load returns the objects of the backup of the run.
+	Load(ctx context.Context, runID string) (object.UnstructuredSet, error)
+}
+
+// NotFoundError is returned by Load if the run has no backup.
+type NotFoundError struct {
+	RunID string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("backup not found: %q", e.RunID)
+}
+
+// Restore replaces the live objects with their state in the backup, and
+// re-creates the objects that were deleted. Returns the errors of the objects
+// that could not be restored.
+func Restore(ctx context.Context, client dynamic.Interface, mapper meta.RESTMapper,
+	objs object.UnstructuredSet) error {
+	var errs []error
+	for _, obj := range objs {
+		id := object.UnstructuredToObjMetadata(obj)
+		klog.V(4).Infof("restoring object from backup (object: %s)", id)
+		mapping, err := mapper.RESTMapping(id.GroupKind)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to restore %s: %w", id, err))
+			continue
+		}
+		var resourceClient dynamic.ResourceInterface
+		if mapping.Scope.Name() == meta.RESTScopeNameRoot {
+			resourceClient = client.Resource(mapping.Resource)
+		} else {
+			resourceClient = client.Resource(mapping.Resource).Namespace(id.Namespace)
+		}
+		if err := RestoreObject(ctx, resourceClient, obj); err != nil {
+			errs = append(errs, fmt.Errorf("failed to restore %s: %w", id, err))
+		}
+	}
+	return multierror.Wrap(errs...)
+}
+
+// RestoreObject replaces the live object with its previous state, or
+// re-creates it if it was deleted.
+func RestoreObject(ctx context.Context, client dynamic.ResourceInterface, prev *unstructured.Unstructured) error {
+	obj := prev.DeepCopy()
+	live, err := client.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		obj.SetResourceVersion("")
+		obj.SetUID("")
+		_, err = client.Create(ctx, obj, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	obj.SetResourceVersion(live.GetResourceVersion())
+	_, err = client.Update(ctx, obj, metav1.UpdateOptions{})
+	return err
+}
+
+// encode encodes the objects as a multi-document YAML stream.
+func encode(objs object.UnstructuredSet) ([]byte, error) {
+	var b bytes.Buffer
+	for _, obj := range objs {
+		data, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return nil, err
+		}
+		b.WriteString("---\n")
+		b.Write(data)
+	}
+	return b.Bytes(), nil
+}
+
+// decode decodes the objects of a multi-document YAML stream.
+func decode(data []byte) (object.UnstructuredSet, error) {
+	var objs object.UnstructuredSet
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(data)))
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return objs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		jsonData, err := yaml.YAMLToJSON(doc)
+		if err != nil {
+			return nil, err
+		}
+		if len(bytes.TrimSpace(jsonData)) == 0 || string(jsonData) == "null" {
+			continue
+		}
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(jsonData); err != nil {
+			return nil, err
+		}
+		objs = append(objs, obj)
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package backup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/kubectl/pkg/scheme"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/cli-utils/pkg/testutil"
+)
+
+var deployment = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: default
+  resourceVersion: "1"
+spec:
+  replicas: 3
+`
+
+var configMap = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  namespace: default
+data:
+  key: value
+`
+
+var deploymentsGVR = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+var configMapsGVR = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+
+func TestStores(t *testing.T) {
+	stores := map[string]Store{
+		"directory": &DirectoryStore{Dir: t.TempDir()},
+		"secret": &SecretStore{
+			Client:    dynamicfake.NewSimpleDynamicClient(scheme.Scheme),
+			Namespace: "backups",
+		},
+	}
+	objs := object.UnstructuredSet{
+		testutil.Unstructured(t, deployment),
+		testutil.Unstructured(t, configMap),
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.TODO()
+			require.NoError(t, store.Save(ctx, "run-1", objs))
+
+			loaded, err := store.Load(ctx, "run-1")
+			require.NoError(t, err)
+			testutil.AssertEqual(t, objs, loaded)
+
+			_, err = store.Load(ctx, "run-2")
+			assert.Equal(t, &NotFoundError{RunID: "run-2"}, err)
+		})
+	}
+}
+
+func TestRestore(t *testing.T) {
+	// The deployment was modified, and the config map deleted.
+	modified := testutil.Unstructured(t, deployment)
+	modified.SetResourceVersion("2")
+	modified.Object["spec"] = map[string]interface{}{"replicas": int64(1)}
+	client := dynamicfake.NewSimpleDynamicClient(scheme.Scheme, modified)
+	mapper := testutil.NewFakeRESTMapper(
+		schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+		schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"},
+	)
+
+	err := Restore(context.TODO(), client, mapper, object.UnstructuredSet{
+		testutil.Unstructured(t, deployment),
+		testutil.Unstructured(t, configMap),
+	})
+	require.NoError(t, err)
+
+	live, err := client.Resource(deploymentsGVR).Namespace("default").Get(context.TODO(), "web", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"replicas": int64(3)}, live.Object["spec"])
+	live, err = client.Resource(configMapsGVR).Namespace("default").Get(context.TODO(), "config", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"key": "value"}, live.Object["data"])
+}
+
+func TestRestoreUnknownKind(t *testing.T) {
+	client := dynamicfake.NewSimpleDynamicClient(scheme.Scheme)
+	mapper := testutil.NewFakeRESTMapper()
+
+	err := Restore(context.TODO(), client, mapper, object.UnstructuredSet{
+		testutil.Unstructured(t, configMap),
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to restore default_config__ConfigMap")
+}
@@ -0,0 +1,51 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package backup
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+// DirectoryStore stores the backup of each run in a local directory, as a
+// multi-document YAML file named after the run ID.
+// Implements the Store interface
+type DirectoryStore struct {
+	// Dir is the directory of the backups. It is created if missing.
+	Dir string
+}
+
+var _ Store = &DirectoryStore{}
+
+// Save writes the objects to the file of the run.
+func (s *DirectoryStore) Save(_ context.Context, runID string, objs object.UnstructuredSet) error {
+	data, err := encode(objs)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.Dir, 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(s.path(runID), data, 0o600)
+}
+
+// Load reads the objects from the file of the run.
+func (s *DirectoryStore) Load(_ context.Context, runID string) (object.UnstructuredSet, error) {
+	data, err := os.ReadFile(s.path(runID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, &NotFoundError{RunID: runID}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decode(data)
+}
+
+func (s *DirectoryStore) path(runID string) string {
+	return filepath.Join(s.Dir, filepath.Base(runID)+".yaml")
+}
@@ -0,0 +1,86 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package backup
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+const (
+	// SecretNamePrefix is the prefix of the names of the backup Secrets,
+	// followed by the run ID.
+	SecretNamePrefix = "cli-utils-backup-"
+	// SecretDataKey is the key of the objects in the data of the backup
+	// Secrets.
+	SecretDataKey = "objects.yaml"
+	// RunIDLabel is the label of the backup Secrets with the run ID.
+	RunIDLabel = "cli-utils.sigs.k8s.io/backup-run-id"
+)
+
+var secretGVR = schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+
+// SecretStore stores the backup of each run in a Secret, named after the run
+// ID. The run ID must be a valid label value, and the backup is limited to
+// the maximum size of a Secret (1MiB).
+// Implements the Store interface
+type SecretStore struct {
+	Client dynamic.Interface
+	// Namespace is the namespace of the Secrets.
+	Namespace string
+}
+
+var _ Store = &SecretStore{}
+
+// Save creates the Secret of the run.
+func (s *SecretStore) Save(ctx context.Context, runID string, objs object.UnstructuredSet) error {
+	data, err := encode(objs)
+	if err != nil {
+		return err
+	}
+	secret := &unstructured.Unstructured{}
+	secret.SetAPIVersion("v1")
+	secret.SetKind("Secret")
+	secret.SetName(SecretNamePrefix + runID)
+	secret.SetNamespace(s.Namespace)
+	secret.SetLabels(map[string]string{RunIDLabel: runID})
+	secret.Object["type"] = "Opaque"
+	secret.Object["data"] = map[string]interface{}{
+		SecretDataKey: base64.StdEncoding.EncodeToString(data),
+	}
+	_, err = s.Client.Resource(secretGVR).Namespace(s.Namespace).Create(ctx, secret, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create backup secret: %w", err)
+	}
+	return nil
+}
+
+// Load reads the objects from the Secret of the run.
+func (s *SecretStore) Load(ctx context.Context, runID string) (object.UnstructuredSet, error) {
+	secret, err := s.Client.Resource(secretGVR).Namespace(s.Namespace).
+		Get(ctx, SecretNamePrefix+runID, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, &NotFoundError{RunID: runID}
+	}
+	if err != nil {
+		return nil, err
+	}
+	encoded, _, err := unstructured.NestedString(secret.Object, "data", SecretDataKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid backup secret: %w", err)
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid backup secret: %w", err)
+	}
+	return decode(data)
+}
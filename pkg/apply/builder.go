@@ -13,6 +13,8 @@ import (
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
 	"k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/cli-utils/pkg/apply/prune"
+	"sigs.k8s.io/cli-utils/pkg/backoff"
 	"sigs.k8s.io/cli-utils/pkg/inventory"
 	"sigs.k8s.io/cli-utils/pkg/kstatus/watcher"
 )
@@ -28,6 +30,8 @@ type commonBuilder struct {
 	unstructuredClientForMapping func(*meta.RESTMapping) (resource.RESTClient, error)
 	statusWatcher                watcher.StatusWatcher
 	statusWatcherFilters         *watcher.Filters
+	backoffPolicy                backoff.Policy
+	deleteRetryPolicy            backoff.RetryPolicy
 }
 
 func (cb *commonBuilder) finalize() (*commonBuilder, error) {
@@ -83,6 +87,7 @@ func (cb *commonBuilder) finalize() (*commonBuilder, error) {
 		if cx.statusWatcherFilters != nil {
 			statusWatcher.Filters = cx.statusWatcherFilters
 		}
+		statusWatcher.Backoff = cx.backoffPolicy.StrategyFor(backoff.StatusReadOperation)
 		cx.statusWatcher = statusWatcher
 	} else if cx.statusWatcherFilters != nil {
 		// If you want to use a custom status watcher with a label selector,
@@ -91,3 +96,19 @@ func (cb *commonBuilder) finalize() (*commonBuilder, error) {
 	}
 	return &cx, nil
 }
+
+// newPruner returns the Pruner used to delete objects, retrying the deletions
+// with the delete strategy of the backoff policy, unless the delete retry
+// policy has its own Backoff.
+func (cb *commonBuilder) newPruner() *prune.Pruner {
+	retryPolicy := cb.deleteRetryPolicy
+	if retryPolicy.Backoff == nil {
+		retryPolicy.Backoff = cb.backoffPolicy.StrategyFor(backoff.DeleteOperation)
+	}
+	return &prune.Pruner{
+		InvClient:   cb.invClient,
+		Client:      cb.client,
+		Mapper:      cb.mapper,
+		RetryPolicy: retryPolicy,
+	}
+}
@@ -9,7 +9,9 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"path"
 	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -237,6 +239,14 @@ func (g *genericHandler) handle(t *testing.T, req *http.Request) (*http.Response
 			allPath = fmt.Sprintf("/%s", mapping.Resource.Resource)
 		}
 		singlePath := allPath + "/" + r.resource.GetName()
+		// Objects with a generateName are applied with a generated name.
+		if r.resource.GetName() == "" && r.resource.GetGenerateName() != "" &&
+			strings.HasPrefix(req.URL.Path, allPath+"/"+r.resource.GetGenerateName()) {
+			singlePath = req.URL.Path
+			named := r.resource.DeepCopy()
+			named.SetName(path.Base(req.URL.Path))
+			r = resourceInfo{resource: named, exists: r.exists}
+		}
 
 		if req.URL.Path == singlePath && req.Method == http.MethodGet {
 			if r.exists {
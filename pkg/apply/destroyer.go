@@ -8,8 +8,10 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/google/uuid"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/klog/v2"
@@ -57,55 +59,168 @@ type DestroyerOptions struct {
 	// use the Background policy.
 	DeletePropagationPolicy metav1.DeletionPropagation
 
+	// DeleteGracePeriodSeconds defines the grace period of the deletions, in
+	// seconds. If this is not provided, the default grace period of each
+	// object is used. The deletion-propagation and
+	// deletion-grace-period-seconds annotations override the propagation
+	// policy and grace period of individual objects.
+	DeleteGracePeriodSeconds *int64
+
+	// ProtectedKey is the label or annotation key that protects objects from
+	// being deleted, when set to "true". Protected objects are skipped and
+	// kept in the inventory. Defaults to common.ProtectedKey.
+	ProtectedKey string
+
+	// SkipDeleteWait defines whether the destroyer should not wait for the
+	// objects to be deleted, e.g. until their finalizers have run, after the
+	// delete requests are accepted. The objects deleted before their
+	// dependencies are still waited on, so that the dependencies are not
+	// deleted first.
+	SkipDeleteWait bool
+
+	// DeleteStuckDeletionThreshold defines how long to wait for the objects
+	// to be deleted, before sending a StuckDeletionEvent with the remaining
+	// finalizers of every object that is still terminating. Zero disables
+	// the report.
+	DeleteStuckDeletionThreshold time.Duration
+
+	// DeleteRemoveStuckFinalizers defines whether the finalizers of the
+	// objects reported by a StuckDeletionEvent are forcibly removed. This
+	// skips the cleanup of the controllers of the finalizers.
+	DeleteRemoveStuckFinalizers bool
+
+	// DeleteFetchConcurrency is the maximum number of objects of the
+	// inventory retrieved in parallel from the cluster before deleting them.
+	// Zero uses prune.DefaultFetchConcurrency.
+	DeleteFetchConcurrency int
+
+	// DeleteCollectionThreshold is the minimum number of objects with the
+	// same GroupKind and namespace that are deleted with a single
+	// DeleteCollection request, instead of one request per object. The
+	// request is only used if the objects share labels that select exactly
+	// these objects. Zero disables the batching.
+	//
+	// DeleteCollection has no UID precondition, so an object deleted and
+	// re-created with the same name and labels while the batch is deleted
+	// is deleted too, even if it is not in the inventory.
+	DeleteCollectionThreshold int
+
+	// DeleteListDependents defines whether the dependents of each object are
+	// listed before it is deleted, and reported on its DeleteEvent. The
+	// dependents are the objects owned by the object, directly or indirectly
+	// through their ownerReferences, which the garbage collector deletes
+	// with it.
+	DeleteListDependents bool
+
+	// DeleteDependentKinds are the kinds of the listed dependents. If empty,
+	// prune.DefaultDependentKinds is used.
+	DeleteDependentKinds []schema.GroupKind
+
 	// EmitStatusEvents defines whether status events should be
 	// emitted on the eventChannel to the caller.
 	EmitStatusEvents bool
 
+	// AggregateDeleteErrors defines whether the run should end with a PruneError
+	// aggregating the causes of all the failed deletes. Objects that fail to
+	// delete never stop the run: each failure is reported on a DeleteFailed
+	// event, the remaining objects are still deleted, and the failed objects
+	// are kept in the inventory. By default, the run ends without error, and
+	// the caller has to inspect the events.
+	AggregateDeleteErrors bool
+
 	// ValidationPolicy defines how to handle invalid objects.
 	ValidationPolicy validation.Policy
+
+	// StrictAnnotations enables validation of the annotations that affect
+	// the behavior of cli-utils. Misspelled keys and unsupported values are
+	// handled according to the ValidationPolicy.
+	StrictAnnotations bool
+
+	// CircuitBreakerThreshold is the number of consecutive server errors
+	// (5xx) after which the run is aborted. Zero disables the circuit breaker.
+	CircuitBreakerThreshold int
+
+	// EventBuffer configures the buffering of the returned event channel,
+	// and what happens to events when the buffer is full. By default, the
+	// channel is unbuffered and the run blocks until each event is received.
+	EventBuffer event.BufferOptions
+
+	// KeepInventory defines whether the inventory object should be retained
+	// after all the objects have been deleted. By default, the inventory
+	// object is deleted. When retained, the inventory is updated to only
+	// contain the objects that were not deleted, and the namespace of the
+	// inventory is retained as well.
+	KeepInventory bool
+
+	// KeepInventoryNamespace defines whether the namespace of the inventory
+	// object should be retained, if it is in the inventory. By default, it
+	// is deleted with the other objects.
+	KeepInventoryNamespace bool
+
+	// RunID identifies the run in the events and logs. If this is not
+	// provided, a random UUID is generated.
+	RunID string
 }
 
 func setDestroyerDefaults(o *DestroyerOptions) {
 	if o.DeletePropagationPolicy == "" {
 		o.DeletePropagationPolicy = metav1.DeletePropagationBackground
 	}
+	if o.RunID == "" {
+		o.RunID = uuid.NewString()
+	}
 }
 
 // Run performs the destroy step. Passes the inventory object. This
 // happens asynchronously on progress and any errors are reported
 // back on the event channel.
 func (d *Destroyer) Run(ctx context.Context, invInfo inventory.Info, options DestroyerOptions) <-chan event.Event {
-	eventChannel := make(chan event.Event)
 	setDestroyerDefaults(&options)
+	klog.V(4).Infof("destroy run %s", options.RunID)
+	eventChannel := make(chan event.Event)
 	go func() {
 		defer close(eventChannel)
 		// Retrieve the objects to be deleted from the cluster. Second parameter is empty
 		// because no local objects returns all inventory objects for deletion.
 		emptyLocalObjs := object.UnstructuredSet{}
 		deleteObjs, err := d.pruner.GetPruneObjs(invInfo, emptyLocalObjs, prune.Options{
-			DryRunStrategy: options.DryRunStrategy,
+			DryRunStrategy:   options.DryRunStrategy,
+			FetchConcurrency: options.DeleteFetchConcurrency,
 		})
 		if err != nil {
 			handleError(eventChannel, err)
 			return
 		}
+		invUIDs, err := inventoryUIDs(d.invClient, invInfo)
+		if err != nil {
+			handleError(eventChannel, err)
+			return
+		}
 
 		// Validate the resources to make sure we catch those problems early
 		// before anything has been updated in the cluster.
 		vCollector := &validation.Collector{}
 		validator := &validation.Validator{
-			Collector: vCollector,
-			Mapper:    d.mapper,
+			Collector:         vCollector,
+			Mapper:            d.mapper,
+			StrictAnnotations: options.StrictAnnotations,
 		}
 		validator.Validate(deleteObjs)
 
 		// Build a TaskContext for passing info between tasks
 		resourceCache := cache.NewResourceCacheMap()
 		taskContext := taskrunner.NewTaskContext(eventChannel, resourceCache)
+		if options.CircuitBreakerThreshold > 0 {
+			taskContext.SetCircuitBreaker(taskrunner.NewCircuitBreaker(options.CircuitBreakerThreshold))
+		}
 
-		klog.V(4).Infoln("destroyer building task queue...")
+		klog.V(4).Infof("destroyer building task queue (run: %s)...", options.RunID)
 		deleteFilters := []filter.ValidationFilter{
+			filter.ProtectedFilter{Key: options.ProtectedKey},
 			filter.PreventRemoveFilter{},
+			filter.InventoryUIDFilter{
+				ExpectedUIDs: invUIDs,
+			},
 			filter.InventoryPolicyPruneFilter{
 				Inv:       invInfo,
 				InvPolicy: options.InventoryPolicy,
@@ -116,6 +231,11 @@ func (d *Destroyer) Run(ctx context.Context, invInfo inventory.Info, options Des
 				DryRunStrategy:    options.DryRunStrategy,
 			},
 		}
+		if options.KeepInventory || options.KeepInventoryNamespace {
+			deleteFilters = append(deleteFilters, filter.LocalNamespacesFilter{
+				LocalNamespaces: localNamespaces(invInfo, nil),
+			})
+		}
 		taskBuilder := &solver.TaskQueueBuilder{
 			Pruner:        d.pruner,
 			DynamicClient: d.client,
@@ -127,12 +247,20 @@ func (d *Destroyer) Run(ctx context.Context, invInfo inventory.Info, options Des
 			PruneFilters:  deleteFilters,
 		}
 		opts := solver.Options{
-			Destroy:                true,
-			Prune:                  true,
-			DryRunStrategy:         options.DryRunStrategy,
-			PrunePropagationPolicy: options.DeletePropagationPolicy,
-			PruneTimeout:           options.DeleteTimeout,
-			InventoryPolicy:        options.InventoryPolicy,
+			Destroy:                        true,
+			KeepInventory:                  options.KeepInventory,
+			Prune:                          true,
+			DryRunStrategy:                 options.DryRunStrategy,
+			PrunePropagationPolicy:         options.DeletePropagationPolicy,
+			PruneGracePeriodSeconds:        options.DeleteGracePeriodSeconds,
+			PruneTimeout:                   options.DeleteTimeout,
+			PruneSkipWait:                  options.SkipDeleteWait,
+			PruneStuckDeletionThreshold:    options.DeleteStuckDeletionThreshold,
+			PruneRemoveStuckFinalizers:     options.DeleteRemoveStuckFinalizers,
+			InventoryPolicy:                options.InventoryPolicy,
+			PruneDeleteCollectionThreshold: options.DeleteCollectionThreshold,
+			PruneListDependents:            options.DeleteListDependents,
+			PruneDependentKinds:            options.DeleteDependentKinds,
 		}
 
 		// Build the ordered set of tasks to execute.
@@ -141,8 +269,8 @@ func (d *Destroyer) Run(ctx context.Context, invInfo inventory.Info, options Des
 			WithInventory(invInfo).
 			Build(taskContext, opts)
 
-		klog.V(4).Infof("validation errors: %d", len(vCollector.Errors))
-		klog.V(4).Infof("invalid objects: %d", len(vCollector.InvalidIDs))
+		klog.V(4).Infof("validation errors: %d (run: %s)", len(vCollector.Errors), options.RunID)
+		klog.V(4).Infof("invalid objects: %d (run: %s)", len(vCollector.InvalidIDs), options.RunID)
 
 		// Handle validation errors
 		switch options.ValidationPolicy {
@@ -175,7 +303,7 @@ func (d *Destroyer) Run(ctx context.Context, invInfo inventory.Info, options Des
 			},
 		}
 		// Create a new TaskStatusRunner to execute the taskQueue.
-		klog.V(4).Infoln("destroyer building TaskStatusRunner...")
+		klog.V(4).Infof("destroyer building TaskStatusRunner (run: %s)...", options.RunID)
 		deleteIDs := object.UnstructuredSetToObjMetadataSet(deleteObjs)
 		statusWatcher := d.statusWatcher
 		// Disable watcher for dry runs
@@ -183,7 +311,7 @@ func (d *Destroyer) Run(ctx context.Context, invInfo inventory.Info, options Des
 			statusWatcher = watcher.BlindStatusWatcher{}
 		}
 		runner := taskrunner.NewTaskStatusRunner(deleteIDs, statusWatcher)
-		klog.V(4).Infoln("destroyer running TaskStatusRunner...")
+		klog.V(4).Infof("destroyer running TaskStatusRunner (run: %s)...", options.RunID)
 		err = runner.Run(ctx, taskContext, taskQueue.ToChannel(), taskrunner.Options{
 			EmitStatusEvents: options.EmitStatusEvents,
 		})
@@ -191,6 +319,12 @@ func (d *Destroyer) Run(ctx context.Context, invInfo inventory.Info, options Des
 			handleError(eventChannel, err)
 			return
 		}
+		// Report the delete failures, if any, as the result of the run.
+		if options.AggregateDeleteErrors {
+			if pruneErr := taskContext.PruneError(); pruneErr != nil {
+				handleError(eventChannel, pruneErr)
+			}
+		}
 	}()
-	return eventChannel
+	return event.Buffer(event.WithRunID(eventChannel, options.RunID), options.EventBuffer)
 }
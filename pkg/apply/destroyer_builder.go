@@ -11,7 +11,7 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/kubectl/pkg/cmd/util"
 	"sigs.k8s.io/cli-utils/pkg/apply/info"
-	"sigs.k8s.io/cli-utils/pkg/apply/prune"
+	"sigs.k8s.io/cli-utils/pkg/backoff"
 	"sigs.k8s.io/cli-utils/pkg/inventory"
 	"sigs.k8s.io/cli-utils/pkg/kstatus/watcher"
 )
@@ -33,11 +33,7 @@ func (b *DestroyerBuilder) Build() (*Destroyer, error) {
 		return nil, err
 	}
 	return &Destroyer{
-		pruner: &prune.Pruner{
-			InvClient: bx.invClient,
-			Client:    bx.client,
-			Mapper:    bx.mapper,
-		},
+		pruner:        bx.newPruner(),
 		statusWatcher: bx.statusWatcher,
 		invClient:     bx.invClient,
 		mapper:        bx.mapper,
@@ -91,3 +87,19 @@ func (b *DestroyerBuilder) WithStatusWatcherFilters(filters *watcher.Filters) *D
 	b.statusWatcherFilters = filters
 	return b
 }
+
+// WithBackoffPolicy sets the backoff strategies used when retrying
+// operations against the cluster.
+func (b *DestroyerBuilder) WithBackoffPolicy(policy backoff.Policy) *DestroyerBuilder {
+	b.backoffPolicy = policy
+	return b
+}
+
+// WithDeleteRetryPolicy sets the policy used to retry the deletion of an
+// object that failed with a transient error, like throttling or a timeout.
+// If the policy has no Backoff, the delete strategy of the backoff policy is
+// used. By default, deletions are not retried.
+func (b *DestroyerBuilder) WithDeleteRetryPolicy(policy backoff.RetryPolicy) *DestroyerBuilder {
+	b.deleteRetryPolicy = policy
+	return b
+}
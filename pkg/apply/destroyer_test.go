@@ -10,7 +10,11 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
 	"sigs.k8s.io/cli-utils/pkg/apply/event"
+	"sigs.k8s.io/cli-utils/pkg/common"
 	"sigs.k8s.io/cli-utils/pkg/inventory"
 	pollevent "sigs.k8s.io/cli-utils/pkg/kstatus/polling/event"
 	"sigs.k8s.io/cli-utils/pkg/kstatus/status"
@@ -393,3 +397,210 @@ func TestDestroyerCancel(t *testing.T) {
 		})
 	}
 }
+
+func TestDestroyerKeepInventory(t *testing.T) {
+	invNamespace := testutil.Unstructured(t, `
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: test
+  uid: ns-uid
+`, testutil.AddOwningInv(t, "test"))
+	deployment := testutil.Unstructured(t, resources["deployment"], testutil.AddOwningInv(t, "test"))
+	nsID := object.UnstructuredToObjMetadata(invNamespace)
+	deploymentID := object.UnstructuredToObjMetadata(deployment)
+
+	testCases := map[string]struct {
+		options                  DestroyerOptions
+		expectedStatuses         map[object.ObjMetadata]event.DeleteEventStatus
+		expectedInventoryDeleted bool
+	}{
+		"namespace deleted by default": {
+			options: DestroyerOptions{
+				DryRunStrategy: common.DryRunClient,
+			},
+			expectedStatuses: map[object.ObjMetadata]event.DeleteEventStatus{
+				nsID:         event.DeleteSuccessful,
+				deploymentID: event.DeleteSuccessful,
+			},
+		},
+		"namespace retained with inventory": {
+			options: DestroyerOptions{
+				DryRunStrategy: common.DryRunClient,
+				KeepInventory:  true,
+			},
+			expectedStatuses: map[object.ObjMetadata]event.DeleteEventStatus{
+				nsID:         event.DeleteSkipped,
+				deploymentID: event.DeleteSuccessful,
+			},
+		},
+		"namespace retained without inventory": {
+			options: DestroyerOptions{
+				DryRunStrategy:         common.DryRunClient,
+				KeepInventoryNamespace: true,
+			},
+			expectedStatuses: map[object.ObjMetadata]event.DeleteEventStatus{
+				nsID:         event.DeleteSkipped,
+				deploymentID: event.DeleteSuccessful,
+			},
+		},
+		"inventory deleted by default": {
+			options: DestroyerOptions{
+				SkipDeleteWait: true,
+			},
+			expectedStatuses: map[object.ObjMetadata]event.DeleteEventStatus{
+				nsID:         event.DeleteSuccessful,
+				deploymentID: event.DeleteSuccessful,
+			},
+			expectedInventoryDeleted: true,
+		},
+		"inventory kept": {
+			options: DestroyerOptions{
+				SkipDeleteWait: true,
+				KeepInventory:  true,
+			},
+			expectedStatuses: map[object.ObjMetadata]event.DeleteEventStatus{
+				nsID:         event.DeleteSkipped,
+				deploymentID: event.DeleteSuccessful,
+			},
+			expectedInventoryDeleted: false,
+		},
+		"inventory deleted despite the skipped namespace": {
+			options: DestroyerOptions{
+				SkipDeleteWait:         true,
+				KeepInventoryNamespace: true,
+			},
+			expectedStatuses: map[object.ObjMetadata]event.DeleteEventStatus{
+				nsID:         event.DeleteSkipped,
+				deploymentID: event.DeleteSuccessful,
+			},
+			expectedInventoryDeleted: true,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			invInfo := inventoryInfo{
+				name:      "abc-123",
+				namespace: "test",
+				id:        "test",
+				set:       object.ObjMetadataSet{nsID, deploymentID},
+			}
+			// Send no status events, without waiting for deletion
+			statusWatcher := newFakeWatcher(nil)
+			statusWatcher.Start()
+			destroyer := newTestDestroyer(t,
+				invInfo,
+				object.UnstructuredSet{
+					invNamespace.DeepCopy(),
+					deployment.DeepCopy(),
+					inventory.InvInfoToConfigMap(invInfo.toWrapped()),
+				},
+				statusWatcher,
+			)
+
+			statuses := map[object.ObjMetadata]event.DeleteEventStatus{}
+			for e := range destroyer.Run(context.Background(), invInfo.toWrapped(), tc.options) {
+				switch e.Type {
+				case event.ErrorType:
+					t.Errorf("unexpected error event: %v", e.ErrorEvent.Err)
+				case event.DeleteType:
+					statuses[e.DeleteEvent.Identifier] = e.DeleteEvent.Status
+				}
+			}
+			assert.Equal(t, tc.expectedStatuses, statuses)
+
+			// Validate whether the inventory ConfigMap was deleted
+			fakeClient, ok := destroyer.client.(*dynamicfake.FakeDynamicClient)
+			require.True(t, ok)
+			inventoryDeleted := false
+			for _, action := range fakeClient.Actions() {
+				deleteAction, ok := action.(clienttesting.DeleteAction)
+				if ok && deleteAction.GetResource().Resource == "configmaps" &&
+					deleteAction.GetName() == invInfo.name {
+					inventoryDeleted = true
+				}
+			}
+			assert.Equal(t, tc.expectedInventoryDeleted, inventoryDeleted)
+		})
+	}
+}
+
+func TestDestroyerProtected(t *testing.T) {
+	deployment := testutil.Unstructured(t, resources["deployment"], testutil.AddOwningInv(t, "test"))
+	deployment.SetLabels(map[string]string{common.ProtectedKey: "true"})
+	secret := testutil.Unstructured(t, resources["secret"], testutil.AddOwningInv(t, "test"))
+	secret.SetAnnotations(map[string]string{
+		inventory.OwningInventoryKey: "test",
+		"example.com/keep":           "true",
+	})
+	deploymentID := object.UnstructuredToObjMetadata(deployment)
+	secretID := object.UnstructuredToObjMetadata(secret)
+
+	testCases := map[string]struct {
+		options             DestroyerOptions
+		expectedStatuses    map[object.ObjMetadata]event.DeleteEventStatus
+		expectedSkipReasons map[object.ObjMetadata]event.SkipReason
+	}{
+		"protected by the default key": {
+			options: DestroyerOptions{},
+			expectedStatuses: map[object.ObjMetadata]event.DeleteEventStatus{
+				deploymentID: event.DeleteSkipped,
+				secretID:     event.DeleteSuccessful,
+			},
+			expectedSkipReasons: map[object.ObjMetadata]event.SkipReason{
+				deploymentID: event.SkipReasonProtected,
+				secretID:     event.SkipReasonUnspecified,
+			},
+		},
+		"protected by a custom key": {
+			options: DestroyerOptions{
+				ProtectedKey: "example.com/keep",
+			},
+			expectedStatuses: map[object.ObjMetadata]event.DeleteEventStatus{
+				deploymentID: event.DeleteSuccessful,
+				secretID:     event.DeleteSkipped,
+			},
+			expectedSkipReasons: map[object.ObjMetadata]event.SkipReason{
+				deploymentID: event.SkipReasonUnspecified,
+				secretID:     event.SkipReasonProtected,
+			},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			invInfo := inventoryInfo{
+				name:      "abc-123",
+				namespace: "test",
+				id:        "test",
+				set:       object.ObjMetadataSet{deploymentID, secretID},
+			}
+			destroyer := newTestDestroyer(t,
+				invInfo,
+				object.UnstructuredSet{
+					deployment.DeepCopy(),
+					secret.DeepCopy(),
+					inventory.InvInfoToConfigMap(invInfo.toWrapped()),
+				},
+				newFakeWatcher(nil),
+			)
+
+			// Dry-run to skip waiting for deletion
+			tc.options.DryRunStrategy = common.DryRunClient
+			statuses := map[object.ObjMetadata]event.DeleteEventStatus{}
+			skipReasons := map[object.ObjMetadata]event.SkipReason{}
+			for e := range destroyer.Run(context.Background(), invInfo.toWrapped(), tc.options) {
+				switch e.Type {
+				case event.ErrorType:
+					t.Errorf("unexpected error event: %v", e.ErrorEvent.Err)
+				case event.DeleteType:
+					statuses[e.DeleteEvent.Identifier] = e.DeleteEvent.Status
+					skipReasons[e.DeleteEvent.Identifier] = e.DeleteEvent.SkipReason
+				}
+			}
+			assert.Equal(t, tc.expectedStatuses, statuses)
+			assert.Equal(t, tc.expectedSkipReasons, skipReasons)
+		})
+	}
+}
@@ -0,0 +1,229 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package error
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
+)
+
+// ConflictError is returned when a server-side apply fails, because fields of
+// the object are owned by other field managers. The Conflicts report who owns
+// each conflicting field.
+type ConflictError struct {
+	Conflicts []event.FieldConflict
+	err       error
+}
+
+func (e *ConflictError) Error() string {
+	return e.err.Error()
+}
+
+func (e *ConflictError) Unwrap() error {
+	return e.err
+}
+
+func NewConflictError(err error, conflicts []event.FieldConflict) *ConflictError {
+	return &ConflictError{Conflicts: conflicts, err: err}
+}
+
+// conflictManagerRegexp matches the field manager in the message of a field
+// manager conflict, like `conflict with "kubectl" using apps/v1`.
+var conflictManagerRegexp = regexp.MustCompile(`^conflict with ("(?:[^"\\]|\\.)*")`)
+
+// ParseFieldConflicts returns the field manager conflicts of an error
+// returned by a server-side apply, with the values of the conflicting fields
+// in the live object, if not nil. Returns nil if the error is not a field
+// manager conflict.
+func ParseFieldConflicts(err error, live *unstructured.Unstructured) []event.FieldConflict {
+	if !apierrors.IsConflict(err) {
+		return nil
+	}
+	var statusErr apierrors.APIStatus
+	if !errors.As(err, &statusErr) || statusErr.Status().Details == nil {
+		return nil
+	}
+	var conflicts []event.FieldConflict
+	for _, cause := range statusErr.Status().Details.Causes {
+		if cause.Type != metav1.CauseTypeFieldManagerConflict {
+			continue
+		}
+		conflict := event.FieldConflict{
+			Field:   cause.Field,
+			Message: cause.Message,
+		}
+		if match := conflictManagerRegexp.FindStringSubmatch(cause.Message); match != nil {
+			conflict.Manager, _ = strconv.Unquote(match[1])
+		}
+		if live != nil {
+			conflict.Value, _, _ = FieldValue(live.Object, cause.Field)
+		}
+		conflicts = append(conflicts, conflict)
+	}
+	return conflicts
+}
+
+// FieldValue returns the value of the field in the object, and true if the
+// field was found. The path uses the format of field manager conflicts:
+// fields are prefixed with a dot, list items are selected by their keys
+// (`[name="foo"]`), by their value (`[="foo"]`) or by their index (`[0]`).
+// Returns an error if the path is malformed.
+func FieldValue(obj map[string]interface{}, path string) (interface{}, bool, error) {
+	var value interface{} = obj
+	for rest := path; rest != ""; {
+		switch rest[0] {
+		case '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			name := rest[1 : end+1]
+			rest = rest[end+1:]
+			m, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, false, nil
+			}
+			if value, ok = m[name]; !ok {
+				return nil, false, nil
+			}
+		case '[':
+			selector, remaining, err := splitSelector(rest)
+			if err != nil {
+				return nil, false, fmt.Errorf("invalid field path %q: %w", path, err)
+			}
+			rest = remaining
+			list, ok := value.([]interface{})
+			if !ok {
+				return nil, false, nil
+			}
+			if value, ok, err = selectItem(list, selector); err != nil {
+				return nil, false, fmt.Errorf("invalid field path %q: %w", path, err)
+			} else if !ok {
+				return nil, false, nil
+			}
+		default:
+			return nil, false, fmt.Errorf("invalid field path %q: unexpected %q", path, rest[0])
+		}
+	}
+	return value, true, nil
+}
+
+// splitSelector returns the content of the list item selector at the start
+// of the path, without brackets, and the rest of the path.
+func splitSelector(path string) (string, string, error) {
+	inString := false
+	for i := 1; i < len(path); i++ {
+		switch {
+		case inString && path[i] == '\\':
+			i++
+		case path[i] == '"':
+			inString = !inString
+		case !inString && path[i] == ']':
+			return path[1:i], path[i+1:], nil
+		}
+	}
+	return "", "", fmt.Errorf("unterminated list item selector %q", path)
+}
+
+// selectItem returns the list item selected by the selector.
+func selectItem(list []interface{}, selector string) (interface{}, bool, error) {
+	// Index
+	if index, err := strconv.Atoi(selector); err == nil {
+		if index < 0 || index >= len(list) {
+			return nil, false, nil
+		}
+		return list[index], true, nil
+	}
+	// Value
+	if strings.HasPrefix(selector, "=") {
+		var want interface{}
+		if err := json.Unmarshal([]byte(selector[1:]), &want); err != nil {
+			return nil, false, fmt.Errorf("invalid list item value %q: %w", selector[1:], err)
+		}
+		for _, item := range list {
+			if jsonEqual(item, want) {
+				return item, true, nil
+			}
+		}
+		return nil, false, nil
+	}
+	// Keys
+	keyValues, err := splitKeys(selector)
+	if err != nil {
+		return nil, false, err
+	}
+	keys := make(map[string]interface{}, len(keyValues))
+	for key, value := range keyValues {
+		var want interface{}
+		if err := json.Unmarshal([]byte(value), &want); err != nil {
+			return nil, false, fmt.Errorf("invalid list item key value %q: %w", value, err)
+		}
+		keys[key] = want
+	}
+	for _, item := range list {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		matches := true
+		for key, want := range keys {
+			if !jsonEqual(m[key], want) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return item, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// splitKeys splits the keys of a selector, like `name="foo",port=80`, into
+// the keys and their JSON values.
+func splitKeys(selector string) (map[string]string, error) {
+	keys := make(map[string]string)
+	inString := false
+	start := 0
+	for i := 0; i <= len(selector); i++ {
+		switch {
+		case i < len(selector) && inString && selector[i] == '\\':
+			i++
+			continue
+		case i < len(selector) && selector[i] == '"':
+			inString = !inString
+			continue
+		case i < len(selector) && (inString || selector[i] != ','):
+			continue
+		}
+		key, value, found := strings.Cut(selector[start:i], "=")
+		if !found || key == "" {
+			return nil, fmt.Errorf("invalid list item key %q", selector[start:i])
+		}
+		keys[key] = value
+		start = i + 1
+	}
+	return keys, nil
+}
+
+// jsonEqual returns true if the values are equal when encoded as JSON, which
+// ignores the difference between integer and float numbers.
+func jsonEqual(a, b interface{}) bool {
+	aJSON, aErr := json.Marshal(a)
+	bJSON, bErr := json.Marshal(b)
+	if aErr != nil || bErr != nil {
+		return reflect.DeepEqual(a, b)
+	}
+	return string(aJSON) == string(bJSON)
+}
@@ -0,0 +1,185 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package error
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
+)
+
+var liveDeployment = map[string]interface{}{
+	"apiVersion": "apps/v1",
+	"kind":       "Deployment",
+	"metadata": map[string]interface{}{
+		"name":      "foo",
+		"namespace": "default",
+	},
+	"spec": map[string]interface{}{
+		"replicas": int64(3),
+		"template": map[string]interface{}{
+			"spec": map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{
+						"name":  "sidecar",
+						"image": "sidecar:1",
+					},
+					map[string]interface{}{
+						"name":  "nginx",
+						"image": "nginx:1.25",
+						"ports": []interface{}{
+							map[string]interface{}{
+								"containerPort": int64(80),
+								"protocol":      "TCP",
+							},
+						},
+						"args": []interface{}{"--verbose", "--port=80"},
+					},
+				},
+			},
+		},
+	},
+}
+
+func TestFieldValue(t *testing.T) {
+	testCases := map[string]struct {
+		path          string
+		expected      interface{}
+		expectedFound bool
+		expectedError string
+	}{
+		"field": {
+			path:          ".spec.replicas",
+			expected:      int64(3),
+			expectedFound: true,
+		},
+		"list item by key": {
+			path:          `.spec.template.spec.containers[name="nginx"].image`,
+			expected:      "nginx:1.25",
+			expectedFound: true,
+		},
+		"list item by multiple keys": {
+			path:          `.spec.template.spec.containers[name="nginx"].ports[containerPort=80,protocol="TCP"].protocol`,
+			expected:      "TCP",
+			expectedFound: true,
+		},
+		"list item by value": {
+			path:          `.spec.template.spec.containers[name="nginx"].args[="--port=80"]`,
+			expected:      "--port=80",
+			expectedFound: true,
+		},
+		"list item by index": {
+			path:          `.spec.template.spec.containers[0].name`,
+			expected:      "sidecar",
+			expectedFound: true,
+		},
+		"missing field": {
+			path: ".spec.paused",
+		},
+		"missing list item": {
+			path: `.spec.template.spec.containers[name="other"].image`,
+		},
+		"not a list": {
+			path: `.spec.replicas[0]`,
+		},
+		"unterminated selector": {
+			path: `.spec.template.spec.containers[name="nginx"`,
+			expectedError: `invalid field path ".spec.template.spec.containers[name=\"nginx\"": ` +
+				`unterminated list item selector "[name=\"nginx\""`,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			value, found, err := FieldValue(liveDeployment, tc.path)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedFound, found)
+			assert.Equal(t, tc.expected, value)
+		})
+	}
+}
+
+func TestParseFieldConflicts(t *testing.T) {
+	conflictErr := &apierrors.StatusError{ErrStatus: metav1.Status{
+		Status: metav1.StatusFailure,
+		Code:   409,
+		Reason: metav1.StatusReasonConflict,
+		Details: &metav1.StatusDetails{
+			Causes: []metav1.StatusCause{
+				{
+					Type:    metav1.CauseTypeFieldManagerConflict,
+					Message: `conflict with "kubectl-client-side-apply" using apps/v1`,
+					Field:   ".spec.replicas",
+				},
+				{
+					Type:    metav1.CauseTypeFieldManagerConflict,
+					Message: `conflict with "hpa \"controller\""`,
+					Field:   ".spec.paused",
+				},
+			},
+		},
+	}}
+
+	testCases := map[string]struct {
+		err      error
+		live     *unstructured.Unstructured
+		expected []event.FieldConflict
+	}{
+		"not a conflict": {
+			err: errors.New("apply failed"),
+		},
+		"other conflict": {
+			err: apierrors.NewConflict(schema.GroupResource{Resource: "deployments"}, "foo", errors.New("modified")),
+		},
+		"conflicts with live values": {
+			err:  conflictErr,
+			live: &unstructured.Unstructured{Object: liveDeployment},
+			expected: []event.FieldConflict{
+				{
+					Field:   ".spec.replicas",
+					Message: `conflict with "kubectl-client-side-apply" using apps/v1`,
+					Manager: "kubectl-client-side-apply",
+					Value:   int64(3),
+				},
+				{
+					Field:   ".spec.paused",
+					Message: `conflict with "hpa \"controller\""`,
+					Manager: `hpa "controller"`,
+				},
+			},
+		},
+		"conflicts without live object": {
+			err: conflictErr,
+			expected: []event.FieldConflict{
+				{
+					Field:   ".spec.replicas",
+					Message: `conflict with "kubectl-client-side-apply" using apps/v1`,
+					Manager: "kubectl-client-side-apply",
+				},
+				{
+					Field:   ".spec.paused",
+					Message: `conflict with "hpa \"controller\""`,
+					Manager: `hpa "controller"`,
+				},
+			},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ParseFieldConflicts(tc.err, tc.live))
+		})
+	}
+}
@@ -2,6 +2,17 @@
 // SPDX-License-Identifier: Apache-2.0
 package error
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/cli-utils/pkg/common"
+	"sigs.k8s.io/cli-utils/pkg/multierror"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
 type UnknownTypeError struct {
 	err error
 }
@@ -10,6 +21,10 @@ func (e *UnknownTypeError) Error() string {
 	return e.err.Error()
 }
 
+func (e *UnknownTypeError) Unwrap() error {
+	return e.err
+}
+
 func NewUnknownTypeError(err error) *UnknownTypeError {
 	return &UnknownTypeError{err: err}
 }
@@ -22,6 +37,10 @@ func (e *ApplyRunError) Error() string {
 	return e.err.Error()
 }
 
+func (e *ApplyRunError) Unwrap() error {
+	return e.err
+}
+
 func NewApplyRunError(err error) *ApplyRunError {
 	return &ApplyRunError{err: err}
 }
@@ -34,6 +53,126 @@ func (e *InitializeApplyOptionError) Error() string {
 	return e.err.Error()
 }
 
+func (e *InitializeApplyOptionError) Unwrap() error {
+	return e.err
+}
+
 func NewInitializeApplyOptionError(err error) *InitializeApplyOptionError {
 	return &InitializeApplyOptionError{err: err}
 }
+
+// ObjectApplyError is the cause of a failed apply of an object.
+type ObjectApplyError struct {
+	Identifier object.ObjMetadata
+	err        error
+}
+
+func (e *ObjectApplyError) Error() string {
+	return fmt.Sprintf("failed to apply %s: %v", e.Identifier, e.err)
+}
+
+func (e *ObjectApplyError) Unwrap() error {
+	return e.err
+}
+
+func NewObjectApplyError(id object.ObjMetadata, err error) *ObjectApplyError {
+	return &ObjectApplyError{Identifier: id, err: err}
+}
+
+// ObjectPruneError is the cause of a failed prune or delete of an object.
+type ObjectPruneError struct {
+	Identifier object.ObjMetadata
+	err        error
+}
+
+func (e *ObjectPruneError) Error() string {
+	return fmt.Sprintf("failed to delete %s: %v", e.Identifier, e.err)
+}
+
+func (e *ObjectPruneError) Unwrap() error {
+	return e.err
+}
+
+func NewObjectPruneError(id object.ObjMetadata, err error) *ObjectPruneError {
+	return &ObjectPruneError{Identifier: id, err: err}
+}
+
+// PruneError is returned when some objects failed to be pruned or deleted.
+// The other objects are still deleted, and the failed objects are kept in
+// the inventory, so that the next run deletes them again.
+type PruneError struct {
+	// Causes are the ObjectPruneErrors of the failed objects, in the order
+	// they failed.
+	Causes []*ObjectPruneError
+}
+
+func (e *PruneError) Error() string {
+	return multierror.New(e.Unwrap()...).Error()
+}
+
+func (e *PruneError) Unwrap() []error {
+	errs := make([]error, len(e.Causes))
+	for i, cause := range e.Causes {
+		errs[i] = cause
+	}
+	return errs
+}
+
+// Identifiers returns the identifiers of the objects that failed to be
+// pruned or deleted.
+func (e *PruneError) Identifiers() object.ObjMetadataSet {
+	ids := make(object.ObjMetadataSet, len(e.Causes))
+	for i, cause := range e.Causes {
+		ids[i] = cause.Identifier
+	}
+	return ids
+}
+
+func NewPruneError(causes ...*ObjectPruneError) *PruneError {
+	return &PruneError{Causes: causes}
+}
+
+// ImmutableFieldError is returned when an object could not be updated,
+// because the update changes an immutable field. The object can only be
+// updated by deleting and re-creating it.
+type ImmutableFieldError struct {
+	err error
+}
+
+func (e *ImmutableFieldError) Error() string {
+	return fmt.Sprintf("%v (set the %q annotation to \"true\" to re-create the object)",
+		e.err, common.RecreateAnnotation)
+}
+
+func (e *ImmutableFieldError) Unwrap() error {
+	return e.err
+}
+
+func NewImmutableFieldError(err error) *ImmutableFieldError {
+	return &ImmutableFieldError{err: err}
+}
+
+// immutableFieldMessage is the message of the validation errors returned by
+// the api server for changes to immutable fields.
+const immutableFieldMessage = "field is immutable"
+
+// IsImmutableFieldError returns true if the error was returned by the api
+// server because an update changes an immutable field.
+func IsImmutableFieldError(err error) bool {
+	var immutableErr *ImmutableFieldError
+	if errors.As(err, &immutableErr) {
+		return true
+	}
+	if !apierrors.IsInvalid(err) {
+		return false
+	}
+	var statusErr apierrors.APIStatus
+	if errors.As(err, &statusErr) && statusErr.Status().Details != nil {
+		for _, cause := range statusErr.Status().Details.Causes {
+			if strings.Contains(cause.Message, immutableFieldMessage) {
+				return true
+			}
+		}
+	}
+	return strings.Contains(err.Error(), immutableFieldMessage)
+}
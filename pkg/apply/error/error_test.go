@@ -0,0 +1,50 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package error
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+func TestPruneError(t *testing.T) {
+	pod := object.ObjMetadata{
+		GroupKind: schema.GroupKind{Kind: "Pod"},
+		Namespace: "default",
+		Name:      "foo",
+	}
+	role := object.ObjMetadata{
+		GroupKind: schema.GroupKind{Group: "rbac.authorization.k8s.io", Kind: "Role"},
+		Namespace: "default",
+		Name:      "bar",
+	}
+	forbidden := apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, "foo", errors.New("denied"))
+	denied := errors.New("admission webhook denied the request")
+
+	err := error(NewPruneError(
+		NewObjectPruneError(pod, forbidden),
+		NewObjectPruneError(role, denied),
+	))
+
+	assert.Equal(t, "2 errors:\n"+
+		"- failed to delete default_foo__Pod: "+forbidden.Error()+"\n"+
+		"- failed to delete default_bar_rbac.authorization.k8s.io_Role: admission webhook denied the request\n",
+		err.Error())
+	assert.True(t, errors.Is(err, forbidden))
+	assert.True(t, errors.Is(err, denied))
+
+	var objErr *ObjectPruneError
+	require.True(t, errors.As(err, &objErr))
+	assert.Equal(t, pod, objErr.Identifier)
+
+	var pruneErr *PruneError
+	require.True(t, errors.As(err, &pruneErr))
+	assert.Equal(t, object.ObjMetadataSet{pod, role}, pruneErr.Identifiers())
+}
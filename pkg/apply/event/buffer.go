@@ -0,0 +1,172 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package event
+
+import (
+	"sync/atomic"
+)
+
+// OverflowPolicy determines what happens to events when the buffer of an
+// event channel is full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock blocks the producer until the consumer has received
+	// enough events to make room in the buffer.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDrop drops new events when the buffer is full. Only status
+	// events, and other events that neither report an error nor start or
+	// finish an action group, are dropped. The others are added anyway.
+	OverflowDrop
+	// OverflowCoalesceStatus replaces the buffered status event of an object
+	// with its new status event when the buffer is full, so only the latest
+	// status of each object is kept. Other events block the producer, like
+	// with OverflowBlock.
+	OverflowCoalesceStatus
+)
+
+// BufferOptions configures the buffering of an event channel.
+type BufferOptions struct {
+	// Size is the number of events buffered between the producer and the
+	// consumer. Zero means unbuffered: every event blocks the producer until
+	// the consumer receives it.
+	Size int
+
+	// Policy determines what happens to new events when the buffer is full.
+	Policy OverflowPolicy
+
+	// Dropped, if not nil, is incremented for every event that is dropped,
+	// or replaced by a newer status event.
+	Dropped *atomic.Int64
+}
+
+// Buffer returns a channel that receives the events sent to the passed
+// channel, buffered according to the options. The returned channel is
+// closed after the passed channel is closed and all the buffered events
+// have been received. If the options are unbuffered, the passed channel is
+// returned as is.
+func Buffer(in <-chan Event, o BufferOptions) <-chan Event {
+	if o.Size <= 0 {
+		return in
+	}
+	out := make(chan Event)
+	go func() {
+		defer close(out)
+		b := &buffer{options: o}
+		for in != nil || len(b.queue) > 0 {
+			// Stop receiving while the buffer is full, to block the producer.
+			recvCh := in
+			if b.full() {
+				recvCh = nil
+			}
+			var sendCh chan<- Event
+			var next Event
+			if len(b.queue) > 0 {
+				sendCh = out
+				next = b.queue[0]
+			}
+			select {
+			case e, ok := <-recvCh:
+				if !ok {
+					in = nil
+					continue
+				}
+				b.add(e)
+			case sendCh <- next:
+				b.queue = b.queue[1:]
+			}
+		}
+	}()
+	return out
+}
+
+// buffer is the queue of events that have been received from the producer,
+// but not yet by the consumer.
+type buffer struct {
+	options BufferOptions
+	queue   []Event
+}
+
+// full returns true if no more events can be added to the queue.
+func (b *buffer) full() bool {
+	switch b.options.Policy {
+	case OverflowDrop:
+		// New events are dropped instead.
+		return false
+	case OverflowCoalesceStatus:
+		// A status event may be added to a full queue, if it can be
+		// coalesced. Otherwise, it is added anyway, and the producer is
+		// blocked until there is room again.
+		return len(b.queue) > b.options.Size
+	default:
+		return len(b.queue) >= b.options.Size
+	}
+}
+
+// add adds the event to the queue, unless it is dropped or coalesced
+// according to the overflow policy.
+func (b *buffer) add(e Event) {
+	if len(b.queue) < b.options.Size {
+		b.queue = append(b.queue, e)
+		return
+	}
+	switch b.options.Policy {
+	case OverflowDrop:
+		if droppable(e) {
+			b.drop()
+			return
+		}
+	case OverflowCoalesceStatus:
+		if e.Type != StatusType {
+			break
+		}
+		// Only the trailing status events are coalesced, to keep the status
+		// events in order with the other events.
+		for i := len(b.queue) - 1; i >= 0 && b.queue[i].Type == StatusType; i-- {
+			if b.queue[i].StatusEvent.Identifier == e.StatusEvent.Identifier {
+				b.queue[i] = e
+				b.drop()
+				return
+			}
+		}
+	}
+	b.queue = append(b.queue, e)
+}
+
+// droppable returns true if the event may be dropped by OverflowDrop.
+// Status events are superseded by the next status event of the same object,
+// so they are always droppable. The init, error and action group events,
+// the events that report an error, and the wait events of objects that
+// failed or timed out, are never dropped, because the consumer relies on
+// them to report the outcome of the run.
+func droppable(e Event) bool {
+	switch e.Type {
+	case StatusType:
+		return true
+	case InitType, ErrorType, ActionGroupType:
+		return false
+	case ApplyType:
+		return e.ApplyEvent.Error == nil
+	case PruneType:
+		return e.PruneEvent.Error == nil
+	case DeleteType:
+		return e.DeleteEvent.Error == nil
+	case ValidationType:
+		return e.ValidationEvent.Error == nil
+	case RollbackType:
+		return e.RollbackEvent.Error == nil
+	case StuckDeletionType:
+		return e.StuckDeletionEvent.Error == nil
+	case WaitType:
+		return e.WaitEvent.Status != ReconcileFailed && e.WaitEvent.Status != ReconcileTimeout
+	default:
+		return true
+	}
+}
+
+func (b *buffer) drop() {
+	if b.options.Dropped != nil {
+		b.options.Dropped.Add(1)
+	}
+}
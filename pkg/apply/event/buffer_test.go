@@ -0,0 +1,156 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package event
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+func statusEvent(name string, generation int) Event {
+	return Event{
+		Type: StatusType,
+		StatusEvent: StatusEvent{
+			Identifier: object.ObjMetadata{
+				GroupKind: schema.GroupKind{Kind: "ConfigMap"},
+				Namespace: "default",
+				Name:      name,
+			},
+			Error: fmt.Errorf("generation %d", generation),
+		},
+	}
+}
+
+func TestBuffer(t *testing.T) {
+	errorEvent := Event{
+		Type:       ErrorType,
+		ErrorEvent: ErrorEvent{Err: errors.New("failed")},
+	}
+	applyEvent := Event{Type: ApplyType}
+	failedApplyEvent := Event{
+		Type:       ApplyType,
+		ApplyEvent: ApplyEvent{Error: errors.New("apply failed")},
+	}
+	waitEvent := Event{
+		Type:      WaitType,
+		WaitEvent: WaitEvent{Status: ReconcileSuccessful},
+	}
+	failedWaitEvent := Event{
+		Type:      WaitType,
+		WaitEvent: WaitEvent{Status: ReconcileFailed},
+	}
+	timeoutWaitEvent := Event{
+		Type:      WaitType,
+		WaitEvent: WaitEvent{Status: ReconcileTimeout},
+	}
+	actionGroupEvent := Event{
+		Type:             ActionGroupType,
+		ActionGroupEvent: ActionGroupEvent{GroupName: "apply-0", Status: Finished},
+	}
+
+	testCases := map[string]struct {
+		options BufferOptions
+		events  []Event
+		// pending is the number of events the producer is blocked on, while
+		// the consumer is not receiving.
+		pending         int
+		expectedEvents  []Event
+		expectedDropped int64
+	}{
+		"block": {
+			options: BufferOptions{Size: 2, Policy: OverflowBlock},
+			events: []Event{
+				statusEvent("a", 1), statusEvent("a", 2), applyEvent, errorEvent,
+			},
+			pending: 2,
+			expectedEvents: []Event{
+				statusEvent("a", 1), statusEvent("a", 2), applyEvent, errorEvent,
+			},
+		},
+		"drop": {
+			options: BufferOptions{Size: 2, Policy: OverflowDrop},
+			events: []Event{
+				statusEvent("a", 1), applyEvent, statusEvent("a", 2), errorEvent, applyEvent,
+			},
+			expectedEvents: []Event{
+				statusEvent("a", 1), applyEvent, errorEvent,
+			},
+			expectedDropped: 2,
+		},
+		"drop keeps failed and action group events": {
+			options: BufferOptions{Size: 1, Policy: OverflowDrop},
+			events: []Event{
+				applyEvent, failedApplyEvent, statusEvent("a", 1), actionGroupEvent, applyEvent,
+			},
+			expectedEvents: []Event{
+				applyEvent, failedApplyEvent, actionGroupEvent,
+			},
+			expectedDropped: 2,
+		},
+		"drop keeps failed and timed out wait events": {
+			options: BufferOptions{Size: 1, Policy: OverflowDrop},
+			events: []Event{
+				waitEvent, failedWaitEvent, waitEvent, timeoutWaitEvent, waitEvent,
+			},
+			expectedEvents: []Event{
+				waitEvent, failedWaitEvent, timeoutWaitEvent,
+			},
+			expectedDropped: 2,
+		},
+		"coalesce status": {
+			options: BufferOptions{Size: 3, Policy: OverflowCoalesceStatus},
+			events: []Event{
+				applyEvent, statusEvent("a", 1), statusEvent("b", 1),
+				statusEvent("a", 2), statusEvent("b", 2), statusEvent("a", 3),
+				statusEvent("c", 1), applyEvent,
+			},
+			// The status event of c is added to the full buffer, and blocks
+			// the producer until there is room.
+			pending: 1,
+			expectedEvents: []Event{
+				applyEvent, statusEvent("a", 3), statusEvent("b", 2),
+				statusEvent("c", 1), applyEvent,
+			},
+			expectedDropped: 3,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			in := make(chan Event, len(tc.events))
+			for _, e := range tc.events {
+				in <- e
+			}
+			close(in)
+			dropped := &atomic.Int64{}
+			tc.options.Dropped = dropped
+			out := Buffer(in, tc.options)
+
+			// Wait for the buffer to fill up before receiving.
+			require.Eventually(t, func() bool {
+				return len(in) == tc.pending
+			}, time.Second, time.Millisecond)
+
+			var received []Event
+			for e := range out {
+				received = append(received, e)
+			}
+			assert.Equal(t, tc.expectedEvents, received)
+			assert.Equal(t, tc.expectedDropped, dropped.Load())
+		})
+	}
+}
+
+func TestBufferUnbuffered(t *testing.T) {
+	in := make(chan Event)
+	assert.Equal(t, (<-chan Event)(in), Buffer(in, BufferOptions{}))
+}
@@ -4,6 +4,7 @@
 package event
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -27,6 +28,9 @@ const (
 	DeleteType
 	WaitType
 	ValidationType
+	RollbackType
+	AdoptType
+	StuckDeletionType
 )
 
 // Event is the type of the objects that will be returned through
@@ -37,6 +41,10 @@ type Event struct {
 	// Type is the type of event.
 	Type Type
 
+	// RunID is the ID of the apply or destroy run that sent the event, to
+	// correlate the events with the logs and the objects of the run.
+	RunID string
+
 	// InitEvent contains information about which resources will
 	// be applied/pruned.
 	InitEvent InitEvent
@@ -70,6 +78,18 @@ type Event struct {
 
 	// ValidationEvent contains information about validation errors.
 	ValidationEvent ValidationEvent
+
+	// RollbackEvent contains information about objects that have been
+	// rolled back after a failed apply.
+	RollbackEvent RollbackEvent
+
+	// AdoptEvent contains information about existing objects that were
+	// not owned by the inventory, and were adopted by the apply.
+	AdoptEvent AdoptEvent
+
+	// StuckDeletionEvent contains information about deleted objects that
+	// are still terminating, because of their remaining finalizers.
+	StuckDeletionEvent StuckDeletionEvent
 }
 
 // String returns a string suitable for logging
@@ -94,6 +114,12 @@ func (e Event) String() string {
 		sb.WriteString(e.WaitEvent.String())
 	case ValidationType:
 		sb.WriteString(e.ValidationEvent.String())
+	case RollbackType:
+		sb.WriteString(e.RollbackEvent.String())
+	case AdoptType:
+		sb.WriteString(e.AdoptEvent.String())
+	case StuckDeletionType:
+		sb.WriteString(e.StuckDeletionEvent.String())
 	}
 	return sb.String()
 }
@@ -180,6 +206,36 @@ func (we WaitEvent) String() string {
 		we.GroupName, we.Status, we.Identifier)
 }
 
+// WaitResult lists which objects of a wait group reconciled, and which did
+// not, so that a run that proceeded after a wait group can report its
+// partial success.
+type WaitResult struct {
+	// Reconciled are the objects that reached the condition of the group.
+	Reconciled object.ObjMetadataSet
+	// Skipped are the objects that were not waited on, e.g. because they
+	// failed to apply.
+	Skipped object.ObjMetadataSet
+	// Failed are the objects that failed to reconcile.
+	Failed object.ObjMetadataSet
+	// TimedOut are the objects that did not reconcile before their timeout.
+	TimedOut object.ObjMetadataSet
+	// Pending are the objects that were still reconciling when the group
+	// was cancelled.
+	Pending object.ObjMetadataSet
+}
+
+// Incomplete returns true if any object failed, timed out or was still
+// pending.
+func (wr WaitResult) Incomplete() bool {
+	return len(wr.Failed) > 0 || len(wr.TimedOut) > 0 || len(wr.Pending) > 0
+}
+
+// String returns a string suitable for logging
+func (wr WaitResult) String() string {
+	return fmt.Sprintf("WaitResult{ Reconciled: %d, Skipped: %d, Failed: %d, TimedOut: %d, Pending: %d }",
+		len(wr.Reconciled), len(wr.Skipped), len(wr.Failed), len(wr.TimedOut), len(wr.Pending))
+}
+
 //go:generate stringer -type=ActionGroupEventStatus
 type ActionGroupEventStatus int
 
@@ -192,6 +248,9 @@ type ActionGroupEvent struct {
 	GroupName string
 	Action    ResourceAction
 	Status    ActionGroupEventStatus
+	// WaitResult summarizes the outcome of the wait group. Only set on the
+	// Finished event of wait groups.
+	WaitResult *WaitResult
 }
 
 // String returns a string suitable for logging
@@ -216,6 +275,26 @@ type ApplyEvent struct {
 	Status     ApplyEventStatus
 	Resource   *unstructured.Unstructured
 	Error      error
+	// SkipReason is the reason the object was skipped, if the Status is
+	// ApplySkipped.
+	SkipReason SkipReason
+	// ForcedConflicts are the field conflicts with other field managers that
+	// were overridden by a server-side apply with ForceConflicts enabled.
+	// Only set if ReportForcedConflicts is enabled.
+	ForcedConflicts []FieldConflict
+}
+
+// FieldConflict describes a field owned by another field manager.
+type FieldConflict struct {
+	// Field is the path of the conflicting field.
+	Field string
+	// Message describes the conflict, including the other field manager.
+	Message string
+	// Manager is the name of the other field manager that owns the field.
+	Manager string
+	// Value is the value of the field in the live object, or nil if
+	// unknown.
+	Value interface{}
 }
 
 // String returns a string suitable for logging
@@ -224,6 +303,10 @@ func (ae ApplyEvent) String() string {
 		return fmt.Sprintf("ApplyEvent{ GroupName: %q, Status: %q, Identifier: %q, Error: %q }",
 			ae.GroupName, ae.Status, ae.Identifier, ae.Error)
 	}
+	if len(ae.ForcedConflicts) > 0 {
+		return fmt.Sprintf("ApplyEvent{ GroupName: %q, Status: %q, Identifier: %q, ForcedConflicts: %d }",
+			ae.GroupName, ae.Status, ae.Identifier, len(ae.ForcedConflicts))
+	}
 	return fmt.Sprintf("ApplyEvent{ GroupName: %q, Status: %q, Identifier: %q }",
 		ae.GroupName, ae.Status, ae.Identifier)
 }
@@ -269,6 +352,13 @@ type PruneEvent struct {
 	Status     PruneEventStatus
 	Object     *unstructured.Unstructured
 	Error      error
+	// SkipReason is the reason the object was skipped, if the Status is
+	// PruneSkipped.
+	SkipReason SkipReason
+	// Dependents are the objects that the garbage collector deletes with
+	// the object, because they are owned by it, if the Status is PruneSuccessful and
+	// the dependents were listed.
+	Dependents object.ObjMetadataSet
 }
 
 // String returns a string suitable for logging
@@ -297,6 +387,13 @@ type DeleteEvent struct {
 	Status     DeleteEventStatus
 	Object     *unstructured.Unstructured
 	Error      error
+	// SkipReason is the reason the object was skipped, if the Status is
+	// DeleteSkipped.
+	SkipReason SkipReason
+	// Dependents are the objects that the garbage collector deletes with
+	// the object, because they are owned by it, if the Status is DeleteSuccessful and
+	// the dependents were listed.
+	Dependents object.ObjMetadataSet
 }
 
 // String returns a string suitable for logging
@@ -309,6 +406,77 @@ func (de DeleteEvent) String() string {
 		de.GroupName, de.Status, de.Identifier)
 }
 
+// SkipReason is a machine-readable code of the reason an object was skipped,
+// so that consumers of the events can branch on it without parsing the
+// error message.
+//
+//go:generate stringer -type=SkipReason -linecomment
+type SkipReason int
+
+const (
+	// SkipReasonUnspecified is the reason of the events that were not
+	// skipped, and of the objects skipped by filters without a reason code.
+	SkipReasonUnspecified SkipReason = iota // Unspecified
+	// SkipReasonLocalPolicyPreventedDeletion means the lifecycle annotation
+	// of the object prevented its deletion.
+	SkipReasonLocalPolicyPreventedDeletion // LocalPolicyPreventedDeletion
+	// SkipReasonInventoryPolicyPreventedActuation means the object belongs to
+	// another inventory, and the inventory policy prevented its actuation.
+	SkipReasonInventoryPolicyPreventedActuation // InventoryPolicyPreventedActuation
+	// SkipReasonNamespaceInUse means the namespace is still used by the
+	// objects being applied, so it was not deleted.
+	SkipReasonNamespaceInUse // NamespaceInUse
+	// SkipReasonDependencyPreventedActuation means a dependency of the
+	// object was skipped or failed.
+	SkipReasonDependencyPreventedActuation // DependencyPreventedActuation
+	// SkipReasonDependencyActuationMismatch means a dependency of the object
+	// is scheduled for the opposite actuation (apply or delete).
+	SkipReasonDependencyActuationMismatch // DependencyActuationMismatch
+	// SkipReasonApplyPreventedDeletion means the object was applied in the
+	// same run, so it was not deleted.
+	SkipReasonApplyPreventedDeletion // ApplyPreventedDeletion
+	// SkipReasonApplyFailurePreventedDeletion means the object failed to
+	// apply, so it was not deleted.
+	SkipReasonApplyFailurePreventedDeletion // ApplyFailurePreventedDeletion
+	// SkipReasonUIDMismatch means the live object is not the object in the
+	// inventory.
+	SkipReasonUIDMismatch // UIDMismatch
+	// SkipReasonNotApplied means the test resource was not applied, so it
+	// was not cleaned up.
+	SkipReasonNotApplied // NotApplied
+	// SkipReasonProtected means the object is protected by a label or
+	// annotation, so it was not deleted.
+	SkipReasonProtected // Protected
+	// SkipReasonOutOfScope means the namespace or the GroupKind of the
+	// object is outside the prune scope, so it was not pruned.
+	SkipReasonOutOfScope // OutOfScope
+	// SkipReasonOwnershipTransferred means the object was adopted by another
+	// inventory, so it was not deleted.
+	SkipReasonOwnershipTransferred // OwnershipTransferred
+	// SkipReasonPruneDelayed means the object was marked for deletion, but
+	// the prune delay has not elapsed yet, so it was not pruned.
+	SkipReasonPruneDelayed // PruneDelayed
+	// SkipReasonAlreadyExists means the object already exists, and its
+	// create-only apply strategy does not update existing objects.
+	SkipReasonAlreadyExists // AlreadyExists
+)
+
+// SkipReasoner is implemented by the errors of the filters that skip
+// objects, to provide the SkipReason of the skipped events.
+type SkipReasoner interface {
+	SkipReason() SkipReason
+}
+
+// SkipReasonOf returns the SkipReason of the first error in the chain that
+// implements SkipReasoner, or SkipReasonUnspecified if none does.
+func SkipReasonOf(err error) SkipReason {
+	var reasoner SkipReasoner
+	if errors.As(err, &reasoner) {
+		return reasoner.SkipReason()
+	}
+	return SkipReasonUnspecified
+}
+
 type ValidationEvent struct {
 	Identifiers object.ObjMetadataSet
 	Error       error
@@ -323,3 +491,67 @@ func (ve ValidationEvent) String() string {
 	return fmt.Sprintf("ValidationEvent{ Identifiers: %+v }",
 		ve.Identifiers)
 }
+
+//go:generate stringer -type=RollbackEventStatus -linecomment
+type RollbackEventStatus int
+
+const (
+	RollbackRestored RollbackEventStatus = iota // Restored
+	RollbackDeleted                             // Deleted
+	RollbackFailed                              // Failed
+)
+
+// RollbackEvent reports the rollback of an object to its state before a
+// failed apply: objects that existed are restored, and objects that were
+// created are deleted.
+type RollbackEvent struct {
+	Identifier object.ObjMetadata
+	Status     RollbackEventStatus
+	Error      error
+}
+
+// String returns a string suitable for logging
+func (re RollbackEvent) String() string {
+	if re.Error != nil {
+		return fmt.Sprintf("RollbackEvent{ Status: %q, Identifier: %q, Error: %q }",
+			re.Status, re.Identifier, re.Error)
+	}
+	return fmt.Sprintf("RollbackEvent{ Status: %q, Identifier: %q }",
+		re.Status, re.Identifier)
+}
+
+// AdoptEvent reports the adoption of an existing object by the inventory:
+// the object was applied successfully, and is now annotated with the
+// inventory ID and recorded in the inventory. PreviousOwner is the ID of the
+// inventory that owned the object before, or empty if it was not owned by
+// any inventory.
+type AdoptEvent struct {
+	GroupName     string
+	Identifier    object.ObjMetadata
+	PreviousOwner string
+}
+
+// String returns a string suitable for logging
+func (ae AdoptEvent) String() string {
+	return fmt.Sprintf("AdoptEvent{ GroupName: %q, Identifier: %q, PreviousOwner: %q }",
+		ae.GroupName, ae.Identifier, ae.PreviousOwner)
+}
+
+// StuckDeletionEvent reports an object that is still terminating after the
+// stuck deletion threshold, with the finalizers that block its deletion.
+type StuckDeletionEvent struct {
+	GroupName  string
+	Identifier object.ObjMetadata
+	// Finalizers are the remaining finalizers of the object.
+	Finalizers []string
+	// FinalizersRemoved is true if the finalizers were forcibly removed.
+	FinalizersRemoved bool
+	// Error is the error of the forced removal of the finalizers, if any.
+	Error error
+}
+
+// String returns a string suitable for logging
+func (se StuckDeletionEvent) String() string {
+	return fmt.Sprintf("StuckDeletionEvent{ GroupName: %q, Identifier: %q, Finalizers: %q, FinalizersRemoved: %t, Error: %q }",
+		se.GroupName, se.Identifier, se.Finalizers, se.FinalizersRemoved, se.Error)
+}
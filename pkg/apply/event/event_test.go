@@ -0,0 +1,52 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package event
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type notAppliedError struct{}
+
+func (e *notAppliedError) Error() string {
+	return "not applied"
+}
+
+func (e *notAppliedError) SkipReason() SkipReason {
+	return SkipReasonNotApplied
+}
+
+func TestSkipReasonOf(t *testing.T) {
+	testCases := map[string]struct {
+		err      error
+		expected SkipReason
+	}{
+		"nil error": {
+			err:      nil,
+			expected: SkipReasonUnspecified,
+		},
+		"error without reason": {
+			err:      errors.New("custom filter"),
+			expected: SkipReasonUnspecified,
+		},
+		"error with reason": {
+			err:      &notAppliedError{},
+			expected: SkipReasonNotApplied,
+		},
+		"wrapped error with reason": {
+			err:      fmt.Errorf("skipped: %w", &notAppliedError{}),
+			expected: SkipReasonNotApplied,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			assert.Equal(t, tc.expected, SkipReasonOf(tc.err))
+		})
+	}
+}
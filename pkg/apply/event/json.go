@@ -0,0 +1,81 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package event
+
+import "sigs.k8s.io/cli-utils/pkg/jsonenum"
+
+// The enums are encoded in JSON by name, so that consumers of the JSON do
+// not depend on the order of the constants.
+
+func (x Type) MarshalJSON() ([]byte, error) {
+	return jsonenum.Marshal(x)
+}
+
+func (x *Type) UnmarshalJSON(data []byte) error {
+	return jsonenum.Unmarshal(data, x)
+}
+
+func (x ResourceAction) MarshalJSON() ([]byte, error) {
+	return jsonenum.Marshal(x)
+}
+
+func (x *ResourceAction) UnmarshalJSON(data []byte) error {
+	return jsonenum.Unmarshal(data, x)
+}
+
+func (x WaitEventStatus) MarshalJSON() ([]byte, error) {
+	return jsonenum.Marshal(x)
+}
+
+func (x *WaitEventStatus) UnmarshalJSON(data []byte) error {
+	return jsonenum.Unmarshal(data, x)
+}
+
+func (x ActionGroupEventStatus) MarshalJSON() ([]byte, error) {
+	return jsonenum.Marshal(x)
+}
+
+func (x *ActionGroupEventStatus) UnmarshalJSON(data []byte) error {
+	return jsonenum.Unmarshal(data, x)
+}
+
+func (x ApplyEventStatus) MarshalJSON() ([]byte, error) {
+	return jsonenum.Marshal(x)
+}
+
+func (x *ApplyEventStatus) UnmarshalJSON(data []byte) error {
+	return jsonenum.Unmarshal(data, x)
+}
+
+func (x PruneEventStatus) MarshalJSON() ([]byte, error) {
+	return jsonenum.Marshal(x)
+}
+
+func (x *PruneEventStatus) UnmarshalJSON(data []byte) error {
+	return jsonenum.Unmarshal(data, x)
+}
+
+func (x DeleteEventStatus) MarshalJSON() ([]byte, error) {
+	return jsonenum.Marshal(x)
+}
+
+func (x *DeleteEventStatus) UnmarshalJSON(data []byte) error {
+	return jsonenum.Unmarshal(data, x)
+}
+
+func (x RollbackEventStatus) MarshalJSON() ([]byte, error) {
+	return jsonenum.Marshal(x)
+}
+
+func (x *RollbackEventStatus) UnmarshalJSON(data []byte) error {
+	return jsonenum.Unmarshal(data, x)
+}
+
+func (x SkipReason) MarshalJSON() ([]byte, error) {
+	return jsonenum.Marshal(x)
+}
+
+func (x *SkipReason) UnmarshalJSON(data []byte) error {
+	return jsonenum.Unmarshal(data, x)
+}
@@ -0,0 +1,85 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package event
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+func TestEventJSON(t *testing.T) {
+	e := Event{
+		Type: WaitType,
+		WaitEvent: WaitEvent{
+			GroupName: "wait-0",
+			Identifier: object.ObjMetadata{
+				Name: "foo",
+			},
+			Status: ReconcileTimeout,
+		},
+		ActionGroupEvent: ActionGroupEvent{
+			Action: InventoryAction,
+			Status: Finished,
+		},
+	}
+	data, err := json.Marshal(e)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"Type":"WaitType"`)
+	assert.Contains(t, string(data), `"Status":"Timeout"`)
+	assert.Contains(t, string(data), `"Action":"Inventory"`)
+	assert.Contains(t, string(data), `"Status":"Finished"`)
+
+	var decoded Event
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, e, decoded)
+}
+
+func TestEventStatusJSON(t *testing.T) {
+	testCases := map[string]struct {
+		value   interface{}
+		pointer interface{}
+		json    string
+	}{
+		"apply": {
+			value:   ApplySkipped,
+			pointer: new(ApplyEventStatus),
+			json:    `"Skipped"`,
+		},
+		"prune": {
+			value:   PruneFailed,
+			pointer: new(PruneEventStatus),
+			json:    `"Failed"`,
+		},
+		"delete": {
+			value:   DeleteSuccessful,
+			pointer: new(DeleteEventStatus),
+			json:    `"Successful"`,
+		},
+		"rollback": {
+			value:   RollbackDeleted,
+			pointer: new(RollbackEventStatus),
+			json:    `"Deleted"`,
+		},
+		"skip reason": {
+			value:   SkipReasonNamespaceInUse,
+			pointer: new(SkipReason),
+			json:    `"NamespaceInUse"`,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			data, err := json.Marshal(tc.value)
+			require.NoError(t, err)
+			assert.Equal(t, tc.json, string(data))
+			require.NoError(t, json.Unmarshal(data, tc.pointer))
+			assert.Equal(t, tc.value, reflect.ValueOf(tc.pointer).Elem().Interface())
+		})
+	}
+}
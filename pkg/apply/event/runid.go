@@ -0,0 +1,23 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package event
+
+// WithRunID returns a channel that receives the events sent to the passed
+// channel, with their RunID set to the passed run ID. The returned channel is
+// closed after the passed channel is closed. If the run ID is empty, the
+// passed channel is returned as is.
+func WithRunID(in <-chan Event, runID string) <-chan Event {
+	if runID == "" {
+		return in
+	}
+	out := make(chan Event)
+	go func() {
+		defer close(out)
+		for e := range in {
+			e.RunID = runID
+			out <- e
+		}
+	}()
+	return out
+}
@@ -0,0 +1,47 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package event
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithRunID(t *testing.T) {
+	testCases := map[string]struct {
+		runID    string
+		events   []Event
+		expected []Event
+	}{
+		"sets the run ID": {
+			runID:  "run-1",
+			events: []Event{{Type: InitType}, {Type: ApplyType}},
+			expected: []Event{
+				{Type: InitType, RunID: "run-1"},
+				{Type: ApplyType, RunID: "run-1"},
+			},
+		},
+		"empty run ID": {
+			events:   []Event{{Type: InitType}, {Type: ApplyType, RunID: "other"}},
+			expected: []Event{{Type: InitType}, {Type: ApplyType, RunID: "other"}},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			in := make(chan Event, len(tc.events))
+			for _, e := range tc.events {
+				in <- e
+			}
+			close(in)
+
+			var received []Event
+			for e := range WithRunID(in, tc.runID) {
+				received = append(received, e)
+			}
+			assert.Equal(t, tc.expected, received)
+		})
+	}
+}
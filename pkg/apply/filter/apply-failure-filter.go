@@ -0,0 +1,60 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package filter
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
+	"sigs.k8s.io/cli-utils/pkg/apply/taskrunner"
+)
+
+// ApplyFailureFilter implements ValidationFilter interface to prevent
+// pruning once any object failed to apply, so that the objects are still
+// there if the run is rolled back.
+type ApplyFailureFilter struct {
+	TaskContext *taskrunner.TaskContext
+}
+
+const ApplyFailureFilterName = "ApplyFailureFilter"
+
+// Name returns the preferred name for the filter. Usually
+// used for logging.
+func (aff ApplyFailureFilter) Name() string {
+	return ApplyFailureFilterName
+}
+
+// Filter returns an ApplyFailurePreventedDeletionError if any object failed
+// to apply.
+func (aff ApplyFailureFilter) Filter(_ *unstructured.Unstructured) error {
+	if failures := len(aff.TaskContext.ApplyErrors()); failures > 0 {
+		return &ApplyFailurePreventedDeletionError{Failures: failures}
+	}
+	return nil
+}
+
+type ApplyFailurePreventedDeletionError struct {
+	Failures int
+}
+
+func (e *ApplyFailurePreventedDeletionError) Error() string {
+	return fmt.Sprintf("deletion prevented by %d apply failure(s)", e.Failures)
+}
+
+func (e *ApplyFailurePreventedDeletionError) Is(err error) bool {
+	if err == nil {
+		return false
+	}
+	tErr, ok := err.(*ApplyFailurePreventedDeletionError)
+	if !ok {
+		return false
+	}
+	return e.Failures == tErr.Failures
+}
+
+// SkipReason returns the reason code of the skipped events.
+func (e *ApplyFailurePreventedDeletionError) SkipReason() event.SkipReason {
+	return event.SkipReasonApplyFailurePreventedDeletion
+}
@@ -0,0 +1,45 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package filter
+
+import (
+	"fmt"
+	"testing"
+
+	"sigs.k8s.io/cli-utils/pkg/apply/cache"
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
+	"sigs.k8s.io/cli-utils/pkg/apply/taskrunner"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/cli-utils/pkg/testutil"
+)
+
+func TestApplyFailureFilter(t *testing.T) {
+	tests := map[string]struct {
+		failedIDs     object.ObjMetadataSet
+		expectedError error
+	}{
+		"No apply failure": {},
+		"Apply failures prevent deletion": {
+			failedIDs: object.ObjMetadataSet{
+				testutil.ToIdentifier(t, "apiVersion: v1\nkind: Pod\nmetadata:\n  name: a\n  namespace: ns\n"),
+				testutil.ToIdentifier(t, "apiVersion: v1\nkind: Pod\nmetadata:\n  name: b\n  namespace: ns\n"),
+			},
+			expectedError: &ApplyFailurePreventedDeletionError{
+				Failures: 2,
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			taskContext := taskrunner.NewTaskContext(make(chan event.Event), cache.NewResourceCacheMap())
+			for _, id := range tc.failedIDs {
+				taskContext.AddApplyError(id, fmt.Errorf("apply failed"))
+			}
+			filter := ApplyFailureFilter{TaskContext: taskContext}
+			err := filter.Filter(defaultObj.DeepCopy())
+			testutil.AssertEqual(t, tc.expectedError, err)
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
 )
 
 // CurrentUIDFilter implements ValidationFilter interface to determine
@@ -51,3 +52,8 @@ func (e *ApplyPreventedDeletionError) Is(err error) bool {
 	}
 	return e.UID == tErr.UID
 }
+
+// SkipReason returns the reason code of the skipped events.
+func (e *ApplyPreventedDeletionError) SkipReason() event.SkipReason {
+	return event.SkipReasonApplyPreventedDeletion
+}
@@ -9,6 +9,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/cli-utils/pkg/apis/actuation"
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
 	"sigs.k8s.io/cli-utils/pkg/apply/taskrunner"
 	"sigs.k8s.io/cli-utils/pkg/common"
 	"sigs.k8s.io/cli-utils/pkg/object"
@@ -229,6 +230,11 @@ func (e *DependencyPreventedActuationError) Is(err error) bool {
 		e.RelationReconcileStatus == tErr.RelationReconcileStatus
 }
 
+// SkipReason returns the reason code of the skipped events.
+func (e *DependencyPreventedActuationError) SkipReason() event.SkipReason {
+	return event.SkipReasonDependencyPreventedActuation
+}
+
 type DependencyActuationMismatchError struct {
 	Object       object.ObjMetadata
 	Strategy     actuation.ActuationStrategy
@@ -259,3 +265,8 @@ func (e *DependencyActuationMismatchError) Is(err error) bool {
 		e.Relation == tErr.Relation &&
 		e.RelationStrategy == tErr.RelationStrategy
 }
+
+// SkipReason returns the reason code of the skipped events.
+func (e *DependencyActuationMismatchError) SkipReason() event.SkipReason {
+	return event.SkipReasonDependencyActuationMismatch
+}
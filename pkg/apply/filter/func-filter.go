@@ -0,0 +1,38 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package filter
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// FuncFilter is a ValidationFilter that calls a function, to implement
+// custom filters without declaring a type, like skipping the objects of
+// protected namespaces:
+//
+//	filter.FuncFilter{
+//		FilterName: "ProtectedNamespaceFilter",
+//		Func: func(obj *unstructured.Unstructured) error {
+//			if obj.GetNamespace() == "kube-system" {
+//				return errors.New("namespace is protected")
+//			}
+//			return nil
+//		},
+//	}
+//
+// The error returned by the function is the reason the object is skipped.
+type FuncFilter struct {
+	FilterName string
+	Func       func(obj *unstructured.Unstructured) error
+}
+
+// Name returns a filter identifier for logging.
+func (ff FuncFilter) Name() string {
+	return ff.FilterName
+}
+
+// Filter returns the error of the function, if the object should be skipped.
+func (ff FuncFilter) Filter(obj *unstructured.Unstructured) error {
+	return ff.Func(obj)
+}
@@ -24,6 +24,10 @@ type InventoryPolicyApplyFilter struct {
 	Mapper    meta.RESTMapper
 	Inv       inventory.Info
 	InvPolicy inventory.Policy
+	// OnAdopt, if set, is called for the objects that are allowed to be
+	// applied, but exist and are not owned by the inventory yet, with the
+	// ID of their previous owning inventory, or empty if none.
+	OnAdopt func(id object.ObjMetadata, previousOwner string)
 }
 
 // Name returns a filter identifier for logging.
@@ -35,11 +39,12 @@ func (ipaf InventoryPolicyApplyFilter) Name() string {
 // apply should be skipped.
 func (ipaf InventoryPolicyApplyFilter) Filter(obj *unstructured.Unstructured) error {
 	// optimization to avoid unnecessary API calls
-	if ipaf.InvPolicy == inventory.PolicyAdoptAll {
+	if ipaf.InvPolicy == inventory.PolicyAdoptAll && ipaf.OnAdopt == nil {
 		return nil
 	}
 	// Object must be retrieved from the cluster to get the inventory id.
-	clusterObj, err := ipaf.getObject(object.UnstructuredToObjMetadata(obj))
+	id := object.UnstructuredToObjMetadata(obj)
+	clusterObj, err := ipaf.getObject(id)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			// This simply means the object hasn't been created yet.
@@ -51,6 +56,9 @@ func (ipaf InventoryPolicyApplyFilter) Filter(obj *unstructured.Unstructured) er
 	if err != nil {
 		return err
 	}
+	if ipaf.OnAdopt != nil && inventory.IDMatch(ipaf.Inv, clusterObj) != inventory.Match {
+		ipaf.OnAdopt(id, clusterObj.GetAnnotations()[inventory.OwningInventoryKey])
+	}
 	return nil
 }
 
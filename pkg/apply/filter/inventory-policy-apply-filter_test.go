@@ -6,6 +6,7 @@ package filter
 import (
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"k8s.io/apimachinery/pkg/api/meta/testrestmapper"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	dynamicfake "k8s.io/client-go/dynamic/fake"
@@ -13,6 +14,7 @@ import (
 	"sigs.k8s.io/cli-utils/pkg/apis/actuation"
 	"sigs.k8s.io/cli-utils/pkg/common"
 	"sigs.k8s.io/cli-utils/pkg/inventory"
+	"sigs.k8s.io/cli-utils/pkg/object"
 	"sigs.k8s.io/cli-utils/pkg/testutil"
 )
 
@@ -110,3 +112,64 @@ func TestInventoryPolicyApplyFilter(t *testing.T) {
 		})
 	}
 }
+
+func TestInventoryPolicyApplyFilter_OnAdopt(t *testing.T) {
+	tests := map[string]struct {
+		objAnnotations        map[string]string
+		policy                inventory.Policy
+		expectedAdopted       bool
+		expectedPreviousOwner string
+	}{
+		"object owned by the inventory, not adopted": {
+			objAnnotations: map[string]string{
+				inventory.OwningInventoryKey: "foo",
+			},
+			policy: inventory.PolicyAdoptIfNoInventory,
+		},
+		"object without owner and adopt if no inventory, adopted": {
+			policy:          inventory.PolicyAdoptIfNoInventory,
+			expectedAdopted: true,
+		},
+		"object owned by another inventory and adopt all, adopted": {
+			objAnnotations: map[string]string{
+				inventory.OwningInventoryKey: "bar",
+			},
+			policy:                inventory.PolicyAdoptAll,
+			expectedAdopted:       true,
+			expectedPreviousOwner: "bar",
+		},
+		"object owned by another inventory and adopt if no inventory, not adopted": {
+			objAnnotations: map[string]string{
+				inventory.OwningInventoryKey: "bar",
+			},
+			policy: inventory.PolicyAdoptIfNoInventory,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			obj := defaultObj.DeepCopy()
+			obj.SetAnnotations(tc.objAnnotations)
+			invObj := invObjTemplate.DeepCopy()
+			invObj.SetLabels(map[string]string{
+				common.InventoryLabel: "foo",
+			})
+			adopted := false
+			previousOwner := ""
+			filter := InventoryPolicyApplyFilter{
+				Client: dynamicfake.NewSimpleDynamicClient(scheme.Scheme, obj),
+				Mapper: testrestmapper.TestOnlyStaticRESTMapper(scheme.Scheme,
+					scheme.Scheme.PrioritizedVersionsAllGroups()...),
+				Inv:       inventory.WrapInventoryInfoObj(invObj),
+				InvPolicy: tc.policy,
+				OnAdopt: func(_ object.ObjMetadata, owner string) {
+					adopted = true
+					previousOwner = owner
+				},
+			}
+			_ = filter.Filter(obj)
+			assert.Equal(t, tc.expectedAdopted, adopted)
+			assert.Equal(t, tc.expectedPreviousOwner, previousOwner)
+		})
+	}
+}
@@ -11,6 +11,7 @@ import (
 // InventoryPolicyPruneFilter implements ValidationFilter interface to determine
 // if an object should be pruned (deleted) because of the InventoryPolicy
 // and if the objects owning inventory identifier matchs the inventory id.
+// Objects adopted by another inventory are never pruned, whatever the policy.
 type InventoryPolicyPruneFilter struct {
 	Inv       inventory.Info
 	InvPolicy inventory.Policy
@@ -21,9 +22,13 @@ func (ipf InventoryPolicyPruneFilter) Name() string {
 	return "InventoryPolicyFilter"
 }
 
-// Filter returns an inventory.PolicyPreventedActuationError if the object
-// prune/delete should be skipped.
+// Filter returns an inventory.OwnershipTransferredError if the object is owned
+// by another inventory, or an inventory.PolicyPreventedActuationError if the
+// object prune/delete should be skipped.
 func (ipf InventoryPolicyPruneFilter) Filter(obj *unstructured.Unstructured) error {
+	if owner := obj.GetAnnotations()[inventory.OwningInventoryKey]; owner != "" && owner != ipf.Inv.ID() {
+		return &inventory.OwnershipTransferredError{Owner: owner}
+	}
 	_, err := inventory.CanPrune(ipf.Inv, obj, ipf.InvPolicy)
 	if err != nil {
 		return err
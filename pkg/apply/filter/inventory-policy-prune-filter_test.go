@@ -45,26 +45,25 @@ func TestInventoryPolicyPruneFilter(t *testing.T) {
 			inventoryID:    "foo",
 			objInventoryID: "bar",
 			policy:         inventory.PolicyMustMatch,
-			expectedError: &inventory.PolicyPreventedActuationError{
-				Strategy: actuation.ActuationStrategyDelete,
-				Policy:   inventory.PolicyMustMatch,
-				Status:   inventory.NoMatch,
+			expectedError: &inventory.OwnershipTransferredError{
+				Owner: "bar",
 			},
 		},
 		"inventory and object ids do no match and adopt if no inventory, filtered": {
 			inventoryID:    "foo",
 			objInventoryID: "bar",
 			policy:         inventory.PolicyAdoptIfNoInventory,
-			expectedError: &inventory.PolicyPreventedActuationError{
-				Strategy: actuation.ActuationStrategyDelete,
-				Policy:   inventory.PolicyAdoptIfNoInventory,
-				Status:   inventory.NoMatch,
+			expectedError: &inventory.OwnershipTransferredError{
+				Owner: "bar",
 			},
 		},
-		"inventory and object ids do no match and adopt all, not filtered": {
+		"inventory and object ids do no match and adopt all, filtered": {
 			inventoryID:    "foo",
 			objInventoryID: "bar",
 			policy:         inventory.PolicyAdoptAll,
+			expectedError: &inventory.OwnershipTransferredError{
+				Owner: "bar",
+			},
 		},
 		"object id empty and adopt all, not filtered": {
 			inventoryID:    "foo",
@@ -0,0 +1,75 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package filter
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+// InventoryUIDFilter implements ValidationFilter interface to determine
+// if an object should not be pruned (deleted) because its UID does not match
+// the UID recorded in the inventory. This means the object was deleted and
+// re-created by another client since it was last actuated, and merely shares
+// the same name.
+type InventoryUIDFilter struct {
+	// ExpectedUIDs are the last known UIDs recorded in the inventory.
+	// Objects without a recorded UID are not filtered.
+	ExpectedUIDs map[object.ObjMetadata]types.UID
+}
+
+// Name returns a filter identifier for logging.
+func (iuf InventoryUIDFilter) Name() string {
+	return "InventoryUIDFilter"
+}
+
+// Filter returns a UIDMismatchError if the object prune/delete should be
+// skipped.
+func (iuf InventoryUIDFilter) Filter(obj *unstructured.Unstructured) error {
+	expected, found := iuf.ExpectedUIDs[object.UnstructuredToObjMetadata(obj)]
+	if !found || expected == "" {
+		return nil
+	}
+	if actual := obj.GetUID(); actual != expected {
+		return &UIDMismatchError{
+			ExpectedUID: expected,
+			ActualUID:   actual,
+		}
+	}
+	return nil
+}
+
+// UIDMismatchError is the reason the prune or deletion of an object is
+// skipped, if the UID of the live object does not match the UID recorded in
+// the inventory.
+type UIDMismatchError struct {
+	ExpectedUID types.UID
+	ActualUID   types.UID
+}
+
+func (e *UIDMismatchError) Error() string {
+	return fmt.Sprintf("object UID does not match inventory (expected: %q, actual: %q): object may have been re-created by another client",
+		e.ExpectedUID, e.ActualUID)
+}
+
+func (e *UIDMismatchError) Is(err error) bool {
+	if err == nil {
+		return false
+	}
+	tErr, ok := err.(*UIDMismatchError)
+	if !ok {
+		return false
+	}
+	return e.ExpectedUID == tErr.ExpectedUID &&
+		e.ActualUID == tErr.ActualUID
+}
+
+// SkipReason returns the reason code of the skipped events.
+func (e *UIDMismatchError) SkipReason() event.SkipReason {
+	return event.SkipReasonUIDMismatch
+}
@@ -0,0 +1,51 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package filter
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/cli-utils/pkg/testutil"
+)
+
+func TestInventoryUIDFilter(t *testing.T) {
+	id := object.UnstructuredToObjMetadata(defaultObj)
+	tests := map[string]struct {
+		expectedUIDs  map[object.ObjMetadata]types.UID
+		objUID        string
+		expectedError error
+	}{
+		"No recorded UIDs, object is not filtered": {
+			expectedUIDs: map[object.ObjMetadata]types.UID{},
+			objUID:       "foo",
+		},
+		"Matching UID, object is not filtered": {
+			expectedUIDs: map[object.ObjMetadata]types.UID{id: "foo"},
+			objUID:       "foo",
+		},
+		"Empty recorded UID, object is not filtered": {
+			expectedUIDs: map[object.ObjMetadata]types.UID{id: ""},
+			objUID:       "foo",
+		},
+		"Different UID, object is filtered": {
+			expectedUIDs:  map[object.ObjMetadata]types.UID{id: "foo"},
+			objUID:        "bar",
+			expectedError: &UIDMismatchError{ExpectedUID: "foo", ActualUID: "bar"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			filter := InventoryUIDFilter{
+				ExpectedUIDs: tc.expectedUIDs,
+			}
+			obj := defaultObj.DeepCopy()
+			obj.SetUID(types.UID(tc.objUID))
+			err := filter.Filter(obj)
+			testutil.AssertEqual(t, tc.expectedError, err)
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
 	"sigs.k8s.io/cli-utils/pkg/object"
 )
 
@@ -59,3 +60,8 @@ func (e *NamespaceInUseError) Is(err error) bool {
 	}
 	return e.Namespace == tErr.Namespace
 }
+
+// SkipReason returns the reason code of the skipped events.
+func (e *NamespaceInUseError) SkipReason() event.SkipReason {
+	return event.SkipReasonNamespaceInUse
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
 	"sigs.k8s.io/cli-utils/pkg/common"
 )
 
@@ -57,3 +58,8 @@ func (e *AnnotationPreventedDeletionError) Is(err error) bool {
 	return e.Annotation == tErr.Annotation &&
 		e.Value == tErr.Value
 }
+
+// SkipReason returns the reason code of the skipped events.
+func (e *AnnotationPreventedDeletionError) SkipReason() event.SkipReason {
+	return event.SkipReasonLocalPolicyPreventedDeletion
+}
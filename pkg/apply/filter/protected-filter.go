@@ -0,0 +1,66 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package filter
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
+	"sigs.k8s.io/cli-utils/pkg/common"
+)
+
+// ProtectedFilter implements ValidationFilter interface to determine if an
+// object should not be pruned (deleted) because it is protected by a label
+// or annotation set to "true". Unlike the "on-remove: keep" annotation, the
+// protected object is kept in the inventory.
+type ProtectedFilter struct {
+	// Key is the label or annotation key. Defaults to common.ProtectedKey.
+	Key string
+}
+
+const ProtectedFilterName = "ProtectedFilter"
+
+// Name returns the preferred name for the filter. Usually
+// used for logging.
+func (pf ProtectedFilter) Name() string {
+	return ProtectedFilterName
+}
+
+// Filter returns a ProtectedError if the object has the protection label or
+// annotation.
+func (pf ProtectedFilter) Filter(obj *unstructured.Unstructured) error {
+	key := pf.Key
+	if key == "" {
+		key = common.ProtectedKey
+	}
+	if obj.GetLabels()[key] == "true" || obj.GetAnnotations()[key] == "true" {
+		return &ProtectedError{Key: key}
+	}
+	return nil
+}
+
+type ProtectedError struct {
+	Key string
+}
+
+func (e *ProtectedError) Error() string {
+	return fmt.Sprintf("object is protected (%q: %q)", e.Key, "true")
+}
+
+func (e *ProtectedError) Is(err error) bool {
+	if err == nil {
+		return false
+	}
+	tErr, ok := err.(*ProtectedError)
+	if !ok {
+		return false
+	}
+	return e.Key == tErr.Key
+}
+
+// SkipReason returns the reason code of the skipped events.
+func (e *ProtectedError) SkipReason() event.SkipReason {
+	return event.SkipReasonProtected
+}
@@ -0,0 +1,69 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package filter
+
+import (
+	"testing"
+
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
+	"sigs.k8s.io/cli-utils/pkg/common"
+	"sigs.k8s.io/cli-utils/pkg/testutil"
+)
+
+func TestProtectedFilter(t *testing.T) {
+	tests := map[string]struct {
+		key           string
+		labels        map[string]string
+		annotations   map[string]string
+		expectedError error
+	}{
+		"not protected": {},
+		"protected by label": {
+			labels: map[string]string{
+				common.ProtectedKey: "true",
+			},
+			expectedError: &ProtectedError{Key: common.ProtectedKey},
+		},
+		"protected by annotation": {
+			annotations: map[string]string{
+				common.ProtectedKey: "true",
+			},
+			expectedError: &ProtectedError{Key: common.ProtectedKey},
+		},
+		"not protected if the value is not true": {
+			labels: map[string]string{
+				common.ProtectedKey: "false",
+			},
+			annotations: map[string]string{
+				common.ProtectedKey: "yes",
+			},
+		},
+		"protected by a custom key": {
+			key: "example.com/keep",
+			labels: map[string]string{
+				"example.com/keep": "true",
+			},
+			expectedError: &ProtectedError{Key: "example.com/keep"},
+		},
+		"not protected by the default key with a custom key": {
+			key: "example.com/keep",
+			annotations: map[string]string{
+				common.ProtectedKey: "true",
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			filter := ProtectedFilter{Key: tc.key}
+			obj := defaultObj.DeepCopy()
+			obj.SetLabels(tc.labels)
+			obj.SetAnnotations(tc.annotations)
+			err := filter.Filter(obj)
+			testutil.AssertEqual(t, tc.expectedError, err)
+			if tc.expectedError != nil {
+				testutil.AssertEqual(t, event.SkipReasonProtected, event.SkipReasonOf(err))
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package filter
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
+	"sigs.k8s.io/cli-utils/pkg/common"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+// PruneDelayFilter implements ValidationFilter interface to delay the prune
+// of the objects removed from the package. The first run that would prune an
+// object marks it for deletion instead, and the object is only pruned by a
+// later run, after the delay, if it is still absent from the package.
+//
+// The mark is the prune-marked-at annotation of the object. It is only valid
+// if the prune of the object was skipped by the previous run, as recorded in
+// the inventory, so that the mark of an object that was applied again in the
+// meantime is ignored.
+type PruneDelayFilter struct {
+	// Delay is the minimum duration between the mark and the prune.
+	Delay time.Duration
+	// Marked are the objects whose prune was skipped by the previous run.
+	Marked object.ObjMetadataSet
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+const PruneDelayFilterName = "PruneDelayFilter"
+
+// Name returns the preferred name for the filter. Usually
+// used for logging.
+func (pdf PruneDelayFilter) Name() string {
+	return PruneDelayFilterName
+}
+
+// Filter returns a PruneDelayedError if the object is not marked for
+// deletion, or if the delay since the mark has not elapsed yet.
+func (pdf PruneDelayFilter) Filter(obj *unstructured.Unstructured) error {
+	now := time.Now
+	if pdf.Now != nil {
+		now = pdf.Now
+	}
+	mark := func() error {
+		markedAt := now().UTC().Truncate(time.Second)
+		return &PruneDelayedError{
+			Mark:        true,
+			MarkedAt:    markedAt,
+			DeleteAfter: markedAt.Add(pdf.Delay),
+		}
+	}
+	value, found := obj.GetAnnotations()[common.PruneMarkedAtAnnotation]
+	if !found || !pdf.Marked.Contains(object.UnstructuredToObjMetadata(obj)) {
+		return mark()
+	}
+	markedAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		// Mark the object again, rather than deleting it too early.
+		return mark()
+	}
+	deleteAfter := markedAt.Add(pdf.Delay)
+	if now().Before(deleteAfter) {
+		return &PruneDelayedError{MarkedAt: markedAt, DeleteAfter: deleteAfter}
+	}
+	return nil
+}
+
+type PruneDelayedError struct {
+	// Mark is true if the object must be marked for deletion, at MarkedAt.
+	Mark bool
+	// MarkedAt is when the object was marked for deletion.
+	MarkedAt time.Time
+	// DeleteAfter is when the object can be pruned.
+	DeleteAfter time.Time
+}
+
+func (e *PruneDelayedError) Error() string {
+	return fmt.Sprintf("object marked for deletion at %s, to prune after %s",
+		e.MarkedAt.UTC().Format(time.RFC3339), e.DeleteAfter.UTC().Format(time.RFC3339))
+}
+
+func (e *PruneDelayedError) Is(err error) bool {
+	if err == nil {
+		return false
+	}
+	tErr, ok := err.(*PruneDelayedError)
+	if !ok {
+		return false
+	}
+	return e.Mark == tErr.Mark &&
+		e.MarkedAt.Equal(tErr.MarkedAt) &&
+		e.DeleteAfter.Equal(tErr.DeleteAfter)
+}
+
+// SkipReason returns the reason code of the skipped events.
+func (e *PruneDelayedError) SkipReason() event.SkipReason {
+	return event.SkipReasonPruneDelayed
+}
@@ -0,0 +1,76 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package filter
+
+import (
+	"testing"
+	"time"
+
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
+	"sigs.k8s.io/cli-utils/pkg/common"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/cli-utils/pkg/testutil"
+)
+
+func TestPruneDelayFilter(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	delay := time.Hour
+	marked := object.ObjMetadataSet{object.UnstructuredToObjMetadata(defaultObj)}
+
+	tests := map[string]struct {
+		annotations   map[string]string
+		marked        object.ObjMetadataSet
+		expectedError error
+	}{
+		"not marked": {
+			marked:        marked,
+			expectedError: &PruneDelayedError{Mark: true, MarkedAt: now, DeleteAfter: now.Add(delay)},
+		},
+		"delay not elapsed": {
+			annotations: map[string]string{
+				common.PruneMarkedAtAnnotation: now.Add(-time.Minute).Format(time.RFC3339),
+			},
+			marked: marked,
+			expectedError: &PruneDelayedError{
+				MarkedAt:    now.Add(-time.Minute),
+				DeleteAfter: now.Add(delay - time.Minute),
+			},
+		},
+		"delay elapsed": {
+			annotations: map[string]string{
+				common.PruneMarkedAtAnnotation: now.Add(-delay).Format(time.RFC3339),
+			},
+			marked: marked,
+		},
+		"mark ignored if the previous run did not skip the prune": {
+			annotations: map[string]string{
+				common.PruneMarkedAtAnnotation: now.Add(-2 * delay).Format(time.RFC3339),
+			},
+			expectedError: &PruneDelayedError{Mark: true, MarkedAt: now, DeleteAfter: now.Add(delay)},
+		},
+		"invalid mark": {
+			annotations: map[string]string{
+				common.PruneMarkedAtAnnotation: "yesterday",
+			},
+			marked:        marked,
+			expectedError: &PruneDelayedError{Mark: true, MarkedAt: now, DeleteAfter: now.Add(delay)},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			filter := PruneDelayFilter{
+				Delay:  delay,
+				Marked: tc.marked,
+				Now:    func() time.Time { return now },
+			}
+			obj := defaultObj.DeepCopy()
+			obj.SetAnnotations(tc.annotations)
+			err := filter.Filter(obj)
+			testutil.AssertEqual(t, tc.expectedError, err)
+			if tc.expectedError != nil {
+				testutil.AssertEqual(t, event.SkipReasonPruneDelayed, event.SkipReasonOf(err))
+			}
+		})
+	}
+}
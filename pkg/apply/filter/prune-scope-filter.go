@@ -0,0 +1,84 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package filter
+
+import (
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+// PruneScopeFilter implements ValidationFilter interface to determine if an
+// object should not be pruned because it is outside the prune scope, e.g.
+// in a namespace that is not part of a partial apply.
+type PruneScopeFilter struct {
+	// Namespaces are the namespaces of the namespaced objects that can be
+	// pruned. All the namespaces if empty.
+	Namespaces []string
+	// GroupKinds are the GroupKinds of the objects that can be pruned. All
+	// the GroupKinds if empty.
+	GroupKinds []schema.GroupKind
+	// NamespacedOnly is true if cluster-scoped objects must not be pruned.
+	NamespacedOnly bool
+}
+
+const PruneScopeFilterName = "PruneScopeFilter"
+
+// Name returns the preferred name for the filter. Usually
+// used for logging.
+func (psf PruneScopeFilter) Name() string {
+	return PruneScopeFilterName
+}
+
+// Filter returns an OutOfScopeError if the object is outside the prune scope.
+// The live objects of the prune set only have an empty namespace if they are
+// cluster-scoped.
+func (psf PruneScopeFilter) Filter(obj *unstructured.Unstructured) error {
+	id := object.UnstructuredToObjMetadata(obj)
+	if len(psf.GroupKinds) > 0 && !slices.Contains(psf.GroupKinds, id.GroupKind) {
+		return &OutOfScopeError{
+			Reason: fmt.Sprintf("kind %s not in scope", id.GroupKind),
+		}
+	}
+	if id.Namespace == "" {
+		if psf.NamespacedOnly {
+			return &OutOfScopeError{Reason: "cluster-scoped object"}
+		}
+		return nil
+	}
+	if len(psf.Namespaces) > 0 && !slices.Contains(psf.Namespaces, id.Namespace) {
+		return &OutOfScopeError{
+			Reason: fmt.Sprintf("namespace %s not in scope", id.Namespace),
+		}
+	}
+	return nil
+}
+
+type OutOfScopeError struct {
+	Reason string
+}
+
+func (e *OutOfScopeError) Error() string {
+	return fmt.Sprintf("object outside the prune scope: %s", e.Reason)
+}
+
+func (e *OutOfScopeError) Is(err error) bool {
+	if err == nil {
+		return false
+	}
+	tErr, ok := err.(*OutOfScopeError)
+	if !ok {
+		return false
+	}
+	return e.Reason == tErr.Reason
+}
+
+// SkipReason returns the reason code of the skipped events.
+func (e *OutOfScopeError) SkipReason() event.SkipReason {
+	return event.SkipReasonOutOfScope
+}
@@ -0,0 +1,81 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package filter
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/cli-utils/pkg/testutil"
+)
+
+var clusterRole = &unstructured.Unstructured{
+	Object: map[string]interface{}{
+		"apiVersion": "rbac.authorization.k8s.io/v1",
+		"kind":       "ClusterRole",
+		"metadata": map[string]interface{}{
+			"name": "cluster-role",
+		},
+	},
+}
+
+func TestPruneScopeFilter(t *testing.T) {
+	podGK := schema.GroupKind{Kind: "Pod"}
+	tests := map[string]struct {
+		filter        PruneScopeFilter
+		obj           *unstructured.Unstructured
+		expectedError error
+	}{
+		"empty scope allows namespaced objects": {
+			obj: defaultObj,
+		},
+		"empty scope allows cluster-scoped objects": {
+			obj: clusterRole,
+		},
+		"namespace in scope": {
+			filter: PruneScopeFilter{Namespaces: []string{"test-namespace"}},
+			obj:    defaultObj,
+		},
+		"namespace not in scope": {
+			filter: PruneScopeFilter{Namespaces: []string{"other"}},
+			obj:    defaultObj,
+			expectedError: &OutOfScopeError{
+				Reason: "namespace test-namespace not in scope",
+			},
+		},
+		"namespaces do not restrict cluster-scoped objects": {
+			filter: PruneScopeFilter{Namespaces: []string{"other"}},
+			obj:    clusterRole,
+		},
+		"cluster-scoped object with namespaced only": {
+			filter: PruneScopeFilter{NamespacedOnly: true},
+			obj:    clusterRole,
+			expectedError: &OutOfScopeError{
+				Reason: "cluster-scoped object",
+			},
+		},
+		"namespaced object with namespaced only": {
+			filter: PruneScopeFilter{NamespacedOnly: true},
+			obj:    defaultObj,
+		},
+		"kind in scope": {
+			filter: PruneScopeFilter{GroupKinds: []schema.GroupKind{podGK}},
+			obj:    defaultObj,
+		},
+		"kind not in scope": {
+			filter: PruneScopeFilter{GroupKinds: []schema.GroupKind{podGK}},
+			obj:    clusterRole,
+			expectedError: &OutOfScopeError{
+				Reason: "kind ClusterRole.rbac.authorization.k8s.io not in scope",
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tc.filter.Filter(tc.obj)
+			testutil.AssertEqual(t, tc.expectedError, err)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package lifecycle models the lifecycle of the actuation of each object of
+// an apply or destroy run as a state machine, and tracks the state of the
+// objects of each run from the events of the run, so that UIs can show the
+// state of each object without deriving it from the events.
+package lifecycle
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/cli-utils/pkg/jsonenum"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+// State is the state of an object in the lifecycle of its actuation.
+//
+//go:generate stringer -type=State -linecomment
+type State int
+
+const (
+	// Pending objects are planned to be applied, pruned or deleted.
+	Pending State = iota // Pending
+	// Applying objects are being applied.
+	Applying // Applying
+	// Applied objects were applied, and have not been checked for
+	// reconciliation yet.
+	Applied // Applied
+	// Reconciling objects are waited on, until they are reconciled, or
+	// deleted for pruned and deleted objects.
+	Reconciling // Reconciling
+	// Current objects were applied and are reconciled.
+	Current // Current
+	// Pruning objects are being pruned.
+	Pruning // Pruning
+	// Pruned objects were pruned.
+	Pruned // Pruned
+	// Deleting objects are being deleted by a destroy.
+	Deleting // Deleting
+	// Deleted objects were deleted by a destroy.
+	Deleted // Deleted
+	// Skipped objects were not actuated.
+	Skipped // Skipped
+	// Failed objects failed to be actuated or to reconcile.
+	Failed // Failed
+	// TimedOut objects did not reconcile, or were not deleted, before the
+	// timeout.
+	TimedOut // TimedOut
+	// RolledBack objects were restored to their state before the run, or
+	// deleted if created by the run.
+	RolledBack // RolledBack
+)
+
+// transitions are the valid transitions from each state.
+var transitions = map[State][]State{
+	Pending:     {Applying, Pruning, Deleting, Skipped, Failed},
+	Applying:    {Applied, Skipped, Failed},
+	Applied:     {Reconciling, Current, Skipped, Failed, TimedOut, Pruning, RolledBack},
+	Reconciling: {Current, Failed, TimedOut, Pruning, RolledBack},
+	Current:     {Reconciling, Pruning, RolledBack},
+	Failed:      {Reconciling, Current, Pruning, RolledBack},
+	TimedOut:    {Pruning, RolledBack},
+	Pruning:     {Pruned, Skipped, Failed},
+	Pruned:      {Failed, TimedOut},
+	Deleting:    {Deleted, Skipped, Failed},
+	Deleted:     {Failed, TimedOut},
+}
+
+// CanTransition returns true if an object can go from a state to another.
+// Test resources are applied and then pruned, so applied objects can be
+// pruned. Pruned and deleted objects can fail or time out while waiting for
+// their deletion.
+func CanTransition(from, to State) bool {
+	for _, s := range transitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// InvalidTransitionError is returned for an event that would move an object
+// to a state that can not follow its current state.
+type InvalidTransitionError struct {
+	ID   object.ObjMetadata
+	From State
+	To   State
+}
+
+func (e *InvalidTransitionError) Error() string {
+	return fmt.Sprintf("invalid lifecycle transition of object %s: from %s to %s", e.ID, e.From, e.To)
+}
+
+func (x State) MarshalJSON() ([]byte, error) {
+	return jsonenum.Marshal(x)
+}
+
+func (x *State) UnmarshalJSON(data []byte) error {
+	return jsonenum.Unmarshal(data, x)
+}
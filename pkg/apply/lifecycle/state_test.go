@@ -0,0 +1,51 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package lifecycle
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanTransition(t *testing.T) {
+	testCases := map[string]struct {
+		from     State
+		to       State
+		expected bool
+	}{
+		"pending to applying":              {from: Pending, to: Applying, expected: true},
+		"applying to applied":              {from: Applying, to: Applied, expected: true},
+		"applied to reconciling":           {from: Applied, to: Reconciling, expected: true},
+		"reconciling to current":           {from: Reconciling, to: Current, expected: true},
+		"current back to reconciling":      {from: Current, to: Reconciling, expected: true},
+		"current test resource to pruning": {from: Current, to: Pruning, expected: true},
+		"pruned to timed out":              {from: Pruned, to: TimedOut, expected: true},
+		"pending to applied":               {from: Pending, to: Applied, expected: false},
+		"applying to pruning":              {from: Applying, to: Pruning, expected: false},
+		"pruned to current":                {from: Pruned, to: Current, expected: false},
+		"deleting to pruned":               {from: Deleting, to: Pruned, expected: false},
+		"skipped is final":                 {from: Skipped, to: Applying, expected: false},
+		"rolled back is final":             {from: RolledBack, to: Current, expected: false},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			assert.Equal(t, tc.expected, CanTransition(tc.from, tc.to))
+		})
+	}
+}
+
+func TestStateJSON(t *testing.T) {
+	data, err := json.Marshal(Transition{From: Applying, To: Applied})
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"from":"Applying","to":"Applied"`)
+
+	var transition Transition
+	require.NoError(t, json.Unmarshal(data, &transition))
+	assert.Equal(t, Applying, transition.From)
+	assert.Equal(t, Applied, transition.To)
+}
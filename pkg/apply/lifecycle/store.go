@@ -0,0 +1,310 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package lifecycle
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
+	"sigs.k8s.io/cli-utils/pkg/multierror"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+// Transition is a change of the state of an object.
+type Transition struct {
+	From State     `json:"from"`
+	To   State     `json:"to"`
+	Time time.Time `json:"time"`
+}
+
+// Store tracks the state of the objects of each run, from the events of the
+// runs, identified by their run ID. It is safe for concurrent use.
+type Store struct {
+	mu     sync.RWMutex
+	runs   map[string]*runState
+	runIDs []string
+}
+
+// runState is the state of the objects of a run.
+type runState struct {
+	// groups are the action groups of the run, by name.
+	groups  map[string]event.ActionGroup
+	objects map[object.ObjMetadata]*objectState
+	// ids are the objects of the run, in the order of the action groups.
+	ids object.ObjMetadataSet
+}
+
+type objectState struct {
+	state   State
+	history []Transition
+}
+
+// NewStore returns an empty Store.
+func NewStore() *Store {
+	return &Store{
+		runs: make(map[string]*runState),
+	}
+}
+
+// Forward observes the events, and forwards them to the returned channel,
+// which is closed after the event channel. Invalid transitions are logged.
+func (s *Store) Forward(eventChannel <-chan event.Event) <-chan event.Event {
+	observedChannel := make(chan event.Event)
+	go func() {
+		defer close(observedChannel)
+		for e := range eventChannel {
+			if err := s.Observe(e); err != nil {
+				klog.Warningf("failed to track the state of the objects (run: %s): %v", e.RunID, err)
+			}
+			observedChannel <- e
+		}
+	}()
+	return observedChannel
+}
+
+// Observe updates the states of the objects of the run of the event. The
+// InitEvent of a run adds its objects as Pending, replacing the states of a
+// previous run with the same ID. Returns an InvalidTransitionError, without
+// changing the state of the object, if the event does not follow the
+// lifecycle of the object.
+func (s *Store) Observe(e event.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e.Type == event.InitType {
+		s.init(e.RunID, e.InitEvent.ActionGroups)
+		return nil
+	}
+	r, found := s.runs[e.RunID]
+	if !found {
+		// Events of runs that were forgotten, or whose InitEvent was not
+		// observed, are ignored.
+		return nil
+	}
+	switch e.Type {
+	case event.ActionGroupType:
+		return r.startActionGroup(e.ActionGroupEvent)
+	case event.ApplyType:
+		switch e.ApplyEvent.Status {
+		case event.ApplySuccessful:
+			return r.transition(e.ApplyEvent.Identifier, Applied)
+		case event.ApplySkipped:
+			return r.transition(e.ApplyEvent.Identifier, Skipped)
+		case event.ApplyFailed:
+			return r.transition(e.ApplyEvent.Identifier, Failed)
+		}
+	case event.PruneType:
+		switch e.PruneEvent.Status {
+		case event.PruneSuccessful:
+			return r.transition(e.PruneEvent.Identifier, Pruned)
+		case event.PruneSkipped:
+			return r.transition(e.PruneEvent.Identifier, Skipped)
+		case event.PruneFailed:
+			return r.transition(e.PruneEvent.Identifier, Failed)
+		}
+	case event.DeleteType:
+		switch e.DeleteEvent.Status {
+		case event.DeleteSuccessful:
+			return r.transition(e.DeleteEvent.Identifier, Deleted)
+		case event.DeleteSkipped:
+			return r.transition(e.DeleteEvent.Identifier, Skipped)
+		case event.DeleteFailed:
+			return r.transition(e.DeleteEvent.Identifier, Failed)
+		}
+	case event.WaitType:
+		return r.reconcile(e.WaitEvent.Identifier, e.WaitEvent.Status)
+	case event.RollbackType:
+		switch e.RollbackEvent.Status {
+		case event.RollbackRestored, event.RollbackDeleted:
+			return r.transition(e.RollbackEvent.Identifier, RolledBack)
+		case event.RollbackFailed:
+			return r.transition(e.RollbackEvent.Identifier, Failed)
+		}
+	}
+	return nil
+}
+
+func (s *Store) init(runID string, groups event.ActionGroupList) {
+	if _, found := s.runs[runID]; !found {
+		s.runIDs = append(s.runIDs, runID)
+	}
+	r := &runState{
+		groups:  make(map[string]event.ActionGroup, len(groups)),
+		objects: make(map[object.ObjMetadata]*objectState),
+	}
+	for _, group := range groups {
+		r.groups[group.Name] = group
+		switch group.Action {
+		case event.ApplyAction, event.PruneAction, event.DeleteAction:
+			for _, id := range group.Identifiers {
+				if _, found := r.objects[id]; !found {
+					r.objects[id] = &objectState{state: Pending}
+					r.ids = append(r.ids, id)
+				}
+			}
+		}
+	}
+	s.runs[runID] = r
+}
+
+// startActionGroup moves the objects of an apply, prune or delete action
+// group to Applying, Pruning or Deleting.
+func (r *runState) startActionGroup(e event.ActionGroupEvent) error {
+	if e.Status != event.Started {
+		return nil
+	}
+	var to State
+	switch e.Action {
+	case event.ApplyAction:
+		to = Applying
+	case event.PruneAction:
+		to = Pruning
+	case event.DeleteAction:
+		to = Deleting
+	default:
+		return nil
+	}
+	var errs []error
+	for _, id := range r.groups[e.GroupName].Identifiers {
+		if err := r.transition(id, to); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return multierror.Wrap(errs...)
+}
+
+// reconcile updates the state of an object from a WaitEvent. Waiting for
+// the deletion of pruned and deleted objects only changes their state if
+// it fails or times out, and skipped waits keep the state of failed and
+// skipped objects.
+func (r *runState) reconcile(id object.ObjMetadata, status event.WaitEventStatus) error {
+	from := r.state(id).state
+	deleted := from == Pruned || from == Deleted
+	switch status {
+	case event.ReconcilePending:
+		if !deleted {
+			return r.transition(id, Reconciling)
+		}
+	case event.ReconcileSuccessful:
+		if !deleted {
+			return r.transition(id, Current)
+		}
+	case event.ReconcileSkipped:
+		if !deleted && from != Failed && from != Skipped {
+			return r.transition(id, Skipped)
+		}
+	case event.ReconcileTimeout:
+		return r.transition(id, TimedOut)
+	case event.ReconcileFailed:
+		return r.transition(id, Failed)
+	}
+	return nil
+}
+
+// state returns the state of an object, adding it as Pending if it is not
+// in the action groups of the run.
+func (r *runState) state(id object.ObjMetadata) *objectState {
+	obj, found := r.objects[id]
+	if !found {
+		obj = &objectState{state: Pending}
+		r.objects[id] = obj
+		r.ids = append(r.ids, id)
+	}
+	return obj
+}
+
+func (r *runState) transition(id object.ObjMetadata, to State) error {
+	obj := r.state(id)
+	if obj.state == to {
+		return nil
+	}
+	if !CanTransition(obj.state, to) {
+		return &InvalidTransitionError{ID: id, From: obj.state, To: to}
+	}
+	obj.history = append(obj.history, Transition{From: obj.state, To: to, Time: time.Now()})
+	obj.state = to
+	return nil
+}
+
+// RunIDs returns the IDs of the runs, in the order they were first observed.
+func (s *Store) RunIDs() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]string(nil), s.runIDs...)
+}
+
+// IDs returns the objects of the run, in the order of its action groups.
+func (s *Store) IDs(runID string) object.ObjMetadataSet {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	r, found := s.runs[runID]
+	if !found {
+		return nil
+	}
+	return append(object.ObjMetadataSet(nil), r.ids...)
+}
+
+// State returns the state of an object of the run. Returns false if the
+// object is not part of the run.
+func (s *Store) State(runID string, id object.ObjMetadata) (State, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	r, found := s.runs[runID]
+	if !found {
+		return Pending, false
+	}
+	obj, found := r.objects[id]
+	if !found {
+		return Pending, false
+	}
+	return obj.state, true
+}
+
+// States returns the states of the objects of the run.
+func (s *Store) States(runID string) map[object.ObjMetadata]State {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	r, found := s.runs[runID]
+	if !found {
+		return nil
+	}
+	states := make(map[object.ObjMetadata]State, len(r.objects))
+	for id, obj := range r.objects {
+		states[id] = obj.state
+	}
+	return states
+}
+
+// History returns the transitions of an object of the run, in order.
+func (s *Store) History(runID string, id object.ObjMetadata) []Transition {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	r, found := s.runs[runID]
+	if !found {
+		return nil
+	}
+	obj, found := r.objects[id]
+	if !found {
+		return nil
+	}
+	return append([]Transition(nil), obj.history...)
+}
+
+// Forget removes the states of the run, to free their memory.
+func (s *Store) Forget(runID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, found := s.runs[runID]; !found {
+		return
+	}
+	delete(s.runs, runID)
+	for i, id := range s.runIDs {
+		if id == runID {
+			s.runIDs = append(s.runIDs[:i], s.runIDs[i+1:]...)
+			break
+		}
+	}
+}
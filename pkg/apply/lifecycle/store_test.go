@@ -0,0 +1,180 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package lifecycle
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+var (
+	deploymentID = object.ObjMetadata{
+		GroupKind: schema.GroupKind{Group: "apps", Kind: "Deployment"},
+		Namespace: "default",
+		Name:      "deployment",
+	}
+	secretID = object.ObjMetadata{
+		GroupKind: schema.GroupKind{Kind: "Secret"},
+		Namespace: "default",
+		Name:      "secret",
+	}
+	configMapID = object.ObjMetadata{
+		GroupKind: schema.GroupKind{Kind: "ConfigMap"},
+		Namespace: "default",
+		Name:      "config-map",
+	}
+)
+
+func initEvent(runID string) event.Event {
+	return event.Event{
+		Type:  event.InitType,
+		RunID: runID,
+		InitEvent: event.InitEvent{
+			ActionGroups: event.ActionGroupList{
+				{Name: "inventory-add-0", Action: event.InventoryAction},
+				{Name: "apply-0", Action: event.ApplyAction, Identifiers: object.ObjMetadataSet{deploymentID, secretID}},
+				{Name: "wait-0", Action: event.WaitAction, Identifiers: object.ObjMetadataSet{deploymentID, secretID}},
+				{Name: "prune-0", Action: event.PruneAction, Identifiers: object.ObjMetadataSet{configMapID}},
+				{Name: "wait-1", Action: event.WaitAction, Identifiers: object.ObjMetadataSet{configMapID}},
+				{Name: "inventory-set-0", Action: event.InventoryAction},
+			},
+		},
+	}
+}
+
+func actionGroupEvent(runID, name string, action event.ResourceAction) event.Event {
+	return event.Event{
+		Type:             event.ActionGroupType,
+		RunID:            runID,
+		ActionGroupEvent: event.ActionGroupEvent{GroupName: name, Action: action, Status: event.Started},
+	}
+}
+
+func applyEvent(runID string, id object.ObjMetadata, status event.ApplyEventStatus) event.Event {
+	return event.Event{
+		Type:       event.ApplyType,
+		RunID:      runID,
+		ApplyEvent: event.ApplyEvent{Identifier: id, Status: status},
+	}
+}
+
+func waitEvent(runID string, id object.ObjMetadata, status event.WaitEventStatus) event.Event {
+	return event.Event{
+		Type:      event.WaitType,
+		RunID:     runID,
+		WaitEvent: event.WaitEvent{Identifier: id, Status: status},
+	}
+}
+
+func pruneEvent(runID string, id object.ObjMetadata, status event.PruneEventStatus) event.Event {
+	return event.Event{
+		Type:       event.PruneType,
+		RunID:      runID,
+		PruneEvent: event.PruneEvent{Identifier: id, Status: status},
+	}
+}
+
+func TestStore(t *testing.T) {
+	store := NewStore()
+	events := []event.Event{
+		initEvent("run-1"),
+		actionGroupEvent("run-1", "apply-0", event.ApplyAction),
+		applyEvent("run-1", deploymentID, event.ApplySuccessful),
+		applyEvent("run-1", secretID, event.ApplyFailed),
+		actionGroupEvent("run-1", "wait-0", event.WaitAction),
+		waitEvent("run-1", deploymentID, event.ReconcilePending),
+		waitEvent("run-1", secretID, event.ReconcileSkipped),
+		waitEvent("run-1", deploymentID, event.ReconcileSuccessful),
+		actionGroupEvent("run-1", "prune-0", event.PruneAction),
+		pruneEvent("run-1", configMapID, event.PruneSuccessful),
+		actionGroupEvent("run-1", "wait-1", event.WaitAction),
+		waitEvent("run-1", configMapID, event.ReconcilePending),
+		waitEvent("run-1", configMapID, event.ReconcileSuccessful),
+	}
+
+	// Check the states after the first events.
+	for _, e := range events[:2] {
+		require.NoError(t, store.Observe(e))
+	}
+	assert.Equal(t, map[object.ObjMetadata]State{
+		deploymentID: Applying,
+		secretID:     Applying,
+		configMapID:  Pending,
+	}, store.States("run-1"))
+
+	for _, e := range events[2:] {
+		require.NoError(t, store.Observe(e))
+	}
+	assert.Equal(t, []string{"run-1"}, store.RunIDs())
+	assert.Equal(t, object.ObjMetadataSet{deploymentID, secretID, configMapID}, store.IDs("run-1"))
+	assert.Equal(t, map[object.ObjMetadata]State{
+		deploymentID: Current,
+		secretID:     Failed,
+		configMapID:  Pruned,
+	}, store.States("run-1"))
+
+	var states []State
+	for _, transition := range store.History("run-1", deploymentID) {
+		states = append(states, transition.To)
+		assert.False(t, transition.Time.IsZero())
+	}
+	assert.Equal(t, []State{Applying, Applied, Reconciling, Current}, states)
+
+	state, found := store.State("run-1", configMapID)
+	assert.True(t, found)
+	assert.Equal(t, Pruned, state)
+	_, found = store.State("run-2", configMapID)
+	assert.False(t, found)
+
+	store.Forget("run-1")
+	assert.Empty(t, store.RunIDs())
+	assert.Nil(t, store.States("run-1"))
+}
+
+func TestStore_InvalidTransition(t *testing.T) {
+	store := NewStore()
+	require.NoError(t, store.Observe(initEvent("run-1")))
+
+	// The deployment was not applied yet.
+	err := store.Observe(waitEvent("run-1", deploymentID, event.ReconcileSuccessful))
+	var transitionErr *InvalidTransitionError
+	require.True(t, errors.As(err, &transitionErr))
+	assert.Equal(t, &InvalidTransitionError{ID: deploymentID, From: Pending, To: Current}, transitionErr)
+	assert.EqualError(t, err, "invalid lifecycle transition of object default_deployment_apps_Deployment: from Pending to Current")
+
+	state, found := store.State("run-1", deploymentID)
+	assert.True(t, found)
+	assert.Equal(t, Pending, state)
+	assert.Empty(t, store.History("run-1", deploymentID))
+}
+
+func TestStore_Forward(t *testing.T) {
+	store := NewStore()
+	eventChannel := make(chan event.Event)
+	go func() {
+		defer close(eventChannel)
+		eventChannel <- initEvent("run-1")
+		eventChannel <- initEvent("run-2")
+		eventChannel <- actionGroupEvent("run-2", "apply-0", event.ApplyAction)
+		eventChannel <- applyEvent("run-2", secretID, event.ApplySkipped)
+	}()
+	var count int
+	for range store.Forward(eventChannel) {
+		count++
+	}
+	assert.Equal(t, 4, count)
+	assert.Equal(t, []string{"run-1", "run-2"}, store.RunIDs())
+	state, _ := store.State("run-1", secretID)
+	assert.Equal(t, Pending, state)
+	state, _ = store.State("run-2", secretID)
+	assert.Equal(t, Skipped, state)
+	state, _ = store.State("run-2", deploymentID)
+	assert.Equal(t, Applying, state)
+}
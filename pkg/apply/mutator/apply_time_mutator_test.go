@@ -73,6 +73,10 @@ spec:
             name: old
             port:
               number: 80
+status:
+  loadBalancer:
+    ingress:
+    - ip: 10.0.0.1
 `
 
 var pod2y = `
@@ -261,6 +265,37 @@ var joinedPathsYaml = `
         number: 80
 `
 
+var configmap5y = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: map5-name
+  namespace: map-namespace
+  annotations:
+    config.kubernetes.io/apply-time-mutation: |
+      - sourceRef:
+          kind: Service
+          name: backend-name
+          namespace: map-namespace
+        sourcePath: $.spec.clusterIP
+        targetPath: $.data.url
+        token: ${backend-ip}
+data:
+  url: http://${backend-ip}:8080/api
+`
+
+var backendServicey = `
+apiVersion: v1
+kind: Service
+metadata:
+  name: backend-name
+  namespace: map-namespace
+spec:
+  clusterIP: 10.96.0.42
+  ports:
+  - port: 8080
+`
+
 var service1y = `
 apiVersion: v1
 kind: Service
@@ -376,6 +411,8 @@ func TestMutate(t *testing.T) {
 	configmap2 := ktestutil.YamlToUnstructured(t, configmap2y)
 	configmap3 := ktestutil.YamlToUnstructured(t, configmap3y)
 	configmap4 := ktestutil.YamlToUnstructured(t, configmap4y)
+	configmap5 := ktestutil.YamlToUnstructured(t, configmap5y)
+	backendService := ktestutil.YamlToUnstructured(t, backendServicey)
 	ingress2 := ktestutil.YamlToUnstructured(t, ingress2y)
 	service1 := ktestutil.YamlToUnstructured(t, service1y)
 	deployment1 := ktestutil.YamlToUnstructured(t, deployment1y)
@@ -547,6 +584,18 @@ func TestMutate(t *testing.T) {
 				},
 			},
 		},
+		"configmap data from service cluster ip": {
+			target:  configmap5,
+			sources: []*unstructured.Unstructured{backendService},
+			mutated: true,
+			reason:  expectedReason,
+			expected: []nestedFieldValue{
+				{
+					Field: []interface{}{"data", "url"},
+					Value: "http://10.96.0.42:8080/api",
+				},
+			},
+		},
 		"cluster-scoped": {
 			target:  clusterrolebinding1,
 			sources: []*unstructured.Unstructured{clusterrole1},
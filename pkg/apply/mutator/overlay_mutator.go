@@ -0,0 +1,151 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package mutator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	jsonpatch "gopkg.in/evanphx/json-patch.v4"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/klog/v2"
+	"k8s.io/kubectl/pkg/scheme"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/yaml"
+)
+
+// OverlayTarget selects the objects to patch. Empty fields match any value.
+type OverlayTarget struct {
+	Group     string
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+// Matches returns true if the object is selected by the target.
+func (t OverlayTarget) Matches(id object.ObjMetadata) bool {
+	return (t.Group == "" || t.Group == id.GroupKind.Group) &&
+		(t.Kind == "" || t.Kind == id.GroupKind.Kind) &&
+		(t.Namespace == "" || t.Namespace == id.Namespace) &&
+		(t.Name == "" || t.Name == id.Name)
+}
+
+// OverlayPatch is a patch of the objects selected by the Target.
+type OverlayPatch struct {
+	Target OverlayTarget
+	// Type is the type of the patch: types.StrategicMergePatchType,
+	// types.MergePatchType or types.JSONPatchType (RFC 6902). Strategic
+	// merge patches of types without a Go struct, like custom resources,
+	// are applied as JSON merge patches.
+	Type types.PatchType
+	// Patch is the patch, in JSON or YAML.
+	Patch string
+}
+
+// OverlayMutator applies the patches of an environment to the objects, for
+// the minor differences between environments, like the number of replicas.
+// The patches must not change the group, kind, namespace or name of the
+// objects.
+// Implements the Mutator interface
+type OverlayMutator struct {
+	// Environment is the name of the environment whose patches are applied.
+	// No patches are applied if empty.
+	Environment string
+	// Overlays are the patches of each environment, by environment name,
+	// applied in order.
+	Overlays map[string][]OverlayPatch
+}
+
+// Name returns a mutator identifier for logging.
+func (om *OverlayMutator) Name() string {
+	return "OverlayMutator"
+}
+
+// Mutate applies the patches of the environment that select the object.
+// Returns true with a reason, if any patch was applied.
+func (om *OverlayMutator) Mutate(_ context.Context, obj *unstructured.Unstructured) (bool, string, error) {
+	if om.Environment == "" {
+		return false, "", nil
+	}
+	patches, found := om.Overlays[om.Environment]
+	if !found {
+		return false, "", fmt.Errorf("unknown overlay environment: %q", om.Environment)
+	}
+	id := object.UnstructuredToObjMetadata(obj)
+	applied := 0
+	for i, patch := range patches {
+		if !patch.Target.Matches(id) {
+			continue
+		}
+		klog.V(5).Infof("applying overlay patch (environment: %q, index: %d, object: %s)", om.Environment, i, id)
+		patched, err := applyOverlayPatch(obj, patch)
+		if err != nil {
+			return false, "", fmt.Errorf("failed to apply overlay patch %d of environment %q to object (%s): %w",
+				i, om.Environment, id, err)
+		}
+		if patchedID := object.UnstructuredToObjMetadata(patched); patchedID != id {
+			return false, "", fmt.Errorf("overlay patch %d of environment %q changed the object (%s) into (%s)",
+				i, om.Environment, id, patchedID)
+		}
+		obj.Object = patched.Object
+		applied++
+	}
+	if applied == 0 {
+		return false, "", nil
+	}
+	return true, fmt.Sprintf("applied %d overlay patches of environment %q", applied, om.Environment), nil
+}
+
+// applyOverlayPatch returns a copy of the object with the patch applied.
+func applyOverlayPatch(obj *unstructured.Unstructured, patch OverlayPatch) (*unstructured.Unstructured, error) {
+	patchJSON, err := yaml.YAMLToJSON([]byte(patch.Patch))
+	if err != nil {
+		return nil, fmt.Errorf("invalid patch: %w", err)
+	}
+	objJSON, err := json.Marshal(obj.Object)
+	if err != nil {
+		return nil, err
+	}
+
+	var patchedJSON []byte
+	switch patch.Type {
+	case types.StrategicMergePatchType:
+		versioned, err := scheme.Scheme.New(obj.GroupVersionKind())
+		switch {
+		case err == nil:
+			patchedJSON, err = strategicpatch.StrategicMergePatch(objJSON, patchJSON, versioned)
+		case runtime.IsNotRegisteredError(err):
+			patchedJSON, err = jsonpatch.MergePatch(objJSON, patchJSON)
+		}
+		if err != nil {
+			return nil, err
+		}
+	case types.MergePatchType:
+		patchedJSON, err = jsonpatch.MergePatch(objJSON, patchJSON)
+		if err != nil {
+			return nil, err
+		}
+	case types.JSONPatchType:
+		jsonPatch, err := jsonpatch.DecodePatch(patchJSON)
+		if err != nil {
+			return nil, fmt.Errorf("invalid patch: %w", err)
+		}
+		patchedJSON, err = jsonPatch.Apply(objJSON)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported patch type: %q", patch.Type)
+	}
+
+	patched := &unstructured.Unstructured{}
+	if err := patched.UnmarshalJSON(patchedJSON); err != nil {
+		return nil, err
+	}
+	return patched, nil
+}
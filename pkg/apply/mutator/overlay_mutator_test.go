@@ -0,0 +1,179 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package mutator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/cli-utils/pkg/testutil"
+)
+
+var overlayDeployment = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: default
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:1
+      - name: sidecar
+        image: sidecar:1
+`
+
+var overlayCustomResource = `
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: widget
+  namespace: default
+spec:
+  size: small
+  color: blue
+`
+
+var overlays = map[string][]OverlayPatch{
+	"dev": nil,
+	"prod": {
+		{
+			Target: OverlayTarget{Kind: "Deployment", Name: "web"},
+			Type:   types.StrategicMergePatchType,
+			Patch: `
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:2
+`,
+		},
+		{
+			Target: OverlayTarget{Group: "example.com", Kind: "Widget"},
+			Type:   types.StrategicMergePatchType,
+			Patch: `
+spec:
+  size: large
+`,
+		},
+		{
+			Target: OverlayTarget{Kind: "Widget", Name: "widget"},
+			Type:   types.JSONPatchType,
+			Patch:  `[{"op": "remove", "path": "/spec/color"}]`,
+		},
+	},
+	"rename": {
+		{
+			Target: OverlayTarget{Kind: "Deployment"},
+			Type:   types.MergePatchType,
+			Patch:  `{"metadata": {"name": "other"}}`,
+		},
+	},
+}
+
+func TestOverlayMutator(t *testing.T) {
+	tests := map[string]struct {
+		environment string
+		obj         string
+		mutated     bool
+		reason      string
+		errMsg      string
+		expected    []nestedFieldValue
+		notExpected [][]interface{}
+	}{
+		"no environment": {
+			obj: overlayDeployment,
+		},
+		"environment without patches": {
+			environment: "dev",
+			obj:         overlayDeployment,
+		},
+		"unknown environment": {
+			environment: "staging",
+			obj:         overlayDeployment,
+			errMsg:      `unknown overlay environment: "staging"`,
+		},
+		"strategic merge patch of built-in type": {
+			environment: "prod",
+			obj:         overlayDeployment,
+			mutated:     true,
+			reason:      `applied 1 overlay patches of environment "prod"`,
+			expected: []nestedFieldValue{
+				{
+					Field: []interface{}{"spec", "replicas"},
+					Value: int64(3),
+				},
+				{
+					Field: []interface{}{"spec", "template", "spec", "containers", 0, "image"},
+					Value: "app:2",
+				},
+				// list items are merged by name
+				{
+					Field: []interface{}{"spec", "template", "spec", "containers", 1, "image"},
+					Value: "sidecar:1",
+				},
+			},
+		},
+		"merge and json patches of custom resource": {
+			environment: "prod",
+			obj:         overlayCustomResource,
+			mutated:     true,
+			reason:      `applied 2 overlay patches of environment "prod"`,
+			expected: []nestedFieldValue{
+				{
+					Field: []interface{}{"spec", "size"},
+					Value: "large",
+				},
+			},
+			notExpected: [][]interface{}{
+				{"spec", "color"},
+			},
+		},
+		"patch changes the object identity": {
+			environment: "rename",
+			obj:         overlayDeployment,
+			errMsg: `overlay patch 0 of environment "rename" changed the object (default_web_apps_Deployment) ` +
+				`into (default_other_apps_Deployment)`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			mutator := &OverlayMutator{
+				Environment: tc.environment,
+				Overlays:    overlays,
+			}
+			obj := testutil.Unstructured(t, tc.obj)
+
+			mutated, reason, err := mutator.Mutate(context.TODO(), obj)
+			if tc.errMsg != "" {
+				require.EqualError(t, err, tc.errMsg)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tc.mutated, mutated, "unexpected mutated bool")
+			require.Equal(t, tc.reason, reason, "unexpected mutated reason")
+
+			for _, efv := range tc.expected {
+				received, found, err := object.NestedField(obj.Object, efv.Field...)
+				require.NoError(t, err)
+				require.True(t, found, "target field not found")
+				require.Equal(t, efv.Value, received, "unexpected target field value")
+			}
+			for _, field := range tc.notExpected {
+				_, found, err := object.NestedField(obj.Object, field...)
+				require.NoError(t, err)
+				require.False(t, found, "unexpected target field")
+			}
+		})
+	}
+}
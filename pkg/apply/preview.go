@@ -0,0 +1,260 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package apply
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
+	"sigs.k8s.io/cli-utils/pkg/common"
+	"sigs.k8s.io/cli-utils/pkg/diff"
+	"sigs.k8s.io/cli-utils/pkg/inventory"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+// ChangeType is the type of change that applying the objects would make to
+// an object.
+//
+//go:generate stringer -type=ChangeType -linecomment
+type ChangeType int
+
+const (
+	// ChangeNoOp means the object exists and would not be changed.
+	ChangeNoOp ChangeType = iota // NoOp
+	// ChangeCreate means the object does not exist and would be created.
+	ChangeCreate // Create
+	// ChangeUpdate means the object exists and would be updated.
+	ChangeUpdate // Update
+	// ChangePrune means the object is no longer in the local objects and
+	// would be pruned.
+	ChangePrune // Prune
+	// ChangeForbidden means the object would fail to apply or prune, because
+	// of insufficient permissions.
+	ChangeForbidden // Forbidden
+	// ChangeSkipped means the apply or prune of the object would be skipped.
+	ChangeSkipped // Skipped
+	// ChangeFailed means the object is invalid, or would fail to apply or
+	// prune for another reason than insufficient permissions.
+	ChangeFailed // Failed
+)
+
+// Change is the change that applying the objects would make to one object.
+type Change struct {
+	Identifier object.ObjMetadata
+	Type       ChangeType
+	// Live is the object in the cluster before the change, if it exists.
+	Live *unstructured.Unstructured
+	// Desired is the object returned by the server-side dry-run, if the
+	// object would be created or updated.
+	Desired *unstructured.Unstructured
+	// Hunks are the lines that an update would change in the live object,
+	// rendered as YAML. See diff.Objects.
+	Hunks []diff.Hunk
+	// Dependents are the objects that the garbage collector would delete
+	// with a pruned object, if ApplierOptions.PruneListDependents is set.
+	Dependents object.ObjMetadataSet
+	// Error is the reason the object would be skipped, forbidden or fail.
+	Error error
+}
+
+// ChangeSet is the set of changes that applying the objects would make.
+type ChangeSet struct {
+	Changes []Change
+}
+
+// HasChanges returns true if applying the objects would create, update or
+// prune any object.
+func (cs *ChangeSet) HasChanges() bool {
+	for _, c := range cs.Changes {
+		switch c.Type {
+		case ChangeCreate, ChangeUpdate, ChangePrune:
+			return true
+		}
+	}
+	return false
+}
+
+// HasErrors returns true if any object would be forbidden or fail.
+func (cs *ChangeSet) HasErrors() bool {
+	for _, c := range cs.Changes {
+		switch c.Type {
+		case ChangeForbidden, ChangeFailed:
+			return true
+		}
+	}
+	return false
+}
+
+// Filter returns the changes of the specified type.
+func (cs *ChangeSet) Filter(changeType ChangeType) []Change {
+	var changes []Change
+	for _, c := range cs.Changes {
+		if c.Type == changeType {
+			changes = append(changes, c)
+		}
+	}
+	return changes
+}
+
+// Preview performs a server-side dry-run of the apply, including pruning,
+// and returns the changes that applying the objects would make, so callers
+// can decide whether to perform the apply. The DryRunStrategy of the options
+// is ignored. Returns an error if the preview could not be completed.
+func (a *Applier) Preview(ctx context.Context, invInfo inventory.Info, objects object.UnstructuredSet,
+	options ApplierOptions) (*ChangeSet, error) {
+	options.DryRunStrategy = common.DryRunServer
+	options.EmitStatusEvents = false
+
+	// Name the objects with a generateName first, so that they are looked
+	// up, and reported, with the name used by the dry-run.
+	objects = generateNames(objects)
+
+	// Look up the live objects before the dry-run, to compute the diffs.
+	live := make(map[object.ObjMetadata]*unstructured.Unstructured, len(objects))
+	for _, obj := range objects {
+		id := object.UnstructuredToObjMetadata(obj)
+		liveObj, err := a.getLiveObject(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if liveObj != nil {
+			live[id] = liveObj
+		}
+	}
+
+	builder := &changeSetBuilder{live: live}
+	var runErr error
+	// Consume all the events, even after an error, to let the run terminate.
+	for e := range a.Run(ctx, invInfo, objects, options) {
+		if err := builder.add(e); err != nil && runErr == nil {
+			runErr = err
+		}
+	}
+	if runErr != nil {
+		return nil, runErr
+	}
+	return builder.changeSet(), nil
+}
+
+// getLiveObject returns the object from the cluster, or nil if the object or
+// its type does not exist.
+func (a *Applier) getLiveObject(ctx context.Context, id object.ObjMetadata) (*unstructured.Unstructured, error) {
+	mapping, err := a.mapper.RESTMapping(id.GroupKind)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var obj *unstructured.Unstructured
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		obj, err = a.client.Resource(mapping.Resource).Namespace(id.Namespace).Get(ctx, id.Name, metav1.GetOptions{})
+	} else {
+		obj, err = a.client.Resource(mapping.Resource).Get(ctx, id.Name, metav1.GetOptions{})
+	}
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get object %q: %w", id, err)
+	}
+	return obj, nil
+}
+
+// changeSetBuilder builds a ChangeSet from the events of a dry-run.
+type changeSetBuilder struct {
+	live    map[object.ObjMetadata]*unstructured.Unstructured
+	changes []Change
+}
+
+// add adds the change described by the event, if any. Returns the error of
+// error events.
+func (b *changeSetBuilder) add(e event.Event) error {
+	switch e.Type {
+	case event.ErrorType:
+		return e.ErrorEvent.Err
+	case event.ValidationType:
+		for _, id := range e.ValidationEvent.Identifiers {
+			b.changes = append(b.changes, Change{
+				Identifier: id,
+				Type:       ChangeFailed,
+				Live:       b.live[id],
+				Error:      e.ValidationEvent.Error,
+			})
+		}
+	case event.ApplyType:
+		ae := e.ApplyEvent
+		change := Change{
+			Identifier: ae.Identifier,
+			Live:       b.live[ae.Identifier],
+			Error:      ae.Error,
+		}
+		switch ae.Status {
+		case event.ApplySuccessful:
+			change.Desired = ae.Resource
+			if change.Live != nil {
+				objDiff, err := diff.Objects(ae.Identifier, change.Live, change.Desired)
+				if err != nil {
+					return err
+				}
+				change.Hunks = objDiff.Hunks
+			}
+			switch {
+			case change.Live == nil:
+				change.Type = ChangeCreate
+			case len(change.Hunks) > 0:
+				change.Type = ChangeUpdate
+			default:
+				change.Type = ChangeNoOp
+			}
+		case event.ApplySkipped:
+			change.Type = ChangeSkipped
+		case event.ApplyFailed:
+			change.Type = failedChangeType(ae.Error)
+		default:
+			return nil
+		}
+		b.changes = append(b.changes, change)
+	case event.PruneType:
+		pe := e.PruneEvent
+		change := Change{
+			Identifier: pe.Identifier,
+			Live:       pe.Object,
+			Error:      pe.Error,
+		}
+		switch pe.Status {
+		case event.PruneSuccessful:
+			change.Type = ChangePrune
+			change.Dependents = pe.Dependents
+		case event.PruneSkipped:
+			change.Type = ChangeSkipped
+		case event.PruneFailed:
+			change.Type = failedChangeType(pe.Error)
+		default:
+			return nil
+		}
+		b.changes = append(b.changes, change)
+	}
+	return nil
+}
+
+func (b *changeSetBuilder) changeSet() *ChangeSet {
+	return &ChangeSet{Changes: b.changes}
+}
+
+// failedChangeType returns ChangeForbidden if the error is caused by
+// insufficient permissions, otherwise ChangeFailed.
+func failedChangeType(err error) ChangeType {
+	var statusErr apierrors.APIStatus
+	if errors.As(err, &statusErr) && statusErr.Status().Reason == metav1.StatusReasonForbidden {
+		return ChangeForbidden
+	}
+	return ChangeFailed
+}
@@ -0,0 +1,177 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package apply
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+	applyerror "sigs.k8s.io/cli-utils/pkg/apply/error"
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
+	"sigs.k8s.io/cli-utils/pkg/diff"
+	"sigs.k8s.io/cli-utils/pkg/inventory"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/watcher"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/cli-utils/pkg/testutil"
+)
+
+func newPreviewConfigMap(name, resourceVersion string, data map[string]interface{}) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]interface{}{
+				"name":            name,
+				"namespace":       "default",
+				"resourceVersion": resourceVersion,
+			},
+		},
+	}
+	if data != nil {
+		u.Object["data"] = data
+	}
+	return u
+}
+
+func TestChangeSetBuilder(t *testing.T) {
+	createdObj := newPreviewConfigMap("created", "", map[string]interface{}{"a": "1"})
+	updatedLive := newPreviewConfigMap("updated", "1", map[string]interface{}{"a": "1", "b": "2"})
+	updatedObj := newPreviewConfigMap("updated", "2", map[string]interface{}{"a": "3"})
+	unchangedLive := newPreviewConfigMap("unchanged", "1", map[string]interface{}{"a": "1"})
+	unchangedObj := newPreviewConfigMap("unchanged", "1", map[string]interface{}{"a": "1"})
+	prunedObj := newPreviewConfigMap("pruned", "1", nil)
+	forbiddenObj := newPreviewConfigMap("forbidden", "", nil)
+	failedObj := newPreviewConfigMap("failed", "", nil)
+	forbiddenErr := applyerror.NewApplyRunError(apierrors.NewForbidden(
+		schema.GroupResource{Resource: "configmaps"}, "forbidden", errors.New("not allowed")))
+	failedErr := applyerror.NewApplyRunError(errors.New("failed"))
+
+	id := object.UnstructuredToObjMetadata
+	builder := &changeSetBuilder{
+		live: map[object.ObjMetadata]*unstructured.Unstructured{
+			id(updatedLive):   updatedLive,
+			id(unchangedLive): unchangedLive,
+		},
+	}
+	events := []event.Event{
+		{Type: event.InitType},
+		{Type: event.ApplyType, ApplyEvent: event.ApplyEvent{
+			Identifier: id(createdObj), Status: event.ApplySuccessful, Resource: createdObj}},
+		{Type: event.ApplyType, ApplyEvent: event.ApplyEvent{
+			Identifier: id(updatedObj), Status: event.ApplySuccessful, Resource: updatedObj}},
+		{Type: event.ApplyType, ApplyEvent: event.ApplyEvent{
+			Identifier: id(unchangedObj), Status: event.ApplySuccessful, Resource: unchangedObj}},
+		{Type: event.ApplyType, ApplyEvent: event.ApplyEvent{
+			Identifier: id(forbiddenObj), Status: event.ApplyFailed, Error: forbiddenErr}},
+		{Type: event.ApplyType, ApplyEvent: event.ApplyEvent{
+			Identifier: id(failedObj), Status: event.ApplyFailed, Error: failedErr}},
+		{Type: event.PruneType, PruneEvent: event.PruneEvent{
+			Identifier: id(prunedObj), Status: event.PruneSuccessful, Object: prunedObj}},
+	}
+	for _, e := range events {
+		require.NoError(t, builder.add(e))
+	}
+
+	expected := &ChangeSet{
+		Changes: []Change{
+			{
+				Identifier: id(createdObj),
+				Type:       ChangeCreate,
+				Desired:    createdObj,
+			},
+			{
+				Identifier: id(updatedObj),
+				Type:       ChangeUpdate,
+				Live:       updatedLive,
+				Desired:    updatedObj,
+				Hunks: []diff.Hunk{
+					{
+						LiveStart:   1,
+						LiveLines:   7,
+						MergedStart: 1,
+						MergedLines: 6,
+						Lines: []diff.Line{
+							{Type: diff.LineContext, Text: "apiVersion: v1"},
+							{Type: diff.LineContext, Text: "data:"},
+							{Type: diff.LineRemoved, Text: `  a: "1"`},
+							{Type: diff.LineRemoved, Text: `  b: "2"`},
+							{Type: diff.LineAdded, Text: `  a: "3"`},
+							{Type: diff.LineContext, Text: "kind: ConfigMap"},
+							{Type: diff.LineContext, Text: "metadata:"},
+							{Type: diff.LineContext, Text: "  name: updated"},
+						},
+					},
+				},
+			},
+			{
+				Identifier: id(unchangedObj),
+				Type:       ChangeNoOp,
+				Live:       unchangedLive,
+				Desired:    unchangedObj,
+			},
+			{
+				Identifier: id(forbiddenObj),
+				Type:       ChangeForbidden,
+				Error:      forbiddenErr,
+			},
+			{
+				Identifier: id(failedObj),
+				Type:       ChangeFailed,
+				Error:      failedErr,
+			},
+			{
+				Identifier: id(prunedObj),
+				Type:       ChangePrune,
+				Live:       prunedObj,
+			},
+		},
+	}
+	changeSet := builder.changeSet()
+	assert.Equal(t, expected, changeSet)
+	assert.True(t, changeSet.HasChanges())
+	assert.True(t, changeSet.HasErrors())
+	assert.Len(t, changeSet.Filter(ChangeForbidden), 1)
+
+	runErr := errors.New("inventory error")
+	assert.Equal(t, runErr, builder.add(event.Event{Type: event.ErrorType, ErrorEvent: event.ErrorEvent{Err: runErr}}))
+}
+
+func TestApplierPreviewGenerateName(t *testing.T) {
+	inventoryObj := testutil.Unstructured(t, resources["inventory"])
+	inv := inventory.WrapInventoryInfoObj(inventoryObj)
+	invInfo := inventoryInfo{
+		name:      inv.Name(),
+		namespace: inv.Namespace(),
+		id:        inv.ID(),
+	}
+	generated := testutil.Unstructured(t, resources["obj1"])
+	generated.SetName("")
+	generated.SetGenerateName("obj1-")
+
+	applier := newTestApplier(t, invInfo, object.UnstructuredSet{generated}, object.UnstructuredSet{},
+		watcher.BlindStatusWatcher{})
+	// Preview is a server-side dry-run, which requires the server version.
+	applier.discoClient = &fakediscovery.FakeDiscovery{
+		Fake:               &clienttesting.Fake{},
+		FakedServerVersion: &version.Info{GitVersion: "v1.22.0"},
+	}
+
+	changeSet, err := applier.Preview(context.Background(), invInfo.toWrapped(), object.UnstructuredSet{generated}, ApplierOptions{})
+	require.NoError(t, err)
+
+	creates := changeSet.Filter(ChangeCreate)
+	require.Len(t, creates, 1)
+	assert.Regexp(t, "^obj1-[a-z0-9]{5}$", creates[0].Identifier.Name)
+	// The name is generated on a copy of the object.
+	assert.Empty(t, generated.GetName())
+}
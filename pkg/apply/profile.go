@@ -0,0 +1,207 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package apply
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
+)
+
+const (
+	// cpuProfileSuffix is the suffix of the CPU profile of a run, after the
+	// run ID.
+	cpuProfileSuffix = ".cpu.pprof"
+	// heapProfileSuffix is the suffix of the heap profile of a run, after
+	// the run ID.
+	heapProfileSuffix = ".heap.pprof"
+	// timingsSuffix is the suffix of the timings of a run, after the run ID.
+	timingsSuffix = ".timings.json"
+)
+
+// RunTimings is the time spent in each phase of a run, and in each task of
+// the run, written to the profile directory as JSON.
+type RunTimings struct {
+	RunID    string          `json:"runID"`
+	Duration metav1.Duration `json:"duration"`
+	// Phases are the phases of the run, in order: preparing the objects,
+	// building the task queue, running the tasks, etc.
+	Phases []Timing `json:"phases"`
+	// Tasks are the tasks run by the task runner, in order.
+	Tasks []Timing `json:"tasks"`
+}
+
+// Timing is the time spent in a phase or a task of a run.
+type Timing struct {
+	Name string `json:"name"`
+	// Start is the time since the start of the run.
+	Start    metav1.Duration `json:"start"`
+	Duration metav1.Duration `json:"duration"`
+}
+
+// runProfiler captures the CPU and heap profiles of a run, and the timings
+// of its phases and tasks, and writes them to the profile directory, named
+// after the run ID. The methods are no-ops on a nil runProfiler.
+type runProfiler struct {
+	dir   string
+	runID string
+
+	mu         sync.Mutex
+	cpuFile    *os.File
+	startTime  time.Time
+	phase      string
+	phaseStart time.Time
+	taskStarts map[string]time.Time
+	timings    RunTimings
+}
+
+func newRunProfiler(dir, runID string) *runProfiler {
+	return &runProfiler{
+		dir:        dir,
+		runID:      runID,
+		taskStarts: make(map[string]time.Time),
+		timings:    RunTimings{RunID: runID},
+	}
+}
+
+// start starts the CPU profile. Only one CPU profile can run at a time in a
+// process, so concurrent runs can not be profiled.
+func (p *runProfiler) start() error {
+	if p == nil {
+		return nil
+	}
+	if err := os.MkdirAll(p.dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create the profile directory: %w", err)
+	}
+	f, err := os.Create(p.path(cpuProfileSuffix))
+	if err != nil {
+		return fmt.Errorf("failed to create the CPU profile: %w", err)
+	}
+	if err := pprof.StartCPUProfile(f); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to start the CPU profile: %w", err)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cpuFile = f
+	p.startTime = time.Now()
+	return nil
+}
+
+// startPhase ends the current phase, if any, and starts the named phase.
+func (p *runProfiler) startPhase(name string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.endPhase(time.Now())
+	p.phase = name
+	p.phaseStart = time.Now()
+}
+
+// endPhase records the timing of the current phase. Requires the lock.
+func (p *runProfiler) endPhase(now time.Time) {
+	if p.phase == "" {
+		return
+	}
+	p.timings.Phases = append(p.timings.Phases, p.timing(p.phase, p.phaseStart, now))
+	p.phase = ""
+}
+
+// forward forwards the events of the run, and records the timings of the
+// tasks from the action group events. When the run is done, it stops the
+// profiles and writes them to the profile directory, before closing the
+// returned channel.
+func (p *runProfiler) forward(eventChannel <-chan event.Event) <-chan event.Event {
+	if p == nil {
+		return eventChannel
+	}
+	profiledChannel := make(chan event.Event)
+	go func() {
+		defer close(profiledChannel)
+		for e := range eventChannel {
+			if e.Type == event.ActionGroupType {
+				p.recordTask(e.ActionGroupEvent)
+			}
+			profiledChannel <- e
+		}
+		if err := p.stop(); err != nil {
+			klog.Warningf("failed to write the profiles (run: %s): %v", p.runID, err)
+		}
+	}()
+	return profiledChannel
+}
+
+func (p *runProfiler) recordTask(e event.ActionGroupEvent) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	switch e.Status {
+	case event.Started:
+		p.taskStarts[e.GroupName] = time.Now()
+	case event.Finished:
+		if start, found := p.taskStarts[e.GroupName]; found {
+			p.timings.Tasks = append(p.timings.Tasks, p.timing(e.GroupName, start, time.Now()))
+		}
+	}
+}
+
+// stop stops the CPU profile, and writes the heap profile and the timings.
+// Does nothing if the CPU profile was not started.
+func (p *runProfiler) stop() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cpuFile == nil {
+		return nil
+	}
+	now := time.Now()
+	p.endPhase(now)
+	p.timings.Duration = metav1.Duration{Duration: now.Sub(p.startTime)}
+	pprof.StopCPUProfile()
+	if err := p.cpuFile.Close(); err != nil {
+		return err
+	}
+	p.cpuFile = nil
+
+	f, err := os.Create(p.path(heapProfileSuffix))
+	if err != nil {
+		return err
+	}
+	// Collect the garbage, to profile the live objects.
+	runtime.GC()
+	if err := pprof.WriteHeapProfile(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(p.timings, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(p.path(timingsSuffix), data, 0o600)
+}
+
+func (p *runProfiler) timing(name string, start, end time.Time) Timing {
+	return Timing{
+		Name:     name,
+		Start:    metav1.Duration{Duration: start.Sub(p.startTime)},
+		Duration: metav1.Duration{Duration: end.Sub(start)},
+	}
+}
+
+func (p *runProfiler) path(suffix string) string {
+	return filepath.Join(p.dir, filepath.Base(p.runID)+suffix)
+}
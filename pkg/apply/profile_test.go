@@ -0,0 +1,66 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package apply
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
+)
+
+func TestRunProfiler(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "profiles")
+	prof := newRunProfiler(dir, "run-1")
+	require.NoError(t, prof.start())
+
+	eventChannel := make(chan event.Event)
+	profiledChannel := prof.forward(eventChannel)
+	go func() {
+		defer close(eventChannel)
+		prof.startPhase("validate")
+		prof.startPhase("run")
+		eventChannel <- event.Event{
+			Type:             event.ActionGroupType,
+			ActionGroupEvent: event.ActionGroupEvent{GroupName: "apply-0", Status: event.Started},
+		}
+		eventChannel <- event.Event{
+			Type:             event.ActionGroupType,
+			ActionGroupEvent: event.ActionGroupEvent{GroupName: "apply-0", Status: event.Finished},
+		}
+	}()
+	var events []event.Event
+	for e := range profiledChannel {
+		events = append(events, e)
+	}
+	assert.Len(t, events, 2)
+
+	for _, suffix := range []string{cpuProfileSuffix, heapProfileSuffix} {
+		info, err := os.Stat(filepath.Join(dir, "run-1"+suffix))
+		require.NoError(t, err)
+		assert.NotZero(t, info.Size())
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "run-1"+timingsSuffix))
+	require.NoError(t, err)
+	var timings RunTimings
+	require.NoError(t, json.Unmarshal(data, &timings))
+	assert.Equal(t, "run-1", timings.RunID)
+	require.Len(t, timings.Phases, 2)
+	assert.Equal(t, "validate", timings.Phases[0].Name)
+	assert.Equal(t, "run", timings.Phases[1].Name)
+	require.Len(t, timings.Tasks, 1)
+	assert.Equal(t, "apply-0", timings.Tasks[0].Name)
+}
+
+func TestRunProfilerDisabled(t *testing.T) {
+	var prof *runProfiler
+	require.NoError(t, prof.start())
+	prof.startPhase("validate")
+	eventChannel := make(chan event.Event)
+	assert.Equal(t, (<-chan event.Event)(eventChannel), prof.forward(eventChannel))
+}
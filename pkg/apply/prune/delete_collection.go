@@ -0,0 +1,154 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package prune
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/cli-utils/pkg/apply/taskrunner"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+// pruneBatches deletes the objects, grouped by GroupKind and namespace.
+// Groups of at least DeleteCollectionThreshold objects are deleted with a
+// single DeleteCollection request, if the objects share labels that select
+// exactly the objects of the group. Otherwise, or if the request fails, for
+// example because the resource does not support it, the objects are deleted
+// one by one.
+func (p *Pruner) pruneBatches(
+	objs object.UnstructuredSet,
+	taskContext *taskrunner.TaskContext,
+	eventFactory EventFactory,
+	opts Options,
+) error {
+	for _, batch := range groupByGroupKindNamespace(objs) {
+		// Stop early if the cluster is consistently failing.
+		if err := taskContext.CircuitBreaker().Err(); err != nil {
+			return err
+		}
+		if len(batch) >= opts.DeleteCollectionThreshold {
+			err := p.deleteCollection(batch, taskContext.CircuitBreaker(), opts)
+			if err == nil {
+				for _, obj := range batch {
+					taskContext.InventoryManager().AddSuccessfulDelete(object.UnstructuredToObjMetadata(obj), obj.GetUID())
+					taskContext.SendEvent(eventFactory.CreateSuccessEvent(obj))
+				}
+				continue
+			}
+			id := object.UnstructuredToObjMetadata(batch[0])
+			klog.V(4).Infof("deleting objects one by one (group: %q, namespace: %q, objects: %d): %v",
+				id.GroupKind, id.Namespace, len(batch), err)
+		}
+		for _, obj := range batch {
+			if err := taskContext.CircuitBreaker().Err(); err != nil {
+				return err
+			}
+			p.pruneObject(obj, taskContext, eventFactory, opts)
+		}
+	}
+	return nil
+}
+
+// deleteCollection deletes the objects, which must all have the same
+// GroupKind and namespace, with a single DeleteCollection request. Returns an
+// error, without deleting anything, if no label selector matches exactly the
+// objects.
+//
+// The DeleteCollection request is pinned to the resourceVersion of the List
+// that checked the selector, so that objects created with the same labels
+// after the check are not deleted. Unlike the deletion of a single object,
+// DeleteCollection has no UID precondition: an object deleted and re-created
+// with the same name and labels between the two requests is still deleted.
+func (p *Pruner) deleteCollection(objs object.UnstructuredSet, cb *taskrunner.CircuitBreaker, opts Options) error {
+	id := object.UnstructuredToObjMetadata(objs[0])
+	selector := commonLabels(objs)
+	if selector.Empty() {
+		return fmt.Errorf("objects have no labels in common")
+	}
+	namespacedClient, err := p.namespacedClient(id)
+	if err != nil {
+		return err
+	}
+	listOpts := metav1.ListOptions{LabelSelector: selector.String()}
+	list, err := namespacedClient.List(context.TODO(), listOpts)
+	cb.Record(err)
+	if err != nil {
+		return err
+	}
+	// Other objects may have the same labels, and must not be deleted.
+	expected := sets.New[types.UID]()
+	for _, obj := range objs {
+		expected.Insert(obj.GetUID())
+	}
+	found := sets.New[types.UID]()
+	for _, item := range list.Items {
+		found.Insert(item.GetUID())
+	}
+	if !expected.Equal(found) {
+		return fmt.Errorf("label selector %q matches %d objects, expected %d", listOpts.LabelSelector,
+			found.Len(), expected.Len())
+	}
+	if rv := list.GetResourceVersion(); rv != "" {
+		listOpts.ResourceVersion = rv
+		listOpts.ResourceVersionMatch = metav1.ResourceVersionMatchExact
+	}
+	klog.V(4).Infof("deleting collection (group: %q, namespace: %q, selector: %q, objects: %d)",
+		id.GroupKind, id.Namespace, listOpts.LabelSelector, len(objs))
+	err = namespacedClient.DeleteCollection(context.TODO(), metav1.DeleteOptions{
+		PropagationPolicy:  &opts.PropagationPolicy,
+		GracePeriodSeconds: opts.GracePeriodSeconds,
+	}, listOpts)
+	cb.Record(err)
+	return err
+}
+
+// commonLabels returns a selector for the labels with the same value on all
+// the objects.
+func commonLabels(objs object.UnstructuredSet) labels.Selector {
+	common := labels.Set{}
+	for k, v := range objs[0].GetLabels() {
+		common[k] = v
+	}
+	for _, obj := range objs[1:] {
+		objLabels := obj.GetLabels()
+		for k, v := range common {
+			if objLabels[k] != v {
+				delete(common, k)
+			}
+		}
+	}
+	return labels.SelectorFromSet(common)
+}
+
+// groupByGroupKindNamespace groups the objects by GroupKind and namespace,
+// preserving the order of the objects.
+func groupByGroupKindNamespace(objs object.UnstructuredSet) []object.UnstructuredSet {
+	type key struct {
+		groupKind schema.GroupKind
+		namespace string
+	}
+	index := make(map[key]int)
+	var groups []object.UnstructuredSet
+	for _, obj := range objs {
+		k := key{
+			groupKind: obj.GroupVersionKind().GroupKind(),
+			namespace: obj.GetNamespace(),
+		}
+		i, found := index[k]
+		if !found {
+			i = len(groups)
+			index[k] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], obj)
+	}
+	return groups
+}
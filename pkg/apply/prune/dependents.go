@@ -0,0 +1,127 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package prune
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+// DefaultDependentKinds are the kinds of the dependents listed when
+// Options.ListDependents is set, unless Options.DependentKinds is set. They
+// are the objects created by the controllers of the built-in workloads.
+var DefaultDependentKinds = []schema.GroupKind{
+	{Group: "apps", Kind: "ReplicaSet"},
+	{Group: "apps", Kind: "ControllerRevision"},
+	{Group: "batch", Kind: "Job"},
+	{Group: "", Kind: "Pod"},
+}
+
+// listDependents returns the dependents of each object, by UID: the objects
+// of the dependent kinds that the garbage collector deletes with the object,
+// because they are owned by the object, directly or indirectly, through
+// their ownerReferences. Objects deleted with the orphan propagation policy
+// have no dependents. Returns nil if the dependents are not listed.
+//
+// Failures to list a kind are logged and ignored, since the dependents are
+// only informational.
+func (p *Pruner) listDependents(ctx context.Context, objs object.UnstructuredSet, opts Options) map[types.UID]object.ObjMetadataSet {
+	if !opts.ListDependents || len(objs) == 0 {
+		return nil
+	}
+	kinds := opts.DependentKinds
+	if len(kinds) == 0 {
+		kinds = DefaultDependentKinds
+	}
+
+	// Dependents are in the namespace of their owner. Cluster-scoped owners
+	// may have dependents in any namespace.
+	namespaces := make(map[string]struct{})
+	for _, obj := range objs {
+		namespaces[obj.GetNamespace()] = struct{}{}
+	}
+	if _, found := namespaces[metav1.NamespaceAll]; found {
+		namespaces = map[string]struct{}{metav1.NamespaceAll: {}}
+	}
+
+	children := make(map[types.UID][]*unstructured.Unstructured)
+	for _, gk := range kinds {
+		mapping, err := p.Mapper.RESTMapping(gk)
+		if err != nil {
+			klog.V(4).Infof("skip listing dependents (kind: %q): %v", gk, err)
+			continue
+		}
+		listNamespaces := namespaces
+		if mapping.Scope.Name() == meta.RESTScopeNameRoot {
+			listNamespaces = map[string]struct{}{metav1.NamespaceAll: {}}
+		}
+		for ns := range listNamespaces {
+			list, err := p.Client.Resource(mapping.Resource).Namespace(ns).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				klog.Warningf("failed to list dependents (kind: %q, namespace: %q): %v", gk, ns, err)
+				continue
+			}
+			for i := range list.Items {
+				item := &list.Items[i]
+				for _, ref := range item.GetOwnerReferences() {
+					children[ref.UID] = append(children[ref.UID], item)
+				}
+			}
+		}
+	}
+
+	dependents := make(map[types.UID]object.ObjMetadataSet, len(objs))
+	for _, obj := range objs {
+		if propagation, _ := deleteOptions(obj, opts); propagation == metav1.DeletePropagationOrphan {
+			continue
+		}
+		// Breadth first, in case of ownership cycles.
+		var ids object.ObjMetadataSet
+		seen := map[types.UID]struct{}{obj.GetUID(): {}}
+		queue := []types.UID{obj.GetUID()}
+		for len(queue) > 0 {
+			uid := queue[0]
+			queue = queue[1:]
+			for _, child := range children[uid] {
+				if _, found := seen[child.GetUID()]; found {
+					continue
+				}
+				seen[child.GetUID()] = struct{}{}
+				ids = append(ids, object.UnstructuredToObjMetadata(child))
+				queue = append(queue, child.GetUID())
+			}
+		}
+		if len(ids) > 0 {
+			dependents[obj.GetUID()] = ids
+		}
+	}
+	return dependents
+}
+
+// dependentsEventFactory adds the dependents of the objects to the success
+// events of the wrapped EventFactory.
+type dependentsEventFactory struct {
+	EventFactory
+	dependents map[types.UID]object.ObjMetadataSet
+}
+
+func (f dependentsEventFactory) CreateSuccessEvent(obj *unstructured.Unstructured) event.Event {
+	e := f.EventFactory.CreateSuccessEvent(obj)
+	dependents := f.dependents[obj.GetUID()]
+	switch e.Type {
+	case event.PruneType:
+		e.PruneEvent.Dependents = dependents
+	case event.DeleteType:
+		e.DeleteEvent.Dependents = dependents
+	}
+	return e
+}
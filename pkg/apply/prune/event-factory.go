@@ -60,6 +60,7 @@ func (pef PruneEventFactory) CreateSkippedEvent(obj *unstructured.Unstructured,
 			Object:     obj,
 			Identifier: object.UnstructuredToObjMetadata(obj),
 			Error:      err,
+			SkipReason: event.SkipReasonOf(err),
 		},
 	}
 }
@@ -103,6 +104,7 @@ func (def DeleteEventFactory) CreateSkippedEvent(obj *unstructured.Unstructured,
 			Object:     obj,
 			Identifier: object.UnstructuredToObjMetadata(obj),
 			Error:      err,
+			SkipReason: event.SkipReasonOf(err),
 		},
 	}
 }
@@ -0,0 +1,66 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package prune
+
+import (
+	"context"
+
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/cli-utils/pkg/apply/cache"
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
+	"sigs.k8s.io/cli-utils/pkg/apply/filter"
+	"sigs.k8s.io/cli-utils/pkg/apply/taskrunner"
+	"sigs.k8s.io/cli-utils/pkg/common"
+	"sigs.k8s.io/cli-utils/pkg/inventory"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+// planTaskName is the name of the task group of the events of a Plan.
+const planTaskName = "prune-plan"
+
+// Plan is the set of objects that a prune would delete, computed without
+// deleting anything.
+type Plan struct {
+	// IDs are the objects that would be deleted.
+	IDs object.ObjMetadataSet
+	// Events are the prune (or delete) events of every object of the prune
+	// set, as sent by a dry-run: successful for the objects that would be
+	// deleted, skipped or failed for the others.
+	Events []event.Event
+}
+
+// Plan calculates the prune set, like GetPruneObjs, and evaluates the prune
+// filters on the live objects, without deleting or updating them, so the
+// deletions can be reviewed before pruning. The DryRunStrategy of the options
+// is ignored. Returns an error if the prune set can not be calculated.
+func (p *Pruner) Plan(
+	ctx context.Context,
+	inv inventory.Info,
+	objs object.UnstructuredSet,
+	pruneFilters []filter.ValidationFilter,
+	opts Options,
+) (*Plan, error) {
+	pruneObjs, err := p.getPruneObjs(ctx, inv, objs, opts)
+	if err != nil {
+		return nil, err
+	}
+	opts.DryRunStrategy = common.DryRunClient
+	opts.DeleteCollectionThreshold = 0
+
+	// Prune sends exactly one event per object, so the channel never blocks.
+	eventChannel := make(chan event.Event, len(pruneObjs))
+	taskContext := taskrunner.NewTaskContext(eventChannel, cache.NewResourceCacheMap())
+	err = p.Prune(pruneObjs, pruneFilters, taskContext, planTaskName, opts)
+	close(eventChannel)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{IDs: taskContext.InventoryManager().SuccessfulDeletes()}
+	for e := range eventChannel {
+		plan.Events = append(plan.Events, e)
+	}
+	klog.V(4).Infof("prune plan: %d of %d objects would be deleted", len(plan.IDs), len(pruneObjs))
+	return plan, nil
+}
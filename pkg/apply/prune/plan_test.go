@@ -0,0 +1,144 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package prune
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta/testrestmapper"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic/fake"
+	"k8s.io/kubectl/pkg/scheme"
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
+	"sigs.k8s.io/cli-utils/pkg/apply/filter"
+	"sigs.k8s.io/cli-utils/pkg/common"
+	"sigs.k8s.io/cli-utils/pkg/inventory"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+func TestPlan(t *testing.T) {
+	tests := map[string]struct {
+		localObjs      []*unstructured.Unstructured
+		prevInventory  []*unstructured.Unstructured
+		options        Options
+		expectedIDs    object.ObjMetadataSet
+		expectedEvents []event.Event
+	}{
+		"no prune objects": {
+			localObjs:     []*unstructured.Unstructured{pod, pdb},
+			prevInventory: []*unstructured.Unstructured{pod, pdb},
+			options:       defaultOptions,
+		},
+		"removed objects would be pruned": {
+			localObjs:     []*unstructured.Unstructured{pdb},
+			prevInventory: []*unstructured.Unstructured{pod, pdb, namespace},
+			options:       defaultOptions,
+			expectedIDs: object.ObjMetadataSet{
+				object.UnstructuredToObjMetadata(pod),
+				object.UnstructuredToObjMetadata(namespace),
+			},
+			expectedEvents: []event.Event{
+				{
+					Type: event.PruneType,
+					PruneEvent: event.PruneEvent{
+						GroupName:  planTaskName,
+						Identifier: object.UnstructuredToObjMetadata(pod),
+						Status:     event.PruneSuccessful,
+						Object:     pod,
+					},
+				},
+				{
+					Type: event.PruneType,
+					PruneEvent: event.PruneEvent{
+						GroupName:  planTaskName,
+						Identifier: object.UnstructuredToObjMetadata(namespace),
+						Status:     event.PruneSuccessful,
+						Object:     namespace,
+					},
+				},
+			},
+		},
+		"objects would be deleted by destroy": {
+			prevInventory: []*unstructured.Unstructured{pod},
+			options:       defaultOptionsDestroy,
+			expectedIDs: object.ObjMetadataSet{
+				object.UnstructuredToObjMetadata(pod),
+			},
+			expectedEvents: []event.Event{
+				{
+					Type: event.DeleteType,
+					DeleteEvent: event.DeleteEvent{
+						GroupName:  planTaskName,
+						Identifier: object.UnstructuredToObjMetadata(pod),
+						Status:     event.DeleteSuccessful,
+						Object:     pod,
+					},
+				},
+			},
+		},
+		"filtered objects would be skipped": {
+			prevInventory: []*unstructured.Unstructured{pod, podDeletionPrevention},
+			options:       defaultOptions,
+			expectedIDs: object.ObjMetadataSet{
+				object.UnstructuredToObjMetadata(pod),
+			},
+			expectedEvents: []event.Event{
+				{
+					Type: event.PruneType,
+					PruneEvent: event.PruneEvent{
+						GroupName:  planTaskName,
+						Identifier: object.UnstructuredToObjMetadata(pod),
+						Status:     event.PruneSuccessful,
+						Object:     pod,
+					},
+				},
+				{
+					Type: event.PruneType,
+					PruneEvent: event.PruneEvent{
+						GroupName:  planTaskName,
+						Identifier: object.UnstructuredToObjMetadata(podDeletionPrevention),
+						Status:     event.PruneSkipped,
+						Object:     podDeletionPrevention,
+						Error: &filter.AnnotationPreventedDeletionError{
+							Annotation: common.OnRemoveAnnotation,
+							Value:      common.OnRemoveKeep,
+						},
+						SkipReason: event.SkipReasonLocalPolicyPreventedDeletion,
+					},
+				},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			objs := make([]runtime.Object, 0, len(tc.prevInventory))
+			for _, obj := range tc.prevInventory {
+				objs = append(objs, obj)
+			}
+			client := fake.NewSimpleDynamicClient(scheme.Scheme, objs...)
+			po := Pruner{
+				InvClient: inventory.NewFakeClient(object.UnstructuredSetToObjMetadataSet(tc.prevInventory)),
+				Client:    client,
+				Mapper: testrestmapper.TestOnlyStaticRESTMapper(scheme.Scheme,
+					scheme.Scheme.PrioritizedVersionsAllGroups()...),
+			}
+			plan, err := po.Plan(context.Background(), createInventoryInfo(tc.prevInventory...),
+				tc.localObjs, []filter.ValidationFilter{filter.PreventRemoveFilter{}}, tc.options)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedIDs, plan.IDs)
+			assert.Equal(t, tc.expectedEvents, plan.Events)
+
+			// Nothing is deleted or updated.
+			for _, action := range client.Actions() {
+				assert.True(t, action.Matches("get", action.GetResource().Resource) ||
+					action.Matches("list", action.GetResource().Resource),
+					"unexpected action: %v", action)
+			}
+		})
+	}
+}
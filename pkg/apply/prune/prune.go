@@ -14,16 +14,21 @@ package prune
 import (
 	"context"
 	"errors"
+	"sync"
+	"time"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/klog/v2"
 	"k8s.io/kubectl/pkg/cmd/util"
 	"sigs.k8s.io/cli-utils/pkg/apply/filter"
 	"sigs.k8s.io/cli-utils/pkg/apply/taskrunner"
+	"sigs.k8s.io/cli-utils/pkg/backoff"
 	"sigs.k8s.io/cli-utils/pkg/common"
 	"sigs.k8s.io/cli-utils/pkg/inventory"
 	"sigs.k8s.io/cli-utils/pkg/object"
@@ -35,6 +40,10 @@ type Pruner struct {
 	InvClient inventory.Client
 	Client    dynamic.Interface
 	Mapper    meta.RESTMapper
+
+	// RetryPolicy configures how the deletion of an object is retried, when
+	// it fails with a transient error. The zero value disables retries.
+	RetryPolicy backoff.RetryPolicy
 }
 
 // NewPruner returns a new Pruner.
@@ -65,11 +74,46 @@ type Options struct {
 
 	PropagationPolicy metav1.DeletionPropagation
 
+	// GracePeriodSeconds is the grace period of the deletions, or nil to use
+	// the default grace period of each object.
+	GracePeriodSeconds *int64
+
 	// True if we are destroying, which deletes the inventory object
 	// as well (possibly) the inventory namespace.
 	Destroy bool
+
+	// DeleteCollectionThreshold is the minimum number of objects with the
+	// same GroupKind and namespace that are deleted with a single
+	// DeleteCollection request, instead of one request per object. Zero
+	// disables the batching.
+	//
+	// DeleteCollection has no UID precondition, so an object deleted and
+	// re-created with the same name and labels while the batch is deleted
+	// is deleted too, even if it is not in the inventory.
+	DeleteCollectionThreshold int
+
+	// ListDependents defines whether the dependents of each object are
+	// listed before it is deleted, and reported on its success event. The
+	// dependents are the objects of the DependentKinds that are owned by the
+	// object, directly or indirectly, and deleted with it by the garbage
+	// collector.
+	ListDependents bool
+
+	// DependentKinds are the kinds of the listed dependents. If empty,
+	// DefaultDependentKinds is used.
+	DependentKinds []schema.GroupKind
+
+	// FetchConcurrency is the maximum number of prune objects retrieved in
+	// parallel from the cluster, when calculating the prune set. Zero uses
+	// DefaultFetchConcurrency.
+	FetchConcurrency int
 }
 
+// DefaultFetchConcurrency is the default maximum number of prune objects
+// retrieved in parallel. The requests are still limited by the rate limiter
+// of the client.
+const DefaultFetchConcurrency = 10
+
 // Prune deletes the set of passed objects. A prune skip/failure is
 // captured in the TaskContext, so we do not lose track of these
 // objects from the inventory. The passed prune filters are used to
@@ -93,10 +137,19 @@ func (p *Pruner) Prune(
 	taskName string,
 	opts Options,
 ) error {
-	eventFactory := CreateEventFactory(opts.Destroy, taskName)
+	var eventFactory EventFactory = CreateEventFactory(opts.Destroy, taskName)
+	// List the dependents before anything is deleted.
+	if dependents := p.listDependents(context.TODO(), objs, opts); len(dependents) > 0 {
+		eventFactory = dependentsEventFactory{EventFactory: eventFactory, dependents: dependents}
+	}
+	var batchObjs object.UnstructuredSet
 	// Iterate through objects to prune (delete). If an object is not pruned
 	// and we need to keep it in the inventory, we must capture the prune failure.
 	for _, obj := range objs {
+		// Stop early if the cluster is consistently failing.
+		if err := taskContext.CircuitBreaker().Err(); err != nil {
+			return err
+		}
 		id := object.UnstructuredToObjMetadata(obj)
 		klog.V(5).Infof("evaluating prune filters (object: %q)", id)
 
@@ -108,8 +161,7 @@ func (p *Pruner) Prune(
 				// only log event emitted errors if the verbosity > 4
 				klog.Errorf("prune uid lookup errored (object: %s): %v", id, err)
 			}
-			taskContext.SendEvent(eventFactory.CreateFailedEvent(id, err))
-			taskContext.InventoryManager().AddFailedDelete(id)
+			failPrune(taskContext, eventFactory, id, err)
 			continue
 		}
 
@@ -125,8 +177,7 @@ func (p *Pruner) Prune(
 						// only log event emitted errors if the verbosity > 4
 						klog.Errorf("prune filter errored (filter: %s, object: %s): %v", pruneFilter.Name(), id, fatalErr.Err)
 					}
-					taskContext.SendEvent(eventFactory.CreateFailedEvent(id, fatalErr.Err))
-					taskContext.InventoryManager().AddFailedDelete(id)
+					failPrune(taskContext, eventFactory, id, fatalErr.Err)
 					break
 				}
 				klog.V(4).Infof("prune filtered (filter: %s, object: %s): %v", pruneFilter.Name(), id, filterErr)
@@ -143,8 +194,7 @@ func (p *Pruner) Prune(
 								// only log event emitted errors if the verbosity > 4
 								klog.Errorf("error removing annotation (object: %q, annotation: %q): %v", id, inventory.OwningInventoryKey, err)
 							}
-							taskContext.SendEvent(eventFactory.CreateFailedEvent(id, err))
-							taskContext.InventoryManager().AddFailedDelete(id)
+							failPrune(taskContext, eventFactory, id, err)
 							break
 						}
 						// Inventory annotation was successfully removed from the object.
@@ -165,6 +215,34 @@ func (p *Pruner) Prune(
 					}
 				}
 
+				// Mark the object for deletion if the prune was delayed, so that
+				// a later run prunes it once the delay has elapsed.
+				var delayedErr *filter.PruneDelayedError
+				if errors.As(filterErr, &delayedErr) && delayedErr.Mark {
+					if !opts.DryRunStrategy.ClientOrServerDryRun() {
+						var err error
+						obj, err = p.markForDeletion(obj, delayedErr.MarkedAt)
+						if err != nil {
+							if klog.V(4).Enabled() {
+								// only log event emitted errors if the verbosity > 4
+								klog.Errorf("error adding annotation (object: %q, annotation: %q): %v", id, common.PruneMarkedAtAnnotation, err)
+							}
+							failPrune(taskContext, eventFactory, id, err)
+							break
+						}
+					}
+				}
+
+				// Remove the object from inventory if it was adopted by another inventory,
+				// which now owns it.
+				var transferredErr *inventory.OwnershipTransferredError
+				if errors.As(filterErr, &transferredErr) {
+					if !opts.DryRunStrategy.ClientOrServerDryRun() {
+						// Register for removal from the inventory.
+						taskContext.AddAbandonedObject(id)
+					}
+				}
+
 				taskContext.SendEvent(eventFactory.CreateSkippedEvent(obj, filterErr))
 				taskContext.InventoryManager().AddSkippedDelete(id)
 				break
@@ -174,36 +252,122 @@ func (p *Pruner) Prune(
 			continue
 		}
 
+		// Objects deleted in batches are deleted after all filters passed.
+		// Objects that override the delete options are deleted one by one,
+		// with their own options.
+		if opts.DeleteCollectionThreshold > 0 && !opts.DryRunStrategy.ClientOrServerDryRun() &&
+			!overridesDeleteOptions(obj) {
+			batchObjs = append(batchObjs, obj)
+			continue
+		}
 		// Filters passed--actually delete object if not dry run.
-		if !opts.DryRunStrategy.ClientOrServerDryRun() {
-			klog.V(4).Infof("deleting object (object: %q)", id)
-			err := p.deleteObject(id, metav1.DeleteOptions{
+		p.pruneObject(obj, taskContext, eventFactory, opts)
+	}
+	return p.pruneBatches(batchObjs, taskContext, eventFactory, opts)
+}
+
+// pruneObject deletes the object, unless this is a dry run, and records the
+// result in the TaskContext.
+func (p *Pruner) pruneObject(
+	obj *unstructured.Unstructured,
+	taskContext *taskrunner.TaskContext,
+	eventFactory EventFactory,
+	opts Options,
+) {
+	id := object.UnstructuredToObjMetadata(obj)
+	uid := obj.GetUID()
+	if !opts.DryRunStrategy.ClientOrServerDryRun() {
+		propagationPolicy, gracePeriodSeconds := deleteOptions(obj, opts)
+		klog.V(4).Infof("deleting object (object: %q, propagation: %s)", id, propagationPolicy)
+		attempt := 0
+		err := p.retryPolicy(taskContext).Do(context.TODO(), func() error {
+			attempt++
+			if attempt > 1 {
+				klog.V(4).Infof("retrying delete (object: %q, attempt: %d)", id, attempt)
+			}
+			deleteErr := p.deleteObject(id, metav1.DeleteOptions{
 				// Only delete the resource if it hasn't already been deleted
 				// and recreated since the last GET. Otherwise error.
 				Preconditions: &metav1.Preconditions{
 					UID: &uid,
 				},
-				PropagationPolicy: &opts.PropagationPolicy,
+				PropagationPolicy:  &propagationPolicy,
+				GracePeriodSeconds: gracePeriodSeconds,
 			})
-			if err != nil {
-				if apierrors.IsNotFound(err) {
-					klog.Warningf("error deleting object (object: %q): object not found: object may have been deleted asynchronously by another client", id)
-					// treat this as successful idempotent deletion
-				} else {
-					if klog.V(4).Enabled() {
-						// only log event emitted errors if the verbosity > 4
-						klog.Errorf("error deleting object (object: %q): %v", id, err)
-					}
-					taskContext.SendEvent(eventFactory.CreateFailedEvent(id, err))
-					taskContext.InventoryManager().AddFailedDelete(id)
-					continue
+			taskContext.CircuitBreaker().Record(deleteErr)
+			return deleteErr
+		})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				klog.Warningf("error deleting object (object: %q): object not found: object may have been deleted asynchronously by another client", id)
+				// treat this as successful idempotent deletion
+			} else {
+				if klog.V(4).Enabled() {
+					// only log event emitted errors if the verbosity > 4
+					klog.Errorf("error deleting object (object: %q): %v", id, err)
 				}
+				failPrune(taskContext, eventFactory, id, err)
+				return
 			}
 		}
-		taskContext.InventoryManager().AddSuccessfulDelete(id, obj.GetUID())
-		taskContext.SendEvent(eventFactory.CreateSuccessEvent(obj))
 	}
-	return nil
+	taskContext.InventoryManager().AddSuccessfulDelete(id, obj.GetUID())
+	taskContext.SendEvent(eventFactory.CreateSuccessEvent(obj))
+}
+
+// retryPolicy returns the RetryPolicy of the pruner, which only retries
+// transient errors, and stops retrying once the circuit breaker has tripped.
+// Conflicts are not retried, because they are returned when the object was
+// re-created since it was retrieved, and the UID precondition failed.
+func (p *Pruner) retryPolicy(taskContext *taskrunner.TaskContext) backoff.RetryPolicy {
+	policy := p.RetryPolicy
+	retriable := policy.Retriable
+	if retriable == nil {
+		retriable = backoff.IsTransient
+	}
+	policy.Retriable = func(err error) bool {
+		return !taskContext.CircuitBreaker().Tripped() && retriable(err)
+	}
+	return policy
+}
+
+// failPrune reports the failed prune or delete of the object. The object is
+// kept in the inventory, so that the next run deletes it again.
+func failPrune(taskContext *taskrunner.TaskContext, eventFactory EventFactory, id object.ObjMetadata, err error) {
+	taskContext.SendEvent(eventFactory.CreateFailedEvent(id, err))
+	taskContext.InventoryManager().AddFailedDelete(id)
+	taskContext.AddPruneError(id, err)
+}
+
+// deleteOptions returns the propagation policy and grace period to delete the
+// object with: the options of the prune, unless overridden by the annotations
+// of the object. The on-remove annotation takes precedence over the
+// deletion-propagation annotation. Invalid annotations are ignored, as they
+// are reported by the validation.
+func deleteOptions(obj *unstructured.Unstructured, opts Options) (metav1.DeletionPropagation, *int64) {
+	propagationPolicy := opts.PropagationPolicy
+	if propagation, err := common.GetDeletionPropagation(obj); err == nil && propagation != "" {
+		propagationPolicy = propagation
+	}
+	// The on-remove annotation can keep the dependents of the object.
+	if common.ObjectLifecycleDirective(obj) == common.LifecycleOrphanDependents {
+		propagationPolicy = metav1.DeletePropagationOrphan
+	}
+	gracePeriodSeconds := opts.GracePeriodSeconds
+	if seconds, err := common.GetGracePeriodSeconds(obj); err == nil && seconds != nil {
+		gracePeriodSeconds = seconds
+	}
+	return propagationPolicy, gracePeriodSeconds
+}
+
+// overridesDeleteOptions returns true if the annotations of the object
+// override the options used to delete it.
+func overridesDeleteOptions(obj *unstructured.Unstructured) bool {
+	annotations := obj.GetAnnotations()
+	_, propagation := annotations[common.DeletionPropagationAnnotation]
+	_, gracePeriod := annotations[common.DeletionGracePeriodAnnotation]
+	return propagation || gracePeriod ||
+		common.ObjectLifecycleDirective(obj) == common.LifecycleOrphanDependents
 }
 
 // removeInventoryAnnotation removes the `config.k8s.io/owning-inventory` annotation from pruneObj.
@@ -229,6 +393,27 @@ func (p *Pruner) removeInventoryAnnotation(obj *unstructured.Unstructured) (*uns
 	return obj, nil
 }
 
+// markForDeletion sets the prune-marked-at annotation of the object to the
+// passed time, and updates the object in the cluster.
+func (p *Pruner) markForDeletion(obj *unstructured.Unstructured, markedAt time.Time) (*unstructured.Unstructured, error) {
+	// Make a copy of the input object to avoid modifying the input.
+	obj = obj.DeepCopy()
+	id := object.UnstructuredToObjMetadata(obj)
+	klog.V(4).Infof("marking object for deletion (object: %q, at: %s)", id, markedAt)
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[common.PruneMarkedAtAnnotation] = markedAt.UTC().Format(time.RFC3339)
+	obj.SetAnnotations(annotations)
+	namespacedClient, err := p.namespacedClient(id)
+	if err != nil {
+		return obj, err
+	}
+	_, err = namespacedClient.Update(context.TODO(), obj, metav1.UpdateOptions{})
+	return obj, err
+}
+
 // GetPruneObjs calculates the set of prune objects, and retrieves them
 // from the cluster. Set of prune objects equals the set of inventory
 // objects minus the set of currently applied objects. Returns an error
@@ -238,17 +423,71 @@ func (p *Pruner) GetPruneObjs(
 	objs object.UnstructuredSet,
 	opts Options,
 ) (object.UnstructuredSet, error) {
-	ids := object.UnstructuredSetToObjMetadataSet(objs)
+	return p.getPruneObjs(context.TODO(), inv, objs, opts)
+}
+
+func (p *Pruner) getPruneObjs(
+	ctx context.Context,
+	inv inventory.Info,
+	objs object.UnstructuredSet,
+	opts Options,
+) (object.UnstructuredSet, error) {
+	ids, err := object.NormalizeNamespaces(object.UnstructuredSetToObjMetadataSet(objs), p.Mapper)
+	if err != nil {
+		return nil, err
+	}
 	invIDs, err := p.InvClient.GetClusterObjs(inv)
 	if err != nil {
 		return nil, err
 	}
+	invIDs, err = object.NormalizeNamespaces(invIDs, p.Mapper)
+	if err != nil {
+		return nil, err
+	}
 	// only return objects that were in the inventory but not in the object set
 	ids = invIDs.Diff(ids)
-	objs = object.UnstructuredSet{}
-	for _, id := range ids {
-		pruneObj, err := p.getObject(id)
-		if err != nil {
+	return p.getObjects(ctx, ids, opts.FetchConcurrency)
+}
+
+// getObjects retrieves the objects from the cluster, with up to concurrency
+// GET requests in parallel, in the order of the ids. Objects that are not
+// found, or whose type is not registered, are skipped.
+func (p *Pruner) getObjects(
+	ctx context.Context,
+	ids object.ObjMetadataSet,
+	concurrency int,
+) (object.UnstructuredSet, error) {
+	if concurrency < 1 {
+		concurrency = DefaultFetchConcurrency
+	}
+	if concurrency > len(ids) {
+		concurrency = len(ids)
+	}
+	klog.V(4).Infof("retrieving prune objects (objects: %d, workers: %d)", len(ids), concurrency)
+
+	// Each worker only writes the results of its own indexes.
+	results := make(object.UnstructuredSet, len(ids))
+	errs := make([]error, len(ids))
+	indexCh := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexCh {
+				results[index], errs[index] = p.getObject(ctx, ids[index])
+			}
+		}()
+	}
+	for index := range ids {
+		indexCh <- index
+	}
+	close(indexCh)
+	wg.Wait()
+
+	objs := object.UnstructuredSet{}
+	for index, id := range ids {
+		if err := errs[index]; err != nil {
 			if meta.IsNoMatchError(err) {
 				klog.V(4).Infof("skip pruning (object: %q): resource type not registered", id)
 				continue
@@ -259,17 +498,17 @@ func (p *Pruner) GetPruneObjs(
 			}
 			return nil, err
 		}
-		objs = append(objs, pruneObj)
+		objs = append(objs, results[index])
 	}
 	return objs, nil
 }
 
-func (p *Pruner) getObject(id object.ObjMetadata) (*unstructured.Unstructured, error) {
+func (p *Pruner) getObject(ctx context.Context, id object.ObjMetadata) (*unstructured.Unstructured, error) {
 	namespacedClient, err := p.namespacedClient(id)
 	if err != nil {
 		return nil, err
 	}
-	return namespacedClient.Get(context.TODO(), id.Name, metav1.GetOptions{})
+	return namespacedClient.Get(ctx, id.Name, metav1.GetOptions{})
 }
 
 func (p *Pruner) deleteObject(id object.ObjMetadata, opts metav1.DeleteOptions) error {
@@ -280,10 +519,31 @@ func (p *Pruner) deleteObject(id object.ObjMetadata, opts metav1.DeleteOptions)
 	return namespacedClient.Delete(context.TODO(), id.Name, opts)
 }
 
+// finalizersPatch is a merge patch that removes all the finalizers.
+var finalizersPatch = []byte(`{"metadata":{"finalizers":null}}`)
+
+// RemoveFinalizers removes all the finalizers of the object, so that its
+// deletion completes without waiting for the controllers of the finalizers.
+func (p *Pruner) RemoveFinalizers(ctx context.Context, id object.ObjMetadata) error {
+	namespacedClient, err := p.namespacedClient(id)
+	if err != nil {
+		return err
+	}
+	_, err = namespacedClient.Patch(ctx, id.Name, types.MergePatchType, finalizersPatch, metav1.PatchOptions{})
+	if apierrors.IsNotFound(err) {
+		// already deleted
+		return nil
+	}
+	return err
+}
+
 func (p *Pruner) namespacedClient(id object.ObjMetadata) (dynamic.ResourceInterface, error) {
 	mapping, err := p.Mapper.RESTMapping(id.GroupKind)
 	if err != nil {
 		return nil, err
 	}
+	if mapping.Scope.Name() == meta.RESTScopeNameRoot {
+		return p.Client.Resource(mapping.Resource), nil
+	}
 	return p.Client.Resource(mapping.Resource).Namespace(id.Namespace), nil
 }
@@ -5,27 +5,35 @@ package prune
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/meta/testrestmapper"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
 	"k8s.io/kubectl/pkg/scheme"
 	"sigs.k8s.io/cli-utils/pkg/apply/cache"
+	applyerror "sigs.k8s.io/cli-utils/pkg/apply/error"
 	"sigs.k8s.io/cli-utils/pkg/apply/event"
 	"sigs.k8s.io/cli-utils/pkg/apply/filter"
 	"sigs.k8s.io/cli-utils/pkg/apply/taskrunner"
+	"sigs.k8s.io/cli-utils/pkg/backoff"
 	"sigs.k8s.io/cli-utils/pkg/common"
 	"sigs.k8s.io/cli-utils/pkg/inventory"
 	"sigs.k8s.io/cli-utils/pkg/object"
@@ -136,6 +144,13 @@ func createInventoryInfo(children ...*unstructured.Unstructured) inventory.Info
 	return inventory.WrapInventoryInfoObj(obj)
 }
 
+// Returns an inventory with the passed inventory ID.
+func inventoryInfoWithID(id string) inventory.Info {
+	inventoryObjCopy := inventoryObj.DeepCopy()
+	inventoryObjCopy.SetLabels(map[string]string{common.InventoryLabel: id})
+	return inventory.WrapInventoryInfoObj(inventoryObjCopy)
+}
+
 // podDeletionPrevention object contains the "on-remove:keep" lifecycle directive.
 var podDeletionPrevention = &unstructured.Unstructured{
 	Object: map[string]interface{}{
@@ -345,6 +360,39 @@ func TestPrune(t *testing.T) {
 						Error: testutil.EqualError(&filter.ApplyPreventedDeletionError{
 							UID: "pod-uid",
 						}),
+						SkipReason: event.SkipReasonApplyPreventedDeletion,
+					},
+				},
+			},
+			expectedSkipped: object.ObjMetadataSet{
+				object.UnstructuredToObjMetadata(pod),
+			},
+			expectedAbandoned: object.ObjMetadataSet{
+				object.UnstructuredToObjMetadata(pod),
+			},
+		},
+		"Object adopted by another inventory means prune skipped and object abandoned": {
+			clusterObjs: []*unstructured.Unstructured{pod},
+			pruneObjs:   []*unstructured.Unstructured{pod},
+			pruneFilters: []filter.ValidationFilter{
+				filter.InventoryPolicyPruneFilter{
+					// The pod is owned by the testInventoryLabel inventory.
+					Inv:       inventoryInfoWithID("previous-app-label"),
+					InvPolicy: inventory.PolicyMustMatch,
+				},
+			},
+			options: defaultOptions,
+			expectedEvents: []event.Event{
+				{
+					Type: event.PruneType,
+					PruneEvent: event.PruneEvent{
+						Identifier: object.UnstructuredToObjMetadata(pod),
+						Status:     event.PruneSkipped,
+						Object:     pod,
+						Error: testutil.EqualError(&inventory.OwnershipTransferredError{
+							Owner: testInventoryLabel,
+						}),
+						SkipReason: event.SkipReasonOwnershipTransferred,
 					},
 				},
 			},
@@ -375,6 +423,7 @@ func TestPrune(t *testing.T) {
 						Error: testutil.EqualError(&filter.ApplyPreventedDeletionError{
 							UID: "pod-uid",
 						}),
+						SkipReason: event.SkipReasonApplyPreventedDeletion,
 					},
 				},
 				{
@@ -416,6 +465,7 @@ func TestPrune(t *testing.T) {
 							Annotation: common.OnRemoveAnnotation,
 							Value:      common.OnRemoveKeep,
 						}),
+						SkipReason: event.SkipReasonLocalPolicyPreventedDeletion,
 					},
 				},
 				{
@@ -429,6 +479,7 @@ func TestPrune(t *testing.T) {
 							Annotation: common.LifecycleDeleteAnnotation,
 							Value:      common.PreventDeletion,
 						}),
+						SkipReason: event.SkipReasonLocalPolicyPreventedDeletion,
 					},
 				},
 			},
@@ -464,6 +515,7 @@ func TestPrune(t *testing.T) {
 							Annotation: common.OnRemoveAnnotation,
 							Value:      common.OnRemoveKeep,
 						}),
+						SkipReason: event.SkipReasonLocalPolicyPreventedDeletion,
 					},
 				},
 				{
@@ -477,6 +529,7 @@ func TestPrune(t *testing.T) {
 							Annotation: common.LifecycleDeleteAnnotation,
 							Value:      common.PreventDeletion,
 						}),
+						SkipReason: event.SkipReasonLocalPolicyPreventedDeletion,
 					},
 				},
 			},
@@ -506,6 +559,7 @@ func TestPrune(t *testing.T) {
 							Annotation: common.OnRemoveAnnotation,
 							Value:      common.OnRemoveKeep,
 						}),
+						SkipReason: event.SkipReasonLocalPolicyPreventedDeletion,
 					},
 				},
 				{
@@ -543,6 +597,7 @@ func TestPrune(t *testing.T) {
 						Error: testutil.EqualError(&filter.NamespaceInUseError{
 							Namespace: namespace.GetName(),
 						}),
+						SkipReason: event.SkipReasonNamespaceInUse,
 					},
 				},
 			},
@@ -685,7 +740,7 @@ func TestPruneDeletionPrevention(t *testing.T) {
 			require.NoError(t, err)
 
 			// verify that the object no longer has the annotation
-			obj, err := po.getObject(pruneID)
+			obj, err := po.getObject(context.TODO(), pruneID)
 			require.NoError(t, err)
 
 			for annotation := range obj.GetAnnotations() {
@@ -796,6 +851,12 @@ func TestPruneWithErrors(t *testing.T) {
 			}
 			err = testutil.VerifyEvents(tc.expectedEvents, actualEvents)
 			assert.NoError(t, err)
+
+			// The failed objects are aggregated, and kept in the inventory.
+			var pruneErr *applyerror.PruneError
+			require.True(t, errors.As(taskContext.PruneError(), &pruneErr))
+			testutil.AssertEqual(t, pruneIDs, pruneErr.Identifiers())
+			testutil.AssertEqual(t, pruneIDs, taskContext.InventoryManager().FailedDeletes())
 		})
 	}
 }
@@ -876,13 +937,76 @@ func TestGetPruneObjs(t *testing.T) {
 	}
 }
 
+func TestGetObjects(t *testing.T) {
+	var clusterObjs []runtime.Object
+	var ids, expectedIDs object.ObjMetadataSet
+	for i := 0; i < 50; i++ {
+		obj := pod.DeepCopy()
+		obj.SetName(fmt.Sprintf("pod-%d", i))
+		ids = append(ids, object.UnstructuredToObjMetadata(obj))
+		// Every third object is missing from the cluster.
+		if i%3 == 0 {
+			continue
+		}
+		clusterObjs = append(clusterObjs, obj)
+		expectedIDs = append(expectedIDs, object.UnstructuredToObjMetadata(obj))
+	}
+
+	tests := map[string]struct {
+		concurrency   int
+		failName      string
+		expectedIDs   object.ObjMetadataSet
+		expectedError string
+	}{
+		"default concurrency": {
+			expectedIDs: expectedIDs,
+		},
+		"sequential": {
+			concurrency: 1,
+			expectedIDs: expectedIDs,
+		},
+		"more workers than objects": {
+			concurrency: 100,
+			expectedIDs: expectedIDs,
+		},
+		"get error": {
+			concurrency:   4,
+			failName:      "pod-10",
+			expectedError: "expected get error",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := fake.NewSimpleDynamicClient(scheme.Scheme, clusterObjs...)
+			client.PrependReactor("get", "pods", func(action clienttesting.Action) (bool, runtime.Object, error) {
+				if action.(clienttesting.GetAction).GetName() == tc.failName {
+					return true, nil, fmt.Errorf("expected get error")
+				}
+				return false, nil, nil
+			})
+			po := Pruner{
+				Client: client,
+				Mapper: testrestmapper.TestOnlyStaticRESTMapper(scheme.Scheme,
+					scheme.Scheme.PrioritizedVersionsAllGroups()...),
+			}
+			objs, err := po.getObjects(context.TODO(), ids, tc.concurrency)
+			if tc.expectedError != "" {
+				require.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedIDs, object.UnstructuredSetToObjMetadataSet(objs))
+		})
+	}
+}
+
 func TestGetObject_NoMatchError(t *testing.T) {
 	po := Pruner{
 		Client: fake.NewSimpleDynamicClient(scheme.Scheme, pod, namespace),
 		Mapper: testrestmapper.TestOnlyStaticRESTMapper(scheme.Scheme,
 			scheme.Scheme.PrioritizedVersionsAllGroups()...),
 	}
-	_, err := po.getObject(testutil.ToIdentifier(t, crontabCRManifest))
+	_, err := po.getObject(context.TODO(), testutil.ToIdentifier(t, crontabCRManifest))
 	if err == nil {
 		t.Fatalf("expected GetObject() to return a NoKindMatchError, got nil")
 	}
@@ -898,7 +1022,7 @@ func TestGetObject_NotFoundError(t *testing.T) {
 			scheme.Scheme.PrioritizedVersionsAllGroups()...),
 	}
 	id := object.UnstructuredToObjMetadata(pdb)
-	_, err := po.getObject(id)
+	_, err := po.getObject(context.TODO(), id)
 	if err == nil {
 		t.Fatalf("expected GetObject() to return a NotFound error, got nil")
 	}
@@ -928,7 +1052,7 @@ func TestHandleDeletePrevention(t *testing.T) {
 	}
 
 	// Get the object from the cluster
-	obj, err = po.getObject(testutil.ToIdentifier(t, pdbDeletePreventionManifest))
+	obj, err = po.getObject(context.TODO(), testutil.ToIdentifier(t, pdbDeletePreventionManifest))
 	if err != nil {
 		t.Fatalf("unexpected error %s returned", err)
 	}
@@ -954,14 +1078,52 @@ func (c *optionsCaptureNamespaceClient) Delete(_ context.Context, _ string, opti
 }
 
 func TestPrune_PropagationPolicy(t *testing.T) {
+	orphanPDB := pdb.DeepCopy()
+	orphanPDB.SetAnnotations(map[string]string{common.OnRemoveAnnotation: common.OnRemoveOrphan})
+	deletePDB := pdb.DeepCopy()
+	deletePDB.SetAnnotations(map[string]string{common.OnRemoveAnnotation: common.OnRemoveDelete})
+	foregroundPDB := pdb.DeepCopy()
+	foregroundPDB.SetAnnotations(map[string]string{common.DeletionPropagationAnnotation: "Foreground"})
+	orphanForegroundPDB := pdb.DeepCopy()
+	orphanForegroundPDB.SetAnnotations(map[string]string{
+		common.OnRemoveAnnotation:            common.OnRemoveOrphan,
+		common.DeletionPropagationAnnotation: "Foreground",
+	})
+
 	testCases := map[string]struct {
+		obj               *unstructured.Unstructured
 		propagationPolicy metav1.DeletionPropagation
+		expected          metav1.DeletionPropagation
 	}{
 		"background propagation policy": {
+			obj:               pdb,
 			propagationPolicy: metav1.DeletePropagationBackground,
+			expected:          metav1.DeletePropagationBackground,
 		},
 		"foreground propagation policy": {
+			obj:               pdb,
 			propagationPolicy: metav1.DeletePropagationForeground,
+			expected:          metav1.DeletePropagationForeground,
+		},
+		"on-remove delete keeps the propagation policy": {
+			obj:               deletePDB,
+			propagationPolicy: metav1.DeletePropagationForeground,
+			expected:          metav1.DeletePropagationForeground,
+		},
+		"on-remove orphan overrides the propagation policy": {
+			obj:               orphanPDB,
+			propagationPolicy: metav1.DeletePropagationBackground,
+			expected:          metav1.DeletePropagationOrphan,
+		},
+		"deletion-propagation annotation overrides the propagation policy": {
+			obj:               foregroundPDB,
+			propagationPolicy: metav1.DeletePropagationBackground,
+			expected:          metav1.DeletePropagationForeground,
+		},
+		"on-remove orphan takes precedence over deletion-propagation": {
+			obj:               orphanForegroundPDB,
+			propagationPolicy: metav1.DeletePropagationBackground,
+			expected:          metav1.DeletePropagationOrphan,
 		},
 	}
 	for name, tc := range testCases {
@@ -979,12 +1141,274 @@ func TestPrune_PropagationPolicy(t *testing.T) {
 			eventChannel := make(chan event.Event, 1)
 			resourceCache := cache.NewResourceCacheMap()
 			taskContext := taskrunner.NewTaskContext(eventChannel, resourceCache)
-			err := po.Prune([]*unstructured.Unstructured{pdb}, []filter.ValidationFilter{}, taskContext, "test-0", Options{
+			err := po.Prune([]*unstructured.Unstructured{tc.obj}, []filter.ValidationFilter{}, taskContext, "test-0", Options{
 				PropagationPolicy: tc.propagationPolicy,
 			})
 			assert.NoError(t, err)
 			require.NotNil(t, captureClient.options.PropagationPolicy)
-			assert.Equal(t, tc.propagationPolicy, *captureClient.options.PropagationPolicy)
+			assert.Equal(t, tc.expected, *captureClient.options.PropagationPolicy)
+		})
+	}
+}
+
+func TestPrune_GracePeriodSeconds(t *testing.T) {
+	annotatedPDB := pdb.DeepCopy()
+	annotatedPDB.SetAnnotations(map[string]string{common.DeletionGracePeriodAnnotation: "0"})
+	zero := int64(0)
+	thirty := int64(30)
+
+	testCases := map[string]struct {
+		obj                *unstructured.Unstructured
+		gracePeriodSeconds *int64
+		expected           *int64
+	}{
+		"default grace period": {
+			obj:      pdb,
+			expected: nil,
+		},
+		"grace period option": {
+			obj:                pdb,
+			gracePeriodSeconds: &thirty,
+			expected:           &thirty,
+		},
+		"annotation overrides the grace period option": {
+			obj:                annotatedPDB,
+			gracePeriodSeconds: &thirty,
+			expected:           &zero,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			captureClient := &optionsCaptureNamespaceClient{}
+			po := Pruner{
+				InvClient: inventory.NewFakeClient(object.ObjMetadataSet{}),
+				Client: &fakeDynamicClient{
+					resourceInterface: captureClient,
+				},
+				Mapper: testrestmapper.TestOnlyStaticRESTMapper(scheme.Scheme,
+					scheme.Scheme.PrioritizedVersionsAllGroups()...),
+			}
+
+			eventChannel := make(chan event.Event, 1)
+			resourceCache := cache.NewResourceCacheMap()
+			taskContext := taskrunner.NewTaskContext(eventChannel, resourceCache)
+			err := po.Prune([]*unstructured.Unstructured{tc.obj}, []filter.ValidationFilter{}, taskContext, "test-0", Options{
+				PropagationPolicy:  metav1.DeletePropagationBackground,
+				GracePeriodSeconds: tc.gracePeriodSeconds,
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, captureClient.options.GracePeriodSeconds)
+		})
+	}
+}
+
+func TestPrune_RetryPolicy(t *testing.T) {
+	gr := schema.GroupResource{Group: "policy", Resource: "poddisruptionbudgets"}
+	errTransient := apierrors.NewTooManyRequests("slow down", 1)
+	errConflict := apierrors.NewConflict(gr, pdb.GetName(), errors.New("uid precondition failed"))
+
+	testCases := map[string]struct {
+		retryPolicy     backoff.RetryPolicy
+		errs            []error
+		expectedDeletes int
+		expectedStatus  event.PruneEventStatus
+	}{
+		"not retried by default": {
+			errs:            []error{errTransient, nil},
+			expectedDeletes: 1,
+			expectedStatus:  event.PruneFailed,
+		},
+		"transient error retried": {
+			retryPolicy:     backoff.RetryPolicy{MaxAttempts: 3, Backoff: backoff.Constant{}},
+			errs:            []error{errTransient, errTransient, nil},
+			expectedDeletes: 3,
+			expectedStatus:  event.PruneSuccessful,
+		},
+		"conflict not retried": {
+			retryPolicy:     backoff.RetryPolicy{MaxAttempts: 3, Backoff: backoff.Constant{}},
+			errs:            []error{errConflict, nil},
+			expectedDeletes: 1,
+			expectedStatus:  event.PruneFailed,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			client := fake.NewSimpleDynamicClient(scheme.Scheme, pdb)
+			deletes := 0
+			client.PrependReactor("delete", "poddisruptionbudgets", func(clienttesting.Action) (bool, runtime.Object, error) {
+				err := tc.errs[deletes]
+				deletes++
+				return true, nil, err
+			})
+			po := Pruner{
+				InvClient: inventory.NewFakeClient(object.ObjMetadataSet{}),
+				Client:    client,
+				Mapper: testrestmapper.TestOnlyStaticRESTMapper(scheme.Scheme,
+					scheme.Scheme.PrioritizedVersionsAllGroups()...),
+				RetryPolicy: tc.retryPolicy,
+			}
+
+			eventChannel := make(chan event.Event, 1)
+			taskContext := taskrunner.NewTaskContext(eventChannel, cache.NewResourceCacheMap())
+			err := po.Prune([]*unstructured.Unstructured{pdb}, []filter.ValidationFilter{}, taskContext, "test-0", defaultOptions)
+			require.NoError(t, err)
+			close(eventChannel)
+
+			assert.Equal(t, tc.expectedDeletes, deletes)
+			e := <-eventChannel
+			assert.Equal(t, tc.expectedStatus, e.PruneEvent.Status)
+		})
+	}
+}
+
+func TestPrune_ListDependents(t *testing.T) {
+	newObj := func(apiVersion, kind, name, uid string, owner *unstructured.Unstructured) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion(apiVersion)
+		u.SetKind(kind)
+		u.SetName(name)
+		u.SetNamespace(testNamespace)
+		u.SetUID(types.UID(uid))
+		if owner != nil {
+			u.SetOwnerReferences([]metav1.OwnerReference{{
+				APIVersion: owner.GetAPIVersion(),
+				Kind:       owner.GetKind(),
+				Name:       owner.GetName(),
+				UID:        owner.GetUID(),
+			}})
+		}
+		return u
+	}
+	deployment := newObj("apps/v1", "Deployment", "dep", "dep-uid", nil)
+	replicaSet := newObj("apps/v1", "ReplicaSet", "dep-rs", "rs-uid", deployment)
+	ownedPod := newObj("v1", "Pod", "dep-rs-pod", "owned-pod-uid", replicaSet)
+	otherPod := newObj("v1", "Pod", "other-pod", "other-pod-uid", nil)
+
+	testCases := map[string]struct {
+		options            Options
+		expectedDependents object.ObjMetadataSet
+	}{
+		"not listed by default": {
+			options: defaultOptions,
+		},
+		"owned objects listed": {
+			options: Options{
+				DryRunStrategy: common.DryRunNone,
+				ListDependents: true,
+			},
+			expectedDependents: object.ObjMetadataSet{
+				object.UnstructuredToObjMetadata(replicaSet),
+				object.UnstructuredToObjMetadata(ownedPod),
+			},
+		},
+		"only listed kinds": {
+			options: Options{
+				DryRunStrategy: common.DryRunNone,
+				ListDependents: true,
+				DependentKinds: []schema.GroupKind{{Group: "apps", Kind: "ReplicaSet"}},
+			},
+			expectedDependents: object.ObjMetadataSet{
+				object.UnstructuredToObjMetadata(replicaSet),
+			},
+		},
+		"orphaned dependents not listed": {
+			options: Options{
+				DryRunStrategy:    common.DryRunNone,
+				ListDependents:    true,
+				PropagationPolicy: metav1.DeletePropagationOrphan,
+			},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			po := Pruner{
+				InvClient: inventory.NewFakeClient(object.ObjMetadataSet{}),
+				Client:    fake.NewSimpleDynamicClient(scheme.Scheme, deployment, replicaSet, ownedPod, otherPod),
+				// Prefer apps/v1, the version of the objects in the client.
+				Mapper: testrestmapper.TestOnlyStaticRESTMapper(scheme.Scheme,
+					append([]schema.GroupVersion{appsv1.SchemeGroupVersion},
+						scheme.Scheme.PrioritizedVersionsAllGroups()...)...),
+			}
+
+			eventChannel := make(chan event.Event, 1)
+			taskContext := taskrunner.NewTaskContext(eventChannel, cache.NewResourceCacheMap())
+			err := po.Prune([]*unstructured.Unstructured{deployment}, []filter.ValidationFilter{}, taskContext, "test-0", tc.options)
+			require.NoError(t, err)
+			close(eventChannel)
+
+			e := <-eventChannel
+			assert.Equal(t, event.PruneSuccessful, e.PruneEvent.Status)
+			testutil.AssertEqual(t, tc.expectedDependents, e.PruneEvent.Dependents)
+		})
+	}
+}
+
+func TestPrune_Delay(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	delay := time.Hour
+	podID := object.UnstructuredToObjMetadata(pod)
+	markedPod := pod.DeepCopy()
+	annotations := markedPod.GetAnnotations()
+	annotations[common.PruneMarkedAtAnnotation] = now.Add(-2 * delay).Format(time.RFC3339)
+	markedPod.SetAnnotations(annotations)
+
+	testCases := map[string]struct {
+		obj            *unstructured.Unstructured
+		marked         object.ObjMetadataSet
+		options        Options
+		expectedStatus event.PruneEventStatus
+		expectedMark   string
+	}{
+		"object marked": {
+			obj:            pod,
+			options:        defaultOptions,
+			expectedStatus: event.PruneSkipped,
+			expectedMark:   now.Format(time.RFC3339),
+		},
+		"object not marked in dry-run": {
+			obj:            pod,
+			options:        clientDryRunOptions,
+			expectedStatus: event.PruneSkipped,
+		},
+		"marked object pruned after the delay": {
+			obj:            markedPod,
+			marked:         object.ObjMetadataSet{podID},
+			options:        defaultOptions,
+			expectedStatus: event.PruneSuccessful,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			client := fake.NewSimpleDynamicClient(scheme.Scheme, tc.obj.DeepCopy())
+			po := Pruner{
+				InvClient: inventory.NewFakeClient(object.ObjMetadataSet{podID}),
+				Client:    client,
+				Mapper: testrestmapper.TestOnlyStaticRESTMapper(scheme.Scheme,
+					scheme.Scheme.PrioritizedVersionsAllGroups()...),
+			}
+			pruneFilters := []filter.ValidationFilter{
+				filter.PruneDelayFilter{
+					Delay:  delay,
+					Marked: tc.marked,
+					Now:    func() time.Time { return now },
+				},
+			}
+
+			eventChannel := make(chan event.Event, 1)
+			taskContext := taskrunner.NewTaskContext(eventChannel, cache.NewResourceCacheMap())
+			err := po.Prune([]*unstructured.Unstructured{tc.obj}, pruneFilters, taskContext, "test-0", tc.options)
+			require.NoError(t, err)
+			close(eventChannel)
+
+			e := <-eventChannel
+			require.Equal(t, tc.expectedStatus, e.PruneEvent.Status)
+			if tc.expectedStatus != event.PruneSkipped {
+				return
+			}
+			assert.Equal(t, event.SkipReasonPruneDelayed, e.PruneEvent.SkipReason)
+			live, err := client.Resource(schema.GroupVersionResource{Version: "v1", Resource: "pods"}).Namespace(testNamespace).Get(context.TODO(), podName, metav1.GetOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedMark, live.GetAnnotations()[common.PruneMarkedAtAnnotation])
 		})
 	}
 }
@@ -1010,3 +1434,144 @@ type fakeDynamicResourceClient struct {
 func (c *fakeDynamicResourceClient) Namespace(ns string) dynamic.ResourceInterface {
 	return c.resourceInterface
 }
+
+func TestPrune_DeleteCollection(t *testing.T) {
+	labeledPod := func(name string, labels map[string]interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Pod",
+				"metadata": map[string]interface{}{
+					"name":      name,
+					"namespace": testNamespace,
+					"uid":       name + "-uid",
+					"labels":    labels,
+					"annotations": map[string]interface{}{
+						"config.k8s.io/owning-inventory": testInventoryLabel,
+					},
+				},
+			},
+		}
+	}
+	appLabels := map[string]interface{}{"app": "foo"}
+	pods := object.UnstructuredSet{
+		labeledPod("pod-a", map[string]interface{}{"app": "foo", "tier": "a"}),
+		labeledPod("pod-b", map[string]interface{}{"app": "foo", "tier": "b"}),
+		labeledPod("pod-c", appLabels),
+	}
+
+	testCases := map[string]struct {
+		pruneObjs             object.UnstructuredSet
+		otherObjs             object.UnstructuredSet
+		threshold             int
+		deleteCollectionErr   error
+		expectedCollections   []string
+		expectedDeletes       int
+		expectedDeleteSuccess int
+	}{
+		"objects deleted with a single request": {
+			pruneObjs:             pods,
+			threshold:             2,
+			expectedCollections:   []string{"app=foo"},
+			expectedDeleteSuccess: 3,
+		},
+		"fewer objects than the threshold": {
+			pruneObjs:             pods,
+			threshold:             4,
+			expectedDeletes:       3,
+			expectedDeleteSuccess: 3,
+		},
+		"other objects match the labels": {
+			pruneObjs:             pods,
+			otherObjs:             object.UnstructuredSet{labeledPod("pod-d", appLabels)},
+			threshold:             2,
+			expectedDeletes:       3,
+			expectedDeleteSuccess: 3,
+		},
+		"objects without common labels": {
+			pruneObjs: object.UnstructuredSet{
+				labeledPod("pod-a", map[string]interface{}{"app": "a"}),
+				labeledPod("pod-b", map[string]interface{}{"app": "b"}),
+			},
+			threshold:             2,
+			expectedDeletes:       2,
+			expectedDeleteSuccess: 2,
+		},
+		"delete collection not supported": {
+			pruneObjs:             pods,
+			threshold:             2,
+			deleteCollectionErr:   apierrors.NewMethodNotSupported(schema.GroupResource{Resource: "pods"}, "deletecollection"),
+			expectedCollections:   []string{"app=foo"},
+			expectedDeletes:       3,
+			expectedDeleteSuccess: 3,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var clusterObjs []runtime.Object
+			for _, obj := range append(tc.pruneObjs, tc.otherObjs...) {
+				clusterObjs = append(clusterObjs, obj)
+			}
+			client := fake.NewSimpleDynamicClient(scheme.Scheme, clusterObjs...)
+			// The List that checks the selector returns a resourceVersion.
+			client.PrependReactor("list", "pods", func(action clienttesting.Action) (bool, runtime.Object, error) {
+				objs, err := client.Tracker().List(
+					schema.GroupVersionResource{Version: "v1", Resource: "pods"},
+					schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, testNamespace)
+				if err != nil {
+					return true, nil, err
+				}
+				list := objs.(*unstructured.UnstructuredList)
+				var items []unstructured.Unstructured
+				selector := action.(clienttesting.ListAction).GetListRestrictions().Labels
+				for _, item := range list.Items {
+					if selector.Matches(labels.Set(item.GetLabels())) {
+						items = append(items, item)
+					}
+				}
+				list.Items = items
+				list.SetResourceVersion("42")
+				return true, list, nil
+			})
+			var collections []string
+			client.PrependReactor("delete-collection", "pods", func(action clienttesting.Action) (bool, runtime.Object, error) {
+				collections = append(collections,
+					action.(clienttesting.DeleteCollectionAction).GetListRestrictions().Labels.String())
+				listOpts := action.(clienttesting.DeleteCollectionActionImpl).ListOptions
+				assert.Equal(t, "42", listOpts.ResourceVersion)
+				assert.Equal(t, metav1.ResourceVersionMatchExact, listOpts.ResourceVersionMatch)
+				return true, nil, tc.deleteCollectionErr
+			})
+			deletes := 0
+			client.PrependReactor("delete", "pods", func(clienttesting.Action) (bool, runtime.Object, error) {
+				deletes++
+				return true, nil, nil
+			})
+			po := Pruner{
+				InvClient: inventory.NewFakeClient(object.ObjMetadataSet{}),
+				Client:    client,
+				Mapper: testrestmapper.TestOnlyStaticRESTMapper(scheme.Scheme,
+					scheme.Scheme.PrioritizedVersionsAllGroups()...),
+			}
+
+			eventChannel := make(chan event.Event, len(tc.pruneObjs))
+			taskContext := taskrunner.NewTaskContext(eventChannel, cache.NewResourceCacheMap())
+			err := po.Prune(tc.pruneObjs, []filter.ValidationFilter{}, taskContext, "test-0", Options{
+				PropagationPolicy:         metav1.DeletePropagationBackground,
+				DeleteCollectionThreshold: tc.threshold,
+			})
+			require.NoError(t, err)
+			close(eventChannel)
+
+			assert.Equal(t, tc.expectedCollections, collections)
+			assert.Equal(t, tc.expectedDeletes, deletes)
+			successes := 0
+			for e := range eventChannel {
+				if e.PruneEvent.Status == event.PruneSuccessful {
+					successes++
+				}
+			}
+			assert.Equal(t, tc.expectedDeleteSuccess, successes)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package readiness provides readiness gates: checks outside the cluster,
+// like smoke tests, that applied objects must pass in addition to being
+// Current, before they are considered reconciled.
+package readiness
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os/exec"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+// Gate checks whether an object is ready. Gates are only checked once the
+// object is Current, and are checked again until they pass, or the wait
+// times out. Gates that do not apply to the object must return true.
+type Gate interface {
+	// Ready returns true if the object is ready. The object is the latest
+	// state of the object known to the applier. An error is treated as not
+	// ready, and logged.
+	Ready(ctx context.Context, id object.ObjMetadata, obj *unstructured.Unstructured) (bool, error)
+}
+
+// GateFunc is a function implementing the Gate interface.
+type GateFunc func(ctx context.Context, id object.ObjMetadata, obj *unstructured.Unstructured) (bool, error)
+
+// Ready implements the Gate interface.
+func (f GateFunc) Ready(ctx context.Context, id object.ObjMetadata, obj *unstructured.Unstructured) (bool, error) {
+	return f(ctx, id, obj)
+}
+
+// ForObjects returns a Gate that only applies the gate to the given objects.
+func ForObjects(gate Gate, ids ...object.ObjMetadata) Gate {
+	set := object.ObjMetadataSet(ids)
+	return GateFunc(func(ctx context.Context, id object.ObjMetadata, obj *unstructured.Unstructured) (bool, error) {
+		if !set.Contains(id) {
+			return true, nil
+		}
+		return gate.Ready(ctx, id, obj)
+	})
+}
+
+// HTTPGet returns a Gate that sends a GET request to the URL, e.g. of a
+// Service exposed by the object, and passes if the response has a 2xx
+// status code. If client is nil, http.DefaultClient is used.
+func HTTPGet(client *http.Client, url string) Gate {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return GateFunc(func(ctx context.Context, _ object.ObjMetadata, _ *unstructured.Unstructured) (bool, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return false, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return false, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return false, fmt.Errorf("GET %s: unexpected status: %s", url, resp.Status)
+		}
+		return true, nil
+	})
+}
+
+// Exec returns a Gate that runs the command, e.g. a smoke test script, and
+// passes if the command exits with status 0.
+func Exec(name string, args ...string) Gate {
+	return GateFunc(func(ctx context.Context, _ object.ObjMetadata, _ *unstructured.Unstructured) (bool, error) {
+		out, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+		if err != nil {
+			return false, fmt.Errorf("%s: %w: %s", name, err, out)
+		}
+		return true, nil
+	})
+}
+
+// All returns true if all the gates pass for the object. It stops at the
+// first gate that does not pass.
+func All(ctx context.Context, gates []Gate, id object.ObjMetadata, obj *unstructured.Unstructured) (bool, error) {
+	for _, gate := range gates {
+		ready, err := gate.Ready(ctx, id, obj)
+		if err != nil || !ready {
+			return false, err
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,74 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package readiness
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+var (
+	deploymentID = object.ObjMetadata{
+		GroupKind: schema.GroupKind{Group: "apps", Kind: "Deployment"},
+		Name:      "foo",
+		Namespace: "default",
+	}
+	serviceID = object.ObjMetadata{
+		GroupKind: schema.GroupKind{Kind: "Service"},
+		Name:      "foo",
+		Namespace: "default",
+	}
+	notReady = GateFunc(func(context.Context, object.ObjMetadata, *unstructured.Unstructured) (bool, error) {
+		return false, nil
+	})
+)
+
+func TestHTTPGet(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/healthz" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	ready, err := HTTPGet(server.Client(), server.URL+"/healthz").Ready(context.Background(), serviceID, nil)
+	assert.NoError(t, err)
+	assert.True(t, ready)
+
+	ready, err = HTTPGet(server.Client(), server.URL+"/other").Ready(context.Background(), serviceID, nil)
+	assert.EqualError(t, err, "GET "+server.URL+"/other: unexpected status: 503 Service Unavailable")
+	assert.False(t, ready)
+}
+
+func TestExec(t *testing.T) {
+	ready, err := Exec("true").Ready(context.Background(), deploymentID, nil)
+	assert.NoError(t, err)
+	assert.True(t, ready)
+
+	ready, err = Exec("false").Ready(context.Background(), deploymentID, nil)
+	assert.Error(t, err)
+	assert.False(t, ready)
+}
+
+func TestAll(t *testing.T) {
+	gates := []Gate{
+		Exec("true"),
+		ForObjects(notReady, serviceID),
+	}
+
+	ready, err := All(context.Background(), gates, deploymentID, nil)
+	assert.NoError(t, err)
+	assert.True(t, ready, "the gate of the service does not apply to the deployment")
+
+	ready, err = All(context.Background(), gates, serviceID, nil)
+	assert.NoError(t, err)
+	assert.False(t, ready)
+}
@@ -0,0 +1,259 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package apply
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/cli-utils/pkg/apis/actuation"
+	"sigs.k8s.io/cli-utils/pkg/apply/backup"
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
+	"sigs.k8s.io/cli-utils/pkg/apply/taskrunner"
+	"sigs.k8s.io/cli-utils/pkg/common"
+	"sigs.k8s.io/cli-utils/pkg/inventory"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/cli-utils/pkg/object/graph"
+)
+
+// rollbackSnapshot records the state of the cluster before an apply, so that
+// a failed apply can be rolled back.
+type rollbackSnapshot struct {
+	// invIDs are the objects stored in the inventory.
+	invIDs object.ObjMetadataSet
+	// invStatus is the status of the objects recorded in the inventory, if
+	// the inventory client records it.
+	invStatus []actuation.ObjectStatus
+	// ids are the objects to apply, in apply order.
+	ids object.ObjMetadataSet
+	// objs are the live objects to apply. Objects that did not exist are
+	// missing.
+	objs map[object.ObjMetadata]*unstructured.Unstructured
+}
+
+// takeRollbackSnapshot reads the inventory and the live state of the objects
+// to apply.
+func (a *Applier) takeRollbackSnapshot(ctx context.Context, invInfo inventory.Info,
+	applyObjs object.UnstructuredSet) (*rollbackSnapshot, error) {
+	invIDs, err := a.invClient.GetClusterObjs(invInfo)
+	if err != nil {
+		return nil, err
+	}
+	invStatus, err := inventoryStatus(a.invClient, invInfo)
+	if err != nil {
+		return nil, err
+	}
+	// Sort the objects in apply order, to roll them back in reverse order.
+	ids := graph.SortIDs(applyObjs)
+	objs, err := a.liveObjects(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to take rollback snapshot: %w", err)
+	}
+	snapshot := &rollbackSnapshot{
+		invIDs:    invIDs,
+		invStatus: invStatus,
+		ids:       ids,
+		objs:      objs,
+	}
+	klog.V(4).Infof("rollback snapshot taken (objects: %d, existing: %d)", len(snapshot.ids), len(snapshot.objs))
+	return snapshot, nil
+}
+
+// inventoryStatus returns the status of the objects recorded in the
+// inventory, if the inventory client records it.
+func inventoryStatus(invClient inventory.Client, invInfo inventory.Info) ([]actuation.ObjectStatus, error) {
+	statusClient, ok := invClient.(inventory.StatusClient)
+	if !ok {
+		return nil, nil
+	}
+	return statusClient.GetClusterObjStatus(invInfo)
+}
+
+// liveObjects returns the live objects, by ID. Objects that do not exist, or
+// whose kind is not served yet, are missing.
+func (a *Applier) liveObjects(ctx context.Context, ids object.ObjMetadataSet) (map[object.ObjMetadata]*unstructured.Unstructured, error) {
+	objs := make(map[object.ObjMetadata]*unstructured.Unstructured)
+	for _, id := range ids {
+		client, err := a.resourceClient(id)
+		if meta.IsNoMatchError(err) {
+			// The CRD is applied in the same run.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		obj, err := client.Get(ctx, id.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", id, err)
+		}
+		objs[id] = obj
+	}
+	return objs, nil
+}
+
+// backupObjects stores the live state of the objects to apply that exist,
+// and of the objects to prune, as the backup of the run. The live objects to
+// apply are read from the rollback snapshot, if taken.
+func (a *Applier) backupObjects(ctx context.Context, store backup.Store, runID string,
+	applyObjs, pruneObjs object.UnstructuredSet, snapshot *rollbackSnapshot) error {
+	ids := object.UnstructuredSetToObjMetadataSet(applyObjs)
+	var liveObjs map[object.ObjMetadata]*unstructured.Unstructured
+	if snapshot != nil {
+		liveObjs = snapshot.objs
+	} else {
+		var err error
+		liveObjs, err = a.liveObjects(ctx, ids)
+		if err != nil {
+			return fmt.Errorf("failed to back up objects: %w", err)
+		}
+	}
+	var objs object.UnstructuredSet
+	for _, id := range ids {
+		if obj, found := liveObjs[id]; found {
+			objs = append(objs, backupCopy(obj))
+		}
+	}
+	for _, obj := range pruneObjs {
+		objs = append(objs, backupCopy(obj))
+	}
+	klog.V(4).Infof("backing up objects (run: %s, objects: %d)", runID, len(objs))
+	if err := store.Save(ctx, runID, objs); err != nil {
+		return fmt.Errorf("failed to back up objects: %w", err)
+	}
+	return nil
+}
+
+// backupCopy returns a copy of the live object without its managed fields,
+// which are not restored.
+func backupCopy(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	obj = obj.DeepCopy()
+	obj.SetManagedFields(nil)
+	return obj
+}
+
+// rollback restores the objects that were applied to their state in the
+// snapshot, deletes the objects that were created, and restores the
+// inventory. The objects are rolled back in reverse order, so that objects
+// are rolled back before their dependencies. Objects that failed to apply,
+// or were skipped, are left unchanged. Returns the errors of the objects
+// that could not be rolled back.
+func (a *Applier) rollback(ctx context.Context, taskContext *taskrunner.TaskContext,
+	snapshot *rollbackSnapshot, invInfo inventory.Info) []error {
+	im := taskContext.InventoryManager()
+	invIDs := snapshot.invIDs
+	var errs []error
+	for i := len(snapshot.ids) - 1; i >= 0; i-- {
+		id := snapshot.ids[i]
+		if !im.IsSuccessfulApply(id) {
+			continue
+		}
+		var status event.RollbackEventStatus
+		var err error
+		if prev, found := snapshot.objs[id]; found {
+			klog.V(4).Infof("rollback restoring object (object: %s)", id)
+			status = event.RollbackRestored
+			err = a.restoreObject(ctx, id, prev)
+		} else {
+			klog.V(4).Infof("rollback deleting object (object: %s)", id)
+			status = event.RollbackDeleted
+			uid, _ := im.AppliedResourceUID(id)
+			err = a.deleteCreatedObject(ctx, id, uid)
+			if err != nil {
+				// Keep track of the object, to prune it later.
+				invIDs = invIDs.Union(object.ObjMetadataSet{id})
+			}
+		}
+		if err != nil {
+			if klog.V(4).Enabled() {
+				// only log event emitted errors if the verbosity > 4
+				klog.Errorf("rollback errored (object: %s): %v", id, err)
+			}
+			status = event.RollbackFailed
+			errs = append(errs, fmt.Errorf("failed to roll back %s: %w", id, err))
+		}
+		taskContext.SendEvent(event.Event{
+			Type: event.RollbackType,
+			RollbackEvent: event.RollbackEvent{
+				Identifier: id,
+				Status:     status,
+				Error:      err,
+			},
+		})
+	}
+	klog.V(4).Infof("rollback restoring inventory (objects: %d)", len(invIDs))
+	invStatus := rollbackStatus(snapshot, im, invIDs)
+	if err := a.invClient.Replace(invInfo, invIDs, invStatus, common.DryRunNone); err != nil {
+		errs = append(errs, fmt.Errorf("failed to roll back inventory: %w", err))
+	}
+	return errs
+}
+
+// rollbackStatus returns the status of the objects to store in the restored
+// inventory: the status recorded in the snapshot, or the status of this run
+// for the created objects that could not be deleted.
+func rollbackStatus(snapshot *rollbackSnapshot, im *inventory.Manager, invIDs object.ObjMetadataSet) []actuation.ObjectStatus {
+	recorded := make(map[object.ObjMetadata]actuation.ObjectStatus, len(snapshot.invStatus))
+	for _, status := range snapshot.invStatus {
+		recorded[inventory.ObjMetadataFromObjectReference(status.ObjectReference)] = status
+	}
+	var invStatus []actuation.ObjectStatus
+	for _, id := range invIDs {
+		if status, found := recorded[id]; found {
+			invStatus = append(invStatus, status)
+		} else if status, found := im.ObjectStatus(id); found {
+			invStatus = append(invStatus, *status)
+		}
+	}
+	return invStatus
+}
+
+// restoreObject replaces the live object with its previous state, or
+// re-creates it if it was deleted.
+func (a *Applier) restoreObject(ctx context.Context, id object.ObjMetadata, prev *unstructured.Unstructured) error {
+	client, err := a.resourceClient(id)
+	if err != nil {
+		return err
+	}
+	return backup.RestoreObject(ctx, client, prev)
+}
+
+// deleteCreatedObject deletes an object created by the failed apply, unless
+// it has since been deleted and re-created.
+func (a *Applier) deleteCreatedObject(ctx context.Context, id object.ObjMetadata, uid types.UID) error {
+	client, err := a.resourceClient(id)
+	if err != nil {
+		return err
+	}
+	opts := metav1.DeleteOptions{}
+	if uid != "" {
+		opts.Preconditions = &metav1.Preconditions{UID: &uid}
+	}
+	err = client.Delete(ctx, id.Name, opts)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// resourceClient returns a dynamic client for the resource of the object.
+func (a *Applier) resourceClient(id object.ObjMetadata) (dynamic.ResourceInterface, error) {
+	mapping, err := a.mapper.RESTMapping(id.GroupKind)
+	if err != nil {
+		return nil, err
+	}
+	if mapping.Scope.Name() == meta.RESTScopeNameRoot {
+		return a.client.Resource(mapping.Resource), nil
+	}
+	return a.client.Resource(mapping.Resource).Namespace(id.Namespace), nil
+}
@@ -0,0 +1,154 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package apply
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/kubectl/pkg/scheme"
+	"sigs.k8s.io/cli-utils/pkg/apis/actuation"
+	"sigs.k8s.io/cli-utils/pkg/apply/backup"
+	"sigs.k8s.io/cli-utils/pkg/apply/cache"
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
+	"sigs.k8s.io/cli-utils/pkg/apply/taskrunner"
+	"sigs.k8s.io/cli-utils/pkg/inventory"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/cli-utils/pkg/testutil"
+)
+
+func TestApplierRollback(t *testing.T) {
+	// The deployment existed before the apply, the secret was created by
+	// the apply and the pod failed to apply.
+	deployment := testutil.Unstructured(t, resources["deployment"])
+	secret := testutil.Unstructured(t, resources["secret"])
+	pod := testutil.Unstructured(t, resources["obj1"])
+	deploymentID := object.UnstructuredToObjMetadata(deployment)
+	secretID := object.UnstructuredToObjMetadata(secret)
+	podID := object.UnstructuredToObjMetadata(pod)
+	prevInvIDs := object.ObjMetadataSet{deploymentID}
+
+	prevDeployment := deployment.DeepCopy()
+	prevDeployment.SetLabels(map[string]string{"version": "previous"})
+	prevDeployment.SetResourceVersion("1")
+	appliedDeployment := deployment.DeepCopy()
+	appliedDeployment.SetResourceVersion("2")
+
+	client := dynamicfake.NewSimpleDynamicClient(scheme.Scheme, prevDeployment)
+	invClient := inventory.NewFakeClient(prevInvIDs)
+	prevInvStatus := []actuation.ObjectStatus{
+		{
+			ObjectReference: inventory.ObjectReferenceFromObjMetadata(deploymentID),
+			Strategy:        actuation.ActuationStrategyApply,
+			Actuation:       actuation.ActuationSucceeded,
+			Reconcile:       actuation.ReconcileSucceeded,
+			UID:             "deployment-uid",
+		},
+	}
+	invClient.Status = prevInvStatus
+	applier := &Applier{
+		invClient: invClient,
+		client:    client,
+		mapper: testutil.NewFakeRESTMapper(
+			schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+			schema.GroupVersionKind{Version: "v1", Kind: "Secret"},
+			schema.GroupVersionKind{Version: "v1", Kind: "Pod"},
+		),
+	}
+	invInfo := inventoryInfo{name: "inv", namespace: "default", id: "test"}.toWrapped()
+
+	snapshot, err := applier.takeRollbackSnapshot(context.TODO(), invInfo,
+		object.UnstructuredSet{deployment, secret, pod})
+	require.NoError(t, err)
+	// Secrets are applied before deployments.
+	assert.Equal(t, object.ObjMetadataSet{secretID, deploymentID, podID}, snapshot.ids)
+	assert.Equal(t, prevInvIDs, snapshot.invIDs)
+	assert.Equal(t, prevInvStatus, snapshot.invStatus)
+	assert.Len(t, snapshot.objs, 1)
+
+	// Apply the objects.
+	deploymentsGVR := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	_, err = client.Resource(deploymentsGVR).Namespace(deploymentID.Namespace).
+		Update(context.TODO(), appliedDeployment, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	secretsGVR := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	_, err = client.Resource(secretsGVR).Namespace(secretID.Namespace).
+		Create(context.TODO(), secret, metav1.CreateOptions{})
+	require.NoError(t, err)
+	invClient.Objs = object.ObjMetadataSet{deploymentID, secretID, podID}
+	invClient.Status = nil
+
+	eventChannel := make(chan event.Event, 3)
+	taskContext := taskrunner.NewTaskContext(eventChannel, cache.NewResourceCacheMap())
+	taskContext.InventoryManager().AddSuccessfulApply(deploymentID, "", 1)
+	taskContext.InventoryManager().AddSuccessfulApply(secretID, "", 1)
+	taskContext.InventoryManager().AddFailedApply(podID)
+
+	errs := applier.rollback(context.TODO(), taskContext, snapshot, invInfo)
+	assert.Empty(t, errs)
+	close(eventChannel)
+
+	var events []event.RollbackEvent
+	for e := range eventChannel {
+		require.Equal(t, event.RollbackType, e.Type)
+		events = append(events, e.RollbackEvent)
+	}
+	assert.Equal(t, []event.RollbackEvent{
+		{Identifier: deploymentID, Status: event.RollbackRestored},
+		{Identifier: secretID, Status: event.RollbackDeleted},
+	}, events)
+
+	live, err := client.Resource(deploymentsGVR).Namespace(deploymentID.Namespace).
+		Get(context.TODO(), deploymentID.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"version": "previous"}, live.GetLabels())
+	_, err = client.Resource(secretsGVR).Namespace(secretID.Namespace).
+		Get(context.TODO(), secretID.Name, metav1.GetOptions{})
+	assert.Error(t, err)
+	assert.Equal(t, prevInvIDs, invClient.Objs)
+	assert.Equal(t, prevInvStatus, invClient.Status)
+}
+
+func TestApplierBackupObjects(t *testing.T) {
+	// The deployment exists, the secret does not, and the pod is pruned.
+	deployment := testutil.Unstructured(t, resources["deployment"])
+	secret := testutil.Unstructured(t, resources["secret"])
+	pod := testutil.Unstructured(t, resources["obj1"])
+
+	liveDeployment := deployment.DeepCopy()
+	liveDeployment.SetLabels(map[string]string{"version": "previous"})
+	liveDeployment.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: "kubectl"}})
+	livePod := pod.DeepCopy()
+	livePod.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: "kubectl"}})
+
+	applier := &Applier{
+		client: dynamicfake.NewSimpleDynamicClient(scheme.Scheme, liveDeployment),
+		mapper: testutil.NewFakeRESTMapper(
+			schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+			schema.GroupVersionKind{Version: "v1", Kind: "Secret"},
+			schema.GroupVersionKind{Version: "v1", Kind: "Pod"},
+		),
+	}
+	store := &backup.DirectoryStore{Dir: t.TempDir()}
+
+	err := applier.backupObjects(context.TODO(), store, "run-1",
+		object.UnstructuredSet{deployment, secret}, object.UnstructuredSet{livePod}, nil)
+	require.NoError(t, err)
+
+	objs, err := store.Load(context.TODO(), "run-1")
+	require.NoError(t, err)
+	require.Len(t, objs, 2)
+	assert.Equal(t, object.UnstructuredToObjMetadata(deployment), object.UnstructuredToObjMetadata(objs[0]))
+	assert.Equal(t, "previous", objs[0].GetLabels()["version"])
+	assert.Empty(t, objs[0].GetManagedFields())
+	assert.Equal(t, object.UnstructuredToObjMetadata(pod), object.UnstructuredToObjMetadata(objs[1]))
+	assert.Empty(t, objs[1].GetManagedFields())
+	// The live objects are not modified.
+	assert.NotEmpty(t, livePod.GetManagedFields())
+}
@@ -0,0 +1,102 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package solver
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/cli-utils/pkg/common"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/cli-utils/pkg/object/graph"
+	"sigs.k8s.io/cli-utils/pkg/object/mutation"
+)
+
+// hookOrder is the order in which the apply hook phases are applied.
+var hookOrder = map[common.ApplyHook]int{
+	common.ApplyHookPreApply:      0,
+	common.ApplyHookNone:          1,
+	common.ApplyHookPostApply:     2,
+	common.ApplyHookPostReconcile: 3,
+	common.ApplyHookTest:          4,
+}
+
+// HookDependencyError represents a dependency on an object that is applied,
+// or reconciled, in a later apply hook phase.
+type HookDependencyError struct {
+	Edge           graph.Edge
+	Hook           common.ApplyHook
+	DependencyHook common.ApplyHook
+}
+
+func (e HookDependencyError) Error() string {
+	return fmt.Sprintf("dependency from a later apply hook phase: %s (%s) -> %s (%s)",
+		mutation.ResourceReferenceFromObjMetadata(e.Edge.From), hookName(e.Hook),
+		mutation.ResourceReferenceFromObjMetadata(e.Edge.To), hookName(e.DependencyHook))
+}
+
+func hookName(hook common.ApplyHook) string {
+	if hook == common.ApplyHookNone {
+		return "no hook"
+	}
+	return string(hook)
+}
+
+// splitByHook splits the objects into apply hook phases. Objects with an
+// invalid apply hook annotation are ignored.
+func splitByHook(objs object.UnstructuredSet) map[common.ApplyHook]object.UnstructuredSet {
+	hookObjs := make(map[common.ApplyHook]object.UnstructuredSet)
+	for _, obj := range objs {
+		hook, err := common.GetApplyHook(obj)
+		if err != nil {
+			continue
+		}
+		hookObjs[hook] = append(hookObjs[hook], obj)
+	}
+	return hookObjs
+}
+
+// validateHookDependencies returns an error for each object to apply that
+// depends on an object that is not reconciled before it is applied: an
+// object of a later apply hook phase, or, for post-apply hooks, an object
+// applied by the last apply task of the other objects.
+func validateHookDependencies(g *graph.Graph, idSetList []object.ObjMetadataSet,
+	applyObjs object.UnstructuredSet) []HookDependencyError {
+	hookObjs := splitByHook(applyObjs)
+	hooks := make(map[object.ObjMetadata]common.ApplyHook)
+	for hook, objs := range hookObjs {
+		for _, obj := range objs {
+			hooks[object.UnstructuredToObjMetadata(obj)] = hook
+		}
+	}
+	var lastApplySet object.ObjMetadataSet
+	if applySets := graph.HydrateSetList(idSetList, hookObjs[common.ApplyHookNone]); len(applySets) > 0 {
+		lastApplySet = object.UnstructuredSetToObjMetadataSet(applySets[len(applySets)-1])
+	}
+
+	var errs []HookDependencyError
+	for _, obj := range applyObjs {
+		id := object.UnstructuredToObjMetadata(obj)
+		hook, found := hooks[id]
+		if !found {
+			continue
+		}
+		for _, dep := range g.Dependencies(id) {
+			depHook, found := hooks[dep]
+			if !found {
+				// Not applied
+				continue
+			}
+			premature := hookOrder[depHook] > hookOrder[hook] ||
+				(hook == common.ApplyHookPostApply && lastApplySet.Contains(dep))
+			if premature {
+				errs = append(errs, HookDependencyError{
+					Edge:           graph.Edge{From: id, To: dep},
+					Hook:           hook,
+					DependencyHook: depHook,
+				})
+			}
+		}
+	}
+	return errs
+}
@@ -20,6 +20,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/klog/v2"
@@ -28,13 +29,16 @@ import (
 	"sigs.k8s.io/cli-utils/pkg/apply/info"
 	"sigs.k8s.io/cli-utils/pkg/apply/mutator"
 	"sigs.k8s.io/cli-utils/pkg/apply/prune"
+	"sigs.k8s.io/cli-utils/pkg/apply/readiness"
 	"sigs.k8s.io/cli-utils/pkg/apply/task"
 	"sigs.k8s.io/cli-utils/pkg/apply/taskrunner"
+	"sigs.k8s.io/cli-utils/pkg/backoff"
 	"sigs.k8s.io/cli-utils/pkg/common"
 	"sigs.k8s.io/cli-utils/pkg/inventory"
 	"sigs.k8s.io/cli-utils/pkg/object"
 	"sigs.k8s.io/cli-utils/pkg/object/graph"
 	"sigs.k8s.io/cli-utils/pkg/object/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type TaskQueueBuilder struct {
@@ -50,21 +54,47 @@ type TaskQueueBuilder struct {
 	ApplyFilters  []filter.ValidationFilter
 	ApplyMutators []mutator.Interface
 	PruneFilters  []filter.ValidationFilter
+	// TypedClient applies the objects of TypedKinds as typed objects.
+	TypedClient client.Client
+	TypedKinds  []schema.GroupKind
 
 	// The accumulated tasks and counter variables to name tasks.
 	applyCounter int
 	pruneCounter int
 	waitCounter  int
 
-	invInfo   inventory.Info
-	applyObjs object.UnstructuredSet
-	pruneObjs object.UnstructuredSet
+	invInfo      inventory.Info
+	applyObjs    object.UnstructuredSet
+	pruneObjs    object.UnstructuredSet
+	deferredObjs object.UnstructuredSet
+
+	// reconcileTimeouts are the reconcile timeouts of the apply objects
+	// that override the ReconcileTimeout.
+	reconcileTimeouts map[object.ObjMetadata]time.Duration
+	// crdKinds are the kinds defined by the CRDs of the apply objects.
+	crdKinds map[schema.GroupKind]struct{}
 }
 
+// customResourceKindTimeout is how long the apply tasks wait for the kinds
+// defined by the CRDs applied in the same run to be served. The discovery of
+// the API server can lag behind the Established condition of the CRDs.
+const customResourceKindTimeout = 30 * time.Second
+
 type TaskQueue struct {
 	tasks []taskrunner.Task
 }
 
+// NewTaskQueue returns a queue of the tasks, to run a custom pipeline of
+// tasks with a taskrunner.TaskStatusRunner.
+func NewTaskQueue(tasks ...taskrunner.Task) *TaskQueue {
+	return &TaskQueue{tasks: tasks}
+}
+
+// Tasks returns the tasks of the queue, in order.
+func (tq *TaskQueue) Tasks() []taskrunner.Task {
+	return tq.tasks
+}
+
 func (tq *TaskQueue) ToChannel() chan taskrunner.Task {
 	taskQueue := make(chan taskrunner.Task, len(tq.tasks))
 	for _, t := range tq.tasks {
@@ -89,15 +119,65 @@ func (tq *TaskQueue) ToActionGroups() []event.ActionGroup {
 type Options struct {
 	ServerSideOptions common.ServerSideOptions
 	ReconcileTimeout  time.Duration
+	// True if the objects that are Suspended, e.g. paused Deployments, are
+	// considered reconciled.
+	ReconcileSuspended bool
+	// True if the run stops after a wait task in which objects failed to
+	// reconcile or timed out.
+	StopOnReconcileFailure bool
+	// Checks that applied objects must pass, in addition to being Current,
+	// before they are considered reconciled.
+	ReadinessGates []readiness.Gate
 	// True if we are destroying, which deletes the inventory object
 	// as well (possibly) the inventory namespace.
 	Destroy bool
+	// True if the inventory object should be retained when destroying.
+	KeepInventory bool
 	// True if we're deleting prune objects
 	Prune                  bool
 	DryRunStrategy         common.DryRunStrategy
 	PrunePropagationPolicy metav1.DeletionPropagation
 	PruneTimeout           time.Duration
 	InventoryPolicy        inventory.Policy
+	// Minimum number of objects of the same GroupKind and namespace that are
+	// deleted with a single DeleteCollection request.
+	PruneDeleteCollectionThreshold int
+	// True if the dependents of the pruned objects should be listed and
+	// reported on their prune events.
+	PruneListDependents bool
+	// Kinds of the listed dependents, or nil for the default kinds.
+	PruneDependentKinds []schema.GroupKind
+	// Grace period of the prune deletions, or nil for the default of each
+	// object.
+	PruneGracePeriodSeconds *int64
+	// True if the deletion of the objects of the last prune phase should not
+	// be waited on. Earlier prune phases are still waited on, so that
+	// dependents are deleted before their dependencies.
+	PruneSkipWait bool
+	// How long to wait for the pruned objects to be deleted, before
+	// reporting the objects that are still terminating with their remaining
+	// finalizers. Zero disables the report.
+	PruneStuckDeletionThreshold time.Duration
+	// True if the finalizers of the pruned objects that are still
+	// terminating after the PruneStuckDeletionThreshold should be removed.
+	PruneRemoveStuckFinalizers bool
+	// Maximum number of objects applied in parallel by each apply task.
+	ApplyConcurrency int
+	// Policy used to retry the apply of objects that failed with a
+	// transient error.
+	ApplyRetryPolicy backoff.RetryPolicy
+	// True if objects should be deleted and re-created when an update
+	// changes an immutable field.
+	RecreateOnImmutableFieldChange bool
+	// True if the apply of objects whose applied hash is unchanged should
+	// be skipped.
+	ApplySkipUnchanged bool
+	// True if objects skipped because they are unchanged should not be
+	// waited on, if they are already reconciled.
+	ApplySkipUnchangedWait bool
+	// Maximum time to wait for the kinds of the deferred objects to be
+	// served, before they are applied.
+	UnknownKindsTimeout time.Duration
 }
 
 // WithInventory sets the inventory info and returns the builder for chaining.
@@ -118,6 +198,15 @@ func (t *TaskQueueBuilder) WithPruneObjects(pruneObjs object.UnstructuredSet) *T
 	return t
 }
 
+// WithDeferredObjects sets the objects whose kind is not yet known, and
+// returns the builder for chaining. They are applied after the other objects
+// have been applied and pruned, once their kinds are served, and are not
+// waited on.
+func (t *TaskQueueBuilder) WithDeferredObjects(deferredObjs object.UnstructuredSet) *TaskQueueBuilder {
+	t.deferredObjs = deferredObjs
+	return t
+}
+
 // Build returns the queue of tasks that have been created
 func (t *TaskQueueBuilder) Build(taskContext *taskrunner.TaskContext, o Options) *TaskQueue {
 	var tasks []taskrunner.Task
@@ -130,13 +219,92 @@ func (t *TaskQueueBuilder) Build(taskContext *taskrunner.TaskContext, o Options)
 	// Filter objects that failed earlier validation
 	applyObjs := t.Collector.FilterInvalidObjects(t.applyObjs)
 	pruneObjs := t.Collector.FilterInvalidObjects(t.pruneObjs)
+	deferredObjs := t.Collector.FilterInvalidObjects(t.deferredObjs)
+
+	// Objects with an unsupported apply strategy or apply hook, an invalid
+	// apply wave, recreate or reconcile timeout annotation are invalid.
+	waves := make(map[object.ObjMetadata]int)
+	t.reconcileTimeouts = make(map[object.ObjMetadata]time.Duration)
+	t.crdKinds = make(map[schema.GroupKind]struct{})
+	for _, obj := range applyObjs {
+		if !object.IsCRD(obj) {
+			continue
+		}
+		if gk, found := object.GetCRDGroupKind(obj); found {
+			t.crdKinds[gk] = struct{}{}
+		}
+	}
+	for _, obj := range append(applyObjs[:len(applyObjs):len(applyObjs)], deferredObjs...) {
+		id := object.UnstructuredToObjMetadata(obj)
+		if _, err := common.GetApplyStrategy(obj); err != nil {
+			t.Collector.Collect(validation.NewError(
+				object.InvalidAnnotationError{
+					Annotation: common.ApplyStrategyAnnotation,
+					Cause:      err,
+				},
+				id,
+			))
+		}
+		if _, err := common.GetApplyHook(obj); err != nil {
+			t.Collector.Collect(validation.NewError(
+				object.InvalidAnnotationError{
+					Annotation: common.ApplyHookAnnotation,
+					Cause:      err,
+				},
+				id,
+			))
+		}
+		if _, err := common.GetRecreate(obj); err != nil {
+			t.Collector.Collect(validation.NewError(
+				object.InvalidAnnotationError{
+					Annotation: common.RecreateAnnotation,
+					Cause:      err,
+				},
+				id,
+			))
+		}
+		timeout, err := common.GetReconcileTimeout(obj)
+		if err != nil {
+			t.Collector.Collect(validation.NewError(
+				object.InvalidAnnotationError{
+					Annotation: common.ReconcileTimeoutAnnotation,
+					Cause:      err,
+				},
+				id,
+			))
+		} else if timeout != 0 {
+			t.reconcileTimeouts[id] = timeout
+		}
+		wave, err := common.GetApplyWave(obj)
+		if err != nil {
+			t.Collector.Collect(validation.NewError(
+				object.InvalidAnnotationError{
+					Annotation: common.ApplyWaveAnnotation,
+					Cause:      err,
+				},
+				id,
+			))
+		} else if wave != 0 {
+			waves[id] = wave
+		}
+	}
+	// Objects to prune are deleted in reverse wave order. An invalid wave
+	// must not prevent deletion, so it is treated as wave zero.
+	for _, obj := range pruneObjs {
+		if wave, err := common.GetApplyWave(obj); err == nil && wave != 0 {
+			waves[object.UnstructuredToObjMetadata(obj)] = wave
+		}
+	}
 
 	// Merge applyObjs & pruneObjs and graph them together.
 	// This detects implicit and explicit dependencies.
 	// Invalid dependency annotations will be treated as validation errors.
-	allObjs := make(object.UnstructuredSet, 0, len(applyObjs)+len(pruneObjs))
+	// Deferred objects are graphed too, so that their dependencies are
+	// checked before they are applied.
+	allObjs := make(object.UnstructuredSet, 0, len(applyObjs)+len(pruneObjs)+len(deferredObjs))
 	allObjs = append(allObjs, applyObjs...)
 	allObjs = append(allObjs, pruneObjs...)
+	allObjs = append(allObjs, deferredObjs...)
 	g, err := graph.DependencyGraph(allObjs)
 	if err != nil {
 		t.Collector.Collect(err)
@@ -149,44 +317,71 @@ func (t *TaskQueueBuilder) Build(taskContext *taskrunner.TaskContext, o Options)
 	if err != nil {
 		t.Collector.Collect(err)
 	}
+	// Split the phases into apply waves.
+	idSetList = graph.SortByWave(g, idSetList, waves)
+	// Objects must not depend on objects of later apply hook phases.
+	for _, err := range validateHookDependencies(g, idSetList, applyObjs) {
+		t.Collector.Collect(validation.NewError(err, err.Edge.From))
+	}
 
-	// Filter objects with cycles or invalid dependency annotations
+	// Filter objects with cycles, invalid dependency annotations,
+	// dependencies on later apply hook phases or unsupported apply strategies
 	applyObjs = t.Collector.FilterInvalidObjects(applyObjs)
 	pruneObjs = t.Collector.FilterInvalidObjects(pruneObjs)
+	deferredObjs = t.Collector.FilterInvalidObjects(deferredObjs)
 
 	if !o.Destroy {
 		// InvAddTask creates the inventory and adds any objects being applied
-		klog.V(2).Infof("adding inventory add task (%d objects)", len(applyObjs))
+		invObjs := append(applyObjs[:len(applyObjs):len(applyObjs)], deferredObjs...)
+		klog.V(2).Infof("adding inventory add task (%d objects)", len(invObjs))
 		tasks = append(tasks, &task.InvAddTask{
 			TaskName:      "inventory-add-0",
 			InvClient:     t.InvClient,
 			DynamicClient: t.DynamicClient,
 			Mapper:        t.Mapper,
 			InvInfo:       t.invInfo,
-			Objects:       applyObjs,
+			Objects:       invObjs,
 			DryRun:        o.DryRunStrategy,
 		})
 	}
 
+	var testObjs object.UnstructuredSet
 	if len(applyObjs) > 0 {
 		// Register actuation plan in the inventory
 		for _, id := range object.UnstructuredSetToObjMetadataSet(applyObjs) {
 			taskContext.InventoryManager().AddPendingApply(id)
 		}
 
-		// Filter idSetList down to just apply objects
-		applySets := graph.HydrateSetList(idSetList, applyObjs)
+		// Split the apply objects into apply hook phases.
+		hookObjs := splitByHook(applyObjs)
 
-		for _, applySet := range applySets {
+		// Filter idSetList down to the apply objects of each phase
+		preApplySets := graph.HydrateSetList(idSetList, hookObjs[common.ApplyHookPreApply])
+		applySets := graph.HydrateSetList(idSetList, hookObjs[common.ApplyHookNone])
+		postApplySets := graph.HydrateSetList(idSetList, hookObjs[common.ApplyHookPostApply])
+		postReconcileSets := graph.HydrateSetList(idSetList, hookObjs[common.ApplyHookPostReconcile])
+		testObjs = hookObjs[common.ApplyHookTest]
+
+		tasks = append(tasks, t.newApplyAndWaitTasks(preApplySets, o)...)
+		if len(applySets) == 0 {
+			tasks = append(tasks, t.newApplyAndWaitTasks(postApplySets, o)...)
+		}
+		for i, applySet := range applySets {
 			tasks = append(tasks,
 				t.newApplyTask(applySet, t.ApplyFilters, t.ApplyMutators, o))
+			// Post-apply hooks are applied after the last apply task,
+			// before waiting for its objects to reconcile.
+			if i == len(applySets)-1 {
+				tasks = append(tasks, t.newApplyAndWaitTasks(postApplySets, o)...)
+			}
 			// dry-run skips wait tasks
 			if !o.DryRunStrategy.ClientOrServerDryRun() {
 				applyIDs := object.UnstructuredSetToObjMetadataSet(applySet)
 				tasks = append(tasks,
-					t.newWaitTask(applyIDs, taskrunner.AllCurrent, o.ReconcileTimeout))
+					t.newApplyWaitTask(applyIDs, o))
 			}
 		}
+		tasks = append(tasks, t.newApplyAndWaitTasks(postReconcileSets, o)...)
 	}
 
 	if o.Prune && len(pruneObjs) > 0 {
@@ -201,15 +396,54 @@ func (t *TaskQueueBuilder) Build(taskContext *taskrunner.TaskContext, o Options)
 		// Reverse apply order to get prune order
 		graph.ReverseSetList(pruneSets)
 
-		for _, pruneSet := range pruneSets {
+		for i, pruneSet := range pruneSets {
 			tasks = append(tasks,
 				t.newPruneTask(pruneSet, t.PruneFilters, o))
+			if o.PruneSkipWait && i == len(pruneSets)-1 {
+				klog.V(2).Infof("skipping wait for deletion (%d objects)", len(pruneSet))
+				continue
+			}
 			// dry-run skips wait tasks
 			if !o.DryRunStrategy.ClientOrServerDryRun() {
 				pruneIDs := object.UnstructuredSetToObjMetadataSet(pruneSet)
-				tasks = append(tasks,
-					t.newWaitTask(pruneIDs, taskrunner.AllNotFound, o.PruneTimeout))
+				tasks = append(tasks, t.newDeleteWaitTask(pruneIDs, o))
+			}
+		}
+	}
+
+	if len(deferredObjs) > 0 {
+		// Register actuation plan in the inventory
+		for _, id := range object.UnstructuredSetToObjMetadataSet(deferredObjs) {
+			taskContext.InventoryManager().AddPendingApply(id)
+		}
+
+		// The objects whose kind was not known are applied last, once
+		// their kinds are served. They can not be waited on, because the
+		// status watcher does not watch kinds added during the run.
+		applyTask := t.newApplyTask(deferredObjs, t.ApplyFilters, t.ApplyMutators, o).(*task.ApplyTask)
+		applyTask.RefreshKinds = true
+		applyTask.KindTimeout = o.UnknownKindsTimeout
+		tasks = append(tasks, applyTask)
+	}
+
+	// Test resources are applied after all the other resources, and deleted
+	// once they have completed, whether they succeeded or not.
+	if len(testObjs) > 0 {
+		testSets := graph.HydrateSetList(idSetList, testObjs)
+		tasks = append(tasks, t.newApplyAndWaitTasks(testSets, o)...)
+		// dry-run does not create the test resources
+		if !o.DryRunStrategy.ClientOrServerDryRun() {
+			// Test resources are deleted in reverse apply order.
+			cleanupSets := graph.HydrateSetList(idSetList, testObjs)
+			graph.ReverseSetList(cleanupSets)
+			var cleanupObjs object.UnstructuredSet
+			for _, cleanupSet := range cleanupSets {
+				cleanupObjs = append(cleanupObjs, cleanupSet...)
 			}
+			testIDs := object.UnstructuredSetToObjMetadataSet(testObjs)
+			tasks = append(tasks,
+				t.newTestCleanupTask(cleanupObjs, o),
+				t.newDeleteWaitTask(testIDs, o))
 		}
 	}
 
@@ -228,33 +462,73 @@ func (t *TaskQueueBuilder) Build(taskContext *taskrunner.TaskContext, o Options)
 		PrevInventory: prevInvIDs,
 		DryRun:        o.DryRunStrategy,
 		Destroy:       o.Destroy,
+		KeepInventory: o.KeepInventory,
 	})
 
 	return &TaskQueue{tasks: tasks}
 }
 
-// AppendApplyTask appends a task to the task queue to apply the passed objects
-// to the cluster. Returns a pointer to the Builder to chain function calls.
+// newApplyAndWaitTasks returns an apply task for each set of objects,
+// followed by a wait task for the objects to reconcile, unless dry-run.
+func (t *TaskQueueBuilder) newApplyAndWaitTasks(applySets []object.UnstructuredSet, o Options) []taskrunner.Task {
+	var tasks []taskrunner.Task
+	for _, applySet := range applySets {
+		tasks = append(tasks,
+			t.newApplyTask(applySet, t.ApplyFilters, t.ApplyMutators, o))
+		// dry-run skips wait tasks
+		if !o.DryRunStrategy.ClientOrServerDryRun() {
+			applyIDs := object.UnstructuredSetToObjMetadataSet(applySet)
+			tasks = append(tasks,
+				t.newApplyWaitTask(applyIDs, o))
+		}
+	}
+	return tasks
+}
+
 func (t *TaskQueueBuilder) newApplyTask(applyObjs object.UnstructuredSet,
 	applyFilters []filter.ValidationFilter, applyMutators []mutator.Interface, o Options) taskrunner.Task {
 	applyObjs = t.Collector.FilterInvalidObjects(applyObjs)
 	klog.V(2).Infof("adding apply task (%d objects)", len(applyObjs))
 	task := &task.ApplyTask{
-		TaskName:          fmt.Sprintf("apply-%d", t.applyCounter),
-		Objects:           applyObjs,
-		Filters:           applyFilters,
-		Mutators:          applyMutators,
-		ServerSideOptions: o.ServerSideOptions,
-		DryRunStrategy:    o.DryRunStrategy,
-		DynamicClient:     t.DynamicClient,
-		OpenAPIGetter:     t.OpenAPIGetter,
-		InfoHelper:        t.InfoHelper,
-		Mapper:            t.Mapper,
+		TaskName:                       fmt.Sprintf("apply-%d", t.applyCounter),
+		Objects:                        applyObjs,
+		Filters:                        applyFilters,
+		Mutators:                       applyMutators,
+		ServerSideOptions:              o.ServerSideOptions,
+		DryRunStrategy:                 o.DryRunStrategy,
+		DynamicClient:                  t.DynamicClient,
+		OpenAPIGetter:                  t.OpenAPIGetter,
+		InfoHelper:                     t.InfoHelper,
+		Mapper:                         t.Mapper,
+		Concurrency:                    o.ApplyConcurrency,
+		RetryPolicy:                    o.ApplyRetryPolicy,
+		RecreateOnImmutableFieldChange: o.RecreateOnImmutableFieldChange,
+		SkipUnchanged:                  o.ApplySkipUnchanged,
+		SkipUnchangedWait:              o.ApplySkipUnchangedWait,
+		TypedClient:                    t.TypedClient,
+		TypedKinds:                     t.TypedKinds,
+	}
+	// Custom resources of the CRDs applied in the same run are applied once
+	// their kinds are served. Dry-runs do not create the CRDs.
+	if !o.DryRunStrategy.ClientOrServerDryRun() && t.hasCustomResources(applyObjs) {
+		task.RefreshKinds = true
+		task.KindTimeout = customResourceKindTimeout
 	}
 	t.applyCounter++
 	return task
 }
 
+// hasCustomResources returns true if any of the objects is a custom resource
+// of a CRD applied in the same run.
+func (t *TaskQueueBuilder) hasCustomResources(objs object.UnstructuredSet) bool {
+	for _, obj := range objs {
+		if _, found := t.crdKinds[obj.GroupVersionKind().GroupKind()]; found {
+			return true
+		}
+	}
+	return false
+}
+
 // AppendWaitTask appends a task to wait on the passed objects to the task queue.
 // Returns a pointer to the Builder to chain function calls.
 func (t *TaskQueueBuilder) newWaitTask(waitIDs object.ObjMetadataSet, condition taskrunner.Condition,
@@ -268,10 +542,58 @@ func (t *TaskQueueBuilder) newWaitTask(waitIDs object.ObjMetadataSet, condition
 		waitTimeout,
 		t.Mapper,
 	)
+	if condition == taskrunner.AllCurrent {
+		for _, id := range waitIDs {
+			if timeout, found := t.reconcileTimeouts[id]; found {
+				if task.ObjectTimeouts == nil {
+					task.ObjectTimeouts = make(map[object.ObjMetadata]time.Duration)
+				}
+				task.ObjectTimeouts[id] = timeout
+			}
+		}
+	}
 	t.waitCounter++
 	return task
 }
 
+// newApplyWaitTask returns a task to wait for the passed objects to be
+// reconciled.
+func (t *TaskQueueBuilder) newApplyWaitTask(waitIDs object.ObjMetadataSet, o Options) taskrunner.Task {
+	task := t.newWaitTask(waitIDs, taskrunner.AllCurrent, o.ReconcileTimeout).(*taskrunner.WaitTask)
+	task.ReconcileSuspended = o.ReconcileSuspended
+	task.StopOnFailure = o.StopOnReconcileFailure
+	task.ReadinessGates = o.ReadinessGates
+	return task
+}
+
+// newDeleteWaitTask returns a task to wait for the passed objects to be
+// deleted, which reports the objects whose deletion is stuck.
+func (t *TaskQueueBuilder) newDeleteWaitTask(waitIDs object.ObjMetadataSet, o Options) taskrunner.Task {
+	task := t.newWaitTask(waitIDs, taskrunner.AllNotFound, o.PruneTimeout).(*taskrunner.WaitTask)
+	task.StuckDeletionThreshold = o.PruneStuckDeletionThreshold
+	if o.PruneRemoveStuckFinalizers && task.StuckDeletionThreshold > 0 {
+		task.FinalizerRemover = t.Pruner
+	}
+	return task
+}
+
+// newTestCleanupTask returns a task that deletes the test resources. Only
+// an on-remove annotation can prevent their deletion.
+func (t *TaskQueueBuilder) newTestCleanupTask(testObjs object.UnstructuredSet, o Options) taskrunner.Task {
+	klog.V(2).Infof("adding test cleanup task (%d objects)", len(testObjs))
+	task := &task.TestCleanupTask{
+		TaskName:           fmt.Sprintf("prune-%d", t.pruneCounter),
+		Objects:            testObjs,
+		Filters:            []filter.ValidationFilter{filter.PreventRemoveFilter{}},
+		Pruner:             t.Pruner,
+		PropagationPolicy:  o.PrunePropagationPolicy,
+		GracePeriodSeconds: o.PruneGracePeriodSeconds,
+		DryRunStrategy:     o.DryRunStrategy,
+	}
+	t.pruneCounter++
+	return task
+}
+
 // AppendPruneTask appends a task to delete objects from the cluster to the task queue.
 // Returns a pointer to the Builder to chain function calls.
 func (t *TaskQueueBuilder) newPruneTask(pruneObjs object.UnstructuredSet,
@@ -279,13 +601,18 @@ func (t *TaskQueueBuilder) newPruneTask(pruneObjs object.UnstructuredSet,
 	pruneObjs = t.Collector.FilterInvalidObjects(pruneObjs)
 	klog.V(2).Infof("adding prune task (%d objects)", len(pruneObjs))
 	task := &task.PruneTask{
-		TaskName:          fmt.Sprintf("prune-%d", t.pruneCounter),
-		Objects:           pruneObjs,
-		Filters:           pruneFilters,
-		Pruner:            t.Pruner,
-		PropagationPolicy: o.PrunePropagationPolicy,
-		DryRunStrategy:    o.DryRunStrategy,
-		Destroy:           o.Destroy,
+		TaskName:           fmt.Sprintf("prune-%d", t.pruneCounter),
+		Objects:            pruneObjs,
+		Filters:            pruneFilters,
+		Pruner:             t.Pruner,
+		PropagationPolicy:  o.PrunePropagationPolicy,
+		GracePeriodSeconds: o.PruneGracePeriodSeconds,
+		DryRunStrategy:     o.DryRunStrategy,
+		Destroy:            o.Destroy,
+
+		DeleteCollectionThreshold: o.PruneDeleteCollectionThreshold,
+		ListDependents:            o.PruneListDependents,
+		DependentKinds:            o.PruneDependentKinds,
 	}
 	t.pruneCounter++
 	return task
@@ -4,20 +4,28 @@
 package solver
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/cli-utils/pkg/apis/actuation"
+	"sigs.k8s.io/cli-utils/pkg/apply/cache"
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
+	"sigs.k8s.io/cli-utils/pkg/apply/filter"
 	"sigs.k8s.io/cli-utils/pkg/apply/prune"
 	"sigs.k8s.io/cli-utils/pkg/apply/task"
 	"sigs.k8s.io/cli-utils/pkg/apply/taskrunner"
 	"sigs.k8s.io/cli-utils/pkg/common"
 	"sigs.k8s.io/cli-utils/pkg/inventory"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/watcher"
 	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/cli-utils/pkg/object/dependson"
 	"sigs.k8s.io/cli-utils/pkg/object/graph"
 	"sigs.k8s.io/cli-utils/pkg/object/validation"
 	"sigs.k8s.io/cli-utils/pkg/testutil"
@@ -135,6 +143,7 @@ func TestTaskQueueBuilder_ApplyBuild(t *testing.T) {
 
 	testCases := map[string]struct {
 		applyObjs      []*unstructured.Unstructured
+		deferredObjs   []*unstructured.Unstructured
 		options        Options
 		expectedTasks  []taskrunner.Task
 		expectedError  error
@@ -483,6 +492,8 @@ func TestTaskQueueBuilder_ApplyBuild(t *testing.T) {
 						testutil.Unstructured(t, resources["crontab2"]),
 					},
 					DryRunStrategy: common.DryRunNone,
+					RefreshKinds:   true,
+					KindTimeout:    customResourceKindTimeout,
 				},
 				&taskrunner.WaitTask{
 					TaskName: "wait-1",
@@ -791,6 +802,594 @@ func TestTaskQueueBuilder_ApplyBuild(t *testing.T) {
 				testutil.ToIdentifier(t, resources["deployment"]),
 			),
 		},
+		"apply waves split the objects into phases": {
+			applyObjs: []*unstructured.Unstructured{
+				testutil.Unstructured(t, resources["deployment"]),
+				withAnnotation(testutil.Unstructured(t, resources["secret"]),
+					common.ApplyWaveAnnotation, "1"),
+			},
+			expectedTasks: []taskrunner.Task{
+				&task.InvAddTask{
+					TaskName:  "inventory-add-0",
+					InvClient: &inventory.FakeClient{},
+					InvInfo:   invInfo,
+					Objects: object.UnstructuredSet{
+						testutil.Unstructured(t, resources["deployment"]),
+						withAnnotation(testutil.Unstructured(t, resources["secret"]),
+							common.ApplyWaveAnnotation, "1"),
+					},
+				},
+				&task.ApplyTask{
+					TaskName: "apply-0",
+					Objects: []*unstructured.Unstructured{
+						testutil.Unstructured(t, resources["deployment"]),
+					},
+				},
+				&taskrunner.WaitTask{
+					TaskName: "wait-0",
+					IDs: object.ObjMetadataSet{
+						testutil.ToIdentifier(t, resources["deployment"]),
+					},
+					Condition: taskrunner.AllCurrent,
+				},
+				&task.ApplyTask{
+					TaskName: "apply-1",
+					Objects: []*unstructured.Unstructured{
+						withAnnotation(testutil.Unstructured(t, resources["secret"]),
+							common.ApplyWaveAnnotation, "1"),
+					},
+				},
+				&taskrunner.WaitTask{
+					TaskName: "wait-1",
+					IDs: object.ObjMetadataSet{
+						testutil.ToIdentifier(t, resources["secret"]),
+					},
+					Condition: taskrunner.AllCurrent,
+				},
+				&task.DeleteOrUpdateInvTask{
+					TaskName:  "inventory-set-0",
+					InvClient: &inventory.FakeClient{},
+					InvInfo:   invInfo,
+					PrevInventory: object.ObjMetadataSet{
+						testutil.ToIdentifier(t, resources["deployment"]),
+						testutil.ToIdentifier(t, resources["secret"]),
+					},
+				},
+			},
+			expectedStatus: []actuation.ObjectStatus{
+				{
+					ObjectReference: inventory.ObjectReferenceFromObjMetadata(
+						testutil.ToIdentifier(t, resources["deployment"]),
+					),
+					Strategy:  actuation.ActuationStrategyApply,
+					Actuation: actuation.ActuationPending,
+					Reconcile: actuation.ReconcilePending,
+				},
+				{
+					ObjectReference: inventory.ObjectReferenceFromObjMetadata(
+						testutil.ToIdentifier(t, resources["secret"]),
+					),
+					Strategy:  actuation.ActuationStrategyApply,
+					Actuation: actuation.ActuationPending,
+					Reconcile: actuation.ReconcilePending,
+				},
+			},
+		},
+		"apply hooks are applied in separate phases": {
+			applyObjs: []*unstructured.Unstructured{
+				testutil.Unstructured(t, resources["deployment"]),
+				withAnnotation(testutil.Unstructured(t, resources["pod"]),
+					common.ApplyHookAnnotation, "post-reconcile"),
+				withAnnotation(testutil.Unstructured(t, resources["default-pod"]),
+					common.ApplyHookAnnotation, "post-apply"),
+				withAnnotation(testutil.Unstructured(t, resources["secret"]),
+					common.ApplyHookAnnotation, "pre-apply"),
+			},
+			expectedTasks: []taskrunner.Task{
+				&task.InvAddTask{
+					TaskName:  "inventory-add-0",
+					InvClient: &inventory.FakeClient{},
+					InvInfo:   invInfo,
+					Objects: object.UnstructuredSet{
+						testutil.Unstructured(t, resources["deployment"]),
+						withAnnotation(testutil.Unstructured(t, resources["pod"]),
+							common.ApplyHookAnnotation, "post-reconcile"),
+						withAnnotation(testutil.Unstructured(t, resources["default-pod"]),
+							common.ApplyHookAnnotation, "post-apply"),
+						withAnnotation(testutil.Unstructured(t, resources["secret"]),
+							common.ApplyHookAnnotation, "pre-apply"),
+					},
+				},
+				&task.ApplyTask{
+					TaskName: "apply-0",
+					Objects: []*unstructured.Unstructured{
+						withAnnotation(testutil.Unstructured(t, resources["secret"]),
+							common.ApplyHookAnnotation, "pre-apply"),
+					},
+				},
+				&taskrunner.WaitTask{
+					TaskName: "wait-0",
+					IDs: object.ObjMetadataSet{
+						testutil.ToIdentifier(t, resources["secret"]),
+					},
+					Condition: taskrunner.AllCurrent,
+				},
+				&task.ApplyTask{
+					TaskName: "apply-1",
+					Objects: []*unstructured.Unstructured{
+						testutil.Unstructured(t, resources["deployment"]),
+					},
+				},
+				&task.ApplyTask{
+					TaskName: "apply-2",
+					Objects: []*unstructured.Unstructured{
+						withAnnotation(testutil.Unstructured(t, resources["default-pod"]),
+							common.ApplyHookAnnotation, "post-apply"),
+					},
+				},
+				&taskrunner.WaitTask{
+					TaskName: "wait-1",
+					IDs: object.ObjMetadataSet{
+						testutil.ToIdentifier(t, resources["default-pod"]),
+					},
+					Condition: taskrunner.AllCurrent,
+				},
+				&taskrunner.WaitTask{
+					TaskName: "wait-2",
+					IDs: object.ObjMetadataSet{
+						testutil.ToIdentifier(t, resources["deployment"]),
+					},
+					Condition: taskrunner.AllCurrent,
+				},
+				&task.ApplyTask{
+					TaskName: "apply-3",
+					Objects: []*unstructured.Unstructured{
+						withAnnotation(testutil.Unstructured(t, resources["pod"]),
+							common.ApplyHookAnnotation, "post-reconcile"),
+					},
+				},
+				&taskrunner.WaitTask{
+					TaskName: "wait-3",
+					IDs: object.ObjMetadataSet{
+						testutil.ToIdentifier(t, resources["pod"]),
+					},
+					Condition: taskrunner.AllCurrent,
+				},
+				&task.DeleteOrUpdateInvTask{
+					TaskName:  "inventory-set-0",
+					InvClient: &inventory.FakeClient{},
+					InvInfo:   invInfo,
+					PrevInventory: object.ObjMetadataSet{
+						testutil.ToIdentifier(t, resources["deployment"]),
+						testutil.ToIdentifier(t, resources["pod"]),
+						testutil.ToIdentifier(t, resources["default-pod"]),
+						testutil.ToIdentifier(t, resources["secret"]),
+					},
+				},
+			},
+			expectedStatus: []actuation.ObjectStatus{
+				{
+					ObjectReference: inventory.ObjectReferenceFromObjMetadata(
+						testutil.ToIdentifier(t, resources["deployment"]),
+					),
+					Strategy:  actuation.ActuationStrategyApply,
+					Actuation: actuation.ActuationPending,
+					Reconcile: actuation.ReconcilePending,
+				},
+				{
+					ObjectReference: inventory.ObjectReferenceFromObjMetadata(
+						testutil.ToIdentifier(t, resources["pod"]),
+					),
+					Strategy:  actuation.ActuationStrategyApply,
+					Actuation: actuation.ActuationPending,
+					Reconcile: actuation.ReconcilePending,
+				},
+				{
+					ObjectReference: inventory.ObjectReferenceFromObjMetadata(
+						testutil.ToIdentifier(t, resources["default-pod"]),
+					),
+					Strategy:  actuation.ActuationStrategyApply,
+					Actuation: actuation.ActuationPending,
+					Reconcile: actuation.ReconcilePending,
+				},
+				{
+					ObjectReference: inventory.ObjectReferenceFromObjMetadata(
+						testutil.ToIdentifier(t, resources["secret"]),
+					),
+					Strategy:  actuation.ActuationStrategyApply,
+					Actuation: actuation.ActuationPending,
+					Reconcile: actuation.ReconcilePending,
+				},
+			},
+		},
+		"test resources are applied last and deleted": {
+			applyObjs: []*unstructured.Unstructured{
+				withAnnotation(testutil.Unstructured(t, resources["pod"]),
+					common.ApplyHookAnnotation, "test"),
+				testutil.Unstructured(t, resources["deployment"]),
+			},
+			expectedTasks: []taskrunner.Task{
+				&task.InvAddTask{
+					TaskName:  "inventory-add-0",
+					InvClient: &inventory.FakeClient{},
+					InvInfo:   invInfo,
+					Objects: object.UnstructuredSet{
+						withAnnotation(testutil.Unstructured(t, resources["pod"]),
+							common.ApplyHookAnnotation, "test"),
+						testutil.Unstructured(t, resources["deployment"]),
+					},
+				},
+				&task.ApplyTask{
+					TaskName: "apply-0",
+					Objects: []*unstructured.Unstructured{
+						testutil.Unstructured(t, resources["deployment"]),
+					},
+				},
+				&taskrunner.WaitTask{
+					TaskName: "wait-0",
+					IDs: object.ObjMetadataSet{
+						testutil.ToIdentifier(t, resources["deployment"]),
+					},
+					Condition: taskrunner.AllCurrent,
+				},
+				&task.ApplyTask{
+					TaskName: "apply-1",
+					Objects: []*unstructured.Unstructured{
+						withAnnotation(testutil.Unstructured(t, resources["pod"]),
+							common.ApplyHookAnnotation, "test"),
+					},
+				},
+				&taskrunner.WaitTask{
+					TaskName: "wait-1",
+					IDs: object.ObjMetadataSet{
+						testutil.ToIdentifier(t, resources["pod"]),
+					},
+					Condition: taskrunner.AllCurrent,
+				},
+				&task.TestCleanupTask{
+					TaskName: "prune-0",
+					Objects: []*unstructured.Unstructured{
+						withAnnotation(testutil.Unstructured(t, resources["pod"]),
+							common.ApplyHookAnnotation, "test"),
+					},
+					Filters: []filter.ValidationFilter{filter.PreventRemoveFilter{}},
+					Pruner:  pruner,
+				},
+				&taskrunner.WaitTask{
+					TaskName: "wait-2",
+					IDs: object.ObjMetadataSet{
+						testutil.ToIdentifier(t, resources["pod"]),
+					},
+					Condition: taskrunner.AllNotFound,
+				},
+				&task.DeleteOrUpdateInvTask{
+					TaskName:  "inventory-set-0",
+					InvClient: &inventory.FakeClient{},
+					InvInfo:   invInfo,
+					PrevInventory: object.ObjMetadataSet{
+						testutil.ToIdentifier(t, resources["pod"]),
+						testutil.ToIdentifier(t, resources["deployment"]),
+					},
+				},
+			},
+			expectedStatus: []actuation.ObjectStatus{
+				{
+					ObjectReference: inventory.ObjectReferenceFromObjMetadata(
+						testutil.ToIdentifier(t, resources["pod"]),
+					),
+					Strategy:  actuation.ActuationStrategyApply,
+					Actuation: actuation.ActuationPending,
+					Reconcile: actuation.ReconcilePending,
+				},
+				{
+					ObjectReference: inventory.ObjectReferenceFromObjMetadata(
+						testutil.ToIdentifier(t, resources["deployment"]),
+					),
+					Strategy:  actuation.ActuationStrategyApply,
+					Actuation: actuation.ActuationPending,
+					Reconcile: actuation.ReconcilePending,
+				},
+			},
+		},
+		"test resources are deleted in reverse apply order": {
+			applyObjs: []*unstructured.Unstructured{
+				withAnnotation(testutil.Unstructured(t, resources["secret"]),
+					common.ApplyHookAnnotation, "test"),
+				withAnnotation(testutil.Unstructured(t, resources["pod"],
+					testutil.AddDependsOn(t, testutil.ToIdentifier(t, resources["secret"]))),
+					common.ApplyHookAnnotation, "test"),
+			},
+			expectedTasks: []taskrunner.Task{
+				&task.InvAddTask{
+					TaskName:  "inventory-add-0",
+					InvClient: &inventory.FakeClient{},
+					InvInfo:   invInfo,
+					Objects: object.UnstructuredSet{
+						withAnnotation(testutil.Unstructured(t, resources["secret"]),
+							common.ApplyHookAnnotation, "test"),
+						withAnnotation(testutil.Unstructured(t, resources["pod"],
+							testutil.AddDependsOn(t, testutil.ToIdentifier(t, resources["secret"]))),
+							common.ApplyHookAnnotation, "test"),
+					},
+				},
+				&task.ApplyTask{
+					TaskName: "apply-0",
+					Objects: []*unstructured.Unstructured{
+						withAnnotation(testutil.Unstructured(t, resources["secret"]),
+							common.ApplyHookAnnotation, "test"),
+					},
+				},
+				&taskrunner.WaitTask{
+					TaskName: "wait-0",
+					IDs: object.ObjMetadataSet{
+						testutil.ToIdentifier(t, resources["secret"]),
+					},
+					Condition: taskrunner.AllCurrent,
+				},
+				&task.ApplyTask{
+					TaskName: "apply-1",
+					Objects: []*unstructured.Unstructured{
+						withAnnotation(testutil.Unstructured(t, resources["pod"],
+							testutil.AddDependsOn(t, testutil.ToIdentifier(t, resources["secret"]))),
+							common.ApplyHookAnnotation, "test"),
+					},
+				},
+				&taskrunner.WaitTask{
+					TaskName: "wait-1",
+					IDs: object.ObjMetadataSet{
+						testutil.ToIdentifier(t, resources["pod"]),
+					},
+					Condition: taskrunner.AllCurrent,
+				},
+				&task.TestCleanupTask{
+					TaskName: "prune-0",
+					Objects: []*unstructured.Unstructured{
+						withAnnotation(testutil.Unstructured(t, resources["pod"],
+							testutil.AddDependsOn(t, testutil.ToIdentifier(t, resources["secret"]))),
+							common.ApplyHookAnnotation, "test"),
+						withAnnotation(testutil.Unstructured(t, resources["secret"]),
+							common.ApplyHookAnnotation, "test"),
+					},
+					Filters: []filter.ValidationFilter{filter.PreventRemoveFilter{}},
+					Pruner:  pruner,
+				},
+				&taskrunner.WaitTask{
+					TaskName: "wait-2",
+					IDs: object.ObjMetadataSet{
+						testutil.ToIdentifier(t, resources["secret"]),
+						testutil.ToIdentifier(t, resources["pod"]),
+					},
+					Condition: taskrunner.AllNotFound,
+				},
+				&task.DeleteOrUpdateInvTask{
+					TaskName:  "inventory-set-0",
+					InvClient: &inventory.FakeClient{},
+					InvInfo:   invInfo,
+					PrevInventory: object.ObjMetadataSet{
+						testutil.ToIdentifier(t, resources["secret"]),
+						testutil.ToIdentifier(t, resources["pod"]),
+					},
+				},
+			},
+			expectedStatus: []actuation.ObjectStatus{
+				{
+					ObjectReference: inventory.ObjectReferenceFromObjMetadata(
+						testutil.ToIdentifier(t, resources["secret"]),
+					),
+					Strategy:  actuation.ActuationStrategyApply,
+					Actuation: actuation.ActuationPending,
+					Reconcile: actuation.ReconcilePending,
+				},
+				{
+					ObjectReference: inventory.ObjectReferenceFromObjMetadata(
+						testutil.ToIdentifier(t, resources["pod"]),
+					),
+					Strategy:  actuation.ActuationStrategyApply,
+					Actuation: actuation.ActuationPending,
+					Reconcile: actuation.ReconcilePending,
+				},
+			},
+		},
+		"dependency on a later apply hook phase returns error": {
+			applyObjs: []*unstructured.Unstructured{
+				testutil.Unstructured(t, resources["deployment"]),
+				withAnnotation(withAnnotation(testutil.Unstructured(t, resources["secret"]),
+					common.ApplyHookAnnotation, "pre-apply"),
+					dependson.Annotation, "apps/namespaces/test-namespace/Deployment/foo"),
+			},
+			expectedTasks: []taskrunner.Task{},
+			expectedError: validation.NewError(
+				HookDependencyError{
+					Edge: graph.Edge{
+						From: testutil.ToIdentifier(t, resources["secret"]),
+						To:   testutil.ToIdentifier(t, resources["deployment"]),
+					},
+					Hook:           common.ApplyHookPreApply,
+					DependencyHook: common.ApplyHookNone,
+				},
+				testutil.ToIdentifier(t, resources["secret"]),
+			),
+		},
+		"invalid apply wave returns error": {
+			applyObjs: []*unstructured.Unstructured{
+				withAnnotation(testutil.Unstructured(t, resources["secret"]),
+					common.ApplyWaveAnnotation, "first"),
+			},
+			expectedTasks: []taskrunner.Task{},
+			expectedError: validation.NewError(
+				object.InvalidAnnotationError{
+					Annotation: common.ApplyWaveAnnotation,
+					Cause: &object.ParseError{
+						Value:  "first",
+						Length: 5,
+						Cause:  errors.New(`invalid apply wave "first": must be an integer`),
+					},
+				},
+				testutil.ToIdentifier(t, resources["secret"]),
+			),
+		},
+		"reconcile timeout annotation overrides the timeout": {
+			applyObjs: []*unstructured.Unstructured{
+				withAnnotation(testutil.Unstructured(t, resources["deployment"]),
+					common.ReconcileTimeoutAnnotation, "30s"),
+			},
+			options: Options{ReconcileTimeout: time.Minute},
+			expectedTasks: []taskrunner.Task{
+				&task.InvAddTask{
+					TaskName:  "inventory-add-0",
+					InvClient: &inventory.FakeClient{},
+					InvInfo:   invInfo,
+					Objects: object.UnstructuredSet{
+						withAnnotation(testutil.Unstructured(t, resources["deployment"]),
+							common.ReconcileTimeoutAnnotation, "30s"),
+					},
+				},
+				&task.ApplyTask{
+					TaskName: "apply-0",
+					Objects: []*unstructured.Unstructured{
+						withAnnotation(testutil.Unstructured(t, resources["deployment"]),
+							common.ReconcileTimeoutAnnotation, "30s"),
+					},
+				},
+				&taskrunner.WaitTask{
+					TaskName: "wait-0",
+					IDs: object.ObjMetadataSet{
+						testutil.ToIdentifier(t, resources["deployment"]),
+					},
+					Condition: taskrunner.AllCurrent,
+					Timeout:   time.Minute,
+					ObjectTimeouts: map[object.ObjMetadata]time.Duration{
+						testutil.ToIdentifier(t, resources["deployment"]): 30 * time.Second,
+					},
+				},
+				&task.DeleteOrUpdateInvTask{
+					TaskName:  "inventory-set-0",
+					InvClient: &inventory.FakeClient{},
+					InvInfo:   invInfo,
+					PrevInventory: object.ObjMetadataSet{
+						testutil.ToIdentifier(t, resources["deployment"]),
+					},
+				},
+			},
+			expectedStatus: []actuation.ObjectStatus{
+				{
+					ObjectReference: inventory.ObjectReferenceFromObjMetadata(
+						testutil.ToIdentifier(t, resources["deployment"]),
+					),
+					Strategy:  actuation.ActuationStrategyApply,
+					Actuation: actuation.ActuationPending,
+					Reconcile: actuation.ReconcilePending,
+				},
+			},
+		},
+		"deferred objects are applied last": {
+			applyObjs: []*unstructured.Unstructured{
+				testutil.Unstructured(t, resources["deployment"]),
+			},
+			deferredObjs: []*unstructured.Unstructured{
+				testutil.Unstructured(t, resources["crontab1"]),
+			},
+			options: Options{
+				ReconcileTimeout:    time.Minute,
+				UnknownKindsTimeout: 30 * time.Second,
+			},
+			expectedTasks: []taskrunner.Task{
+				&task.InvAddTask{
+					TaskName:  "inventory-add-0",
+					InvClient: &inventory.FakeClient{},
+					InvInfo:   invInfo,
+					Objects: object.UnstructuredSet{
+						testutil.Unstructured(t, resources["deployment"]),
+						testutil.Unstructured(t, resources["crontab1"]),
+					},
+				},
+				&task.ApplyTask{
+					TaskName: "apply-0",
+					Objects: []*unstructured.Unstructured{
+						testutil.Unstructured(t, resources["deployment"]),
+					},
+				},
+				&taskrunner.WaitTask{
+					TaskName: "wait-0",
+					IDs: object.ObjMetadataSet{
+						testutil.ToIdentifier(t, resources["deployment"]),
+					},
+					Condition: taskrunner.AllCurrent,
+					Timeout:   time.Minute,
+				},
+				&task.ApplyTask{
+					TaskName: "apply-1",
+					Objects: []*unstructured.Unstructured{
+						testutil.Unstructured(t, resources["crontab1"]),
+					},
+					RefreshKinds: true,
+					KindTimeout:  30 * time.Second,
+				},
+				&task.DeleteOrUpdateInvTask{
+					TaskName:  "inventory-set-0",
+					InvClient: &inventory.FakeClient{},
+					InvInfo:   invInfo,
+					PrevInventory: object.ObjMetadataSet{
+						testutil.ToIdentifier(t, resources["deployment"]),
+					},
+				},
+			},
+			expectedStatus: []actuation.ObjectStatus{
+				{
+					ObjectReference: inventory.ObjectReferenceFromObjMetadata(
+						testutil.ToIdentifier(t, resources["deployment"]),
+					),
+					Strategy:  actuation.ActuationStrategyApply,
+					Actuation: actuation.ActuationPending,
+					Reconcile: actuation.ReconcilePending,
+				},
+				{
+					ObjectReference: inventory.ObjectReferenceFromObjMetadata(
+						testutil.ToIdentifier(t, resources["crontab1"]),
+					),
+					Strategy:  actuation.ActuationStrategyApply,
+					Actuation: actuation.ActuationPending,
+					Reconcile: actuation.ReconcilePending,
+				},
+			},
+		},
+		"invalid reconcile timeout returns error": {
+			applyObjs: []*unstructured.Unstructured{
+				withAnnotation(testutil.Unstructured(t, resources["secret"]),
+					common.ReconcileTimeoutAnnotation, "-1m"),
+			},
+			expectedTasks: []taskrunner.Task{},
+			expectedError: validation.NewError(
+				object.InvalidAnnotationError{
+					Annotation: common.ReconcileTimeoutAnnotation,
+					Cause: &object.ParseError{
+						Value:  "-1m",
+						Length: 3,
+						Cause:  errors.New(`invalid timeout "-1m": timeout must be positive`),
+					},
+				},
+				testutil.ToIdentifier(t, resources["secret"]),
+			),
+		},
+		"unsupported apply strategy returns error": {
+			applyObjs: []*unstructured.Unstructured{
+				withAnnotation(testutil.Unstructured(t, resources["secret"]),
+					common.ApplyStrategyAnnotation, "merge"),
+			},
+			expectedTasks: []taskrunner.Task{},
+			expectedError: validation.NewError(
+				object.InvalidAnnotationError{
+					Annotation: common.ApplyStrategyAnnotation,
+					Cause: &object.ParseError{
+						Value:  "merge",
+						Length: 5,
+						Cause: errors.New(`unsupported value "merge": supported values: ` +
+							`["server-side" "client-side" "replace" "create-only"]`),
+					},
+				},
+				testutil.ToIdentifier(t, resources["secret"]),
+			),
+		},
 	}
 
 	for tn, tc := range testCases {
@@ -820,6 +1419,7 @@ func TestTaskQueueBuilder_ApplyBuild(t *testing.T) {
 			taskContext := taskrunner.NewTaskContext(nil, nil)
 			tq := tqb.WithInventory(invInfo).
 				WithApplyObjects(tc.applyObjs).
+				WithDeferredObjects(tc.deferredObjs).
 				Build(taskContext, tc.options)
 			err := vCollector.ToError()
 			if tc.expectedError != nil {
@@ -835,6 +1435,44 @@ func TestTaskQueueBuilder_ApplyBuild(t *testing.T) {
 	}
 }
 
+// TestTaskQueueBuilder_ApplierValidatedAnnotations makes sure that the
+// solver rejects the invalid values of all the annotations that the linter
+// reports as errors.
+func TestTaskQueueBuilder_ApplierValidatedAnnotations(t *testing.T) {
+	invalidValues := map[string]string{
+		common.ApplyStrategyAnnotation:    "merge",
+		common.ApplyWaveAnnotation:        "first",
+		common.ApplyHookAnnotation:        "post-delete",
+		common.RecreateAnnotation:         "sometimes",
+		common.ReconcileTimeoutAnnotation: "soon",
+	}
+	invInfo := inventory.WrapInventoryInfoObj(newInvObject(
+		"abc-123", "default", "test"))
+
+	for _, annotation := range validation.ApplierValidatedAnnotations {
+		t.Run(annotation, func(t *testing.T) {
+			value, found := invalidValues[annotation]
+			require.True(t, found, "missing invalid value")
+			obj := withAnnotation(testutil.Unstructured(t, resources["secret"]), annotation, value)
+
+			vCollector := &validation.Collector{}
+			tqb := TaskQueueBuilder{
+				Pruner:    pruner,
+				Mapper:    testutil.NewFakeRESTMapper(),
+				InvClient: inventory.NewFakeClient(object.ObjMetadataSet{}),
+				Collector: vCollector,
+			}
+			tqb.WithInventory(invInfo).
+				WithApplyObjects(object.UnstructuredSet{obj}).
+				Build(taskrunner.NewTaskContext(nil, nil), Options{})
+
+			var annotationErr object.InvalidAnnotationError
+			require.ErrorAs(t, vCollector.ToError(), &annotationErr)
+			assert.Equal(t, annotation, annotationErr.Annotation)
+		})
+	}
+}
+
 func TestTaskQueueBuilder_PruneBuild(t *testing.T) {
 	// Use a custom Asserter to customize the comparison options
 	asserter := testutil.NewAsserter(
@@ -974,6 +1612,76 @@ func TestTaskQueueBuilder_PruneBuild(t *testing.T) {
 				},
 			},
 		},
+		"apply waves are pruned in reverse order": {
+			pruneObjs: []*unstructured.Unstructured{
+				withAnnotation(testutil.Unstructured(t, resources["default-pod"]),
+					common.ApplyWaveAnnotation, "-1"),
+				testutil.Unstructured(t, resources["pod"]),
+			},
+			options: Options{Prune: true},
+			expectedTasks: []taskrunner.Task{
+				&task.InvAddTask{
+					TaskName:  "inventory-add-0",
+					InvClient: &inventory.FakeClient{},
+					InvInfo:   invInfo,
+					Objects:   object.UnstructuredSet{},
+				},
+				&task.PruneTask{
+					TaskName: "prune-0",
+					Objects: []*unstructured.Unstructured{
+						testutil.Unstructured(t, resources["pod"]),
+					},
+				},
+				&taskrunner.WaitTask{
+					TaskName: "wait-0",
+					IDs: object.ObjMetadataSet{
+						testutil.ToIdentifier(t, resources["pod"]),
+					},
+					Condition: taskrunner.AllNotFound,
+				},
+				&task.PruneTask{
+					TaskName: "prune-1",
+					Objects: []*unstructured.Unstructured{
+						withAnnotation(testutil.Unstructured(t, resources["default-pod"]),
+							common.ApplyWaveAnnotation, "-1"),
+					},
+				},
+				&taskrunner.WaitTask{
+					TaskName: "wait-1",
+					IDs: object.ObjMetadataSet{
+						testutil.ToIdentifier(t, resources["default-pod"]),
+					},
+					Condition: taskrunner.AllNotFound,
+				},
+				&task.DeleteOrUpdateInvTask{
+					TaskName:  "inventory-set-0",
+					InvClient: &inventory.FakeClient{},
+					InvInfo:   invInfo,
+					PrevInventory: object.ObjMetadataSet{
+						testutil.ToIdentifier(t, resources["default-pod"]),
+						testutil.ToIdentifier(t, resources["pod"]),
+					},
+				},
+			},
+			expectedStatus: []actuation.ObjectStatus{
+				{
+					ObjectReference: inventory.ObjectReferenceFromObjMetadata(
+						testutil.ToIdentifier(t, resources["default-pod"]),
+					),
+					Strategy:  actuation.ActuationStrategyDelete,
+					Actuation: actuation.ActuationPending,
+					Reconcile: actuation.ReconcilePending,
+				},
+				{
+					ObjectReference: inventory.ObjectReferenceFromObjMetadata(
+						testutil.ToIdentifier(t, resources["pod"]),
+					),
+					Strategy:  actuation.ActuationStrategyDelete,
+					Actuation: actuation.ActuationPending,
+					Reconcile: actuation.ReconcilePending,
+				},
+			},
+		},
 		"dependent resources, two prune tasks, two wait tasks": {
 			pruneObjs: []*unstructured.Unstructured{
 				testutil.Unstructured(t, resources["pod"],
@@ -1045,6 +1753,69 @@ func TestTaskQueueBuilder_PruneBuild(t *testing.T) {
 				},
 			},
 		},
+		"skip prune wait skips the wait of the last prune task": {
+			pruneObjs: []*unstructured.Unstructured{
+				testutil.Unstructured(t, resources["pod"],
+					testutil.AddDependsOn(t, testutil.ToIdentifier(t, resources["secret"]))),
+				testutil.Unstructured(t, resources["secret"]),
+			},
+			options: Options{Prune: true, PruneSkipWait: true},
+			expectedTasks: []taskrunner.Task{
+				&task.InvAddTask{
+					TaskName:  "inventory-add-0",
+					InvClient: &inventory.FakeClient{},
+					InvInfo:   invInfo,
+					Objects:   object.UnstructuredSet{},
+				},
+				&task.PruneTask{
+					TaskName: "prune-0",
+					Objects: []*unstructured.Unstructured{
+						testutil.Unstructured(t, resources["pod"],
+							testutil.AddDependsOn(t, testutil.ToIdentifier(t, resources["secret"]))),
+					},
+				},
+				&taskrunner.WaitTask{
+					TaskName: "wait-0",
+					IDs: object.ObjMetadataSet{
+						testutil.ToIdentifier(t, resources["pod"]),
+					},
+					Condition: taskrunner.AllNotFound,
+				},
+				&task.PruneTask{
+					TaskName: "prune-1",
+					Objects: []*unstructured.Unstructured{
+						testutil.Unstructured(t, resources["secret"]),
+					},
+				},
+				&task.DeleteOrUpdateInvTask{
+					TaskName:  "inventory-set-0",
+					InvClient: &inventory.FakeClient{},
+					InvInfo:   invInfo,
+					PrevInventory: object.ObjMetadataSet{
+						testutil.ToIdentifier(t, resources["pod"]),
+						testutil.ToIdentifier(t, resources["secret"]),
+					},
+				},
+			},
+			expectedStatus: []actuation.ObjectStatus{
+				{
+					ObjectReference: inventory.ObjectReferenceFromObjMetadata(
+						testutil.ToIdentifier(t, resources["pod"]),
+					),
+					Strategy:  actuation.ActuationStrategyDelete,
+					Actuation: actuation.ActuationPending,
+					Reconcile: actuation.ReconcilePending,
+				},
+				{
+					ObjectReference: inventory.ObjectReferenceFromObjMetadata(
+						testutil.ToIdentifier(t, resources["secret"]),
+					),
+					Strategy:  actuation.ActuationStrategyDelete,
+					Actuation: actuation.ActuationPending,
+					Reconcile: actuation.ReconcilePending,
+				},
+			},
+		},
 		"single resource with prune timeout has wait task": {
 			pruneObjs: []*unstructured.Unstructured{
 				testutil.Unstructured(t, resources["pod"]),
@@ -1094,6 +1865,57 @@ func TestTaskQueueBuilder_PruneBuild(t *testing.T) {
 				},
 			},
 		},
+		"stuck deletion threshold with finalizer removal on prune wait task": {
+			pruneObjs: []*unstructured.Unstructured{
+				testutil.Unstructured(t, resources["pod"]),
+			},
+			options: Options{
+				Prune:                       true,
+				PruneStuckDeletionThreshold: time.Minute,
+				PruneRemoveStuckFinalizers:  true,
+			},
+			expectedTasks: []taskrunner.Task{
+				&task.InvAddTask{
+					TaskName:  "inventory-add-0",
+					InvClient: &inventory.FakeClient{},
+					InvInfo:   invInfo,
+					Objects:   object.UnstructuredSet{},
+				},
+				&task.PruneTask{
+					TaskName: "prune-0",
+					Objects: []*unstructured.Unstructured{
+						testutil.Unstructured(t, resources["pod"]),
+					},
+				},
+				&taskrunner.WaitTask{
+					TaskName: "wait-0",
+					IDs: object.ObjMetadataSet{
+						testutil.ToIdentifier(t, resources["pod"]),
+					},
+					Condition:              taskrunner.AllNotFound,
+					StuckDeletionThreshold: time.Minute,
+					FinalizerRemover:       &prune.Pruner{},
+				},
+				&task.DeleteOrUpdateInvTask{
+					TaskName:  "inventory-set-0",
+					InvClient: &inventory.FakeClient{},
+					InvInfo:   invInfo,
+					PrevInventory: object.ObjMetadataSet{
+						testutil.ToIdentifier(t, resources["pod"]),
+					},
+				},
+			},
+			expectedStatus: []actuation.ObjectStatus{
+				{
+					ObjectReference: inventory.ObjectReferenceFromObjMetadata(
+						testutil.ToIdentifier(t, resources["pod"]),
+					),
+					Strategy:  actuation.ActuationStrategyDelete,
+					Actuation: actuation.ActuationPending,
+					Reconcile: actuation.ReconcilePending,
+				},
+			},
+		},
 		"multiple resources with prune timeout and server-dryrun": {
 			pruneObjs: []*unstructured.Unstructured{
 				testutil.Unstructured(t, resources["pod"]),
@@ -1882,6 +2704,9 @@ func waitTaskComparer() cmp.Option {
 			x.IDs.Hash() == y.IDs.Hash() && // exact order match
 			x.Condition == y.Condition &&
 			x.Timeout == y.Timeout &&
+			cmp.Equal(x.ObjectTimeouts, y.ObjectTimeouts) &&
+			x.StuckDeletionThreshold == y.StuckDeletionThreshold &&
+			(x.FinalizerRemover == nil) == (y.FinalizerRemover == nil) &&
 			cmp.Equal(x.Mapper, y.Mapper)
 	})
 }
@@ -1908,3 +2733,54 @@ func inventoryInfoComparer() cmp.Option {
 			x.Strategy() == y.Strategy()
 	})
 }
+
+func withAnnotation(u *unstructured.Unstructured, key, value string) *unstructured.Unstructured {
+	annotations := u.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[key] = value
+	u.SetAnnotations(annotations)
+	return u
+}
+
+func TestTaskQueue_CustomPipeline(t *testing.T) {
+	var calls []string
+	newStep := func(name string, err error) *task.FuncTask {
+		return &task.FuncTask{
+			TaskName:   name,
+			TaskAction: event.WaitAction,
+			Func: func(*taskrunner.TaskContext) error {
+				calls = append(calls, name)
+				return err
+			},
+		}
+	}
+	errVerify := errors.New("verification failed")
+	tq := NewTaskQueue(
+		newStep("step-0", nil),
+		newStep("verify-0", errVerify),
+		newStep("step-1", nil),
+	)
+	assert.Len(t, tq.Tasks(), 3)
+	assert.Equal(t, []event.ActionGroup{
+		{Name: "step-0", Action: event.WaitAction},
+		{Name: "verify-0", Action: event.WaitAction},
+		{Name: "step-1", Action: event.WaitAction},
+	}, tq.ToActionGroups())
+
+	eventChannel := make(chan event.Event)
+	taskContext := taskrunner.NewTaskContext(eventChannel, cache.NewResourceCacheMap())
+	go func() {
+		for range eventChannel {
+			// Discard the action group events.
+		}
+	}()
+	runner := taskrunner.NewTaskStatusRunner(object.ObjMetadataSet{}, watcher.BlindStatusWatcher{})
+	err := runner.Run(context.Background(), taskContext, tq.ToChannel(), taskrunner.Options{})
+	close(eventChannel)
+
+	// The error of a step stops the pipeline.
+	assert.ErrorIs(t, err, errVerify)
+	assert.Equal(t, []string{"step-0", "verify-0"}, calls)
+}
@@ -9,11 +9,17 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"sync"
+	"time"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 	"k8s.io/cli-runtime/pkg/resource"
@@ -28,8 +34,10 @@ import (
 	"sigs.k8s.io/cli-utils/pkg/apply/info"
 	"sigs.k8s.io/cli-utils/pkg/apply/mutator"
 	"sigs.k8s.io/cli-utils/pkg/apply/taskrunner"
+	"sigs.k8s.io/cli-utils/pkg/backoff"
 	"sigs.k8s.io/cli-utils/pkg/common"
 	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // applyOptions defines the two key functions on the ApplyOptions
@@ -59,8 +67,49 @@ type ApplyTask struct {
 	Mutators          []mutator.Interface
 	DryRunStrategy    common.DryRunStrategy
 	ServerSideOptions common.ServerSideOptions
+	// Concurrency is the maximum number of objects applied in parallel.
+	// Zero or one applies the objects sequentially.
+	Concurrency int
+	// RetryPolicy configures how the apply of an object is retried, when it
+	// fails with a transient error. The zero value disables retries.
+	RetryPolicy backoff.RetryPolicy
+	// RecreateOnImmutableFieldChange deletes and re-creates objects whose
+	// update is rejected because it changes an immutable field. Objects
+	// with the recreate annotation are re-created regardless.
+	RecreateOnImmutableFieldChange bool
+	// SkipUnchanged records the hash of the applied objects in an
+	// annotation, and skips the apply of the objects whose live hash is
+	// the same. See skipUnchanged.
+	SkipUnchanged bool
+	// SkipUnchangedWait registers the objects skipped by SkipUnchanged
+	// whose live status is Current as unchanged in the task context, so
+	// that the wait tasks do not wait for them to reconcile.
+	SkipUnchangedWait bool
+	// TypedClient, if set, is used to server-side apply the objects of
+	// TypedKinds as typed objects. See typedApply.
+	TypedClient client.Client
+	TypedKinds  []schema.GroupKind
+	// RefreshKinds resets the RESTMapper before applying, to find the kinds
+	// of the Objects added since it was last reset, like when their CRDs are
+	// installed at runtime by an operator. If KindTimeout is set, the task
+	// waits up to KindTimeout for all the kinds to be served. Objects whose
+	// kind is still unknown fail to apply.
+	RefreshKinds bool
+	KindTimeout  time.Duration
 }
 
+// recreatePollInterval and recreateTimeout configure how long to wait for an
+// object to be deleted, before re-creating it. Variables to allow unit
+// testing.
+var (
+	recreatePollInterval = time.Second
+	recreateTimeout      = time.Minute
+)
+
+// kindPollInterval is how often the RESTMapper is reset while waiting for
+// the kinds of the objects to be served. Variable to allow unit testing.
+var kindPollInterval = 2 * time.Second
+
 // applyOptionsFactoryFunc is a factory function for creating a new
 // applyOptions implementation. Used to allow unit testing.
 var applyOptionsFactoryFunc = newApplyOptions
@@ -85,107 +134,479 @@ func (a *ApplyTask) Identifiers() object.ObjMetadataSet {
 // after the Run function has completed. This information is then added
 // to the taskContext. The generation is increased every time
 // the desired state of a resource is changed.
+//
+// If Concurrency is greater than one, up to Concurrency objects are applied
+// in parallel, and the events of different objects may be sent in any order.
 func (a *ApplyTask) Start(taskContext *taskrunner.TaskContext) {
 	go func() {
 		// TODO: pipe Context through TaskContext
 		ctx := context.TODO()
+		if a.RefreshKinds {
+			a.waitForKinds(ctx, taskContext)
+		}
 		objects := a.Objects
-		klog.V(2).Infof("apply task starting (name: %q, objects: %d)",
-			a.Name(), len(objects))
-		for _, obj := range objects {
-			// Set the client and mapping fields on the provided
-			// info so they can be applied to the cluster.
-			info, err := a.InfoHelper.BuildInfo(obj)
-			// BuildInfo strips path annotations.
-			// Use modified object for filters, mutations, and events.
-			obj = info.Object.(*unstructured.Unstructured)
-			id := object.UnstructuredToObjMetadata(obj)
-			if err != nil {
-				err = applyerror.NewUnknownTypeError(err)
-				if klog.V(4).Enabled() {
-					// only log event emitted errors if the verbosity > 4
-					klog.Errorf("apply task errored (object: %s): unable to convert obj to info: %v", id, err)
-				}
-				taskContext.SendEvent(a.createApplyFailedEvent(id, err))
-				taskContext.InventoryManager().AddFailedApply(id)
-				continue
-			}
+		workers := a.Concurrency
+		if workers < 1 {
+			workers = 1
+		}
+		if workers > len(objects) {
+			workers = len(objects)
+		}
+		klog.V(2).Infof("apply task starting (name: %q, objects: %d, workers: %d)",
+			a.Name(), len(objects), workers)
 
-			// Check filters to see if we're prevented from applying.
-			var filterErr error
-			for _, applyFilter := range a.Filters {
-				klog.V(6).Infof("apply filter evaluating (filter: %s, object: %s)", applyFilter.Name(), id)
-				filterErr = applyFilter.Filter(obj)
-				if filterErr != nil {
-					var fatalErr *filter.FatalError
-					if errors.As(filterErr, &fatalErr) {
-						if klog.V(4).Enabled() {
-							// only log event emitted errors if the verbosity > 4
-							klog.Errorf("apply filter errored (filter: %s, object: %s): %v", applyFilter.Name(), id, fatalErr.Err)
-						}
-						taskContext.SendEvent(a.createApplyFailedEvent(id, fatalErr))
-						taskContext.InventoryManager().AddFailedApply(id)
-						break
+		// The inventory manager is not safe for concurrent use, so the
+		// workers take turns to read and update the actuation status,
+		// including while evaluating the filters.
+		var invMu sync.Mutex
+		var abortOnce sync.Once
+		var abortErr error
+		objCh := make(chan *unstructured.Unstructured)
+		var wg sync.WaitGroup
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for obj := range objCh {
+					// Stop early if the cluster is consistently failing.
+					if err := taskContext.CircuitBreaker().Err(); err != nil {
+						abortOnce.Do(func() {
+							abortErr = err
+						})
+						continue
 					}
-					klog.V(4).Infof("apply filtered (filter: %s, object: %s): %v", applyFilter.Name(), id, filterErr)
-					taskContext.SendEvent(a.createApplySkippedEvent(id, obj, filterErr))
-					taskContext.InventoryManager().AddSkippedApply(id)
-					break
+					a.applyObject(ctx, taskContext, &invMu, obj)
 				}
+			}()
+		}
+		for _, obj := range objects {
+			if err := taskContext.CircuitBreaker().Err(); err != nil {
+				abortOnce.Do(func() {
+					abortErr = err
+				})
+				break
 			}
-			if filterErr != nil {
-				continue
+			objCh <- obj
+		}
+		close(objCh)
+		wg.Wait()
+
+		if abortErr != nil {
+			klog.V(2).Infof("apply task aborting (name: %q): %v", a.Name(), abortErr)
+			taskContext.TaskChannel() <- taskrunner.TaskResult{Err: abortErr}
+			return
+		}
+		a.sendTaskResult(taskContext)
+	}()
+}
+
+// waitForKinds waits until the kinds of all the objects are served by the
+// cluster, the KindTimeout expires or the deadline of the run is reached.
+// The RESTMapper is reset before each attempt, to find the new kinds.
+func (a *ApplyTask) waitForKinds(ctx context.Context, taskContext *taskrunner.TaskContext) {
+	deadline := time.Now().Add(a.KindTimeout)
+	if runDeadline := taskContext.Deadline(); !runDeadline.IsZero() && runDeadline.Before(deadline) {
+		deadline = runDeadline
+	}
+	for {
+		meta.MaybeResetRESTMapper(a.Mapper)
+		unknown := a.unknownKinds()
+		if len(unknown) == 0 {
+			return
+		}
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			klog.V(2).Infof("apply task stopped waiting for kinds (name: %q): %v", a.Name(), unknown)
+			return
+		}
+		klog.V(3).Infof("apply task waiting for kinds (name: %q): %v", a.Name(), unknown)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(min(kindPollInterval, remaining)):
+		}
+	}
+}
+
+// unknownKinds returns the kinds of the objects that are not served by the
+// cluster.
+func (a *ApplyTask) unknownKinds() []schema.GroupVersionKind {
+	var unknown []schema.GroupVersionKind
+	seen := make(map[schema.GroupVersionKind]bool)
+	for _, obj := range a.Objects {
+		gvk := obj.GroupVersionKind()
+		if seen[gvk] {
+			continue
+		}
+		seen[gvk] = true
+		if _, err := a.Mapper.RESTMapping(gvk.GroupKind(), gvk.Version); meta.IsNoMatchError(err) {
+			unknown = append(unknown, gvk)
+		}
+	}
+	return unknown
+}
+
+// applyObject applies a single object, unless it is filtered, and records
+// the result in the inventory. The invMu guards the inventory manager.
+func (a *ApplyTask) applyObject(ctx context.Context, taskContext *taskrunner.TaskContext,
+	invMu *sync.Mutex, obj *unstructured.Unstructured) {
+	// Set the client and mapping fields on the provided
+	// info so they can be applied to the cluster.
+	info, err := a.InfoHelper.BuildInfo(obj)
+	// BuildInfo strips path annotations.
+	// Use modified object for filters, mutations, and events.
+	obj = info.Object.(*unstructured.Unstructured)
+	id := object.UnstructuredToObjMetadata(obj)
+	if err != nil {
+		err = applyerror.NewUnknownTypeError(err)
+		if klog.V(4).Enabled() {
+			// only log event emitted errors if the verbosity > 4
+			klog.Errorf("apply task errored (object: %s): unable to convert obj to info: %v", id, err)
+		}
+		a.sendApplyFailedEvent(taskContext, id, err)
+		invMu.Lock()
+		taskContext.InventoryManager().AddFailedApply(id)
+		invMu.Unlock()
+		return
+	}
+
+	// Check filters to see if we're prevented from applying.
+	// Filters may read the actuation status of other objects.
+	invMu.Lock()
+	for _, applyFilter := range a.Filters {
+		klog.V(6).Infof("apply filter evaluating (filter: %s, object: %s)", applyFilter.Name(), id)
+		filterErr := applyFilter.Filter(obj)
+		if filterErr == nil {
+			continue
+		}
+		var fatalErr *filter.FatalError
+		if errors.As(filterErr, &fatalErr) {
+			if klog.V(4).Enabled() {
+				// only log event emitted errors if the verbosity > 4
+				klog.Errorf("apply filter errored (filter: %s, object: %s): %v", applyFilter.Name(), id, fatalErr.Err)
 			}
+			taskContext.InventoryManager().AddFailedApply(id)
+			invMu.Unlock()
+			a.sendApplyFailedEvent(taskContext, id, fatalErr)
+			return
+		}
+		klog.V(4).Infof("apply filtered (filter: %s, object: %s): %v", applyFilter.Name(), id, filterErr)
+		taskContext.InventoryManager().AddSkippedApply(id)
+		invMu.Unlock()
+		taskContext.SendEvent(a.createApplySkippedEvent(id, obj, filterErr))
+		return
+	}
+	invMu.Unlock()
 
-			// Execute mutators, if any apply
-			err = a.mutate(ctx, obj)
-			if err != nil {
-				if klog.V(4).Enabled() {
-					// only log event emitted errors if the verbosity > 4
-					klog.Errorf("apply mutation errored (object: %s): %v", id, err)
-				}
-				taskContext.SendEvent(a.createApplyFailedEvent(id, err))
-				taskContext.InventoryManager().AddFailedApply(id)
-				continue
+	// Execute mutators, if any apply
+	err = a.mutate(ctx, obj)
+	if err != nil {
+		if klog.V(4).Enabled() {
+			// only log event emitted errors if the verbosity > 4
+			klog.Errorf("apply mutation errored (object: %s): %v", id, err)
+		}
+		a.sendApplyFailedEvent(taskContext, id, err)
+		invMu.Lock()
+		taskContext.InventoryManager().AddFailedApply(id)
+		invMu.Unlock()
+		return
+	}
+
+	if a.SkipUnchanged && !a.DryRunStrategy.ClientOrServerDryRun() && a.DynamicClient != nil {
+		skipped, err := a.skipUnchanged(ctx, info, taskContext.EventChannel())
+		if err != nil {
+			// Apply the object anyway.
+			klog.V(4).Infof("apply unable to compare applied hash (object: %s): %v", id, err)
+		}
+		if skipped {
+			live := info.Object.(*unstructured.Unstructured)
+			invMu.Lock()
+			taskContext.InventoryManager().AddSuccessfulApply(id, live.GetUID(), live.GetGeneration())
+			if a.SkipUnchangedWait && isCurrent(live) {
+				taskContext.AddUnchangedObject(id)
 			}
+			invMu.Unlock()
+			return
+		}
+	}
 
-			// Create a new instance of the applyOptions interface and use it
-			// to apply the objects.
-			ao := applyOptionsFactoryFunc(a.Name(), taskContext.EventChannel(),
-				a.ServerSideOptions, a.DryRunStrategy, a.DynamicClient, a.OpenAPIGetter)
-			ao.SetObjects([]*resource.Info{info})
-			klog.V(5).Infof("applying object: %v", id)
-			err = ao.Run()
-			if err != nil && a.ServerSideOptions.ServerSideApply && isAPIService(obj) && isStreamError(err) {
-				// Server-side Apply doesn't work with APIService before k8s 1.21
-				// https://github.com/kubernetes/kubernetes/issues/89264
-				// Thus APIService is handled specially using client-side apply.
-				err = a.clientSideApply(info, taskContext.EventChannel())
+	// The apply strategy annotation was validated by the solver.
+	strategy, _ := common.GetApplyStrategy(obj)
+	attempt := 0
+	err = a.retryPolicy(taskContext).Do(ctx, func() error {
+		attempt++
+		if attempt > 1 {
+			klog.V(4).Infof("retrying apply (object: %s, attempt: %d)", id, attempt)
+		}
+		applyErr := a.applyWithStrategy(ctx, info, strategy, taskContext.EventChannel())
+		taskContext.CircuitBreaker().Record(applyErr)
+		return applyErr
+	})
+	if err != nil && applyerror.IsImmutableFieldError(err) {
+		if a.recreateAllowed(obj) {
+			klog.V(4).Infof("apply re-creating object (object: %s): %v", id, err)
+			err = a.recreate(ctx, info, strategy, taskContext.EventChannel())
+			taskContext.CircuitBreaker().Record(err)
+		} else {
+			err = applyerror.NewImmutableFieldError(err)
+		}
+	}
+	if err != nil {
+		err = applyerror.NewApplyRunError(err)
+		if klog.V(4).Enabled() {
+			// only log event emitted errors if the verbosity > 4
+			klog.Errorf("apply errored (object: %s): %v", id, err)
+		}
+		a.sendApplyFailedEvent(taskContext, id, err)
+		invMu.Lock()
+		taskContext.InventoryManager().AddFailedApply(id)
+		invMu.Unlock()
+	} else if info.Object != nil {
+		acc, err := meta.Accessor(info.Object)
+		if err == nil {
+			uid := acc.GetUID()
+			gen := acc.GetGeneration()
+			invMu.Lock()
+			taskContext.InventoryManager().AddSuccessfulApply(id, uid, gen)
+			previousOwner, adopted := taskContext.AdoptedObject(id)
+			invMu.Unlock()
+			if adopted {
+				a.sendAdoptEvent(taskContext, id, previousOwner)
 			}
-			if err != nil {
-				err = applyerror.NewApplyRunError(err)
-				if klog.V(4).Enabled() {
-					// only log event emitted errors if the verbosity > 4
-					klog.Errorf("apply errored (object: %s): %v", id, err)
-				}
-				taskContext.SendEvent(a.createApplyFailedEvent(id, err))
-				taskContext.InventoryManager().AddFailedApply(id)
-			} else if info.Object != nil {
-				acc, err := meta.Accessor(info.Object)
-				if err == nil {
-					uid := acc.GetUID()
-					gen := acc.GetGeneration()
-					taskContext.InventoryManager().AddSuccessfulApply(id, uid, gen)
-				}
+		}
+	}
+}
+
+// applyWithStrategy applies the object with the given apply strategy.
+func (a *ApplyTask) applyWithStrategy(ctx context.Context, info *resource.Info, strategy common.ApplyStrategy,
+	eventChannel chan<- event.Event) error {
+	id := object.UnstructuredToObjMetadata(info.Object.(*unstructured.Unstructured))
+	switch strategy {
+	case common.ApplyStrategyReplace:
+		klog.V(5).Infof("replacing object: %v", id)
+		return a.replace(ctx, info, eventChannel)
+	case common.ApplyStrategyCreateOnly:
+		klog.V(5).Infof("creating object: %v", id)
+		return a.createOnly(ctx, info, eventChannel)
+	default:
+		klog.V(5).Infof("applying object: %v", id)
+		return a.apply(ctx, info, strategy, eventChannel)
+	}
+}
+
+// recreateAllowed returns true if the object may be deleted and re-created
+// to change immutable fields. Dry runs never re-create objects.
+func (a *ApplyTask) recreateAllowed(obj *unstructured.Unstructured) bool {
+	if a.DryRunStrategy.ClientOrServerDryRun() || a.DynamicClient == nil {
+		return false
+	}
+	// The recreate annotation was validated by the solver.
+	recreate, _ := common.GetRecreate(obj)
+	return recreate || a.RecreateOnImmutableFieldChange
+}
+
+// recreate deletes the live object, waits for it to be gone, and applies the
+// object again.
+func (a *ApplyTask) recreate(ctx context.Context, info *resource.Info, strategy common.ApplyStrategy,
+	eventChannel chan<- event.Event) error {
+	obj := info.Object.(*unstructured.Unstructured)
+	client := a.resourceClient(info)
+	live, err := client.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		// Already deleted.
+	case err != nil:
+		return err
+	default:
+		// Only delete the object that was read, in case it was
+		// re-created in the meantime.
+		uid := live.GetUID()
+		propagation := metav1.DeletePropagationBackground
+		err = client.Delete(ctx, obj.GetName(), metav1.DeleteOptions{
+			Preconditions:     &metav1.Preconditions{UID: &uid},
+			PropagationPolicy: &propagation,
+		})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete object to re-create it: %w", err)
+		}
+	}
+	// Objects with finalizers are not deleted immediately.
+	err = wait.PollUntilContextTimeout(ctx, recreatePollInterval, recreateTimeout, true,
+		func(ctx context.Context) (bool, error) {
+			_, err := client.Get(ctx, obj.GetName(), metav1.GetOptions{})
+			if apierrors.IsNotFound(err) {
+				return true, nil
 			}
+			return false, err
+		})
+	if err != nil {
+		return fmt.Errorf("failed to wait for object to be deleted to re-create it: %w", err)
+	}
+	// Clear the fields of the deleted object.
+	obj.SetResourceVersion("")
+	obj.SetUID("")
+	return a.applyWithStrategy(ctx, info, strategy, eventChannel)
+}
+
+// apply applies the object with the applyOptions, using server-side or
+// client-side apply, depending on the strategy and ServerSideOptions.
+func (a *ApplyTask) apply(ctx context.Context, info *resource.Info, strategy common.ApplyStrategy,
+	eventChannel chan<- event.Event) error {
+	obj := info.Object.(*unstructured.Unstructured)
+	opts := a.serverSideOptions(strategy)
+
+	// Look up the field conflicts that will be overridden, so they
+	// can be reported on the apply event.
+	forcedConflicts := a.forcedConflicts(ctx, info, opts)
+
+	if a.useTypedClient(obj, opts) {
+		err := a.typedApply(ctx, info, opts, forcedConflicts, eventChannel)
+		if err != nil {
+			return a.conflictError(ctx, info, opts, err)
 		}
-		a.sendTaskResult(taskContext)
-	}()
+		return nil
+	}
+
+	// Create a new instance of the applyOptions interface and use it
+	// to apply the objects.
+	ao := applyOptionsFactoryFunc(a.Name(), eventChannel,
+		opts, a.DryRunStrategy, a.DynamicClient, a.OpenAPIGetter, forcedConflicts)
+	ao.SetObjects([]*resource.Info{info})
+	err := ao.Run()
+	if err != nil && opts.ServerSideApply && object.IsAPIService(obj) && isStreamError(err) {
+		// Server-side Apply doesn't work with APIService before k8s 1.21
+		// https://github.com/kubernetes/kubernetes/issues/89264
+		// Thus APIService is handled specially using client-side apply.
+		err = a.clientSideApply(info, eventChannel)
+	}
+	if err != nil {
+		return a.conflictError(ctx, info, opts, err)
+	}
+	return nil
+}
+
+// replace creates the object, or updates the object if it already exists,
+// replacing the live object instead of merging the changes.
+func (a *ApplyTask) replace(ctx context.Context, info *resource.Info, eventChannel chan<- event.Event) error {
+	obj := info.Object.(*unstructured.Unstructured)
+	client := a.resourceClient(info)
+	live, err := client.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return a.create(ctx, info, eventChannel)
+	}
+	if err != nil {
+		return err
+	}
+	result := obj
+	if !a.DryRunStrategy.ClientDryRun() {
+		obj = obj.DeepCopy()
+		obj.SetResourceVersion(live.GetResourceVersion())
+		result, err = client.Update(ctx, obj, metav1.UpdateOptions{
+			DryRun:       a.dryRunOption(),
+			FieldManager: a.serverSideOptions(common.ApplyStrategyDefault).FieldManager,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return a.sendApplySuccessfulEvent(info, result, eventChannel)
+}
+
+// createOnly creates the object if it does not exist. Existing objects are
+// not updated: their apply is reported as skipped, with an
+// AlreadyExistsError. They are still recorded as applied in the inventory,
+// so that their dependents are applied.
+func (a *ApplyTask) createOnly(ctx context.Context, info *resource.Info, eventChannel chan<- event.Event) error {
+	obj := info.Object.(*unstructured.Unstructured)
+	live, err := a.resourceClient(info).Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return a.create(ctx, info, eventChannel)
+	}
+	if err != nil {
+		return err
+	}
+	id := object.UnstructuredToObjMetadata(obj)
+	klog.V(4).Infof("apply skipped update of existing object (object: %s, strategy: %q)",
+		id, common.ApplyStrategyCreateOnly)
+	if err := info.Refresh(live, true); err != nil {
+		return err
+	}
+	eventChannel <- a.createApplySkippedEvent(id, live, &AlreadyExistsError{})
+	return nil
+}
+
+// AlreadyExistsError is the reason the apply of an object with the
+// create-only apply strategy is skipped, if the object already exists.
+type AlreadyExistsError struct{}
+
+func (e *AlreadyExistsError) Error() string {
+	return "object already exists, and the create-only apply strategy does not update it"
+}
+
+func (e *AlreadyExistsError) Is(err error) bool {
+	_, ok := err.(*AlreadyExistsError)
+	return ok
+}
+
+// SkipReason returns the reason code of the skipped events.
+func (e *AlreadyExistsError) SkipReason() event.SkipReason {
+	return event.SkipReasonAlreadyExists
+}
+
+// create creates the object.
+func (a *ApplyTask) create(ctx context.Context, info *resource.Info, eventChannel chan<- event.Event) error {
+	result := info.Object.(*unstructured.Unstructured)
+	if !a.DryRunStrategy.ClientDryRun() {
+		var err error
+		result, err = a.resourceClient(info).Create(ctx, result, metav1.CreateOptions{
+			DryRun:       a.dryRunOption(),
+			FieldManager: a.serverSideOptions(common.ApplyStrategyDefault).FieldManager,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return a.sendApplySuccessfulEvent(info, result, eventChannel)
+}
+
+// sendApplySuccessfulEvent updates the info with the result object and sends
+// an apply successful event.
+func (a *ApplyTask) sendApplySuccessfulEvent(info *resource.Info, result *unstructured.Unstructured,
+	eventChannel chan<- event.Event) error {
+	if err := info.Refresh(result, true); err != nil {
+		return err
+	}
+	eventChannel <- event.Event{
+		Type: event.ApplyType,
+		ApplyEvent: event.ApplyEvent{
+			GroupName:  a.Name(),
+			Identifier: object.UnstructuredToObjMetadata(result),
+			Status:     event.ApplySuccessful,
+			Resource:   result,
+		},
+	}
+	return nil
+}
+
+// resourceClient returns a dynamic client for the resource of the info.
+func (a *ApplyTask) resourceClient(info *resource.Info) dynamic.ResourceInterface {
+	if info.Mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		return a.DynamicClient.Resource(info.Mapping.Resource).Namespace(info.Namespace)
+	}
+	return a.DynamicClient.Resource(info.Mapping.Resource)
+}
+
+// dryRunOption returns the DryRun option for requests to the server.
+func (a *ApplyTask) dryRunOption() []string {
+	if a.DryRunStrategy.ServerDryRun() {
+		return []string{metav1.DryRunAll}
+	}
+	return nil
 }
 
 func newApplyOptions(taskName string, eventChannel chan<- event.Event, serverSideOptions common.ServerSideOptions,
 	strategy common.DryRunStrategy, dynamicClient dynamic.Interface,
-	openAPIGetter discovery.OpenAPISchemaInterface) applyOptions {
+	openAPIGetter discovery.OpenAPISchemaInterface, forcedConflicts []event.FieldConflict) applyOptions {
 	emptyString := ""
 	return &apply.ApplyOptions{
 		VisitedNamespaces: sets.New[string](),
@@ -211,13 +632,92 @@ func newApplyOptions(taskName string, eventChannel chan<- event.Event, serverSid
 		FieldManager:    serverSideOptions.FieldManager,
 		DryRunStrategy:  strategy.Strategy(),
 		ToPrinter: (&KubectlPrinterAdapter{
-			ch:        eventChannel,
-			groupName: taskName,
+			ch:              eventChannel,
+			groupName:       taskName,
+			forcedConflicts: forcedConflicts,
 		}).toPrinterFunc(),
 		DynamicClient: dynamicClient,
 	}
 }
 
+// serverSideOptions returns the ServerSideOptions of the task, overridden by
+// the apply strategy of the object, using the default field manager if none
+// is specified.
+func (a *ApplyTask) serverSideOptions(strategy common.ApplyStrategy) common.ServerSideOptions {
+	opts := a.ServerSideOptions
+	switch strategy {
+	case common.ApplyStrategyServerSide:
+		opts.ServerSideApply = true
+	case common.ApplyStrategyClientSide:
+		opts.ServerSideApply = false
+	}
+	if opts.ServerSideApply && opts.FieldManager == "" {
+		opts.FieldManager = common.DefaultFieldManager
+	}
+	return opts
+}
+
+// forcedConflicts returns the fields of the object that are owned by other
+// field managers and would be overridden by a server-side apply with
+// ForceConflicts enabled. Returns nil if conflicts are not forced or not
+// reported, for dry-runs, or if the conflicts could not be determined.
+func (a *ApplyTask) forcedConflicts(ctx context.Context, info *resource.Info, opts common.ServerSideOptions) []event.FieldConflict {
+	if !opts.ServerSideApply || !opts.ForceConflicts || !opts.ReportForcedConflicts ||
+		a.DryRunStrategy.ClientOrServerDryRun() {
+		return nil
+	}
+	conflicts := a.fieldConflicts(ctx, info, opts)
+	if len(conflicts) > 0 {
+		klog.V(4).Infof("apply forcing conflicts (object: %s, conflicts: %d)",
+			object.UnstructuredToObjMetadata(info.Object.(*unstructured.Unstructured)), len(conflicts))
+	}
+	return conflicts
+}
+
+// fieldConflicts returns the fields of the object that are owned by other
+// field managers, with their live values. The conflicts are detected with a
+// server-side dry-run apply without force. Returns nil if the conflicts
+// could not be determined.
+func (a *ApplyTask) fieldConflicts(ctx context.Context, info *resource.Info, opts common.ServerSideOptions) []event.FieldConflict {
+	if a.DynamicClient == nil || info.Mapping == nil {
+		return nil
+	}
+	obj := info.Object.(*unstructured.Unstructured)
+	client := a.resourceClient(info)
+	_, err := client.Apply(ctx, obj.GetName(), obj, metav1.ApplyOptions{
+		FieldManager: opts.FieldManager,
+		DryRun:       []string{metav1.DryRunAll},
+	})
+	if err == nil || !apierrors.IsConflict(err) {
+		// Other errors are reported by the actual apply.
+		return nil
+	}
+	// The live values are reported if the object can be read.
+	live, getErr := client.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if getErr != nil {
+		live = nil
+	}
+	return applyerror.ParseFieldConflicts(err, live)
+}
+
+// conflictError returns the apply error with the field conflicts of the
+// object, if the server-side apply failed because fields are owned by other
+// field managers. Otherwise, returns the apply error.
+func (a *ApplyTask) conflictError(ctx context.Context, info *resource.Info, opts common.ServerSideOptions, err error) error {
+	if !opts.ServerSideApply || opts.ForceConflicts {
+		return err
+	}
+	// Errors of kubectl lose their status, so the conflicts are determined
+	// with another dry-run apply, if the error looks like a conflict.
+	if !apierrors.IsConflict(err) && !strings.Contains(err.Error(), "conflict") {
+		return err
+	}
+	if conflicts := a.fieldConflicts(ctx, info, opts); len(conflicts) > 0 {
+		return applyerror.NewConflictError(err, conflicts)
+	}
+	return err
+}
+
 func (a *ApplyTask) sendTaskResult(taskContext *taskrunner.TaskContext) {
 	klog.V(2).Infof("apply task completing (name: %q)", a.Name())
 	taskContext.TaskChannel() <- taskrunner.TaskResult{}
@@ -229,6 +729,20 @@ func (a *ApplyTask) Cancel(_ *taskrunner.TaskContext) {}
 // StatusUpdate is not supported by the ApplyTask.
 func (a *ApplyTask) StatusUpdate(_ *taskrunner.TaskContext, _ object.ObjMetadata) {}
 
+// retryPolicy returns the RetryPolicy of the task, which stops retrying once
+// the circuit breaker has tripped.
+func (a *ApplyTask) retryPolicy(taskContext *taskrunner.TaskContext) backoff.RetryPolicy {
+	policy := a.RetryPolicy
+	retriable := policy.Retriable
+	if retriable == nil {
+		retriable = backoff.IsRetriable
+	}
+	policy.Retriable = func(err error) bool {
+		return !taskContext.CircuitBreaker().Tripped() && retriable(err)
+	}
+	return policy
+}
+
 // mutate loops through the mutator list and executes them on the object.
 func (a *ApplyTask) mutate(ctx context.Context, obj *unstructured.Unstructured) error {
 	id := object.UnstructuredToObjMetadata(obj)
@@ -245,6 +759,13 @@ func (a *ApplyTask) mutate(ctx context.Context, obj *unstructured.Unstructured)
 	return nil
 }
 
+// sendApplyFailedEvent registers the cause of the failed apply of the object
+// and sends an apply failed event.
+func (a *ApplyTask) sendApplyFailedEvent(taskContext *taskrunner.TaskContext, id object.ObjMetadata, err error) {
+	taskContext.AddApplyError(id, err)
+	taskContext.SendEvent(a.createApplyFailedEvent(id, err))
+}
+
 func (a *ApplyTask) createApplyFailedEvent(id object.ObjMetadata, err error) event.Event {
 	return event.Event{
 		Type: event.ApplyType,
@@ -257,6 +778,20 @@ func (a *ApplyTask) createApplyFailedEvent(id object.ObjMetadata, err error) eve
 	}
 }
 
+// sendAdoptEvent sends an event reporting that the object, which was not
+// owned by the inventory, was adopted.
+func (a *ApplyTask) sendAdoptEvent(taskContext *taskrunner.TaskContext, id object.ObjMetadata, previousOwner string) {
+	klog.V(4).Infof("apply adopted object (object: %s, previous owner: %q)", id, previousOwner)
+	taskContext.SendEvent(event.Event{
+		Type: event.AdoptType,
+		AdoptEvent: event.AdoptEvent{
+			GroupName:     a.Name(),
+			Identifier:    id,
+			PreviousOwner: previousOwner,
+		},
+	})
+}
+
 func (a *ApplyTask) createApplySkippedEvent(id object.ObjMetadata, resource *unstructured.Unstructured, err error) event.Event {
 	return event.Event{
 		Type: event.ApplyType,
@@ -266,15 +801,11 @@ func (a *ApplyTask) createApplySkippedEvent(id object.ObjMetadata, resource *uns
 			Status:     event.ApplySkipped,
 			Resource:   resource,
 			Error:      err,
+			SkipReason: event.SkipReasonOf(err),
 		},
 	}
 }
 
-func isAPIService(obj *unstructured.Unstructured) bool {
-	gk := obj.GroupVersionKind().GroupKind()
-	return gk.Group == "apiregistration.k8s.io" && gk.Kind == "APIService"
-}
-
 // isStreamError checks if the error is a StreamError. Since kubectl wraps the actual StreamError,
 // we can't check the error type.
 func isStreamError(err error) bool {
@@ -282,7 +813,7 @@ func isStreamError(err error) bool {
 }
 
 func (a *ApplyTask) clientSideApply(info *resource.Info, eventChannel chan<- event.Event) error {
-	ao := applyOptionsFactoryFunc(a.Name(), eventChannel, common.ServerSideOptions{ServerSideApply: false}, a.DryRunStrategy, a.DynamicClient, a.OpenAPIGetter)
+	ao := applyOptionsFactoryFunc(a.Name(), eventChannel, common.ServerSideOptions{ServerSideApply: false}, a.DryRunStrategy, a.DynamicClient, a.OpenAPIGetter, nil)
 	ao.SetObjects([]*resource.Info{info})
 	return ao.Run()
 }
@@ -4,24 +4,42 @@
 package task
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/cli-runtime/pkg/resource"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/kubectl/pkg/scheme"
 	"sigs.k8s.io/cli-utils/pkg/apply/cache"
+	applyerror "sigs.k8s.io/cli-utils/pkg/apply/error"
 	"sigs.k8s.io/cli-utils/pkg/apply/event"
 	"sigs.k8s.io/cli-utils/pkg/apply/taskrunner"
+	"sigs.k8s.io/cli-utils/pkg/backoff"
 	"sigs.k8s.io/cli-utils/pkg/common"
 	"sigs.k8s.io/cli-utils/pkg/object"
 	"sigs.k8s.io/cli-utils/pkg/testutil"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 )
 
 type resourceInfo struct {
@@ -87,7 +105,7 @@ func TestApplyTask_BasicAppliedObjects(t *testing.T) {
 
 			oldAO := applyOptionsFactoryFunc
 			applyOptionsFactoryFunc = func(string, chan<- event.Event, common.ServerSideOptions, common.DryRunStrategy,
-				dynamic.Interface, discovery.OpenAPISchemaInterface) applyOptions {
+				dynamic.Interface, discovery.OpenAPISchemaInterface, []event.FieldConflict) applyOptions {
 				return &fakeApplyOptions{}
 			}
 			defer func() { applyOptionsFactoryFunc = oldAO }()
@@ -179,7 +197,7 @@ func TestApplyTask_FetchGeneration(t *testing.T) {
 
 			oldAO := applyOptionsFactoryFunc
 			applyOptionsFactoryFunc = func(string, chan<- event.Event, common.ServerSideOptions, common.DryRunStrategy,
-				dynamic.Interface, discovery.OpenAPISchemaInterface) applyOptions {
+				dynamic.Interface, discovery.OpenAPISchemaInterface, []event.FieldConflict) applyOptions {
 				return &fakeApplyOptions{}
 			}
 			defer func() { applyOptionsFactoryFunc = oldAO }()
@@ -301,7 +319,7 @@ func TestApplyTask_DryRun(t *testing.T) {
 				ao := &fakeApplyOptions{}
 				oldAO := applyOptionsFactoryFunc
 				applyOptionsFactoryFunc = func(string, chan<- event.Event, common.ServerSideOptions, common.DryRunStrategy,
-					dynamic.Interface, discovery.OpenAPISchemaInterface) applyOptions {
+					dynamic.Interface, discovery.OpenAPISchemaInterface, []event.FieldConflict) applyOptions {
 					return ao
 				}
 				defer func() { applyOptionsFactoryFunc = oldAO }()
@@ -450,7 +468,7 @@ func TestApplyTaskWithError(t *testing.T) {
 			ao := &fakeApplyOptions{}
 			oldAO := applyOptionsFactoryFunc
 			applyOptionsFactoryFunc = func(string, chan<- event.Event, common.ServerSideOptions, common.DryRunStrategy,
-				dynamic.Interface, discovery.OpenAPISchemaInterface) applyOptions {
+				dynamic.Interface, discovery.OpenAPISchemaInterface, []event.FieldConflict) applyOptions {
 				return ao
 			}
 			defer func() { applyOptionsFactoryFunc = oldAO }()
@@ -510,6 +528,14 @@ func TestApplyTaskWithError(t *testing.T) {
 			for _, id := range applyIDs.Diff(tc.expectedSkipped) {
 				assert.Falsef(t, im.IsSkippedApply(id), "ApplyTask should NOT mark object as skipped: %s", id)
 			}
+			// validate record of failure causes
+			applyErrs := taskContext.ApplyErrors()
+			require.Len(t, applyErrs, len(tc.expectedFailed))
+			for i, id := range tc.expectedFailed {
+				var objErr *applyerror.ObjectApplyError
+				require.ErrorAs(t, applyErrs[i], &objErr)
+				assert.Equal(t, id, objErr.Identifier)
+			}
 		})
 	}
 }
@@ -543,6 +569,194 @@ func toUnstructureds(rss []resourceInfo) []*unstructured.Unstructured {
 	return objs
 }
 
+func TestApplyTask_Concurrency(t *testing.T) {
+	const concurrency = 3
+	var applied []resourceInfo
+	for i := 0; i < 10; i++ {
+		applied = append(applied, resourceInfo{
+			group:      "apps",
+			apiVersion: "apps/v1",
+			kind:       "Deployment",
+			name:       fmt.Sprintf("deployment-%d", i),
+			namespace:  "default",
+			uid:        types.UID(fmt.Sprintf("uid-%d", i)),
+			generation: int64(1),
+		})
+	}
+	objs := toUnstructureds(applied)
+
+	// The first applies block until the maximum number of applies are in
+	// flight, to make sure they run in parallel.
+	ao := &concurrentApplyOptions{
+		released: make(chan struct{}),
+		barrier:  concurrency,
+	}
+	oldAO := applyOptionsFactoryFunc
+	applyOptionsFactoryFunc = func(string, chan<- event.Event, common.ServerSideOptions, common.DryRunStrategy,
+		dynamic.Interface, discovery.OpenAPISchemaInterface, []event.FieldConflict) applyOptions {
+		return &concurrentApplyOptionsRun{parent: ao}
+	}
+	defer func() { applyOptionsFactoryFunc = oldAO }()
+
+	eventChannel := make(chan event.Event)
+	defer close(eventChannel)
+	taskContext := taskrunner.NewTaskContext(eventChannel, cache.NewResourceCacheMap())
+	applyTask := &ApplyTask{
+		Objects:     objs,
+		InfoHelper:  &fakeInfoHelper{},
+		Concurrency: concurrency,
+	}
+	applyTask.Start(taskContext)
+	result := <-taskContext.TaskChannel()
+	require.NoError(t, result.Err)
+
+	assert.Equal(t, concurrency, ao.maxInFlight)
+	expectedIDs := object.UnstructuredSetToObjMetadataSet(objs)
+	assert.True(t, taskContext.InventoryManager().SuccessfulApplies().Equal(expectedIDs))
+}
+
+// concurrentApplyOptions tracks the applies in flight, for all the
+// concurrentApplyOptionsRun created for a task.
+type concurrentApplyOptions struct {
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+	barrier     int
+	released    chan struct{}
+	releaseOnce sync.Once
+}
+
+type concurrentApplyOptionsRun struct {
+	parent *concurrentApplyOptions
+}
+
+func (f *concurrentApplyOptionsRun) Run() error {
+	p := f.parent
+	p.mu.Lock()
+	p.inFlight++
+	if p.inFlight > p.maxInFlight {
+		p.maxInFlight = p.inFlight
+	}
+	if p.inFlight == p.barrier {
+		p.releaseOnce.Do(func() {
+			close(p.released)
+		})
+	}
+	p.mu.Unlock()
+
+	select {
+	case <-p.released:
+	case <-time.After(5 * time.Second):
+	}
+
+	p.mu.Lock()
+	p.inFlight--
+	p.mu.Unlock()
+	return nil
+}
+
+func (f *concurrentApplyOptionsRun) SetObjects([]*resource.Info) {}
+
+func TestApplyTask_Retry(t *testing.T) {
+	errThrottled := apierrors.NewTooManyRequests("slow down", 0)
+	errInvalid := apierrors.NewBadRequest("invalid")
+	obj := toUnstructured(map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name":      "foo",
+			"namespace": "default",
+		},
+	})
+	id := object.UnstructuredToObjMetadata(obj)
+
+	testCases := map[string]struct {
+		retryPolicy   backoff.RetryPolicy
+		errs          []error
+		expectedCalls int
+		expectFailed  bool
+	}{
+		"no retries by default": {
+			errs:          []error{errThrottled, nil},
+			expectedCalls: 1,
+			expectFailed:  true,
+		},
+		"transient error is retried": {
+			retryPolicy:   backoff.RetryPolicy{MaxAttempts: 3, Backoff: backoff.Constant{}},
+			errs:          []error{errThrottled, errThrottled, nil},
+			expectedCalls: 3,
+		},
+		"retries are exhausted": {
+			retryPolicy:   backoff.RetryPolicy{MaxAttempts: 2, Backoff: backoff.Constant{}},
+			errs:          []error{errThrottled, errThrottled, nil},
+			expectedCalls: 2,
+			expectFailed:  true,
+		},
+		"other error is not retried": {
+			retryPolicy:   backoff.RetryPolicy{MaxAttempts: 3, Backoff: backoff.Constant{}},
+			errs:          []error{errInvalid, nil},
+			expectedCalls: 1,
+			expectFailed:  true,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			ao := &retryApplyOptions{errs: tc.errs}
+			oldAO := applyOptionsFactoryFunc
+			applyOptionsFactoryFunc = func(string, chan<- event.Event, common.ServerSideOptions, common.DryRunStrategy,
+				dynamic.Interface, discovery.OpenAPISchemaInterface, []event.FieldConflict) applyOptions {
+				return ao
+			}
+			defer func() { applyOptionsFactoryFunc = oldAO }()
+
+			eventChannel := make(chan event.Event)
+			taskContext := taskrunner.NewTaskContext(eventChannel, cache.NewResourceCacheMap())
+			applyTask := &ApplyTask{
+				Objects:     object.UnstructuredSet{obj},
+				InfoHelper:  &fakeInfoHelper{},
+				RetryPolicy: tc.retryPolicy,
+			}
+
+			var events []event.Event
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for msg := range eventChannel {
+					events = append(events, msg)
+				}
+			}()
+
+			applyTask.Start(taskContext)
+			<-taskContext.TaskChannel()
+			close(eventChannel)
+			wg.Wait()
+
+			assert.Equal(t, tc.expectedCalls, ao.calls)
+			assert.Equal(t, tc.expectFailed, taskContext.InventoryManager().IsFailedApply(id))
+			if tc.expectFailed {
+				require.Len(t, events, 1)
+				assert.Equal(t, event.ApplyFailed, events[0].ApplyEvent.Status)
+			}
+		})
+	}
+}
+
+// retryApplyOptions returns the next error of errs each time it is run.
+type retryApplyOptions struct {
+	errs  []error
+	calls int
+}
+
+func (f *retryApplyOptions) Run() error {
+	err := f.errs[f.calls]
+	f.calls++
+	return err
+}
+
+func (f *retryApplyOptions) SetObjects([]*resource.Info) {}
+
 type fakeApplyOptions struct {
 	objects       []*resource.Info
 	passedObjects []*resource.Info
@@ -577,3 +791,804 @@ func (f *fakeInfoHelper) BuildInfos(objs []*unstructured.Unstructured) ([]*resou
 func (f *fakeInfoHelper) BuildInfo(obj *unstructured.Unstructured) (*resource.Info, error) {
 	return object.UnstructuredToInfo(obj)
 }
+
+func TestApplyTask_ForcedConflicts(t *testing.T) {
+	conflictErr := &apierrors.StatusError{ErrStatus: metav1.Status{
+		Status: metav1.StatusFailure,
+		Code:   http.StatusConflict,
+		Reason: metav1.StatusReasonConflict,
+		Details: &metav1.StatusDetails{
+			Causes: []metav1.StatusCause{
+				{
+					Type:    metav1.CauseTypeFieldManagerConflict,
+					Message: `conflict with "other-manager": .spec.replicas`,
+					Field:   ".spec.replicas",
+				},
+			},
+		},
+	}}
+
+	testCases := map[string]struct {
+		serverSideOptions common.ServerSideOptions
+		dryRunStrategy    common.DryRunStrategy
+		applyErr          error
+		expectedManager   string
+		expectedConflicts []event.FieldConflict
+	}{
+		"conflicts not forced": {
+			serverSideOptions: common.ServerSideOptions{ServerSideApply: true},
+		},
+		"conflicts not reported": {
+			serverSideOptions: common.ServerSideOptions{ServerSideApply: true, ForceConflicts: true},
+			applyErr:          conflictErr,
+		},
+		"client-side apply": {
+			serverSideOptions: common.ServerSideOptions{ForceConflicts: true, ReportForcedConflicts: true},
+		},
+		"dry-run": {
+			serverSideOptions: common.ServerSideOptions{ServerSideApply: true, ForceConflicts: true, ReportForcedConflicts: true},
+			dryRunStrategy:    common.DryRunServer,
+		},
+		"no conflicts": {
+			serverSideOptions: common.ServerSideOptions{ServerSideApply: true, ForceConflicts: true, ReportForcedConflicts: true, FieldManager: "test"},
+			expectedManager:   "test",
+		},
+		"forced conflicts with default field manager": {
+			serverSideOptions: common.ServerSideOptions{ServerSideApply: true, ForceConflicts: true, ReportForcedConflicts: true},
+			applyErr:          conflictErr,
+			expectedManager:   common.DefaultFieldManager,
+			expectedConflicts: []event.FieldConflict{
+				{
+					Field:   ".spec.replicas",
+					Message: `conflict with "other-manager": .spec.replicas`,
+					Manager: "other-manager",
+				},
+			},
+		},
+		"other errors ignored": {
+			serverSideOptions: common.ServerSideOptions{ServerSideApply: true, ForceConflicts: true, ReportForcedConflicts: true, FieldManager: "test"},
+			applyErr:          apierrors.NewInternalError(fmt.Errorf("internal error")),
+			expectedManager:   "test",
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			obj := toUnstructured(map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata": map[string]interface{}{
+					"name":      "foo",
+					"namespace": "default",
+				},
+			})
+			restMapper := testutil.NewFakeRESTMapper(obj.GroupVersionKind())
+			mapping, err := restMapper.RESTMapping(obj.GroupVersionKind().GroupKind())
+			require.NoError(t, err)
+
+			var applied int
+			dc := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+			dc.PrependReactor("patch", "deployments", func(clienttesting.Action) (bool, runtime.Object, error) {
+				applied++
+				return true, obj, tc.applyErr
+			})
+
+			applyTask := &ApplyTask{
+				DynamicClient:     dc,
+				DryRunStrategy:    tc.dryRunStrategy,
+				ServerSideOptions: tc.serverSideOptions,
+			}
+			info := &resource.Info{Mapping: mapping, Namespace: obj.GetNamespace(), Object: obj}
+			opts := applyTask.serverSideOptions(common.ApplyStrategyDefault)
+			conflicts := applyTask.forcedConflicts(context.Background(), info, opts)
+			assert.Equal(t, tc.expectedConflicts, conflicts)
+			if tc.expectedManager == "" {
+				assert.Equal(t, 0, applied)
+				return
+			}
+			assert.Equal(t, 1, applied)
+			assert.Equal(t, tc.expectedManager, opts.FieldManager)
+		})
+	}
+}
+
+func TestApplyTask_ConflictError(t *testing.T) {
+	conflictErr := &apierrors.StatusError{ErrStatus: metav1.Status{
+		Status:  metav1.StatusFailure,
+		Code:    http.StatusConflict,
+		Reason:  metav1.StatusReasonConflict,
+		Message: `Apply failed with 1 conflict: conflict with "other-manager" using apps/v1: .spec.replicas`,
+		Details: &metav1.StatusDetails{
+			Causes: []metav1.StatusCause{
+				{
+					Type:    metav1.CauseTypeFieldManagerConflict,
+					Message: `conflict with "other-manager" using apps/v1`,
+					Field:   ".spec.replicas",
+				},
+			},
+		},
+	}}
+	// kubectl formats the conflict error as text.
+	kubectlErr := fmt.Errorf("%v\nPlease review the fields above--they currently have other managers.", conflictErr)
+
+	testCases := map[string]struct {
+		serverSideOptions common.ServerSideOptions
+		applyErr          error
+		expectedConflicts []event.FieldConflict
+	}{
+		"client-side apply": {
+			applyErr: kubectlErr,
+		},
+		"conflicts forced": {
+			serverSideOptions: common.ServerSideOptions{ServerSideApply: true, ForceConflicts: true},
+			applyErr:          kubectlErr,
+		},
+		"other error": {
+			serverSideOptions: common.ServerSideOptions{ServerSideApply: true},
+			applyErr:          apierrors.NewInternalError(fmt.Errorf("internal error")),
+		},
+		"kubectl conflict error": {
+			serverSideOptions: common.ServerSideOptions{ServerSideApply: true},
+			applyErr:          kubectlErr,
+			expectedConflicts: []event.FieldConflict{
+				{
+					Field:   ".spec.replicas",
+					Message: `conflict with "other-manager" using apps/v1`,
+					Manager: "other-manager",
+					Value:   int64(3),
+				},
+			},
+		},
+		"conflict status error": {
+			serverSideOptions: common.ServerSideOptions{ServerSideApply: true},
+			applyErr:          conflictErr,
+			expectedConflicts: []event.FieldConflict{
+				{
+					Field:   ".spec.replicas",
+					Message: `conflict with "other-manager" using apps/v1`,
+					Manager: "other-manager",
+					Value:   int64(3),
+				},
+			},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			obj := toUnstructured(map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata": map[string]interface{}{
+					"name":      "foo",
+					"namespace": "default",
+				},
+				"spec": map[string]interface{}{
+					"replicas": int64(1),
+				},
+			})
+			live := obj.DeepCopy()
+			require.NoError(t, unstructured.SetNestedField(live.Object, int64(3), "spec", "replicas"))
+			restMapper := testutil.NewFakeRESTMapper(obj.GroupVersionKind())
+			mapping, err := restMapper.RESTMapping(obj.GroupVersionKind().GroupKind())
+			require.NoError(t, err)
+
+			dc := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), live)
+			dc.PrependReactor("patch", "deployments", func(clienttesting.Action) (bool, runtime.Object, error) {
+				return true, nil, conflictErr
+			})
+
+			applyTask := &ApplyTask{
+				DynamicClient:     dc,
+				ServerSideOptions: tc.serverSideOptions,
+			}
+			info := &resource.Info{Mapping: mapping, Namespace: obj.GetNamespace(), Object: obj}
+			opts := applyTask.serverSideOptions(common.ApplyStrategyDefault)
+			err = applyTask.conflictError(context.Background(), info, opts, tc.applyErr)
+			assert.Equal(t, tc.applyErr.Error(), err.Error())
+			var conflictErr *applyerror.ConflictError
+			if tc.expectedConflicts == nil {
+				assert.False(t, errors.As(err, &conflictErr))
+				return
+			}
+			require.True(t, errors.As(err, &conflictErr))
+			assert.Equal(t, tc.expectedConflicts, conflictErr.Conflicts)
+			assert.ErrorIs(t, err, tc.applyErr)
+		})
+	}
+}
+
+func TestApplyTask_ApplyStrategy(t *testing.T) {
+	newConfigMap := func(data map[string]interface{}) *unstructured.Unstructured {
+		return toUnstructured(map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]interface{}{
+				"name":      "foo",
+				"namespace": "default",
+			},
+			"data": data,
+		})
+	}
+	liveData := map[string]interface{}{"live": "true", "key": "old"}
+	localData := map[string]interface{}{"key": "new"}
+
+	testCases := map[string]struct {
+		strategy       common.ApplyStrategy
+		dryRunStrategy common.DryRunStrategy
+		live           *unstructured.Unstructured
+		expectedData   map[string]interface{}
+		expectSkipped  bool
+	}{
+		"replace existing object": {
+			strategy:     common.ApplyStrategyReplace,
+			live:         newConfigMap(liveData),
+			expectedData: localData,
+		},
+		"replace missing object": {
+			strategy:     common.ApplyStrategyReplace,
+			expectedData: localData,
+		},
+		"replace dry-run": {
+			strategy:       common.ApplyStrategyReplace,
+			dryRunStrategy: common.DryRunClient,
+			live:           newConfigMap(liveData),
+			expectedData:   liveData,
+		},
+		"create-only existing object": {
+			strategy:      common.ApplyStrategyCreateOnly,
+			live:          newConfigMap(liveData),
+			expectedData:  liveData,
+			expectSkipped: true,
+		},
+		"create-only missing object": {
+			strategy:     common.ApplyStrategyCreateOnly,
+			expectedData: localData,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			var liveObjs []runtime.Object
+			if tc.live != nil {
+				liveObjs = append(liveObjs, tc.live)
+			}
+			dc := dynamicfake.NewSimpleDynamicClient(scheme.Scheme, liveObjs...)
+			obj := newConfigMap(localData)
+			restMapper := testutil.NewFakeRESTMapper(obj.GroupVersionKind())
+			mapping, err := restMapper.RESTMapping(obj.GroupVersionKind().GroupKind())
+			require.NoError(t, err)
+			info := &resource.Info{Mapping: mapping, Namespace: "default", Name: "foo", Object: obj}
+
+			applyTask := &ApplyTask{
+				TaskName:       "apply-0",
+				DynamicClient:  dc,
+				DryRunStrategy: tc.dryRunStrategy,
+			}
+			eventChannel := make(chan event.Event, 1)
+			if tc.strategy == common.ApplyStrategyReplace {
+				err = applyTask.replace(context.Background(), info, eventChannel)
+			} else {
+				err = applyTask.createOnly(context.Background(), info, eventChannel)
+			}
+			require.NoError(t, err)
+
+			e := <-eventChannel
+			if tc.expectSkipped {
+				assert.Equal(t, event.ApplySkipped, e.ApplyEvent.Status)
+				assert.Equal(t, event.SkipReasonAlreadyExists, e.ApplyEvent.SkipReason)
+				assert.ErrorIs(t, e.ApplyEvent.Error, &AlreadyExistsError{})
+			} else {
+				assert.Equal(t, event.ApplySuccessful, e.ApplyEvent.Status)
+			}
+			assert.Equal(t, object.UnstructuredToObjMetadata(obj), e.ApplyEvent.Identifier)
+			assert.Equal(t, e.ApplyEvent.Resource, info.Object)
+
+			if tc.live == nil && tc.dryRunStrategy.ClientOrServerDryRun() {
+				return
+			}
+			result, err := dc.Resource(mapping.Resource).Namespace("default").
+				Get(context.Background(), "foo", metav1.GetOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedData, result.Object["data"])
+		})
+	}
+}
+
+func TestApplyTask_Recreate(t *testing.T) {
+	immutableErr := apierrors.NewInvalid(schema.GroupKind{Group: "batch", Kind: "Job"}, "foo",
+		field.ErrorList{field.Invalid(field.NewPath("spec", "selector"), "new", "field is immutable")})
+	newJob := func(annotations map[string]string) *unstructured.Unstructured {
+		u := toUnstructured(map[string]interface{}{
+			"apiVersion": "batch/v1",
+			"kind":       "Job",
+			"metadata": map[string]interface{}{
+				"name":      "foo",
+				"namespace": "default",
+			},
+		})
+		u.SetAnnotations(annotations)
+		return u
+	}
+
+	testCases := map[string]struct {
+		annotations    map[string]string
+		recreate       bool
+		dryRunStrategy common.DryRunStrategy
+		expectRecreate bool
+	}{
+		"not re-created by default": {},
+		"re-created with annotation": {
+			annotations:    map[string]string{common.RecreateAnnotation: "true"},
+			expectRecreate: true,
+		},
+		"re-created with option": {
+			recreate:       true,
+			expectRecreate: true,
+		},
+		"not re-created with annotation false": {
+			annotations: map[string]string{common.RecreateAnnotation: "false"},
+		},
+		"not re-created on dry-run": {
+			recreate:       true,
+			dryRunStrategy: common.DryRunServer,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			obj := newJob(tc.annotations)
+			id := object.UnstructuredToObjMetadata(obj)
+
+			ao := &retryApplyOptions{errs: []error{immutableErr, nil}}
+			oldAO := applyOptionsFactoryFunc
+			applyOptionsFactoryFunc = func(string, chan<- event.Event, common.ServerSideOptions, common.DryRunStrategy,
+				dynamic.Interface, discovery.OpenAPISchemaInterface, []event.FieldConflict) applyOptions {
+				return ao
+			}
+			defer func() { applyOptionsFactoryFunc = oldAO }()
+			oldInterval := recreatePollInterval
+			recreatePollInterval = time.Millisecond
+			defer func() { recreatePollInterval = oldInterval }()
+
+			dc := dynamicfake.NewSimpleDynamicClient(scheme.Scheme, newJob(nil))
+			var deleted int
+			dc.PrependReactor("delete", "jobs", func(clienttesting.Action) (bool, runtime.Object, error) {
+				deleted++
+				return false, nil, nil
+			})
+
+			eventChannel := make(chan event.Event)
+			taskContext := taskrunner.NewTaskContext(eventChannel, cache.NewResourceCacheMap())
+			applyTask := &ApplyTask{
+				Objects:                        object.UnstructuredSet{obj},
+				InfoHelper:                     &recreateInfoHelper{mapper: testutil.NewFakeRESTMapper(obj.GroupVersionKind())},
+				DynamicClient:                  dc,
+				DryRunStrategy:                 tc.dryRunStrategy,
+				RecreateOnImmutableFieldChange: tc.recreate,
+			}
+
+			var events []event.Event
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for msg := range eventChannel {
+					events = append(events, msg)
+				}
+			}()
+
+			applyTask.Start(taskContext)
+			<-taskContext.TaskChannel()
+			close(eventChannel)
+			wg.Wait()
+
+			if tc.expectRecreate {
+				assert.Equal(t, 1, deleted)
+				assert.Equal(t, 2, ao.calls)
+				assert.False(t, taskContext.InventoryManager().IsFailedApply(id))
+				assert.Empty(t, events)
+				return
+			}
+			assert.Equal(t, 0, deleted)
+			assert.Equal(t, 1, ao.calls)
+			assert.True(t, taskContext.InventoryManager().IsFailedApply(id))
+			require.Len(t, events, 1)
+			var immutableFieldErr *applyerror.ImmutableFieldError
+			assert.True(t, errors.As(events[0].ApplyEvent.Error, &immutableFieldErr))
+		})
+	}
+}
+
+// recreateInfoHelper builds infos with the mapping of the object, so the
+// task can access the object with the dynamic client.
+type recreateInfoHelper struct {
+	fakeInfoHelper
+	mapper meta.RESTMapper
+}
+
+func (f *recreateInfoHelper) BuildInfo(obj *unstructured.Unstructured) (*resource.Info, error) {
+	info, err := object.UnstructuredToInfo(obj)
+	if err != nil {
+		return nil, err
+	}
+	info.Mapping, err = f.mapper.RESTMapping(obj.GroupVersionKind().GroupKind())
+	return info, err
+}
+
+func TestIsImmutableFieldError(t *testing.T) {
+	testCases := map[string]struct {
+		err      error
+		expected bool
+	}{
+		"immutable field": {
+			err: apierrors.NewInvalid(schema.GroupKind{Kind: "Service"}, "foo",
+				field.ErrorList{field.Invalid(field.NewPath("spec", "clusterIP"), "10.0.0.1", "field is immutable")}),
+			expected: true,
+		},
+		"wrapped immutable field": {
+			err: fmt.Errorf("error when applying patch: %w", apierrors.NewInvalid(schema.GroupKind{Kind: "Service"}, "foo",
+				field.ErrorList{field.Invalid(field.NewPath("spec", "clusterIP"), "10.0.0.1", "field is immutable")})),
+			expected: true,
+		},
+		"other invalid field": {
+			err: apierrors.NewInvalid(schema.GroupKind{Kind: "Service"}, "foo",
+				field.ErrorList{field.Required(field.NewPath("spec", "ports"), "")}),
+		},
+		"other error": {
+			err: apierrors.NewBadRequest("field is immutable"),
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			assert.Equal(t, tc.expected, applyerror.IsImmutableFieldError(tc.err))
+		})
+	}
+}
+
+func TestApplyTask_TypedApply(t *testing.T) {
+	newObj := func(apiVersion, kind string) *unstructured.Unstructured {
+		return toUnstructured(map[string]interface{}{
+			"apiVersion": apiVersion,
+			"kind":       kind,
+			"metadata": map[string]interface{}{
+				"name":      "foo",
+				"namespace": "default",
+			},
+		})
+	}
+
+	testCases := map[string]struct {
+		obj               *unstructured.Unstructured
+		serverSideOptions common.ServerSideOptions
+		dryRunStrategy    common.DryRunStrategy
+		expectTyped       bool
+		expectedOptions   []string
+	}{
+		"typed kind": {
+			obj:               newObj("v1", "ConfigMap"),
+			serverSideOptions: common.ServerSideOptions{ServerSideApply: true, FieldManager: "test"},
+			expectTyped:       true,
+			expectedOptions:   []string{"fieldManager=test"},
+		},
+		"typed kind with force conflicts and server dry-run": {
+			obj:               newObj("v1", "ConfigMap"),
+			serverSideOptions: common.ServerSideOptions{ServerSideApply: true, ForceConflicts: true, FieldManager: "test"},
+			dryRunStrategy:    common.DryRunServer,
+			expectTyped:       true,
+			expectedOptions:   []string{"fieldManager=test", "force", "dryRun"},
+		},
+		"other kind": {
+			obj:               newObj("apps/v1", "Deployment"),
+			serverSideOptions: common.ServerSideOptions{ServerSideApply: true, FieldManager: "test"},
+		},
+		"client-side apply": {
+			obj: newObj("v1", "ConfigMap"),
+		},
+		"client dry-run": {
+			obj:               newObj("v1", "ConfigMap"),
+			serverSideOptions: common.ServerSideOptions{ServerSideApply: true, FieldManager: "test"},
+			dryRunStrategy:    common.DryRunClient,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			ao := &retryApplyOptions{errs: []error{nil}}
+			oldAO := applyOptionsFactoryFunc
+			applyOptionsFactoryFunc = func(string, chan<- event.Event, common.ServerSideOptions, common.DryRunStrategy,
+				dynamic.Interface, discovery.OpenAPISchemaInterface, []event.FieldConflict) applyOptions {
+				return ao
+			}
+			defer func() { applyOptionsFactoryFunc = oldAO }()
+
+			var patched []string
+			typedClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithInterceptorFuncs(interceptor.Funcs{
+				Patch: func(_ context.Context, _ client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+					assert.Equal(t, types.ApplyPatchType, patch.Type())
+					patchOpts := &client.PatchOptions{}
+					patchOpts.ApplyOptions(opts)
+					patched = append(patched, fmt.Sprintf("%T", obj), "fieldManager="+patchOpts.FieldManager)
+					if patchOpts.Force != nil && *patchOpts.Force {
+						patched = append(patched, "force")
+					}
+					if len(patchOpts.DryRun) > 0 {
+						patched = append(patched, "dryRun")
+					}
+					obj.SetUID("typed-uid")
+					return nil
+				},
+			}).Build()
+
+			applyTask := &ApplyTask{
+				TaskName:          "apply-0",
+				ServerSideOptions: tc.serverSideOptions,
+				DryRunStrategy:    tc.dryRunStrategy,
+				TypedClient:       typedClient,
+				TypedKinds:        []schema.GroupKind{{Kind: "ConfigMap"}},
+			}
+			info, err := object.UnstructuredToInfo(tc.obj)
+			require.NoError(t, err)
+			eventChannel := make(chan event.Event, 1)
+			err = applyTask.apply(context.Background(), info, common.ApplyStrategyDefault, eventChannel)
+			require.NoError(t, err)
+
+			if !tc.expectTyped {
+				assert.Empty(t, patched)
+				assert.Equal(t, 1, ao.calls)
+				return
+			}
+			assert.Equal(t, 0, ao.calls)
+			assert.Equal(t, append([]string{"*v1.ConfigMap"}, tc.expectedOptions...), patched)
+			e := <-eventChannel
+			assert.Equal(t, event.ApplySuccessful, e.ApplyEvent.Status)
+			assert.Equal(t, object.UnstructuredToObjMetadata(tc.obj), e.ApplyEvent.Identifier)
+			assert.Equal(t, tc.obj.GroupVersionKind(), e.ApplyEvent.Resource.GroupVersionKind())
+			assert.Equal(t, types.UID("typed-uid"), e.ApplyEvent.Resource.GetUID())
+		})
+	}
+}
+
+func TestApplyTask_SkipUnchanged(t *testing.T) {
+	newConfigMap := func(data string) *unstructured.Unstructured {
+		return toUnstructured(map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]interface{}{
+				"name":      "foo",
+				"namespace": "default",
+			},
+			"data": map[string]interface{}{"key": data},
+		})
+	}
+	withHash := func(obj *unstructured.Unstructured, hash string) *unstructured.Unstructured {
+		obj.SetAnnotations(map[string]string{common.AppliedHashAnnotation: hash})
+		obj.SetUID("live-uid")
+		return obj
+	}
+	hash, err := AppliedHash(newConfigMap("value"))
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		live              *unstructured.Unstructured
+		dryRunStrategy    common.DryRunStrategy
+		skipUnchangedWait bool
+		expectSkipped     bool
+		expectUnchanged   bool
+	}{
+		"missing object is applied": {},
+		"changed object is applied": {
+			live: withHash(newConfigMap("old"), "old-hash"),
+		},
+		"object without hash is applied": {
+			live: newConfigMap("value"),
+		},
+		"unchanged object is skipped": {
+			live:          withHash(newConfigMap("value"), hash),
+			expectSkipped: true,
+		},
+		"unchanged current object is not waited on": {
+			live:              withHash(newConfigMap("value"), hash),
+			skipUnchangedWait: true,
+			expectSkipped:     true,
+			expectUnchanged:   true,
+		},
+		"dry-run is applied": {
+			live:           withHash(newConfigMap("value"), hash),
+			dryRunStrategy: common.DryRunServer,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			obj := newConfigMap("value")
+			id := object.UnstructuredToObjMetadata(obj)
+
+			ao := &retryApplyOptions{errs: []error{nil}}
+			oldAO := applyOptionsFactoryFunc
+			applyOptionsFactoryFunc = func(string, chan<- event.Event, common.ServerSideOptions, common.DryRunStrategy,
+				dynamic.Interface, discovery.OpenAPISchemaInterface, []event.FieldConflict) applyOptions {
+				return ao
+			}
+			defer func() { applyOptionsFactoryFunc = oldAO }()
+
+			var liveObjs []runtime.Object
+			if tc.live != nil {
+				liveObjs = append(liveObjs, tc.live)
+			}
+			eventChannel := make(chan event.Event)
+			taskContext := taskrunner.NewTaskContext(eventChannel, cache.NewResourceCacheMap())
+			applyTask := &ApplyTask{
+				Objects:           object.UnstructuredSet{obj},
+				InfoHelper:        &recreateInfoHelper{mapper: testutil.NewFakeRESTMapper(obj.GroupVersionKind())},
+				DynamicClient:     dynamicfake.NewSimpleDynamicClient(scheme.Scheme, liveObjs...),
+				DryRunStrategy:    tc.dryRunStrategy,
+				SkipUnchanged:     true,
+				SkipUnchangedWait: tc.skipUnchangedWait,
+			}
+
+			var events []event.Event
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for msg := range eventChannel {
+					events = append(events, msg)
+				}
+			}()
+
+			applyTask.Start(taskContext)
+			<-taskContext.TaskChannel()
+			close(eventChannel)
+			wg.Wait()
+
+			assert.True(t, taskContext.InventoryManager().IsSuccessfulApply(id))
+			assert.Equal(t, tc.expectUnchanged, taskContext.IsUnchangedObject(id))
+			if !tc.expectSkipped {
+				assert.Equal(t, 1, ao.calls)
+				assert.Empty(t, events)
+				return
+			}
+			assert.Equal(t, 0, ao.calls)
+			require.Len(t, events, 1)
+			assert.Equal(t, event.ApplySuccessful, events[0].ApplyEvent.Status)
+			assert.Equal(t, types.UID("live-uid"), events[0].ApplyEvent.Resource.GetUID())
+			uid, _ := taskContext.InventoryManager().AppliedResourceUID(id)
+			assert.Equal(t, types.UID("live-uid"), uid)
+		})
+	}
+}
+
+func TestAppliedHash(t *testing.T) {
+	obj := toUnstructured(map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name":      "foo",
+			"namespace": "default",
+		},
+		"data": map[string]interface{}{"a": "1", "b": "2"},
+	})
+	hash, err := AppliedHash(obj)
+	require.NoError(t, err)
+
+	// The hash and run ID annotations are ignored.
+	annotated := obj.DeepCopy()
+	annotated.SetAnnotations(map[string]string{
+		common.AppliedHashAnnotation: hash,
+		common.RunIDAnnotation:       "run-1",
+	})
+	annotatedHash, err := AppliedHash(annotated)
+	require.NoError(t, err)
+	assert.Equal(t, hash, annotatedHash)
+
+	changed := obj.DeepCopy()
+	changed.Object["data"] = map[string]interface{}{"a": "1", "b": "3"}
+	changedHash, err := AppliedHash(changed)
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, changedHash)
+}
+
+func TestApplyTask_RefreshKinds(t *testing.T) {
+	obj := testutil.Unstructured(t, `
+apiVersion: custom.io/v1
+kind: Custom
+metadata:
+  name: foo
+  namespace: default
+`)
+	id := object.UnstructuredToObjMetadata(obj)
+
+	testCases := map[string]struct {
+		servedAfter    int
+		kindTimeout    time.Duration
+		expectedResets int
+		expectFailed   bool
+	}{
+		"kind served": {
+			servedAfter:    0,
+			kindTimeout:    time.Minute,
+			expectedResets: 1,
+		},
+		"kind served after resets": {
+			servedAfter:    3,
+			kindTimeout:    time.Minute,
+			expectedResets: 3,
+		},
+		"kind not served without timeout": {
+			servedAfter:    3,
+			expectedResets: 1,
+			expectFailed:   true,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			oldAO := applyOptionsFactoryFunc
+			applyOptionsFactoryFunc = func(string, chan<- event.Event, common.ServerSideOptions, common.DryRunStrategy,
+				dynamic.Interface, discovery.OpenAPISchemaInterface, []event.FieldConflict) applyOptions {
+				return &fakeApplyOptions{}
+			}
+			defer func() { applyOptionsFactoryFunc = oldAO }()
+			oldInterval := kindPollInterval
+			kindPollInterval = time.Millisecond
+			defer func() { kindPollInterval = oldInterval }()
+
+			mapper := &resettableRESTMapper{
+				RESTMapper:  testutil.NewFakeRESTMapper(),
+				gvk:         obj.GroupVersionKind(),
+				servedAfter: tc.servedAfter,
+			}
+			eventChannel := make(chan event.Event)
+			taskContext := taskrunner.NewTaskContext(eventChannel, cache.NewResourceCacheMap())
+			applyTask := &ApplyTask{
+				Objects:      object.UnstructuredSet{obj},
+				Mapper:       mapper,
+				InfoHelper:   &recreateInfoHelper{mapper: mapper},
+				RefreshKinds: true,
+				KindTimeout:  tc.kindTimeout,
+			}
+
+			var events []event.Event
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for msg := range eventChannel {
+					events = append(events, msg)
+				}
+			}()
+
+			applyTask.Start(taskContext)
+			<-taskContext.TaskChannel()
+			close(eventChannel)
+			wg.Wait()
+
+			assert.Equal(t, tc.expectedResets, mapper.resets)
+			if !tc.expectFailed {
+				assert.True(t, taskContext.InventoryManager().IsSuccessfulApply(id))
+				return
+			}
+			assert.True(t, taskContext.InventoryManager().IsFailedApply(id))
+			require.Len(t, events, 1)
+			var unknownTypeErr *applyerror.UnknownTypeError
+			assert.True(t, errors.As(events[0].ApplyEvent.Error, &unknownTypeErr))
+		})
+	}
+}
+
+// resettableRESTMapper serves the kind once it has been reset servedAfter
+// times, like when a CRD is installed while waiting.
+type resettableRESTMapper struct {
+	meta.RESTMapper
+	gvk         schema.GroupVersionKind
+	servedAfter int
+	resets      int
+}
+
+func (m *resettableRESTMapper) Reset() {
+	m.resets++
+	if m.resets >= m.servedAfter {
+		m.RESTMapper = testutil.NewFakeRESTMapper(m.gvk)
+	}
+}
@@ -132,3 +132,62 @@ func TestDeleteInvTask(t *testing.T) {
 		})
 	}
 }
+
+func TestDeleteInvTask_KeepInventory(t *testing.T) {
+	id1 := object.UnstructuredToObjMetadata(obj1)
+	id2 := object.UnstructuredToObjMetadata(obj2)
+	testCases := map[string]struct {
+		keepInventory bool
+		skippedDelete object.ObjMetadataSet
+		expectedObjs  object.ObjMetadataSet
+	}{
+		"inventory is deleted by default": {
+			keepInventory: false,
+			// The fake client does not clear the objects on deletion
+			expectedObjs: object.ObjMetadataSet{id1, id2},
+		},
+		"inventory is emptied instead of deleted": {
+			keepInventory: true,
+			expectedObjs:  object.ObjMetadataSet{},
+		},
+		"inventory retains skipped objects": {
+			keepInventory: true,
+			skippedDelete: object.ObjMetadataSet{id2},
+			expectedObjs:  object.ObjMetadataSet{id2},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			prevInventory := object.ObjMetadataSet{id1, id2}
+			client := inventory.NewFakeClient(prevInventory)
+			eventChannel := make(chan event.Event)
+			resourceCache := cache.NewResourceCacheMap()
+			context := taskrunner.NewTaskContext(eventChannel, resourceCache)
+			im := context.InventoryManager()
+			for _, id := range prevInventory.Diff(tc.skippedDelete) {
+				im.AddSuccessfulDelete(id, "unused-uid")
+			}
+			for _, id := range tc.skippedDelete {
+				im.AddSkippedDelete(id)
+			}
+
+			task := DeleteOrUpdateInvTask{
+				TaskName:      taskName,
+				InvClient:     client,
+				DryRun:        common.DryRunNone,
+				PrevInventory: prevInventory,
+				Destroy:       true,
+				KeepInventory: tc.keepInventory,
+			}
+			task.Start(context)
+			result := <-context.TaskChannel()
+			if result.Err != nil {
+				t.Errorf("unexpected error running DeleteOrUpdateInvTask: %s", result.Err)
+			}
+			actual, _ := client.GetClusterObjs(nil)
+			testutil.AssertEqual(t, tc.expectedObjs, actual,
+				"Actual cluster objects (%d) do not match expected cluster objects (%d)",
+				len(actual), len(tc.expectedObjs))
+		})
+	}
+}
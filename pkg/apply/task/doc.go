@@ -0,0 +1,24 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package task contains the tasks executed by the Applier and the Destroyer.
+// The tasks can also be composed into custom pipelines, and run with a
+// taskrunner.TaskStatusRunner, like applying objects, verifying them with a
+// FuncTask, then pruning:
+//
+//	queue := solver.NewTaskQueue(
+//		&task.InvAddTask{TaskName: "inventory-add-0", ...},
+//		&task.ApplyTask{TaskName: "apply-0", ...},
+//		taskrunner.NewWaitTask("wait-0", ids, taskrunner.AllCurrent, timeout, mapper),
+//		&task.FuncTask{TaskName: "verify-0", TaskAction: event.WaitAction, Func: verify},
+//		&task.PruneTask{TaskName: "prune-0", ...},
+//		&task.DeleteOrUpdateInvTask{TaskName: "inventory-set-0", ...},
+//	)
+//	taskContext := taskrunner.NewTaskContext(eventChannel, cache.NewResourceCacheMap())
+//	runner := taskrunner.NewTaskStatusRunner(ids, statusWatcher)
+//	err := runner.Run(ctx, taskContext, queue.ToChannel(), taskrunner.Options{})
+//
+// The tasks communicate through the TaskContext: the apply and prune tasks
+// record the actuation of each object in its inventory manager, which the
+// wait tasks and the inventory tasks read.
+package task
@@ -0,0 +1,54 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package task
+
+import (
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
+	"sigs.k8s.io/cli-utils/pkg/apply/taskrunner"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+// FuncTask is a task that calls a function, to add custom steps to a
+// pipeline of tasks, like verifying the applied objects before pruning.
+// The error returned by the function, if any, stops the pipeline.
+type FuncTask struct {
+	TaskName string
+	// TaskAction is the action reported for the task in the action group
+	// events, like event.WaitAction for a verification step.
+	TaskAction event.ResourceAction
+	// TaskIdentifiers are the objects the task acts on, if any.
+	TaskIdentifiers object.ObjMetadataSet
+	// Func is called in a separate goroutine. It may send events with the
+	// SendEvent method of the task context.
+	Func func(taskContext *taskrunner.TaskContext) error
+}
+
+func (f *FuncTask) Name() string {
+	return f.TaskName
+}
+
+func (f *FuncTask) Action() event.ResourceAction {
+	return f.TaskAction
+}
+
+func (f *FuncTask) Identifiers() object.ObjMetadataSet {
+	return f.TaskIdentifiers
+}
+
+// Start calls the function in a separate goroutine, and sends its error as
+// the task result.
+func (f *FuncTask) Start(taskContext *taskrunner.TaskContext) {
+	go func() {
+		klog.V(2).Infof("func task starting (name: %q)", f.Name())
+		err := f.Func(taskContext)
+		taskContext.TaskChannel() <- taskrunner.TaskResult{Err: err}
+	}()
+}
+
+// Cancel is not supported by the FuncTask.
+func (f *FuncTask) Cancel(_ *taskrunner.TaskContext) {}
+
+// StatusUpdate is not supported by the FuncTask.
+func (f *FuncTask) StatusUpdate(_ *taskrunner.TaskContext, _ object.ObjMetadata) {}
@@ -23,6 +23,9 @@ type DeleteOrUpdateInvTask struct {
 	DryRun        common.DryRunStrategy
 	// if Destroy is set, the inventory will be deleted if all objects were successfully pruned
 	Destroy bool
+	// if KeepInventory is set, the inventory will be updated instead of
+	// deleted, even if all objects were successfully pruned
+	KeepInventory bool
 }
 
 func (i *DeleteOrUpdateInvTask) Name() string {
@@ -41,14 +44,17 @@ func (i *DeleteOrUpdateInvTask) Identifiers() object.ObjMetadataSet {
 // Destroy.
 //
 // If Destroy is set, the intent is to delete the inventory. The inventory will
-// only be deleted if all prunes were successful (none failed/skipped). If any
-// prunes were failed or skipped, the inventory will be updated.
+// only be deleted if no prunes failed, and no pruned objects failed or timed
+// out reconciling. Skipped prunes do not keep the inventory: the skipped
+// objects, e.g. abandoned objects or the retained inventory namespace, are
+// left behind with the inventory deleted. If any prunes failed, or if
+// KeepInventory is set, the inventory will be updated.
 //
 // If Destroy is false, the inventory will be updated.
 func (i *DeleteOrUpdateInvTask) Start(taskContext *taskrunner.TaskContext) {
 	go func() {
 		var err error
-		if i.Destroy && i.destroySuccessful(taskContext) {
+		if i.Destroy && !i.KeepInventory && i.destroySuccessful(taskContext) {
 			err = i.deleteInventory()
 		} else {
 			err = i.updateInventory(taskContext)
@@ -177,7 +183,8 @@ func (i *DeleteOrUpdateInvTask) deleteInventory() error {
 }
 
 // destroySuccessful returns true when destroy actuation and reconciliation was
-// fully successful. When true, it's safe to delete the inventory.
+// fully successful. When true, it's safe to delete the inventory. Skipped
+// deletes are not failures.
 func (i *DeleteOrUpdateInvTask) destroySuccessful(taskContext *taskrunner.TaskContext) bool {
 	// if any deletes failed, the Destroy is considered failed
 	if len(taskContext.InventoryManager().FailedDeletes()) > 0 {
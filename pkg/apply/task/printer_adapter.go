@@ -22,14 +22,17 @@ import (
 type KubectlPrinterAdapter struct {
 	ch        chan<- event.Event
 	groupName string
+	// forcedConflicts are added to successful apply events.
+	forcedConflicts []event.FieldConflict
 }
 
 // resourcePrinterImpl implements the ResourcePrinter interface. But
 // instead of printing, it emits information on the provided channel.
 type resourcePrinterImpl struct {
-	applyStatus event.ApplyEventStatus
-	ch          chan<- event.Event
-	groupName   string
+	applyStatus     event.ApplyEventStatus
+	ch              chan<- event.Event
+	groupName       string
+	forcedConflicts []event.FieldConflict
 }
 
 // PrintObj takes the provided object and operation and emits
@@ -39,13 +42,18 @@ func (r *resourcePrinterImpl) PrintObj(obj runtime.Object, _ io.Writer) error {
 	if err != nil {
 		return err
 	}
+	var forcedConflicts []event.FieldConflict
+	if r.applyStatus == event.ApplySuccessful {
+		forcedConflicts = r.forcedConflicts
+	}
 	r.ch <- event.Event{
 		Type: event.ApplyType,
 		ApplyEvent: event.ApplyEvent{
-			GroupName:  r.groupName,
-			Identifier: id,
-			Status:     r.applyStatus,
-			Resource:   obj.(*unstructured.Unstructured),
+			GroupName:       r.groupName,
+			Identifier:      id,
+			Status:          r.applyStatus,
+			Resource:        obj.(*unstructured.Unstructured),
+			ForcedConflicts: forcedConflicts,
 		},
 	}
 	return nil
@@ -59,9 +67,10 @@ func (p *KubectlPrinterAdapter) toPrinterFunc() toPrinterFunc {
 	return func(operation string) (printers.ResourcePrinter, error) {
 		applyStatus, err := kubectlOperationToApplyStatus(operation)
 		return &resourcePrinterImpl{
-			ch:          p.ch,
-			applyStatus: applyStatus,
-			groupName:   p.groupName,
+			ch:              p.ch,
+			applyStatus:     applyStatus,
+			groupName:       p.groupName,
+			forcedConflicts: p.forcedConflicts,
 		}, err
 	}
 }
@@ -5,6 +5,7 @@ package task
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/cli-utils/pkg/apply/event"
 	"sigs.k8s.io/cli-utils/pkg/apply/filter"
@@ -25,9 +26,22 @@ type PruneTask struct {
 	Filters           []filter.ValidationFilter
 	DryRunStrategy    common.DryRunStrategy
 	PropagationPolicy metav1.DeletionPropagation
+	// GracePeriodSeconds is the grace period of the deletions, or nil to use
+	// the default grace period of each object.
+	GracePeriodSeconds *int64
 	// True if we are destroying, which deletes the inventory object
 	// as well (possibly) the inventory namespace.
 	Destroy bool
+	// DeleteCollectionThreshold is the minimum number of objects with the
+	// same GroupKind and namespace that are deleted with a single request.
+	// Zero disables the batching.
+	DeleteCollectionThreshold int
+	// ListDependents defines whether the dependents of the objects are
+	// listed and reported on their events.
+	ListDependents bool
+	// DependentKinds are the kinds of the listed dependents, or nil for
+	// prune.DefaultDependentKinds.
+	DependentKinds []schema.GroupKind
 }
 
 func (p *PruneTask) Name() string {
@@ -66,9 +80,14 @@ func (p *PruneTask) Start(taskContext *taskrunner.TaskContext) {
 			taskContext,
 			p.Name(),
 			prune.Options{
-				DryRunStrategy:    p.DryRunStrategy,
-				PropagationPolicy: p.PropagationPolicy,
-				Destroy:           p.Destroy,
+				DryRunStrategy:     p.DryRunStrategy,
+				PropagationPolicy:  p.PropagationPolicy,
+				GracePeriodSeconds: p.GracePeriodSeconds,
+				Destroy:            p.Destroy,
+
+				DeleteCollectionThreshold: p.DeleteCollectionThreshold,
+				ListDependents:            p.ListDependents,
+				DependentKinds:            p.DependentKinds,
 			},
 		)
 		klog.V(2).Infof("prune task completing (name: %q)", p.Name())
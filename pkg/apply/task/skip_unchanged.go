@@ -0,0 +1,88 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package task
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
+	"sigs.k8s.io/cli-utils/pkg/common"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/status"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+// skipUnchanged sets the applied hash annotation on the object, and returns
+// true if the live object has the same hash, in which case the object is not
+// applied. A successful apply event is sent with the live object instead.
+//
+// Reading the live object is cheaper for the server than applying it, but
+// changes made to the live object by others are not detected, unless they
+// also change the annotation.
+func (a *ApplyTask) skipUnchanged(ctx context.Context, info *resource.Info,
+	eventChannel chan<- event.Event) (bool, error) {
+	obj := info.Object.(*unstructured.Unstructured)
+	hash, err := AppliedHash(obj)
+	if err != nil {
+		return false, err
+	}
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[common.AppliedHashAnnotation] = hash
+	obj.SetAnnotations(annotations)
+
+	if info.Mapping == nil {
+		return false, nil
+	}
+	live, err := a.resourceClient(info).Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if live.GetAnnotations()[common.AppliedHashAnnotation] != hash || live.GetDeletionTimestamp() != nil {
+		return false, nil
+	}
+	klog.V(4).Infof("apply skipped unchanged object (object: %s, hash: %s)",
+		object.UnstructuredToObjMetadata(obj), hash)
+	return true, a.sendApplySuccessfulEvent(info, live, eventChannel)
+}
+
+// isCurrent returns true if the computed status of the live object is
+// Current. Objects whose status can not be computed are not Current.
+func isCurrent(live *unstructured.Unstructured) bool {
+	result, err := status.Compute(live)
+	return err == nil && result.Status == status.CurrentStatus
+}
+
+// AppliedHash returns the hash of the object, to detect changes to the
+// configuration of the object between applies. The applied hash and run ID
+// annotations are excluded, so that the hash only changes with the
+// configuration.
+func AppliedHash(obj *unstructured.Unstructured) (string, error) {
+	obj = obj.DeepCopy()
+	annotations := obj.GetAnnotations()
+	delete(annotations, common.AppliedHashAnnotation)
+	delete(annotations, common.RunIDAnnotation)
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	obj.SetAnnotations(annotations)
+	// Encoding maps as JSON sorts their keys, so the hash is deterministic.
+	data, err := obj.MarshalJSON()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package task
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
+	"sigs.k8s.io/cli-utils/pkg/apply/filter"
+	"sigs.k8s.io/cli-utils/pkg/apply/prune"
+	"sigs.k8s.io/cli-utils/pkg/apply/taskrunner"
+	"sigs.k8s.io/cli-utils/pkg/common"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+// TestCleanupTask deletes the test resources applied earlier in the same
+// run, whether they succeeded, failed or timed out. Unlike the PruneTask, it
+// deletes objects that were just applied. Objects that were not applied are
+// skipped.
+type TestCleanupTask struct {
+	TaskName string
+
+	Pruner            *prune.Pruner
+	Objects           object.UnstructuredSet
+	Filters           []filter.ValidationFilter
+	DryRunStrategy    common.DryRunStrategy
+	PropagationPolicy metav1.DeletionPropagation
+	// GracePeriodSeconds is the grace period of the deletions, or nil to use
+	// the default grace period of each object.
+	GracePeriodSeconds *int64
+}
+
+func (c *TestCleanupTask) Name() string {
+	return c.TaskName
+}
+
+func (c *TestCleanupTask) Action() event.ResourceAction {
+	return event.PruneAction
+}
+
+func (c *TestCleanupTask) Identifiers() object.ObjMetadataSet {
+	return object.UnstructuredSetToObjMetadataSet(c.Objects)
+}
+
+// Start deletes the objects with the UID they were applied with, in a new
+// goroutine, and pushes a TaskResult on the taskChannel when done.
+func (c *TestCleanupTask) Start(taskContext *taskrunner.TaskContext) {
+	go func() {
+		klog.V(2).Infof("test cleanup task starting (name: %q, objects: %d)",
+			c.Name(), len(c.Objects))
+		eventFactory := prune.CreateEventFactory(false, c.Name())
+		var objs object.UnstructuredSet
+		for _, obj := range c.Objects {
+			id := object.UnstructuredToObjMetadata(obj)
+			uid, found := taskContext.InventoryManager().AppliedResourceUID(id)
+			if !found || uid == "" {
+				taskContext.SendEvent(eventFactory.CreateSkippedEvent(obj, &NotAppliedError{}))
+				taskContext.InventoryManager().AddSkippedDelete(id)
+				continue
+			}
+			obj = obj.DeepCopy()
+			obj.SetUID(uid)
+			objs = append(objs, obj)
+		}
+		err := c.Pruner.Prune(
+			objs,
+			c.Filters,
+			taskContext,
+			c.Name(),
+			prune.Options{
+				DryRunStrategy:     c.DryRunStrategy,
+				PropagationPolicy:  c.PropagationPolicy,
+				GracePeriodSeconds: c.GracePeriodSeconds,
+			},
+		)
+		klog.V(2).Infof("test cleanup task completing (name: %q)", c.Name())
+		taskContext.TaskChannel() <- taskrunner.TaskResult{
+			Err: err,
+		}
+	}()
+}
+
+// Cancel is not supported by the TestCleanupTask.
+func (c *TestCleanupTask) Cancel(_ *taskrunner.TaskContext) {}
+
+// StatusUpdate is not supported by the TestCleanupTask.
+func (c *TestCleanupTask) StatusUpdate(_ *taskrunner.TaskContext, _ object.ObjMetadata) {}
+
+// NotAppliedError is the reason the deletion of a test resource is skipped,
+// if it was not applied.
+type NotAppliedError struct{}
+
+func (e *NotAppliedError) Error() string {
+	return "not applied"
+}
+
+func (e *NotAppliedError) Is(err error) bool {
+	_, ok := err.(*NotAppliedError)
+	return ok
+}
+
+// SkipReason returns the reason code of the skipped events.
+func (e *NotAppliedError) SkipReason() event.SkipReason {
+	return event.SkipReasonNotApplied
+}
@@ -0,0 +1,107 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package taskrunner
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// CircuitBreaker tracks consecutive server errors returned by the cluster
+// and trips when a threshold is reached, so that tasks can stop early instead
+// of attempting to actuate every remaining object against an unhealthy
+// apiserver.
+//
+// A nil CircuitBreaker is valid and never trips.
+type CircuitBreaker struct {
+	// Threshold is the number of consecutive server errors after which the
+	// breaker trips. A Threshold less than 1 never trips.
+	Threshold int
+
+	mu          sync.Mutex
+	consecutive int
+	lastErr     error
+}
+
+// NewCircuitBreaker returns a new CircuitBreaker that trips after the
+// specified number of consecutive server errors.
+func NewCircuitBreaker(threshold int) *CircuitBreaker {
+	return &CircuitBreaker{Threshold: threshold}
+}
+
+// Record registers the result of a request to the cluster. Server errors
+// (5xx) increment the failure count. Any other result, including client
+// errors, resets it, because the apiserver responded normally.
+func (cb *CircuitBreaker) Record(err error) {
+	if cb == nil {
+		return
+	}
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if isServerError(err) {
+		cb.consecutive++
+		cb.lastErr = err
+		return
+	}
+	cb.consecutive = 0
+	cb.lastErr = nil
+}
+
+// Tripped returns true if the number of consecutive server errors has
+// reached the threshold.
+func (cb *CircuitBreaker) Tripped() bool {
+	if cb == nil {
+		return false
+	}
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.Threshold > 0 && cb.consecutive >= cb.Threshold
+}
+
+// Err returns a CircuitBreakerError if the breaker has tripped, otherwise nil.
+func (cb *CircuitBreaker) Err() error {
+	if !cb.Tripped() {
+		return nil
+	}
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return &CircuitBreakerError{
+		Failures: cb.consecutive,
+		LastErr:  cb.lastErr,
+	}
+}
+
+// CircuitBreakerError is returned when a task is aborted because the
+// CircuitBreaker tripped.
+type CircuitBreakerError struct {
+	// Failures is the number of consecutive server errors observed.
+	Failures int
+	// LastErr is the most recent server error.
+	LastErr error
+}
+
+func (e *CircuitBreakerError) Error() string {
+	return fmt.Sprintf("circuit breaker tripped after %d consecutive server errors: %v",
+		e.Failures, e.LastErr)
+}
+
+func (e *CircuitBreakerError) Unwrap() error {
+	return e.LastErr
+}
+
+// isServerError returns true if the error is an API error with a 5xx status
+// code.
+func isServerError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var statusErr apierrors.APIStatus
+	if !errors.As(err, &statusErr) {
+		return false
+	}
+	return statusErr.Status().Code >= 500
+}
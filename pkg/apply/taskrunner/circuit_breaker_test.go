@@ -0,0 +1,85 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package taskrunner
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	errServer := apierrors.NewInternalError(errors.New("etcd unavailable"))
+	errUnavailable := apierrors.NewServiceUnavailable("apiserver shutting down")
+	errClient := apierrors.NewNotFound(schema.GroupResource{Resource: "pods"}, "foo")
+
+	testCases := map[string]struct {
+		threshold       int
+		results         []error
+		expectedTripped bool
+		expectedLastErr error
+	}{
+		"disabled": {
+			threshold:       0,
+			results:         []error{errServer, errServer, errServer},
+			expectedTripped: false,
+		},
+		"below threshold": {
+			threshold:       3,
+			results:         []error{errServer, errServer},
+			expectedTripped: false,
+		},
+		"consecutive server errors": {
+			threshold:       3,
+			results:         []error{errServer, errServer, errUnavailable},
+			expectedTripped: true,
+			expectedLastErr: errUnavailable,
+		},
+		"success resets count": {
+			threshold:       2,
+			results:         []error{errServer, nil, errServer},
+			expectedTripped: false,
+		},
+		"client error resets count": {
+			threshold:       2,
+			results:         []error{errServer, errClient, errServer},
+			expectedTripped: false,
+		},
+		"generic errors do not count": {
+			threshold:       1,
+			results:         []error{errors.New("connection refused")},
+			expectedTripped: false,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			cb := NewCircuitBreaker(tc.threshold)
+			for _, err := range tc.results {
+				cb.Record(err)
+			}
+			assert.Equal(t, tc.expectedTripped, cb.Tripped())
+			err := cb.Err()
+			if !tc.expectedTripped {
+				assert.NoError(t, err)
+				return
+			}
+			var cbErr *CircuitBreakerError
+			if assert.True(t, errors.As(err, &cbErr)) {
+				assert.Equal(t, tc.threshold, cbErr.Failures)
+				assert.Equal(t, tc.expectedLastErr, cbErr.LastErr)
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerNil(t *testing.T) {
+	var cb *CircuitBreaker
+	cb.Record(apierrors.NewInternalError(errors.New("fail")))
+	assert.False(t, cb.Tripped())
+	assert.NoError(t, cb.Err())
+}
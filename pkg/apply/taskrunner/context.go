@@ -4,10 +4,15 @@
 package taskrunner
 
 import (
+	"sync"
+	"time"
+
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/cli-utils/pkg/apply/cache"
+	applyerror "sigs.k8s.io/cli-utils/pkg/apply/error"
 	"sigs.k8s.io/cli-utils/pkg/apply/event"
 	"sigs.k8s.io/cli-utils/pkg/inventory"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/watcher"
 	"sigs.k8s.io/cli-utils/pkg/object"
 	"sigs.k8s.io/cli-utils/pkg/object/graph"
 )
@@ -21,6 +26,8 @@ func NewTaskContext(eventChannel chan event.Event, resourceCache cache.ResourceC
 		inventoryManager: inventory.NewManager(),
 		abandonedObjects: make(map[object.ObjMetadata]struct{}),
 		invalidObjects:   make(map[object.ObjMetadata]struct{}),
+		unchangedObjects: make(map[object.ObjMetadata]struct{}),
+		adoptedObjects:   make(map[object.ObjMetadata]string),
 		graph:            graph.New(),
 	}
 }
@@ -34,9 +41,32 @@ type TaskContext struct {
 	inventoryManager *inventory.Manager
 	abandonedObjects map[object.ObjMetadata]struct{}
 	invalidObjects   map[object.ObjMetadata]struct{}
+	unchangedObjects map[object.ObjMetadata]struct{}
+	adoptedObjects   map[object.ObjMetadata]string
 	graph            *graph.Graph
+	circuitBreaker   *CircuitBreaker
+	deadline         time.Time
+	kindRefresher    watcher.KindRefresher
+
+	applyErrorsMu sync.Mutex
+	applyErrors   []error
+
+	pruneErrorsMu sync.Mutex
+	pruneErrors   []*applyerror.ObjectPruneError
+
+	injectMu      sync.Mutex
+	taskInjector  TaskInjector
+	injectedTasks []Task
 }
 
+// TaskInjector is called with every event sent by the tasks and the runner.
+// The returned tasks run after the current task, before the remaining tasks
+// of the queue, e.g. to remediate a failure reported by the event.
+//
+// The injector is called synchronously by the goroutine sending the event,
+// so it must not block.
+type TaskInjector func(e event.Event) []Task
+
 func (tc *TaskContext) TaskChannel() chan TaskResult {
 	return tc.taskChannel
 }
@@ -61,9 +91,121 @@ func (tc *TaskContext) SetGraph(g *graph.Graph) {
 	tc.graph = g
 }
 
-// SendEvent sends an event on the event channel
+// CircuitBreaker returns the CircuitBreaker used to abort tasks early when
+// the cluster is failing. Returns nil if disabled.
+func (tc *TaskContext) CircuitBreaker() *CircuitBreaker {
+	return tc.circuitBreaker
+}
+
+// SetCircuitBreaker sets the CircuitBreaker shared by all tasks.
+func (tc *TaskContext) SetCircuitBreaker(cb *CircuitBreaker) {
+	tc.circuitBreaker = cb
+}
+
+// Deadline returns the deadline of the run, or the zero time if the run has
+// no deadline.
+func (tc *TaskContext) Deadline() time.Time {
+	return tc.deadline
+}
+
+// SetDeadline sets the deadline of the run. Wait tasks that are still
+// waiting at the deadline report their pending objects as timed out.
+func (tc *TaskContext) SetDeadline(deadline time.Time) {
+	tc.deadline = deadline
+}
+
+// SetKindRefresher sets the KindRefresher used to re-discover the resource
+// types of the watched objects.
+func (tc *TaskContext) SetKindRefresher(refresher watcher.KindRefresher) {
+	tc.kindRefresher = refresher
+}
+
+// RefreshKinds re-discovers the resource types of the watched objects, if the
+// status watcher supports it, e.g. after CRDs or APIServices were applied.
+func (tc *TaskContext) RefreshKinds() {
+	if tc.kindRefresher != nil {
+		tc.kindRefresher.RefreshKinds()
+	}
+}
+
+// AddApplyError registers the cause of a failed apply of an object.
+// Safe for concurrent use.
+func (tc *TaskContext) AddApplyError(id object.ObjMetadata, err error) {
+	tc.applyErrorsMu.Lock()
+	defer tc.applyErrorsMu.Unlock()
+	tc.applyErrors = append(tc.applyErrors, applyerror.NewObjectApplyError(id, err))
+}
+
+// ApplyErrors returns the causes of all the failed applies, in the order
+// they were registered.
+func (tc *TaskContext) ApplyErrors() []error {
+	tc.applyErrorsMu.Lock()
+	defer tc.applyErrorsMu.Unlock()
+	return append([]error(nil), tc.applyErrors...)
+}
+
+// AddPruneError registers the cause of a failed prune or delete of an
+// object. Safe for concurrent use.
+func (tc *TaskContext) AddPruneError(id object.ObjMetadata, err error) {
+	tc.pruneErrorsMu.Lock()
+	defer tc.pruneErrorsMu.Unlock()
+	tc.pruneErrors = append(tc.pruneErrors, applyerror.NewObjectPruneError(id, err))
+}
+
+// PruneError returns a PruneError with the causes of all the failed prunes
+// and deletes, in the order they were registered, or nil if none failed.
+func (tc *TaskContext) PruneError() error {
+	tc.pruneErrorsMu.Lock()
+	defer tc.pruneErrorsMu.Unlock()
+	if len(tc.pruneErrors) == 0 {
+		return nil
+	}
+	return applyerror.NewPruneError(append([]*applyerror.ObjectPruneError(nil), tc.pruneErrors...)...)
+}
+
+// SetTaskInjector sets the TaskInjector called with every sent event.
+func (tc *TaskContext) SetTaskInjector(injector TaskInjector) {
+	tc.injectMu.Lock()
+	defer tc.injectMu.Unlock()
+	tc.taskInjector = injector
+}
+
+// InjectTasks queues the tasks to run after the current task, before the
+// remaining tasks of the queue. The tasks run in the order they are injected.
+// Injected tasks are not run if the run is aborted, and the objects of
+// injected tasks are only watched if they are in the inventory of the run.
+// Safe for concurrent use.
+func (tc *TaskContext) InjectTasks(tasks ...Task) {
+	tc.injectMu.Lock()
+	defer tc.injectMu.Unlock()
+	tc.injectedTasks = append(tc.injectedTasks, tasks...)
+}
+
+// nextInjectedTask removes and returns the first injected task, or nil if no
+// task is injected.
+func (tc *TaskContext) nextInjectedTask() Task {
+	tc.injectMu.Lock()
+	defer tc.injectMu.Unlock()
+	if len(tc.injectedTasks) == 0 {
+		return nil
+	}
+	tsk := tc.injectedTasks[0]
+	tc.injectedTasks = tc.injectedTasks[1:]
+	return tsk
+}
+
+// SendEvent sends an event on the event channel, and injects the tasks
+// returned by the TaskInjector, if set.
 func (tc *TaskContext) SendEvent(e event.Event) {
 	klog.V(3).Infof("Sending event: %v", e)
+	tc.injectMu.Lock()
+	injector := tc.taskInjector
+	tc.injectMu.Unlock()
+	if injector != nil {
+		if tasks := injector(e); len(tasks) > 0 {
+			tc.InjectTasks(tasks...)
+		}
+	}
 	tc.eventChannel <- e
 }
 
@@ -98,3 +240,31 @@ func (tc *TaskContext) AddInvalidObject(id object.ObjMetadata) {
 func (tc *TaskContext) InvalidObjects() object.ObjMetadataSet {
 	return object.ObjMetadataSetFromMap(tc.invalidObjects)
 }
+
+// IsUnchangedObject returns true if the object was not applied, because it
+// was unchanged and already reconciled.
+func (tc *TaskContext) IsUnchangedObject(id object.ObjMetadata) bool {
+	_, found := tc.unchangedObjects[id]
+	return found
+}
+
+// AddUnchangedObject registers that the object was not applied, because it
+// was unchanged and already reconciled. Wait tasks do not wait for these
+// objects.
+func (tc *TaskContext) AddUnchangedObject(id object.ObjMetadata) {
+	tc.unchangedObjects[id] = struct{}{}
+}
+
+// AdoptedObject returns the ID of the inventory that owned the object before
+// it was adopted, and true if the object is being adopted.
+func (tc *TaskContext) AdoptedObject(id object.ObjMetadata) (string, bool) {
+	previousOwner, found := tc.adoptedObjects[id]
+	return previousOwner, found
+}
+
+// AddAdoptedObject registers that the object exists, but is not owned by the
+// inventory, and is adopted if applied successfully. The previous owner is
+// the ID of the inventory that owned the object, or empty if none.
+func (tc *TaskContext) AddAdoptedObject(id object.ObjMetadata, previousOwner string) {
+	tc.adoptedObjects[id] = previousOwner
+}
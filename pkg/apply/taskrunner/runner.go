@@ -63,6 +63,9 @@ func (tsr *TaskStatusRunner) Run(
 	statusChannel := tsr.StatusWatcher.Watch(statusCtx, tsr.Identifiers, watcher.Options{
 		RESTScopeStrategy: opts.WatcherRESTScopeStrategy,
 	})
+	if refresher, ok := tsr.StatusWatcher.(watcher.KindRefresher); ok {
+		taskContext.SetKindRefresher(refresher)
+	}
 
 	// complete stops the statusPoller, drains the statusChannel, and returns
 	// the provided error.
@@ -144,6 +147,12 @@ func (tsr *TaskStatusRunner) Run(
 				continue
 			}
 
+			// Resource usage of the StatusWatcher, not requested by the
+			// runner, but ignored in case a custom StatusWatcher sends it.
+			if statusEvent.Type == pollevent.StatsEvent {
+				continue
+			}
+
 			if opts.EmitStatusEvents {
 				// Forward all normal events to the eventChannel
 				taskContext.SendEvent(event.Event{
@@ -186,9 +195,10 @@ func (tsr *TaskStatusRunner) Run(
 			taskContext.SendEvent(event.Event{
 				Type: event.ActionGroupType,
 				ActionGroupEvent: event.ActionGroupEvent{
-					GroupName: currentTask.Name(),
-					Action:    currentTask.Action(),
-					Status:    event.Finished,
+					GroupName:  currentTask.Name(),
+					Action:     currentTask.Action(),
+					Status:     event.Finished,
+					WaitResult: msg.WaitResult,
 				},
 			})
 			if msg.Err != nil {
@@ -223,19 +233,21 @@ func (tsr *TaskStatusRunner) Run(
 	}
 }
 
-// nextTask fetches the latest task from the taskQueue and
-// starts it. If the taskQueue is empty, it the second
-// return value will be true.
+// nextTask fetches the next injected task, or else the latest task from the
+// taskQueue, and starts it. If there are no injected tasks and the taskQueue
+// is empty, the second return value will be true.
 func nextTask(taskQueue chan Task, taskContext *TaskContext) (Task, bool) {
-	var tsk Task
-	select {
-	// If there is any tasks left in the queue, this
-	// case statement will be executed.
-	case t := <-taskQueue:
-		tsk = t
-	default:
-		// Only happens when the channel is empty.
-		return nil, true
+	tsk := taskContext.nextInjectedTask()
+	if tsk == nil {
+		select {
+		// If there is any tasks left in the queue, this
+		// case statement will be executed.
+		case t := <-taskQueue:
+			tsk = t
+		default:
+			// Only happens when the channel is empty.
+			return nil, true
+		}
 	}
 
 	taskContext.SendEvent(event.Event{
@@ -257,4 +269,6 @@ func nextTask(taskQueue chan Task, taskContext *TaskContext) (Task, bool) {
 // set.
 type TaskResult struct {
 	Err error
+	// WaitResult is the outcome of a WaitTask.
+	WaitResult *event.WaitResult
 }
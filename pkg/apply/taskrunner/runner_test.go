@@ -507,6 +507,70 @@ func TestBaseRunnerCancellation(t *testing.T) {
 	}
 }
 
+func TestBaseRunnerTaskInjection(t *testing.T) {
+	tasks := []Task{
+		&fakeApplyTask{
+			name: "apply-0",
+			resultEvent: event.Event{
+				Type: event.ApplyType,
+				ApplyEvent: event.ApplyEvent{
+					Identifier: depID,
+					Status:     event.ApplyFailed,
+				},
+			},
+		},
+		&fakeApplyTask{
+			name: "apply-1",
+			resultEvent: event.Event{
+				Type: event.ApplyType,
+			},
+		},
+	}
+	taskQueue := make(chan Task, len(tasks))
+	for _, tsk := range tasks {
+		taskQueue <- tsk
+	}
+
+	statusWatcher := newFakeWatcher(nil)
+	eventChannel := make(chan event.Event)
+	taskContext := NewTaskContext(eventChannel, cache.NewResourceCacheMap())
+	// Inject a remediation task for every failed apply.
+	taskContext.SetTaskInjector(func(e event.Event) []Task {
+		if e.Type != event.ApplyType || e.ApplyEvent.Status != event.ApplyFailed {
+			return nil
+		}
+		return []Task{
+			&fakeApplyTask{
+				name: "remediate-" + e.ApplyEvent.Identifier.Name,
+				resultEvent: event.Event{
+					Type: event.ApplyType,
+				},
+			},
+		}
+	})
+	runner := NewTaskStatusRunner(object.ObjMetadataSet{}, statusWatcher)
+
+	var wg sync.WaitGroup
+	var groups []string
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for e := range eventChannel {
+			if e.Type == event.ActionGroupType && e.ActionGroupEvent.Status == event.Started {
+				groups = append(groups, e.ActionGroupEvent.GroupName)
+			}
+		}
+	}()
+
+	statusWatcher.Start()
+	err := runner.Run(context.Background(), taskContext, taskQueue, Options{})
+	close(eventChannel)
+	wg.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"apply-0", "remediate-dep", "apply-1"}, groups)
+}
+
 type fakeApplyTask struct {
 	name        string
 	resultEvent event.Event
@@ -6,6 +6,7 @@ package taskrunner
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -13,13 +14,20 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
+	"sigs.k8s.io/cli-utils/pkg/apis/actuation"
 	"sigs.k8s.io/cli-utils/pkg/apply/event"
+	"sigs.k8s.io/cli-utils/pkg/apply/readiness"
 	"sigs.k8s.io/cli-utils/pkg/kstatus/status"
 	"sigs.k8s.io/cli-utils/pkg/object"
 )
 
+// DefaultReadinessInterval is how often the ReadinessGates of an object are
+// checked, if the WaitTask has no ReadinessInterval.
+const DefaultReadinessInterval = 2 * time.Second
+
 var (
-	crdGK = schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}
+	crdGK        = schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}
+	apiServiceGK = schema.GroupKind{Group: "apiregistration.k8s.io", Kind: "APIService"}
 )
 
 // Task is the interface that must be implemented by
@@ -45,6 +53,12 @@ func NewWaitTask(name string, ids object.ObjMetadataSet, cond Condition, timeout
 	}
 }
 
+// FinalizerRemover removes the finalizers of an object, so that the deletion
+// of the object can complete.
+type FinalizerRemover interface {
+	RemoveFinalizers(ctx context.Context, id object.ObjMetadata) error
+}
+
 // WaitTask is an implementation of the Task interface that is used
 // to wait for a set of resources (identified by a slice of ObjMetadata)
 // will all meet the condition specified. It also specifies a timeout
@@ -62,8 +76,39 @@ type WaitTask struct {
 	// Timeout defines how long we are willing to wait for the condition
 	// to be met.
 	Timeout time.Duration
+	// ObjectTimeouts overrides the Timeout of individual objects. Objects
+	// that time out are reported as timed out, without waiting for the
+	// other objects.
+	ObjectTimeouts map[object.ObjMetadata]time.Duration
 	// Mapper is the RESTMapper to update after CRDs have been reconciled
 	Mapper meta.RESTMapper
+	// StuckDeletionThreshold is how long to wait for deleted objects to be
+	// removed, before reporting the objects that are still terminating with
+	// their remaining finalizers. Zero disables the report. Only used with
+	// the AllNotFound condition.
+	StuckDeletionThreshold time.Duration
+	// FinalizerRemover, if set, forcibly removes the finalizers of the
+	// objects that are still terminating after the StuckDeletionThreshold.
+	FinalizerRemover FinalizerRemover
+	// ReconcileSuspended, if true, considers the objects that are Suspended,
+	// e.g. paused Deployments, as reconciled. Otherwise, they are waited on
+	// until they are resumed, or the task times out. Only used with the
+	// AllCurrent condition.
+	ReconcileSuspended bool
+	// StopOnFailure, if true, fails the task with a ReconcileError if any
+	// object failed to reconcile or timed out, which stops the run.
+	// Otherwise, the run proceeds, and the outcome of the task is only
+	// reported in its WaitResult.
+	StopOnFailure bool
+	// ReadinessGates must all pass, in addition to the object being Current,
+	// before the object is considered reconciled. Only used with the
+	// AllCurrent condition.
+	ReadinessGates []readiness.Gate
+	// ReadinessInterval is how often the ReadinessGates of an object are
+	// checked, until they pass. Defaults to DefaultReadinessInterval.
+	ReadinessInterval time.Duration
+	// ctx is the context of the task, which is done when the task completes.
+	ctx context.Context
 	// cancelFunc is a function that will cancel the timeout timer
 	// on the task.
 	cancelFunc context.CancelFunc
@@ -72,6 +117,13 @@ type WaitTask struct {
 	// failed is the set of resources that we are waiting for, but is considered
 	// failed, i.e. unlikely to successfully reconcile.
 	failed object.ObjMetadataSet
+	// expired is the set of resources whose object timeout has expired.
+	expired object.ObjMetadataSet
+	// gating is the set of pending resources that are reconciled, and whose
+	// ReadinessGates are being checked.
+	gating object.ObjMetadataSet
+	// timers are the timers of the object timeouts.
+	timers []*time.Timer
 	// mu protects the pending ObjMetadataSet
 	mu sync.RWMutex
 }
@@ -98,13 +150,24 @@ func (w *WaitTask) Start(taskContext *TaskContext) {
 	ctx := context.Background()
 
 	// use a context wrapper to handle complete/cancel/timeout
-	if w.Timeout > 0 {
-		ctx, w.cancelFunc = context.WithTimeout(ctx, w.Timeout)
+	// The deadline of the run applies, if earlier than the timeout.
+	timeout := w.taskTimeout()
+	deadline := taskContext.Deadline()
+	if timeout > 0 {
+		if taskDeadline := time.Now().Add(timeout); deadline.IsZero() || taskDeadline.Before(deadline) {
+			deadline = taskDeadline
+		}
+	}
+	if !deadline.IsZero() {
+		ctx, w.cancelFunc = context.WithDeadline(ctx, deadline)
 	} else {
 		ctx, w.cancelFunc = context.WithCancel(ctx)
 	}
+	w.ctx = ctx
 
 	w.startInner(taskContext)
+	w.startObjectTimers(ctx, taskContext, timeout)
+	w.startStuckDeletionTimer(ctx, taskContext, timeout)
 
 	// A goroutine to handle ending the WaitTask.
 	go func() {
@@ -115,9 +178,15 @@ func (w *WaitTask) Start(taskContext *TaskContext) {
 
 		klog.V(2).Infof("wait task completing (name: %q,): %v", w.TaskName, err)
 
+		w.stopObjectTimers()
+
 		switch err {
 		case context.Canceled:
-			// happy path - cancelled or completed (not considered an error)
+			// happy path - cancelled or completed (not considered an error),
+			// unless cancelled because the deadline of the run was exceeded.
+			if deadline := taskContext.Deadline(); !deadline.IsZero() && !time.Now().Before(deadline) {
+				w.sendTimeoutEvents(taskContext)
+			}
 		case context.DeadlineExceeded:
 			// timed out
 			w.sendTimeoutEvents(taskContext)
@@ -127,10 +196,60 @@ func (w *WaitTask) Start(taskContext *TaskContext) {
 		w.updateRESTMapper(taskContext)
 
 		// Done here. signal completion to the task runner
-		taskContext.TaskChannel() <- TaskResult{}
+		result := w.result(taskContext)
+		var resultErr error
+		if w.StopOnFailure && (len(result.Failed) > 0 || len(result.TimedOut) > 0) {
+			resultErr = &ReconcileError{Result: *result}
+		}
+		taskContext.TaskChannel() <- TaskResult{
+			Err:        resultErr,
+			WaitResult: result,
+		}
 	}()
 }
 
+// result sorts the objects of the task by their reconcile status.
+func (w *WaitTask) result(taskContext *TaskContext) *event.WaitResult {
+	result := &event.WaitResult{}
+	for _, id := range w.IDs {
+		objStatus, found := taskContext.InventoryManager().ObjectStatus(id)
+		if !found {
+			continue
+		}
+		switch objStatus.Reconcile {
+		case actuation.ReconcileSucceeded:
+			result.Reconciled = append(result.Reconciled, id)
+		case actuation.ReconcileSkipped:
+			result.Skipped = append(result.Skipped, id)
+		case actuation.ReconcileFailed:
+			result.Failed = append(result.Failed, id)
+		case actuation.ReconcileTimeout:
+			result.TimedOut = append(result.TimedOut, id)
+		default:
+			result.Pending = append(result.Pending, id)
+		}
+	}
+	return result
+}
+
+// ReconcileError is the error of a WaitTask with StopOnFailure, when objects
+// failed to reconcile or timed out.
+type ReconcileError struct {
+	Result event.WaitResult
+}
+
+func (e *ReconcileError) Error() string {
+	var reasons []string
+	if len(e.Result.Failed) > 0 {
+		reasons = append(reasons, fmt.Sprintf("failed: %s", e.Result.Failed))
+	}
+	if len(e.Result.TimedOut) > 0 {
+		reasons = append(reasons, fmt.Sprintf("timed out: %s", e.Result.TimedOut))
+	}
+	return fmt.Sprintf("%d objects did not reconcile (%s)",
+		len(e.Result.Failed)+len(e.Result.TimedOut), strings.Join(reasons, "; "))
+}
+
 func (w *WaitTask) sendEvent(taskContext *TaskContext, id object.ObjMetadata, status event.WaitEventStatus) {
 	taskContext.SendEvent(event.Event{
 		Type: event.WaitType,
@@ -161,10 +280,18 @@ func (w *WaitTask) startInner(taskContext *TaskContext) {
 				klog.Errorf("Failed to mark object as skipped reconcile: %v", err)
 			}
 			w.sendEvent(taskContext, id, event.ReconcileSkipped)
+		case w.Condition == AllCurrent && taskContext.IsUnchangedObject(id):
+			// not applied and already reconciled
+			err := taskContext.InventoryManager().SetSuccessfulReconcile(id)
+			if err != nil {
+				// Object never applied or deleted!
+				klog.Errorf("Failed to mark object as successful reconcile: %v", err)
+			}
+			w.sendEvent(taskContext, id, event.ReconcileSuccessful)
 		case w.changedUID(taskContext, id):
 			// replaced
 			w.handleChangedUID(taskContext, id)
-		case w.reconciledByID(taskContext, id):
+		case w.reconciledByID(taskContext, id) && !w.gated():
 			err := taskContext.InventoryManager().SetSuccessfulReconcile(id)
 			if err != nil {
 				// Object never applied or deleted!
@@ -179,6 +306,10 @@ func (w *WaitTask) startInner(taskContext *TaskContext) {
 			}
 			pending = append(pending, id)
 			w.sendEvent(taskContext, id, event.ReconcilePending)
+			if w.gated() && w.reconciledByID(taskContext, id) {
+				// reconciled, but not ready yet
+				w.startReadinessGates(taskContext, id)
+			}
 		}
 	}
 	w.pending = pending
@@ -208,12 +339,242 @@ func (w *WaitTask) sendTimeoutEvents(taskContext *TaskContext) {
 	}
 }
 
+// taskTimeout returns how long to wait for all the objects, or zero to wait
+// until cancelled.
+func (w *WaitTask) taskTimeout() time.Duration {
+	if len(w.ObjectTimeouts) == 0 {
+		return w.Timeout
+	}
+	var maxTimeout time.Duration
+	for _, id := range w.IDs {
+		timeout := w.objectTimeout(id)
+		if timeout <= 0 {
+			return 0
+		}
+		if timeout > maxTimeout {
+			maxTimeout = timeout
+		}
+	}
+	return maxTimeout
+}
+
+// objectTimeout returns how long to wait for the object, or zero to wait
+// until cancelled.
+func (w *WaitTask) objectTimeout(id object.ObjMetadata) time.Duration {
+	if timeout, found := w.ObjectTimeouts[id]; found {
+		return timeout
+	}
+	return w.Timeout
+}
+
+// startObjectTimers starts a timer for every object that times out before
+// the task.
+func (w *WaitTask) startObjectTimers(ctx context.Context, taskContext *TaskContext, taskTimeout time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, id := range w.IDs {
+		timeout := w.objectTimeout(id)
+		if timeout <= 0 || (taskTimeout > 0 && timeout >= taskTimeout) {
+			continue
+		}
+		id := id
+		w.timers = append(w.timers, time.AfterFunc(timeout, func() {
+			w.expireObject(ctx, taskContext, id)
+		}))
+	}
+}
+
+// stopObjectTimers stops the timers of the object timeouts. Expired timers
+// have no effect after the task has completed.
+func (w *WaitTask) stopObjectTimers() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, timer := range w.timers {
+		timer.Stop()
+	}
+	w.timers = nil
+}
+
+// expireObject sends a timeout event for the object, if still pending, and
+// ignores its subsequent status updates.
+// If all objects are reconciled or skipped, cancelFunc is called.
+// The pending set is write locked during execution of expireObject.
+func (w *WaitTask) expireObject(ctx context.Context, taskContext *TaskContext, id object.ObjMetadata) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if ctx.Err() != nil {
+		// task completed
+		return
+	}
+	w.expired = append(w.expired, id)
+	if !w.pending.Contains(id) {
+		return
+	}
+	klog.V(3).Infof("object timed out (name: %q): %v", w.TaskName, id)
+	err := taskContext.InventoryManager().SetTimeoutReconcile(id)
+	if err != nil {
+		// Object never applied or deleted!
+		klog.Errorf("Failed to mark object as timeout reconcile: %v", err)
+	}
+	w.pending = w.pending.Remove(id)
+	w.sendEvent(taskContext, id, event.ReconcileTimeout)
+
+	if len(w.pending) == 0 {
+		// all reconciled, skipped or timed out - exit
+		klog.V(3).Infof("all objects reconciled, skipped or timed out (name: %q)", w.TaskName)
+		w.cancelFunc()
+	}
+}
+
+// startStuckDeletionTimer starts a timer to report the objects that are
+// still terminating after the StuckDeletionThreshold, unless the task times
+// out first.
+func (w *WaitTask) startStuckDeletionTimer(ctx context.Context, taskContext *TaskContext, taskTimeout time.Duration) {
+	if w.Condition != AllNotFound || w.StuckDeletionThreshold <= 0 ||
+		(taskTimeout > 0 && w.StuckDeletionThreshold >= taskTimeout) {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.timers = append(w.timers, time.AfterFunc(w.StuckDeletionThreshold, func() {
+		w.reportStuckDeletions(ctx, taskContext)
+	}))
+}
+
+// reportStuckDeletions sends a StuckDeletionEvent for every pending object
+// that is terminating, with its remaining finalizers. If a FinalizerRemover
+// is set, the finalizers are removed, and the event reports the result.
+// The pending set is write locked during execution of reportStuckDeletions,
+// so that the task does not complete while the events are sent.
+func (w *WaitTask) reportStuckDeletions(ctx context.Context, taskContext *TaskContext) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if ctx.Err() != nil {
+		// task completed
+		return
+	}
+	for _, id := range w.pending {
+		obj := taskContext.ResourceCache().Get(id).Resource
+		if obj == nil || obj.GetDeletionTimestamp() == nil {
+			continue
+		}
+		finalizers := obj.GetFinalizers()
+		klog.V(3).Infof("object deletion stuck (name: %q, finalizers: %v): %v",
+			w.TaskName, finalizers, id)
+		e := event.StuckDeletionEvent{
+			GroupName:  w.Name(),
+			Identifier: id,
+			Finalizers: finalizers,
+		}
+		if w.FinalizerRemover != nil && len(finalizers) > 0 {
+			if err := w.FinalizerRemover.RemoveFinalizers(ctx, id); err != nil {
+				e.Error = err
+			} else {
+				e.FinalizersRemoved = true
+			}
+		}
+		taskContext.SendEvent(event.Event{
+			Type:               event.StuckDeletionType,
+			StuckDeletionEvent: e,
+		})
+	}
+}
+
 // reconciledByID checks whether the condition set in the task is currently met
 // for the specified object given the status of resource in the cache.
 func (w *WaitTask) reconciledByID(taskContext *TaskContext, id object.ObjMetadata) bool {
+	if w.Condition == AllCurrent && w.ReconcileSuspended &&
+		allMatchStatus(taskContext, object.ObjMetadataSet{id}, status.SuspendedStatus) {
+		return true
+	}
 	return conditionMet(taskContext, object.ObjMetadataSet{id}, w.Condition)
 }
 
+// gated returns true if reconciled objects must pass the ReadinessGates,
+// before they are considered reconciled.
+func (w *WaitTask) gated() bool {
+	return w.Condition == AllCurrent && len(w.ReadinessGates) > 0
+}
+
+// startReadinessGates starts checking the ReadinessGates of the pending
+// object, unless they are already being checked.
+// The pending set must be write locked by the caller.
+func (w *WaitTask) startReadinessGates(taskContext *TaskContext, id object.ObjMetadata) {
+	if w.gating.Contains(id) {
+		return
+	}
+	klog.V(3).Infof("object reconciled, checking readiness gates (name: %q): %v", w.TaskName, id)
+	w.gating = append(w.gating, id)
+	go w.checkReadinessGates(w.ctx, taskContext, id)
+}
+
+// checkReadinessGates checks the ReadinessGates of the object every
+// ReadinessInterval, until they pass, the object is no longer pending and
+// reconciled, or the task completes.
+func (w *WaitTask) checkReadinessGates(ctx context.Context, taskContext *TaskContext, id object.ObjMetadata) {
+	interval := w.ReadinessInterval
+	if interval <= 0 {
+		interval = DefaultReadinessInterval
+	}
+	for {
+		obj := taskContext.ResourceCache().Get(id).Resource
+		ready, err := readiness.All(ctx, w.ReadinessGates, id, obj)
+		if err != nil {
+			klog.V(3).Infof("readiness gate failed (name: %q, object: %v): %v", w.TaskName, id, err)
+		}
+		if w.readinessChecked(ctx, taskContext, id, ready) {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// readinessChecked records the result of checking the ReadinessGates of the
+// object. If the object is ready, it is removed from pending, and an event is
+// sent. Returns true if the gates no longer need to be checked.
+// If all objects are reconciled or skipped, cancelFunc is called.
+// The pending set is write locked during execution of readinessChecked.
+func (w *WaitTask) readinessChecked(ctx context.Context, taskContext *TaskContext, id object.ObjMetadata, ready bool) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if ctx.Err() != nil {
+		// task completed
+		return true
+	}
+	if !w.pending.Contains(id) || !w.reconciledByID(taskContext, id) {
+		// timed out, failed or unreconciled - check again once reconciled
+		w.gating = w.gating.Remove(id)
+		return true
+	}
+	if !ready {
+		return false
+	}
+	w.gating = w.gating.Remove(id)
+	err := taskContext.InventoryManager().SetSuccessfulReconcile(id)
+	if err != nil {
+		// Object never applied or deleted!
+		klog.Errorf("Failed to mark object as successful reconcile: %v", err)
+	}
+	w.pending = w.pending.Remove(id)
+	w.sendEvent(taskContext, id, event.ReconcileSuccessful)
+
+	klog.V(3).Infof("wait task progress: %d/%d", len(w.IDs)-len(w.pending), len(w.IDs))
+
+	if len(w.pending) == 0 {
+		// all reconciled, so exit
+		klog.V(3).Infof("all objects reconciled or skipped (name: %q)", w.TaskName)
+		w.cancelFunc()
+	}
+	return true
+}
+
 // skipped returns true if the object failed or was skipped by a preceding
 // apply/delete/prune task.
 func (w *WaitTask) skipped(taskContext *TaskContext, id object.ObjMetadata) bool {
@@ -330,6 +691,9 @@ func (w *WaitTask) StatusUpdate(taskContext *TaskContext, id object.ObjMetadata)
 			// replaced
 			w.handleChangedUID(taskContext, id)
 			w.pending = w.pending.Remove(id)
+		case w.reconciledByID(taskContext, id) && w.gated():
+			// reconciled - check readiness before removing from pending
+			w.startReadinessGates(taskContext, id)
 		case w.reconciledByID(taskContext, id):
 			// reconciled - remove from pending & send event
 			err := taskContext.InventoryManager().SetSuccessfulReconcile(id)
@@ -354,6 +718,9 @@ func (w *WaitTask) StatusUpdate(taskContext *TaskContext, id object.ObjMetadata)
 	case !w.IDs.Contains(id):
 		// not in wait group - ignore
 		return
+	case w.expired.Contains(id):
+		// timed out - ignore
+		return
 	case w.skipped(taskContext, id):
 		// skipped - ignore
 		return
@@ -361,7 +728,18 @@ func (w *WaitTask) StatusUpdate(taskContext *TaskContext, id object.ObjMetadata)
 		// If a failed resource becomes current before other
 		// resources have completed/timed out, we consider it
 		// current.
-		if w.reconciledByID(taskContext, id) {
+		if w.reconciledByID(taskContext, id) && w.gated() {
+			// reconciled - add to pending until ready & send event
+			err := taskContext.InventoryManager().SetPendingReconcile(id)
+			if err != nil {
+				// Object never applied or deleted!
+				klog.Errorf("Failed to mark object as pending reconcile: %v", err)
+			}
+			w.failed = w.failed.Remove(id)
+			w.pending = append(w.pending, id)
+			w.sendEvent(taskContext, id, event.ReconcilePending)
+			w.startReadinessGates(taskContext, id)
+		} else if w.reconciledByID(taskContext, id) {
 			// reconciled - remove from pending & send event
 			err := taskContext.InventoryManager().SetSuccessfulReconcile(id)
 			if err != nil {
@@ -409,23 +787,26 @@ func (w *WaitTask) StatusUpdate(taskContext *TaskContext, id object.ObjMetadata)
 	}
 }
 
-// updateRESTMapper resets the RESTMapper if CRDs were applied, so that new
-// resource types can be applied by subsequent tasks.
+// updateRESTMapper resets the RESTMapper if CRDs or APIServices were applied,
+// so that new resource types can be applied by subsequent tasks, and
+// refreshes the kinds of the status watcher, so that objects of the new
+// resource types can be watched.
 // TODO: find a way to add/remove mappers without resetting the entire mapper
 // Resetting the mapper requires all CRDs to be queried again.
 func (w *WaitTask) updateRESTMapper(taskContext *TaskContext) {
-	foundCRD := false
+	foundKinds := false
 	for _, id := range w.IDs {
-		if id.GroupKind == crdGK && !w.skipped(taskContext, id) {
-			foundCRD = true
+		if (id.GroupKind == crdGK || id.GroupKind == apiServiceGK) && !w.skipped(taskContext, id) {
+			foundKinds = true
 			break
 		}
 	}
-	if !foundCRD {
+	if !foundKinds {
 		// no update required
 		return
 	}
 
 	klog.V(3).Infof("Resetting RESTMapper")
 	meta.MaybeResetRESTMapper(w.Mapper)
+	taskContext.RefreshKinds()
 }
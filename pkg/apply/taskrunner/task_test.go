@@ -4,13 +4,21 @@
 package taskrunner
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/cli-utils/pkg/apis/actuation"
 	"sigs.k8s.io/cli-utils/pkg/apply/cache"
 	"sigs.k8s.io/cli-utils/pkg/apply/event"
+	"sigs.k8s.io/cli-utils/pkg/apply/readiness"
 	"sigs.k8s.io/cli-utils/pkg/inventory"
 	"sigs.k8s.io/cli-utils/pkg/kstatus/status"
 	"sigs.k8s.io/cli-utils/pkg/object"
@@ -425,6 +433,442 @@ loop:
 	testutil.AssertEqual(t, &expectedInventory, taskContext.InventoryManager().Inventory())
 }
 
+func TestWaitTask_ObjectTimeout(t *testing.T) {
+	testDeployment1ID := testutil.ToIdentifier(t, testDeployment1YAML)
+	testDeployment1 := testutil.Unstructured(t, testDeployment1YAML)
+	testDeployment2ID := testutil.ToIdentifier(t, testDeployment2YAML)
+	testDeployment2 := testutil.Unstructured(t, testDeployment2YAML)
+	ids := object.ObjMetadataSet{
+		testDeployment1ID,
+		testDeployment2ID,
+	}
+	taskName := "wait-2"
+	task := NewWaitTask(taskName, ids, AllCurrent,
+		time.Minute, testutil.NewFakeRESTMapper())
+	task.ObjectTimeouts = map[object.ObjMetadata]time.Duration{
+		testDeployment1ID: 100 * time.Millisecond,
+	}
+
+	eventChannel := make(chan event.Event)
+	resourceCache := cache.NewResourceCacheMap()
+	taskContext := NewTaskContext(eventChannel, resourceCache)
+	defer close(eventChannel)
+
+	taskContext.InventoryManager().AddSuccessfulApply(testDeployment1ID,
+		testDeployment1.GetUID(), testDeployment1.GetGeneration())
+	taskContext.InventoryManager().AddSuccessfulApply(testDeployment2ID,
+		testDeployment2.GetUID(), testDeployment2.GetGeneration())
+
+	// run task async, to let the test collect events
+	go func() {
+		// start the task
+		task.Start(taskContext)
+		// mark deployment2 as Current
+		resourceCache.Put(testDeployment2ID, cache.ResourceStatus{
+			Resource: testDeployment2,
+			Status:   status.CurrentStatus,
+		})
+		// tell the WaitTask deployment2 has new status
+		task.StatusUpdate(taskContext, testDeployment2ID)
+	}()
+
+	// wait for task result, long before the task timeout
+	timer := time.NewTimer(5 * time.Second)
+	receivedEvents := []event.Event{}
+loop:
+	for {
+		select {
+		case e := <-taskContext.EventChannel():
+			receivedEvents = append(receivedEvents, e)
+		case res := <-taskContext.TaskChannel():
+			timer.Stop()
+			assert.NoError(t, res.Err)
+			// The task proceeds, and reports its partial success.
+			assert.Equal(t, &event.WaitResult{
+				Reconciled: object.ObjMetadataSet{testDeployment2ID},
+				TimedOut:   object.ObjMetadataSet{testDeployment1ID},
+			}, res.WaitResult)
+			break loop
+		case <-timer.C:
+			t.Fatalf("timed out waiting for TaskResult")
+		}
+	}
+
+	expectedEvents := []event.Event{
+		{
+			Type: event.WaitType,
+			WaitEvent: event.WaitEvent{
+				GroupName:  taskName,
+				Identifier: testDeployment1ID,
+				Status:     event.ReconcilePending,
+			},
+		},
+		{
+			Type: event.WaitType,
+			WaitEvent: event.WaitEvent{
+				GroupName:  taskName,
+				Identifier: testDeployment2ID,
+				Status:     event.ReconcilePending,
+			},
+		},
+		{
+			Type: event.WaitType,
+			WaitEvent: event.WaitEvent{
+				GroupName:  taskName,
+				Identifier: testDeployment2ID,
+				Status:     event.ReconcileSuccessful,
+			},
+		},
+		{
+			Type: event.WaitType,
+			WaitEvent: event.WaitEvent{
+				GroupName:  taskName,
+				Identifier: testDeployment1ID,
+				Status:     event.ReconcileTimeout,
+			},
+		},
+	}
+	testutil.AssertEqual(t, expectedEvents, receivedEvents,
+		"Actual events (%d) do not match expected events (%d)",
+		len(receivedEvents), len(expectedEvents))
+
+	objStatus, found := taskContext.InventoryManager().ObjectStatus(testDeployment1ID)
+	assert.True(t, found)
+	assert.Equal(t, actuation.ReconcileTimeout, objStatus.Reconcile)
+}
+
+func TestWaitTask_StopOnFailure(t *testing.T) {
+	testDeployment1ID := testutil.ToIdentifier(t, testDeployment1YAML)
+	testDeployment1 := testutil.Unstructured(t, testDeployment1YAML)
+	testDeployment2ID := testutil.ToIdentifier(t, testDeployment2YAML)
+	testDeployment2 := testutil.Unstructured(t, testDeployment2YAML)
+	ids := object.ObjMetadataSet{
+		testDeployment1ID,
+		testDeployment2ID,
+	}
+	task := NewWaitTask("wait-2", ids, AllCurrent,
+		100*time.Millisecond, testutil.NewFakeRESTMapper())
+	task.StopOnFailure = true
+
+	// buffer events, because they're sent by StatusUpdate
+	eventChannel := make(chan event.Event, 10)
+	resourceCache := cache.NewResourceCacheMap()
+	taskContext := NewTaskContext(eventChannel, resourceCache)
+	defer close(eventChannel)
+
+	taskContext.InventoryManager().AddSuccessfulApply(testDeployment1ID,
+		testDeployment1.GetUID(), testDeployment1.GetGeneration())
+	taskContext.InventoryManager().AddSuccessfulApply(testDeployment2ID,
+		testDeployment2.GetUID(), testDeployment2.GetGeneration())
+
+	go func() {
+		task.Start(taskContext)
+		// mark deployment2 as Failed
+		resourceCache.Put(testDeployment2ID, cache.ResourceStatus{
+			Resource: testDeployment2,
+			Status:   status.FailedStatus,
+		})
+		task.StatusUpdate(taskContext, testDeployment2ID)
+	}()
+
+	timer := time.NewTimer(5 * time.Second)
+	defer timer.Stop()
+	var res TaskResult
+loop:
+	for {
+		select {
+		case <-taskContext.EventChannel():
+		case res = <-taskContext.TaskChannel():
+			break loop
+		case <-timer.C:
+			t.Fatalf("timed out waiting for TaskResult")
+		}
+	}
+
+	expectedResult := event.WaitResult{
+		Failed:   object.ObjMetadataSet{testDeployment2ID},
+		TimedOut: object.ObjMetadataSet{testDeployment1ID},
+	}
+	assert.Equal(t, &expectedResult, res.WaitResult)
+	var reconcileErr *ReconcileError
+	if assert.ErrorAs(t, res.Err, &reconcileErr) {
+		assert.Equal(t, expectedResult, reconcileErr.Result)
+	}
+	assert.EqualError(t, res.Err, fmt.Sprintf("2 objects did not reconcile (failed: [%s]; timed out: [%s])",
+		testDeployment2ID, testDeployment1ID))
+}
+
+func TestWaitTask_ReadinessGates(t *testing.T) {
+	testDeploymentID := testutil.ToIdentifier(t, testDeployment1YAML)
+	testDeployment := testutil.Unstructured(t, testDeployment1YAML)
+	taskName := "wait-1"
+	task := NewWaitTask(taskName, object.ObjMetadataSet{testDeploymentID},
+		AllCurrent, 5*time.Second, testutil.NewFakeRESTMapper())
+	var ready atomic.Bool
+	var checked atomic.Int32
+	task.ReadinessGates = []readiness.Gate{
+		readiness.GateFunc(func(_ context.Context, id object.ObjMetadata, obj *unstructured.Unstructured) (bool, error) {
+			assert.Equal(t, testDeploymentID, id)
+			assert.Equal(t, testDeployment, obj)
+			checked.Add(1)
+			if !ready.Load() {
+				return false, errors.New("smoke test failed")
+			}
+			return true, nil
+		}),
+	}
+	task.ReadinessInterval = 10 * time.Millisecond
+
+	// buffer events, because they're sent by StatusUpdate
+	eventChannel := make(chan event.Event, 10)
+	resourceCache := cache.NewResourceCacheMap()
+	taskContext := NewTaskContext(eventChannel, resourceCache)
+	defer close(eventChannel)
+
+	taskContext.InventoryManager().AddSuccessfulApply(testDeploymentID,
+		testDeployment.GetUID(), testDeployment.GetGeneration())
+
+	task.Start(taskContext)
+	// mark deployment as Current
+	resourceCache.Put(testDeploymentID, cache.ResourceStatus{
+		Resource: testDeployment,
+		Status:   status.CurrentStatus,
+	})
+	task.StatusUpdate(taskContext, testDeploymentID)
+
+	// the deployment is pending until the gate passes
+	assert.Eventually(t, func() bool { return checked.Load() >= 2 },
+		time.Second, 5*time.Millisecond)
+	select {
+	case res := <-taskContext.TaskChannel():
+		t.Fatalf("unexpected TaskResult before the readiness gate passed: %v", res)
+	default:
+	}
+	ready.Store(true)
+
+	timer := time.NewTimer(5 * time.Second)
+	defer timer.Stop()
+	var res TaskResult
+	select {
+	case res = <-taskContext.TaskChannel():
+	case <-timer.C:
+		t.Fatalf("timed out waiting for TaskResult")
+	}
+	assert.NoError(t, res.Err)
+	assert.Equal(t, &event.WaitResult{
+		Reconciled: object.ObjMetadataSet{testDeploymentID},
+	}, res.WaitResult)
+
+	expectedEvents := []event.Event{
+		{
+			Type: event.WaitType,
+			WaitEvent: event.WaitEvent{
+				GroupName:  taskName,
+				Identifier: testDeploymentID,
+				Status:     event.ReconcilePending,
+			},
+		},
+		{
+			Type: event.WaitType,
+			WaitEvent: event.WaitEvent{
+				GroupName:  taskName,
+				Identifier: testDeploymentID,
+				Status:     event.ReconcileSuccessful,
+			},
+		},
+	}
+	var receivedEvents []event.Event
+	for len(receivedEvents) < len(expectedEvents) {
+		receivedEvents = append(receivedEvents, <-eventChannel)
+	}
+	testutil.AssertEqual(t, expectedEvents, receivedEvents)
+}
+
+func TestWaitTask_RunDeadline(t *testing.T) {
+	testDeploymentID := testutil.ToIdentifier(t, testDeployment1YAML)
+	testDeployment := testutil.Unstructured(t, testDeployment1YAML)
+	taskName := "wait-2"
+	// no task timeout
+	task := NewWaitTask(taskName, object.ObjMetadataSet{testDeploymentID},
+		AllCurrent, 0, testutil.NewFakeRESTMapper())
+
+	eventChannel := make(chan event.Event)
+	resourceCache := cache.NewResourceCacheMap()
+	taskContext := NewTaskContext(eventChannel, resourceCache)
+	taskContext.SetDeadline(time.Now().Add(100 * time.Millisecond))
+	defer close(eventChannel)
+
+	taskContext.InventoryManager().AddSuccessfulApply(testDeploymentID,
+		testDeployment.GetUID(), testDeployment.GetGeneration())
+
+	// run task async, to let the test collect events
+	go task.Start(taskContext)
+
+	// wait for task result
+	timer := time.NewTimer(5 * time.Second)
+	receivedEvents := []event.Event{}
+loop:
+	for {
+		select {
+		case e := <-taskContext.EventChannel():
+			receivedEvents = append(receivedEvents, e)
+		case res := <-taskContext.TaskChannel():
+			timer.Stop()
+			assert.NoError(t, res.Err)
+			break loop
+		case <-timer.C:
+			t.Fatalf("timed out waiting for TaskResult")
+		}
+	}
+
+	expectedEvents := []event.Event{
+		{
+			Type: event.WaitType,
+			WaitEvent: event.WaitEvent{
+				GroupName:  taskName,
+				Identifier: testDeploymentID,
+				Status:     event.ReconcilePending,
+			},
+		},
+		{
+			Type: event.WaitType,
+			WaitEvent: event.WaitEvent{
+				GroupName:  taskName,
+				Identifier: testDeploymentID,
+				Status:     event.ReconcileTimeout,
+			},
+		},
+	}
+	testutil.AssertEqual(t, expectedEvents, receivedEvents,
+		"Actual events (%d) do not match expected events (%d)",
+		len(receivedEvents), len(expectedEvents))
+}
+
+type fakeFinalizerRemover struct {
+	removed object.ObjMetadataSet
+	err     error
+}
+
+func (f *fakeFinalizerRemover) RemoveFinalizers(_ context.Context, id object.ObjMetadata) error {
+	f.removed = append(f.removed, id)
+	return f.err
+}
+
+func TestWaitTask_StuckDeletion(t *testing.T) {
+	testDeploymentID := testutil.ToIdentifier(t, testDeployment1YAML)
+	testDeployment := testutil.Unstructured(t, testDeployment1YAML)
+	terminatingDeployment := testDeployment.DeepCopy()
+	terminatingDeployment.SetDeletionTimestamp(&metav1.Time{Time: time.Now()})
+	terminatingDeployment.SetFinalizers([]string{"example.com/cleanup"})
+	errRemove := errors.New("forbidden")
+
+	testCases := map[string]struct {
+		removeErr      error
+		removeStuck    bool
+		expectedEvent  event.StuckDeletionEvent
+		expectedRemove object.ObjMetadataSet
+	}{
+		"report only": {
+			expectedEvent: event.StuckDeletionEvent{
+				GroupName:  "wait-0",
+				Identifier: testDeploymentID,
+				Finalizers: []string{"example.com/cleanup"},
+			},
+		},
+		"finalizers removed": {
+			removeStuck: true,
+			expectedEvent: event.StuckDeletionEvent{
+				GroupName:         "wait-0",
+				Identifier:        testDeploymentID,
+				Finalizers:        []string{"example.com/cleanup"},
+				FinalizersRemoved: true,
+			},
+			expectedRemove: object.ObjMetadataSet{testDeploymentID},
+		},
+		"finalizer removal failed": {
+			removeStuck: true,
+			removeErr:   errRemove,
+			expectedEvent: event.StuckDeletionEvent{
+				GroupName:  "wait-0",
+				Identifier: testDeploymentID,
+				Finalizers: []string{"example.com/cleanup"},
+				Error:      errRemove,
+			},
+			expectedRemove: object.ObjMetadataSet{testDeploymentID},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			task := NewWaitTask("wait-0", object.ObjMetadataSet{testDeploymentID},
+				AllNotFound, 500*time.Millisecond, testutil.NewFakeRESTMapper())
+			task.StuckDeletionThreshold = 100 * time.Millisecond
+			remover := &fakeFinalizerRemover{err: tc.removeErr}
+			if tc.removeStuck {
+				task.FinalizerRemover = remover
+			}
+
+			eventChannel := make(chan event.Event)
+			resourceCache := cache.NewResourceCacheMap()
+			taskContext := NewTaskContext(eventChannel, resourceCache)
+			defer close(eventChannel)
+
+			taskContext.InventoryManager().AddSuccessfulDelete(testDeploymentID,
+				testDeployment.GetUID())
+			resourceCache.Put(testDeploymentID, cache.ResourceStatus{
+				Resource: terminatingDeployment,
+				Status:   status.InProgressStatus,
+			})
+
+			// run task async, to let the test collect events
+			go task.Start(taskContext)
+
+			timer := time.NewTimer(5 * time.Second)
+			receivedEvents := []event.Event{}
+		loop:
+			for {
+				select {
+				case e := <-taskContext.EventChannel():
+					receivedEvents = append(receivedEvents, e)
+				case res := <-taskContext.TaskChannel():
+					timer.Stop()
+					assert.NoError(t, res.Err)
+					break loop
+				case <-timer.C:
+					t.Fatalf("timed out waiting for TaskResult")
+				}
+			}
+
+			expectedEvents := []event.Event{
+				{
+					Type: event.WaitType,
+					WaitEvent: event.WaitEvent{
+						GroupName:  "wait-0",
+						Identifier: testDeploymentID,
+						Status:     event.ReconcilePending,
+					},
+				},
+				{
+					Type:               event.StuckDeletionType,
+					StuckDeletionEvent: tc.expectedEvent,
+				},
+				{
+					Type: event.WaitType,
+					WaitEvent: event.WaitEvent{
+						GroupName:  "wait-0",
+						Identifier: testDeploymentID,
+						Status:     event.ReconcileTimeout,
+					},
+				},
+			}
+			testutil.AssertEqual(t, expectedEvents, receivedEvents,
+				"Actual events (%d) do not match expected events (%d)",
+				len(receivedEvents), len(expectedEvents))
+			assert.Equal(t, tc.expectedRemove, remover.removed)
+		})
+	}
+}
+
 func TestWaitTask_StartAndComplete(t *testing.T) {
 	testDeploymentID := testutil.ToIdentifier(t, testDeployment1YAML)
 	testDeployment := testutil.Unstructured(t, testDeployment1YAML)
@@ -510,6 +954,191 @@ loop:
 	testutil.AssertEqual(t, &expectedInventory, taskContext.InventoryManager().Inventory())
 }
 
+func TestWaitTask_UnchangedObject(t *testing.T) {
+	testDeploymentID := testutil.ToIdentifier(t, testDeployment1YAML)
+	testDeployment := testutil.Unstructured(t, testDeployment1YAML)
+	ids := object.ObjMetadataSet{
+		testDeploymentID,
+	}
+	taskName := "wait-0"
+	task := NewWaitTask(taskName, ids, AllCurrent,
+		2*time.Second, testutil.NewFakeRESTMapper())
+
+	eventChannel := make(chan event.Event)
+	resourceCache := cache.NewResourceCacheMap()
+	taskContext := NewTaskContext(eventChannel, resourceCache)
+	defer close(eventChannel)
+
+	testDeployment.SetUID("a")
+	testDeployment.SetGeneration(1)
+
+	// mark deployment as skipped because it is unchanged, without status
+	// in the cache
+	taskContext.InventoryManager().AddSuccessfulApply(testDeploymentID,
+		testDeployment.GetUID(), testDeployment.GetGeneration())
+	taskContext.AddUnchangedObject(testDeploymentID)
+
+	// run task async, to let the test collect events
+	go func() {
+		task.Start(taskContext)
+	}()
+
+	timer := time.NewTimer(5 * time.Second)
+	receivedEvents := []event.Event{}
+loop:
+	for {
+		select {
+		case e := <-taskContext.EventChannel():
+			receivedEvents = append(receivedEvents, e)
+		case res := <-taskContext.TaskChannel():
+			timer.Stop()
+			assert.NoError(t, res.Err)
+			break loop
+		case <-timer.C:
+			t.Fatalf("timed out waiting for TaskResult")
+		}
+	}
+
+	expectedEvents := []event.Event{
+		{
+			Type: event.WaitType,
+			WaitEvent: event.WaitEvent{
+				GroupName:  taskName,
+				Identifier: testDeploymentID,
+				Status:     event.ReconcileSuccessful,
+			},
+		},
+	}
+	testutil.AssertEqual(t, expectedEvents, receivedEvents,
+		"Actual events (%d) do not match expected events (%d)",
+		len(receivedEvents), len(expectedEvents))
+
+	objStatus, found := taskContext.InventoryManager().ObjectStatus(testDeploymentID)
+	require.True(t, found)
+	assert.Equal(t, actuation.ReconcileSucceeded, objStatus.Reconcile)
+}
+
+func TestWaitTask_Suspended(t *testing.T) {
+	testDeploymentID := testutil.ToIdentifier(t, testDeployment1YAML)
+	testDeployment := testutil.Unstructured(t, testDeployment1YAML)
+	taskName := "wait-0"
+
+	testCases := map[string]struct {
+		reconcileSuspended bool
+		expectedStatuses   []event.WaitEventStatus
+	}{
+		"suspended objects are waited on by default": {
+			reconcileSuspended: false,
+			expectedStatuses:   []event.WaitEventStatus{event.ReconcilePending, event.ReconcileTimeout},
+		},
+		"suspended objects are reconciled": {
+			reconcileSuspended: true,
+			expectedStatuses:   []event.WaitEventStatus{event.ReconcileSuccessful},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			task := NewWaitTask(taskName, object.ObjMetadataSet{testDeploymentID}, AllCurrent,
+				time.Second, testutil.NewFakeRESTMapper())
+			task.ReconcileSuspended = tc.reconcileSuspended
+
+			eventChannel := make(chan event.Event, 10)
+			resourceCache := cache.NewResourceCacheMap()
+			taskContext := NewTaskContext(eventChannel, resourceCache)
+			defer close(eventChannel)
+
+			testDeployment.SetUID("a")
+			testDeployment.SetGeneration(1)
+			taskContext.InventoryManager().AddSuccessfulApply(testDeploymentID,
+				testDeployment.GetUID(), testDeployment.GetGeneration())
+			resourceCache.Put(testDeploymentID, cache.ResourceStatus{
+				Resource: withGeneration(testDeployment, 1),
+				Status:   status.SuspendedStatus,
+			})
+
+			go func() {
+				task.Start(taskContext)
+			}()
+
+			timer := time.NewTimer(5 * time.Second)
+			var receivedStatuses []event.WaitEventStatus
+		loop:
+			for {
+				select {
+				case e := <-taskContext.EventChannel():
+					receivedStatuses = append(receivedStatuses, e.WaitEvent.Status)
+				case res := <-taskContext.TaskChannel():
+					timer.Stop()
+					assert.NoError(t, res.Err)
+					break loop
+				case <-timer.C:
+					t.Fatalf("timed out waiting for TaskResult")
+				}
+			}
+			assert.Equal(t, tc.expectedStatuses, receivedStatuses)
+		})
+	}
+}
+
+type countingKindRefresher struct {
+	refreshes int
+}
+
+func (r *countingKindRefresher) RefreshKinds() {
+	r.refreshes++
+}
+
+func TestWaitTask_RefreshKinds(t *testing.T) {
+	apiServiceID := object.ObjMetadata{
+		GroupKind: apiServiceGK,
+		Name:      "v1beta1.metrics.k8s.io",
+	}
+	apiService := &unstructured.Unstructured{}
+	apiService.SetGroupVersionKind(apiServiceGK.WithVersion("v1"))
+	apiService.SetName(apiServiceID.Name)
+	apiService.SetUID("a")
+	apiService.SetGeneration(1)
+
+	task := NewWaitTask("wait-0", object.ObjMetadataSet{apiServiceID}, AllCurrent,
+		2*time.Second, testutil.NewFakeRESTMapper())
+
+	eventChannel := make(chan event.Event)
+	resourceCache := cache.NewResourceCacheMap()
+	taskContext := NewTaskContext(eventChannel, resourceCache)
+	defer close(eventChannel)
+	refresher := &countingKindRefresher{}
+	taskContext.SetKindRefresher(refresher)
+
+	taskContext.InventoryManager().AddSuccessfulApply(apiServiceID,
+		apiService.GetUID(), apiService.GetGeneration())
+	resourceCache.Put(apiServiceID, cache.ResourceStatus{
+		Resource: apiService,
+		Status:   status.CurrentStatus,
+	})
+
+	go func() {
+		task.Start(taskContext)
+	}()
+
+	timer := time.NewTimer(5 * time.Second)
+loop:
+	for {
+		select {
+		case <-taskContext.EventChannel():
+		case res := <-taskContext.TaskChannel():
+			timer.Stop()
+			assert.NoError(t, res.Err)
+			break loop
+		case <-timer.C:
+			t.Fatalf("timed out waiting for TaskResult")
+		}
+	}
+
+	// The kinds are refreshed once the APIService is reconciled.
+	assert.Equal(t, 1, refresher.refreshes)
+}
+
 func TestWaitTask_Cancel(t *testing.T) {
 	testDeploymentID := testutil.ToIdentifier(t, testDeployment1YAML)
 	testDeployment := testutil.Unstructured(t, testDeployment1YAML)
@@ -684,7 +1313,12 @@ loop:
 		len(receivedEvents), len(expectedEvents))
 
 	expectedResults := []TaskResult{
-		{}, // Empty result means success
+		{
+			// No error means success
+			WaitResult: &event.WaitResult{
+				Reconciled: object.ObjMetadataSet{testDeploymentID},
+			},
+		},
 	}
 	assert.Equal(t, expectedResults, receivedResults)
 
@@ -0,0 +1,155 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package backoff provides pluggable strategies for computing the delay
+// between retries of an operation against the cluster.
+//
+// A Strategy is stateless: callers pass in the number of the retry attempt
+// and the previously returned delay, which allows the same Strategy to be
+// shared between goroutines.
+package backoff
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Strategy computes how long to wait before the next retry attempt.
+type Strategy interface {
+	// Delay returns the duration to wait before retry number attempt.
+	// Attempts start at 1. prev is the delay returned for the previous
+	// attempt, or zero for the first attempt.
+	Delay(attempt int, prev time.Duration) time.Duration
+}
+
+// Constant waits the same Interval between every attempt.
+type Constant struct {
+	Interval time.Duration
+}
+
+var _ Strategy = Constant{}
+
+// Delay returns the constant interval.
+func (c Constant) Delay(_ int, _ time.Duration) time.Duration {
+	return c.Interval
+}
+
+// Exponential multiplies the delay by Factor after every attempt, starting
+// at Initial and never exceeding Max (if Max is greater than zero).
+type Exponential struct {
+	// Initial is the delay before the first retry.
+	Initial time.Duration
+	// Max is the upper bound of the delay. Zero means unbounded.
+	Max time.Duration
+	// Factor is the multiplier applied after each attempt. Values less than
+	// or equal to 1 are treated as 2.
+	Factor float64
+	// Jitter adds a random duration of up to Jitter times the delay, after
+	// it has been capped at Max. Zero disables the jitter.
+	Jitter float64
+	// Reset restarts the backoff at Initial if more than Reset has passed
+	// since the previous delay was computed. Zero disables the reset.
+	Reset time.Duration
+}
+
+var _ Strategy = Exponential{}
+var _ Resetter = Exponential{}
+
+// Delay returns Initial * Factor^(attempt-1), capped at Max, plus the jitter.
+func (e Exponential) Delay(attempt int, _ time.Duration) time.Duration {
+	delay := e.delay(attempt)
+	if e.Jitter > 0 {
+		jitter := float64(delay) * e.Jitter * rand.Float64() // nolint:gosec
+		if float64(delay)+jitter > math.MaxInt64 {
+			return time.Duration(math.MaxInt64)
+		}
+		delay += time.Duration(jitter)
+	}
+	return delay
+}
+
+// ResetAfter returns the Reset duration.
+func (e Exponential) ResetAfter() time.Duration {
+	return e.Reset
+}
+
+func (e Exponential) delay(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	factor := e.Factor
+	if factor <= 1 {
+		factor = 2
+	}
+	delay := float64(e.Initial) * math.Pow(factor, float64(attempt-1))
+	if e.Max > 0 && delay > float64(e.Max) {
+		return e.Max
+	}
+	if delay > math.MaxInt64 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(delay)
+}
+
+// Resetter is implemented by strategies that restart from the first attempt
+// when they have not been used for a while, so that an operation that failed
+// long after its last retry does not wait for the maximum delay.
+type Resetter interface {
+	// ResetAfter returns the duration after which the attempts are reset.
+	// Zero disables the reset.
+	ResetAfter() time.Duration
+}
+
+// sequence tracks the attempts of a series of retries, to compute the delay
+// before each of them with a Strategy.
+type sequence struct {
+	strategy Strategy
+	attempt  int
+	delay    time.Duration
+	last     time.Time
+}
+
+// next returns the delay before the next attempt.
+func (q *sequence) next() time.Duration {
+	now := time.Now()
+	if r, ok := q.strategy.(Resetter); ok && r.ResetAfter() > 0 &&
+		!q.last.IsZero() && now.Sub(q.last) > r.ResetAfter() {
+		q.attempt = 0
+		q.delay = 0
+	}
+	q.last = now
+	q.attempt++
+	q.delay = q.strategy.Delay(q.attempt, q.delay)
+	return q.delay
+}
+
+// DecorrelatedJitter picks a random delay between Base and three times the
+// previous delay, capped at Cap. This spreads out retries from many clients
+// better than plain exponential backoff.
+//
+// See https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/
+type DecorrelatedJitter struct {
+	// Base is the minimum delay.
+	Base time.Duration
+	// Cap is the maximum delay. Zero means unbounded.
+	Cap time.Duration
+}
+
+var _ Strategy = DecorrelatedJitter{}
+
+// Delay returns a random duration in [Base, 3*prev), capped at Cap.
+func (d DecorrelatedJitter) Delay(_ int, prev time.Duration) time.Duration {
+	if prev < d.Base {
+		prev = d.Base
+	}
+	upper := 3 * prev
+	delay := d.Base
+	if upper > d.Base {
+		delay += time.Duration(rand.Int63n(int64(upper - d.Base))) // nolint:gosec
+	}
+	if d.Cap > 0 && delay > d.Cap {
+		return d.Cap
+	}
+	return delay
+}
@@ -0,0 +1,277 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package backoff
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestExponentialDelay(t *testing.T) {
+	testCases := map[string]struct {
+		strategy Exponential
+		attempts []int
+		expected []time.Duration
+	}{
+		"doubles by default": {
+			strategy: Exponential{Initial: time.Second},
+			attempts: []int{1, 2, 3, 4},
+			expected: []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second},
+		},
+		"custom factor": {
+			strategy: Exponential{Initial: time.Second, Factor: 3},
+			attempts: []int{1, 2, 3},
+			expected: []time.Duration{time.Second, 3 * time.Second, 9 * time.Second},
+		},
+		"capped at max": {
+			strategy: Exponential{Initial: time.Second, Max: 5 * time.Second, Factor: 2},
+			attempts: []int{3, 4, 100},
+			expected: []time.Duration{4 * time.Second, 5 * time.Second, 5 * time.Second},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			for i, attempt := range tc.attempts {
+				assert.Equal(t, tc.expected[i], tc.strategy.Delay(attempt, 0), "attempt %d", attempt)
+			}
+		})
+	}
+}
+
+func TestExponentialJitter(t *testing.T) {
+	s := Exponential{Initial: time.Second, Max: 4 * time.Second, Jitter: 1}
+	for attempt := 1; attempt < 10; attempt++ {
+		base := Exponential{Initial: s.Initial, Max: s.Max}.Delay(attempt, 0)
+		delay := s.Delay(attempt, 0)
+		assert.GreaterOrEqual(t, delay, base)
+		assert.LessOrEqual(t, delay, 2*base)
+	}
+}
+
+func TestSequenceReset(t *testing.T) {
+	q := &sequence{strategy: Exponential{Initial: time.Second, Reset: time.Minute}}
+	assert.Equal(t, time.Second, q.next())
+	assert.Equal(t, 2*time.Second, q.next())
+	assert.Equal(t, 4*time.Second, q.next())
+
+	// Not used for longer than the reset duration.
+	q.last = q.last.Add(-2 * time.Minute)
+	assert.Equal(t, time.Second, q.next())
+	assert.Equal(t, 2*time.Second, q.next())
+}
+
+func TestConstantDelay(t *testing.T) {
+	s := Constant{Interval: 2 * time.Second}
+	for attempt := 1; attempt < 5; attempt++ {
+		assert.Equal(t, 2*time.Second, s.Delay(attempt, time.Minute))
+	}
+}
+
+func TestDecorrelatedJitterDelay(t *testing.T) {
+	s := DecorrelatedJitter{Base: 100 * time.Millisecond, Cap: 2 * time.Second}
+	var delay time.Duration
+	for attempt := 1; attempt < 50; attempt++ {
+		prev := delay
+		delay = s.Delay(attempt, delay)
+		assert.GreaterOrEqual(t, delay, s.Base)
+		assert.LessOrEqual(t, delay, s.Cap)
+		if prev >= s.Base {
+			assert.LessOrEqual(t, delay, 3*prev)
+		}
+	}
+}
+
+func TestPolicyStrategyFor(t *testing.T) {
+	apply := Constant{Interval: time.Second}
+	p := Policy{Apply: apply}
+	assert.Equal(t, apply, p.StrategyFor(ApplyOperation))
+	assert.Equal(t, DefaultStrategy, p.StrategyFor(DeleteOperation))
+	assert.Equal(t, DefaultStrategy, p.StrategyFor(StatusReadOperation))
+}
+
+func TestRetry(t *testing.T) {
+	errTransient := apierrors.NewTooManyRequests("slow down", 1)
+	errPermanent := apierrors.NewNotFound(schema.GroupResource{Resource: "pods"}, "foo")
+
+	testCases := map[string]struct {
+		maxAttempts   int
+		errs          []error
+		expectedCalls int
+		expectedError error
+	}{
+		"success on first attempt": {
+			maxAttempts:   3,
+			errs:          []error{nil},
+			expectedCalls: 1,
+		},
+		"success after retries": {
+			maxAttempts:   3,
+			errs:          []error{errTransient, errTransient, nil},
+			expectedCalls: 3,
+		},
+		"max attempts exceeded": {
+			maxAttempts:   2,
+			errs:          []error{errTransient, errTransient, nil},
+			expectedCalls: 2,
+			expectedError: errTransient,
+		},
+		"permanent error not retried": {
+			maxAttempts:   3,
+			errs:          []error{errPermanent, nil},
+			expectedCalls: 1,
+			expectedError: errPermanent,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			calls := 0
+			err := Retry(context.Background(), Constant{Interval: time.Millisecond}, tc.maxAttempts, IsTransient, func() error {
+				err := tc.errs[calls]
+				calls++
+				return err
+			})
+			assert.Equal(t, tc.expectedCalls, calls)
+			if tc.expectedError != nil {
+				assert.True(t, errors.Is(err, tc.expectedError))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRetryContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	calls := 0
+	err := Retry(ctx, Constant{Interval: time.Hour}, 0, nil, func() error {
+		calls++
+		return errors.New("fail")
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestIsRetriable(t *testing.T) {
+	gr := schema.GroupResource{Group: "apps", Resource: "deployments"}
+	fieldConflict := apierrors.NewConflict(gr, "foo", errors.New("field conflict"))
+	fieldConflict.ErrStatus.Details.Causes = []metav1.StatusCause{{
+		Type:  metav1.CauseTypeFieldManagerConflict,
+		Field: ".spec.replicas",
+	}}
+
+	testCases := map[string]struct {
+		err      error
+		expected bool
+	}{
+		"throttled": {
+			err:      apierrors.NewTooManyRequests("slow down", 1),
+			expected: true,
+		},
+		"webhook timeout": {
+			err: apierrors.NewInternalError(errors.New(
+				`failed calling webhook "validate.example.com": context deadline exceeded`)),
+			expected: true,
+		},
+		"update conflict": {
+			err:      apierrors.NewConflict(gr, "foo", errors.New("the object has been modified")),
+			expected: true,
+		},
+		"field manager conflict": {
+			err:      fieldConflict,
+			expected: false,
+		},
+		"invalid": {
+			err:      apierrors.NewBadRequest("invalid"),
+			expected: false,
+		},
+		"not an API error": {
+			err:      errors.New("failed"),
+			expected: false,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			assert.Equal(t, tc.expected, IsRetriable(tc.err))
+		})
+	}
+}
+
+func TestRetryPolicyDo(t *testing.T) {
+	errConflict := apierrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, "foo",
+		errors.New("the object has been modified"))
+	errFailed := errors.New("failed")
+
+	testCases := map[string]struct {
+		policy        RetryPolicy
+		errs          []error
+		expectedCalls int
+		expectedError error
+	}{
+		"zero value does not retry": {
+			errs:          []error{errConflict, nil},
+			expectedCalls: 1,
+			expectedError: errConflict,
+		},
+		"retries retriable errors": {
+			policy:        RetryPolicy{MaxAttempts: 3, Backoff: Constant{}},
+			errs:          []error{errConflict, errConflict, nil},
+			expectedCalls: 3,
+		},
+		"does not retry other errors": {
+			policy:        RetryPolicy{MaxAttempts: 3, Backoff: Constant{}},
+			errs:          []error{errFailed, nil},
+			expectedCalls: 1,
+			expectedError: errFailed,
+		},
+		"custom classifier": {
+			policy: RetryPolicy{
+				MaxAttempts: 3,
+				Backoff:     Constant{},
+				Retriable: func(err error) bool {
+					return err == errFailed
+				},
+			},
+			errs:          []error{errFailed, errConflict, nil},
+			expectedCalls: 2,
+			expectedError: errConflict,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			calls := 0
+			err := tc.policy.Do(context.Background(), func() error {
+				err := tc.errs[calls]
+				calls++
+				return err
+			})
+			assert.Equal(t, tc.expectedCalls, calls)
+			if tc.expectedError != nil {
+				assert.True(t, errors.Is(err, tc.expectedError))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUntil(t *testing.T) {
+	calls := 0
+	Until(context.Background(), Constant{Interval: time.Millisecond}, func() bool {
+		calls++
+		return calls == 3
+	})
+	assert.Equal(t, 3, calls)
+}
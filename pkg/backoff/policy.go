@@ -0,0 +1,64 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package backoff
+
+import (
+	"time"
+)
+
+// Operation identifies a class of cluster operations that can be retried
+// with its own backoff Strategy.
+//
+//go:generate stringer -type=Operation -linecomment
+type Operation int
+
+const (
+	// ApplyOperation covers creating and patching objects.
+	ApplyOperation Operation = iota // Apply
+	// DeleteOperation covers deleting objects (prune & destroy).
+	DeleteOperation // Delete
+	// StatusReadOperation covers reading, listing and watching objects to
+	// compute their status.
+	StatusReadOperation // StatusRead
+)
+
+// DefaultStrategy is used for any operation class that does not have a
+// Strategy configured. It doubles the delay from 800ms up to 30s, adds up to
+// 100% jitter, and resets after 2m without retries.
+var DefaultStrategy Strategy = Exponential{
+	Initial: 800 * time.Millisecond,
+	Max:     30 * time.Second,
+	Factor:  2,
+	Jitter:  1,
+	Reset:   2 * time.Minute,
+}
+
+// Policy configures the backoff Strategy used for each operation class.
+// The zero value uses DefaultStrategy for every operation.
+type Policy struct {
+	// Apply is the Strategy used when retrying apply operations.
+	Apply Strategy
+	// Delete is the Strategy used when retrying delete operations.
+	Delete Strategy
+	// StatusRead is the Strategy used when retrying status reads and watches.
+	StatusRead Strategy
+}
+
+// StrategyFor returns the Strategy configured for the specified operation
+// class, or DefaultStrategy if none is configured.
+func (p Policy) StrategyFor(op Operation) Strategy {
+	var s Strategy
+	switch op {
+	case ApplyOperation:
+		s = p.Apply
+	case DeleteOperation:
+		s = p.Delete
+	case StatusReadOperation:
+		s = p.StatusRead
+	}
+	if s == nil {
+		return DefaultStrategy
+	}
+	return s
+}
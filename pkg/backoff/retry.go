@@ -0,0 +1,133 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package backoff
+
+import (
+	"context"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Wait blocks for the specified duration or until the context is done.
+// Returns the context error if the context is done first.
+func Wait(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Retry calls fn until it succeeds, returns an error that is not retriable,
+// or maxAttempts calls have been made. The delay between calls is computed
+// by the Strategy. If maxAttempts is less than 1, fn is retried until the
+// context is done. If retriable is nil, all errors are retried.
+//
+// Returns nil on success, otherwise the last error returned by fn. Retries stop
+// early if the context is done while waiting.
+func Retry(ctx context.Context, s Strategy, maxAttempts int, retriable func(error) bool, fn func() error) error {
+	q := &sequence{strategy: s}
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		if retriable != nil && !retriable(err) {
+			return err
+		}
+		if maxAttempts > 0 && attempt >= maxAttempts {
+			return err
+		}
+		if waitErr := Wait(ctx, q.next()); waitErr != nil {
+			return err
+		}
+	}
+}
+
+// Until calls fn until it returns true or the context is done, waiting
+// between calls according to the Strategy. If the Strategy is a Resetter,
+// the attempts are reset when fn returns long after the previous delay.
+func Until(ctx context.Context, s Strategy, fn func() bool) {
+	q := &sequence{strategy: s}
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+		if fn() {
+			return
+		}
+		if err := Wait(ctx, q.next()); err != nil {
+			return
+		}
+	}
+}
+
+// IsTransient returns true if the error is likely to be resolved by retrying
+// the request, such as throttling, timeouts, and temporary server errors.
+func IsTransient(err error) bool {
+	return apierrors.IsTooManyRequests(err) ||
+		apierrors.IsServerTimeout(err) ||
+		apierrors.IsTimeout(err) ||
+		apierrors.IsServiceUnavailable(err) ||
+		apierrors.IsInternalError(err)
+}
+
+// IsRetriable returns true if the error is transient, or a conflict caused by
+// a concurrent update of the object. Field manager conflicts of server-side
+// apply are not retriable, because they persist until forced or resolved.
+func IsRetriable(err error) bool {
+	if IsTransient(err) {
+		return true
+	}
+	if !apierrors.IsConflict(err) {
+		return false
+	}
+	if status, ok := err.(apierrors.APIStatus); ok && status.Status().Details != nil {
+		for _, cause := range status.Status().Details.Causes {
+			if cause.Type == metav1.CauseTypeFieldManagerConflict {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// RetryPolicy configures how an operation on an individual object is retried
+// when it fails with a retriable error. The zero value disables retries.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first
+	// one. Values less than 2 disable retries.
+	MaxAttempts int
+	// Backoff computes the delay between attempts. If nil, DefaultStrategy
+	// is used.
+	Backoff Strategy
+	// Retriable returns true if the error should be retried. If nil,
+	// IsRetriable is used.
+	Retriable func(error) bool
+}
+
+// Do calls fn, retrying it according to the policy. Returns nil on success,
+// otherwise the last error returned by fn.
+func (p RetryPolicy) Do(ctx context.Context, fn func() error) error {
+	if p.MaxAttempts < 2 {
+		return fn()
+	}
+	s := p.Backoff
+	if s == nil {
+		s = DefaultStrategy
+	}
+	retriable := p.Retriable
+	if retriable == nil {
+		retriable = IsRetriable
+	}
+	return Retry(ctx, s, p.MaxAttempts, retriable, fn)
+}
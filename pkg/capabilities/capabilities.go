@@ -0,0 +1,121 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package capabilities probes the features supported by a cluster, so
+// callers can gate features on what the server supports. The Applier uses it
+// to reject options that the server does not support, e.g. server-side apply
+// or server-side dry-run, and to check the requirements of the objects,
+// before applying them. It does not change the apply strategy.
+package capabilities
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	flowcontrolapi "k8s.io/api/flowcontrol/v1"
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/client-go/discovery"
+	"k8s.io/klog/v2"
+)
+
+var (
+	// serverSideApplyVersion is the first version with server-side apply
+	// enabled by default (beta).
+	serverSideApplyVersion = version.MajorMinor(1, 16)
+	// dryRunVersion is the first version with server-side dry-run enabled
+	// by default (beta).
+	dryRunVersion = version.MajorMinor(1, 13)
+	// fieldValidationVersion is the first version with server-side field
+	// validation enabled by default (beta).
+	fieldValidationVersion = version.MajorMinor(1, 25)
+)
+
+// Capabilities are the features supported by a cluster.
+type Capabilities struct {
+	// ServerVersion is the version of the API server, as reported by the
+	// server (e.g. "v1.28.3").
+	ServerVersion string
+	// ServerSideApply is true if the server supports server-side apply.
+	ServerSideApply bool
+	// DryRun is true if the server supports server-side dry-run.
+	DryRun bool
+	// FieldValidation is true if the server supports server-side field
+	// validation.
+	FieldValidation bool
+	// FlowControl is true if the server serves the flow control APIs used
+	// by API Priority and Fairness.
+	FlowControl bool
+	// APIVersions are the API versions served by the server, in the
+	// "group/version" form (e.g. "apps/v1", or "v1" for the core group),
+	// sorted.
+	APIVersions []string
+}
+
+// Probe queries the server version and API groups to find out which
+// features the cluster supports.
+//
+// Development builds of the API server may report a v0.0.0 version. In that
+// case, all the features that depend on the version are assumed to be
+// supported.
+func Probe(disco discovery.DiscoveryInterface) (*Capabilities, error) {
+	info, err := disco.ServerVersion()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server version: %w", err)
+	}
+	serverVersion, err := version.ParseGeneric(info.GitVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse server version %q: %w", info.GitVersion, err)
+	}
+	atLeast := func(min *version.Version) bool {
+		return serverVersion.Major() == 0 || serverVersion.AtLeast(min)
+	}
+
+	groups, err := disco.ServerGroups()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server groups: %w", err)
+	}
+	flowControl := false
+	var apiVersions []string
+	for _, group := range groups.Groups {
+		if group.Name == flowcontrolapi.GroupName {
+			flowControl = true
+		}
+		for _, v := range group.Versions {
+			apiVersions = append(apiVersions, v.GroupVersion)
+		}
+	}
+	sort.Strings(apiVersions)
+
+	caps := &Capabilities{
+		ServerVersion:   info.GitVersion,
+		ServerSideApply: atLeast(serverSideApplyVersion),
+		DryRun:          atLeast(dryRunVersion),
+		FieldValidation: atLeast(fieldValidationVersion),
+		FlowControl:     flowControl,
+		APIVersions:     apiVersions,
+	}
+	klog.V(4).Infof("cluster capabilities: %+v", *caps)
+	return caps, nil
+}
+
+// Prober probes the capabilities of a cluster the first time they are
+// requested and caches the result, including errors, so that the cluster is
+// probed at most once per check. Use a new Prober for each run, to pick up
+// changes to the cluster.
+type Prober struct {
+	Discovery discovery.DiscoveryInterface
+
+	once sync.Once
+	caps *Capabilities
+	err  error
+}
+
+// Capabilities returns the cached capabilities of the cluster, probing them
+// if needed.
+func (p *Prober) Capabilities() (*Capabilities, error) {
+	p.once.Do(func() {
+		p.caps, p.err = Probe(p.Discovery)
+	})
+	return p.caps, p.err
+}
@@ -0,0 +1,113 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package capabilities
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func newFakeDiscovery(gitVersion string, groupVersions ...string) *fakediscovery.FakeDiscovery {
+	disco := &fakediscovery.FakeDiscovery{
+		Fake:               &clienttesting.Fake{},
+		FakedServerVersion: &version.Info{GitVersion: gitVersion},
+	}
+	for _, gv := range groupVersions {
+		disco.Resources = append(disco.Resources, &metav1.APIResourceList{GroupVersion: gv})
+	}
+	return disco
+}
+
+func TestProbe(t *testing.T) {
+	testCases := map[string]struct {
+		gitVersion    string
+		groupVersions []string
+		expected      *Capabilities
+		expectedError string
+	}{
+		"recent server": {
+			gitVersion:    "v1.28.3-gke.1286000",
+			groupVersions: []string{"v1", "flowcontrol.apiserver.k8s.io/v1"},
+			expected: &Capabilities{
+				ServerVersion:   "v1.28.3-gke.1286000",
+				ServerSideApply: true,
+				DryRun:          true,
+				FieldValidation: true,
+				FlowControl:     true,
+				APIVersions:     []string{"flowcontrol.apiserver.k8s.io/v1", "v1"},
+			},
+		},
+		"server without field validation or flow control": {
+			gitVersion:    "v1.19.0",
+			groupVersions: []string{"v1"},
+			expected: &Capabilities{
+				ServerVersion:   "v1.19.0",
+				ServerSideApply: true,
+				DryRun:          true,
+				APIVersions:     []string{"v1"},
+			},
+		},
+		"old server": {
+			gitVersion:    "v1.12.10",
+			groupVersions: []string{"v1"},
+			expected: &Capabilities{
+				ServerVersion: "v1.12.10",
+				APIVersions:   []string{"v1"},
+			},
+		},
+		"development server": {
+			gitVersion: "v0.0.0-master+$Format:%H$",
+			expected: &Capabilities{
+				ServerVersion:   "v0.0.0-master+$Format:%H$",
+				ServerSideApply: true,
+				DryRun:          true,
+				FieldValidation: true,
+			},
+		},
+		"invalid version": {
+			gitVersion:    "unknown",
+			expectedError: `failed to parse server version "unknown": could not parse "unknown" as version`,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			caps, err := Probe(newFakeDiscovery(tc.gitVersion, tc.groupVersions...))
+			if tc.expectedError != "" {
+				require.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, caps)
+		})
+	}
+}
+
+func TestProberCachesResult(t *testing.T) {
+	disco := newFakeDiscovery("v1.28.0")
+	calls := 0
+	disco.AddReactor("get", "version", func(clienttesting.Action) (bool, runtime.Object, error) {
+		calls++
+		if calls > 1 {
+			return true, nil, errors.New("probed twice")
+		}
+		return false, nil, nil
+	})
+
+	prober := &Prober{Discovery: disco}
+	first, err := prober.Capabilities()
+	require.NoError(t, err)
+	second, err := prober.Capabilities()
+	require.NoError(t, err)
+	assert.Same(t, first, second)
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,217 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package capabilities
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/version"
+	"sigs.k8s.io/cli-utils/pkg/common"
+)
+
+// Requirements are the capabilities a set of objects requires from a
+// cluster.
+type Requirements struct {
+	// MinServerVersion is the minimum version of the API server
+	// (e.g. "v1.25" or "1.25.0"). Empty means any version.
+	MinServerVersion string
+	// APIVersions are the API versions that must be served by the server,
+	// in the "group/version" form (e.g. "apps/v1", or "v1" for the core
+	// group).
+	APIVersions []string
+	// APIGroups are the API groups that must be served by the server, in
+	// any version (e.g. "cert-manager.io").
+	APIGroups []string
+	// Kinds are the kinds that must be served by the server, but are not
+	// included in the set of objects, like custom resources installed by an
+	// operator.
+	Kinds []schema.GroupKind
+}
+
+// IsEmpty returns true if there are no requirements.
+func (r Requirements) IsEmpty() bool {
+	return r.MinServerVersion == "" && len(r.APIVersions) == 0 && len(r.APIGroups) == 0 &&
+		len(r.Kinds) == 0
+}
+
+// Merge returns the union of both requirements. The minimum server version
+// of other takes precedence, if set.
+func (r Requirements) Merge(other Requirements) Requirements {
+	merged := Requirements{
+		MinServerVersion: r.MinServerVersion,
+	}
+	if other.MinServerVersion != "" {
+		merged.MinServerVersion = other.MinServerVersion
+	}
+	seenVersions := make(map[string]struct{})
+	for _, apiVersion := range append(append([]string{}, r.APIVersions...), other.APIVersions...) {
+		if _, found := seenVersions[apiVersion]; !found {
+			seenVersions[apiVersion] = struct{}{}
+			merged.APIVersions = append(merged.APIVersions, apiVersion)
+		}
+	}
+	seenGroups := make(map[string]struct{})
+	for _, group := range append(append([]string{}, r.APIGroups...), other.APIGroups...) {
+		if _, found := seenGroups[group]; !found {
+			seenGroups[group] = struct{}{}
+			merged.APIGroups = append(merged.APIGroups, group)
+		}
+	}
+	seenKinds := make(map[schema.GroupKind]struct{})
+	for _, gk := range append(append([]schema.GroupKind{}, r.Kinds...), other.Kinds...) {
+		if _, found := seenKinds[gk]; !found {
+			seenKinds[gk] = struct{}{}
+			merged.Kinds = append(merged.Kinds, gk)
+		}
+	}
+	return merged
+}
+
+// ReadRequirements reads the requirements declared by the annotations of a
+// package metadata object, usually the inventory object:
+//
+//   - cli-utils.sigs.k8s.io/min-server-version: the minimum server version
+//   - cli-utils.sigs.k8s.io/required-api-versions: comma-separated API versions
+//   - cli-utils.sigs.k8s.io/required-api-groups: comma-separated API groups
+//   - cli-utils.sigs.k8s.io/required-kinds: comma-separated kinds, in the
+//     "Kind.group" form (e.g. "Certificate.cert-manager.io", or "Pod" for the
+//     core group)
+//
+// Returns empty requirements if obj is nil.
+func ReadRequirements(obj *unstructured.Unstructured) (Requirements, error) {
+	var req Requirements
+	if obj == nil {
+		return req, nil
+	}
+	annotations := obj.GetAnnotations()
+	req.MinServerVersion = strings.TrimSpace(annotations[common.MinServerVersionAnnotation])
+	if req.MinServerVersion != "" {
+		if _, err := version.ParseGeneric(req.MinServerVersion); err != nil {
+			return req, fmt.Errorf("invalid %s annotation: %w", common.MinServerVersionAnnotation, err)
+		}
+	}
+	req.APIVersions = splitList(annotations[common.RequiredAPIVersionsAnnotation])
+	req.APIGroups = splitList(annotations[common.RequiredAPIGroupsAnnotation])
+	for _, kind := range splitList(annotations[common.RequiredKindsAnnotation]) {
+		gk := schema.ParseGroupKind(kind)
+		if gk.Kind == "" {
+			return req, fmt.Errorf("invalid %s annotation: empty kind in %q",
+				common.RequiredKindsAnnotation, kind)
+		}
+		req.Kinds = append(req.Kinds, gk)
+	}
+	return req, nil
+}
+
+// splitList splits a comma-separated list, ignoring empty items.
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// UnmetRequirementsError is returned when a cluster does not meet the
+// requirements of a set of objects.
+type UnmetRequirementsError struct {
+	// ServerVersion is the version of the API server.
+	ServerVersion string
+	// MinServerVersion is the required minimum version of the API server, if
+	// the server version is older.
+	MinServerVersion string
+	// MissingAPIVersions are the required API versions that are not served
+	// by the server.
+	MissingAPIVersions []string
+	// MissingAPIGroups are the required API groups that are not served by
+	// the server, in any version.
+	MissingAPIGroups []string
+	// MissingKinds are the required kinds that are not served by the
+	// server.
+	MissingKinds []schema.GroupKind
+}
+
+func (e *UnmetRequirementsError) Error() string {
+	var b strings.Builder
+	b.WriteString("cluster does not meet the requirements:")
+	if e.MinServerVersion != "" {
+		fmt.Fprintf(&b, "\n- server version %s is older than the minimum version %s",
+			e.ServerVersion, e.MinServerVersion)
+	}
+	for _, apiVersion := range e.MissingAPIVersions {
+		fmt.Fprintf(&b, "\n- API version %s is not served", apiVersion)
+	}
+	for _, group := range e.MissingAPIGroups {
+		fmt.Fprintf(&b, "\n- API group %s is not served", group)
+	}
+	for _, gk := range e.MissingKinds {
+		fmt.Fprintf(&b, "\n- kind %s is not served", gk)
+	}
+	return b.String()
+}
+
+// Check returns an UnmetRequirementsError listing all the requirements the
+// cluster does not meet, or nil if all the requirements are met. Returns an
+// error if the minimum server version is invalid.
+//
+// The mapper is used to look up the required kinds, and may be nil if there
+// are none.
+//
+// Like in Probe, development builds of the API server reporting a v0.0.0
+// version are assumed to meet the minimum version.
+func (c *Capabilities) Check(req Requirements, mapper meta.RESTMapper) error {
+	unmet := &UnmetRequirementsError{
+		ServerVersion: c.ServerVersion,
+	}
+	if req.MinServerVersion != "" {
+		minVersion, err := version.ParseGeneric(req.MinServerVersion)
+		if err != nil {
+			return fmt.Errorf("invalid minimum server version %q: %w", req.MinServerVersion, err)
+		}
+		serverVersion, err := version.ParseGeneric(c.ServerVersion)
+		if err != nil {
+			return fmt.Errorf("failed to parse server version %q: %w", c.ServerVersion, err)
+		}
+		if serverVersion.Major() != 0 && !serverVersion.AtLeast(minVersion) {
+			unmet.MinServerVersion = req.MinServerVersion
+		}
+	}
+	served := make(map[string]struct{}, len(c.APIVersions))
+	servedGroups := make(map[string]struct{})
+	for _, apiVersion := range c.APIVersions {
+		served[apiVersion] = struct{}{}
+		if gv, err := schema.ParseGroupVersion(apiVersion); err == nil {
+			servedGroups[gv.Group] = struct{}{}
+		}
+	}
+	for _, apiVersion := range req.APIVersions {
+		if _, found := served[apiVersion]; !found {
+			unmet.MissingAPIVersions = append(unmet.MissingAPIVersions, apiVersion)
+		}
+	}
+	for _, group := range req.APIGroups {
+		if _, found := servedGroups[group]; !found {
+			unmet.MissingAPIGroups = append(unmet.MissingAPIGroups, group)
+		}
+	}
+	for _, gk := range req.Kinds {
+		if _, err := mapper.RESTMapping(gk); err != nil {
+			if !meta.IsNoMatchError(err) {
+				return fmt.Errorf("failed to look up kind %s: %w", gk, err)
+			}
+			unmet.MissingKinds = append(unmet.MissingKinds, gk)
+		}
+	}
+	if unmet.MinServerVersion == "" && len(unmet.MissingAPIVersions) == 0 &&
+		len(unmet.MissingAPIGroups) == 0 && len(unmet.MissingKinds) == 0 {
+		return nil
+	}
+	return unmet
+}
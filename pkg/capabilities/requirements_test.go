@@ -0,0 +1,214 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package capabilities
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta/testrestmapper"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/kubectl/pkg/scheme"
+	"sigs.k8s.io/cli-utils/pkg/testutil"
+)
+
+func TestCheck(t *testing.T) {
+	caps := &Capabilities{
+		ServerVersion: "v1.24.2",
+		APIVersions:   []string{"v1", "apps/v1", "batch/v1"},
+	}
+
+	testCases := map[string]struct {
+		caps          *Capabilities
+		requirements  Requirements
+		expectedError error
+		expectedMsg   string
+	}{
+		"no requirements": {
+			caps: caps,
+		},
+		"requirements met": {
+			caps: caps,
+			requirements: Requirements{
+				MinServerVersion: "1.24",
+				APIVersions:      []string{"apps/v1", "v1"},
+			},
+		},
+		"server too old and missing APIs": {
+			caps: caps,
+			requirements: Requirements{
+				MinServerVersion: "v1.25.0",
+				APIVersions:      []string{"apps/v1", "policy/v1", "example.com/v1"},
+			},
+			expectedError: &UnmetRequirementsError{
+				ServerVersion:      "v1.24.2",
+				MinServerVersion:   "v1.25.0",
+				MissingAPIVersions: []string{"policy/v1", "example.com/v1"},
+			},
+			expectedMsg: "cluster does not meet the requirements:\n" +
+				"- server version v1.24.2 is older than the minimum version v1.25.0\n" +
+				"- API version policy/v1 is not served\n" +
+				"- API version example.com/v1 is not served",
+		},
+		"required API groups": {
+			caps: caps,
+			requirements: Requirements{
+				APIGroups: []string{"", "apps", "cert-manager.io"},
+			},
+			expectedError: &UnmetRequirementsError{
+				ServerVersion:    "v1.24.2",
+				MissingAPIGroups: []string{"cert-manager.io"},
+			},
+			expectedMsg: "cluster does not meet the requirements:\n" +
+				"- API group cert-manager.io is not served",
+		},
+		"required kinds met": {
+			caps: caps,
+			requirements: Requirements{
+				Kinds: []schema.GroupKind{{Group: "apps", Kind: "Deployment"}, {Kind: "ConfigMap"}},
+			},
+		},
+		"missing kinds": {
+			caps: caps,
+			requirements: Requirements{
+				Kinds: []schema.GroupKind{
+					{Kind: "ConfigMap"},
+					{Group: "cert-manager.io", Kind: "Certificate"},
+				},
+			},
+			expectedError: &UnmetRequirementsError{
+				ServerVersion: "v1.24.2",
+				MissingKinds:  []schema.GroupKind{{Group: "cert-manager.io", Kind: "Certificate"}},
+			},
+			expectedMsg: "cluster does not meet the requirements:\n" +
+				"- kind Certificate.cert-manager.io is not served",
+		},
+		"development server meets any version": {
+			caps: &Capabilities{ServerVersion: "v0.0.0-master"},
+			requirements: Requirements{
+				MinServerVersion: "v1.30",
+			},
+		},
+		"invalid minimum version": {
+			caps: caps,
+			requirements: Requirements{
+				MinServerVersion: "latest",
+			},
+			expectedMsg: `invalid minimum server version "latest": could not parse "latest" as version`,
+		},
+	}
+
+	mapper := testrestmapper.TestOnlyStaticRESTMapper(scheme.Scheme,
+		scheme.Scheme.PrioritizedVersionsAllGroups()...)
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			err := tc.caps.Check(tc.requirements, mapper)
+			if tc.expectedMsg == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tc.expectedMsg)
+			if tc.expectedError != nil {
+				assert.Equal(t, tc.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestReadRequirements(t *testing.T) {
+	testCases := map[string]struct {
+		obj           string
+		expected      Requirements
+		expectedError string
+	}{
+		"no annotations": {
+			obj: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: inventory
+`,
+		},
+		"all annotations": {
+			obj: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: inventory
+  annotations:
+    cli-utils.sigs.k8s.io/min-server-version: v1.25
+    cli-utils.sigs.k8s.io/required-api-versions: "cert-manager.io/v1, monitoring.coreos.com/v1"
+    cli-utils.sigs.k8s.io/required-api-groups: "policy"
+    cli-utils.sigs.k8s.io/required-kinds: "Certificate.cert-manager.io,,ServiceMonitor.monitoring.coreos.com"
+`,
+			expected: Requirements{
+				MinServerVersion: "v1.25",
+				APIVersions:      []string{"cert-manager.io/v1", "monitoring.coreos.com/v1"},
+				APIGroups:        []string{"policy"},
+				Kinds: []schema.GroupKind{
+					{Group: "cert-manager.io", Kind: "Certificate"},
+					{Group: "monitoring.coreos.com", Kind: "ServiceMonitor"},
+				},
+			},
+		},
+		"invalid minimum version": {
+			obj: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: inventory
+  annotations:
+    cli-utils.sigs.k8s.io/min-server-version: latest
+`,
+			expectedError: `invalid cli-utils.sigs.k8s.io/min-server-version annotation: could not parse "latest" as version`,
+		},
+		"invalid kind": {
+			obj: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: inventory
+  annotations:
+    cli-utils.sigs.k8s.io/required-kinds: ".cert-manager.io"
+`,
+			expectedError: `invalid cli-utils.sigs.k8s.io/required-kinds annotation: empty kind in ".cert-manager.io"`,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			req, err := ReadRequirements(testutil.Unstructured(t, tc.obj))
+			if tc.expectedError != "" {
+				require.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, req)
+		})
+	}
+}
+
+func TestMergeRequirements(t *testing.T) {
+	certificate := schema.GroupKind{Group: "cert-manager.io", Kind: "Certificate"}
+	issuer := schema.GroupKind{Group: "cert-manager.io", Kind: "Issuer"}
+	merged := Requirements{
+		MinServerVersion: "v1.24",
+		APIVersions:      []string{"apps/v1"},
+		APIGroups:        []string{"cert-manager.io"},
+		Kinds:            []schema.GroupKind{certificate},
+	}.Merge(Requirements{
+		MinServerVersion: "v1.25",
+		APIVersions:      []string{"apps/v1", "batch/v1"},
+		APIGroups:        []string{"policy", "cert-manager.io"},
+		Kinds:            []schema.GroupKind{issuer, certificate},
+	})
+	assert.Equal(t, Requirements{
+		MinServerVersion: "v1.25",
+		APIVersions:      []string{"apps/v1", "batch/v1"},
+		APIGroups:        []string{"cert-manager.io", "policy"},
+		Kinds:            []schema.GroupKind{certificate, issuer},
+	}, merged)
+}
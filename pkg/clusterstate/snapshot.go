@@ -0,0 +1,133 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterstate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/cli-utils/pkg/common"
+	"sigs.k8s.io/cli-utils/pkg/inventory"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+// SnapshotSource is a Source that reads the live state from a recorded
+// snapshot of the cluster. Objects that are not in the snapshot do not
+// exist.
+type SnapshotSource struct {
+	objs map[object.ObjMetadata]*unstructured.Unstructured
+}
+
+var _ Source = &SnapshotSource{}
+
+// NewSnapshotSource returns a SnapshotSource with the given live objects.
+func NewSnapshotSource(objs object.UnstructuredSet) *SnapshotSource {
+	s := &SnapshotSource{
+		objs: make(map[object.ObjMetadata]*unstructured.Unstructured, len(objs)),
+	}
+	for _, obj := range objs {
+		s.objs[object.UnstructuredToObjMetadata(obj)] = obj
+	}
+	return s
+}
+
+// LoadSnapshot reads a snapshot from the YAML and JSON files in the
+// directory and its subdirectories, like the output of
+// "kubectl get -o yaml". Files may contain multiple documents, and lists of
+// objects.
+func LoadSnapshot(dir string) (*SnapshotSource, error) {
+	var objs object.UnstructuredSet
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !isSnapshotFile(path) {
+			return nil
+		}
+		fileObjs, err := readSnapshotFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read snapshot file %q: %w", path, err)
+		}
+		objs = append(objs, fileObjs...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return NewSnapshotSource(objs), nil
+}
+
+func (s *SnapshotSource) Get(_ context.Context, id object.ObjMetadata) (*unstructured.Unstructured, error) {
+	obj, found := s.objs[id]
+	if !found {
+		return nil, nil
+	}
+	return obj.DeepCopy(), nil
+}
+
+// InventoryObjects returns the objects stored in the inventory object of the
+// snapshot with the inventory ID and namespace of the inventory.
+func (s *SnapshotSource) InventoryObjects(_ context.Context, inv inventory.Info) (object.ObjMetadataSet, error) {
+	for _, obj := range s.objs {
+		if obj.GetNamespace() != inv.Namespace() || !inventory.IsInventoryObject(obj) ||
+			obj.GetLabels()[common.InventoryLabel] != inv.ID() {
+			continue
+		}
+		return inventory.WrapInventoryObj(obj).Load()
+	}
+	return nil, nil
+}
+
+// isSnapshotFile returns true if the file may contain objects.
+func isSnapshotFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
+
+// readSnapshotFile returns the objects in the file, expanding lists.
+func readSnapshotFile(path string) (object.UnstructuredSet, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var objs object.UnstructuredSet
+	decoder := utilyaml.NewYAMLOrJSONDecoder(f, 4096)
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(&obj.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				return objs, nil
+			}
+			return nil, err
+		}
+		if len(obj.Object) == 0 {
+			// Empty document.
+			continue
+		}
+		if !obj.IsList() {
+			objs = append(objs, obj)
+			continue
+		}
+		err := obj.EachListItem(func(item runtime.Object) error {
+			objs = append(objs, item.(*unstructured.Unstructured))
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+}
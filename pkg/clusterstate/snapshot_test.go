@@ -0,0 +1,141 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterstate
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/cli-utils/pkg/inventory"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/cli-utils/pkg/testutil"
+)
+
+var snapshotFiles = map[string]string{
+	"configmaps.yaml": `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: foo
+  namespace: default
+data:
+  key: value
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: inventory
+  namespace: default
+  labels:
+    cli-utils.sigs.k8s.io/inventory-id: test-inventory
+data:
+  default_foo__ConfigMap: ""
+  default_bar_apps_Deployment: ""
+`,
+	"apps/deployments.yml": `
+apiVersion: v1
+kind: List
+items:
+- apiVersion: apps/v1
+  kind: Deployment
+  metadata:
+    name: bar
+    namespace: default
+`,
+	"namespaces.json": `{"apiVersion": "v1", "kind": "Namespace", "metadata": {"name": "default"}}`,
+	"README.md":       "not a snapshot file",
+}
+
+func TestLoadSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range snapshotFiles {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+	source, err := LoadSnapshot(dir)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	testCases := map[string]struct {
+		id       object.ObjMetadata
+		expected bool
+	}{
+		"object in multi-document file": {
+			id:       object.ObjMetadata{GroupKind: schema.GroupKind{Kind: "ConfigMap"}, Namespace: "default", Name: "foo"},
+			expected: true,
+		},
+		"object in list": {
+			id:       object.ObjMetadata{GroupKind: schema.GroupKind{Group: "apps", Kind: "Deployment"}, Namespace: "default", Name: "bar"},
+			expected: true,
+		},
+		"object in json file": {
+			id:       object.ObjMetadata{GroupKind: schema.GroupKind{Kind: "Namespace"}, Name: "default"},
+			expected: true,
+		},
+		"missing object": {
+			id: object.ObjMetadata{GroupKind: schema.GroupKind{Kind: "ConfigMap"}, Namespace: "default", Name: "missing"},
+		},
+	}
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			obj, err := source.Get(ctx, tc.id)
+			require.NoError(t, err)
+			if !tc.expected {
+				assert.Nil(t, obj)
+				return
+			}
+			require.NotNil(t, obj)
+			assert.Equal(t, tc.id, object.UnstructuredToObjMetadata(obj))
+		})
+	}
+}
+
+func TestSnapshotSource_InventoryObjects(t *testing.T) {
+	source := NewSnapshotSource(object.UnstructuredSet{
+		testutil.Unstructured(t, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: inventory
+  namespace: default
+  labels:
+    cli-utils.sigs.k8s.io/inventory-id: test-inventory
+data:
+  default_foo__ConfigMap: ""
+`),
+	})
+	ctx := context.Background()
+
+	newInv := func(namespace, id string) inventory.Info {
+		return inventory.WrapInventoryInfoObj(testutil.Unstructured(t, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: inventory-template
+  namespace: `+namespace+`
+  labels:
+    cli-utils.sigs.k8s.io/inventory-id: `+id+`
+`))
+	}
+
+	ids, err := source.InventoryObjects(ctx, newInv("default", "test-inventory"))
+	require.NoError(t, err)
+	assert.Equal(t, object.ObjMetadataSet{
+		{GroupKind: schema.GroupKind{Kind: "ConfigMap"}, Namespace: "default", Name: "foo"},
+	}, ids)
+
+	ids, err = source.InventoryObjects(ctx, newInv("default", "other-inventory"))
+	require.NoError(t, err)
+	assert.Nil(t, ids)
+
+	ids, err = source.InventoryObjects(ctx, newInv("other", "test-inventory"))
+	require.NoError(t, err)
+	assert.Nil(t, ids)
+}
@@ -0,0 +1,71 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package clusterstate provides the live state of objects and inventories,
+// either read from a cluster, or from a recorded snapshot. Planning with a
+// snapshot, like diffing local objects against it, requires no access to
+// the cluster.
+package clusterstate
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/cli-utils/pkg/inventory"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+// Source provides the live state of objects and inventories.
+type Source interface {
+	// Get returns the live object, or nil if the object or its type does
+	// not exist.
+	Get(ctx context.Context, id object.ObjMetadata) (*unstructured.Unstructured, error)
+	// InventoryObjects returns the objects stored in the live inventory, or
+	// nil if the inventory does not exist.
+	InventoryObjects(ctx context.Context, inv inventory.Info) (object.ObjMetadataSet, error)
+}
+
+// ClusterSource is a Source that reads the live state from a cluster.
+type ClusterSource struct {
+	Client dynamic.Interface
+	Mapper meta.RESTMapper
+	// InvClient reads the inventories. Required by InventoryObjects.
+	InvClient inventory.Client
+}
+
+var _ Source = &ClusterSource{}
+
+func (s *ClusterSource) Get(ctx context.Context, id object.ObjMetadata) (*unstructured.Unstructured, error) {
+	mapping, err := s.Mapper.RESTMapping(id.GroupKind)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var live *unstructured.Unstructured
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		live, err = s.Client.Resource(mapping.Resource).Namespace(id.Namespace).Get(ctx, id.Name, metav1.GetOptions{})
+	} else {
+		live, err = s.Client.Resource(mapping.Resource).Get(ctx, id.Name, metav1.GetOptions{})
+	}
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get object %q: %w", id, err)
+	}
+	return live, nil
+}
+
+func (s *ClusterSource) InventoryObjects(_ context.Context, inv inventory.Info) (object.ObjMetadataSet, error) {
+	if s.InvClient == nil {
+		return nil, fmt.Errorf("no inventory client to read inventory %q", inv.ID())
+	}
+	return s.InvClient.GetClusterObjs(inv)
+}
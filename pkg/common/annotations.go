@@ -0,0 +1,363 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+// LifecycleDirective is the lifecycle behavior requested by a lifecycle
+// annotation.
+type LifecycleDirective int
+
+const (
+	// LifecycleDefault indicates the default lifecycle behavior.
+	LifecycleDefault LifecycleDirective = iota
+	// LifecyclePreventDeletion indicates the object must not be deleted.
+	LifecyclePreventDeletion
+	// LifecycleOrphanDependents indicates the object must be deleted with
+	// the Orphan propagation policy, so that its dependents are kept.
+	LifecycleOrphanDependents
+)
+
+// lifecycleAnnotations maps the lifecycle annotation keys to their supported
+// values, and the lifecycle behavior requested by each value.
+var lifecycleAnnotations = map[string]map[string]LifecycleDirective{
+	LifecycleDeleteAnnotation: {
+		PreventDeletion: LifecyclePreventDeletion,
+	},
+	OnRemoveAnnotation: {
+		OnRemoveKeep:   LifecyclePreventDeletion,
+		OnRemoveDelete: LifecycleDefault,
+		OnRemoveOrphan: LifecycleOrphanDependents,
+	},
+}
+
+// IsLifecycleAnnotation returns true if the passed annotation key is a
+// lifecycle annotation.
+func IsLifecycleAnnotation(key string) bool {
+	_, found := lifecycleAnnotations[key]
+	return found
+}
+
+// ParseLifecycleDirective parses the value of a lifecycle annotation.
+// Returns LifecycleDefault for annotation keys that are not lifecycle
+// annotations. Returns an *object.ParseError if the value is not supported.
+func ParseLifecycleDirective(key, value string) (LifecycleDirective, error) {
+	allowed, found := lifecycleAnnotations[key]
+	if !found {
+		return LifecycleDefault, nil
+	}
+	directive, found := allowed[value]
+	if !found {
+		values := make([]string, 0, len(allowed))
+		for v := range allowed {
+			values = append(values, strconv.Quote(v))
+		}
+		sort.Strings(values)
+		return LifecycleDefault, &object.ParseError{
+			Value:  value,
+			Offset: 0,
+			Length: len(value),
+			Cause: fmt.Errorf("unsupported value %q: supported values: %s",
+				value, strings.Join(values, ", ")),
+		}
+	}
+	return directive, nil
+}
+
+// ObjectLifecycleDirective returns the lifecycle behavior requested by the
+// lifecycle annotations of the object. Preventing deletion takes precedence
+// over the other directives. Invalid values are ignored.
+func ObjectLifecycleDirective(obj *unstructured.Unstructured) LifecycleDirective {
+	result := LifecycleDefault
+	for key, value := range obj.GetAnnotations() {
+		directive, err := ParseLifecycleDirective(key, value)
+		if err != nil {
+			continue
+		}
+		switch directive {
+		case LifecyclePreventDeletion:
+			return LifecyclePreventDeletion
+		case LifecycleOrphanDependents:
+			result = LifecycleOrphanDependents
+		}
+	}
+	return result
+}
+
+// ParseTimeout parses a timeout annotation value, formatted as a Go duration
+// string (e.g. "30s", "5m"). The timeout must be positive. Returns an
+// *object.ParseError if the value is not a valid timeout.
+func ParseTimeout(value string) (time.Duration, error) {
+	trimmed := strings.TrimSpace(value)
+	offset := strings.Index(value, trimmed)
+	timeout, err := time.ParseDuration(trimmed)
+	if err == nil && timeout <= 0 {
+		err = errors.New("timeout must be positive")
+	}
+	if err != nil {
+		return 0, &object.ParseError{
+			Value:  value,
+			Offset: offset,
+			Length: len(trimmed),
+			Cause:  fmt.Errorf("invalid timeout %q: %w", trimmed, err),
+		}
+	}
+	return timeout, nil
+}
+
+// GetReconcileTimeout returns the reconcile timeout requested by the
+// reconcile-timeout annotation of the resource, or zero if the annotation is
+// not set.
+func GetReconcileTimeout(u *unstructured.Unstructured) (time.Duration, error) {
+	value, found := u.GetAnnotations()[ReconcileTimeoutAnnotation]
+	if !found {
+		return 0, nil
+	}
+	return ParseTimeout(value)
+}
+
+// ApplyStrategy is the strategy used to apply a resource, requested by the
+// apply-strategy annotation.
+type ApplyStrategy string
+
+const (
+	// ApplyStrategyDefault applies the resource with the strategy of the
+	// applier run.
+	ApplyStrategyDefault ApplyStrategy = ""
+	// ApplyStrategyServerSide applies the resource with server-side apply.
+	ApplyStrategyServerSide ApplyStrategy = "server-side"
+	// ApplyStrategyClientSide applies the resource with client-side apply,
+	// using the last-applied-configuration annotation.
+	ApplyStrategyClientSide ApplyStrategy = "client-side"
+	// ApplyStrategyReplace replaces the resource with an update, discarding
+	// any fields that are not specified.
+	ApplyStrategyReplace ApplyStrategy = "replace"
+	// ApplyStrategyCreateOnly creates the resource if it does not exist, but
+	// never updates it. The apply of existing resources is reported as
+	// skipped.
+	ApplyStrategyCreateOnly ApplyStrategy = "create-only"
+)
+
+var applyStrategies = []ApplyStrategy{
+	ApplyStrategyServerSide,
+	ApplyStrategyClientSide,
+	ApplyStrategyReplace,
+	ApplyStrategyCreateOnly,
+}
+
+// ParseApplyStrategy parses the value of the apply-strategy annotation.
+// Returns an *object.ParseError if the value is not supported.
+func ParseApplyStrategy(value string) (ApplyStrategy, error) {
+	for _, strategy := range applyStrategies {
+		if value == string(strategy) {
+			return strategy, nil
+		}
+	}
+	return ApplyStrategyDefault, &object.ParseError{
+		Value:  value,
+		Offset: 0,
+		Length: len(value),
+		Cause:  fmt.Errorf("unsupported value %q: supported values: %q", value, applyStrategies),
+	}
+}
+
+// GetApplyStrategy returns the apply strategy requested by the
+// apply-strategy annotation of the resource, or ApplyStrategyDefault if the
+// annotation is not set.
+func GetApplyStrategy(u *unstructured.Unstructured) (ApplyStrategy, error) {
+	value, found := u.GetAnnotations()[ApplyStrategyAnnotation]
+	if !found {
+		return ApplyStrategyDefault, nil
+	}
+	return ParseApplyStrategy(value)
+}
+
+var deletionPropagations = []metav1.DeletionPropagation{
+	metav1.DeletePropagationForeground,
+	metav1.DeletePropagationBackground,
+	metav1.DeletePropagationOrphan,
+}
+
+// ParseDeletionPropagation parses the value of the deletion-propagation
+// annotation. Returns an *object.ParseError if the value is not supported.
+func ParseDeletionPropagation(value string) (metav1.DeletionPropagation, error) {
+	for _, propagation := range deletionPropagations {
+		if value == string(propagation) {
+			return propagation, nil
+		}
+	}
+	return "", &object.ParseError{
+		Value:  value,
+		Offset: 0,
+		Length: len(value),
+		Cause:  fmt.Errorf("unsupported value %q: supported values: %q", value, deletionPropagations),
+	}
+}
+
+// GetDeletionPropagation returns the propagation policy requested by the
+// deletion-propagation annotation of the resource, or an empty policy if the
+// annotation is not set.
+func GetDeletionPropagation(u *unstructured.Unstructured) (metav1.DeletionPropagation, error) {
+	value, found := u.GetAnnotations()[DeletionPropagationAnnotation]
+	if !found {
+		return "", nil
+	}
+	return ParseDeletionPropagation(value)
+}
+
+// ParseGracePeriodSeconds parses the value of the
+// deletion-grace-period-seconds annotation, a non-negative integer. Returns
+// an *object.ParseError if the value is not valid.
+func ParseGracePeriodSeconds(value string) (int64, error) {
+	trimmed := strings.TrimSpace(value)
+	offset := strings.Index(value, trimmed)
+	seconds, err := strconv.ParseInt(trimmed, 10, 64)
+	if err != nil || seconds < 0 {
+		return 0, &object.ParseError{
+			Value:  value,
+			Offset: offset,
+			Length: len(trimmed),
+			Cause:  fmt.Errorf("invalid grace period %q: must be a non-negative integer", trimmed),
+		}
+	}
+	return seconds, nil
+}
+
+// GetGracePeriodSeconds returns the grace period requested by the
+// deletion-grace-period-seconds annotation of the resource, or nil if the
+// annotation is not set.
+func GetGracePeriodSeconds(u *unstructured.Unstructured) (*int64, error) {
+	value, found := u.GetAnnotations()[DeletionGracePeriodAnnotation]
+	if !found {
+		return nil, nil
+	}
+	seconds, err := ParseGracePeriodSeconds(value)
+	if err != nil {
+		return nil, err
+	}
+	return &seconds, nil
+}
+
+// ParseApplyWave parses the value of the apply-wave annotation, an integer
+// that may be negative. Returns an *object.ParseError if the value is not an
+// integer.
+func ParseApplyWave(value string) (int, error) {
+	trimmed := strings.TrimSpace(value)
+	offset := strings.Index(value, trimmed)
+	wave, err := strconv.Atoi(trimmed)
+	if err != nil {
+		return 0, &object.ParseError{
+			Value:  value,
+			Offset: offset,
+			Length: len(trimmed),
+			Cause:  fmt.Errorf("invalid apply wave %q: must be an integer", trimmed),
+		}
+	}
+	return wave, nil
+}
+
+// GetApplyWave returns the apply wave requested by the apply-wave annotation
+// of the resource, or zero if the annotation is not set.
+func GetApplyWave(u *unstructured.Unstructured) (int, error) {
+	value, found := u.GetAnnotations()[ApplyWaveAnnotation]
+	if !found {
+		return 0, nil
+	}
+	return ParseApplyWave(value)
+}
+
+// ParseRecreate parses the value of the recreate annotation, "true" or
+// "false". Returns an *object.ParseError if the value is not supported.
+func ParseRecreate(value string) (bool, error) {
+	switch value {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	return false, &object.ParseError{
+		Value:  value,
+		Offset: 0,
+		Length: len(value),
+		Cause:  fmt.Errorf("unsupported value %q: supported values: %q", value, []string{"true", "false"}),
+	}
+}
+
+// GetRecreate returns true if the recreate annotation of the resource allows
+// the resource to be deleted and re-created, or false if the annotation is
+// not set.
+func GetRecreate(u *unstructured.Unstructured) (bool, error) {
+	value, found := u.GetAnnotations()[RecreateAnnotation]
+	if !found {
+		return false, nil
+	}
+	return ParseRecreate(value)
+}
+
+// ApplyHook is the phase of the apply in which a resource is applied,
+// requested by the apply-hook annotation. Each phase waits for its resources
+// to reconcile before continuing, like Helm hooks.
+type ApplyHook string
+
+const (
+	// ApplyHookNone applies the resource with the other resources.
+	ApplyHookNone ApplyHook = ""
+	// ApplyHookPreApply applies the resource before the other resources.
+	ApplyHookPreApply ApplyHook = "pre-apply"
+	// ApplyHookPostApply applies the resource after the other resources,
+	// before waiting for the last of them to reconcile.
+	ApplyHookPostApply ApplyHook = "post-apply"
+	// ApplyHookPostReconcile applies the resource after the other resources
+	// have reconciled.
+	ApplyHookPostReconcile ApplyHook = "post-reconcile"
+	// ApplyHookTest applies the resource, like a Job or Pod running smoke
+	// tests, after all the other resources, only if the release is verified.
+	// The resource is deleted after it completes, whether it succeeded or
+	// not.
+	ApplyHookTest ApplyHook = "test"
+)
+
+var applyHooks = []ApplyHook{
+	ApplyHookPreApply,
+	ApplyHookPostApply,
+	ApplyHookPostReconcile,
+	ApplyHookTest,
+}
+
+// ParseApplyHook parses the value of the apply-hook annotation. Returns an
+// *object.ParseError if the value is not supported.
+func ParseApplyHook(value string) (ApplyHook, error) {
+	for _, hook := range applyHooks {
+		if value == string(hook) {
+			return hook, nil
+		}
+	}
+	return ApplyHookNone, &object.ParseError{
+		Value:  value,
+		Offset: 0,
+		Length: len(value),
+		Cause:  fmt.Errorf("unsupported value %q: supported values: %q", value, applyHooks),
+	}
+}
+
+// GetApplyHook returns the apply hook requested by the apply-hook annotation
+// of the resource, or ApplyHookNone if the annotation is not set.
+func GetApplyHook(u *unstructured.Unstructured) (ApplyHook, error) {
+	value, found := u.GetAnnotations()[ApplyHookAnnotation]
+	if !found {
+		return ApplyHookNone, nil
+	}
+	return ParseApplyHook(value)
+}
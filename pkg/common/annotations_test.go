@@ -0,0 +1,476 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+func TestParseLifecycleDirective(t *testing.T) {
+	testCases := map[string]struct {
+		key      string
+		value    string
+		expected LifecycleDirective
+		isError  bool
+	}{
+		"unrelated annotation": {
+			key:      "example.com/foo",
+			value:    "keep",
+			expected: LifecycleDefault,
+		},
+		"on-remove keep": {
+			key:      OnRemoveAnnotation,
+			value:    OnRemoveKeep,
+			expected: LifecyclePreventDeletion,
+		},
+		"on-remove delete": {
+			key:      OnRemoveAnnotation,
+			value:    OnRemoveDelete,
+			expected: LifecycleDefault,
+		},
+		"on-remove orphan": {
+			key:      OnRemoveAnnotation,
+			value:    OnRemoveOrphan,
+			expected: LifecycleOrphanDependents,
+		},
+		"deletion detach": {
+			key:      LifecycleDeleteAnnotation,
+			value:    PreventDeletion,
+			expected: LifecyclePreventDeletion,
+		},
+		"on-remove invalid value": {
+			key:      OnRemoveAnnotation,
+			value:    "Keep",
+			expected: LifecycleDefault,
+			isError:  true,
+		},
+		"deletion invalid value": {
+			key:      LifecycleDeleteAnnotation,
+			value:    OnRemoveKeep,
+			expected: LifecycleDefault,
+			isError:  true,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			actual, err := ParseLifecycleDirective(tc.key, tc.value)
+			assert.Equal(t, tc.expected, actual)
+			if !tc.isError {
+				assert.NoError(t, err)
+				return
+			}
+			var parseErr *object.ParseError
+			if assert.True(t, errors.As(err, &parseErr)) {
+				assert.Equal(t, tc.value, parseErr.Invalid())
+			}
+		})
+	}
+}
+
+func TestObjectLifecycleDirective(t *testing.T) {
+	testCases := map[string]struct {
+		annotations map[string]string
+		expected    LifecycleDirective
+	}{
+		"no annotations": {
+			expected: LifecycleDefault,
+		},
+		"on-remove delete": {
+			annotations: map[string]string{OnRemoveAnnotation: OnRemoveDelete},
+			expected:    LifecycleDefault,
+		},
+		"on-remove orphan": {
+			annotations: map[string]string{OnRemoveAnnotation: OnRemoveOrphan},
+			expected:    LifecycleOrphanDependents,
+		},
+		"prevent deletion takes precedence": {
+			annotations: map[string]string{
+				OnRemoveAnnotation:        OnRemoveOrphan,
+				LifecycleDeleteAnnotation: PreventDeletion,
+			},
+			expected: LifecyclePreventDeletion,
+		},
+		"invalid value is ignored": {
+			annotations: map[string]string{OnRemoveAnnotation: "Keep"},
+			expected:    LifecycleDefault,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			obj.SetAnnotations(tc.annotations)
+			assert.Equal(t, tc.expected, ObjectLifecycleDirective(obj))
+		})
+	}
+}
+
+func TestParseTimeout(t *testing.T) {
+	testCases := map[string]struct {
+		value           string
+		expected        time.Duration
+		isError         bool
+		expectedInvalid string
+	}{
+		"seconds": {
+			value:    "30s",
+			expected: 30 * time.Second,
+		},
+		"minutes with whitespace": {
+			value:    " 5m ",
+			expected: 5 * time.Minute,
+		},
+		"missing unit": {
+			value:           " 30",
+			isError:         true,
+			expectedInvalid: "30",
+		},
+		"zero": {
+			value:           "0s",
+			isError:         true,
+			expectedInvalid: "0s",
+		},
+		"negative": {
+			value:           "-1m",
+			isError:         true,
+			expectedInvalid: "-1m",
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			actual, err := ParseTimeout(tc.value)
+			assert.Equal(t, tc.expected, actual)
+			if !tc.isError {
+				assert.NoError(t, err)
+				return
+			}
+			var parseErr *object.ParseError
+			if assert.True(t, errors.As(err, &parseErr)) {
+				assert.Equal(t, tc.expectedInvalid, parseErr.Invalid())
+			}
+		})
+	}
+}
+
+func TestGetApplyStrategy(t *testing.T) {
+	testCases := map[string]struct {
+		annotations map[string]string
+		expected    ApplyStrategy
+		isError     bool
+	}{
+		"no annotation": {
+			expected: ApplyStrategyDefault,
+		},
+		"server-side": {
+			annotations: map[string]string{ApplyStrategyAnnotation: "server-side"},
+			expected:    ApplyStrategyServerSide,
+		},
+		"client-side": {
+			annotations: map[string]string{ApplyStrategyAnnotation: "client-side"},
+			expected:    ApplyStrategyClientSide,
+		},
+		"replace": {
+			annotations: map[string]string{ApplyStrategyAnnotation: "replace"},
+			expected:    ApplyStrategyReplace,
+		},
+		"create-only": {
+			annotations: map[string]string{ApplyStrategyAnnotation: "create-only"},
+			expected:    ApplyStrategyCreateOnly,
+		},
+		"empty value": {
+			annotations: map[string]string{ApplyStrategyAnnotation: ""},
+			expected:    ApplyStrategyDefault,
+			isError:     true,
+		},
+		"unsupported value": {
+			annotations: map[string]string{ApplyStrategyAnnotation: "Replace"},
+			expected:    ApplyStrategyDefault,
+			isError:     true,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			u := &unstructured.Unstructured{}
+			u.SetAnnotations(tc.annotations)
+			actual, err := GetApplyStrategy(u)
+			assert.Equal(t, tc.expected, actual)
+			if !tc.isError {
+				assert.NoError(t, err)
+				return
+			}
+			var parseErr *object.ParseError
+			if assert.True(t, errors.As(err, &parseErr)) {
+				assert.Equal(t, tc.annotations[ApplyStrategyAnnotation], parseErr.Invalid())
+			}
+		})
+	}
+}
+
+func TestGetApplyWave(t *testing.T) {
+	testCases := map[string]struct {
+		annotations     map[string]string
+		expected        int
+		isError         bool
+		expectedInvalid string
+	}{
+		"no annotation": {
+			expected: 0,
+		},
+		"positive": {
+			annotations: map[string]string{ApplyWaveAnnotation: "5"},
+			expected:    5,
+		},
+		"negative with whitespace": {
+			annotations: map[string]string{ApplyWaveAnnotation: " -1 "},
+			expected:    -1,
+		},
+		"empty value": {
+			annotations:     map[string]string{ApplyWaveAnnotation: ""},
+			isError:         true,
+			expectedInvalid: "",
+		},
+		"not an integer": {
+			annotations:     map[string]string{ApplyWaveAnnotation: " 1.5"},
+			isError:         true,
+			expectedInvalid: "1.5",
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			u := &unstructured.Unstructured{}
+			u.SetAnnotations(tc.annotations)
+			actual, err := GetApplyWave(u)
+			assert.Equal(t, tc.expected, actual)
+			if !tc.isError {
+				assert.NoError(t, err)
+				return
+			}
+			var parseErr *object.ParseError
+			if assert.True(t, errors.As(err, &parseErr)) {
+				assert.Equal(t, tc.expectedInvalid, parseErr.Invalid())
+			}
+		})
+	}
+}
+
+func TestGetRecreate(t *testing.T) {
+	testCases := map[string]struct {
+		annotations map[string]string
+		expected    bool
+		isError     bool
+	}{
+		"no annotation": {
+			expected: false,
+		},
+		"true": {
+			annotations: map[string]string{RecreateAnnotation: "true"},
+			expected:    true,
+		},
+		"false": {
+			annotations: map[string]string{RecreateAnnotation: "false"},
+			expected:    false,
+		},
+		"unsupported value": {
+			annotations: map[string]string{RecreateAnnotation: "yes"},
+			isError:     true,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			u := &unstructured.Unstructured{}
+			u.SetAnnotations(tc.annotations)
+			actual, err := GetRecreate(u)
+			assert.Equal(t, tc.expected, actual)
+			if !tc.isError {
+				assert.NoError(t, err)
+				return
+			}
+			var parseErr *object.ParseError
+			assert.True(t, errors.As(err, &parseErr))
+		})
+	}
+}
+
+func TestGetReconcileTimeout(t *testing.T) {
+	testCases := map[string]struct {
+		annotations map[string]string
+		expected    time.Duration
+		isError     bool
+	}{
+		"no annotation": {
+			expected: 0,
+		},
+		"duration": {
+			annotations: map[string]string{ReconcileTimeoutAnnotation: "90s"},
+			expected:    90 * time.Second,
+		},
+		"zero": {
+			annotations: map[string]string{ReconcileTimeoutAnnotation: "0s"},
+			isError:     true,
+		},
+		"not a duration": {
+			annotations: map[string]string{ReconcileTimeoutAnnotation: "5"},
+			isError:     true,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			u := &unstructured.Unstructured{}
+			u.SetAnnotations(tc.annotations)
+			actual, err := GetReconcileTimeout(u)
+			assert.Equal(t, tc.expected, actual)
+			if !tc.isError {
+				assert.NoError(t, err)
+				return
+			}
+			var parseErr *object.ParseError
+			assert.True(t, errors.As(err, &parseErr))
+		})
+	}
+}
+
+func TestGetApplyHook(t *testing.T) {
+	testCases := map[string]struct {
+		annotations map[string]string
+		expected    ApplyHook
+		isError     bool
+	}{
+		"no annotation": {
+			expected: ApplyHookNone,
+		},
+		"pre-apply": {
+			annotations: map[string]string{ApplyHookAnnotation: "pre-apply"},
+			expected:    ApplyHookPreApply,
+		},
+		"post-reconcile": {
+			annotations: map[string]string{ApplyHookAnnotation: "post-reconcile"},
+			expected:    ApplyHookPostReconcile,
+		},
+		"test": {
+			annotations: map[string]string{ApplyHookAnnotation: "test"},
+			expected:    ApplyHookTest,
+		},
+		"unsupported value": {
+			annotations: map[string]string{ApplyHookAnnotation: "pre-install"},
+			isError:     true,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			u := &unstructured.Unstructured{}
+			u.SetAnnotations(tc.annotations)
+			actual, err := GetApplyHook(u)
+			assert.Equal(t, tc.expected, actual)
+			if !tc.isError {
+				assert.NoError(t, err)
+				return
+			}
+			var parseErr *object.ParseError
+			assert.True(t, errors.As(err, &parseErr))
+		})
+	}
+}
+
+func TestGetDeletionPropagation(t *testing.T) {
+	testCases := map[string]struct {
+		annotations map[string]string
+		expected    metav1.DeletionPropagation
+		isError     bool
+	}{
+		"no annotation": {
+			expected: "",
+		},
+		"foreground": {
+			annotations: map[string]string{DeletionPropagationAnnotation: "Foreground"},
+			expected:    metav1.DeletePropagationForeground,
+		},
+		"orphan": {
+			annotations: map[string]string{DeletionPropagationAnnotation: "Orphan"},
+			expected:    metav1.DeletePropagationOrphan,
+		},
+		"unsupported value": {
+			annotations: map[string]string{DeletionPropagationAnnotation: "orphan"},
+			isError:     true,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			u := &unstructured.Unstructured{}
+			u.SetAnnotations(tc.annotations)
+			actual, err := GetDeletionPropagation(u)
+			assert.Equal(t, tc.expected, actual)
+			if !tc.isError {
+				assert.NoError(t, err)
+				return
+			}
+			var parseErr *object.ParseError
+			assert.True(t, errors.As(err, &parseErr))
+		})
+	}
+}
+
+func TestGetGracePeriodSeconds(t *testing.T) {
+	zero := int64(0)
+	thirty := int64(30)
+	testCases := map[string]struct {
+		annotations     map[string]string
+		expected        *int64
+		isError         bool
+		expectedInvalid string
+	}{
+		"no annotation": {
+			expected: nil,
+		},
+		"zero": {
+			annotations: map[string]string{DeletionGracePeriodAnnotation: "0"},
+			expected:    &zero,
+		},
+		"seconds with whitespace": {
+			annotations: map[string]string{DeletionGracePeriodAnnotation: " 30 "},
+			expected:    &thirty,
+		},
+		"negative": {
+			annotations:     map[string]string{DeletionGracePeriodAnnotation: "-1"},
+			isError:         true,
+			expectedInvalid: "-1",
+		},
+		"duration": {
+			annotations:     map[string]string{DeletionGracePeriodAnnotation: "30s"},
+			isError:         true,
+			expectedInvalid: "30s",
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			u := &unstructured.Unstructured{}
+			u.SetAnnotations(tc.annotations)
+			actual, err := GetGracePeriodSeconds(u)
+			assert.Equal(t, tc.expected, actual)
+			if !tc.isError {
+				assert.NoError(t, err)
+				return
+			}
+			var parseErr *object.ParseError
+			if assert.True(t, errors.As(err, &parseErr)) {
+				assert.Equal(t, tc.expectedInvalid, parseErr.Invalid())
+			}
+		})
+	}
+}
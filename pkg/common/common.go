@@ -29,6 +29,12 @@ const (
 	OnRemoveAnnotation = "cli-utils.sigs.k8s.io/on-remove"
 	// Resource lifecycle annotation value to prevent deletion.
 	OnRemoveKeep = "keep"
+	// Resource lifecycle annotation value to delete the resource, the
+	// default.
+	OnRemoveDelete = "delete"
+	// Resource lifecycle annotation value to delete the resource, but keep
+	// its dependents (Orphan propagation policy).
+	OnRemoveOrphan = "orphan"
 	// Maximum random number, non-inclusive, eight digits.
 	maxRandInt = 100000000
 	// DefaultFieldManager is default owner of applied fields in
@@ -41,6 +47,71 @@ const (
 	// PreventDeletion is the value used with LifecycleDeletionAnnotation
 	// to prevent deleting a resource.
 	PreventDeletion = "detach"
+
+	// ApplyStrategyAnnotation is the annotation key that overrides the apply
+	// strategy of an individual resource.
+	ApplyStrategyAnnotation = "cli-utils.sigs.k8s.io/apply-strategy"
+
+	// ApplyWaveAnnotation is the annotation key that assigns a resource to
+	// an apply wave. Waves are applied in ascending order.
+	ApplyWaveAnnotation = "cli-utils.sigs.k8s.io/apply-wave"
+
+	// MinServerVersionAnnotation is the annotation key on the inventory
+	// object that declares the minimum server version of a package.
+	MinServerVersionAnnotation = "cli-utils.sigs.k8s.io/min-server-version"
+	// RequiredAPIVersionsAnnotation is the annotation key on the inventory
+	// object that declares the comma-separated API versions
+	// ("group/version") a package requires.
+	RequiredAPIVersionsAnnotation = "cli-utils.sigs.k8s.io/required-api-versions"
+	// RequiredAPIGroupsAnnotation is the annotation key on the inventory
+	// object that declares the comma-separated API groups a package requires,
+	// in any version.
+	RequiredAPIGroupsAnnotation = "cli-utils.sigs.k8s.io/required-api-groups"
+	// RequiredKindsAnnotation is the annotation key on the inventory object
+	// that declares the comma-separated kinds ("Kind.group") a package
+	// requires, but does not include, like custom resources installed by an
+	// operator.
+	RequiredKindsAnnotation = "cli-utils.sigs.k8s.io/required-kinds"
+
+	// RecreateAnnotation is the annotation key that allows the applier to
+	// delete and re-create a resource, when an update is rejected because it
+	// changes an immutable field.
+	RecreateAnnotation = "cli-utils.sigs.k8s.io/recreate"
+
+	// ApplyHookAnnotation is the annotation key that makes a resource an
+	// apply hook, applied before or after the other resources.
+	ApplyHookAnnotation = "cli-utils.sigs.k8s.io/apply-hook"
+
+	// ReconcileTimeoutAnnotation is the annotation key that overrides how
+	// long the applier waits for a resource to reconcile, as a Go duration.
+	ReconcileTimeoutAnnotation = "cli-utils.sigs.k8s.io/reconcile-timeout"
+
+	// AppliedHashAnnotation is the annotation key that records the hash of
+	// the last applied configuration of a resource, if enabled. Resources
+	// whose hash is unchanged are not applied again.
+	AppliedHashAnnotation = "cli-utils.sigs.k8s.io/applied-hash"
+
+	// RunIDAnnotation is the annotation key that records the ID of the last
+	// apply run that applied a resource, if enabled.
+	RunIDAnnotation = "cli-utils.sigs.k8s.io/run-id"
+
+	// DeletionPropagationAnnotation is the annotation key that overrides the
+	// propagation policy used to delete a resource: Foreground, Background
+	// or Orphan.
+	DeletionPropagationAnnotation = "cli-utils.sigs.k8s.io/deletion-propagation"
+
+	// DeletionGracePeriodAnnotation is the annotation key that overrides the
+	// grace period used to delete a resource, in seconds.
+	DeletionGracePeriodAnnotation = "cli-utils.sigs.k8s.io/deletion-grace-period-seconds"
+
+	// ProtectedKey is the default label or annotation key that protects a
+	// resource from being pruned or destroyed, when set to "true".
+	ProtectedKey = "cli-utils.sigs.k8s.io/protected"
+
+	// PruneMarkedAtAnnotation is the annotation key that records when a
+	// resource removed from the package was marked for deletion, in RFC 3339
+	// format, if the prune is delayed.
+	PruneMarkedAtAnnotation = "cli-utils.sigs.k8s.io/prune-marked-at"
 )
 
 // RandomStr returns an eight-digit (with leading zeros) string of a
@@ -53,14 +124,8 @@ func RandomStr() string {
 // NoDeletion checks the passed in annotation key and value and returns
 // true if that matches with the prevent deletion annotation.
 func NoDeletion(key, value string) bool {
-	m := map[string]string{
-		LifecycleDeleteAnnotation: PreventDeletion,
-		OnRemoveAnnotation:        OnRemoveKeep,
-	}
-	if val, found := m[key]; found {
-		return val == value
-	}
-	return false
+	directive, err := ParseLifecycleDirective(key, value)
+	return err == nil && directive == LifecyclePreventDeletion
 }
 
 var Strategies = []DryRunStrategy{DryRunClient, DryRunServer}
@@ -126,6 +191,13 @@ type ServerSideOptions struct {
 	// ForceConflicts overwrites the fields when applying if the field manager differs.
 	ForceConflicts bool
 
-	// FieldManager identifies the client "owner" of the applied fields (e.g. kubectl)
+	// ReportForcedConflicts reports the fields overwritten by ForceConflicts
+	// in the ForcedConflicts of the apply event. The conflicts are detected
+	// with an extra server-side dry-run apply of every object, and a GET of
+	// the objects with conflicts, so this is disabled by default.
+	ReportForcedConflicts bool
+
+	// FieldManager identifies the client "owner" of the applied fields (e.g. kubectl).
+	// Defaults to DefaultFieldManager for server-side apply.
 	FieldManager string
 }
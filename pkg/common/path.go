@@ -8,6 +8,9 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
+	"unicode"
 
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/klog/v2"
@@ -16,6 +19,10 @@ import (
 	"sigs.k8s.io/kustomize/kyaml/yaml"
 )
 
+// caseInsensitiveFS is true on the platforms whose filesystems are case
+// insensitive by default.
+var caseInsensitiveFS = runtime.GOOS == "windows" || runtime.GOOS == "darwin"
+
 const (
 	stdinDash    = "-"
 	tmpDirPrefix = "diff-cmd-config"
@@ -141,7 +148,10 @@ func FilterInputFile(in io.Reader, tmpDir string) error {
 // Returns an error if one occurred while processing the paths.
 func ExpandDir(dir string) (string, []string, error) {
 	filepaths := []string{}
-	r := kio.LocalPackageReader{PackagePath: dir}
+	r := kio.LocalPackageReader{
+		PackagePath:    dir,
+		MatchFilesGlob: ManifestFileGlobs(),
+	}
 	nodes, err := r.Read()
 	if err != nil {
 		return "", filepaths, err
@@ -153,7 +163,7 @@ func ExpandDir(dir string) (string, []string, error) {
 			continue
 		}
 		path := meta.Annotations[kioutil.PathAnnotation]
-		path = filepath.Join(dir, path)
+		path = filepath.Join(dir, filepath.FromSlash(path))
 		// If object has inventory label, skip it.
 		labels := meta.Labels
 		if _, exists := labels[InventoryLabel]; exists {
@@ -166,3 +176,71 @@ func ExpandDir(dir string) (string, []string, error) {
 	}
 	return invFilepath, filepaths, nil
 }
+
+// NormalizePath returns the absolute, cleaned path of the package directory
+// or file, with the symlinks resolved, so that a symlink to a package is read
+// like the package itself. Slashes are accepted as separators on every
+// platform. The path is made absolute so that the os package can handle
+// paths longer than MAX_PATH on Windows.
+func NormalizePath(path string) (string, error) {
+	if path == "" {
+		return "", fmt.Errorf("empty path")
+	}
+	path, err := filepath.Abs(filepath.FromSlash(path))
+	if err != nil {
+		return "", err
+	}
+	path, err = filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Clean(path), nil
+}
+
+// ManifestFileGlobs returns the patterns of the names of the manifest files
+// in a package directory. The patterns are case insensitive on the platforms
+// with case insensitive filesystems, so that "deployment.YAML" is read the
+// same way as "deployment.yaml".
+func ManifestFileGlobs() []string {
+	if !caseInsensitiveFS {
+		return kio.DefaultMatch
+	}
+	globs := make([]string, len(kio.DefaultMatch))
+	for i, glob := range kio.DefaultMatch {
+		globs[i] = caseInsensitiveGlob(glob)
+	}
+	return globs
+}
+
+// caseInsensitiveGlob replaces the letters of the pattern with character
+// classes of both cases, e.g. "*.yml" with "*.[yY][mM][lL]".
+func caseInsensitiveGlob(glob string) string {
+	var b strings.Builder
+	for _, r := range glob {
+		lower, upper := unicode.ToLower(r), unicode.ToUpper(r)
+		if lower == upper {
+			b.WriteRune(r)
+			continue
+		}
+		b.WriteString("[" + string(lower) + string(upper) + "]")
+	}
+	return b.String()
+}
+
+// SlashPathAnnotations replaces the OS-specific separators of the path
+// annotations of the resources with slashes, so that the sources of the
+// resources are the same on every platform.
+func SlashPathAnnotations(nodes []*yaml.RNode) error {
+	for _, n := range nodes {
+		for _, key := range []string{kioutil.PathAnnotation, kioutil.LegacyPathAnnotation} { //nolint:staticcheck
+			path, found := n.GetAnnotations()[key]
+			if !found || path == filepath.ToSlash(path) {
+				continue
+			}
+			if _, err := n.Pipe(yaml.SetAnnotation(key, filepath.ToSlash(path))); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
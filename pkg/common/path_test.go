@@ -378,3 +378,92 @@ func filepathExists(filepath string, filepaths []string) bool {
 	}
 	return false
 }
+
+func TestNormalizePath(t *testing.T) {
+	dir := t.TempDir()
+	// Resolve the symlinks of the temp dir itself, e.g. /tmp on macOS.
+	dir, err := filepath.EvalSymlinks(dir)
+	assert.NoError(t, err)
+	pkgDir := filepath.Join(dir, "pkg")
+	assert.NoError(t, os.Mkdir(pkgDir, 0o700))
+	link := filepath.Join(dir, "link")
+	assert.NoError(t, os.Symlink(pkgDir, link))
+
+	testCases := map[string]struct {
+		path     string
+		expected string
+		isError  bool
+	}{
+		"empty path is error": {
+			path:    "",
+			isError: true,
+		},
+		"missing path is error": {
+			path:    filepath.Join(dir, "missing"),
+			isError: true,
+		},
+		"directory": {
+			path:     pkgDir,
+			expected: pkgDir,
+		},
+		"slashes": {
+			path:     filepath.ToSlash(pkgDir) + "/",
+			expected: pkgDir,
+		},
+		"unclean path": {
+			path:     filepath.Join(dir, "pkg", "..", "pkg"),
+			expected: pkgDir,
+		},
+		"symlink is resolved": {
+			path:     link,
+			expected: pkgDir,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			path, err := NormalizePath(tc.path)
+			if tc.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, path)
+		})
+	}
+}
+
+func TestManifestFileGlobs(t *testing.T) {
+	defer func(v bool) { caseInsensitiveFS = v }(caseInsensitiveFS)
+
+	caseInsensitiveFS = false
+	assert.Equal(t, []string{"*.yaml", "*.yml"}, ManifestFileGlobs())
+
+	caseInsensitiveFS = true
+	globs := ManifestFileGlobs()
+	assert.Equal(t, []string{"*.[yY][aA][mM][lL]", "*.[yY][mM][lL]"}, globs)
+	for _, name := range []string{"pod.yaml", "pod.YAML", "pod.Yml"} {
+		matched := false
+		for _, glob := range globs {
+			match, err := filepath.Match(glob, name)
+			assert.NoError(t, err)
+			matched = matched || match
+		}
+		assert.Truef(t, matched, "expected %q to match", name)
+	}
+}
+
+func TestExpandDirSymlink(t *testing.T) {
+	tf := setupTestFilesystem(t)
+	defer tf.Clean()
+
+	link := filepath.Join(t.TempDir(), "link")
+	assert.NoError(t, os.Symlink(tf.GetRootDir(), link))
+	path, err := NormalizePath(link)
+	assert.NoError(t, err)
+
+	inv, paths, err := ExpandDir(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "inventory.yaml", filepath.Base(inv))
+	assert.Len(t, paths, 2)
+}
@@ -0,0 +1,348 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package diff computes the differences between local objects and the live
+// objects in the cluster.
+//
+// The local object is merged into the live object the same way
+// client-side apply would: with a three-way strategic merge patch for the
+// built-in types, and a three-way JSON merge patch for other types, using
+// the last-applied configuration of the live object as the original. The
+// live and merged objects are then compared line by line, as YAML.
+package diff
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	jsonpatch "gopkg.in/evanphx/json-patch.v4"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/jsonmergepatch"
+	"k8s.io/apimachinery/pkg/util/mergepatch"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/kubectl/pkg/scheme"
+	"sigs.k8s.io/cli-utils/pkg/clusterstate"
+	"sigs.k8s.io/cli-utils/pkg/inventory"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/yaml"
+)
+
+// lastAppliedAnnotation is the annotation used by client-side apply to store
+// the last applied configuration of an object.
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// contextLines is the number of unchanged lines around each hunk.
+const contextLines = 3
+
+// LineType is the type of a line in a hunk.
+type LineType int
+
+const (
+	// LineContext is a line that is the same in the live and merged objects.
+	LineContext LineType = iota
+	// LineRemoved is a line that is only in the live object.
+	LineRemoved
+	// LineAdded is a line that is only in the merged object.
+	LineAdded
+)
+
+// Line is a line of a hunk.
+type Line struct {
+	Type LineType
+	Text string
+}
+
+// Hunk is a group of changed lines, with the surrounding context lines.
+// Line numbers start at 1, like in unified diffs.
+type Hunk struct {
+	LiveStart   int
+	LiveLines   int
+	MergedStart int
+	MergedLines int
+	Lines       []Line
+}
+
+// ObjectDiff is the difference between a live object and the result of
+// applying the local object to it, or pruning the live object.
+type ObjectDiff struct {
+	Identifier object.ObjMetadata
+	// Live is the object in the cluster, or nil if it does not exist.
+	Live *unstructured.Unstructured
+	// Merged is the object that would result from applying the local object,
+	// or nil if the live object would be pruned.
+	Merged *unstructured.Unstructured
+	// Hunks are the changed lines between the live and merged objects,
+	// rendered as YAML.
+	Hunks []Hunk
+}
+
+// HasChanges returns true if applying the local object would change the
+// live object, or create it.
+func (d ObjectDiff) HasChanges() bool {
+	return len(d.Hunks) > 0
+}
+
+// Differ computes the diffs between local objects and the live objects in
+// the cluster.
+type Differ struct {
+	Client dynamic.Interface
+	Mapper meta.RESTMapper
+	// Source, if set, provides the live objects instead of the Client, for
+	// example from a recorded snapshot of the cluster.
+	Source clusterstate.Source
+}
+
+// Diff returns the diff of each local object, in the same order. Objects
+// that do not exist in the cluster, or whose type does not exist yet, are
+// diffed against an empty object.
+func (d *Differ) Diff(ctx context.Context, objs object.UnstructuredSet) ([]ObjectDiff, error) {
+	diffs := make([]ObjectDiff, 0, len(objs))
+	for _, obj := range objs {
+		live, err := d.getLiveObject(ctx, obj)
+		if err != nil {
+			return nil, err
+		}
+		objDiff, err := Compute(obj, live)
+		if err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, objDiff)
+	}
+	return diffs, nil
+}
+
+// DiffPrune returns the diff of each object of the live inventory that is
+// not in the local objects, and would be pruned. The objects of the
+// inventory that no longer exist are skipped. Requires a Source.
+func (d *Differ) DiffPrune(ctx context.Context, inv inventory.Info, objs object.UnstructuredSet) ([]ObjectDiff, error) {
+	if d.Source == nil {
+		return nil, fmt.Errorf("a source is required to read inventory %q", inv.ID())
+	}
+	invIDs, err := d.Source.InventoryObjects(ctx, inv)
+	if err != nil {
+		return nil, err
+	}
+	pruneIDs := invIDs.Diff(object.UnstructuredSetToObjMetadataSet(objs))
+	diffs := make([]ObjectDiff, 0, len(pruneIDs))
+	for _, id := range pruneIDs {
+		live, err := d.Source.Get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if live == nil {
+			continue
+		}
+		objDiff, err := Objects(id, live, nil)
+		if err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, objDiff)
+	}
+	return diffs, nil
+}
+
+// getLiveObject returns the object from the Source or the cluster, or nil if
+// the object or its type does not exist.
+func (d *Differ) getLiveObject(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	id := object.UnstructuredToObjMetadata(obj)
+	if d.Source != nil {
+		return d.Source.Get(ctx, id)
+	}
+	mapping, err := d.Mapper.RESTMapping(id.GroupKind, obj.GroupVersionKind().Version)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var live *unstructured.Unstructured
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		live, err = d.Client.Resource(mapping.Resource).Namespace(id.Namespace).Get(ctx, id.Name, metav1.GetOptions{})
+	} else {
+		live, err = d.Client.Resource(mapping.Resource).Get(ctx, id.Name, metav1.GetOptions{})
+	}
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get object %q: %w", id, err)
+	}
+	return live, nil
+}
+
+// Compute returns the diff between the live object and the result of
+// applying the local object to it. The live object may be nil, if it does
+// not exist.
+func Compute(local, live *unstructured.Unstructured) (ObjectDiff, error) {
+	id := object.UnstructuredToObjMetadata(local)
+	merged, err := Merge(local, live)
+	if err != nil {
+		return ObjectDiff{}, fmt.Errorf("failed to merge object %q: %w", id, err)
+	}
+	return Objects(id, live, merged)
+}
+
+// Objects returns the diff between the live object and the merged object,
+// for example the result of a server-side dry-run. Either object may be nil,
+// if it does not exist.
+func Objects(id object.ObjMetadata, live, merged *unstructured.Unstructured) (ObjectDiff, error) {
+	liveLines, err := toLines(live)
+	if err != nil {
+		return ObjectDiff{}, fmt.Errorf("failed to encode object %q: %w", id, err)
+	}
+	mergedLines, err := toLines(merged)
+	if err != nil {
+		return ObjectDiff{}, fmt.Errorf("failed to encode object %q: %w", id, err)
+	}
+	return ObjectDiff{
+		Identifier: id,
+		Live:       live,
+		Merged:     merged,
+		Hunks:      hunks(liveLines, mergedLines),
+	}, nil
+}
+
+// Merge returns the object that would result from applying the local object
+// to the live object with client-side apply. Returns a copy of the local
+// object if the live object is nil.
+func Merge(local, live *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	modified := local.DeepCopy()
+	// The last-applied configuration, and the path and index annotations
+	// added by the manifest reader, are not part of the desired state.
+	removeLastApplied(modified)
+	object.StripKyamlAnnotations(modified)
+	if len(modified.GetAnnotations()) == 0 {
+		modified.SetAnnotations(nil)
+	}
+	if live == nil {
+		return modified, nil
+	}
+	modifiedJSON, err := modified.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	currentJSON, err := live.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	var originalJSON []byte
+	if lastApplied, found := live.GetAnnotations()[lastAppliedAnnotation]; found {
+		originalJSON = []byte(lastApplied)
+	}
+
+	var mergedJSON []byte
+	versioned, err := scheme.Scheme.New(local.GroupVersionKind())
+	switch {
+	case err == nil:
+		mergedJSON, err = strategicMerge(originalJSON, modifiedJSON, currentJSON, versioned)
+	case runtime.IsNotRegisteredError(err):
+		mergedJSON, err = jsonMerge(originalJSON, modifiedJSON, currentJSON)
+	}
+	if err != nil {
+		return nil, err
+	}
+	merged := &unstructured.Unstructured{}
+	if err := merged.UnmarshalJSON(mergedJSON); err != nil {
+		return nil, err
+	}
+	return merged, nil
+}
+
+// strategicMerge applies a three-way strategic merge patch, like
+// client-side apply does for built-in types.
+func strategicMerge(original, modified, current []byte, versioned runtime.Object) ([]byte, error) {
+	patchMeta, err := strategicpatch.NewPatchMetaFromStruct(versioned)
+	if err != nil {
+		return nil, err
+	}
+	patch, err := strategicpatch.CreateThreeWayMergePatch(original, modified, current, patchMeta, true)
+	if err != nil {
+		return nil, err
+	}
+	return strategicpatch.StrategicMergePatch(current, patch, versioned)
+}
+
+// jsonMerge applies a three-way JSON merge patch, like client-side apply
+// does for types without a Go struct, like custom resources.
+func jsonMerge(original, modified, current []byte) ([]byte, error) {
+	preconditions := []mergepatch.PreconditionFunc{
+		mergepatch.RequireKeyUnchanged("apiVersion"),
+		mergepatch.RequireKeyUnchanged("kind"),
+		mergepatch.RequireMetadataKeyUnchanged("name"),
+	}
+	patch, err := jsonmergepatch.CreateThreeWayJSONMergePatch(original, modified, current, preconditions...)
+	if err != nil {
+		return nil, err
+	}
+	return jsonpatch.MergePatch(current, patch)
+}
+
+// removeLastApplied removes the last-applied configuration annotation.
+func removeLastApplied(obj *unstructured.Unstructured) {
+	annotations := obj.GetAnnotations()
+	if _, found := annotations[lastAppliedAnnotation]; !found {
+		return
+	}
+	delete(annotations, lastAppliedAnnotation)
+	obj.SetAnnotations(annotations)
+}
+
+// toLines renders the object as YAML lines, without the fields that are
+// only noise in a diff. Returns no lines if the object is nil.
+func toLines(obj *unstructured.Unstructured) ([]string, error) {
+	if obj == nil {
+		return nil, nil
+	}
+	obj = obj.DeepCopy()
+	removeLastApplied(obj)
+	object.StripServerManagedMetadata(obj)
+	data, err := yaml.Marshal(obj.Object)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n"), nil
+}
+
+// hunks returns the hunks of changed lines between the live and merged
+// lines.
+func hunks(live, merged []string) []Hunk {
+	matcher := difflib.NewMatcherWithJunk(live, merged, false, nil)
+	var result []Hunk
+	for _, group := range matcher.GetGroupedOpCodes(contextLines) {
+		first, last := group[0], group[len(group)-1]
+		hunk := Hunk{
+			LiveStart:   first.I1 + 1,
+			LiveLines:   last.I2 - first.I1,
+			MergedStart: first.J1 + 1,
+			MergedLines: last.J2 - first.J1,
+		}
+		for _, op := range group {
+			if op.Tag == 'e' {
+				hunk.Lines = appendLines(hunk.Lines, LineContext, live[op.I1:op.I2])
+				continue
+			}
+			if op.Tag == 'r' || op.Tag == 'd' {
+				hunk.Lines = appendLines(hunk.Lines, LineRemoved, live[op.I1:op.I2])
+			}
+			if op.Tag == 'r' || op.Tag == 'i' {
+				hunk.Lines = appendLines(hunk.Lines, LineAdded, merged[op.J1:op.J2])
+			}
+		}
+		result = append(result, hunk)
+	}
+	return result
+}
+
+func appendLines(lines []Line, lineType LineType, texts []string) []Line {
+	for _, text := range texts {
+		lines = append(lines, Line{Type: lineType, Text: text})
+	}
+	return lines
+}
@@ -0,0 +1,443 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta/testrestmapper"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic/fake"
+	"k8s.io/kubectl/pkg/scheme"
+	"sigs.k8s.io/cli-utils/pkg/clusterstate"
+	"sigs.k8s.io/cli-utils/pkg/inventory"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/cli-utils/pkg/testutil"
+)
+
+var localDeployment = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: foo
+  namespace: default
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:v2
+`
+
+var liveDeployment = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: foo
+  namespace: default
+  labels:
+    removed: "true"
+  annotations:
+    controller: set
+    kubectl.kubernetes.io/last-applied-configuration: |
+      {"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"foo","namespace":"default","labels":{"removed":"true"}},"spec":{"replicas":1,"template":{"spec":{"containers":[{"name":"app","image":"app:v1"}]}}}}
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:v1
+        imagePullPolicy: IfNotPresent
+`
+
+var localCustom = `
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: bar
+  namespace: default
+spec:
+  size: large
+  items: [a, b]
+`
+
+var liveCustom = `
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: bar
+  namespace: default
+  annotations:
+    kubectl.kubernetes.io/last-applied-configuration: |
+      {"apiVersion":"example.com/v1","kind":"Widget","metadata":{"name":"bar","namespace":"default"},"spec":{"size":"small","items":["a"],"color":"red"}}
+spec:
+  size: small
+  items: [a]
+  color: red
+  extra: set
+`
+
+func TestMerge(t *testing.T) {
+	testCases := map[string]struct {
+		local    string
+		live     string
+		expected string
+	}{
+		"object does not exist": {
+			local:    localDeployment,
+			expected: localDeployment,
+		},
+		"path and index annotations removed": {
+			local: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: foo
+  annotations:
+    config.kubernetes.io/index: "0"
+    config.kubernetes.io/path: configmap.yaml
+    internal.config.kubernetes.io/path: configmap.yaml
+`,
+			expected: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: foo
+`,
+		},
+		"strategic merge for built-in types": {
+			local: localDeployment,
+			live:  liveDeployment,
+			expected: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: foo
+  namespace: default
+  annotations:
+    controller: set
+    kubectl.kubernetes.io/last-applied-configuration: |
+      {"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"foo","namespace":"default","labels":{"removed":"true"}},"spec":{"replicas":1,"template":{"spec":{"containers":[{"name":"app","image":"app:v1"}]}}}}
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:v2
+        imagePullPolicy: IfNotPresent
+`,
+		},
+		"json merge for custom types": {
+			local: localCustom,
+			live:  liveCustom,
+			expected: `
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: bar
+  namespace: default
+  annotations:
+    kubectl.kubernetes.io/last-applied-configuration: |
+      {"apiVersion":"example.com/v1","kind":"Widget","metadata":{"name":"bar","namespace":"default"},"spec":{"size":"small","items":["a"],"color":"red"}}
+spec:
+  size: large
+  items: [a, b]
+  extra: set
+`,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			var live *unstructured.Unstructured
+			if tc.live != "" {
+				live = testutil.Unstructured(t, tc.live)
+			}
+			merged, err := Merge(testutil.Unstructured(t, tc.local), live)
+			require.NoError(t, err)
+			assert.Equal(t, testutil.Unstructured(t, tc.expected), merged)
+		})
+	}
+}
+
+func TestCompute(t *testing.T) {
+	testCases := map[string]struct {
+		local         string
+		live          string
+		expectedHunks []Hunk
+	}{
+		"object does not exist": {
+			local: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: foo
+`,
+			expectedHunks: []Hunk{
+				{
+					LiveStart:   1,
+					LiveLines:   0,
+					MergedStart: 1,
+					MergedLines: 4,
+					Lines: []Line{
+						{Type: LineAdded, Text: "apiVersion: v1"},
+						{Type: LineAdded, Text: "kind: ConfigMap"},
+						{Type: LineAdded, Text: "metadata:"},
+						{Type: LineAdded, Text: "  name: foo"},
+					},
+				},
+			},
+		},
+		"no changes": {
+			local: localDeployment,
+			live: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: foo
+  namespace: default
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:v2
+`,
+		},
+		"changed fields": {
+			local: localDeployment,
+			live:  liveDeployment,
+			expectedHunks: []Hunk{
+				{
+					LiveStart:   3,
+					LiveLines:   15,
+					MergedStart: 3,
+					MergedLines: 13,
+					Lines: []Line{
+						{Type: LineContext, Text: "metadata:"},
+						{Type: LineContext, Text: "  annotations:"},
+						{Type: LineContext, Text: "    controller: set"},
+						{Type: LineRemoved, Text: "  labels:"},
+						{Type: LineRemoved, Text: `    removed: "true"`},
+						{Type: LineContext, Text: "  name: foo"},
+						{Type: LineContext, Text: "  namespace: default"},
+						{Type: LineContext, Text: "spec:"},
+						{Type: LineRemoved, Text: "  replicas: 1"},
+						{Type: LineAdded, Text: "  replicas: 2"},
+						{Type: LineContext, Text: "  template:"},
+						{Type: LineContext, Text: "    spec:"},
+						{Type: LineContext, Text: "      containers:"},
+						{Type: LineRemoved, Text: "      - image: app:v1"},
+						{Type: LineAdded, Text: "      - image: app:v2"},
+						{Type: LineContext, Text: "        imagePullPolicy: IfNotPresent"},
+						{Type: LineContext, Text: "        name: app"},
+					},
+				},
+			},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			local := testutil.Unstructured(t, tc.local)
+			var live *unstructured.Unstructured
+			if tc.live != "" {
+				live = testutil.Unstructured(t, tc.live)
+			}
+			objDiff, err := Compute(local, live)
+			require.NoError(t, err)
+			assert.Equal(t, object.UnstructuredToObjMetadata(local), objDiff.Identifier)
+			assert.Equal(t, tc.expectedHunks, objDiff.Hunks)
+			assert.Equal(t, len(tc.expectedHunks) > 0, objDiff.HasChanges())
+		})
+	}
+}
+
+func TestObjects(t *testing.T) {
+	testCases := map[string]struct {
+		live          string
+		merged        string
+		expectedHunks []Hunk
+	}{
+		"server-managed metadata ignored": {
+			live: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: foo
+  resourceVersion: "1"
+  generation: 1
+`,
+			merged: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: foo
+  resourceVersion: "2"
+  generation: 2
+`,
+		},
+		"object removed": {
+			live: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: foo
+`,
+			expectedHunks: []Hunk{
+				{
+					LiveStart:   1,
+					LiveLines:   4,
+					MergedStart: 1,
+					MergedLines: 0,
+					Lines: []Line{
+						{Type: LineRemoved, Text: "apiVersion: v1"},
+						{Type: LineRemoved, Text: "kind: ConfigMap"},
+						{Type: LineRemoved, Text: "metadata:"},
+						{Type: LineRemoved, Text: "  name: foo"},
+					},
+				},
+			},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			live := testutil.Unstructured(t, tc.live)
+			var merged *unstructured.Unstructured
+			if tc.merged != "" {
+				merged = testutil.Unstructured(t, tc.merged)
+			}
+			id := object.UnstructuredToObjMetadata(live)
+			objDiff, err := Objects(id, live, merged)
+			require.NoError(t, err)
+			assert.Equal(t, id, objDiff.Identifier)
+			assert.Equal(t, tc.expectedHunks, objDiff.Hunks)
+		})
+	}
+}
+
+func TestDiffer(t *testing.T) {
+	live := testutil.Unstructured(t, liveDeployment)
+	created := testutil.Unstructured(t, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: created
+  namespace: default
+`)
+	unchanged := testutil.Unstructured(t, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: unchanged
+  namespace: default
+`)
+
+	differ := &Differ{
+		Client: fake.NewSimpleDynamicClient(scheme.Scheme, []runtime.Object{
+			live, unchanged.DeepCopy(),
+		}...),
+		Mapper: testrestmapper.TestOnlyStaticRESTMapper(scheme.Scheme,
+			scheme.Scheme.PrioritizedVersionsAllGroups()...),
+	}
+	diffs, err := differ.Diff(context.Background(), object.UnstructuredSet{
+		testutil.Unstructured(t, localDeployment),
+		created,
+		unchanged,
+		// Unknown types are diffed against an empty object
+		testutil.Unstructured(t, localCustom),
+	})
+	require.NoError(t, err)
+	require.Len(t, diffs, 4)
+	assert.Equal(t, live, diffs[0].Live)
+	assert.True(t, diffs[0].HasChanges())
+	assert.Nil(t, diffs[1].Live)
+	assert.True(t, diffs[1].HasChanges())
+	assert.Equal(t, unchanged, diffs[2].Live)
+	assert.False(t, diffs[2].HasChanges())
+	assert.Nil(t, diffs[3].Live)
+	assert.True(t, diffs[3].HasChanges())
+
+	var out bytes.Buffer
+	require.NoError(t, Render(&out, diffs[1:3]))
+	assert.Equal(t, `--- /dev/null
++++ merged/default_created__ConfigMap
+@@ -0,0 +1,5 @@
++apiVersion: v1
++kind: ConfigMap
++metadata:
++  name: created
++  namespace: default
+`, out.String())
+}
+
+func TestDiffer_Snapshot(t *testing.T) {
+	inv := testutil.Unstructured(t, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: inventory
+  namespace: default
+  labels:
+    cli-utils.sigs.k8s.io/inventory-id: test-inventory
+data:
+  default_kept__ConfigMap: ""
+  default_pruned__ConfigMap: ""
+  default_deleted__ConfigMap: ""
+`)
+	kept := testutil.Unstructured(t, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: kept
+  namespace: default
+`)
+	pruned := testutil.Unstructured(t, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: pruned
+  namespace: default
+`)
+	differ := &Differ{
+		Source: clusterstate.NewSnapshotSource(object.UnstructuredSet{inv, kept.DeepCopy(), pruned}),
+	}
+	ctx := context.Background()
+	objs := object.UnstructuredSet{kept}
+
+	diffs, err := differ.Diff(ctx, objs)
+	require.NoError(t, err)
+	require.Len(t, diffs, 1)
+	assert.Equal(t, kept, diffs[0].Live)
+	assert.False(t, diffs[0].HasChanges())
+
+	// The deleted object is in the inventory, but no longer exists.
+	pruneDiffs, err := differ.DiffPrune(ctx, inventory.WrapInventoryInfoObj(inv), objs)
+	require.NoError(t, err)
+	require.Len(t, pruneDiffs, 1)
+	assert.Equal(t, pruned, pruneDiffs[0].Live)
+	assert.Nil(t, pruneDiffs[0].Merged)
+
+	var out bytes.Buffer
+	require.NoError(t, Render(&out, pruneDiffs))
+	assert.Equal(t, `--- live/default_pruned__ConfigMap
++++ /dev/null
+@@ -1,5 +0,0 @@
+-apiVersion: v1
+-kind: ConfigMap
+-metadata:
+-  name: pruned
+-  namespace: default
+`, out.String())
+}
@@ -0,0 +1,69 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff
+
+import (
+	"fmt"
+	"io"
+)
+
+// Render writes the diffs that have changes in unified diff format. The live
+// object is the "from" file and the merged object is the "to" file. Objects
+// that do not exist in the cluster, or would be pruned, are diffed against
+// /dev/null.
+func Render(w io.Writer, diffs []ObjectDiff) error {
+	for _, d := range diffs {
+		if !d.HasChanges() {
+			continue
+		}
+		liveName := "live/" + d.Identifier.String()
+		if d.Live == nil {
+			liveName = "/dev/null"
+		}
+		mergedName := "merged/" + d.Identifier.String()
+		if d.Merged == nil {
+			mergedName = "/dev/null"
+		}
+		if _, err := fmt.Fprintf(w, "--- %s\n+++ %s\n", liveName, mergedName); err != nil {
+			return err
+		}
+		for _, h := range d.Hunks {
+			if _, err := fmt.Fprintf(w, "@@ -%s +%s @@\n",
+				formatRange(h.LiveStart, h.LiveLines),
+				formatRange(h.MergedStart, h.MergedLines)); err != nil {
+				return err
+			}
+			for _, line := range h.Lines {
+				if _, err := fmt.Fprintf(w, "%c%s\n", linePrefix(line.Type), line.Text); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// formatRange formats a hunk range like GNU diff: the length is omitted if
+// it is one, and an empty range starts at the line before it.
+func formatRange(start, length int) string {
+	switch length {
+	case 0:
+		return fmt.Sprintf("%d,0", start-1)
+	case 1:
+		return fmt.Sprintf("%d", start)
+	default:
+		return fmt.Sprintf("%d,%d", start, length)
+	}
+}
+
+func linePrefix(t LineType) rune {
+	switch t {
+	case LineRemoved:
+		return '-'
+	case LineAdded:
+		return '+'
+	default:
+		return ' '
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package inventory
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/cli-utils/pkg/common"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+// removeOwningInventoryPatch is a merge patch that removes the
+// owning-inventory annotation, without overwriting concurrent changes to the
+// other fields of the object.
+var removeOwningInventoryPatch = []byte(`{"metadata":{"annotations":{"` + OwningInventoryKey + `":null}}}`)
+
+// Abandon removes the objects from the inventory without deleting them, to
+// hand them off to another tool. Objects that are not in the inventory are
+// ignored.
+//
+// The owning-inventory annotation is removed from the live objects before
+// the inventory is updated, so that an interrupted hand-off never leaves an
+// object that the inventory could still prune. Returns a
+// TransferOwnershipError, before making any changes, if any live object is
+// owned by a different inventory.
+func Abandon(ctx context.Context, invClient Client, dc dynamic.Interface, mapper meta.RESTMapper,
+	inv Info, ids object.ObjMetadataSet, dryRun common.DryRunStrategy) error {
+	invIDs, err := invClient.GetClusterObjs(inv)
+	if err != nil {
+		return err
+	}
+	abandonIDs := invIDs.Intersection(ids)
+	if len(abandonIDs) == 0 {
+		klog.V(4).Infof("abandon: no objects in inventory %s/%s", inv.Namespace(), inv.Name())
+		return nil
+	}
+	live, err := getLiveObjects(ctx, dc, mapper, abandonIDs)
+	if err != nil {
+		return err
+	}
+	if err := checkOwnership(inv, live); err != nil {
+		return err
+	}
+	invStatus, err := clusterObjStatus(invClient, inv)
+	if err != nil {
+		return err
+	}
+	if !dryRun.ClientOrServerDryRun() {
+		for _, obj := range live {
+			if _, found := obj.GetAnnotations()[OwningInventoryKey]; !found {
+				continue
+			}
+			id := object.UnstructuredToObjMetadata(obj)
+			mapping, err := mapper.RESTMapping(id.GroupKind)
+			if err != nil {
+				return err
+			}
+			klog.V(4).Infof("removing owning inventory (object: %q, inventory: %q)", id, inv.ID())
+			if _, err := resourceClient(dc, mapping, id.Namespace).Patch(ctx, id.Name,
+				types.MergePatchType, removeOwningInventoryPatch, metav1.PatchOptions{}); err != nil {
+				return fmt.Errorf("failed to remove owning inventory of %q: %w", id, err)
+			}
+		}
+	}
+	remainingIDs := invIDs.Diff(abandonIDs)
+	return invClient.Replace(inv, remainingIDs, statusOf(invStatus, remainingIDs), dryRun)
+}
@@ -0,0 +1,121 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package inventory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta/testrestmapper"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic/fake"
+	"k8s.io/kubectl/pkg/scheme"
+	"sigs.k8s.io/cli-utils/pkg/apis/actuation"
+	"sigs.k8s.io/cli-utils/pkg/common"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+func TestAbandon(t *testing.T) {
+	inv := newTestInventory("inv", "inv-id")
+	podA := ownedPod("pod-a", "inv-id")
+	podB := ownedPod("pod-b", "inv-id")
+	podC := ownedPod("pod-c", "other-id")
+	idA := object.UnstructuredToObjMetadata(podA)
+	idB := object.UnstructuredToObjMetadata(podB)
+	idC := object.UnstructuredToObjMetadata(podC)
+	idMissing := object.UnstructuredToObjMetadata(ownedPod("pod-missing", "inv-id"))
+
+	testCases := map[string]struct {
+		invObjs       object.ObjMetadataSet
+		ids           object.ObjMetadataSet
+		dryRun        common.DryRunStrategy
+		expectedInv   object.ObjMetadataSet
+		expectedOwner string
+		expectedError error
+	}{
+		"abandoned object removed from inventory and annotation": {
+			invObjs:       object.ObjMetadataSet{idA, idB},
+			ids:           object.ObjMetadataSet{idA},
+			expectedInv:   object.ObjMetadataSet{idB},
+			expectedOwner: "",
+		},
+		"missing objects removed from inventory": {
+			invObjs:       object.ObjMetadataSet{idA, idB, idMissing},
+			ids:           object.ObjMetadataSet{idA, idMissing},
+			expectedInv:   object.ObjMetadataSet{idB},
+			expectedOwner: "",
+		},
+		"objects not in the inventory are ignored": {
+			invObjs:       object.ObjMetadataSet{idB},
+			ids:           object.ObjMetadataSet{idA},
+			expectedInv:   object.ObjMetadataSet{idB},
+			expectedOwner: "inv-id",
+		},
+		"dry-run does not remove annotation": {
+			invObjs:       object.ObjMetadataSet{idA, idB},
+			ids:           object.ObjMetadataSet{idA},
+			dryRun:        common.DryRunClient,
+			expectedInv:   object.ObjMetadataSet{idB},
+			expectedOwner: "inv-id",
+		},
+		"object owned by another inventory": {
+			invObjs:       object.ObjMetadataSet{idA, idC},
+			ids:           object.ObjMetadataSet{idA, idC},
+			expectedInv:   object.ObjMetadataSet{idA, idC},
+			expectedOwner: "inv-id",
+			expectedError: &TransferOwnershipError{Identifier: idC, OwningInventory: "other-id"},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			dc := fake.NewSimpleDynamicClient(scheme.Scheme, []runtime.Object{
+				podA.DeepCopy(), podB.DeepCopy(), podC.DeepCopy(),
+			}...)
+			mapper := testrestmapper.TestOnlyStaticRESTMapper(scheme.Scheme,
+				scheme.Scheme.PrioritizedVersionsAllGroups()...)
+			invClient := &multiFakeClient{invs: map[string]object.ObjMetadataSet{
+				"inv": tc.invObjs,
+			}}
+
+			err := Abandon(context.Background(), invClient, dc, mapper, inv, tc.ids, tc.dryRun)
+			if tc.expectedError != nil {
+				assert.Equal(t, tc.expectedError, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedInv, invClient.invs["inv"])
+			assert.Equal(t, tc.expectedOwner, getOwner(t, dc, idA))
+		})
+	}
+}
+
+func TestAbandonKeepsStatus(t *testing.T) {
+	inv := newTestInventory("inv", "inv-id")
+	podA := ownedPod("pod-a", "inv-id")
+	podB := ownedPod("pod-b", "inv-id")
+	idA := object.UnstructuredToObjMetadata(podA)
+	idB := object.UnstructuredToObjMetadata(podB)
+
+	dc := fake.NewSimpleDynamicClient(scheme.Scheme, podA.DeepCopy(), podB.DeepCopy())
+	mapper := testrestmapper.TestOnlyStaticRESTMapper(scheme.Scheme,
+		scheme.Scheme.PrioritizedVersionsAllGroups()...)
+	invClient := &multiFakeClient{
+		invs: map[string]object.ObjMetadataSet{
+			"inv": {idA, idB},
+		},
+		status: map[string][]actuation.ObjectStatus{
+			"inv": {succeededStatus(idA, "uid-a"), succeededStatus(idB, "uid-b")},
+		},
+	}
+
+	err := Abandon(context.Background(), invClient, dc, mapper, inv, object.ObjMetadataSet{idA}, common.DryRunNone)
+	require.NoError(t, err)
+	assert.Equal(t, object.ObjMetadataSet{idB}, invClient.invs["inv"])
+	assert.Equal(t, []actuation.ObjectStatus{succeededStatus(idB, "uid-b")}, invClient.status["inv"])
+	assert.Equal(t, "", getOwner(t, dc, idA))
+	assert.Equal(t, "inv-id", getOwner(t, dc, idB))
+}
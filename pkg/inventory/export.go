@@ -0,0 +1,240 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package inventory
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/cli-utils/pkg/apis/actuation"
+	"sigs.k8s.io/cli-utils/pkg/common"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// ResourceGroupAPIVersion is the apiVersion of the kpt ResourceGroup.
+	ResourceGroupAPIVersion = "kpt.dev/v1alpha1"
+	// ResourceGroupKind is the kind of the kpt ResourceGroup.
+	ResourceGroupKind = "ResourceGroup"
+
+	// helmReleaseSecretType is the type of the Secrets used by Helm 3 to
+	// store release records.
+	helmReleaseSecretType = "helm.sh/release.v1"
+)
+
+// ExportResourceGroup translates the contents of the inventory into a kpt
+// ResourceGroup object, with the same name, namespace and inventory ID. The
+// statuses are optional and are exported as the resource statuses of the
+// ResourceGroup.
+func ExportResourceGroup(inv Info, objs object.ObjMetadataSet, statuses []actuation.ObjectStatus) *unstructured.Unstructured {
+	resources := make([]interface{}, 0, len(objs))
+	for _, id := range objs {
+		resources = append(resources, objectReferenceMap(ObjectReferenceFromObjMetadata(id)))
+	}
+	rg := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": ResourceGroupAPIVersion,
+			"kind":       ResourceGroupKind,
+			"metadata": map[string]interface{}{
+				"name":      inv.Name(),
+				"namespace": inv.Namespace(),
+			},
+			"spec": map[string]interface{}{
+				"resources": resources,
+			},
+		},
+	}
+	if inv.ID() != "" {
+		rg.SetLabels(map[string]string{common.InventoryLabel: inv.ID()})
+	}
+	if len(statuses) > 0 {
+		resourceStatuses := make([]interface{}, 0, len(statuses))
+		for _, status := range statuses {
+			entry := objectReferenceMap(status.ObjectReference)
+			entry["strategy"] = status.Strategy.String()
+			entry["actuation"] = status.Actuation.String()
+			entry["reconcile"] = status.Reconcile.String()
+			resourceStatuses = append(resourceStatuses, entry)
+		}
+		rg.Object["status"] = map[string]interface{}{
+			"resourceStatuses": resourceStatuses,
+		}
+	}
+	return rg
+}
+
+func objectReferenceMap(ref actuation.ObjectReference) map[string]interface{} {
+	return map[string]interface{}{
+		"group":     ref.Group,
+		"kind":      ref.Kind,
+		"name":      ref.Name,
+		"namespace": ref.Namespace,
+	}
+}
+
+// HelmReleaseOptions are the options used to export an inventory as a Helm
+// release record.
+type HelmReleaseOptions struct {
+	// ReleaseName is the name of the release. Defaults to the name of the
+	// inventory.
+	ReleaseName string
+	// ChartName is the name of the chart. Defaults to the release name.
+	ChartName string
+	// ChartVersion is the version of the chart. Defaults to "0.0.0".
+	ChartVersion string
+	// Revision is the release revision. Defaults to 1.
+	Revision int
+	// Timestamp is the deployment time of the release. Defaults to now.
+	Timestamp time.Time
+}
+
+// helmRelease is the subset of the Helm 3 release record that is required
+// for Helm to recognize the release.
+type helmRelease struct {
+	Name      string          `json:"name"`
+	Info      helmReleaseInfo `json:"info"`
+	Chart     helmChart       `json:"chart"`
+	Manifest  string          `json:"manifest"`
+	Version   int             `json:"version"`
+	Namespace string          `json:"namespace"`
+}
+
+type helmReleaseInfo struct {
+	FirstDeployed time.Time `json:"first_deployed"`
+	LastDeployed  time.Time `json:"last_deployed"`
+	Description   string    `json:"description"`
+	Status        string    `json:"status"`
+}
+
+type helmChart struct {
+	Metadata helmChartMetadata `json:"metadata"`
+}
+
+type helmChartMetadata struct {
+	APIVersion string `json:"apiVersion"`
+	Name       string `json:"name"`
+	Version    string `json:"version"`
+}
+
+// ExportHelmRelease translates the objects of the inventory into a Helm 3
+// release record, stored in a Secret in the namespace of the inventory, like
+// Helm does. The objects are included in the release manifest, without
+// status and server-managed metadata.
+//
+// Helm only adopts objects that are annotated with the release name and
+// namespace, so the live objects must be annotated before they can be
+// managed by Helm.
+func ExportHelmRelease(inv Info, objs object.UnstructuredSet, opts HelmReleaseOptions) (*unstructured.Unstructured, error) {
+	if opts.ReleaseName == "" {
+		opts.ReleaseName = inv.Name()
+	}
+	if opts.ChartName == "" {
+		opts.ChartName = opts.ReleaseName
+	}
+	if opts.ChartVersion == "" {
+		opts.ChartVersion = "0.0.0"
+	}
+	if opts.Revision == 0 {
+		opts.Revision = 1
+	}
+	if opts.Timestamp.IsZero() {
+		opts.Timestamp = time.Now()
+	}
+
+	manifest, err := helmManifest(objs)
+	if err != nil {
+		return nil, err
+	}
+	release := helmRelease{
+		Name: opts.ReleaseName,
+		Info: helmReleaseInfo{
+			FirstDeployed: opts.Timestamp,
+			LastDeployed:  opts.Timestamp,
+			Description:   fmt.Sprintf("Exported from inventory %s/%s", inv.Namespace(), inv.Name()),
+			Status:        "deployed",
+		},
+		Chart: helmChart{
+			Metadata: helmChartMetadata{
+				APIVersion: "v2",
+				Name:       opts.ChartName,
+				Version:    opts.ChartVersion,
+			},
+		},
+		Manifest:  manifest,
+		Version:   opts.Revision,
+		Namespace: inv.Namespace(),
+	}
+	encoded, err := encodeHelmRelease(release)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"metadata": map[string]interface{}{
+				"name":      fmt.Sprintf("sh.helm.release.v1.%s.v%d", opts.ReleaseName, opts.Revision),
+				"namespace": inv.Namespace(),
+			},
+			"type": helmReleaseSecretType,
+			"data": map[string]interface{}{
+				// Secret data is base64 encoded, on top of the release encoding.
+				"release": base64.StdEncoding.EncodeToString([]byte(encoded)),
+			},
+		},
+	}
+	secret.SetLabels(map[string]string{
+		"name":    opts.ReleaseName,
+		"owner":   "helm",
+		"status":  "deployed",
+		"version": fmt.Sprintf("%d", opts.Revision),
+	})
+	return secret, nil
+}
+
+// helmManifest returns the objects as a multi-document YAML manifest,
+// without status and server-managed metadata.
+func helmManifest(objs object.UnstructuredSet) (string, error) {
+	docs := make([]string, 0, len(objs))
+	for _, obj := range objs {
+		obj = obj.DeepCopy()
+		delete(obj.Object, "status")
+		object.StripServerManagedMetadata(obj)
+		data, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode object %q: %w", object.UnstructuredToObjMetadata(obj), err)
+		}
+		docs = append(docs, "---\n"+string(data))
+	}
+	return strings.Join(docs, ""), nil
+}
+
+// encodeHelmRelease encodes the release like Helm does: gzipped JSON,
+// base64 encoded.
+func encodeHelmRelease(release helmRelease) (string, error) {
+	data, err := json.Marshal(release)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	w, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return "", err
+	}
+	if _, err := w.Write(data); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
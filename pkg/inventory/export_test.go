@@ -0,0 +1,172 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package inventory
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/cli-utils/pkg/apis/actuation"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+func TestExportResourceGroup(t *testing.T) {
+	inv := newTestInventory("inventory", "inventory-id")
+	podA := ownedPod("pod-a", "inventory-id")
+	idA := object.UnstructuredToObjMetadata(podA)
+
+	testCases := map[string]struct {
+		statuses []actuation.ObjectStatus
+		expected map[string]interface{}
+	}{
+		"without statuses": {
+			expected: map[string]interface{}{
+				"apiVersion": "kpt.dev/v1alpha1",
+				"kind":       "ResourceGroup",
+				"metadata": map[string]interface{}{
+					"name":      "inventory",
+					"namespace": testNamespace,
+					"labels": map[string]interface{}{
+						"cli-utils.sigs.k8s.io/inventory-id": "inventory-id",
+					},
+				},
+				"spec": map[string]interface{}{
+					"resources": []interface{}{
+						map[string]interface{}{
+							"group":     "",
+							"kind":      "Pod",
+							"name":      "pod-a",
+							"namespace": testNamespace,
+						},
+					},
+				},
+			},
+		},
+		"with statuses": {
+			statuses: []actuation.ObjectStatus{
+				{
+					ObjectReference: ObjectReferenceFromObjMetadata(idA),
+					Strategy:        actuation.ActuationStrategyApply,
+					Actuation:       actuation.ActuationSucceeded,
+					Reconcile:       actuation.ReconcileSucceeded,
+				},
+			},
+			expected: map[string]interface{}{
+				"apiVersion": "kpt.dev/v1alpha1",
+				"kind":       "ResourceGroup",
+				"metadata": map[string]interface{}{
+					"name":      "inventory",
+					"namespace": testNamespace,
+					"labels": map[string]interface{}{
+						"cli-utils.sigs.k8s.io/inventory-id": "inventory-id",
+					},
+				},
+				"spec": map[string]interface{}{
+					"resources": []interface{}{
+						map[string]interface{}{
+							"group":     "",
+							"kind":      "Pod",
+							"name":      "pod-a",
+							"namespace": testNamespace,
+						},
+					},
+				},
+				"status": map[string]interface{}{
+					"resourceStatuses": []interface{}{
+						map[string]interface{}{
+							"group":     "",
+							"kind":      "Pod",
+							"name":      "pod-a",
+							"namespace": testNamespace,
+							"strategy":  "Apply",
+							"actuation": "Succeeded",
+							"reconcile": "Succeeded",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			rg := ExportResourceGroup(inv, object.ObjMetadataSet{idA}, tc.statuses)
+			assert.Equal(t, tc.expected, rg.Object)
+		})
+	}
+}
+
+func TestExportHelmRelease(t *testing.T) {
+	inv := newTestInventory("inventory", "inventory-id")
+	pod := ownedPod("pod-a", "inventory-id")
+	pod.SetResourceVersion("123")
+	pod.Object["status"] = map[string]interface{}{"phase": "Running"}
+	timestamp := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	secret, err := ExportHelmRelease(inv, object.UnstructuredSet{pod}, HelmReleaseOptions{
+		ChartVersion: "1.2.3",
+		Timestamp:    timestamp,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "sh.helm.release.v1.inventory.v1", secret.GetName())
+	assert.Equal(t, testNamespace, secret.GetNamespace())
+	assert.Equal(t, map[string]string{
+		"name":    "inventory",
+		"owner":   "helm",
+		"status":  "deployed",
+		"version": "1",
+	}, secret.GetLabels())
+	secretType, _, _ := unstructured.NestedString(secret.Object, "type")
+	assert.Equal(t, "helm.sh/release.v1", secretType)
+
+	// Decode the release like Helm does
+	data, _, _ := unstructured.NestedString(secret.Object, "data", "release")
+	encoded, err := base64.StdEncoding.DecodeString(data)
+	require.NoError(t, err)
+	compressed, err := base64.StdEncoding.DecodeString(string(encoded))
+	require.NoError(t, err)
+	r, err := gzip.NewReader(bytes.NewReader(compressed))
+	require.NoError(t, err)
+	decoded, err := io.ReadAll(r)
+	require.NoError(t, err)
+	var release helmRelease
+	require.NoError(t, json.Unmarshal(decoded, &release))
+
+	assert.Equal(t, helmRelease{
+		Name: "inventory",
+		Info: helmReleaseInfo{
+			FirstDeployed: timestamp,
+			LastDeployed:  timestamp,
+			Description:   "Exported from inventory " + testNamespace + "/inventory",
+			Status:        "deployed",
+		},
+		Chart: helmChart{
+			Metadata: helmChartMetadata{
+				APIVersion: "v2",
+				Name:       "inventory",
+				Version:    "1.2.3",
+			},
+		},
+		Manifest: `---
+apiVersion: v1
+kind: Pod
+metadata:
+  annotations:
+    config.k8s.io/owning-inventory: inventory-id
+  name: pod-a
+  namespace: test-inventory-namespace
+`,
+		Version:   1,
+		Namespace: testNamespace,
+	}, release)
+}
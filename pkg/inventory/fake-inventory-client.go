@@ -7,6 +7,7 @@ import (
 	"context"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"sigs.k8s.io/cli-utils/pkg/apis/actuation"
 	"sigs.k8s.io/cli-utils/pkg/common"
@@ -22,6 +23,8 @@ type FakeClient struct {
 
 var (
 	_ Client        = &FakeClient{}
+	_ UIDClient     = &FakeClient{}
+	_ StatusClient  = &FakeClient{}
 	_ ClientFactory = FakeClientFactory{}
 )
 
@@ -47,6 +50,28 @@ func (fic *FakeClient) GetClusterObjs(Info) (object.ObjMetadataSet, error) {
 	return fic.Objs, nil
 }
 
+// GetClusterObjUIDs returns the UIDs of the currently stored status.
+func (fic *FakeClient) GetClusterObjUIDs(Info) (map[object.ObjMetadata]types.UID, error) {
+	if fic.Err != nil {
+		return nil, fic.Err
+	}
+	uids := map[object.ObjMetadata]types.UID{}
+	for _, status := range fic.Status {
+		if status.UID != "" {
+			uids[ObjMetadataFromObjectReference(status.ObjectReference)] = status.UID
+		}
+	}
+	return uids, nil
+}
+
+// GetClusterObjStatus returns the currently stored status.
+func (fic *FakeClient) GetClusterObjStatus(Info) ([]actuation.ObjectStatus, error) {
+	if fic.Err != nil {
+		return nil, fic.Err
+	}
+	return fic.Status, nil
+}
+
 // Merge stores the passed objects with the current stored cluster inventory
 // objects. Returns the set difference of the current set of objects minus
 // the passed set of objects, or an error if one is set up.
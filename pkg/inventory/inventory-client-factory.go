@@ -19,8 +19,17 @@ type ClientFactory interface {
 // ClusterClientFactory is a factory that creates instances of ClusterClient inventory client.
 type ClusterClientFactory struct {
 	StatusPolicy StatusPolicy
+	// ConvertFunc, if not nil, converts the inventory objects read from the
+	// cluster with a version other than the configured version. See
+	// ClusterClient.InventoryConvertFunc.
+	ConvertFunc ConvertFunc
 }
 
 func (ccf ClusterClientFactory) NewClient(factory cmdutil.Factory) (Client, error) {
-	return NewClient(factory, WrapInventoryObj, InvInfoToConfigMap, ccf.StatusPolicy, ConfigMapGVK)
+	cic, err := NewClient(factory, WrapInventoryObj, InvInfoToConfigMap, ccf.StatusPolicy, ConfigMapGVK)
+	if err != nil {
+		return nil, err
+	}
+	cic.InventoryConvertFunc = ccf.ConvertFunc
+	return cic, nil
 }
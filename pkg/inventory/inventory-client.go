@@ -12,6 +12,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/klog/v2"
@@ -42,19 +43,45 @@ type Client interface {
 	ListClusterInventoryObjs(ctx context.Context) (map[string]object.ObjMetadataSet, error)
 }
 
+// UIDClient is implemented by inventory clients that can retrieve the last
+// known UID of the objects stored in the inventory. The UIDs are only
+// recorded when the inventory status is stored (StatusPolicyAll).
+type UIDClient interface {
+	// GetClusterObjUIDs returns the last known UID of each object stored in
+	// the cluster inventory object. Objects without a recorded UID are
+	// omitted.
+	GetClusterObjUIDs(inv Info) (map[object.ObjMetadata]types.UID, error)
+}
+
+// StatusClient is implemented by inventory clients that can retrieve the
+// recorded status of the objects stored in the inventory. The status is only
+// recorded when the inventory status is stored (StatusPolicyAll).
+type StatusClient interface {
+	// GetClusterObjStatus returns the recorded status of the objects stored
+	// in the cluster inventory object. Objects without a recorded status are
+	// omitted.
+	GetClusterObjStatus(inv Info) ([]actuation.ObjectStatus, error)
+}
+
 // ClusterClient is a concrete implementation of the
 // Client interface.
 type ClusterClient struct {
-	dc                    dynamic.Interface
-	discoveryClient       discovery.CachedDiscoveryInterface
-	mapper                meta.RESTMapper
-	InventoryFactoryFunc  StorageFactoryFunc
+	dc                   dynamic.Interface
+	discoveryClient      discovery.CachedDiscoveryInterface
+	mapper               meta.RESTMapper
+	InventoryFactoryFunc StorageFactoryFunc
+	// InventoryConvertFunc, if not nil, converts the inventory objects read
+	// from the cluster with a version other than the configured version.
+	// Otherwise, they are passed to the InventoryFactoryFunc as is.
+	InventoryConvertFunc  ConvertFunc
 	invToUnstructuredFunc ToUnstructuredFunc
 	statusPolicy          StatusPolicy
 	gvk                   schema.GroupVersionKind
 }
 
 var _ Client = &ClusterClient{}
+var _ UIDClient = &ClusterClient{}
+var _ StatusClient = &ClusterClient{}
 
 // NewClient returns a concrete implementation of the
 // Client interface or an error.
@@ -97,6 +124,10 @@ func NewClient(factory cmdutil.Factory,
 // occurred.
 func (cic *ClusterClient) Merge(localInv Info, objs object.ObjMetadataSet, dryRun common.DryRunStrategy) (object.ObjMetadataSet, error) {
 	pruneIDs := object.ObjMetadataSet{}
+	objs, err := object.NormalizeNamespaces(objs, cic.mapper)
+	if err != nil {
+		return pruneIDs, err
+	}
 	invObj := cic.invToUnstructuredFunc(localInv)
 	clusterInv, err := cic.getClusterInventoryInfo(localInv)
 	if err != nil {
@@ -135,6 +166,14 @@ func (cic *ClusterClient) Merge(localInv Info, objs object.ObjMetadataSet, dryRu
 	var status []actuation.ObjectStatus
 	if cic.statusPolicy == StatusPolicyAll {
 		status = getObjStatus(pruneIDs, unionObjs)
+		// Retain the last known UIDs until the objects are actuated again.
+		uids, err := cic.loadUIDs(clusterInv)
+		if err != nil {
+			return pruneIDs, err
+		}
+		for i := range status {
+			status[i].UID = uids[ObjMetadataFromObjectReference(status[i].ObjectReference)]
+		}
 	}
 	klog.V(4).Infof("num objects to prune: %d", len(pruneIDs))
 	klog.V(4).Infof("num merged objects to store in inventory: %d", len(unionObjs))
@@ -167,6 +206,10 @@ func (cic *ClusterClient) Replace(localInv Info, objs object.ObjMetadataSet, sta
 		klog.V(4).Infoln("dry-run replace inventory object: not applied")
 		return nil
 	}
+	objs, err := object.NormalizeNamespaces(objs, cic.mapper)
+	if err != nil {
+		return err
+	}
 	clusterInv, err := cic.getClusterInventoryInfo(localInv)
 	if err != nil {
 		return fmt.Errorf("failed to read inventory from cluster: %w", err)
@@ -246,7 +289,8 @@ func (cic *ClusterClient) deleteInventoryObjsByLabel(inv Info, dryRun common.Dry
 }
 
 // GetClusterObjs returns the objects stored in the cluster inventory object, or
-// an error if one occurred.
+// an error if one occurred. The namespace of cluster-scoped objects is always
+// empty, even if it was stored with a namespace.
 func (cic *ClusterClient) GetClusterObjs(localInv Info) (object.ObjMetadataSet, error) {
 	var objs object.ObjMetadataSet
 	clusterInv, err := cic.getClusterInventoryInfo(localInv)
@@ -258,7 +302,77 @@ func (cic *ClusterClient) GetClusterObjs(localInv Info) (object.ObjMetadataSet,
 		return objs, nil
 	}
 	wrapped := cic.InventoryFactoryFunc(clusterInv)
-	return wrapped.Load()
+	objs, err = wrapped.Load()
+	if err != nil {
+		return objs, err
+	}
+	return object.NormalizeNamespaces(objs, cic.mapper)
+}
+
+// GetClusterObjUIDs returns the last known UID of each object stored in the
+// cluster inventory object, or an error if one occurred.
+func (cic *ClusterClient) GetClusterObjUIDs(localInv Info) (map[object.ObjMetadata]types.UID, error) {
+	clusterInv, err := cic.getClusterInventoryInfo(localInv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read inventory from cluster: %w", err)
+	}
+	// First time; no inventory obj yet.
+	if clusterInv == nil {
+		return map[object.ObjMetadata]types.UID{}, nil
+	}
+	return cic.loadUIDs(clusterInv)
+}
+
+// GetClusterObjStatus returns the recorded status of each object stored in
+// the cluster inventory object, or an error if one occurred.
+func (cic *ClusterClient) GetClusterObjStatus(localInv Info) ([]actuation.ObjectStatus, error) {
+	clusterInv, err := cic.getClusterInventoryInfo(localInv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read inventory from cluster: %w", err)
+	}
+	// First time; no inventory obj yet.
+	if clusterInv == nil {
+		return nil, nil
+	}
+	loader, ok := cic.InventoryFactoryFunc(clusterInv).(StatusLoader)
+	if !ok {
+		return nil, nil
+	}
+	objStatus, err := loader.LoadStatus()
+	if err != nil {
+		return nil, err
+	}
+	for i := range objStatus {
+		id, err := object.NormalizeNamespace(ObjMetadataFromObjectReference(objStatus[i].ObjectReference), cic.mapper)
+		if err != nil {
+			return nil, err
+		}
+		objStatus[i].ObjectReference = ObjectReferenceFromObjMetadata(id)
+	}
+	return objStatus, nil
+}
+
+// loadUIDs returns the UIDs stored in the inventory object, keyed by
+// normalized object ID. Returns an empty map if the storage does not record
+// UIDs.
+func (cic *ClusterClient) loadUIDs(clusterInv *unstructured.Unstructured) (map[object.ObjMetadata]types.UID, error) {
+	uids := map[object.ObjMetadata]types.UID{}
+	loader, ok := cic.InventoryFactoryFunc(clusterInv).(UIDLoader)
+	if !ok {
+		return uids, nil
+	}
+	stored, err := loader.LoadUIDs()
+	if err != nil {
+		return nil, err
+	}
+	for id, uid := range stored {
+		id, err = object.NormalizeNamespace(id, cic.mapper)
+		if err != nil {
+			return nil, err
+		}
+		uids[id] = uid
+	}
+	return uids, nil
 }
 
 // getClusterInventoryObj returns a pointer to the cluster inventory object, or
@@ -363,12 +477,38 @@ func (cic *ClusterClient) getClusterInventoryObjs(inv Info) (object.Unstructured
 	default:
 		panic(fmt.Errorf("unknown inventory strategy: %s", inv.Strategy()))
 	}
-	return clusterInvObjects, err
+	if err != nil {
+		return nil, err
+	}
+	for i, clusterInv := range clusterInvObjects {
+		clusterInvObjects[i], err = cic.convert(clusterInv)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return clusterInvObjects, nil
+}
+
+// convert converts the inventory object to the configured version with the
+// InventoryConvertFunc, if it was read with another version.
+func (cic *ClusterClient) convert(obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	if cic.InventoryConvertFunc == nil || cic.gvk.Version == "" ||
+		obj.GroupVersionKind() == cic.gvk {
+		return obj, nil
+	}
+	klog.V(4).Infof("converting inventory object (namespace: %q, name: %q) from %q to %q",
+		obj.GetNamespace(), obj.GetName(), obj.GroupVersionKind().GroupVersion(), cic.gvk.GroupVersion())
+	converted, err := cic.InventoryConvertFunc(obj, cic.gvk)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert inventory object %s/%s from %s to %s: %w",
+			obj.GetNamespace(), obj.GetName(), obj.GroupVersionKind().GroupVersion(), cic.gvk.GroupVersion(), err)
+	}
+	return converted, nil
 }
 
 func (cic *ClusterClient) ListClusterInventoryObjs(ctx context.Context) (map[string]object.ObjMetadataSet, error) {
 	// Define the mapping
-	mapping, err := cic.mapper.RESTMapping(cic.gvk.GroupKind(), cic.gvk.Version)
+	mapping, err := cic.versionedMapping(cic.gvk)
 	if err != nil {
 		return nil, err
 	}
@@ -387,7 +527,11 @@ func (cic *ClusterClient) ListClusterInventoryObjs(ctx context.Context) (map[str
 	for i, inv := range clusterInvs.Items {
 		invName := inv.GetName()
 		identifiers[invName] = object.ObjMetadataSet{}
-		wrappedInvObjSlice, err := cic.InventoryFactoryFunc(&clusterInvs.Items[i]).Load()
+		clusterInv, err := cic.convert(&clusterInvs.Items[i])
+		if err != nil {
+			return nil, err
+		}
+		wrappedInvObjSlice, err := cic.InventoryFactoryFunc(clusterInv).Load()
 		if err != nil {
 			return nil, err
 		}
@@ -446,7 +590,28 @@ func (cic *ClusterClient) deleteInventoryObjByName(obj *unstructured.Unstructure
 
 // getMapping returns the RESTMapping for the provided resource.
 func (cic *ClusterClient) getMapping(obj *unstructured.Unstructured) (*meta.RESTMapping, error) {
-	return cic.mapper.RESTMapping(obj.GroupVersionKind().GroupKind(), obj.GroupVersionKind().Version)
+	return cic.versionedMapping(obj.GroupVersionKind())
+}
+
+// versionedMapping returns the RESTMapping for the provided GroupVersionKind.
+// If the requested version is not served by the cluster, the mapping for the
+// preferred served version of the same GroupKind is returned instead. This
+// allows reading inventory objects stored with an older (or newer) version of
+// the inventory schema. Inventory objects read this way keep the apiVersion
+// they were served with, until converted by the InventoryConvertFunc.
+func (cic *ClusterClient) versionedMapping(gvk schema.GroupVersionKind) (*meta.RESTMapping, error) {
+	mapping, err := cic.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err == nil || !meta.IsNoMatchError(err) || gvk.Version == "" {
+		return mapping, err
+	}
+	fallback, fallbackErr := cic.mapper.RESTMapping(gvk.GroupKind())
+	if fallbackErr != nil {
+		// Return the original error, which includes the requested version.
+		return nil, err
+	}
+	klog.V(4).Infof("inventory version %q not served: falling back to %q",
+		gvk.GroupVersion(), fallback.GroupVersionKind.GroupVersion())
+	return fallback, nil
 }
 
 // getObjStatus returns the list of object status
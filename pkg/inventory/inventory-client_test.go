@@ -4,14 +4,17 @@
 package inventory
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/cli-runtime/pkg/resource"
 	clienttesting "k8s.io/client-go/testing"
 	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
@@ -553,3 +556,109 @@ func storeObjsInInventory(info Info, objs object.ObjMetadataSet, status []actuat
 	inv, _ := wrapped.GetObject()
 	return inv
 }
+
+func TestInventoryConvertFunc(t *testing.T) {
+	clusterObjs := object.ObjMetadataSet{ignoreErrInfoToObjMeta(pod1Info)}
+	// The inventory was stored by an older version, which recorded the
+	// objects in the "objects" field instead of "data".
+	oldVersionInv := func(clienttesting.Action) (bool, runtime.Object, error) {
+		u := copyInventoryInfo()
+		u.SetAPIVersion("v1beta1")
+		err := unstructured.SetNestedStringMap(u.Object, clusterObjs.ToStringMap(), "objects")
+		list := &unstructured.UnstructuredList{}
+		list.Items = []unstructured.Unstructured{*u}
+		return true, list, err
+	}
+	convert := func(obj *unstructured.Unstructured, gvk schema.GroupVersionKind) (*unstructured.Unstructured, error) {
+		converted := obj.DeepCopy()
+		converted.SetGroupVersionKind(gvk)
+		objs, _, err := unstructured.NestedStringMap(obj.Object, "objects")
+		if err != nil {
+			return nil, err
+		}
+		unstructured.RemoveNestedField(converted.Object, "objects")
+		err = unstructured.SetNestedStringMap(converted.Object, objs, "data")
+		return converted, err
+	}
+
+	testCases := map[string]struct {
+		convertFunc  ConvertFunc
+		expectedObjs object.ObjMetadataSet
+		isError      bool
+	}{
+		"converted": {
+			convertFunc:  convert,
+			expectedObjs: clusterObjs,
+		},
+		"not converted without convert func": {
+			expectedObjs: object.ObjMetadataSet{},
+		},
+		"conversion error": {
+			convertFunc: func(*unstructured.Unstructured, schema.GroupVersionKind) (*unstructured.Unstructured, error) {
+				return nil, errors.New("unsupported version")
+			},
+			isError: true,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			tf := cmdtesting.NewTestFactory().WithNamespace(testNamespace)
+			defer tf.Cleanup()
+			tf.FakeDynamicClient.PrependReactor("list", "configmaps", oldVersionInv)
+
+			invClient, err := ClusterClientFactory{
+				StatusPolicy: StatusPolicyNone,
+				ConvertFunc:  tc.convertFunc,
+			}.NewClient(tf)
+			require.NoError(t, err)
+
+			objs, err := invClient.GetClusterObjs(copyInventory())
+			if tc.isError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, tc.expectedObjs.Equal(objs), "expected %v, got %v", tc.expectedObjs, objs)
+		})
+	}
+}
+
+func TestVersionedMapping(t *testing.T) {
+	servedGVK := schema.GroupVersionKind{Group: "example.com", Version: "v1alpha1", Kind: "Inventory"}
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{servedGVK.GroupVersion()})
+	mapper.Add(servedGVK, meta.RESTScopeNamespace)
+	cic := &ClusterClient{mapper: mapper}
+
+	testCases := map[string]struct {
+		gvk         schema.GroupVersionKind
+		expectedGVK schema.GroupVersionKind
+		isError     bool
+	}{
+		"served version": {
+			gvk:         servedGVK,
+			expectedGVK: servedGVK,
+		},
+		"unserved version falls back to served version": {
+			gvk:         servedGVK.GroupKind().WithVersion("v1"),
+			expectedGVK: servedGVK,
+		},
+		"unknown kind": {
+			gvk:     schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Unknown"},
+			isError: true,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			mapping, err := cic.versionedMapping(tc.gvk)
+			if tc.isError {
+				require.Error(t, err)
+				assert.True(t, meta.IsNoMatchError(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedGVK, mapping.GroupVersionKind)
+		})
+	}
+}
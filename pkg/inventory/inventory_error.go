@@ -9,6 +9,7 @@ import (
 	"fmt"
 
 	"sigs.k8s.io/cli-utils/pkg/apis/actuation"
+	"sigs.k8s.io/cli-utils/pkg/apply/event"
 	"sigs.k8s.io/cli-utils/pkg/object"
 )
 
@@ -87,3 +88,37 @@ func (e *PolicyPreventedActuationError) Is(err error) bool {
 		e.Policy == tErr.Policy &&
 		e.Status == tErr.Status
 }
+
+// SkipReason returns the reason code of the skipped events.
+func (e *PolicyPreventedActuationError) SkipReason() event.SkipReason {
+	return event.SkipReasonInventoryPolicyPreventedActuation
+}
+
+// OwnershipTransferredError is returned when an object of the inventory has
+// been adopted by another inventory, so it must not be deleted.
+type OwnershipTransferredError struct {
+	// Owner is the ID of the inventory that owns the object.
+	Owner string
+}
+
+func (e *OwnershipTransferredError) Error() string {
+	return fmt.Sprintf("object owned by another inventory (%s)", e.Owner)
+}
+
+// Is returns true if the specified error is equal to this error.
+// Use errors.Is(error) to recursively check if an error wraps this error.
+func (e *OwnershipTransferredError) Is(err error) bool {
+	if err == nil {
+		return false
+	}
+	tErr, ok := err.(*OwnershipTransferredError)
+	if !ok {
+		return false
+	}
+	return e.Owner == tErr.Owner
+}
+
+// SkipReason returns the reason code of the skipped events.
+func (e *OwnershipTransferredError) SkipReason() event.SkipReason {
+	return event.SkipReasonOwnershipTransferred
+}
@@ -12,16 +12,19 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/cli-utils/pkg/apis/actuation"
 	"sigs.k8s.io/cli-utils/pkg/common"
+	"sigs.k8s.io/cli-utils/pkg/jsonenum"
 	"sigs.k8s.io/cli-utils/pkg/object"
 )
 
@@ -64,6 +67,8 @@ type ConfigMap struct {
 
 var _ Info = &ConfigMap{}
 var _ Storage = &ConfigMap{}
+var _ UIDLoader = &ConfigMap{}
+var _ StatusLoader = &ConfigMap{}
 
 func (icm *ConfigMap) Name() string {
 	return icm.inv.GetName()
@@ -107,6 +112,66 @@ func (icm *ConfigMap) Load() (object.ObjMetadataSet, error) {
 	return objs, nil
 }
 
+// LoadUIDs is a UIDLoader interface function returning the last known UID of
+// each object stored in the wrapped ConfigMap. Objects without a recorded UID
+// are omitted.
+func (icm *ConfigMap) LoadUIDs() (map[object.ObjMetadata]types.UID, error) {
+	uids := map[object.ObjMetadata]types.UID{}
+	objMap, _, err := unstructured.NestedStringMap(icm.inv.Object, "data")
+	if err != nil {
+		return uids, fmt.Errorf("error retrieving object metadata from inventory object")
+	}
+	for objStr, statusStr := range objMap {
+		if statusStr == "" {
+			continue
+		}
+		id, err := object.ParseObjMetadata(objStr)
+		if err != nil {
+			return uids, err
+		}
+		var status map[string]string
+		if err := json.Unmarshal([]byte(statusStr), &status); err != nil {
+			return uids, fmt.Errorf("error parsing status of object %q in inventory object: %w", id, err)
+		}
+		if uid := status["uid"]; uid != "" {
+			uids[id] = types.UID(uid)
+		}
+	}
+	return uids, nil
+}
+
+// LoadStatus is a StatusLoader interface function returning the recorded
+// status of each object stored in the wrapped ConfigMap. Objects without a
+// recorded status are omitted.
+func (icm *ConfigMap) LoadStatus() ([]actuation.ObjectStatus, error) {
+	var objStatus []actuation.ObjectStatus
+	objMap, _, err := unstructured.NestedStringMap(icm.inv.Object, "data")
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving object metadata from inventory object")
+	}
+	for objStr, statusStr := range objMap {
+		if statusStr == "" {
+			continue
+		}
+		id, err := object.ParseObjMetadata(objStr)
+		if err != nil {
+			return nil, err
+		}
+		status, err := statusFrom(statusStr)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing status of object %q in inventory object: %w", id, err)
+		}
+		status.ObjectReference = ObjectReferenceFromObjMetadata(id)
+		objStatus = append(objStatus, status)
+	}
+	// Sort for deterministic order
+	sort.Slice(objStatus, func(i, j int) bool {
+		return ObjMetadataFromObjectReference(objStatus[i].ObjectReference).String() <
+			ObjMetadataFromObjectReference(objStatus[j].ObjectReference).String()
+	})
+	return objStatus, nil
+}
+
 // Store is an Inventory interface function implemented to store
 // the object metadata in the wrapped ConfigMap. Actual storing
 // happens in "GetObject".
@@ -218,9 +283,34 @@ func stringFrom(status actuation.ObjectStatus) string {
 		"actuation": status.Actuation.String(),
 		"reconcile": status.Reconcile.String(),
 	}
+	if status.UID != "" {
+		tmp["uid"] = string(status.UID)
+	}
 	data, err := json.Marshal(tmp)
 	if err != nil || string(data) == "{}" {
 		return ""
 	}
 	return string(data)
 }
+
+// statusFrom parses the status stored by stringFrom, without the object
+// reference.
+func statusFrom(data string) (actuation.ObjectStatus, error) {
+	var status actuation.ObjectStatus
+	var tmp map[string]string
+	if err := json.Unmarshal([]byte(data), &tmp); err != nil {
+		return status, err
+	}
+	var err error
+	if status.Strategy, err = jsonenum.Parse[actuation.ActuationStrategy](tmp["strategy"]); err != nil {
+		return status, err
+	}
+	if status.Actuation, err = jsonenum.Parse[actuation.ActuationStatus](tmp["actuation"]); err != nil {
+		return status, err
+	}
+	if status.Reconcile, err = jsonenum.Parse[actuation.ReconcileStatus](tmp["reconcile"]); err != nil {
+		return status, err
+	}
+	status.UID = types.UID(tmp["uid"])
+	return status, nil
+}
@@ -7,6 +7,10 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/cli-utils/pkg/apis/actuation"
 	"sigs.k8s.io/cli-utils/pkg/object"
 )
@@ -52,6 +56,21 @@ func TestBuildObjMap(t *testing.T) {
 				"ns_na_group2_Kind": `{"actuation":"Skipped","reconcile":"Succeeded","strategy":"Delete"}`,
 			},
 		},
+		"object status with uid": {
+			objSet: object.ObjMetadataSet{ObjMetadataFromObjectReference(obj1)},
+			objStatus: []actuation.ObjectStatus{
+				{
+					ObjectReference: obj1,
+					Strategy:        actuation.ActuationStrategyApply,
+					Actuation:       actuation.ActuationSucceeded,
+					Reconcile:       actuation.ReconcileSucceeded,
+					UID:             "uid1",
+				},
+			},
+			expected: map[string]string{
+				"ns_na_group1_Kind": `{"actuation":"Succeeded","reconcile":"Succeeded","strategy":"Apply","uid":"uid1"}`,
+			},
+		},
 		"empty object status list": {
 			objSet:   object.ObjMetadataSet{ObjMetadataFromObjectReference(obj1), ObjMetadataFromObjectReference(obj2)},
 			hasError: false,
@@ -70,3 +89,57 @@ func TestBuildObjMap(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadUIDs(t *testing.T) {
+	inv := inventoryObj.DeepCopy()
+	err := unstructured.SetNestedStringMap(inv.Object, map[string]string{
+		"ns_na_group1_Kind": `{"actuation":"Succeeded","reconcile":"Succeeded","strategy":"Apply","uid":"uid1"}`,
+		"ns_na_group2_Kind": `{"actuation":"Succeeded","reconcile":"Succeeded","strategy":"Apply"}`,
+		"ns_na_group3_Kind": "",
+	}, "data")
+	require.NoError(t, err)
+
+	uids, err := WrapInventoryObj(inv).(UIDLoader).LoadUIDs()
+	require.NoError(t, err)
+	expected := map[object.ObjMetadata]types.UID{
+		{
+			GroupKind: schema.GroupKind{Group: "group1", Kind: "Kind"},
+			Namespace: "ns",
+			Name:      "na",
+		}: "uid1",
+	}
+	if diff := cmp.Diff(expected, uids); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestLoadStatus(t *testing.T) {
+	inv := inventoryObj.DeepCopy()
+	err := unstructured.SetNestedStringMap(inv.Object, map[string]string{
+		"ns_na_group1_Kind": `{"actuation":"Succeeded","reconcile":"Succeeded","strategy":"Apply","uid":"uid1"}`,
+		"ns_na_group2_Kind": `{"actuation":"Pending","reconcile":"Pending","strategy":"Delete"}`,
+		"ns_na_group3_Kind": "",
+	}, "data")
+	require.NoError(t, err)
+
+	objStatus, err := WrapInventoryObj(inv).(StatusLoader).LoadStatus()
+	require.NoError(t, err)
+	expected := []actuation.ObjectStatus{
+		{
+			ObjectReference: actuation.ObjectReference{Group: "group1", Kind: "Kind", Namespace: "ns", Name: "na"},
+			Strategy:        actuation.ActuationStrategyApply,
+			Actuation:       actuation.ActuationSucceeded,
+			Reconcile:       actuation.ReconcileSucceeded,
+			UID:             "uid1",
+		},
+		{
+			ObjectReference: actuation.ObjectReference{Group: "group2", Kind: "Kind", Namespace: "ns", Name: "na"},
+			Strategy:        actuation.ActuationStrategyDelete,
+			Actuation:       actuation.ActuationPending,
+			Reconcile:       actuation.ReconcilePending,
+		},
+	}
+	if diff := cmp.Diff(expected, objStatus); diff != "" {
+		t.Error(diff)
+	}
+}
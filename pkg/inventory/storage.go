@@ -17,6 +17,8 @@ import (
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/cli-utils/pkg/apis/actuation"
@@ -48,10 +50,34 @@ type Storage interface {
 	ApplyWithPrune(dynamic.Interface, meta.RESTMapper, StatusPolicy, object.ObjMetadataSet) error
 }
 
+// UIDLoader is implemented by Storage backends that record the last known UID
+// of the objects in the inventory, to detect objects that have been deleted
+// and re-created by another client.
+type UIDLoader interface {
+	// LoadUIDs retrieves the last known UID of each object in the inventory
+	// object. Objects without a recorded UID are omitted.
+	LoadUIDs() (map[object.ObjMetadata]types.UID, error)
+}
+
+// StatusLoader is implemented by Storage backends that record the actuation
+// and reconcile status of the objects in the inventory.
+type StatusLoader interface {
+	// LoadStatus retrieves the recorded status of the objects in the
+	// inventory object. Objects without a recorded status are omitted.
+	LoadStatus() ([]actuation.ObjectStatus, error)
+}
+
 // StorageFactoryFunc creates the object which implements the Inventory
 // interface from the passed info object.
 type StorageFactoryFunc func(*unstructured.Unstructured) Storage
 
+// ConvertFunc converts an inventory object read from the cluster with
+// another version of the inventory schema to the passed GroupVersionKind,
+// before it is wrapped by the StorageFactoryFunc. This allows custom backends
+// to read inventory objects stored by older (or newer) versions of the
+// library, when the schema changed between versions.
+type ConvertFunc func(obj *unstructured.Unstructured, gvk schema.GroupVersionKind) (*unstructured.Unstructured, error)
+
 // ToUnstructuredFunc returns the unstructured object for the
 // given Info.
 type ToUnstructuredFunc func(Info) *unstructured.Unstructured
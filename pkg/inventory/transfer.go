@@ -0,0 +1,229 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package inventory
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/cli-utils/pkg/apis/actuation"
+	"sigs.k8s.io/cli-utils/pkg/common"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+// TransferOwnershipError is returned by MergeInventories and SplitInventory
+// when an object that would be moved is owned by an inventory other than the
+// source inventory. No changes are made when this error is returned.
+type TransferOwnershipError struct {
+	// Identifier of the object.
+	Identifier object.ObjMetadata
+	// OwningInventory is the value of the owning-inventory annotation on the
+	// live object.
+	OwningInventory string
+}
+
+func (e *TransferOwnershipError) Error() string {
+	return fmt.Sprintf("object %q is owned by another inventory (%q)", e.Identifier, e.OwningInventory)
+}
+
+// MergeInventories moves all the objects stored in the src inventory into the
+// dst inventory, then deletes the src inventory object. The dst inventory
+// object is created if it does not exist.
+//
+// Updates are ordered so that every object is tracked by at least one
+// inventory at all times: the dst inventory is updated first, then the
+// owning-inventory annotation of each live object, and the src inventory is
+// only deleted last. Returns a TransferOwnershipError, before making any
+// changes, if any live object is owned by a different inventory.
+func MergeInventories(ctx context.Context, invClient Client, dc dynamic.Interface, mapper meta.RESTMapper,
+	src, dst Info, dryRun common.DryRunStrategy) error {
+	ids, err := invClient.GetClusterObjs(src)
+	if err != nil {
+		return err
+	}
+	live, err := getLiveObjects(ctx, dc, mapper, ids)
+	if err != nil {
+		return err
+	}
+	if err := checkOwnership(src, live); err != nil {
+		return err
+	}
+	srcStatus, err := clusterObjStatus(invClient, src)
+	if err != nil {
+		return err
+	}
+	if err := transfer(ctx, invClient, dc, mapper, dst, ids, srcStatus, live, dryRun); err != nil {
+		return err
+	}
+	klog.V(4).Infof("deleting merged inventory object: %s/%s", src.Namespace(), src.Name())
+	return invClient.DeleteInventoryObj(src, dryRun)
+}
+
+// SplitInventory moves the objects stored in the src inventory whose live
+// labels match the selector into the dst inventory. Objects that no longer
+// exist in the cluster remain in the src inventory. The dst inventory object
+// is created if it does not exist.
+//
+// Like MergeInventories, the dst inventory is updated before the src
+// inventory, so that every object is tracked by at least one inventory at all
+// times, and a TransferOwnershipError is returned, before making any changes,
+// if any selected object is owned by a different inventory.
+func SplitInventory(ctx context.Context, invClient Client, dc dynamic.Interface, mapper meta.RESTMapper,
+	src, dst Info, selector labels.Selector, dryRun common.DryRunStrategy) error {
+	ids, err := invClient.GetClusterObjs(src)
+	if err != nil {
+		return err
+	}
+	live, err := getLiveObjects(ctx, dc, mapper, ids)
+	if err != nil {
+		return err
+	}
+	var moveIDs object.ObjMetadataSet
+	var moveObjs object.UnstructuredSet
+	for _, obj := range live {
+		if selector.Matches(labels.Set(obj.GetLabels())) {
+			moveIDs = append(moveIDs, object.UnstructuredToObjMetadata(obj))
+			moveObjs = append(moveObjs, obj)
+		}
+	}
+	if len(moveIDs) == 0 {
+		klog.V(4).Infof("split inventory: no objects match selector %q", selector)
+		return nil
+	}
+	if err := checkOwnership(src, moveObjs); err != nil {
+		return err
+	}
+	srcStatus, err := clusterObjStatus(invClient, src)
+	if err != nil {
+		return err
+	}
+	if err := transfer(ctx, invClient, dc, mapper, dst, moveIDs, srcStatus, moveObjs, dryRun); err != nil {
+		return err
+	}
+	remainingIDs := ids.Diff(moveIDs)
+	return invClient.Replace(src, remainingIDs, statusOf(srcStatus, remainingIDs), dryRun)
+}
+
+// transfer stores the ids in the dst inventory, with the recorded status of
+// the src inventory, then updates the owning-inventory annotation of the live
+// objects to match dst.
+func transfer(ctx context.Context, invClient Client, dc dynamic.Interface, mapper meta.RESTMapper,
+	dst Info, ids object.ObjMetadataSet, srcStatus map[object.ObjMetadata]actuation.ObjectStatus,
+	live object.UnstructuredSet, dryRun common.DryRunStrategy) error {
+	// Merge resets the status of the objects, so the status of the objects
+	// already in dst is read first.
+	dstStatus, err := clusterObjStatus(invClient, dst)
+	if err != nil {
+		return err
+	}
+	if _, err := invClient.Merge(dst, ids, dryRun); err != nil {
+		return fmt.Errorf("failed to update inventory %s/%s: %w", dst.Namespace(), dst.Name(), err)
+	}
+	if len(srcStatus) > 0 || len(dstStatus) > 0 {
+		dstIDs, err := invClient.GetClusterObjs(dst)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			if status, found := srcStatus[id]; found {
+				dstStatus[id] = status
+			}
+		}
+		if err := invClient.Replace(dst, dstIDs, statusOf(dstStatus, dstIDs), dryRun); err != nil {
+			return fmt.Errorf("failed to update inventory %s/%s: %w", dst.Namespace(), dst.Name(), err)
+		}
+	}
+	if dryRun.ClientOrServerDryRun() {
+		return nil
+	}
+	for _, obj := range live {
+		id := object.UnstructuredToObjMetadata(obj)
+		mapping, err := mapper.RESTMapping(id.GroupKind)
+		if err != nil {
+			return err
+		}
+		AddInventoryIDAnnotation(obj, dst)
+		klog.V(4).Infof("updating owning inventory (object: %q, inventory: %q)", id, dst.ID())
+		if _, err := resourceClient(dc, mapping, id.Namespace).Update(ctx, obj, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to update owning inventory of %q: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// clusterObjStatus returns the recorded status of the objects stored in the
+// inventory, keyed by object, if the inventory client records it.
+func clusterObjStatus(invClient Client, inv Info) (map[object.ObjMetadata]actuation.ObjectStatus, error) {
+	objStatus := make(map[object.ObjMetadata]actuation.ObjectStatus)
+	statusClient, ok := invClient.(StatusClient)
+	if !ok {
+		return objStatus, nil
+	}
+	stored, err := statusClient.GetClusterObjStatus(inv)
+	if err != nil {
+		return nil, err
+	}
+	for _, status := range stored {
+		objStatus[ObjMetadataFromObjectReference(status.ObjectReference)] = status
+	}
+	return objStatus, nil
+}
+
+// statusOf returns the recorded status of the ids, in order. Objects without
+// a recorded status are omitted.
+func statusOf(objStatus map[object.ObjMetadata]actuation.ObjectStatus, ids object.ObjMetadataSet) []actuation.ObjectStatus {
+	var status []actuation.ObjectStatus
+	for _, id := range ids {
+		if s, found := objStatus[id]; found {
+			status = append(status, s)
+		}
+	}
+	return status
+}
+
+// getLiveObjects returns the live objects for the ids, skipping objects that
+// do not exist or whose type is not registered.
+func getLiveObjects(ctx context.Context, dc dynamic.Interface, mapper meta.RESTMapper,
+	ids object.ObjMetadataSet) (object.UnstructuredSet, error) {
+	var objs object.UnstructuredSet
+	for _, id := range ids {
+		mapping, err := mapper.RESTMapping(id.GroupKind)
+		if err != nil {
+			if meta.IsNoMatchError(err) {
+				continue
+			}
+			return nil, err
+		}
+		obj, err := resourceClient(dc, mapping, id.Namespace).Get(ctx, id.Name, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get object %q: %w", id, err)
+		}
+		objs = append(objs, obj)
+	}
+	return objs, nil
+}
+
+// checkOwnership returns a TransferOwnershipError if any of the objects is
+// owned by an inventory other than inv. Objects without an owning-inventory
+// annotation are allowed.
+func checkOwnership(inv Info, objs object.UnstructuredSet) error {
+	for _, obj := range objs {
+		if IDMatch(inv, obj) == NoMatch {
+			return &TransferOwnershipError{
+				Identifier:      object.UnstructuredToObjMetadata(obj),
+				OwningInventory: obj.GetAnnotations()[OwningInventoryKey],
+			}
+		}
+	}
+	return nil
+}
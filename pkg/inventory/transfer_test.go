@@ -0,0 +1,296 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package inventory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta/testrestmapper"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic/fake"
+	"k8s.io/kubectl/pkg/scheme"
+	"sigs.k8s.io/cli-utils/pkg/apis/actuation"
+	"sigs.k8s.io/cli-utils/pkg/common"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+// multiFakeClient is a Client that stores the objects and status of
+// multiple inventories, keyed by inventory name.
+type multiFakeClient struct {
+	invs    map[string]object.ObjMetadataSet
+	status  map[string][]actuation.ObjectStatus
+	deleted []string
+}
+
+var _ Client = &multiFakeClient{}
+var _ StatusClient = &multiFakeClient{}
+
+func (c *multiFakeClient) GetClusterObjs(inv Info) (object.ObjMetadataSet, error) {
+	return c.invs[inv.Name()], nil
+}
+
+func (c *multiFakeClient) Merge(inv Info, objs object.ObjMetadataSet, _ common.DryRunStrategy) (object.ObjMetadataSet, error) {
+	prune := c.invs[inv.Name()].Diff(objs)
+	c.invs[inv.Name()] = c.invs[inv.Name()].Union(objs)
+	// Like the ClusterClient, Merge resets the status of the objects.
+	delete(c.status, inv.Name())
+	return prune, nil
+}
+
+func (c *multiFakeClient) Replace(inv Info, objs object.ObjMetadataSet, status []actuation.ObjectStatus, _ common.DryRunStrategy) error {
+	c.invs[inv.Name()] = objs
+	if c.status == nil {
+		c.status = map[string][]actuation.ObjectStatus{}
+	}
+	c.status[inv.Name()] = status
+	return nil
+}
+
+func (c *multiFakeClient) GetClusterObjStatus(inv Info) ([]actuation.ObjectStatus, error) {
+	return c.status[inv.Name()], nil
+}
+
+func (c *multiFakeClient) DeleteInventoryObj(inv Info, _ common.DryRunStrategy) error {
+	delete(c.invs, inv.Name())
+	c.deleted = append(c.deleted, inv.Name())
+	return nil
+}
+
+func (c *multiFakeClient) ListClusterInventoryObjs(context.Context) (map[string]object.ObjMetadataSet, error) {
+	return c.invs, nil
+}
+
+func newTestInventory(name, id string) Info {
+	return WrapInventoryInfoObj(&unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": testNamespace,
+				"labels": map[string]interface{}{
+					common.InventoryLabel: id,
+				},
+			},
+		},
+	})
+}
+
+func labeledPod(name, owner, app string) *unstructured.Unstructured {
+	pod := ownedPod(name, owner)
+	pod.SetLabels(map[string]string{"app": app})
+	return pod
+}
+
+func getOwner(t *testing.T, dc *fake.FakeDynamicClient, id object.ObjMetadata) string {
+	obj, err := dc.Resource(schema.GroupVersionResource{Version: "v1", Resource: "pods"}).
+		Namespace(id.Namespace).Get(context.Background(), id.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	return obj.GetAnnotations()[OwningInventoryKey]
+}
+
+func TestMergeInventories(t *testing.T) {
+	src := newTestInventory("src", "src-id")
+	dst := newTestInventory("dst", "dst-id")
+	podA := labeledPod("pod-a", "src-id", "a")
+	podB := labeledPod("pod-b", "dst-id", "b")
+	podC := labeledPod("pod-c", "other-id", "c")
+	idA := object.UnstructuredToObjMetadata(podA)
+	idB := object.UnstructuredToObjMetadata(podB)
+	idC := object.UnstructuredToObjMetadata(podC)
+
+	testCases := map[string]struct {
+		srcObjs       object.ObjMetadataSet
+		dryRun        common.DryRunStrategy
+		expectedDst   object.ObjMetadataSet
+		expectedOwner string
+		expectedError error
+	}{
+		"merge moves objects and deletes src": {
+			srcObjs:       object.ObjMetadataSet{idA},
+			dryRun:        common.DryRunNone,
+			expectedDst:   object.ObjMetadataSet{idB, idA},
+			expectedOwner: "dst-id",
+		},
+		"dry-run does not update owner": {
+			srcObjs:       object.ObjMetadataSet{idA},
+			dryRun:        common.DryRunClient,
+			expectedDst:   object.ObjMetadataSet{idB, idA},
+			expectedOwner: "src-id",
+		},
+		"object owned by another inventory": {
+			srcObjs:       object.ObjMetadataSet{idA, idC},
+			dryRun:        common.DryRunNone,
+			expectedDst:   object.ObjMetadataSet{idB},
+			expectedOwner: "src-id",
+			expectedError: &TransferOwnershipError{Identifier: idC, OwningInventory: "other-id"},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			dc := fake.NewSimpleDynamicClient(scheme.Scheme, []runtime.Object{
+				podA.DeepCopy(), podB.DeepCopy(), podC.DeepCopy(),
+			}...)
+			mapper := testrestmapper.TestOnlyStaticRESTMapper(scheme.Scheme,
+				scheme.Scheme.PrioritizedVersionsAllGroups()...)
+			invClient := &multiFakeClient{invs: map[string]object.ObjMetadataSet{
+				"src": tc.srcObjs,
+				"dst": {idB},
+			}}
+
+			err := MergeInventories(context.Background(), invClient, dc, mapper, src, dst, tc.dryRun)
+			if tc.expectedError != nil {
+				assert.Equal(t, tc.expectedError, err)
+				var ownershipErr *TransferOwnershipError
+				assert.True(t, errors.As(err, &ownershipErr))
+				assert.Empty(t, invClient.deleted)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, []string{"src"}, invClient.deleted)
+			}
+			assert.Equal(t, tc.expectedDst, invClient.invs["dst"])
+			assert.Equal(t, tc.expectedOwner, getOwner(t, dc, idA))
+		})
+	}
+}
+
+func TestSplitInventory(t *testing.T) {
+	src := newTestInventory("src", "src-id")
+	dst := newTestInventory("dst", "dst-id")
+	podA := labeledPod("pod-a", "src-id", "a")
+	podB := labeledPod("pod-b", "src-id", "b")
+	podC := labeledPod("pod-c", "other-id", "c")
+	idA := object.UnstructuredToObjMetadata(podA)
+	idB := object.UnstructuredToObjMetadata(podB)
+	idC := object.UnstructuredToObjMetadata(podC)
+	idMissing := object.UnstructuredToObjMetadata(labeledPod("pod-missing", "src-id", "b"))
+
+	testCases := map[string]struct {
+		selector      string
+		expectedSrc   object.ObjMetadataSet
+		expectedDst   object.ObjMetadataSet
+		expectedError error
+	}{
+		"split by label": {
+			selector:    "app=b",
+			expectedSrc: object.ObjMetadataSet{idA, idMissing},
+			expectedDst: object.ObjMetadataSet{idB},
+		},
+		"no matching objects": {
+			selector:    "app=d",
+			expectedSrc: object.ObjMetadataSet{idA, idB, idMissing},
+		},
+		"selected object owned by another inventory": {
+			selector:      "app in (b, c)",
+			expectedSrc:   object.ObjMetadataSet{idA, idB, idMissing},
+			expectedError: &TransferOwnershipError{Identifier: idC, OwningInventory: "other-id"},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			dc := fake.NewSimpleDynamicClient(scheme.Scheme, []runtime.Object{
+				podA.DeepCopy(), podB.DeepCopy(), podC.DeepCopy(),
+			}...)
+			mapper := testrestmapper.TestOnlyStaticRESTMapper(scheme.Scheme,
+				scheme.Scheme.PrioritizedVersionsAllGroups()...)
+			srcObjs := object.ObjMetadataSet{idA, idB, idMissing}
+			if tc.expectedError != nil {
+				srcObjs = append(srcObjs, idC)
+				tc.expectedSrc = append(tc.expectedSrc, idC)
+			}
+			invClient := &multiFakeClient{invs: map[string]object.ObjMetadataSet{
+				"src": srcObjs,
+			}}
+			selector, err := labels.Parse(tc.selector)
+			require.NoError(t, err)
+
+			err = SplitInventory(context.Background(), invClient, dc, mapper, src, dst, selector, common.DryRunNone)
+			if tc.expectedError != nil {
+				assert.Equal(t, tc.expectedError, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedSrc, invClient.invs["src"])
+			assert.Equal(t, tc.expectedDst, invClient.invs["dst"])
+			for _, id := range tc.expectedDst {
+				assert.Equal(t, "dst-id", getOwner(t, dc, id))
+			}
+		})
+	}
+}
+
+func succeededStatus(id object.ObjMetadata, uid string) actuation.ObjectStatus {
+	return actuation.ObjectStatus{
+		ObjectReference: ObjectReferenceFromObjMetadata(id),
+		Strategy:        actuation.ActuationStrategyApply,
+		Actuation:       actuation.ActuationSucceeded,
+		Reconcile:       actuation.ReconcileSucceeded,
+		UID:             types.UID(uid),
+	}
+}
+
+func TestTransferKeepsStatus(t *testing.T) {
+	src := newTestInventory("src", "src-id")
+	dst := newTestInventory("dst", "dst-id")
+	podA := labeledPod("pod-a", "src-id", "a")
+	podB := labeledPod("pod-b", "src-id", "b")
+	podC := labeledPod("pod-c", "dst-id", "c")
+	idA := object.UnstructuredToObjMetadata(podA)
+	idB := object.UnstructuredToObjMetadata(podB)
+	idC := object.UnstructuredToObjMetadata(podC)
+
+	newClient := func() *multiFakeClient {
+		return &multiFakeClient{
+			invs: map[string]object.ObjMetadataSet{
+				"src": {idA, idB},
+				"dst": {idC},
+			},
+			status: map[string][]actuation.ObjectStatus{
+				"src": {succeededStatus(idA, "uid-a"), succeededStatus(idB, "uid-b")},
+				"dst": {succeededStatus(idC, "uid-c")},
+			},
+		}
+	}
+	mapper := testrestmapper.TestOnlyStaticRESTMapper(scheme.Scheme,
+		scheme.Scheme.PrioritizedVersionsAllGroups()...)
+
+	t.Run("split", func(t *testing.T) {
+		dc := fake.NewSimpleDynamicClient(scheme.Scheme, podA.DeepCopy(), podB.DeepCopy(), podC.DeepCopy())
+		invClient := newClient()
+		selector, err := labels.Parse("app=b")
+		require.NoError(t, err)
+
+		err = SplitInventory(context.Background(), invClient, dc, mapper, src, dst, selector, common.DryRunNone)
+		require.NoError(t, err)
+		assert.Equal(t, []actuation.ObjectStatus{succeededStatus(idA, "uid-a")}, invClient.status["src"])
+		assert.Equal(t, []actuation.ObjectStatus{
+			succeededStatus(idC, "uid-c"),
+			succeededStatus(idB, "uid-b"),
+		}, invClient.status["dst"])
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		dc := fake.NewSimpleDynamicClient(scheme.Scheme, podA.DeepCopy(), podB.DeepCopy(), podC.DeepCopy())
+		invClient := newClient()
+
+		err := MergeInventories(context.Background(), invClient, dc, mapper, src, dst, common.DryRunNone)
+		require.NoError(t, err)
+		assert.Equal(t, []actuation.ObjectStatus{
+			succeededStatus(idC, "uid-c"),
+			succeededStatus(idA, "uid-a"),
+			succeededStatus(idB, "uid-b"),
+		}, invClient.status["dst"])
+	})
+}
@@ -0,0 +1,176 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package inventory
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+// VerifyReport is the result of cross-checking the objects stored in an
+// inventory against the live cluster state.
+type VerifyReport struct {
+	// Verified objects exist in the cluster and are owned by the inventory.
+	Verified object.ObjMetadataSet
+	// Missing objects are stored in the inventory, but do not exist in the
+	// cluster, or their type is no longer registered.
+	Missing object.ObjMetadataSet
+	// Mismatched objects are stored in the inventory and exist in the cluster,
+	// but are not owned by the inventory, or their UID differs from the UID
+	// recorded in the inventory. This usually means the object was deleted
+	// and re-created or adopted by another inventory.
+	Mismatched []VerifyMismatch
+	// Orphaned objects are owned by the inventory, but are not stored in it.
+	// Only objects of the types and namespaces stored in the inventory are
+	// checked.
+	Orphaned object.ObjMetadataSet
+}
+
+// VerifyMismatch describes an object whose ownership or UID does not match
+// the inventory that stores it.
+type VerifyMismatch struct {
+	// Identifier of the object.
+	Identifier object.ObjMetadata
+	// OwningInventory is the value of the owning-inventory annotation on the
+	// live object. Empty if the annotation is not set.
+	OwningInventory string
+	// RecordedUID is the UID of the object recorded in the inventory. Empty
+	// if the inventory does not record UIDs.
+	RecordedUID types.UID
+	// UID is the UID of the live object.
+	UID types.UID
+}
+
+// Healthy returns true if every object stored in the inventory was verified
+// and no orphaned objects were found.
+func (r *VerifyReport) Healthy() bool {
+	return len(r.Missing) == 0 && len(r.Mismatched) == 0 && len(r.Orphaned) == 0
+}
+
+// Verify cross-checks every object stored in the cluster inventory against
+// the live cluster state and reports missing, mismatched, and orphaned
+// objects. Verify does not modify the cluster, which makes it safe to use
+// before enabling prune on an existing environment.
+func Verify(ctx context.Context, invClient Client, dc dynamic.Interface, mapper meta.RESTMapper, inv Info) (*VerifyReport, error) {
+	ids, err := invClient.GetClusterObjs(inv)
+	if err != nil {
+		return nil, err
+	}
+	uids, err := recordedUIDs(invClient, inv)
+	if err != nil {
+		return nil, err
+	}
+	report := &VerifyReport{}
+	for _, id := range ids {
+		mapping, err := mapper.RESTMapping(id.GroupKind)
+		if err != nil {
+			if meta.IsNoMatchError(err) {
+				klog.V(4).Infof("verify inventory (object: %q): resource type not registered", id)
+				report.Missing = append(report.Missing, id)
+				continue
+			}
+			return nil, err
+		}
+		obj, err := resourceClient(dc, mapping, id.Namespace).Get(ctx, id.Name, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				report.Missing = append(report.Missing, id)
+				continue
+			}
+			return nil, fmt.Errorf("failed to get object %q: %w", id, err)
+		}
+		recordedUID := uids[id]
+		if IDMatch(inv, obj) != Match || (recordedUID != "" && recordedUID != obj.GetUID()) {
+			report.Mismatched = append(report.Mismatched, VerifyMismatch{
+				Identifier:      id,
+				OwningInventory: obj.GetAnnotations()[OwningInventoryKey],
+				RecordedUID:     recordedUID,
+				UID:             obj.GetUID(),
+			})
+			continue
+		}
+		report.Verified = append(report.Verified, id)
+	}
+
+	orphaned, err := findOrphans(ctx, dc, mapper, inv, ids)
+	if err != nil {
+		return nil, err
+	}
+	report.Orphaned = orphaned
+	return report, nil
+}
+
+// recordedUIDs returns the UIDs recorded in the inventory, if the inventory
+// client records them.
+func recordedUIDs(invClient Client, inv Info) (map[object.ObjMetadata]types.UID, error) {
+	uidClient, ok := invClient.(UIDClient)
+	if !ok {
+		return nil, nil
+	}
+	return uidClient.GetClusterObjUIDs(inv)
+}
+
+// findOrphans lists the objects of each type and namespace stored in the
+// inventory and returns the ones owned by the inventory but not stored in it.
+func findOrphans(ctx context.Context, dc dynamic.Interface, mapper meta.RESTMapper, inv Info, ids object.ObjMetadataSet) (object.ObjMetadataSet, error) {
+	type scope struct {
+		gk        schema.GroupKind
+		namespace string
+	}
+	var scopes []scope
+	seen := make(map[scope]struct{})
+	for _, id := range ids {
+		s := scope{gk: id.GroupKind, namespace: id.Namespace}
+		if _, found := seen[s]; found {
+			continue
+		}
+		seen[s] = struct{}{}
+		scopes = append(scopes, s)
+	}
+
+	stored := ids.ToMap()
+	var orphaned object.ObjMetadataSet
+	for _, s := range scopes {
+		mapping, err := mapper.RESTMapping(s.gk)
+		if err != nil {
+			if meta.IsNoMatchError(err) {
+				continue
+			}
+			return nil, err
+		}
+		list, err := resourceClient(dc, mapping, s.namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", mapping.Resource, err)
+		}
+		for i := range list.Items {
+			obj := &list.Items[i]
+			if IDMatch(inv, obj) != Match {
+				continue
+			}
+			id := object.UnstructuredToObjMetadata(obj)
+			if _, found := stored[id]; !found {
+				orphaned = append(orphaned, id)
+			}
+		}
+	}
+	return orphaned, nil
+}
+
+// resourceClient returns a client for the mapping, scoped to the namespace if
+// the resource is namespaced.
+func resourceClient(dc dynamic.Interface, mapping *meta.RESTMapping, namespace string) dynamic.ResourceInterface {
+	if mapping.Scope.Name() == meta.RESTScopeNameRoot {
+		return dc.Resource(mapping.Resource)
+	}
+	return dc.Resource(mapping.Resource).Namespace(namespace)
+}
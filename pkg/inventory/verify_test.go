@@ -0,0 +1,135 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package inventory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta/testrestmapper"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic/fake"
+	"k8s.io/kubectl/pkg/scheme"
+	"sigs.k8s.io/cli-utils/pkg/apis/actuation"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+func ownedPod(name, owner string) *unstructured.Unstructured {
+	pod := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Pod",
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": testNamespace,
+			},
+		},
+	}
+	if owner != "" {
+		pod.SetAnnotations(map[string]string{OwningInventoryKey: owner})
+	}
+	return pod
+}
+
+func TestVerify(t *testing.T) {
+	podA := ownedPod("pod-a", testInventoryLabel)
+	podB := ownedPod("pod-b", "other-inventory")
+	podC := ownedPod("pod-c", "")
+	podD := ownedPod("pod-d", testInventoryLabel)
+	idA := object.UnstructuredToObjMetadata(podA)
+	idB := object.UnstructuredToObjMetadata(podB)
+	idC := object.UnstructuredToObjMetadata(podC)
+	idD := object.UnstructuredToObjMetadata(podD)
+	idMissing := object.UnstructuredToObjMetadata(ownedPod("pod-missing", testInventoryLabel))
+	podA.SetUID("uid-a")
+	recreatedPodD := podD.DeepCopy()
+	recreatedPodD.SetUID("uid-d2")
+
+	testCases := map[string]struct {
+		invObjs     object.ObjMetadataSet
+		invStatus   []actuation.ObjectStatus
+		clusterObjs []runtime.Object
+		expected    *VerifyReport
+		healthy     bool
+	}{
+		"empty inventory": {
+			invObjs:  object.ObjMetadataSet{},
+			expected: &VerifyReport{},
+			healthy:  true,
+		},
+		"all objects verified": {
+			invObjs:     object.ObjMetadataSet{idA, idD},
+			clusterObjs: []runtime.Object{podA, podD},
+			expected: &VerifyReport{
+				Verified: object.ObjMetadataSet{idA, idD},
+			},
+			healthy: true,
+		},
+		"missing object": {
+			invObjs:     object.ObjMetadataSet{idA, idMissing},
+			clusterObjs: []runtime.Object{podA},
+			expected: &VerifyReport{
+				Verified: object.ObjMetadataSet{idA},
+				Missing:  object.ObjMetadataSet{idMissing},
+			},
+		},
+		"mismatched owners": {
+			invObjs:     object.ObjMetadataSet{idA, idB, idC},
+			clusterObjs: []runtime.Object{podA, podB, podC},
+			expected: &VerifyReport{
+				Verified: object.ObjMetadataSet{idA},
+				Mismatched: []VerifyMismatch{
+					{Identifier: idB, OwningInventory: "other-inventory"},
+					{Identifier: idC},
+				},
+			},
+		},
+		"recorded UIDs": {
+			invObjs: object.ObjMetadataSet{idA, idD},
+			invStatus: []actuation.ObjectStatus{
+				{ObjectReference: ObjectReferenceFromObjMetadata(idA), UID: "uid-a"},
+				{ObjectReference: ObjectReferenceFromObjMetadata(idD), UID: "uid-d1"},
+			},
+			// pod-d was deleted and re-created
+			clusterObjs: []runtime.Object{podA, recreatedPodD},
+			expected: &VerifyReport{
+				Verified: object.ObjMetadataSet{idA},
+				Mismatched: []VerifyMismatch{
+					{
+						Identifier:      idD,
+						OwningInventory: testInventoryLabel,
+						RecordedUID:     "uid-d1",
+						UID:             "uid-d2",
+					},
+				},
+			},
+		},
+		"orphaned object": {
+			invObjs:     object.ObjMetadataSet{idA},
+			clusterObjs: []runtime.Object{podA, podB, podD},
+			expected: &VerifyReport{
+				Verified: object.ObjMetadataSet{idA},
+				Orphaned: object.ObjMetadataSet{idD},
+			},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			dc := fake.NewSimpleDynamicClient(scheme.Scheme, tc.clusterObjs...)
+			mapper := testrestmapper.TestOnlyStaticRESTMapper(scheme.Scheme,
+				scheme.Scheme.PrioritizedVersionsAllGroups()...)
+			invClient := NewFakeClient(tc.invObjs)
+			invClient.Status = tc.invStatus
+
+			report, err := Verify(context.Background(), invClient, dc, mapper, localInv)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, report)
+			assert.Equal(t, tc.healthy, report.Healthy())
+		})
+	}
+}
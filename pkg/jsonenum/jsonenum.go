@@ -0,0 +1,72 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package jsonenum encodes the integer enums generated with stringer as
+// JSON strings, so that consumers of the JSON do not depend on the order of
+// the constants.
+package jsonenum
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// maxValues limits the search for the value of a string.
+const maxValues = 1000
+
+// Enum is an integer enum with constants from zero, named by stringer.
+type Enum interface {
+	~int
+	String() string
+}
+
+// Marshal returns the JSON string of the enum value. Returns an error if the
+// value has no name.
+func Marshal[T Enum](v T) ([]byte, error) {
+	if !valid(v) {
+		return nil, fmt.Errorf("invalid enum value: %s", v)
+	}
+	return json.Marshal(v.String())
+}
+
+// Unmarshal decodes the enum value from a JSON string. JSON numbers are
+// accepted for compatibility with the integer encoding.
+func Unmarshal[T Enum](data []byte, v *T) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		var i int
+		if numErr := json.Unmarshal(data, &i); numErr != nil {
+			return err
+		}
+		if !valid(T(i)) {
+			return fmt.Errorf("invalid enum value: %d", i)
+		}
+		*v = T(i)
+		return nil
+	}
+	parsed, err := Parse[T](name)
+	if err != nil {
+		return err
+	}
+	*v = parsed
+	return nil
+}
+
+// Parse returns the enum value with the name.
+func Parse[T Enum](name string) (T, error) {
+	for i := T(0); i < maxValues && valid(i); i++ {
+		if i.String() == name {
+			return i, nil
+		}
+	}
+	var zero T
+	return zero, fmt.Errorf("invalid enum value: %q", name)
+}
+
+// valid returns true if the value has a name. Stringer formats unnamed
+// values as "Type(value)".
+func valid[T Enum](v T) bool {
+	return !strings.HasSuffix(v.String(), "("+strconv.Itoa(int(v))+")")
+}
@@ -0,0 +1,80 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package jsonenum
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type color int
+
+const (
+	red color = iota
+	green
+)
+
+func (c color) String() string {
+	switch c {
+	case red:
+		return "Red"
+	case green:
+		return "Green"
+	}
+	return "color(" + strconv.Itoa(int(c)) + ")"
+}
+
+func TestMarshal(t *testing.T) {
+	data, err := Marshal(green)
+	require.NoError(t, err)
+	assert.Equal(t, `"Green"`, string(data))
+
+	_, err = Marshal(color(2))
+	assert.EqualError(t, err, "invalid enum value: color(2)")
+}
+
+func TestUnmarshal(t *testing.T) {
+	testCases := map[string]struct {
+		data          string
+		expected      color
+		expectedError string
+	}{
+		"string": {
+			data:     `"Green"`,
+			expected: green,
+		},
+		"number": {
+			data:     `1`,
+			expected: green,
+		},
+		"unknown string": {
+			data:          `"Blue"`,
+			expectedError: `invalid enum value: "Blue"`,
+		},
+		"unknown number": {
+			data:          `2`,
+			expectedError: `invalid enum value: 2`,
+		},
+		"wrong type": {
+			data:          `true`,
+			expectedError: `json: cannot unmarshal bool into Go value of type string`,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			var c color
+			err := Unmarshal([]byte(tc.data), &c)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, c)
+		})
+	}
+}
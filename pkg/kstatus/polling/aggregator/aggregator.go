@@ -4,6 +4,7 @@
 package aggregator
 
 import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/event"
 	"sigs.k8s.io/cli-utils/pkg/kstatus/status"
 )
@@ -45,3 +46,117 @@ func AggregateStatus(rss []*event.ResourceStatus, desired status.Status) status.
 	}
 	return status.InProgressStatus
 }
+
+// Aggregator computes the aggregate status of the resources, given the
+// status the resources are expected to reach. It allows callers to decide
+// when a set of resources has reached the desired status, or has failed.
+type Aggregator interface {
+	Aggregate(rss []*event.ResourceStatus, desired status.Status) status.Status
+}
+
+// AggregatorFunc is a function that implements the Aggregator interface.
+type AggregatorFunc func(rss []*event.ResourceStatus, desired status.Status) status.Status
+
+// Aggregate calls the function.
+func (f AggregatorFunc) Aggregate(rss []*event.ResourceStatus, desired status.Status) status.Status {
+	return f(rss, desired)
+}
+
+// AllCurrent returns an Aggregator that requires all the resources to have
+// the desired status, following the rules of AggregateStatus.
+func AllCurrent() Aggregator {
+	return AggregatorFunc(AggregateStatus)
+}
+
+// Threshold returns an Aggregator that requires at least the given
+// percentage of the resources to have the desired status. The rules are the
+// following:
+//   - If at least percent% of the resources have the desired status, the
+//     aggregate status is the desired status.
+//   - If so many resources have the FailedStatus that percent% of the
+//     resources can no longer reach the desired status, the aggregate status
+//     is FailedStatus.
+//   - If at least one of the resources is UnknownStatus, the aggregate status
+//     is UnknownStatus
+//   - Otherwise, the aggregate status is InProgressStatus
+func Threshold(percent int) Aggregator {
+	return AggregatorFunc(func(rss []*event.ResourceStatus, desired status.Status) status.Status {
+		if len(rss) == 0 {
+			return desired
+		}
+
+		desiredCount := 0
+		failedCount := 0
+		anyUnknown := false
+		for _, rs := range rss {
+			switch rs.Status {
+			case desired:
+				desiredCount++
+			case status.FailedStatus:
+				failedCount++
+			case status.UnknownStatus:
+				anyUnknown = true
+			}
+		}
+		total := len(rss)
+		if desiredCount*100 >= percent*total {
+			return desired
+		}
+		if (total-failedCount)*100 < percent*total {
+			return status.FailedStatus
+		}
+		if anyUnknown {
+			return status.UnknownStatus
+		}
+		return status.InProgressStatus
+	})
+}
+
+// AnyFailed returns an Aggregator that short-circuits to FailedStatus as soon
+// as any of the resources has the FailedStatus, even if the wrapped Aggregator
+// tolerates failures, e.g. a Threshold. Otherwise, the status is computed by
+// the wrapped Aggregator.
+func AnyFailed(aggregator Aggregator) Aggregator {
+	return AggregatorFunc(func(rss []*event.ResourceStatus, desired status.Status) status.Status {
+		for _, rs := range rss {
+			if rs.Status == status.FailedStatus {
+				return status.FailedStatus
+			}
+		}
+		return aggregator.Aggregate(rss, desired)
+	})
+}
+
+// PerGroup returns an Aggregator that computes the aggregate status of the
+// resources of each GroupKind with the Aggregator of the GroupKind, or with
+// the default Aggregator for the GroupKinds without one. The aggregate
+// statuses of the GroupKinds are then aggregated with AllCurrent. If the
+// default Aggregator is nil, AllCurrent is used.
+func PerGroup(aggregators map[schema.GroupKind]Aggregator, defaultAggregator Aggregator) Aggregator {
+	if defaultAggregator == nil {
+		defaultAggregator = AllCurrent()
+	}
+	return AggregatorFunc(func(rss []*event.ResourceStatus, desired status.Status) status.Status {
+		var groupKinds []schema.GroupKind
+		groups := make(map[schema.GroupKind][]*event.ResourceStatus)
+		for _, rs := range rss {
+			gk := rs.Identifier.GroupKind
+			if _, found := groups[gk]; !found {
+				groupKinds = append(groupKinds, gk)
+			}
+			groups[gk] = append(groups[gk], rs)
+		}
+
+		groupStatuses := make([]*event.ResourceStatus, 0, len(groupKinds))
+		for _, gk := range groupKinds {
+			aggregator, found := aggregators[gk]
+			if !found {
+				aggregator = defaultAggregator
+			}
+			groupStatuses = append(groupStatuses, &event.ResourceStatus{
+				Status: aggregator.Aggregate(groups[gk], desired),
+			})
+		}
+		return AggregateStatus(groupStatuses, desired)
+	})
+}
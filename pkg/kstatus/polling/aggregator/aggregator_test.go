@@ -103,3 +103,100 @@ func TestAggregator(t *testing.T) {
 		})
 	}
 }
+
+func statuses(statuses ...status.Status) []*event.ResourceStatus {
+	rss := make([]*event.ResourceStatus, 0, len(statuses))
+	for _, s := range statuses {
+		rss = append(rss, &event.ResourceStatus{
+			Identifier: resourceIdentifiers["deployment"],
+			Status:     s,
+		})
+	}
+	return rss
+}
+
+func TestAggregators(t *testing.T) {
+	deploymentGK := resourceIdentifiers["deployment"].GroupKind
+	serviceStatus := func(s status.Status) *event.ResourceStatus {
+		return &event.ResourceStatus{
+			Identifier: resourceIdentifiers["service"],
+			Status:     s,
+		}
+	}
+
+	testCases := map[string]struct {
+		aggregator       Aggregator
+		resourceStatuses []*event.ResourceStatus
+		aggregateStatus  status.Status
+	}{
+		"all current": {
+			aggregator:       AllCurrent(),
+			resourceStatuses: statuses(status.CurrentStatus, status.InProgressStatus),
+			aggregateStatus:  status.InProgressStatus,
+		},
+		"threshold reached": {
+			aggregator: Threshold(75),
+			resourceStatuses: statuses(status.CurrentStatus, status.CurrentStatus,
+				status.CurrentStatus, status.FailedStatus),
+			aggregateStatus: status.CurrentStatus,
+		},
+		"threshold not reached": {
+			aggregator: Threshold(75),
+			resourceStatuses: statuses(status.CurrentStatus, status.CurrentStatus,
+				status.InProgressStatus, status.FailedStatus),
+			aggregateStatus: status.InProgressStatus,
+		},
+		"threshold not reached with unknown": {
+			aggregator: Threshold(75),
+			resourceStatuses: statuses(status.CurrentStatus, status.CurrentStatus,
+				status.UnknownStatus, status.FailedStatus),
+			aggregateStatus: status.UnknownStatus,
+		},
+		"threshold can no longer be reached": {
+			aggregator: Threshold(75),
+			resourceStatuses: statuses(status.CurrentStatus, status.InProgressStatus,
+				status.FailedStatus, status.FailedStatus),
+			aggregateStatus: status.FailedStatus,
+		},
+		"threshold with no resources": {
+			aggregator:      Threshold(90),
+			aggregateStatus: status.CurrentStatus,
+		},
+		"any failed": {
+			aggregator: AnyFailed(Threshold(75)),
+			resourceStatuses: statuses(status.CurrentStatus, status.CurrentStatus,
+				status.CurrentStatus, status.FailedStatus),
+			aggregateStatus: status.FailedStatus,
+		},
+		"any failed without failures": {
+			aggregator: AnyFailed(Threshold(75)),
+			resourceStatuses: statuses(status.CurrentStatus, status.CurrentStatus,
+				status.CurrentStatus, status.InProgressStatus),
+			aggregateStatus: status.CurrentStatus,
+		},
+		"per group": {
+			aggregator: PerGroup(map[schema.GroupKind]Aggregator{
+				deploymentGK: Threshold(50),
+			}, nil),
+			resourceStatuses: append(statuses(status.CurrentStatus, status.InProgressStatus),
+				serviceStatus(status.CurrentStatus)),
+			aggregateStatus: status.CurrentStatus,
+		},
+		"per group with default": {
+			aggregator: PerGroup(map[schema.GroupKind]Aggregator{
+				deploymentGK: Threshold(50),
+			}, nil),
+			resourceStatuses: append(statuses(status.CurrentStatus, status.InProgressStatus),
+				serviceStatus(status.CurrentStatus), serviceStatus(status.InProgressStatus)),
+			aggregateStatus: status.InProgressStatus,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			aggStatus := tc.aggregator.Aggregate(tc.resourceStatuses, status.CurrentStatus)
+
+			assert.Equal(t, tc.aggregateStatus, aggStatus)
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -48,6 +49,18 @@ var genGroupKinds = map[schema.GroupKind][]schema.GroupKind{
 			Kind:  "Pod",
 		},
 	},
+	schema.GroupKind{Group: "apps", Kind: "DaemonSet"}: { //nolint:gofmt
+		{
+			Group: "",
+			Kind:  "Pod",
+		},
+	},
+	schema.GroupKind{Group: "batch", Kind: "Job"}: { //nolint:gofmt
+		{
+			Group: "",
+			Kind:  "Pod",
+		},
+	},
 }
 
 // NewCachingClusterReader returns a new instance of the ClusterReader. The
@@ -57,6 +70,21 @@ var genGroupKinds = map[schema.GroupKind][]schema.GroupKind{
 // and namespace combinations it needs to cache when the Sync function is called.
 // We only want to fetch the resources that are actually needed.
 func NewCachingClusterReader(reader client.Reader, mapper meta.RESTMapper, identifiers object.ObjMetadataSet) (engine.ClusterReader, error) {
+	return newCachingClusterReaderWithTTL(reader, mapper, identifiers, 0)
+}
+
+// NewCachingClusterReaderFactory returns a factory of CachingClusterReaders
+// that keep the resources they have listed for the ttl, instead of listing
+// them again on every Sync. This reduces the number of LIST calls when the
+// resources of some GroupKinds are polled more often than others, at the
+// cost of reading resources up to ttl old.
+func NewCachingClusterReaderFactory(ttl time.Duration) engine.ClusterReaderFactory {
+	return engine.ClusterReaderFactoryFunc(func(reader client.Reader, mapper meta.RESTMapper, identifiers object.ObjMetadataSet) (engine.ClusterReader, error) {
+		return newCachingClusterReaderWithTTL(reader, mapper, identifiers, ttl)
+	})
+}
+
+func newCachingClusterReaderWithTTL(reader client.Reader, mapper meta.RESTMapper, identifiers object.ObjMetadataSet, ttl time.Duration) (*CachingClusterReader, error) {
 	gvkNamespaceSet := newGnSet()
 	for _, id := range identifiers {
 		// For every identifier, add the GroupVersionKind and namespace combination to the gvkNamespaceSet and
@@ -71,6 +99,7 @@ func NewCachingClusterReader(reader client.Reader, mapper meta.RESTMapper, ident
 		reader: reader,
 		mapper: mapper,
 		gns:    gvkNamespaceSet.gvkNamespaces,
+		ttl:    ttl,
 	}, nil
 }
 
@@ -133,12 +162,62 @@ type CachingClusterReader struct {
 	// cache contains the resources found in the cluster for the given combination
 	// of GVK and namespace. Before each polling cycle, the framework will call the
 	// Sync function, which is responsible for repopulating the cache.
+	// Combinations that are read, but were not known in advance, are listed
+	// on the first read, and then synced like the others.
 	cache map[gkNamespace]cacheEntry
+
+	// ttl is how long the listed resources are reused by Sync, instead of
+	// being listed again. Zero lists the resources on every Sync.
+	ttl time.Duration
 }
 
 type cacheEntry struct {
 	resources unstructured.UnstructuredList
 	err       error
+	// syncedAt is when the resources were listed.
+	syncedAt time.Time
+	// selections caches the resources matching the selectors they have been
+	// listed with, since the status readers of resources with overlapping
+	// selectors, e.g. Deployments, list the same resources in every cycle.
+	selections *selections
+}
+
+type selections struct {
+	mx    sync.Mutex
+	items map[string][]unstructured.Unstructured
+}
+
+func newSelections() *selections {
+	return &selections{
+		items: make(map[string][]unstructured.Unstructured),
+	}
+}
+
+// selectedItems returns the resources of the entry that match the selector.
+func (e cacheEntry) selectedItems(selector labels.Selector) []unstructured.Unstructured {
+	if e.selections == nil {
+		return selectItems(e.resources.Items, selector)
+	}
+	key := selector.String()
+	e.selections.mx.Lock()
+	defer e.selections.mx.Unlock()
+	items, found := e.selections.items[key]
+	if !found {
+		items = selectItems(e.resources.Items, selector)
+		e.selections.items[key] = items
+	}
+	// Callers own the returned slice.
+	return append([]unstructured.Unstructured(nil), items...)
+}
+
+func selectItems(resources []unstructured.Unstructured, selector labels.Selector) []unstructured.Unstructured {
+	var items []unstructured.Unstructured
+	for _, u := range resources {
+		if selector.Matches(labels.Set(u.GetLabels())) {
+			items = append(items, u)
+		}
+	}
+	return items
 }
 
 // gkNamespace contains information about a GroupVersionKind and a namespace.
@@ -149,9 +228,7 @@ type gkNamespace struct {
 
 // Get looks up the resource identified by the key and the object GVK in the cache. If the needed combination
 // of GVK and namespace is not part of the cache, that is considered an error.
-func (c *CachingClusterReader) Get(_ context.Context, key client.ObjectKey, obj *unstructured.Unstructured) error {
-	c.mx.RLock()
-	defer c.mx.RUnlock()
+func (c *CachingClusterReader) Get(ctx context.Context, key client.ObjectKey, obj *unstructured.Unstructured) error {
 	gvk := obj.GetObjectKind().GroupVersionKind()
 	mapping, err := c.mapper.RESTMapping(gvk.GroupKind())
 	if err != nil {
@@ -161,9 +238,9 @@ func (c *CachingClusterReader) Get(_ context.Context, key client.ObjectKey, obj
 		GroupKind: gvk.GroupKind(),
 		Namespace: key.Namespace,
 	}
-	cacheEntry, found := c.cache[gn]
-	if !found {
-		return fmt.Errorf("GVK %s and Namespace %s not found in cache", gvk.String(), gn.Namespace)
+	cacheEntry, err := c.entry(ctx, gn)
+	if err != nil {
+		return err
 	}
 
 	if cacheEntry.err != nil {
@@ -180,31 +257,23 @@ func (c *CachingClusterReader) Get(_ context.Context, key client.ObjectKey, obj
 
 // ListNamespaceScoped lists all resource identifier by the GVK of the list, the namespace and the selector
 // from the cache. If the needed combination of GVK and namespace is not part of the cache, that is considered an error.
-func (c *CachingClusterReader) ListNamespaceScoped(_ context.Context, list *unstructured.UnstructuredList, namespace string, selector labels.Selector) error {
-	c.mx.RLock()
-	defer c.mx.RUnlock()
+func (c *CachingClusterReader) ListNamespaceScoped(ctx context.Context, list *unstructured.UnstructuredList, namespace string, selector labels.Selector) error {
 	gvk := list.GroupVersionKind()
 	gn := gkNamespace{
 		GroupKind: gvk.GroupKind(),
 		Namespace: namespace,
 	}
 
-	cacheEntry, found := c.cache[gn]
-	if !found {
-		return fmt.Errorf("GVK %s and Namespace %s not found in cache", gvk.String(), gn.Namespace)
+	cacheEntry, err := c.entry(ctx, gn)
+	if err != nil {
+		return err
 	}
 
 	if cacheEntry.err != nil {
 		return cacheEntry.err
 	}
 
-	var items []unstructured.Unstructured
-	for _, u := range cacheEntry.resources.Items {
-		if selector.Matches(labels.Set(u.GetLabels())) {
-			items = append(items, u)
-		}
-	}
-	list.Items = items
+	list.Items = cacheEntry.selectedItems(selector)
 	return nil
 }
 
@@ -215,53 +284,111 @@ func (c *CachingClusterReader) ListClusterScoped(ctx context.Context, list *unst
 	return c.ListNamespaceScoped(ctx, list, "", selector)
 }
 
+// entry returns the cache entry of the combination of GVK and namespace. If
+// the combination is not part of the cache, its resources are listed, and the
+// combination is synced from then on.
+func (c *CachingClusterReader) entry(ctx context.Context, gn gkNamespace) (cacheEntry, error) {
+	c.mx.RLock()
+	entry, found := c.cache[gn]
+	c.mx.RUnlock()
+	if found {
+		return entry, nil
+	}
+
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	// The combination may have been listed while waiting for the lock.
+	if entry, found := c.cache[gn]; found {
+		return entry, nil
+	}
+	entry, err := c.list(ctx, gn, time.Now())
+	if err != nil {
+		return cacheEntry{}, err
+	}
+	if c.cache == nil {
+		c.cache = make(map[gkNamespace]cacheEntry)
+	}
+	c.cache[gn] = entry
+	c.addGn(gn)
+	return entry, nil
+}
+
+// addGn adds the combination of GVK and namespace to the synced
+// combinations, if it is not one of them yet.
+func (c *CachingClusterReader) addGn(gn gkNamespace) {
+	for _, g := range c.gns {
+		if g == gn {
+			return
+		}
+	}
+	c.gns = append(c.gns, gn)
+}
+
 // Sync loops over the list of gkNamespace we know of, and uses list calls to fetch the resources.
-// This information populates the cache.
+// This information populates the cache. Resources listed less than the ttl ago are not listed again.
 func (c *CachingClusterReader) Sync(ctx context.Context) error {
 	c.mx.Lock()
 	defer c.mx.Unlock()
+	now := time.Now()
 	cache := make(map[gkNamespace]cacheEntry)
 	for _, gn := range c.gns {
-		mapping, err := c.mapper.RESTMapping(gn.GroupKind)
+		if entry, found := c.cache[gn]; found && entry.err == nil &&
+			c.ttl > 0 && now.Sub(entry.syncedAt) < c.ttl {
+			cache[gn] = entry
+			continue
+		}
+		entry, err := c.list(ctx, gn, now)
 		if err != nil {
-			if meta.IsNoMatchError(err) {
-				// If we get a NoMatchError, it means we are checking for
-				// a type that doesn't exist. Presumably the CRD is being
-				// applied, so it will be added. Reset the RESTMapper to
-				// make sure we pick up any new resource types on the
-				// APIServer.
-				cache[gn] = cacheEntry{
-					err: err,
-				}
-				continue
-			}
 			return err
 		}
-		ns := ""
-		if mapping.Scope == meta.RESTScopeNamespace {
-			ns = gn.Namespace
-		}
-		list, err := c.listUnstructured(ctx, mapping.GroupVersionKind, ns)
-		if err != nil {
-			// If the context was cancelled, we just stop the work and return
-			// the error.
-			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
-				return err
-			}
-			// For other errors, we just keep it the error. Whenever any pollers
-			// request a resource covered by this gns, we just return the
-			// error.
-			cache[gn] = cacheEntry{
+		cache[gn] = entry
+	}
+	c.cache = cache
+	return nil
+}
+
+// list lists the resources of the combination of GVK and namespace. Errors
+// are kept in the returned cacheEntry, except for errors that should stop
+// the sync.
+func (c *CachingClusterReader) list(ctx context.Context, gn gkNamespace, now time.Time) (cacheEntry, error) {
+	mapping, err := c.mapper.RESTMapping(gn.GroupKind)
+	if err != nil {
+		if engine.IsDiscoveryError(err) {
+			// If we get a NoMatchError, it means we are checking for
+			// a type that doesn't exist. Presumably the CRD is being
+			// applied, so it will be added once the PollerEngine has
+			// reset the RESTMapper. The same goes for types whose
+			// discovery failed. Keep the error for the readers of the
+			// type, and list it again on the next sync.
+			return cacheEntry{
 				err: err,
-			}
-			continue
+			}, nil
 		}
-		cache[gn] = cacheEntry{
-			resources: *list,
+		return cacheEntry{}, err
+	}
+	ns := ""
+	if mapping.Scope == meta.RESTScopeNamespace {
+		ns = gn.Namespace
+	}
+	list, err := c.listUnstructured(ctx, mapping.GroupVersionKind, ns)
+	if err != nil {
+		// If the context was cancelled, we just stop the work and return
+		// the error.
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return cacheEntry{}, err
 		}
+		// For other errors, we just keep it the error. Whenever any pollers
+		// request a resource covered by this gns, we just return the
+		// error.
+		return cacheEntry{
+			err: err,
+		}, nil
 	}
-	c.cache = cache
-	return nil
+	return cacheEntry{
+		resources:  *list,
+		syncedAt:   now,
+		selections: newSelections(),
+	}, nil
 }
 
 // listUnstructured performs one or more LIST calls, paginating the requests
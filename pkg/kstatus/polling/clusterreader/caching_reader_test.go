@@ -10,6 +10,7 @@ import (
 	"sort"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -20,6 +21,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/cli-utils/pkg/object"
 	"sigs.k8s.io/cli-utils/pkg/testutil"
@@ -426,3 +428,136 @@ func cacheEntryComparer() cmp.Option {
 		return string(xBytes) == string(yBytes)
 	})
 }
+
+func TestBuildGvkNamespaceSet(t *testing.T) {
+	testCases := map[string]struct {
+		groupKind schema.GroupKind
+		expected  []gkNamespace
+	}{
+		"without generated resources": {
+			groupKind: schema.GroupKind{Group: "", Kind: "ConfigMap"},
+			expected: []gkNamespace{
+				{GroupKind: schema.GroupKind{Group: "", Kind: "ConfigMap"}, Namespace: "default"},
+			},
+		},
+		"deployment": {
+			groupKind: deploymentGVK.GroupKind(),
+			expected: []gkNamespace{
+				{GroupKind: deploymentGVK.GroupKind(), Namespace: "default"},
+				{GroupKind: rsGVK.GroupKind(), Namespace: "default"},
+				{GroupKind: podGVK.GroupKind(), Namespace: "default"},
+			},
+		},
+		"daemonset": {
+			groupKind: appsv1.SchemeGroupVersion.WithKind("DaemonSet").GroupKind(),
+			expected: []gkNamespace{
+				{GroupKind: appsv1.SchemeGroupVersion.WithKind("DaemonSet").GroupKind(), Namespace: "default"},
+				{GroupKind: podGVK.GroupKind(), Namespace: "default"},
+			},
+		},
+		"job": {
+			groupKind: schema.GroupKind{Group: "batch", Kind: "Job"},
+			expected: []gkNamespace{
+				{GroupKind: schema.GroupKind{Group: "batch", Kind: "Job"}, Namespace: "default"},
+				{GroupKind: podGVK.GroupKind(), Namespace: "default"},
+			},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			set := newGnSet()
+			require.NoError(t, buildGvkNamespaceSet([]schema.GroupKind{tc.groupKind}, "default", set))
+			assert.Equal(t, tc.expected, set.gvkNamespaces)
+		})
+	}
+}
+
+func TestCachingClusterReader_TTL(t *testing.T) {
+	identifiers := object.ObjMetadataSet{
+		{
+			GroupKind: podGVK.GroupKind(),
+			Name:      "pod",
+			Namespace: "default",
+		},
+	}
+	podGKN := gkNamespace{GroupKind: podGVK.GroupKind(), Namespace: "default"}
+	fakeMapper := testutil.NewFakeRESTMapper(podGVK)
+
+	testCases := map[string]struct {
+		ttl            time.Duration
+		expectedSynced []gkNamespace
+	}{
+		"without ttl": {
+			expectedSynced: []gkNamespace{podGKN, podGKN},
+		},
+		"with ttl": {
+			ttl:            time.Hour,
+			expectedSynced: []gkNamespace{podGKN},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			fakeReader := &fakeReader{}
+			factory := NewCachingClusterReaderFactory(tc.ttl)
+			clusterReader, err := factory.New(fakeReader, fakeMapper, identifiers)
+			require.NoError(t, err)
+
+			require.NoError(t, clusterReader.Sync(context.Background()))
+			require.NoError(t, clusterReader.Sync(context.Background()))
+			assert.Equal(t, tc.expectedSynced, fakeReader.syncedGVKNamespaces)
+		})
+	}
+}
+
+func TestCachingClusterReader_ReadThrough(t *testing.T) {
+	rsGKN := gkNamespace{GroupKind: rsGVK.GroupKind(), Namespace: "default"}
+	rs := unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "ReplicaSet",
+			"metadata": map[string]interface{}{
+				"name":      "rs",
+				"namespace": "default",
+				"labels": map[string]interface{}{
+					"app": "app",
+				},
+			},
+		},
+	}
+	fakeReader := &fakeReader{
+		clusterObjs: map[gkNamespace][]unstructured.Unstructured{
+			rsGKN: {rs},
+		},
+	}
+	fakeMapper := testutil.NewFakeRESTMapper(crdGVK, rsGVK)
+	clusterReader, err := newCachingClusterReader(fakeReader, fakeMapper, object.ObjMetadataSet{
+		{
+			GroupKind: crdGVK.GroupKind(),
+			Name:      "crd",
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, clusterReader.Sync(context.Background()))
+
+	// ReplicaSets are not part of the cache, so they are listed when they
+	// are first read, and synced from then on.
+	selector := labels.SelectorFromSet(labels.Set{"app": "app"})
+	for i := 0; i < 2; i++ {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(rsGVK)
+		require.NoError(t, clusterReader.ListNamespaceScoped(context.Background(), list, "default", selector))
+		require.Len(t, list.Items, 1)
+		assert.Equal(t, "rs", list.Items[0].GetName())
+	}
+	assert.Len(t, clusterReader.cache[rsGKN].selections.items, 1)
+
+	require.NoError(t, clusterReader.Sync(context.Background()))
+	assert.Equal(t, []gkNamespace{
+		{GroupKind: crdGVK.GroupKind()},
+		rsGKN,
+		{GroupKind: crdGVK.GroupKind()},
+		rsGKN,
+	}, fakeReader.syncedGVKNamespaces)
+}
@@ -0,0 +1,63 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package fake
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/cli-utils/pkg/testutil"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// reader is the engine.ClusterReader interface. It is not imported, because
+// the tests of the engine package use this package.
+type reader interface {
+	Get(ctx context.Context, key client.ObjectKey, obj *unstructured.Unstructured) error
+	ListNamespaceScoped(ctx context.Context, list *unstructured.UnstructuredList, namespace string, selector labels.Selector) error
+	ListClusterScoped(ctx context.Context, list *unstructured.UnstructuredList, selector labels.Selector) error
+	Sync(ctx context.Context) error
+}
+
+// FaultyClusterReader is a ClusterReader that injects the faults of the
+// Faults into the calls to the wrapped Reader.
+type FaultyClusterReader struct {
+	Reader reader
+	Faults *testutil.FaultInjector
+}
+
+func (f *FaultyClusterReader) Get(ctx context.Context, key client.ObjectKey, obj *unstructured.Unstructured) error {
+	if err := f.Faults.Inject(ctx, "get", groupResource(obj.GroupVersionKind()), key.Name); err != nil {
+		return err
+	}
+	return f.Reader.Get(ctx, key, obj)
+}
+
+func (f *FaultyClusterReader) ListNamespaceScoped(ctx context.Context, list *unstructured.UnstructuredList,
+	namespace string, selector labels.Selector) error {
+	if err := f.Faults.Inject(ctx, "list", groupResource(list.GroupVersionKind()), ""); err != nil {
+		return err
+	}
+	return f.Reader.ListNamespaceScoped(ctx, list, namespace, selector)
+}
+
+func (f *FaultyClusterReader) ListClusterScoped(ctx context.Context, list *unstructured.UnstructuredList,
+	selector labels.Selector) error {
+	if err := f.Faults.Inject(ctx, "list", groupResource(list.GroupVersionKind()), ""); err != nil {
+		return err
+	}
+	return f.Reader.ListClusterScoped(ctx, list, selector)
+}
+
+func (f *FaultyClusterReader) Sync(ctx context.Context) error {
+	return f.Reader.Sync(ctx)
+}
+
+// groupResource returns an approximate GroupResource of the kind, for the
+// messages of injected errors.
+func groupResource(gvk schema.GroupVersionKind) schema.GroupResource {
+	return schema.GroupResource{Group: gvk.Group, Resource: gvk.Kind}
+}
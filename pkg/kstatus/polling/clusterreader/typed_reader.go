@@ -0,0 +1,129 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterreader
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NewTypedReader returns a client.Reader that reads the resources of the
+// given kinds as typed objects, and converts them to unstructured objects.
+// The controller-runtime client reads typed built-in objects with protobuf
+// encoding, which is cheaper to transfer and decode than JSON. Resources of
+// other kinds, or kinds not registered in the scheme, are read with the
+// given reader as is.
+func NewTypedReader(reader client.Reader, scheme *runtime.Scheme, kinds []schema.GroupKind) client.Reader {
+	kindSet := make(map[schema.GroupKind]bool, len(kinds))
+	for _, gk := range kinds {
+		kindSet[gk] = true
+	}
+	return &TypedReader{
+		Reader: reader,
+		Scheme: scheme,
+		Kinds:  kindSet,
+	}
+}
+
+// TypedReader is an implementation of client.Reader that reads the
+// unstructured objects of a set of kinds as typed objects.
+type TypedReader struct {
+	Reader client.Reader
+	Scheme *runtime.Scheme
+	Kinds  map[schema.GroupKind]bool
+}
+
+func (r *TypedReader) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return r.Reader.Get(ctx, key, obj, opts...)
+	}
+	gvk := u.GroupVersionKind()
+	typed, ok := r.newTyped(gvk)
+	if !ok {
+		return r.Reader.Get(ctx, key, obj, opts...)
+	}
+	typedObj, ok := typed.(client.Object)
+	if !ok {
+		return r.Reader.Get(ctx, key, obj, opts...)
+	}
+	if err := r.Reader.Get(ctx, key, typedObj, opts...); err != nil {
+		return err
+	}
+	return toUnstructured(typedObj, gvk, u)
+}
+
+func (r *TypedReader) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	u, ok := list.(*unstructured.UnstructuredList)
+	if !ok {
+		return r.Reader.List(ctx, list, opts...)
+	}
+	gvk := u.GroupVersionKind()
+	typed, ok := r.newTyped(gvk)
+	if !ok {
+		return r.Reader.List(ctx, list, opts...)
+	}
+	typedList, ok := typed.(client.ObjectList)
+	if !ok {
+		return r.Reader.List(ctx, list, opts...)
+	}
+	if err := r.Reader.List(ctx, typedList, opts...); err != nil {
+		return err
+	}
+	items, err := meta.ExtractList(typedList)
+	if err != nil {
+		return fmt.Errorf("failed to extract list items: %w", err)
+	}
+	itemGVK := gvk.GroupVersion().WithKind(listItemKind(gvk.Kind))
+	u.Items = make([]unstructured.Unstructured, len(items))
+	for i, item := range items {
+		if err := toUnstructured(item, itemGVK, &u.Items[i]); err != nil {
+			return err
+		}
+	}
+	u.SetResourceVersion(typedList.GetResourceVersion())
+	u.SetContinue(typedList.GetContinue())
+	return nil
+}
+
+// newTyped returns a new typed object for the GroupVersionKind, if the kind
+// is read as typed objects and registered in the scheme. The GroupVersionKind
+// of lists ends with "List".
+func (r *TypedReader) newTyped(gvk schema.GroupVersionKind) (runtime.Object, bool) {
+	gk := gvk.GroupKind()
+	gk.Kind = listItemKind(gk.Kind)
+	if !r.Kinds[gk] || !r.Scheme.Recognizes(gvk) {
+		return nil, false
+	}
+	obj, err := r.Scheme.New(gvk)
+	if err != nil {
+		return nil, false
+	}
+	return obj, true
+}
+
+// listItemKind returns the kind of the items of a list kind, or the kind
+// itself if it is not a list kind.
+func listItemKind(kind string) string {
+	return strings.TrimSuffix(kind, "List")
+}
+
+// toUnstructured converts the typed object into the unstructured object,
+// setting the GroupVersionKind, which typed objects usually lack.
+func toUnstructured(obj runtime.Object, gvk schema.GroupVersionKind, u *unstructured.Unstructured) error {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return fmt.Errorf("failed to convert %s to unstructured: %w", gvk.Kind, err)
+	}
+	u.SetUnstructuredContent(content)
+	u.SetGroupVersionKind(gvk)
+	return nil
+}
@@ -0,0 +1,82 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterreader
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/kubectl/pkg/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestTypedReader(t *testing.T) {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+	}
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "bar", Namespace: "default"},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(deployment, pod).Build()
+	reader := &recordingReader{Reader: fakeClient}
+	typedReader := NewTypedReader(reader, scheme.Scheme, []schema.GroupKind{deploymentGVK.GroupKind()})
+
+	testCases := map[string]struct {
+		gvk          schema.GroupVersionKind
+		name         string
+		expectedType string
+	}{
+		"typed kind": {
+			gvk:          deploymentGVK,
+			name:         "foo",
+			expectedType: "*v1.Deployment",
+		},
+		"other kind": {
+			gvk:          podGVK,
+			name:         "bar",
+			expectedType: "*unstructured.Unstructured",
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			reader.types = nil
+			obj := &unstructured.Unstructured{}
+			obj.SetGroupVersionKind(tc.gvk)
+			err := typedReader.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: tc.name}, obj)
+			require.NoError(t, err)
+			assert.Equal(t, []string{tc.expectedType}, reader.types)
+			assert.Equal(t, tc.gvk, obj.GroupVersionKind())
+			assert.Equal(t, tc.name, obj.GetName())
+
+			list := &unstructured.UnstructuredList{}
+			list.SetGroupVersionKind(tc.gvk.GroupVersion().WithKind(tc.gvk.Kind + "List"))
+			err = typedReader.List(context.Background(), list, client.InNamespace("default"))
+			require.NoError(t, err)
+			require.Len(t, list.Items, 1)
+			assert.Equal(t, tc.gvk, list.Items[0].GroupVersionKind())
+			assert.Equal(t, tc.name, list.Items[0].GetName())
+		})
+	}
+}
+
+// recordingReader records the types of the objects it reads.
+type recordingReader struct {
+	client.Reader
+	types []string
+}
+
+func (r *recordingReader) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	r.types = append(r.types, fmt.Sprintf("%T", obj))
+	return r.Reader.Get(ctx, key, obj, opts...)
+}
@@ -0,0 +1,295 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterreader
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/engine"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NewWatchingClusterReaderFactory returns a ClusterReaderFactory that creates
+// a WatchingClusterReader for each call to Poll, which reads the resources
+// with the dynamic client. The client.Reader of the StatusPoller is not used.
+func NewWatchingClusterReaderFactory(dynamicClient dynamic.Interface) engine.ClusterReaderFactory {
+	return engine.ClusterReaderFactoryFunc(func(_ client.Reader, mapper meta.RESTMapper, identifiers object.ObjMetadataSet) (engine.ClusterReader, error) {
+		return NewWatchingClusterReader(dynamicClient, mapper, identifiers)
+	})
+}
+
+// NewWatchingClusterReader returns a new instance of the WatchingClusterReader.
+// Like the CachingClusterReader, it reads the resources of all the GroupKind
+// and namespace combinations referenced by the identifiers, including the
+// generated resources.
+func NewWatchingClusterReader(dynamicClient dynamic.Interface, mapper meta.RESTMapper, identifiers object.ObjMetadataSet) (*WatchingClusterReader, error) {
+	gvkNamespaceSet := newGnSet()
+	for _, id := range identifiers {
+		err := buildGvkNamespaceSet([]schema.GroupKind{id.GroupKind}, id.Namespace, gvkNamespaceSet)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &WatchingClusterReader{
+		client:    dynamicClient,
+		mapper:    mapper,
+		gns:       gvkNamespaceSet.gvkNamespaces,
+		informers: make(map[gkNamespace]*informerEntry),
+		changed:   make(chan struct{}, 1),
+	}, nil
+}
+
+// WatchingClusterReader is an implementation of the ClusterReader interface
+// that keeps a local cache of the resources, with an informer for each
+// combination of GroupKind and namespace. Unlike the CachingClusterReader,
+// which LISTs all the resources before every polling loop, the resources are
+// only LISTed once, and then kept up to date with a WATCH, which reduces the
+// load on the apiserver for long waits on large sets of resources.
+//
+// The WatchingClusterReader implements engine.ChangeNotifier, so that the
+// status is computed as soon as a resource changes. The poll interval then
+// only acts as a fallback, and can be increased.
+//
+// The informers are started by the first call to Sync, and stopped when the
+// context of that call is done, i.e. when polling stops.
+type WatchingClusterReader struct {
+	mx sync.RWMutex
+
+	// client is used to list and watch the resources.
+	client dynamic.Interface
+
+	// mapper is used to resolve the resource of the GroupKinds.
+	mapper meta.RESTMapper
+
+	// gns contains the GroupKind and namespace combinations to watch.
+	gns []gkNamespace
+
+	// informers contains the started informer of each GroupKind and
+	// namespace combination, or the error that prevented starting it.
+	informers map[gkNamespace]*informerEntry
+
+	// changed receives a value when a watched resource has changed.
+	changed chan struct{}
+}
+
+var _ engine.ClusterReader = &WatchingClusterReader{}
+var _ engine.ChangeNotifier = &WatchingClusterReader{}
+
+type informerEntry struct {
+	informer cache.SharedIndexInformer
+
+	// mx guards err.
+	mx sync.Mutex
+	// err is the last error of the informer, if it has not synced yet, or
+	// the error that prevented starting it.
+	err error
+}
+
+func (e *informerEntry) setErr(err error) {
+	e.mx.Lock()
+	defer e.mx.Unlock()
+	e.err = err
+}
+
+// readErr returns the error to return when reading from the informer, or nil
+// if its cache has synced.
+func (e *informerEntry) readErr() error {
+	if e.informer != nil && e.informer.HasSynced() {
+		return nil
+	}
+	e.mx.Lock()
+	defer e.mx.Unlock()
+	return e.err
+}
+
+// Changed returns the channel that receives a value when a watched resource
+// has changed. Changes are coalesced until the value is received.
+func (w *WatchingClusterReader) Changed() <-chan struct{} {
+	return w.changed
+}
+
+// Get looks up the resource identified by the key and the object GVK in the
+// cache of the informer. If the needed combination of GVK and namespace is
+// not watched, that is considered an error.
+func (w *WatchingClusterReader) Get(_ context.Context, key client.ObjectKey, obj *unstructured.Unstructured) error {
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	mapping, err := w.mapper.RESTMapping(gvk.GroupKind())
+	if err != nil {
+		return err
+	}
+	entry, err := w.entry(gkNamespace{GroupKind: gvk.GroupKind(), Namespace: key.Namespace})
+	if err != nil {
+		return err
+	}
+	storeKey := key.Name
+	if key.Namespace != "" {
+		storeKey = key.Namespace + "/" + key.Name
+	}
+	item, found, err := entry.informer.GetStore().GetByKey(storeKey)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return apierrors.NewNotFound(mapping.Resource.GroupResource(), key.Name)
+	}
+	u, ok := item.(*unstructured.Unstructured)
+	if !ok {
+		return fmt.Errorf("unexpected cache item type: %T", item)
+	}
+	obj.Object = u.DeepCopy().Object
+	return nil
+}
+
+// ListNamespaceScoped lists the resources of the GVK of the list in the
+// namespace that match the selector, from the cache of the informer. If the
+// needed combination of GVK and namespace is not watched, that is considered
+// an error.
+func (w *WatchingClusterReader) ListNamespaceScoped(_ context.Context, list *unstructured.UnstructuredList, namespace string, selector labels.Selector) error {
+	gvk := list.GroupVersionKind()
+	entry, err := w.entry(gkNamespace{GroupKind: gvk.GroupKind(), Namespace: namespace})
+	if err != nil {
+		return err
+	}
+	var items []unstructured.Unstructured
+	for _, item := range entry.informer.GetStore().List() {
+		u, ok := item.(*unstructured.Unstructured)
+		if !ok {
+			return fmt.Errorf("unexpected cache item type: %T", item)
+		}
+		if selector.Matches(labels.Set(u.GetLabels())) {
+			items = append(items, *u.DeepCopy())
+		}
+	}
+	list.Items = items
+	return nil
+}
+
+// ListClusterScoped lists the resources of the GVK of the list that match
+// the selector, from the cache of the informer.
+func (w *WatchingClusterReader) ListClusterScoped(ctx context.Context, list *unstructured.UnstructuredList, selector labels.Selector) error {
+	return w.ListNamespaceScoped(ctx, list, "", selector)
+}
+
+// entry returns the informer of the combination of GroupKind and namespace,
+// or the error that prevents reading from it.
+func (w *WatchingClusterReader) entry(gn gkNamespace) (*informerEntry, error) {
+	w.mx.RLock()
+	defer w.mx.RUnlock()
+	entry, found := w.informers[gn]
+	if !found {
+		return nil, fmt.Errorf("GroupKind %s and Namespace %s not watched", gn.GroupKind, gn.Namespace)
+	}
+	if err := entry.readErr(); err != nil {
+		return nil, err
+	}
+	if entry.informer == nil || !entry.informer.HasSynced() {
+		return nil, fmt.Errorf("GroupKind %s and Namespace %s not synced", gn.GroupKind, gn.Namespace)
+	}
+	return entry, nil
+}
+
+// Sync starts the informers that are not started yet, and waits until each
+// informer has either synced or failed to list the resources. Informers that
+// could not be started because their GroupKind was not found, e.g. because
+// the CRD is being applied, are retried on every call.
+func (w *WatchingClusterReader) Sync(ctx context.Context) error {
+	w.mx.Lock()
+	var entries []*informerEntry
+	for _, gn := range w.gns {
+		entry, found := w.informers[gn]
+		if !found || entry.informer == nil {
+			var err error
+			entry, err = w.startInformer(ctx, gn)
+			if err != nil {
+				w.mx.Unlock()
+				return err
+			}
+			w.informers[gn] = entry
+		}
+		entries = append(entries, entry)
+	}
+	w.mx.Unlock()
+
+	return wait.PollUntilContextCancel(ctx, 100*time.Millisecond, true, func(context.Context) (bool, error) {
+		for _, entry := range entries {
+			if entry.informer != nil && !entry.informer.HasSynced() && entry.readErr() == nil {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+}
+
+// startInformer starts the informer of the combination of GroupKind and
+// namespace, until the context is done. If the GroupKind is not found, the
+// returned entry has no informer, and records the error.
+func (w *WatchingClusterReader) startInformer(ctx context.Context, gn gkNamespace) (*informerEntry, error) {
+	mapping, err := w.mapper.RESTMapping(gn.GroupKind)
+	if err != nil {
+		if engine.IsDiscoveryError(err) {
+			return &informerEntry{err: err}, nil
+		}
+		return nil, err
+	}
+	ns := ""
+	if mapping.Scope == meta.RESTScopeNamespace {
+		ns = gn.Namespace
+	}
+	resource := w.client.Resource(mapping.Resource).Namespace(ns)
+	example := &unstructured.Unstructured{}
+	example.SetGroupVersionKind(mapping.GroupVersionKind)
+	informer := cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return resource.List(ctx, options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return resource.Watch(ctx, options)
+			},
+		},
+		example,
+		0,
+		cache.Indexers{},
+	)
+	entry := &informerEntry{informer: informer}
+	// Record the list errors, e.g. forbidden, so that Sync does not wait
+	// forever for the informer to sync, and the reads return the error.
+	if err := informer.SetWatchErrorHandler(func(_ *cache.Reflector, err error) {
+		entry.setErr(err)
+	}); err != nil {
+		return nil, err
+	}
+	if _, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { w.notify() },
+		UpdateFunc: func(interface{}, interface{}) { w.notify() },
+		DeleteFunc: func(interface{}) { w.notify() },
+	}); err != nil {
+		return nil, err
+	}
+	go informer.Run(ctx.Done())
+	return entry, nil
+}
+
+// notify signals a change, unless a change is already pending.
+func (w *WatchingClusterReader) notify() {
+	select {
+	case w.changed <- struct{}{}:
+	default:
+	}
+}
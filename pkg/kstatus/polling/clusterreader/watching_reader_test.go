@@ -0,0 +1,105 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterreader
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/cli-utils/pkg/testutil"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newWatchedObject(gvk schema.GroupVersionKind, namespace, name string, labels map[string]string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(gvk)
+	u.SetNamespace(namespace)
+	u.SetName(name)
+	u.SetLabels(labels)
+	return u
+}
+
+func TestWatchingClusterReader(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mapper := testutil.NewFakeRESTMapper(deploymentGVK, rsGVK, podGVK)
+	deployment := newWatchedObject(deploymentGVK, "default", "app", nil)
+	rs := newWatchedObject(rsGVK, "default", "app-1", map[string]string{"app": "app"})
+	otherRS := newWatchedObject(rsGVK, "default", "other-1", map[string]string{"app": "other"})
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			deploymentGVK.GroupVersion().WithResource("deployments"): "DeploymentList",
+			rsGVK.GroupVersion().WithResource("replicasets"):         "ReplicaSetList",
+			podGVK.GroupVersion().WithResource("pods"):               "PodList",
+		},
+		deployment, rs, otherRS)
+
+	reader, err := NewWatchingClusterReader(dynamicClient, mapper, object.ObjMetadataSet{
+		object.UnstructuredToObjMetadata(deployment),
+	})
+	require.NoError(t, err)
+	require.NoError(t, reader.Sync(ctx))
+
+	// Get from the cache.
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(deploymentGVK)
+	require.NoError(t, reader.Get(ctx, client.ObjectKey{Namespace: "default", Name: "app"}, obj))
+	assert.Equal(t, "app", obj.GetName())
+
+	obj = &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(deploymentGVK)
+	err = reader.Get(ctx, client.ObjectKey{Namespace: "default", Name: "missing"}, obj)
+	assert.True(t, errors.IsNotFound(err), "expected NotFound, got %v", err)
+
+	// List the generated resources from the cache.
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(rsGVK)
+	selector := labels.SelectorFromSet(labels.Set{"app": "app"})
+	require.NoError(t, reader.ListNamespaceScoped(ctx, list, "default", selector))
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, "app-1", list.Items[0].GetName())
+
+	// Namespaces that are not watched are not read.
+	obj = &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(deploymentGVK)
+	err = reader.Get(ctx, client.ObjectKey{Namespace: "other", Name: "app"}, obj)
+	assert.Error(t, err)
+
+	// Changes are notified, and then read from the cache.
+	drain(reader.Changed())
+	pod := newWatchedObject(podGVK, "default", "app-1-abcde", map[string]string{"app": "app"})
+	_, err = dynamicClient.Resource(podGVK.GroupVersion().WithResource("pods")).Namespace("default").
+		Create(ctx, pod, metav1.CreateOptions{})
+	require.NoError(t, err)
+	select {
+	case <-reader.Changed():
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for the change notification")
+	}
+	list = &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(podGVK)
+	require.NoError(t, reader.ListNamespaceScoped(ctx, list, "default", selector))
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, "app-1-abcde", list.Items[0].GetName())
+}
+
+// drain receives the pending value of the channel, if any.
+func drain(ch <-chan struct{}) {
+	select {
+	case <-ch:
+	default:
+	}
+}
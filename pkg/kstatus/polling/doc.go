@@ -37,4 +37,27 @@
 //	for e := range eventsChan {
 //	   // Handle event
 //	}
+//
+// # Custom Status Readers
+//
+// The status of the resources of a CRD that does not follow the status
+// conventions can be computed by a custom StatusReader, registered for the
+// GroupKind of the CRD. The registered StatusReaders are preferred over the
+// generic conditions-based StatusReader:
+//
+//	statusreaders.Register(schema.GroupKind{Group: "db.example.com", Kind: "Database"},
+//	  func(mapper meta.RESTMapper) engine.StatusReader {
+//	    return NewDatabaseStatusReader(mapper)
+//	  })
+//
+// # Watching Resources
+//
+// By default, the resources are listed before every polling loop. For long
+// waits on large sets of resources, the clusterreader.WatchingClusterReader
+// keeps a local cache of the resources with informers instead, and triggers
+// a polling loop as soon as a resource changes:
+//
+//	poller := polling.NewStatusPoller(reader, mapper, polling.Options{
+//	  ClusterReaderFactory: clusterreader.NewWatchingClusterReaderFactory(dynamicClient),
+//	})
 package polling
@@ -0,0 +1,63 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package engine
+
+import (
+	"time"
+
+	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/event"
+)
+
+// sendUpdate sends the ResourceUpdateEvent for the resource status, or holds
+// it until the end of the coalescing window, if any. Only the latest event
+// of each resource is sent at the end of the window.
+func (r *statusPollerRunner) sendUpdate(resourceStatus *event.ResourceStatus) {
+	e := event.Event{
+		Type:     event.ResourceUpdateEvent,
+		Resource: resourceStatus,
+	}
+	if r.coalesceWindow <= 0 {
+		r.eventChannel <- e
+		return
+	}
+	id := resourceStatus.Identifier
+	if _, found := r.coalescedEvents[id]; !found {
+		r.coalescedOrder = append(r.coalescedOrder, id)
+	}
+	r.coalescedEvents[id] = e
+	if r.coalesceTimer == nil {
+		r.coalesceTimer = time.NewTimer(r.coalesceWindow)
+	}
+}
+
+// coalesced returns the channel that receives at the end of the coalescing
+// window, or nil if no event is held.
+func (r *statusPollerRunner) coalesced() <-chan time.Time {
+	if r.coalesceTimer == nil {
+		return nil
+	}
+	return r.coalesceTimer.C
+}
+
+// flushUpdates sends the events held in the coalescing window, in the order
+// the resources were first updated in the window.
+func (r *statusPollerRunner) flushUpdates() {
+	if r.coalesceTimer != nil {
+		r.coalesceTimer.Stop()
+		r.coalesceTimer = nil
+	}
+	for _, id := range r.coalescedOrder {
+		if e, found := r.coalescedEvents[id]; found {
+			delete(r.coalescedEvents, id)
+			r.eventChannel <- e
+		}
+	}
+	r.coalescedOrder = nil
+}
+
+// statusChanged returns true if the status or the message of the resource
+// differs from the previous status.
+func statusChanged(rs, previous *event.ResourceStatus) bool {
+	return rs.Status != previous.Status || rs.Message != previous.Message
+}
@@ -0,0 +1,49 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package engine
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/klog/v2"
+)
+
+// rediscoveryInterval is how often the RESTMapper is reset while the
+// GroupKind of any polled resource is unknown.
+var rediscoveryInterval = 30 * time.Second
+
+// IsDiscoveryError returns true if the error means that the RESTMapper could
+// not map a GroupKind, either because it is not served, e.g. because its CRD
+// is not installed yet, or because the discovery of its group failed. These
+// errors only affect the resources of the GroupKind, and may go away once the
+// RESTMapper is reset.
+func IsDiscoveryError(err error) bool {
+	return meta.IsNoMatchError(err) || discovery.IsGroupDiscoveryFailedError(err)
+}
+
+// rediscover resets the RESTMapper if the GroupKind of any polled resource
+// cannot be mapped, so that the resources are polled once their CRD is
+// installed. The RESTMapper is reset at most every rediscoveryInterval,
+// since every reset runs the discovery of all the groups again.
+func (r *statusPollerRunner) rediscover(now time.Time) {
+	if r.mapper == nil || now.Sub(r.lastDiscovery) < rediscoveryInterval {
+		return
+	}
+	seen := make(map[schema.GroupKind]bool)
+	for _, id := range r.identifiers {
+		if seen[id.GroupKind] {
+			continue
+		}
+		seen[id.GroupKind] = true
+		if _, err := r.mapper.RESTMapping(id.GroupKind); err != nil && IsDiscoveryError(err) {
+			klog.V(3).Infof("resetting the RESTMapper to discover %s: %v", id.GroupKind, err)
+			meta.MaybeResetRESTMapper(r.mapper)
+			r.lastDiscovery = now
+			return
+		}
+	}
+}
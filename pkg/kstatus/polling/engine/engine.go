@@ -11,7 +11,9 @@ import (
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/cli-utils/pkg/backoff"
 	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/event"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/metrics"
 	"sigs.k8s.io/cli-utils/pkg/object"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -35,6 +37,17 @@ type PollerEngine struct {
 	StatusReaders        []StatusReader
 	DefaultStatusReader  StatusReader
 	ClusterReaderFactory ClusterReaderFactory
+
+	// Metrics, if set, records the polling loops, the requests of the Reader
+	// and the errors of reading the status of resources.
+	Metrics *metrics.Metrics
+
+	// StatusReaderMiddleware, if set, decorates the StatusReaders and the
+	// DefaultStatusReader, e.g. to log or trace the reads, or to rewrite the
+	// messages. The first middleware is the outermost one. The StatusReaders
+	// used by other StatusReaders, e.g. to read generated resources, are not
+	// decorated.
+	StatusReaderMiddleware []StatusReaderMiddleware
 }
 
 // Poll will create a new statusPollerRunner that will poll all the resources provided and report their status
@@ -54,21 +67,51 @@ func (s *PollerEngine) Poll(ctx context.Context, identifiers object.ObjMetadataS
 			return
 		}
 
-		clusterReader, err := s.ClusterReaderFactory.New(s.Reader, s.Mapper, identifiers)
+		reader := s.Metrics.InstrumentReader(s.Reader)
+		clusterReader, err := s.ClusterReaderFactory.New(reader, s.Mapper, identifiers)
 		if err != nil {
 			handleError(eventChannel, fmt.Errorf("error creating new ClusterReader: %w", err))
 			return
 		}
 
+		statusReaders := make([]StatusReader, 0, len(s.StatusReaders))
+		for _, sr := range s.StatusReaders {
+			statusReaders = append(statusReaders, ChainStatusReader(sr, s.StatusReaderMiddleware...))
+		}
+		defaultStatusReader := ChainStatusReader(s.DefaultStatusReader, s.StatusReaderMiddleware...)
+
 		runner := &statusPollerRunner{
-			clusterReader:            clusterReader,
-			statusReaders:            s.StatusReaders,
-			defaultStatusReader:      s.DefaultStatusReader,
-			identifiers:              identifiers,
-			previousResourceStatuses: make(map[object.ObjMetadata]*event.ResourceStatus),
-			eventChannel:             eventChannel,
-			pollingInterval:          options.PollInterval,
+			clusterReader: clusterReader,
+			newClusterReader: func(identifiers object.ObjMetadataSet) (ClusterReader, error) {
+				return s.ClusterReaderFactory.New(reader, s.Mapper, identifiers)
+			},
+			validateIdentifiers:         s.validateIdentifiers,
+			mapper:                      s.Mapper,
+			lastDiscovery:               time.Now(),
+			statusReaders:               statusReaders,
+			defaultStatusReader:         defaultStatusReader,
+			identifiers:                 identifiers,
+			previousResourceStatuses:    make(map[object.ObjMetadata]*event.ResourceStatus),
+			eventChannel:                eventChannel,
+			pollingInterval:             options.pollInterval(len(identifiers)),
+			pollIntervalFunc:            options.pollInterval,
+			groupKindIntervals:          options.GroupKindPollIntervals,
+			unchangedBackoff:            options.UnchangedBackoff,
+			unchangedPollsBeforeBackoff: options.UnchangedPollsBeforeBackoff,
+			nextPoll:                    make(map[object.ObjMetadata]time.Time),
+			unchangedPolls:              make(map[object.ObjMetadata]int),
+			updates:                     options.Updates,
+			selectors:                   options.Selectors,
+			listSelector: func(ctx context.Context, selector Selector) (object.ObjMetadataSet, error) {
+				return listSelector(ctx, reader, s.Mapper, selector)
+			},
+			deduplicateByStatus: options.DeduplicateByStatus,
+			coalesceWindow:      options.CoalesceWindow,
+			coalescedEvents:     make(map[object.ObjMetadata]event.Event),
+			metrics:             s.Metrics,
+			resourceCounter:     s.Metrics.NewResourceCounter(),
 		}
+		defer runner.resourceCounter.Reset()
 		runner.Run(ctx)
 	}()
 
@@ -89,8 +132,9 @@ func (s *PollerEngine) validateIdentifiers(identifiers object.ObjMetadataSet) er
 		mapping, err := s.Mapper.RESTMapping(id.GroupKind)
 		if err != nil {
 			// If we can't find a match, just keep going. This can happen
-			// if CRDs and CRs are applied at the same time.
-			if meta.IsNoMatchError(err) {
+			// if CRDs and CRs are applied at the same time. The resources
+			// are reported as NotFound until the CRDs are discovered.
+			if IsDiscoveryError(err) {
 				continue
 			}
 			return err
@@ -112,6 +156,95 @@ type Options struct {
 	// PollInterval defines how often the PollerEngine should poll the cluster for the latest
 	// state of the resources.
 	PollInterval time.Duration
+
+	// PollIntervalFunc, if set, computes the interval between polls from the
+	// PollInterval and the number of polled resources. It can be used to poll
+	// large sets of resources less often, to limit the load on the apiserver.
+	PollIntervalFunc PollIntervalFunc
+
+	// GroupKindPollIntervals overrides the poll interval of the resources of
+	// these GroupKinds, e.g. to poll Pods and Jobs more often than the other
+	// resources, and Namespaces less often. The PollIntervalFunc does not
+	// apply to these intervals.
+	GroupKindPollIntervals map[schema.GroupKind]time.Duration
+
+	// UnchangedBackoff, if set, computes the poll interval of the resources
+	// whose status has not changed for UnchangedPollsBeforeBackoff polls. The
+	// attempt passed to the Strategy is the number of unchanged polls beyond
+	// the threshold. The interval is never shorter than the poll interval of
+	// the resource. The interval is reset when the status changes.
+	UnchangedBackoff backoff.Strategy
+
+	// UnchangedPollsBeforeBackoff is the number of polls without a status
+	// change before the UnchangedBackoff applies. Defaults to 1.
+	UnchangedPollsBeforeBackoff int
+
+	// Updates, if set, receives changes to the set of polled resources while
+	// polling, e.g. to poll the objects applied in later phases with the same
+	// poller, or to stop polling the objects that are no longer waited on.
+	// The channel is owned by the caller, and can be closed at any time.
+	Updates <-chan IdentifierUpdate
+
+	// Selectors, if set, adds the resources they select to the polled
+	// resources, before every polling loop. Selected resources are polled
+	// until the poller is cancelled, even if they are deleted, or no longer
+	// selected, so that their deletion is reported.
+	Selectors []Selector
+
+	// DeduplicateByStatus, if true, only sends an event for a resource when
+	// its status or message changes. By default, an event is also sent when
+	// the generation, the error or the generated resources of the resource
+	// change.
+	DeduplicateByStatus bool
+
+	// CoalesceWindow, if set, holds the events of the resources for up to
+	// this duration, and only sends the latest event of each resource at the
+	// end of the window, so that consumers are not flooded when many
+	// resources change at once. Events held when the poller is cancelled are
+	// not sent.
+	CoalesceWindow time.Duration
+}
+
+// IdentifierUpdate changes the set of resources polled by a running poller.
+type IdentifierUpdate struct {
+	// Add are the resources to start polling. Resources that are already
+	// polled are ignored.
+	Add object.ObjMetadataSet
+
+	// Remove are the resources to stop polling. No events are sent for
+	// these resources after the update has been received.
+	Remove object.ObjMetadataSet
+}
+
+// pollInterval returns the interval between polls of the given number of
+// resources.
+func (o Options) pollInterval(resources int) time.Duration {
+	if o.PollIntervalFunc == nil {
+		return o.PollInterval
+	}
+	return o.PollIntervalFunc(o.PollInterval, resources)
+}
+
+// PollIntervalFunc computes the interval between polls from the base
+// interval and the number of polled resources.
+type PollIntervalFunc func(interval time.Duration, resources int) time.Duration
+
+// ScaledPollInterval returns a PollIntervalFunc that stretches the interval
+// linearly with the number of resources, so that about resourcesPerInterval
+// resources are polled per base interval, whatever the number of resources.
+// The interval is never shorter than the base interval, nor longer than
+// maxInterval, unless maxInterval is zero.
+func ScaledPollInterval(resourcesPerInterval int, maxInterval time.Duration) PollIntervalFunc {
+	return func(interval time.Duration, resources int) time.Duration {
+		if resourcesPerInterval < 1 || resources <= resourcesPerInterval {
+			return interval
+		}
+		scaled := time.Duration(float64(interval) * float64(resources) / float64(resourcesPerInterval))
+		if maxInterval > 0 && scaled > maxInterval {
+			return maxInterval
+		}
+		return scaled
+	}
 }
 
 // statusPollerRunner is responsible for polling of a set of resources. Each call to Poll will create
@@ -126,6 +259,23 @@ type statusPollerRunner struct {
 	// to make call directly to the cluster or use caching to reduce the number of calls to the cluster.
 	clusterReader ClusterReader
 
+	// newClusterReader creates a new clusterReader when resources are added
+	// to the polled resources, since the clusterReader might only read the
+	// resources it was created for.
+	newClusterReader func(identifiers object.ObjMetadataSet) (ClusterReader, error)
+
+	// validateIdentifiers validates the resources added to the polled
+	// resources.
+	validateIdentifiers func(identifiers object.ObjMetadataSet) error
+
+	// mapper is reset to discover the GroupKinds of the polled resources
+	// that are unknown.
+	mapper meta.RESTMapper
+
+	// lastDiscovery is when the mapper was last reset, or when polling
+	// started.
+	lastDiscovery time.Time
+
 	// statusReaders contains the resource specific statusReaders. These will contain logic for how to
 	// compute status for specific GroupKinds. These will use an ClusterReader to fetch
 	// status of a resource and any generated resources.
@@ -151,37 +301,193 @@ type statusPollerRunner struct {
 	// pollingInterval determines how often we should poll the cluster for
 	// the latest state of resources.
 	pollingInterval time.Duration
+
+	// pollIntervalFunc computes the pollingInterval from the number of
+	// polled resources, when resources are added or removed.
+	pollIntervalFunc func(resources int) time.Duration
+
+	// groupKindIntervals overrides the pollingInterval of the resources of
+	// these GroupKinds.
+	groupKindIntervals map[schema.GroupKind]time.Duration
+
+	// unchangedBackoff computes the poll interval of the resources whose
+	// status has not changed for unchangedPollsBeforeBackoff polls.
+	unchangedBackoff            backoff.Strategy
+	unchangedPollsBeforeBackoff int
+
+	// nextPoll keeps track of when each resource should be polled next.
+	// Resources that were never polled are due.
+	nextPoll map[object.ObjMetadata]time.Time
+
+	// unchangedPolls keeps track of the number of consecutive polls of each
+	// resource that did not change its status.
+	unchangedPolls map[object.ObjMetadata]int
+
+	// updates receives the changes to the set of polled resources.
+	updates <-chan IdentifierUpdate
+
+	// selectors select resources to add to the polled resources.
+	selectors []Selector
+
+	// listSelector lists the resources selected by a selector.
+	listSelector func(ctx context.Context, selector Selector) (object.ObjMetadataSet, error)
+
+	// deduplicateByStatus only sends events when the status or the message
+	// of a resource changes.
+	deduplicateByStatus bool
+
+	// coalesceWindow is how long events are held before they are sent. Only
+	// the latest event of each resource is sent.
+	coalesceWindow time.Duration
+
+	// coalescedEvents are the latest events of the resources held in the
+	// coalescing window, and coalescedOrder the order in which the
+	// resources were first updated in the window.
+	coalescedEvents map[object.ObjMetadata]event.Event
+	coalescedOrder  []object.ObjMetadata
+
+	// coalesceTimer fires at the end of the coalescing window. It is nil if
+	// no event is held.
+	coalesceTimer *time.Timer
+
+	// metrics records the polling loops. It may be nil.
+	metrics *metrics.Metrics
+
+	// resourceCounter records the number of polled resources with each
+	// status. It is nil if metrics is nil.
+	resourceCounter *metrics.ResourceCounter
 }
 
 // Run starts the polling loop of the statusReaders.
 func (r *statusPollerRunner) Run(ctx context.Context) {
+	if _, err := r.selectIdentifiers(ctx); err != nil {
+		r.handleSyncAndPollErr(err)
+		return
+	}
+
 	// Sets up ticker that will trigger the regular polling loop at a regular interval.
-	ticker := time.NewTicker(r.pollingInterval)
+	// Each loop only polls the resources that are due.
+	ticker := time.NewTicker(r.tickInterval())
 	defer func() {
 		ticker.Stop()
+		if r.coalesceTimer != nil {
+			r.coalesceTimer.Stop()
+		}
 	}()
 
-	err := r.syncAndPoll(ctx)
+	// Watching ClusterReaders also trigger a polling loop when a resource
+	// changes. The channel is nil otherwise, and never receives.
+	changed := r.changed()
+	updates := r.updates
+
+	err := r.syncAndPoll(ctx, true)
 	if err != nil {
 		r.handleSyncAndPollErr(err)
 		return
 	}
 
 	for {
+		// A change may affect any resource, so all resources are polled.
+		all := false
 		select {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			// First sync and then compute status for all resources.
-			err := r.syncAndPoll(ctx)
+		case <-changed:
+			all = true
+		case <-r.coalesced():
+			r.flushUpdates()
+			continue
+		case update, ok := <-updates:
+			if !ok {
+				updates = nil
+				continue
+			}
+			// The added resources are due, so they are polled right away.
+			err := r.updateIdentifiers(update)
 			if err != nil {
 				r.handleSyncAndPollErr(err)
 				return
 			}
+			ticker.Reset(r.tickInterval())
+			changed = r.changed()
+		}
+		// The newly selected resources are due, so they are polled right away.
+		selected, err := r.selectIdentifiers(ctx)
+		if err != nil {
+			r.handleSyncAndPollErr(err)
+			return
+		}
+		if selected {
+			ticker.Reset(r.tickInterval())
+			changed = r.changed()
+		}
+		// First sync and then compute status for the due resources.
+		err = r.syncAndPoll(ctx, all)
+		if err != nil {
+			r.handleSyncAndPollErr(err)
+			return
 		}
 	}
 }
 
+// changed returns the channel of the ChangeNotifier, if the clusterReader is
+// one, or nil otherwise.
+func (r *statusPollerRunner) changed() <-chan struct{} {
+	if notifier, ok := r.clusterReader.(ChangeNotifier); ok {
+		return notifier.Changed()
+	}
+	return nil
+}
+
+// updateIdentifiers adds and removes resources to and from the polled
+// resources. A new clusterReader is created if resources are added.
+func (r *statusPollerRunner) updateIdentifiers(update IdentifierUpdate) error {
+	for _, id := range update.Remove {
+		delete(r.previousResourceStatuses, id)
+		delete(r.nextPoll, id)
+		delete(r.unchangedPolls, id)
+		delete(r.coalescedEvents, id)
+	}
+	identifiers := r.identifiers.Diff(update.Remove)
+	added := update.Add.Diff(identifiers)
+	if len(added) > 0 {
+		if err := r.validateIdentifiers(added); err != nil {
+			return err
+		}
+		identifiers = identifiers.Union(added)
+		clusterReader, err := r.newClusterReader(identifiers)
+		if err != nil {
+			return fmt.Errorf("error creating new ClusterReader: %w", err)
+		}
+		r.clusterReader = clusterReader
+	}
+	r.identifiers = identifiers
+	r.pollingInterval = r.pollIntervalFunc(len(identifiers))
+	return nil
+}
+
+// selectIdentifiers adds the resources selected by the selectors to the
+// polled resources. Returns true if resources were added.
+func (r *statusPollerRunner) selectIdentifiers(ctx context.Context) (bool, error) {
+	if len(r.selectors) == 0 {
+		return false, nil
+	}
+	var selected object.ObjMetadataSet
+	for _, selector := range r.selectors {
+		ids, err := r.listSelector(ctx, selector)
+		if err != nil {
+			return false, fmt.Errorf("error listing resources of %s: %w", selector, err)
+		}
+		selected = selected.Union(ids)
+	}
+	added := selected.Diff(r.identifiers)
+	if len(added) == 0 {
+		return false, nil
+	}
+	return true, r.updateIdentifiers(IdentifierUpdate{Add: added})
+}
+
 // handleSyncAndPollErr decides what to do if we encounter an error while
 // fetching resources to compute status. Errors are usually returned
 // as an ErrorEvent, but we handle context cancellation or deadline exceeded
@@ -191,13 +497,26 @@ func (r *statusPollerRunner) handleSyncAndPollErr(err error) {
 	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
 		return
 	}
+	// The held events are sent before the error, to preserve the order of
+	// the events.
+	r.flushUpdates()
 	r.eventChannel <- event.Event{
 		Type:  event.ErrorEvent,
 		Error: err,
 	}
 }
 
-func (r *statusPollerRunner) syncAndPoll(ctx context.Context) error {
+func (r *statusPollerRunner) syncAndPoll(ctx context.Context, all bool) error {
+	ids := r.identifiers
+	if !all {
+		ids = r.dueIdentifiers(time.Now())
+		// Skip the sync if no resource is due.
+		if len(ids) == 0 && len(r.identifiers) > 0 {
+			return nil
+		}
+	}
+	start := time.Now()
+	r.rediscover(start)
 	// First trigger a sync of the ClusterReader. This may or may not actually
 	// result in calls to the cluster, depending on the implementation.
 	// If this call fails, there is no clean way to recover, so we just return an ErrorEvent
@@ -206,16 +525,85 @@ func (r *statusPollerRunner) syncAndPoll(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	// Poll all resources and compute status. If the polling of resources has completed (based
+	// Poll the resources and compute status. If the polling of resources has completed (based
 	// on information from the StatusAggregator and the value of pollUntilCancelled), we send
 	// a CompletedEvent and return.
-	return r.pollStatusForAllResources(ctx)
+	err = r.pollStatusForResources(ctx, ids)
+	if err != nil {
+		return err
+	}
+	r.observePoll(time.Since(start))
+	return nil
 }
 
-// pollStatusForAllResources iterates over all the resources in the set and delegates
-// to the appropriate engine to compute the status.
-func (r *statusPollerRunner) pollStatusForAllResources(ctx context.Context) error {
+// observePoll records the polling loop in the metrics, if any.
+func (r *statusPollerRunner) observePoll(d time.Duration) {
+	if r.metrics == nil {
+		return
+	}
+	r.metrics.ObservePoll(d)
+	resourceStatuses := make([]*event.ResourceStatus, 0, len(r.previousResourceStatuses))
+	for _, rs := range r.previousResourceStatuses {
+		resourceStatuses = append(resourceStatuses, rs)
+	}
+	r.resourceCounter.Set(resourceStatuses, len(r.identifiers))
+}
+
+// tickInterval returns the interval of the polling loop: the shortest poll
+// interval of the resources.
+func (r *statusPollerRunner) tickInterval() time.Duration {
+	interval := r.pollingInterval
+	for _, gkInterval := range r.groupKindIntervals {
+		if gkInterval > 0 && gkInterval < interval {
+			interval = gkInterval
+		}
+	}
+	return interval
+}
+
+// dueIdentifiers returns the resources that are due for polling. Resources
+// due within half a tick are included, so that the jitter of the ticker does
+// not delay them by a whole tick.
+func (r *statusPollerRunner) dueIdentifiers(now time.Time) object.ObjMetadataSet {
+	cutoff := now.Add(r.tickInterval() / 2)
+	var ids object.ObjMetadataSet
 	for _, id := range r.identifiers {
+		if !r.nextPoll[id].After(cutoff) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// interval returns the poll interval of the resource: the interval of its
+// GroupKind, stretched by the UnchangedBackoff if its status has not changed
+// for a while.
+func (r *statusPollerRunner) interval(id object.ObjMetadata) time.Duration {
+	interval := r.pollingInterval
+	if gkInterval, found := r.groupKindIntervals[id.GroupKind]; found && gkInterval > 0 {
+		interval = gkInterval
+	}
+	if r.unchangedBackoff == nil {
+		return interval
+	}
+	threshold := r.unchangedPollsBeforeBackoff
+	if threshold < 1 {
+		threshold = 1
+	}
+	attempt := r.unchangedPolls[id] - threshold + 1
+	if attempt < 1 {
+		return interval
+	}
+	if delay := r.unchangedBackoff.Delay(attempt, interval); delay > interval {
+		return delay
+	}
+	return interval
+}
+
+// pollStatusForResources iterates over the resources and delegates
+// to the appropriate engine to compute the status.
+func (r *statusPollerRunner) pollStatusForResources(ctx context.Context, ids object.ObjMetadataSet) error {
+	for _, id := range ids {
 		// Check if the context has been cancelled on every iteration.
 		select {
 		case <-ctx.Done():
@@ -228,13 +616,15 @@ func (r *statusPollerRunner) pollStatusForAllResources(ctx context.Context) erro
 		if err != nil {
 			return err
 		}
+		r.metrics.ObserveResourceStatus(resourceStatus)
 		if r.isUpdatedResourceStatus(resourceStatus) {
 			r.previousResourceStatuses[id] = resourceStatus
-			r.eventChannel <- event.Event{
-				Type:     event.ResourceUpdateEvent,
-				Resource: resourceStatus,
-			}
+			r.unchangedPolls[id] = 0
+			r.sendUpdate(resourceStatus)
+		} else {
+			r.unchangedPolls[id]++
 		}
+		r.nextPoll[id] = time.Now().Add(r.interval(id))
 	}
 	return nil
 }
@@ -253,5 +643,8 @@ func (r *statusPollerRunner) isUpdatedResourceStatus(resourceStatus *event.Resou
 	if !found {
 		return true
 	}
+	if r.deduplicateByStatus {
+		return statusChanged(resourceStatus, oldResourceStatus)
+	}
 	return !event.ResourceStatusEqual(resourceStatus, oldResourceStatus)
 }
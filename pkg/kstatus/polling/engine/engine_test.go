@@ -5,22 +5,33 @@ package engine
 
 import (
 	"context"
+	"fmt"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/kubectl/pkg/scheme"
+	"sigs.k8s.io/cli-utils/pkg/backoff"
 	fakecr "sigs.k8s.io/cli-utils/pkg/kstatus/polling/clusterreader/fake"
 	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/event"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/metrics"
 	"sigs.k8s.io/cli-utils/pkg/kstatus/status"
 	"sigs.k8s.io/cli-utils/pkg/object"
 	fakemapper "sigs.k8s.io/cli-utils/pkg/testutil"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestStatusPollerRunner(t *testing.T) {
@@ -136,6 +147,497 @@ func TestStatusPollerRunner(t *testing.T) {
 	}
 }
 
+func TestStatusPollerRunnerChangeNotifier(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	identifiers := object.ObjMetadataSet{
+		{
+			GroupKind: schema.GroupKind{
+				Group: "apps",
+				Kind:  "Deployment",
+			},
+			Name:      "foo",
+			Namespace: "default",
+		},
+	}
+	clusterReader := &notifyingClusterReader{
+		NoopClusterReader: fakecr.NewNoopClusterReader(),
+		changed:           make(chan struct{}),
+	}
+	engine := PollerEngine{
+		Mapper: fakemapper.NewFakeRESTMapper(appsv1.SchemeGroupVersion.WithKind("Deployment")),
+		DefaultStatusReader: &fakeStatusReader{
+			resourceStatuses: map[schema.GroupKind][]status.Status{
+				schema.GroupKind{Group: "apps", Kind: "Deployment"}: { //nolint:gofmt
+					status.InProgressStatus,
+					status.CurrentStatus,
+				},
+			},
+			resourceStatusCount: make(map[schema.GroupKind]int),
+		},
+		ClusterReaderFactory: ClusterReaderFactoryFunc(func(client.Reader, meta.RESTMapper, object.ObjMetadataSet) (ClusterReader, error) {
+			return clusterReader, nil
+		}),
+	}
+
+	// The poll interval is too long for the second poll to be triggered by
+	// the ticker.
+	eventChannel := engine.Poll(ctx, identifiers, Options{PollInterval: time.Hour})
+
+	e := <-eventChannel
+	assert.Equal(t, status.InProgressStatus, e.Resource.Status)
+	clusterReader.changed <- struct{}{}
+	e = <-eventChannel
+	assert.Equal(t, status.CurrentStatus, e.Resource.Status)
+}
+
+func TestStatusPollerRunnerUpdates(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	deploymentID := object.ObjMetadata{
+		GroupKind: schema.GroupKind{Group: "apps", Kind: "Deployment"},
+		Name:      "foo",
+		Namespace: "default",
+	}
+	serviceID := object.ObjMetadata{
+		GroupKind: schema.GroupKind{Group: "", Kind: "Service"},
+		Name:      "bar",
+		Namespace: "default",
+	}
+	var clusterReaderIdentifiers []object.ObjMetadataSet
+	engine := PollerEngine{
+		Mapper: fakemapper.NewFakeRESTMapper(
+			appsv1.SchemeGroupVersion.WithKind("Deployment"),
+			v1.SchemeGroupVersion.WithKind("Service"),
+		),
+		DefaultStatusReader: &fakeStatusReader{
+			resourceStatuses: map[schema.GroupKind][]status.Status{
+				deploymentID.GroupKind: {
+					status.InProgressStatus,
+					status.CurrentStatus,
+				},
+				serviceID.GroupKind: {
+					status.CurrentStatus,
+				},
+			},
+			resourceStatusCount: make(map[schema.GroupKind]int),
+		},
+		ClusterReaderFactory: ClusterReaderFactoryFunc(func(_ client.Reader, _ meta.RESTMapper, ids object.ObjMetadataSet) (ClusterReader, error) {
+			clusterReaderIdentifiers = append(clusterReaderIdentifiers, ids)
+			return fakecr.NewNoopClusterReader(), nil
+		}),
+	}
+
+	updates := make(chan IdentifierUpdate)
+	// The poll interval is too long for the resources to be polled again by
+	// the ticker.
+	eventChannel := engine.Poll(ctx, object.ObjMetadataSet{deploymentID}, Options{
+		PollInterval: time.Hour,
+		Updates:      updates,
+	})
+
+	e := <-eventChannel
+	assert.Equal(t, deploymentID, e.Resource.Identifier)
+
+	// The added resource is polled right away, with a new ClusterReader.
+	updates <- IdentifierUpdate{Add: object.ObjMetadataSet{serviceID}}
+	e = <-eventChannel
+	assert.Equal(t, serviceID, e.Resource.Identifier)
+	assert.Equal(t, []object.ObjMetadataSet{
+		{deploymentID},
+		{deploymentID, serviceID},
+	}, clusterReaderIdentifiers)
+
+	// Adding the same resource again, or removing a resource, does not
+	// create a new ClusterReader.
+	updates <- IdentifierUpdate{Add: object.ObjMetadataSet{serviceID}, Remove: object.ObjMetadataSet{deploymentID}}
+	// The channel can be closed while polling.
+	close(updates)
+	cancel()
+	for range eventChannel {
+		t.Error("unexpected event after the deployment was removed")
+	}
+	assert.Len(t, clusterReaderIdentifiers, 2)
+}
+
+func TestStatusPollerRunnerSelectors(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	deployment := func(name string, labels map[string]string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    labels,
+			},
+		}
+	}
+	deploymentGK := schema.GroupKind{Group: "apps", Kind: "Deployment"}
+	reader := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		deployment("foo", map[string]string{"app": "test"}),
+		deployment("other", map[string]string{"app": "other"}),
+	).Build()
+	engine := PollerEngine{
+		Reader: reader,
+		Mapper: fakemapper.NewFakeRESTMapper(appsv1.SchemeGroupVersion.WithKind("Deployment")),
+		DefaultStatusReader: &fakeStatusReader{
+			resourceStatuses: map[schema.GroupKind][]status.Status{
+				deploymentGK: {status.CurrentStatus},
+			},
+			resourceStatusCount: make(map[schema.GroupKind]int),
+		},
+		ClusterReaderFactory: ClusterReaderFactoryFunc(func(client.Reader, meta.RESTMapper, object.ObjMetadataSet) (ClusterReader, error) {
+			return fakecr.NewNoopClusterReader(), nil
+		}),
+	}
+
+	eventChannel := engine.Poll(ctx, nil, Options{
+		PollInterval: 10 * time.Millisecond,
+		Selectors: []Selector{
+			{
+				GroupKind:     deploymentGK,
+				Namespace:     "default",
+				LabelSelector: labels.SelectorFromSet(labels.Set{"app": "test"}),
+			},
+			{
+				// Resources of unknown kinds are not selected.
+				GroupKind: schema.GroupKind{Group: "example.com", Kind: "Unknown"},
+			},
+		},
+	})
+
+	e := <-eventChannel
+	require.Equal(t, event.ResourceUpdateEvent, e.Type, "unexpected event: %v", e)
+	assert.Equal(t, object.ObjMetadata{GroupKind: deploymentGK, Namespace: "default", Name: "foo"}, e.Resource.Identifier)
+
+	// Resources created while polling are selected.
+	require.NoError(t, reader.Create(ctx, deployment("bar", map[string]string{"app": "test"})))
+	e = <-eventChannel
+	require.Equal(t, event.ResourceUpdateEvent, e.Type, "unexpected event: %v", e)
+	assert.Equal(t, object.ObjMetadata{GroupKind: deploymentGK, Namespace: "default", Name: "bar"}, e.Resource.Identifier)
+
+	cancel()
+	for e := range eventChannel {
+		t.Errorf("unexpected event: %v", e)
+	}
+}
+
+func TestStatusPollerRunnerRediscovery(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	defer func(interval time.Duration) {
+		rediscoveryInterval = interval
+	}(rediscoveryInterval)
+	rediscoveryInterval = 10 * time.Millisecond
+
+	customGVK := schema.GroupVersionKind{Group: "custom.io", Version: "v1", Kind: "Custom"}
+	identifier := object.ObjMetadata{
+		GroupKind: customGVK.GroupKind(),
+		Name:      "foo",
+		Namespace: "default",
+	}
+	// The CRD is installed when the RESTMapper is reset.
+	mapper := &resettableRESTMapper{
+		RESTMapper: fakemapper.NewFakeRESTMapper(),
+		reset:      fakemapper.NewFakeRESTMapper(customGVK),
+	}
+	engine := PollerEngine{
+		Mapper:              mapper,
+		DefaultStatusReader: &mappingStatusReader{mapper: mapper},
+		ClusterReaderFactory: ClusterReaderFactoryFunc(func(client.Reader, meta.RESTMapper, object.ObjMetadataSet) (ClusterReader, error) {
+			return fakecr.NewNoopClusterReader(), nil
+		}),
+	}
+
+	eventChannel := engine.Poll(ctx, object.ObjMetadataSet{identifier}, Options{
+		PollInterval: 10 * time.Millisecond,
+	})
+
+	e := <-eventChannel
+	require.Equal(t, event.ResourceUpdateEvent, e.Type, "unexpected event: %v", e)
+	assert.Equal(t, status.NotFoundStatus, e.Resource.Status)
+
+	// Polling goes on, and the resource is found once discovered.
+	e = <-eventChannel
+	require.Equal(t, event.ResourceUpdateEvent, e.Type, "unexpected event: %v", e)
+	assert.Equal(t, status.CurrentStatus, e.Resource.Status)
+
+	cancel()
+	for range eventChannel {
+		// Wait for the runner to stop, before reading the resets.
+	}
+	assert.Equal(t, 1, mapper.resets)
+}
+
+// resettableRESTMapper replaces the RESTMapper when it is reset.
+type resettableRESTMapper struct {
+	meta.RESTMapper
+	reset  meta.RESTMapper
+	resets int
+}
+
+func (m *resettableRESTMapper) Reset() {
+	m.RESTMapper = m.reset
+	m.resets++
+}
+
+// mappingStatusReader reports the resources of unknown GroupKinds as
+// NotFound, and the others as Current.
+type mappingStatusReader struct {
+	mapper meta.RESTMapper
+}
+
+func (m *mappingStatusReader) Supports(schema.GroupKind) bool {
+	return true
+}
+
+func (m *mappingStatusReader) ReadStatus(_ context.Context, _ ClusterReader, identifier object.ObjMetadata) (*event.ResourceStatus, error) {
+	if _, err := m.mapper.RESTMapping(identifier.GroupKind); err != nil {
+		if !IsDiscoveryError(err) {
+			return nil, err
+		}
+		return &event.ResourceStatus{
+			Identifier: identifier,
+			Status:     status.NotFoundStatus,
+		}, nil
+	}
+	return &event.ResourceStatus{
+		Identifier: identifier,
+		Status:     status.CurrentStatus,
+	}, nil
+}
+
+func (m *mappingStatusReader) ReadStatusForObject(context.Context, ClusterReader, *unstructured.Unstructured) (*event.ResourceStatus, error) {
+	return nil, nil
+}
+
+func TestStatusPollerRunnerDeduplicateByStatus(t *testing.T) {
+	identifier := object.ObjMetadata{
+		GroupKind: schema.GroupKind{Group: "apps", Kind: "Deployment"},
+		Name:      "foo",
+		Namespace: "default",
+	}
+	resourceStatuses := []*event.ResourceStatus{
+		{Identifier: identifier, Status: status.InProgressStatus, Resource: generation(1)},
+		{Identifier: identifier, Status: status.InProgressStatus, Resource: generation(2)},
+		{Identifier: identifier, Status: status.CurrentStatus, Resource: generation(2)},
+		{Identifier: identifier, Status: status.CurrentStatus, Resource: generation(3)},
+	}
+
+	testCases := map[string]struct {
+		deduplicateByStatus bool
+		expectedGenerations []int64
+	}{
+		"without de-duplication": {
+			expectedGenerations: []int64{1, 2, 2, 3},
+		},
+		"with de-duplication": {
+			deduplicateByStatus: true,
+			expectedGenerations: []int64{1, 2},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			statusReader := &sequenceStatusReader{resourceStatuses: resourceStatuses}
+			engine := PollerEngine{
+				Mapper:              fakemapper.NewFakeRESTMapper(appsv1.SchemeGroupVersion.WithKind("Deployment")),
+				DefaultStatusReader: statusReader,
+				ClusterReaderFactory: ClusterReaderFactoryFunc(func(client.Reader, meta.RESTMapper, object.ObjMetadataSet) (ClusterReader, error) {
+					return fakecr.NewNoopClusterReader(), nil
+				}),
+			}
+
+			eventChannel := engine.Poll(ctx, object.ObjMetadataSet{identifier}, Options{
+				PollInterval:        10 * time.Millisecond,
+				DeduplicateByStatus: tc.deduplicateByStatus,
+			})
+
+			var generations []int64
+			for e := range eventChannel {
+				require.Equal(t, event.ResourceUpdateEvent, e.Type, "unexpected event: %v", e)
+				generations = append(generations, e.Resource.Resource.GetGeneration())
+				if len(generations) == len(tc.expectedGenerations) {
+					// Wait for the last status to be polled again, to
+					// make sure that no other event is sent.
+					for statusReader.reads() <= len(resourceStatuses) {
+						time.Sleep(10 * time.Millisecond)
+					}
+					cancel()
+				}
+			}
+			assert.Equal(t, tc.expectedGenerations, generations)
+		})
+	}
+}
+
+func TestStatusPollerRunnerCoalesceWindow(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	identifier := object.ObjMetadata{
+		GroupKind: schema.GroupKind{Group: "apps", Kind: "Deployment"},
+		Name:      "foo",
+		Namespace: "default",
+	}
+	statusReader := &sequenceStatusReader{
+		resourceStatuses: []*event.ResourceStatus{
+			{Identifier: identifier, Status: status.InProgressStatus, Message: "0 of 2 replicas ready"},
+			{Identifier: identifier, Status: status.InProgressStatus, Message: "1 of 2 replicas ready"},
+			{Identifier: identifier, Status: status.CurrentStatus, Message: "2 of 2 replicas ready"},
+		},
+	}
+	engine := PollerEngine{
+		Mapper:              fakemapper.NewFakeRESTMapper(appsv1.SchemeGroupVersion.WithKind("Deployment")),
+		DefaultStatusReader: statusReader,
+		ClusterReaderFactory: ClusterReaderFactoryFunc(func(client.Reader, meta.RESTMapper, object.ObjMetadataSet) (ClusterReader, error) {
+			return fakecr.NewNoopClusterReader(), nil
+		}),
+	}
+
+	eventChannel := engine.Poll(ctx, object.ObjMetadataSet{identifier}, Options{
+		PollInterval:   10 * time.Millisecond,
+		CoalesceWindow: 500 * time.Millisecond,
+	})
+
+	// All the statuses are read within the window, so only the latest one
+	// is sent.
+	e := <-eventChannel
+	require.Equal(t, event.ResourceUpdateEvent, e.Type, "unexpected event: %v", e)
+	assert.Equal(t, status.CurrentStatus, e.Resource.Status)
+	assert.Equal(t, "2 of 2 replicas ready", e.Resource.Message)
+	assert.Greater(t, statusReader.reads(), 3)
+
+	select {
+	case e := <-eventChannel:
+		t.Errorf("unexpected event: %v", e)
+	case <-time.After(time.Second):
+	}
+}
+
+// generation returns an object with the generation.
+func generation(generation int64) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetGeneration(generation)
+	return u
+}
+
+// sequenceStatusReader returns the resource statuses in sequence, and then
+// the last one.
+type sequenceStatusReader struct {
+	mu               sync.Mutex
+	resourceStatuses []*event.ResourceStatus
+	count            int
+}
+
+func (s *sequenceStatusReader) reads() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.count
+}
+
+func (s *sequenceStatusReader) Supports(schema.GroupKind) bool {
+	return true
+}
+
+func (s *sequenceStatusReader) ReadStatus(context.Context, ClusterReader, object.ObjMetadata) (*event.ResourceStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.count
+	if i >= len(s.resourceStatuses) {
+		i = len(s.resourceStatuses) - 1
+	}
+	s.count++
+	return s.resourceStatuses[i], nil
+}
+
+func (s *sequenceStatusReader) ReadStatusForObject(context.Context, ClusterReader, *unstructured.Unstructured) (*event.ResourceStatus, error) {
+	return nil, nil
+}
+
+func TestStatusPollerRunnerMetrics(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	identifiers := object.ObjMetadataSet{
+		{
+			GroupKind: schema.GroupKind{Group: "apps", Kind: "Deployment"},
+			Name:      "foo",
+			Namespace: "default",
+		},
+	}
+	clusterReader := &notifyingClusterReader{
+		NoopClusterReader: fakecr.NewNoopClusterReader(),
+		changed:           make(chan struct{}),
+	}
+	reg := prometheus.NewRegistry()
+	m, err := metrics.New(reg)
+	require.NoError(t, err)
+	engine := PollerEngine{
+		Mapper: fakemapper.NewFakeRESTMapper(appsv1.SchemeGroupVersion.WithKind("Deployment")),
+		DefaultStatusReader: &fakeStatusReader{
+			resourceStatuses: map[schema.GroupKind][]status.Status{
+				identifiers[0].GroupKind: {
+					status.InProgressStatus,
+					status.CurrentStatus,
+				},
+			},
+			resourceStatusCount: make(map[schema.GroupKind]int),
+		},
+		ClusterReaderFactory: ClusterReaderFactoryFunc(func(client.Reader, meta.RESTMapper, object.ObjMetadataSet) (ClusterReader, error) {
+			return clusterReader, nil
+		}),
+		Metrics: m,
+	}
+
+	eventChannel := engine.Poll(ctx, identifiers, Options{PollInterval: time.Hour})
+	<-eventChannel
+	clusterReader.changed <- struct{}{}
+	<-eventChannel
+
+	expected := `
+# HELP kstatus_poller_resources Number of polled resources with each status.
+# TYPE kstatus_poller_resources gauge
+kstatus_poller_resources{status="Current"} %d
+kstatus_poller_resources{status="Failed"} 0
+kstatus_poller_resources{status="InProgress"} 0
+kstatus_poller_resources{status="NotFound"} 0
+kstatus_poller_resources{status="Suspended"} 0
+kstatus_poller_resources{status="Terminating"} 0
+kstatus_poller_resources{status="Unknown"} 0
+`
+	// The second poll is recorded after its event is sent.
+	require.Eventually(t, func() bool {
+		return promtestutil.GatherAndCompare(reg, strings.NewReader(fmt.Sprintf(expected, 1)), "kstatus_poller_resources") == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	for range eventChannel {
+		// Wait for the runner to stop.
+	}
+	// The resources are removed from the gauge when polling stops.
+	assert.NoError(t, promtestutil.GatherAndCompare(reg, strings.NewReader(fmt.Sprintf(expected, 0)), "kstatus_poller_resources"))
+	count, err := promtestutil.GatherAndCount(reg, "kstatus_poller_poll_duration_seconds")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+type notifyingClusterReader struct {
+	*fakecr.NoopClusterReader
+	changed chan struct{}
+}
+
+func (n *notifyingClusterReader) Changed() <-chan struct{} {
+	return n.changed
+}
+
 func TestNewStatusPollerRunnerCancellation(t *testing.T) {
 	identifiers := make(object.ObjMetadataSet, 0)
 
@@ -271,6 +773,138 @@ func TestNewStatusPollerRunnerIdentifierValidation(t *testing.T) {
 	}
 }
 
+func TestScaledPollInterval(t *testing.T) {
+	testCases := map[string]struct {
+		resourcesPerInterval int
+		maxInterval          time.Duration
+		resources            int
+		expected             time.Duration
+	}{
+		"fewer resources than per interval": {
+			resourcesPerInterval: 100,
+			resources:            10,
+			expected:             2 * time.Second,
+		},
+		"more resources than per interval": {
+			resourcesPerInterval: 100,
+			resources:            250,
+			expected:             5 * time.Second,
+		},
+		"capped by max interval": {
+			resourcesPerInterval: 100,
+			maxInterval:          time.Minute,
+			resources:            10000,
+			expected:             time.Minute,
+		},
+		"disabled": {
+			resources: 10000,
+			expected:  2 * time.Second,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			o := Options{
+				PollInterval:     2 * time.Second,
+				PollIntervalFunc: ScaledPollInterval(tc.resourcesPerInterval, tc.maxInterval),
+			}
+			assert.Equal(t, tc.expected, o.pollInterval(tc.resources))
+		})
+	}
+}
+
+func TestStatusPollerRunnerInterval(t *testing.T) {
+	deployment := object.ObjMetadata{
+		GroupKind: schema.GroupKind{Group: "apps", Kind: "Deployment"},
+		Name:      "foo",
+		Namespace: "default",
+	}
+	pod := object.ObjMetadata{
+		GroupKind: schema.GroupKind{Kind: "Pod"},
+		Name:      "foo-1",
+		Namespace: "default",
+	}
+	gkIntervals := map[schema.GroupKind]time.Duration{
+		pod.GroupKind: time.Second,
+	}
+	exponential := backoff.Exponential{Initial: 4 * time.Second, Max: 16 * time.Second, Factor: 2}
+
+	testCases := map[string]struct {
+		id              object.ObjMetadata
+		unchangedPolls  int
+		threshold       int
+		backoffStrategy backoff.Strategy
+		expected        time.Duration
+	}{
+		"default interval": {
+			id:       deployment,
+			expected: 2 * time.Second,
+		},
+		"GroupKind interval": {
+			id:       pod,
+			expected: time.Second,
+		},
+		"no backoff before the threshold": {
+			id:              deployment,
+			unchangedPolls:  2,
+			threshold:       3,
+			backoffStrategy: exponential,
+			expected:        2 * time.Second,
+		},
+		"backoff at the threshold": {
+			id:              deployment,
+			unchangedPolls:  3,
+			threshold:       3,
+			backoffStrategy: exponential,
+			expected:        4 * time.Second,
+		},
+		"backoff beyond the threshold": {
+			id:              deployment,
+			unchangedPolls:  5,
+			threshold:       3,
+			backoffStrategy: exponential,
+			expected:        16 * time.Second,
+		},
+		"backoff never shorter than the interval": {
+			id:              deployment,
+			unchangedPolls:  1,
+			backoffStrategy: backoff.Constant{Interval: time.Second},
+			expected:        2 * time.Second,
+		},
+	}
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			r := &statusPollerRunner{
+				pollingInterval:             2 * time.Second,
+				groupKindIntervals:          gkIntervals,
+				unchangedBackoff:            tc.backoffStrategy,
+				unchangedPollsBeforeBackoff: tc.threshold,
+				unchangedPolls:              map[object.ObjMetadata]int{tc.id: tc.unchangedPolls},
+			}
+			assert.Equal(t, tc.expected, r.interval(tc.id))
+		})
+	}
+}
+
+func TestStatusPollerRunnerDueIdentifiers(t *testing.T) {
+	now := time.Now()
+	polled := object.ObjMetadata{GroupKind: schema.GroupKind{Kind: "Pod"}, Name: "polled", Namespace: "default"}
+	due := object.ObjMetadata{GroupKind: schema.GroupKind{Kind: "Pod"}, Name: "due", Namespace: "default"}
+	almostDue := object.ObjMetadata{GroupKind: schema.GroupKind{Kind: "Pod"}, Name: "almost-due", Namespace: "default"}
+	neverPolled := object.ObjMetadata{GroupKind: schema.GroupKind{Kind: "Pod"}, Name: "never-polled", Namespace: "default"}
+
+	r := &statusPollerRunner{
+		identifiers:     object.ObjMetadataSet{polled, due, almostDue, neverPolled},
+		pollingInterval: 2 * time.Second,
+		nextPoll: map[object.ObjMetadata]time.Time{
+			polled:    now.Add(10 * time.Second),
+			due:       now.Add(-time.Second),
+			almostDue: now.Add(500 * time.Millisecond),
+		},
+	}
+	assert.Equal(t, object.ObjMetadataSet{due, almostDue, neverPolled}, r.dueIdentifiers(now))
+}
+
 type fakeStatusReader struct {
 	resourceStatuses    map[schema.GroupKind][]status.Status
 	resourceStatusCount map[schema.GroupKind]int
@@ -0,0 +1,69 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package engine
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/event"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+// StatusReaderMiddleware decorates a StatusReader, e.g. to log, trace or
+// cache the reads of the status of resources, or to rewrite their messages,
+// without re-implementing the StatusReader.
+type StatusReaderMiddleware func(StatusReader) StatusReader
+
+// ChainStatusReader decorates the StatusReader with the middleware. The first
+// middleware is the outermost one, so it is the first to see the reads, and
+// the last to see the resource statuses.
+func ChainStatusReader(sr StatusReader, middleware ...StatusReaderMiddleware) StatusReader {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		sr = middleware[i](sr)
+	}
+	return sr
+}
+
+// MapResourceStatus returns a StatusReaderMiddleware that passes the resource
+// statuses read by the StatusReader through fn, e.g. to rewrite their
+// messages. fn is not called if the StatusReader returns an error.
+func MapResourceStatus(fn func(*event.ResourceStatus) *event.ResourceStatus) StatusReaderMiddleware {
+	return func(sr StatusReader) StatusReader {
+		return &mappingStatusReaderMiddleware{StatusReader: sr, fn: fn}
+	}
+}
+
+type mappingStatusReaderMiddleware struct {
+	StatusReader
+	fn func(*event.ResourceStatus) *event.ResourceStatus
+}
+
+func (m *mappingStatusReaderMiddleware) ReadStatus(ctx context.Context, reader ClusterReader, id object.ObjMetadata) (*event.ResourceStatus, error) {
+	rs, err := m.StatusReader.ReadStatus(ctx, reader, id)
+	if err != nil {
+		return nil, err
+	}
+	return m.fn(rs), nil
+}
+
+func (m *mappingStatusReaderMiddleware) ReadStatusForObject(ctx context.Context, reader ClusterReader, obj *unstructured.Unstructured) (*event.ResourceStatus, error) {
+	rs, err := m.StatusReader.ReadStatusForObject(ctx, reader, obj)
+	if err != nil {
+		return nil, err
+	}
+	return m.fn(rs), nil
+}
+
+// LogStatus returns a StatusReaderMiddleware that logs the resource statuses
+// read by the StatusReader at the verbosity level.
+func LogStatus(level klog.Level) StatusReaderMiddleware {
+	return MapResourceStatus(func(rs *event.ResourceStatus) *event.ResourceStatus {
+		if rs != nil {
+			klog.V(level).Infof("status of %s: %s: %s", rs.Identifier, rs.Status, rs.Message)
+		}
+		return rs
+	})
+}
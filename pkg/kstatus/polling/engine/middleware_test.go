@@ -0,0 +1,104 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakecr "sigs.k8s.io/cli-utils/pkg/kstatus/polling/clusterreader/fake"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/event"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/status"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	fakemapper "sigs.k8s.io/cli-utils/pkg/testutil"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var deploymentIdentifier = object.ObjMetadata{
+	GroupKind: schema.GroupKind{Group: "apps", Kind: "Deployment"},
+	Name:      "foo",
+	Namespace: "default",
+}
+
+func TestChainStatusReader(t *testing.T) {
+	appendMessage := func(suffix string) StatusReaderMiddleware {
+		return MapResourceStatus(func(rs *event.ResourceStatus) *event.ResourceStatus {
+			rs.Message += suffix
+			return rs
+		})
+	}
+	statusReader := &sequenceStatusReader{
+		resourceStatuses: []*event.ResourceStatus{
+			{Identifier: deploymentIdentifier, Status: status.CurrentStatus, Message: "ready"},
+		},
+	}
+
+	sr := ChainStatusReader(statusReader, appendMessage(" outer"), appendMessage(" inner"))
+
+	assert.True(t, sr.Supports(deploymentIdentifier.GroupKind))
+	rs, err := sr.ReadStatus(context.Background(), fakecr.NewNoopClusterReader(), deploymentIdentifier)
+	require.NoError(t, err)
+	// The innermost middleware is the first to see the resource status.
+	assert.Equal(t, "ready inner outer", rs.Message)
+}
+
+func TestMapResourceStatusError(t *testing.T) {
+	readErr := errors.New("read failed")
+	sr := ChainStatusReader(&erroringStatusReader{err: readErr}, MapResourceStatus(func(*event.ResourceStatus) *event.ResourceStatus {
+		t.Error("unexpected call for a failed read")
+		return nil
+	}))
+
+	_, err := sr.ReadStatus(context.Background(), fakecr.NewNoopClusterReader(), deploymentIdentifier)
+	assert.Equal(t, readErr, err)
+}
+
+func TestStatusPollerRunnerStatusReaderMiddleware(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	engine := PollerEngine{
+		Mapper: fakemapper.NewFakeRESTMapper(appsv1.SchemeGroupVersion.WithKind("Deployment")),
+		DefaultStatusReader: &sequenceStatusReader{
+			resourceStatuses: []*event.ResourceStatus{
+				{Identifier: deploymentIdentifier, Status: status.CurrentStatus, Message: "ready"},
+			},
+		},
+		ClusterReaderFactory: ClusterReaderFactoryFunc(func(client.Reader, meta.RESTMapper, object.ObjMetadataSet) (ClusterReader, error) {
+			return fakecr.NewNoopClusterReader(), nil
+		}),
+		StatusReaderMiddleware: []StatusReaderMiddleware{
+			LogStatus(5),
+			MapResourceStatus(func(rs *event.ResourceStatus) *event.ResourceStatus {
+				rewritten := *rs
+				rewritten.Message = "Deployment is " + rs.Message
+				return &rewritten
+			}),
+		},
+	}
+
+	eventChannel := engine.Poll(ctx, object.ObjMetadataSet{deploymentIdentifier}, Options{PollInterval: time.Hour})
+
+	e := <-eventChannel
+	require.Equal(t, event.ResourceUpdateEvent, e.Type, "unexpected event: %v", e)
+	assert.Equal(t, status.CurrentStatus, e.Resource.Status)
+	assert.Equal(t, "Deployment is ready", e.Resource.Message)
+}
+
+// erroringStatusReader fails to read the status of all resources.
+type erroringStatusReader struct {
+	sequenceStatusReader
+	err error
+}
+
+func (e *erroringStatusReader) ReadStatus(context.Context, ClusterReader, object.ObjMetadata) (*event.ResourceStatus, error) {
+	return nil, e.err
+}
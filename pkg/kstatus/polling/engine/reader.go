@@ -30,3 +30,12 @@ type ClusterReader interface {
 	// to sync caches.
 	Sync(ctx context.Context) error
 }
+
+// ChangeNotifier can be implemented by a ClusterReader that watches the
+// resources, e.g. with informers, to trigger a polling loop as soon as a
+// resource changes, rather than waiting for the next poll interval.
+type ChangeNotifier interface {
+	// Changed returns a channel that receives a value when a resource read
+	// by the ClusterReader has changed since the last value was received.
+	Changed() <-chan struct{}
+}
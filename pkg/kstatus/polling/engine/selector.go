@@ -0,0 +1,73 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Selector selects the resources of a GroupKind whose labels match a label
+// selector, e.g. the resources created by a controller or an operator, whose
+// names are not known in advance.
+type Selector struct {
+	// GroupKind of the resources.
+	GroupKind schema.GroupKind
+
+	// Namespace of the resources. Empty selects the resources in all
+	// namespaces. Ignored for cluster-scoped resources.
+	Namespace string
+
+	// LabelSelector must match the labels of the resources. Nil selects all
+	// the resources of the GroupKind in the Namespace.
+	LabelSelector labels.Selector
+}
+
+// String returns the GroupKind, Namespace and LabelSelector of the Selector.
+func (s Selector) String() string {
+	str := s.GroupKind.String()
+	if s.Namespace != "" {
+		str += fmt.Sprintf(" in namespace %s", s.Namespace)
+	}
+	if s.LabelSelector != nil {
+		str += fmt.Sprintf(" matching %q", s.LabelSelector.String())
+	}
+	return str
+}
+
+// listSelector lists the resources selected by the Selector. Resources of
+// unknown GroupKinds, e.g. of CRDs that are not applied yet, are not listed.
+func listSelector(ctx context.Context, reader client.Reader, mapper meta.RESTMapper, s Selector) (object.ObjMetadataSet, error) {
+	mapping, err := mapper.RESTMapping(s.GroupKind)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var opts []client.ListOption
+	if s.Namespace != "" && mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		opts = append(opts, client.InNamespace(s.Namespace))
+	}
+	if s.LabelSelector != nil {
+		opts = append(opts, client.MatchingLabelsSelector{Selector: s.LabelSelector})
+	}
+	var list unstructured.UnstructuredList
+	list.SetGroupVersionKind(mapping.GroupVersionKind.GroupVersion().WithKind(mapping.GroupVersionKind.Kind + "List"))
+	if err := reader.List(ctx, &list, opts...); err != nil {
+		return nil, err
+	}
+	ids := make(object.ObjMetadataSet, 0, len(list.Items))
+	for i := range list.Items {
+		ids = append(ids, object.UnstructuredToObjMetadata(&list.Items[i]))
+	}
+	return ids, nil
+}
@@ -0,0 +1,56 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package event
+
+import (
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/client-go/discovery"
+)
+
+// ErrorCategory classifies the error of reading a resource, so that
+// consumers can tell apart a deleted resource, a denied read and a failure
+// of the server, and react differently, e.g. by retrying.
+//
+//go:generate stringer -type=ErrorCategory -linecomment
+type ErrorCategory int
+
+const (
+	// NoError means that the resource was read successfully.
+	NoError ErrorCategory = iota // None
+	// NotFoundError means that the resource does not exist, e.g. because it
+	// has been deleted. The status of the resource is NotFound.
+	NotFoundError // NotFound
+	// ForbiddenError means that reading the resource was denied, e.g. by
+	// RBAC. Reading it again fails until the permissions change.
+	ForbiddenError // Forbidden
+	// TransientError means that the server failed to respond, e.g. because
+	// it timed out or throttled the request, or that the discovery of the
+	// resource type failed. Reading the resource again may succeed.
+	TransientError // Transient
+	// UnknownError is any other error, e.g. a failure to compute the status
+	// of the resource.
+	UnknownError // Unknown
+)
+
+// CategorizeError returns the ErrorCategory of the error of reading a
+// resource.
+func CategorizeError(err error) ErrorCategory {
+	switch {
+	case err == nil:
+		return NoError
+	case apierrors.IsNotFound(err) || apierrors.IsGone(err):
+		return NotFoundError
+	case apierrors.IsForbidden(err) || apierrors.IsUnauthorized(err):
+		return ForbiddenError
+	case apierrors.IsServerTimeout(err) || apierrors.IsTimeout(err) ||
+		apierrors.IsTooManyRequests(err) || apierrors.IsServiceUnavailable(err) ||
+		apierrors.IsInternalError(err) || apierrors.IsUnexpectedServerError(err) ||
+		utilnet.IsConnectionRefused(err) || utilnet.IsConnectionReset(err) ||
+		utilnet.IsProbableEOF(err) || discovery.IsGroupDiscoveryFailedError(err):
+		return TransientError
+	default:
+		return UnknownError
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package event
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+)
+
+func TestCategorizeError(t *testing.T) {
+	gr := schema.GroupResource{Group: "apps", Resource: "deployments"}
+
+	testCases := map[string]struct {
+		err              error
+		expectedCategory ErrorCategory
+	}{
+		"no error": {
+			err:              nil,
+			expectedCategory: NoError,
+		},
+		"not found": {
+			err:              apierrors.NewNotFound(gr, "foo"),
+			expectedCategory: NotFoundError,
+		},
+		"wrapped not found": {
+			err:              fmt.Errorf("failed to get: %w", apierrors.NewNotFound(gr, "foo")),
+			expectedCategory: NotFoundError,
+		},
+		"forbidden": {
+			err:              apierrors.NewForbidden(gr, "foo", errors.New("access denied")),
+			expectedCategory: ForbiddenError,
+		},
+		"unauthorized": {
+			err:              apierrors.NewUnauthorized("token expired"),
+			expectedCategory: ForbiddenError,
+		},
+		"server timeout": {
+			err:              apierrors.NewServerTimeout(gr, "get", 1),
+			expectedCategory: TransientError,
+		},
+		"too many requests": {
+			err:              apierrors.NewTooManyRequests("throttled", 1),
+			expectedCategory: TransientError,
+		},
+		"service unavailable": {
+			err:              apierrors.NewServiceUnavailable("unavailable"),
+			expectedCategory: TransientError,
+		},
+		"connection refused": {
+			err:              fmt.Errorf("dial tcp: %w", syscall.ECONNREFUSED),
+			expectedCategory: TransientError,
+		},
+		"group discovery failed": {
+			err: &discovery.ErrGroupDiscoveryFailed{
+				Groups: map[schema.GroupVersion]error{
+					{Group: "custom.io", Version: "v1"}: apierrors.NewServiceUnavailable("unavailable"),
+				},
+			},
+			expectedCategory: TransientError,
+		},
+		"other error": {
+			err:              errors.New("failed to compute status"),
+			expectedCategory: UnknownError,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			assert.Equal(t, tc.expectedCategory, CategorizeError(tc.err))
+		})
+	}
+}
+
+func TestErrorCategoryJSON(t *testing.T) {
+	data, err := ForbiddenError.MarshalJSON()
+	assert.NoError(t, err)
+	assert.Equal(t, `"Forbidden"`, string(data))
+
+	var category ErrorCategory
+	assert.NoError(t, category.UnmarshalJSON(data))
+	assert.Equal(t, ForbiddenError, category)
+}
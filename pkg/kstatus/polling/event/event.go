@@ -5,6 +5,7 @@ package event
 
 import (
 	"fmt"
+	"time"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/cli-utils/pkg/kstatus/status"
@@ -27,6 +28,9 @@ const (
 	// synchronization, and the cache is primed. After this point, it's safe to
 	// assume that you won't miss events caused by your own subsequent actions.
 	SyncEvent // Sync
+	// StatsEvent reports the resource usage of a long-running watch. It is
+	// only sent if requested, periodically.
+	StatsEvent // Stats
 )
 
 // Event defines that type that is passed back through the event channel to notify the caller of changes
@@ -42,10 +46,17 @@ type Event struct {
 	// Error is only available for ErrorEvents. It contains the error that caused the engine to
 	// give up.
 	Error error
+
+	// Stats is only available for StatsEvents.
+	Stats *WatchStats
 }
 
 // String returns a string suitable for logging
 func (e Event) String() string {
+	if e.Stats != nil {
+		return fmt.Sprintf("Event{ Type: %q, Stats: %v }",
+			e.Type, *e.Stats)
+	}
 	if e.Error != nil {
 		return fmt.Sprintf("Event{ Type: %q, Resource: %v, Error: %q }",
 			e.Type, e.Resource, e.Error)
@@ -54,6 +65,24 @@ func (e Event) String() string {
 		e.Type, e.Resource)
 }
 
+// WatchStats is the resource usage of a watch, reported periodically so that
+// long-running watches can be checked for leaks of watches, goroutines or
+// memory. The goroutines and the memory are those of the whole process.
+type WatchStats struct {
+	// Uptime is the time since the watch started.
+	Uptime time.Duration
+	// Informers is the number of running informers of the watch.
+	Informers int
+	// PendingTasks is the number of delayed status checks of the watch.
+	PendingTasks int
+	// Goroutines is the number of goroutines of the process.
+	Goroutines int
+	// HeapAlloc is the number of bytes of the allocated heap objects.
+	HeapAlloc uint64
+	// HeapObjects is the number of allocated heap objects.
+	HeapObjects uint64
+}
+
 // ResourceStatus contains information about a resource after we have
 // fetched it from the cluster and computed status.
 type ResourceStatus struct {
@@ -72,6 +101,10 @@ type ResourceStatus struct {
 	// process of fetching the resource and computing the status.
 	Error error
 
+	// ErrorCategory classifies the Error. Resources that were not found
+	// have the NotFoundError category, but no Error.
+	ErrorCategory ErrorCategory
+
 	// Message is text describing the status of the resource.
 	Message string
 
@@ -127,7 +160,8 @@ func (g ResourceStatuses) Swap(i, j int) {
 func ResourceStatusEqual(or1, or2 *ResourceStatus) bool {
 	if or1.Identifier != or2.Identifier ||
 		or1.Status != or2.Status ||
-		or1.Message != or2.Message {
+		or1.Message != or2.Message ||
+		or1.ErrorCategory != or2.ErrorCategory {
 		return false
 	}
 
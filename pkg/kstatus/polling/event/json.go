@@ -0,0 +1,25 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package event
+
+import "sigs.k8s.io/cli-utils/pkg/jsonenum"
+
+// The enums are encoded in JSON by name, so that consumers of the JSON do
+// not depend on the order of the constants.
+
+func (x Type) MarshalJSON() ([]byte, error) {
+	return jsonenum.Marshal(x)
+}
+
+func (x *Type) UnmarshalJSON(data []byte) error {
+	return jsonenum.Unmarshal(data, x)
+}
+
+func (x ErrorCategory) MarshalJSON() ([]byte, error) {
+	return jsonenum.Marshal(x)
+}
+
+func (x *ErrorCategory) UnmarshalJSON(data []byte) error {
+	return jsonenum.Unmarshal(data, x)
+}
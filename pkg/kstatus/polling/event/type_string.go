@@ -11,11 +11,12 @@ func _() {
 	_ = x[ResourceUpdateEvent-0]
 	_ = x[ErrorEvent-1]
 	_ = x[SyncEvent-2]
+	_ = x[StatsEvent-3]
 }
 
-const _Type_name = "UpdateErrorSync"
+const _Type_name = "UpdateErrorSyncStats"
 
-var _Type_index = [...]uint8{0, 6, 11, 15}
+var _Type_index = [...]uint8{0, 6, 11, 15, 20}
 
 func (i Type) String() string {
 	if i < 0 || i >= Type(len(_Type_index)-1) {
@@ -0,0 +1,209 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package metrics provides Prometheus metrics of the StatusPoller, so that
+// long-running integrators can monitor how resources are polled and waited
+// on.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/event"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/status"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const namespace = "kstatus_poller"
+
+// statuses are the statuses of the resources gauge. Unlike status.Statuses,
+// they include NotFound, since polled resources may not exist.
+var statuses = append([]status.Status{status.NotFoundStatus}, status.Statuses...)
+
+// Metrics are the collectors of the StatusPoller. A nil *Metrics is valid,
+// and records nothing.
+type Metrics struct {
+	// pollDuration is the duration of the polling loops, including the sync
+	// of the ClusterReader.
+	pollDuration prometheus.Histogram
+
+	// resources is the number of polled resources with each status, as of
+	// the most recent polling loop of each running poll.
+	resources *prometheus.GaugeVec
+
+	// apiRequests is the number of requests to the apiserver, by verb.
+	apiRequests *prometheus.CounterVec
+
+	// readerErrors is the number of errors of reading the status of
+	// resources, by ErrorCategory.
+	readerErrors *prometheus.CounterVec
+}
+
+// New creates the Metrics and registers them with the Registerer. Metrics
+// that are already registered, e.g. by another StatusPoller, are shared.
+func New(reg prometheus.Registerer) (*Metrics, error) {
+	m := &Metrics{
+		pollDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "poll_duration_seconds",
+			Help:      "Duration of the polling loops, including the sync of the ClusterReader.",
+			Buckets:   prometheus.ExponentialBuckets(0.01, 2, 12),
+		}),
+		resources: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "resources",
+			Help:      "Number of polled resources with each status.",
+		}, []string{"status"}),
+		apiRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "api_requests_total",
+			Help:      "Number of requests to the apiserver, by verb.",
+		}, []string{"verb"}),
+		readerErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "reader_errors_total",
+			Help:      "Number of errors of reading the status of resources, by category.",
+		}, []string{"category"}),
+	}
+	var err error
+	if m.pollDuration, err = register(reg, m.pollDuration); err != nil {
+		return nil, err
+	}
+	if m.resources, err = register(reg, m.resources); err != nil {
+		return nil, err
+	}
+	if m.apiRequests, err = register(reg, m.apiRequests); err != nil {
+		return nil, err
+	}
+	if m.readerErrors, err = register(reg, m.readerErrors); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// register registers the collector, or returns the collector that is
+// already registered in its place.
+func register[T prometheus.Collector](reg prometheus.Registerer, c T) (T, error) {
+	err := reg.Register(c)
+	if err == nil {
+		return c, nil
+	}
+	var are prometheus.AlreadyRegisteredError
+	if errors.As(err, &are) {
+		if existing, ok := are.ExistingCollector.(T); ok {
+			return existing, nil
+		}
+	}
+	return c, err
+}
+
+// ObservePoll records the duration of a polling loop.
+func (m *Metrics) ObservePoll(d time.Duration) {
+	if m == nil {
+		return
+	}
+	m.pollDuration.Observe(d.Seconds())
+}
+
+// NewResourceCounter returns a ResourceCounter that records the resources of
+// a poll in the resources gauge.
+func (m *Metrics) NewResourceCounter() *ResourceCounter {
+	if m == nil {
+		return nil
+	}
+	for _, s := range statuses {
+		// Initialize the gauge of every status, so that all the statuses
+		// are exported.
+		m.resources.WithLabelValues(s.String())
+	}
+	return &ResourceCounter{
+		resources: m.resources,
+		counts:    make(map[status.Status]int),
+	}
+}
+
+// ResourceCounter records the number of resources of a poll with each
+// status. The resources gauge is shared by all the polls of the StatusPollers
+// registered with the same Registerer, so the ResourceCounter adds the
+// changes of its counts to the gauge, instead of setting it. A nil
+// *ResourceCounter is valid, and records nothing.
+type ResourceCounter struct {
+	resources *prometheus.GaugeVec
+	counts    map[status.Status]int
+}
+
+// Set records the number of polled resources with each status. Resources
+// without a status yet are counted as Unknown.
+func (c *ResourceCounter) Set(resourceStatuses []*event.ResourceStatus, polled int) {
+	if c == nil {
+		return
+	}
+	counts := make(map[status.Status]int)
+	for _, rs := range resourceStatuses {
+		counts[rs.Status]++
+	}
+	counts[status.UnknownStatus] += polled - len(resourceStatuses)
+	c.add(counts)
+}
+
+// Reset removes the resources of the poll from the gauge. It must be called
+// when the poll stops.
+func (c *ResourceCounter) Reset() {
+	if c == nil {
+		return
+	}
+	c.add(make(map[status.Status]int))
+}
+
+// add adds the difference between the counts and the recorded counts to the
+// gauge, and records the counts.
+func (c *ResourceCounter) add(counts map[status.Status]int) {
+	for _, s := range statuses {
+		if delta := counts[s] - c.counts[s]; delta != 0 {
+			c.resources.WithLabelValues(s.String()).Add(float64(delta))
+		}
+	}
+	c.counts = counts
+}
+
+// ObserveResourceStatus records the error of reading the status of a
+// resource, if any.
+func (m *Metrics) ObserveResourceStatus(rs *event.ResourceStatus) {
+	if m == nil || rs.Error == nil {
+		return
+	}
+	m.readerErrors.WithLabelValues(rs.ErrorCategory.String()).Inc()
+}
+
+// InstrumentReader returns a client.Reader that counts the requests of the
+// reader. Readers that do not read through the client.Reader, e.g. the
+// WatchingClusterReader, are not counted.
+func (m *Metrics) InstrumentReader(reader client.Reader) client.Reader {
+	if m == nil {
+		return reader
+	}
+	return &instrumentedReader{
+		reader:  reader,
+		metrics: m,
+	}
+}
+
+type instrumentedReader struct {
+	reader  client.Reader
+	metrics *Metrics
+}
+
+var _ client.Reader = &instrumentedReader{}
+
+func (r *instrumentedReader) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	r.metrics.apiRequests.WithLabelValues("get").Inc()
+	return r.reader.Get(ctx, key, obj, opts...)
+}
+
+func (r *instrumentedReader) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	r.metrics.apiRequests.WithLabelValues("list").Inc()
+	return r.reader.List(ctx, list, opts...)
+}
@@ -0,0 +1,133 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/kubectl/pkg/scheme"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/event"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/status"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestNew(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m1, err := New(reg)
+	require.NoError(t, err)
+
+	// The metrics of a second StatusPoller are shared with the first one.
+	m2, err := New(reg)
+	require.NoError(t, err)
+	m2.ObservePoll(time.Second)
+	assert.Equal(t, 1, testutil.CollectAndCount(m1.pollDuration))
+
+	// Collectors of a different type with the same name are an error.
+	conflicting := prometheus.NewRegistry()
+	conflicting.MustRegister(prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "poll_duration_seconds",
+		Help:      "Conflicting metric.",
+	}))
+	_, err = New(conflicting)
+	assert.Error(t, err)
+}
+
+func TestMetrics(t *testing.T) {
+	m, err := New(prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	m.NewResourceCounter().Set([]*event.ResourceStatus{
+		{Status: status.CurrentStatus},
+		{Status: status.CurrentStatus},
+		{Status: status.InProgressStatus},
+		{Status: status.NotFoundStatus},
+	}, 5)
+	assert.Equal(t, float64(2), testutil.ToFloat64(m.resources.WithLabelValues("Current")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.resources.WithLabelValues("InProgress")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.resources.WithLabelValues("NotFound")))
+	// The resource without a status yet is Unknown.
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.resources.WithLabelValues("Unknown")))
+	assert.Equal(t, float64(0), testutil.ToFloat64(m.resources.WithLabelValues("Failed")))
+
+	m.ObserveResourceStatus(&event.ResourceStatus{Status: status.CurrentStatus})
+	m.ObserveResourceStatus(&event.ResourceStatus{
+		Status:        status.UnknownStatus,
+		Error:         errors.New("forbidden"),
+		ErrorCategory: event.ForbiddenError,
+	})
+	assert.Equal(t, 1, testutil.CollectAndCount(m.readerErrors))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.readerErrors.WithLabelValues("Forbidden")))
+}
+
+func TestResourceCounterSharedGauge(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m1, err := New(reg)
+	require.NoError(t, err)
+	m2, err := New(reg)
+	require.NoError(t, err)
+
+	// Two pollers sharing the registry, and the resources gauge.
+	c1 := m1.NewResourceCounter()
+	c2 := m2.NewResourceCounter()
+	c1.Set([]*event.ResourceStatus{
+		{Status: status.CurrentStatus},
+		{Status: status.InProgressStatus},
+	}, 2)
+	c2.Set([]*event.ResourceStatus{
+		{Status: status.CurrentStatus},
+	}, 1)
+	assert.Equal(t, float64(2), testutil.ToFloat64(m1.resources.WithLabelValues("Current")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m1.resources.WithLabelValues("InProgress")))
+
+	// An update of a poller only changes its own resources.
+	c1.Set([]*event.ResourceStatus{
+		{Status: status.CurrentStatus},
+		{Status: status.CurrentStatus},
+	}, 2)
+	assert.Equal(t, float64(3), testutil.ToFloat64(m1.resources.WithLabelValues("Current")))
+	assert.Equal(t, float64(0), testutil.ToFloat64(m1.resources.WithLabelValues("InProgress")))
+
+	// The resources of a stopped poller are removed.
+	c1.Reset()
+	assert.Equal(t, float64(1), testutil.ToFloat64(m1.resources.WithLabelValues("Current")))
+	c2.Reset()
+	assert.Equal(t, float64(0), testutil.ToFloat64(m1.resources.WithLabelValues("Current")))
+}
+
+func TestInstrumentReader(t *testing.T) {
+	m, err := New(prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	deployment := &appsv1.Deployment{}
+	deployment.SetName("foo")
+	deployment.SetNamespace("default")
+	reader := m.InstrumentReader(fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(deployment).Build())
+
+	ctx := context.Background()
+	require.NoError(t, reader.Get(ctx, client.ObjectKeyFromObject(deployment), &appsv1.Deployment{}))
+	require.NoError(t, reader.List(ctx, &appsv1.DeploymentList{}))
+	require.NoError(t, reader.List(ctx, &appsv1.DeploymentList{}))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.apiRequests.WithLabelValues("get")))
+	assert.Equal(t, float64(2), testutil.ToFloat64(m.apiRequests.WithLabelValues("list")))
+}
+
+func TestNilMetrics(t *testing.T) {
+	var m *Metrics
+	m.ObservePoll(time.Second)
+	m.NewResourceCounter().Set(nil, 1)
+	m.NewResourceCounter().Reset()
+	m.ObserveResourceStatus(&event.ResourceStatus{Error: errors.New("error")})
+	reader := fake.NewClientBuilder().Build()
+	assert.Equal(t, client.Reader(reader), m.InstrumentReader(reader))
+}
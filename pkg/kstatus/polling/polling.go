@@ -8,12 +8,17 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"k8s.io/kubectl/pkg/scheme"
+	"sigs.k8s.io/cli-utils/pkg/backoff"
 	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/clusterreader"
 	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/engine"
 	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/event"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/metrics"
 	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/statusreaders"
 	"sigs.k8s.io/cli-utils/pkg/kstatus/status"
 	"sigs.k8s.io/cli-utils/pkg/object"
@@ -27,18 +32,37 @@ func NewStatusPoller(reader client.Reader, mapper meta.RESTMapper, o Options) *S
 	var statusReaders []engine.StatusReader
 
 	statusReaders = append(statusReaders, o.CustomStatusReaders...)
+	statusReaders = append(statusReaders, o.Registry.StatusReaders(mapper)...)
 
-	srs, defaultStatusReader := createStatusReaders(mapper)
+	statusFunc := status.Compute
+	if o.CompatibleStatus {
+		statusFunc = status.ComputeCompatible
+	}
+	srs, defaultStatusReader := createStatusReaders(mapper, statusFunc)
 	statusReaders = append(statusReaders, srs...)
 
+	var m *metrics.Metrics
+	if o.Registerer != nil {
+		var err error
+		m, err = metrics.New(o.Registerer)
+		if err != nil {
+			klog.Warningf("failed to register the StatusPoller metrics: %v", err)
+		}
+	}
+
 	return &StatusPoller{
 		engine: &engine.PollerEngine{
-			Reader:               reader,
-			Mapper:               mapper,
-			DefaultStatusReader:  defaultStatusReader,
-			StatusReaders:        statusReaders,
-			ClusterReaderFactory: o.ClusterReaderFactory,
+			Reader:                 reader,
+			Mapper:                 mapper,
+			DefaultStatusReader:    defaultStatusReader,
+			StatusReaders:          statusReaders,
+			ClusterReaderFactory:   o.ClusterReaderFactory,
+			Metrics:                m,
+			StatusReaderMiddleware: o.StatusReaderMiddleware,
 		},
+		groupKindPollIntervals:      o.GroupKindPollIntervals,
+		unchangedBackoff:            o.UnchangedBackoff,
+		unchangedPollsBeforeBackoff: o.UnchangedPollsBeforeBackoff,
 	}
 }
 
@@ -60,12 +84,27 @@ func NewStatusPollerFromFactory(f cmdutil.Factory, o Options) (*StatusPoller, er
 		return nil, fmt.Errorf("error creating client: %w", err)
 	}
 
-	return NewStatusPoller(c, mapper, o), nil
+	if o.Watch && o.ClusterReaderFactory == nil {
+		dynamicClient, err := f.DynamicClient()
+		if err != nil {
+			return nil, fmt.Errorf("error creating dynamic client: %w", err)
+		}
+		o.ClusterReaderFactory = clusterreader.NewWatchingClusterReaderFactory(dynamicClient)
+	}
+
+	var reader client.Reader = c
+	if len(o.TypedKinds) > 0 {
+		reader = clusterreader.NewTypedReader(c, scheme.Scheme, o.TypedKinds)
+	}
+	return NewStatusPoller(reader, mapper, o), nil
 }
 
 func setDefaults(o *Options) {
 	if o.ClusterReaderFactory == nil {
-		o.ClusterReaderFactory = engine.ClusterReaderFactoryFunc(clusterreader.NewCachingClusterReader)
+		o.ClusterReaderFactory = clusterreader.NewCachingClusterReaderFactory(o.CacheTTL)
+	}
+	if o.Registry == nil {
+		o.Registry = statusreaders.DefaultRegistry
 	}
 }
 
@@ -76,14 +115,77 @@ type Options struct {
 	// be used to compute reconcile status for resources.
 	CustomStatusReaders []engine.StatusReader
 
+	// Registry contains the StatusReaders registered for GroupKinds, e.g. of
+	// CRDs, which are used after the CustomStatusReaders, and before the
+	// built-in StatusReaders. Defaults to statusreaders.DefaultRegistry.
+	Registry *statusreaders.Registry
+
 	// ClusterReaderFactory allows for custom implementations of the engine.ClusterReader interface
 	// in the StatusPoller. The default implementation if the clusterreader.CachingClusterReader.
 	ClusterReaderFactory engine.ClusterReaderFactory
+
+	// CacheTTL is how long the default CachingClusterReader reuses the
+	// resources it has listed, instead of listing them again before every
+	// polling loop. Zero lists the resources before every polling loop.
+	// Ignored if ClusterReaderFactory is set.
+	CacheTTL time.Duration
+
+	// Watch makes the StatusPoller created by NewStatusPollerFromFactory
+	// watch the resources with informers, using the
+	// clusterreader.WatchingClusterReader, instead of listing them before
+	// every polling loop. The status is then computed as soon as a resource
+	// changes, and the poll interval only acts as a fallback. Ignored if
+	// ClusterReaderFactory is set.
+	Watch bool
+
+	// TypedKinds are the built-in kinds read as typed objects, using protobuf
+	// encoding, by the StatusPoller created by NewStatusPollerFromFactory.
+	// Reading typed objects reduces CPU and bandwidth usage when polling
+	// many resources of a few kinds.
+	TypedKinds []schema.GroupKind
+
+	// GroupKindPollIntervals overrides the poll interval of the resources of
+	// these GroupKinds, e.g. to poll Pods and Jobs more often than the other
+	// resources, and Namespaces less often. The PollIntervalFunc of the
+	// PollOptions does not apply to these intervals. The default
+	// CachingClusterReader still lists the resources of every GroupKind
+	// whenever a resource is due.
+	GroupKindPollIntervals map[schema.GroupKind]time.Duration
+
+	// UnchangedBackoff, if set, stretches the poll interval of the resources
+	// whose status has not changed for UnchangedPollsBeforeBackoff polls,
+	// e.g. with a backoff.Exponential. The interval is reset when the status
+	// changes.
+	UnchangedBackoff backoff.Strategy
+
+	// UnchangedPollsBeforeBackoff is the number of polls without a status
+	// change before the UnchangedBackoff applies. Defaults to 1.
+	UnchangedPollsBeforeBackoff int
+
+	// Registerer, if set, registers the metrics of the StatusPoller, e.g.
+	// the duration of the polling loops, the number of resources with each
+	// status, the number of requests to the apiserver and the number of
+	// errors of reading the status of resources. See the metrics package.
+	Registerer prometheus.Registerer
+
+	// CompatibleStatus computes the status of the resources without specific
+	// StatusReaders with status.ComputeCompatible, which also honors the
+	// health conventions of other tools, e.g. Flux and Argo CD.
+	CompatibleStatus bool
+
+	// StatusReaderMiddleware, if set, decorates all the StatusReaders, e.g.
+	// to log, trace or cache the reads, or to rewrite the messages of the
+	// resource statuses. See engine.StatusReaderMiddleware.
+	StatusReaderMiddleware []engine.StatusReaderMiddleware
 }
 
 // StatusPoller provides functionality for polling a cluster for status for a set of resources.
 type StatusPoller struct {
 	engine *engine.PollerEngine
+
+	groupKindPollIntervals      map[schema.GroupKind]time.Duration
+	unchangedBackoff            backoff.Strategy
+	unchangedPollsBeforeBackoff int
 }
 
 // Poll will create a new statusPollerRunner that will poll all the resources provided and report their status
@@ -91,7 +193,15 @@ type StatusPoller struct {
 // context passed in.
 func (s *StatusPoller) Poll(ctx context.Context, identifiers object.ObjMetadataSet, options PollOptions) <-chan event.Event {
 	return s.engine.Poll(ctx, identifiers, engine.Options{
-		PollInterval: options.PollInterval,
+		PollInterval:                options.PollInterval,
+		PollIntervalFunc:            options.PollIntervalFunc,
+		GroupKindPollIntervals:      s.groupKindPollIntervals,
+		UnchangedBackoff:            s.unchangedBackoff,
+		UnchangedPollsBeforeBackoff: s.unchangedPollsBeforeBackoff,
+		Updates:                     options.Updates,
+		Selectors:                   options.Selectors,
+		DeduplicateByStatus:         options.DeduplicateByStatus,
+		CoalesceWindow:              options.CoalesceWindow,
 	})
 }
 
@@ -101,6 +211,28 @@ type PollOptions struct {
 	// PollInterval defines how often the PollerEngine should poll the cluster for the latest
 	// state of the resources.
 	PollInterval time.Duration
+
+	// PollIntervalFunc, if set, adjusts the PollInterval to the number of
+	// polled resources. See engine.ScaledPollInterval.
+	PollIntervalFunc engine.PollIntervalFunc
+
+	// Updates, if set, adds and removes resources to and from the polled
+	// resources while polling, without starting a new poll.
+	Updates <-chan engine.IdentifierUpdate
+
+	// Selectors, if set, also polls the resources selected by label, e.g.
+	// the resources created with generateName, or by operators. The
+	// selected resources are listed before every polling loop, so that new
+	// resources are polled as soon as they are created.
+	Selectors []engine.Selector
+
+	// DeduplicateByStatus, if true, only sends an event for a resource when
+	// its status or message changes.
+	DeduplicateByStatus bool
+
+	// CoalesceWindow, if set, only sends the latest event of each resource
+	// once per window, to limit the events sent for large sets of resources.
+	CoalesceWindow time.Duration
 }
 
 // createStatusReaders creates an instance of all the statusreaders. This includes a set of statusreaders for
@@ -108,17 +240,21 @@ type PollOptions struct {
 // a specific statusreaders.
 // TODO: We should consider making the registration more automatic instead of having to create each of them
 // here. Also, it might be worth creating them on demand.
-func createStatusReaders(mapper meta.RESTMapper) ([]engine.StatusReader, engine.StatusReader) {
-	defaultStatusReader := statusreaders.NewGenericStatusReader(mapper, status.Compute)
+func createStatusReaders(mapper meta.RESTMapper, statusFunc statusreaders.StatusFunc) ([]engine.StatusReader, engine.StatusReader) {
+	defaultStatusReader := statusreaders.NewGenericStatusReader(mapper, statusFunc)
 
 	replicaSetStatusReader := statusreaders.NewReplicaSetStatusReader(mapper, defaultStatusReader)
 	deploymentStatusReader := statusreaders.NewDeploymentResourceReader(mapper, replicaSetStatusReader)
 	statefulSetStatusReader := statusreaders.NewStatefulSetResourceReader(mapper, defaultStatusReader)
+	daemonSetStatusReader := statusreaders.NewDaemonSetResourceReader(mapper, defaultStatusReader)
+	jobStatusReader := statusreaders.NewJobStatusReader(mapper, defaultStatusReader)
 
 	statusReaders := []engine.StatusReader{
 		deploymentStatusReader,
 		statefulSetStatusReader,
+		daemonSetStatusReader,
 		replicaSetStatusReader,
+		jobStatusReader,
 	}
 
 	return statusReaders, defaultStatusReader
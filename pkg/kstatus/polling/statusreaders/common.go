@@ -161,6 +161,21 @@ func toSelector(resource *unstructured.Unstructured, path ...string) (labels.Sel
 	return metav1.LabelSelectorAsSelector(&s)
 }
 
+// computeStatus computes the status of the resource with the StatusFunc,
+// unless the controller of the resource has not observed its latest
+// generation, so that all the readers report stale status the same way,
+// whatever the StatusFunc. Resources that are being deleted are left to the
+// StatusFunc, since their deletion may update their generation.
+func computeStatus(statusFunc StatusFunc, obj *unstructured.Unstructured) (*status.Result, error) {
+	if obj.GetDeletionTimestamp() == nil {
+		res, err := status.CheckObservedGeneration(obj)
+		if res != nil || err != nil {
+			return res, err
+		}
+	}
+	return statusFunc(obj)
+}
+
 // errResourceToResourceStatus construct the appropriate ResourceStatus
 // object based on an error and the resource itself.
 func errResourceToResourceStatus(err error, resource *unstructured.Unstructured, genResources ...*event.ResourceStatus) (*event.ResourceStatus, error) {
@@ -173,9 +188,10 @@ func errResourceToResourceStatus(err error, resource *unstructured.Unstructured,
 	identifier := object.UnstructuredToObjMetadata(resource)
 	if apierrors.IsNotFound(err) {
 		return &event.ResourceStatus{
-			Identifier: identifier,
-			Status:     status.NotFoundStatus,
-			Message:    "Resource not found",
+			Identifier:    identifier,
+			Status:        status.NotFoundStatus,
+			Message:       "Resource not found",
+			ErrorCategory: event.NotFoundError,
 		}, nil
 	}
 	return &event.ResourceStatus{
@@ -183,6 +199,7 @@ func errResourceToResourceStatus(err error, resource *unstructured.Unstructured,
 		Status:             status.UnknownStatus,
 		Resource:           resource,
 		Error:              err,
+		ErrorCategory:      event.CategorizeError(err),
 		GeneratedResources: genResources,
 	}, nil
 }
@@ -198,14 +215,31 @@ func errIdentifierToResourceStatus(err error, identifier object.ObjMetadata) (*e
 	}
 	if apierrors.IsNotFound(err) {
 		return &event.ResourceStatus{
-			Identifier: identifier,
-			Status:     status.NotFoundStatus,
-			Message:    "Resource not found",
+			Identifier:    identifier,
+			Status:        status.NotFoundStatus,
+			Message:       "Resource not found",
+			ErrorCategory: event.NotFoundError,
 		}, nil
 	}
+	// Resources of types that are not served, e.g. because their CRD is
+	// not installed yet, cannot exist.
+	if meta.IsNoMatchError(err) {
+		return &event.ResourceStatus{
+			Identifier:    identifier,
+			Status:        status.NotFoundStatus,
+			Message:       fmt.Sprintf("Resource type %s not found, its CRD may not be installed yet", identifier.GroupKind),
+			ErrorCategory: event.NotFoundError,
+		}, nil
+	}
+	var message string
+	if engine.IsDiscoveryError(err) {
+		message = fmt.Sprintf("Failed to discover resource type %s", identifier.GroupKind)
+	}
 	return &event.ResourceStatus{
-		Identifier: identifier,
-		Status:     status.UnknownStatus,
-		Error:      err,
+		Identifier:    identifier,
+		Status:        status.UnknownStatus,
+		Message:       message,
+		Error:         err,
+		ErrorCategory: event.CategorizeError(err),
 	}, nil
 }
@@ -16,8 +16,10 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	fakecr "sigs.k8s.io/cli-utils/pkg/kstatus/polling/clusterreader/fake"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/event"
 	fakesr "sigs.k8s.io/cli-utils/pkg/kstatus/polling/statusreaders/fake"
 	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/testutil"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/status"
 	"sigs.k8s.io/cli-utils/pkg/object"
 	fakemapper "sigs.k8s.io/cli-utils/pkg/testutil"
 )
@@ -107,6 +109,29 @@ func TestLookupResource(t *testing.T) {
 	}
 }
 
+func TestReadStatusUnknownType(t *testing.T) {
+	identifier := object.ObjMetadata{
+		GroupKind: schema.GroupKind{
+			Group: "custom.io",
+			Kind:  "Custom",
+		},
+		Name:      "Bar",
+		Namespace: "default",
+	}
+	statusReader := &baseStatusReader{
+		mapper: fakemapper.NewFakeRESTMapper(deploymentGVK),
+	}
+
+	rs, err := statusReader.ReadStatus(context.Background(), &fakecr.ClusterReader{}, identifier)
+	require.NoError(t, err)
+	assert.Equal(t, &event.ResourceStatus{
+		Identifier:    identifier,
+		Status:        status.NotFoundStatus,
+		Message:       "Resource type Custom.custom.io not found, its CRD may not be installed yet",
+		ErrorCategory: event.NotFoundError,
+	}, rs)
+}
+
 func TestStatusForGeneratedResources(t *testing.T) {
 	testCases := map[string]struct {
 		manifest    string
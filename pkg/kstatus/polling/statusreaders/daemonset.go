@@ -0,0 +1,45 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package statusreaders
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/engine"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/event"
+)
+
+func NewDaemonSetResourceReader(mapper meta.RESTMapper, podResourceReader resourceTypeStatusReader) engine.StatusReader {
+	return &baseStatusReader{
+		mapper: mapper,
+		resourceStatusReader: &daemonSetResourceReader{
+			mapper:            mapper,
+			podResourceReader: podResourceReader,
+		},
+	}
+}
+
+// daemonSetResourceReader is an implementation of the ResourceReader interface
+// that can fetch DaemonSet resources from the cluster, knows how to find any
+// Pods belonging to the DaemonSet, and compute status for the DaemonSet.
+type daemonSetResourceReader struct {
+	mapper meta.RESTMapper
+
+	podResourceReader resourceTypeStatusReader
+}
+
+var _ resourceTypeStatusReader = &daemonSetResourceReader{}
+
+func (d *daemonSetResourceReader) Supports(gk schema.GroupKind) bool {
+	return gk == appsv1.SchemeGroupVersion.WithKind("DaemonSet").GroupKind()
+}
+
+func (d *daemonSetResourceReader) ReadStatusForObject(ctx context.Context, reader engine.ClusterReader,
+	daemonSet *unstructured.Unstructured) (*event.ResourceStatus, error) {
+	return newPodControllerStatusReader(d.mapper, d.podResourceReader).readStatus(ctx, reader, daemonSet)
+}
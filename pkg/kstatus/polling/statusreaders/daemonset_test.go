@@ -0,0 +1,115 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package statusreaders
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	fakecr "sigs.k8s.io/cli-utils/pkg/kstatus/polling/clusterreader/fake"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/testutil"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/status"
+	fakemapper "sigs.k8s.io/cli-utils/pkg/testutil"
+)
+
+var (
+	daemonSetGVK = appsv1.SchemeGroupVersion.WithKind("DaemonSet")
+
+	inProgressDaemonSet = strings.TrimSpace(`
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: test
+  generation: 1
+  namespace: qual
+spec:
+  selector:
+    matchLabels:
+      app: test
+status:
+  observedGeneration: 1
+  desiredNumberScheduled: 2
+  currentNumberScheduled: 2
+  updatedNumberScheduled: 2
+  numberAvailable: 1
+  numberReady: 1
+`)
+
+	readyDaemonSetPod = strings.TrimSpace(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: test-abcde
+  namespace: qual
+  labels:
+    app: test
+status:
+  phase: Running
+  conditions:
+  - type: Ready
+    status: "True"
+`)
+
+	crashLoopingDaemonSetPod = strings.TrimSpace(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: test-fghij
+  namespace: qual
+  labels:
+    app: test
+status:
+  phase: Running
+  containerStatuses:
+  - name: main
+    state:
+      waiting:
+        reason: CrashLoopBackOff
+`)
+)
+
+func TestDaemonSetStatusReader(t *testing.T) {
+	testCases := map[string]struct {
+		pods            []string
+		expectedStatus  status.Status
+		expectedMessage string
+	}{
+		"pods in progress": {
+			pods:            []string{readyDaemonSetPod},
+			expectedStatus:  status.InProgressStatus,
+			expectedMessage: "Available: 1/2",
+		},
+		"pod crash looping": {
+			pods:            []string{readyDaemonSetPod, crashLoopingDaemonSetPod},
+			expectedStatus:  status.FailedStatus,
+			expectedMessage: "1 pods have failed: test-fghij: Containers in CrashLoop state: main",
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			pods := &unstructured.UnstructuredList{}
+			for _, pod := range tc.pods {
+				pods.Items = append(pods.Items, *testutil.YamlToUnstructured(t, pod))
+			}
+			fakeReader := &fakecr.ClusterReader{
+				ListResources: pods,
+			}
+			fakeMapper := fakemapper.NewFakeRESTMapper(daemonSetGVK, podGVK)
+			statusReader := NewDaemonSetResourceReader(fakeMapper, NewGenericStatusReader(fakeMapper, status.Compute))
+
+			rs, err := statusReader.ReadStatusForObject(context.Background(), fakeReader,
+				testutil.YamlToUnstructured(t, inProgressDaemonSet))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, rs.Status)
+			assert.Equal(t, tc.expectedMessage, rs.Message)
+			assert.Len(t, rs.GeneratedResources, len(tc.pods))
+		})
+	}
+}
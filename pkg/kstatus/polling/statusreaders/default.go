@@ -24,18 +24,25 @@ func NewDefaultStatusReader(mapper meta.RESTMapper) engine.StatusReader {
 }
 
 // NewStatusReader returns a DelegatingStatusReader that includes the statusreaders
-// for the build-in Kubernetes resources and also any provided custom status readers.
+// for the build-in Kubernetes resources and also any provided custom status readers,
+// followed by the status readers of the DefaultRegistry.
 func NewStatusReader(mapper meta.RESTMapper, statusReaders ...engine.StatusReader) engine.StatusReader {
 	defaultStatusReader := NewGenericStatusReader(mapper, status.Compute)
 
+	statusReaders = append(statusReaders, DefaultRegistry.StatusReaders(mapper)...)
+
 	replicaSetStatusReader := NewReplicaSetStatusReader(mapper, defaultStatusReader)
 	deploymentStatusReader := NewDeploymentResourceReader(mapper, replicaSetStatusReader)
 	statefulSetStatusReader := NewStatefulSetResourceReader(mapper, defaultStatusReader)
+	daemonSetStatusReader := NewDaemonSetResourceReader(mapper, defaultStatusReader)
+	jobStatusReader := NewJobStatusReader(mapper, defaultStatusReader)
 
 	statusReaders = append(statusReaders,
 		deploymentStatusReader,
 		statefulSetStatusReader,
+		daemonSetStatusReader,
 		replicaSetStatusReader,
+		jobStatusReader,
 		defaultStatusReader,
 	)
 
@@ -5,6 +5,8 @@ package statusreaders
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -53,20 +55,81 @@ func (d *deploymentResourceReader) ReadStatusForObject(ctx context.Context, read
 		return errResourceToResourceStatus(err, deployment)
 	}
 
-	// Currently this engine just uses the status library for computing
-	// status for the deployment. But we do have the status and state for all
-	// ReplicaSets and Pods in the ObservedReplicaSets data structure, so the
-	// rules can be improved to take advantage of this information.
-	res, err := status.Compute(deployment)
+	// The status library computes the status of the Deployment. The Pods of
+	// the ReplicaSets are only used to explain why the rollout is stuck, e.g.
+	// because images cannot be pulled.
+	res, err := computeStatus(status.Compute, deployment)
 	if err != nil {
 		return errResourceToResourceStatus(err, deployment, replicaSetStatuses...)
 	}
 
+	message := res.Message
+	if res.Status != status.CurrentStatus && !status.IsLatestGenerationNotObserved(res) {
+		if podMessages := unhealthyPodMessages(replicaSetStatuses); len(podMessages) > 0 {
+			message = fmt.Sprintf("%s. %d pods are not ready: %s", strings.TrimSuffix(message, "."), len(podMessages),
+				strings.Join(podMessages, "; "))
+		}
+	}
 	return &event.ResourceStatus{
 		Identifier:         identifier,
 		Status:             res.Status,
 		Resource:           deployment,
-		Message:            res.Message,
+		Message:            message,
 		GeneratedResources: replicaSetStatuses,
 	}, nil
 }
+
+// unhealthyPodMessages returns the reasons why the Pods of the ReplicaSets
+// have failed, or are stuck waiting. The messages of the Pods that are stuck
+// are replaced by the reason.
+func unhealthyPodMessages(replicaSetStatuses event.ResourceStatuses) []string {
+	var messages []string
+	for _, replicaSetStatus := range replicaSetStatuses {
+		for _, podResourceStatus := range replicaSetStatus.GeneratedResources {
+			if podResourceStatus.Status == status.FailedStatus {
+				messages = append(messages,
+					fmt.Sprintf("%s: %s", podResourceStatus.Identifier.Name, podResourceStatus.Message))
+				continue
+			}
+			if message, waiting := podWaitingMessage(podResourceStatus.Resource); waiting {
+				podResourceStatus.Message = message
+				messages = append(messages,
+					fmt.Sprintf("%s: %s", podResourceStatus.Identifier.Name, message))
+			}
+		}
+	}
+	return messages
+}
+
+// podWaitingMessage returns the reasons why the containers of the Pod are
+// waiting, e.g. ImagePullBackOff, and true if any container is waiting for
+// another reason than being created.
+func podWaitingMessage(pod *unstructured.Unstructured) (string, bool) {
+	if pod == nil {
+		return "", false
+	}
+	var messages []string
+	for _, field := range []string{"initContainerStatuses", "containerStatuses"} {
+		containerStatuses, _, _ := unstructured.NestedSlice(pod.Object, "status", field)
+		for _, cs := range containerStatuses {
+			containerStatus, ok := cs.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			reason, _, _ := unstructured.NestedString(containerStatus, "state", "waiting", "reason")
+			if reason == "" || reason == "ContainerCreating" || reason == "PodInitializing" {
+				continue
+			}
+			name, _, _ := unstructured.NestedString(containerStatus, "name")
+			message := fmt.Sprintf("container %s: %s", name, reason)
+			if m, _, _ := unstructured.NestedString(containerStatus, "state", "waiting", "message"); m != "" {
+				message = fmt.Sprintf("%s: %s", message, m)
+			}
+			messages = append(messages, message)
+		}
+	}
+	if len(messages) == 0 {
+		return "", false
+	}
+	return strings.Join(messages, ", "), true
+}
@@ -5,6 +5,7 @@ package statusreaders
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"testing"
 
@@ -12,7 +13,9 @@ import (
 	"github.com/stretchr/testify/require"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	fakecr "sigs.k8s.io/cli-utils/pkg/kstatus/polling/clusterreader/fake"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/engine"
 	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/event"
 	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/statusreaders/fake"
 	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/testutil"
@@ -70,9 +73,20 @@ func TestReadStatus(t *testing.T) {
 			identifier: object.UnstructuredToObjMetadata(testutil.YamlToUnstructured(t, currentDeployment)),
 			readerErr:  errors.NewNotFound(deploymentGVR.GroupResource(), "test"),
 			expectedResourceStatus: &event.ResourceStatus{
-				Identifier: object.UnstructuredToObjMetadata(testutil.YamlToUnstructured(t, currentDeployment)),
-				Status:     status.NotFoundStatus,
-				Message:    "Resource not found",
+				Identifier:    object.UnstructuredToObjMetadata(testutil.YamlToUnstructured(t, currentDeployment)),
+				Status:        status.NotFoundStatus,
+				Message:       "Resource not found",
+				ErrorCategory: event.NotFoundError,
+			},
+		},
+		"Resource forbidden": {
+			identifier: object.UnstructuredToObjMetadata(testutil.YamlToUnstructured(t, currentDeployment)),
+			readerErr:  errors.NewForbidden(deploymentGVR.GroupResource(), "test", fmt.Errorf("access denied")),
+			expectedResourceStatus: &event.ResourceStatus{
+				Identifier:    object.UnstructuredToObjMetadata(testutil.YamlToUnstructured(t, currentDeployment)),
+				Status:        status.UnknownStatus,
+				Error:         errors.NewForbidden(deploymentGVR.GroupResource(), "test", fmt.Errorf("access denied")),
+				ErrorCategory: event.ForbiddenError,
 			},
 		},
 		"Context cancelled": {
@@ -110,3 +124,182 @@ func TestReadStatus(t *testing.T) {
 		})
 	}
 }
+
+var (
+	inProgressDeployment = strings.TrimSpace(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: test
+  generation: 1
+  namespace: qual
+spec:
+  replicas: 2
+  selector:
+    matchLabels:
+      app: app
+status:
+  observedGeneration: 1
+  updatedReplicas: 2
+  replicas: 2
+  availableReplicas: 0
+`)
+
+	stalledDeployment = strings.TrimSpace(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: test
+  generation: 1
+  namespace: qual
+spec:
+  selector:
+    matchLabels:
+      app: app
+status:
+  observedGeneration: 1
+  conditions:
+  - type: Progressing
+    status: "False"
+    reason: ProgressDeadlineExceeded
+    message: ReplicaSet "test-abc" has timed out progressing.
+`)
+
+	imagePullBackOffPod = strings.TrimSpace(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: test-abc-12345
+  namespace: qual
+status:
+  phase: Pending
+  containerStatuses:
+  - name: main
+    state:
+      waiting:
+        reason: ImagePullBackOff
+        message: Back-off pulling image "nginx:missing"
+`)
+
+	crashLoopingPod = strings.TrimSpace(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: test-abc-67890
+  namespace: qual
+status:
+  phase: Running
+  containerStatuses:
+  - name: main
+    state:
+      waiting:
+        reason: CrashLoopBackOff
+`)
+
+	containerCreatingPod = strings.TrimSpace(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: test-abc-24680
+  namespace: qual
+status:
+  phase: Pending
+  containerStatuses:
+  - name: main
+    state:
+      waiting:
+        reason: ContainerCreating
+`)
+
+	testReplicaSet = strings.TrimSpace(`
+apiVersion: apps/v1
+kind: ReplicaSet
+metadata:
+  name: test-abc
+  namespace: qual
+`)
+)
+
+func TestDeploymentRolloutFailureReasons(t *testing.T) {
+	testCases := map[string]struct {
+		deployment      string
+		pods            []string
+		expectedStatus  status.Status
+		expectedMessage string
+	}{
+		"image pull failure": {
+			deployment:     inProgressDeployment,
+			pods:           []string{imagePullBackOffPod, containerCreatingPod},
+			expectedStatus: status.InProgressStatus,
+			expectedMessage: "Available: 0/2. 1 pods are not ready: test-abc-12345: " +
+				`container main: ImagePullBackOff: Back-off pulling image "nginx:missing"`,
+		},
+		"progress deadline exceeded": {
+			deployment:     stalledDeployment,
+			pods:           []string{crashLoopingPod},
+			expectedStatus: status.FailedStatus,
+			expectedMessage: `Progress deadline exceeded: ReplicaSet "test-abc" has timed out progressing. ` +
+				"1 pods are not ready: test-abc-67890: Containers in CrashLoop state: main",
+		},
+		"pods still being created": {
+			deployment:      inProgressDeployment,
+			pods:            []string{containerCreatingPod},
+			expectedStatus:  status.InProgressStatus,
+			expectedMessage: "Available: 0/2",
+		},
+		"current deployment": {
+			deployment:      currentDeployment,
+			pods:            []string{imagePullBackOffPod},
+			expectedStatus:  status.CurrentStatus,
+			expectedMessage: "Deployment is available. Replicas: 1",
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			var podStatuses event.ResourceStatuses
+			for _, pod := range tc.pods {
+				u := testutil.YamlToUnstructured(t, pod)
+				res, err := status.Compute(u)
+				require.NoError(t, err)
+				podStatuses = append(podStatuses, &event.ResourceStatus{
+					Identifier: object.UnstructuredToObjMetadata(u),
+					Status:     res.Status,
+					Resource:   u,
+					Message:    res.Message,
+				})
+			}
+			fakeReader := &fakecr.ClusterReader{
+				ListResources: &unstructured.UnstructuredList{
+					Items: []unstructured.Unstructured{*testutil.YamlToUnstructured(t, testReplicaSet)},
+				},
+			}
+			fakeMapper := fakemapper.NewFakeRESTMapper(deploymentGVK, replicaSetGVK)
+			statusReader := NewDeploymentResourceReader(fakeMapper, &fakeReplicaSetStatusReader{pods: podStatuses})
+
+			rs, err := statusReader.ReadStatusForObject(context.Background(), fakeReader,
+				testutil.YamlToUnstructured(t, tc.deployment))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, rs.Status)
+			assert.Equal(t, tc.expectedMessage, rs.Message)
+		})
+	}
+}
+
+// fakeReplicaSetStatusReader returns the same Pods for every ReplicaSet.
+type fakeReplicaSetStatusReader struct {
+	pods event.ResourceStatuses
+}
+
+func (f *fakeReplicaSetStatusReader) Supports(schema.GroupKind) bool {
+	return true
+}
+
+func (f *fakeReplicaSetStatusReader) ReadStatusForObject(_ context.Context, _ engine.ClusterReader, rs *unstructured.Unstructured) (*event.ResourceStatus, error) {
+	return &event.ResourceStatus{
+		Identifier:         object.UnstructuredToObjMetadata(rs),
+		Status:             status.InProgressStatus,
+		Resource:           rs,
+		GeneratedResources: f.pods,
+	}, nil
+}
@@ -51,7 +51,7 @@ func (g *genericStatusReader) Supports(schema.GroupKind) bool {
 func (g *genericStatusReader) ReadStatusForObject(_ context.Context, _ engine.ClusterReader, resource *unstructured.Unstructured) (*event.ResourceStatus, error) {
 	identifier := object.UnstructuredToObjMetadata(resource)
 
-	res, err := g.statusFunc(resource)
+	res, err := computeStatus(g.statusFunc, resource)
 	if err != nil {
 		return errResourceToResourceStatus(err, resource)
 	}
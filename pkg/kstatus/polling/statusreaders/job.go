@@ -0,0 +1,171 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package statusreaders
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/engine"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/event"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/status"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+func NewJobStatusReader(mapper meta.RESTMapper, podStatusReader resourceTypeStatusReader) engine.StatusReader {
+	return &baseStatusReader{
+		mapper: mapper,
+		resourceStatusReader: &jobStatusReader{
+			mapper:          mapper,
+			podStatusReader: podStatusReader,
+		},
+	}
+}
+
+// jobStatusReader is an engine that can fetch Job resources from the
+// cluster, knows how to find any Pods belonging to the Job, and compute
+// status for the Job. Unlike the status library, which considers a running
+// Job as Current, the Job is InProgress until it has completed, and Failed
+// once the Job controller has given up on it, e.g. because the backoffLimit
+// has been exceeded.
+type jobStatusReader struct {
+	mapper meta.RESTMapper
+
+	podStatusReader resourceTypeStatusReader
+}
+
+var _ resourceTypeStatusReader = &jobStatusReader{}
+
+func (j *jobStatusReader) Supports(gk schema.GroupKind) bool {
+	return gk == batchv1.SchemeGroupVersion.WithKind("Job").GroupKind()
+}
+
+func (j *jobStatusReader) ReadStatusForObject(ctx context.Context, reader engine.ClusterReader, job *unstructured.Unstructured) (*event.ResourceStatus, error) {
+	identifier := object.UnstructuredToObjMetadata(job)
+
+	// The Job controller sets the selector of the Job, so it might not be
+	// set yet if the Job has just been created.
+	var podResourceStatuses event.ResourceStatuses
+	if _, found, _ := unstructured.NestedMap(job.Object, "spec", "selector"); found {
+		var err error
+		podResourceStatuses, err = statusForGeneratedResources(ctx, j.mapper, reader, j.podStatusReader, job,
+			schema.GroupKind{Group: "", Kind: "Pod"}, "spec", "selector")
+		if err != nil {
+			return errResourceToResourceStatus(err, job)
+		}
+	}
+	// Pods that have failed are Current for the status library, since they
+	// have completed, so the failures are found in the Pods themselves.
+	var failedPodMessages []string
+	for _, podResourceStatus := range podResourceStatuses {
+		if message, failed := podFailureMessage(podResourceStatus.Resource); failed {
+			podResourceStatus.Message = message
+			failedPodMessages = append(failedPodMessages,
+				fmt.Sprintf("%s: %s", podResourceStatus.Identifier.Name, message))
+		}
+	}
+
+	res, err := status.Compute(job)
+	if err != nil {
+		return errResourceToResourceStatus(err, job, podResourceStatuses...)
+	}
+	res, err = jobStatus(job, res)
+	if err != nil {
+		return errResourceToResourceStatus(err, job, podResourceStatuses...)
+	}
+
+	message := res.Message
+	if res.Status != status.CurrentStatus && len(failedPodMessages) > 0 {
+		message = fmt.Sprintf("%s. %d pods have failed: %s", message, len(failedPodMessages),
+			strings.Join(failedPodMessages, "; "))
+	}
+	return &event.ResourceStatus{
+		Identifier:         identifier,
+		Status:             res.Status,
+		Resource:           job,
+		Message:            message,
+		GeneratedResources: podResourceStatuses,
+	}, nil
+}
+
+// jobStatus refines the result of the status library for the Job. The Job is
+// Current once it has the Complete condition, and Failed with the Failed
+// condition. Otherwise, it is InProgress.
+func jobStatus(job *unstructured.Unstructured, res *status.Result) (*status.Result, error) {
+	if res.Status != status.CurrentStatus && res.Status != status.FailedStatus {
+		return res, nil
+	}
+	obj := job.UnstructuredContent()
+	objc, err := status.GetObjectWithConditions(obj)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range objc.Status.Conditions {
+		if c.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch c.Type {
+		case string(batchv1.JobComplete):
+			return res, nil
+		case string(batchv1.JobFailed):
+			message := fmt.Sprintf("Job failed: %s", c.Reason)
+			if c.Message != "" {
+				message = fmt.Sprintf("%s: %s", message, c.Message)
+			}
+			return &status.Result{
+				Status:  status.FailedStatus,
+				Message: message,
+			}, nil
+		}
+	}
+	return &status.Result{
+		Status: status.InProgressStatus,
+		Message: fmt.Sprintf("Job in progress. succeeded: %d, active: %d, failed: %d",
+			status.GetIntField(obj, ".status.succeeded", 0),
+			status.GetIntField(obj, ".status.active", 0),
+			status.GetIntField(obj, ".status.failed", 0)),
+	}, nil
+}
+
+// podFailureMessage returns the reason why the Pod has failed, and true if the
+// Pod is in the Failed phase.
+func podFailureMessage(pod *unstructured.Unstructured) (string, bool) {
+	if pod == nil {
+		return "", false
+	}
+	obj := pod.UnstructuredContent()
+	if status.GetStringField(obj, ".status.phase", "") != string(corev1.PodFailed) {
+		return "", false
+	}
+	if message := status.GetStringField(obj, ".status.message", ""); message != "" {
+		return message, true
+	}
+	if reason := status.GetStringField(obj, ".status.reason", ""); reason != "" {
+		return reason, true
+	}
+	containerStatuses, _, _ := unstructured.NestedSlice(obj, "status", "containerStatuses")
+	for _, cs := range containerStatuses {
+		containerStatus, ok := cs.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		exitCode, found, _ := unstructured.NestedInt64(containerStatus, "state", "terminated", "exitCode")
+		if !found || exitCode == 0 {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(containerStatus, "name")
+		message := fmt.Sprintf("container %s terminated with exit code %d", name, exitCode)
+		if reason, _, _ := unstructured.NestedString(containerStatus, "state", "terminated", "reason"); reason != "" {
+			message = fmt.Sprintf("%s (%s)", message, reason)
+		}
+		return message, true
+	}
+	return "Pod has failed", true
+}
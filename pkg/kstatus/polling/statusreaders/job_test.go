@@ -0,0 +1,157 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package statusreaders
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	fakecr "sigs.k8s.io/cli-utils/pkg/kstatus/polling/clusterreader/fake"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/testutil"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/status"
+	fakemapper "sigs.k8s.io/cli-utils/pkg/testutil"
+)
+
+var (
+	jobGVK = batchv1.SchemeGroupVersion.WithKind("Job")
+	podGVK = corev1.SchemeGroupVersion.WithKind("Pod")
+
+	runningJob = strings.TrimSpace(`
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: test
+  generation: 1
+  namespace: qual
+spec:
+  backoffLimit: 1
+  selector:
+    matchLabels:
+      controller-uid: abc
+status:
+  startTime: "2024-05-01T12:00:00Z"
+  active: 1
+  failed: 1
+`)
+
+	completeJob = strings.TrimSpace(`
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: test
+  generation: 1
+  namespace: qual
+spec:
+  selector:
+    matchLabels:
+      controller-uid: abc
+status:
+  startTime: "2024-05-01T12:00:00Z"
+  succeeded: 1
+  conditions:
+  - type: Complete
+    status: "True"
+`)
+
+	failedJob = strings.TrimSpace(`
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: test
+  generation: 1
+  namespace: qual
+spec:
+  backoffLimit: 1
+  selector:
+    matchLabels:
+      controller-uid: abc
+status:
+  startTime: "2024-05-01T12:00:00Z"
+  failed: 2
+  conditions:
+  - type: Failed
+    status: "True"
+    reason: BackoffLimitExceeded
+    message: Job has reached the specified backoff limit
+`)
+
+	failedJobPod = strings.TrimSpace(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: test-abcde
+  namespace: qual
+  labels:
+    controller-uid: abc
+status:
+  phase: Failed
+  containerStatuses:
+  - name: main
+    state:
+      terminated:
+        exitCode: 2
+        reason: Error
+`)
+)
+
+func TestJobStatusReader(t *testing.T) {
+	testCases := map[string]struct {
+		job             string
+		pods            []string
+		expectedStatus  status.Status
+		expectedMessage string
+	}{
+		"running job": {
+			job:             runningJob,
+			expectedStatus:  status.InProgressStatus,
+			expectedMessage: "Job in progress. succeeded: 0, active: 1, failed: 1",
+		},
+		"running job with failed pod": {
+			job:            runningJob,
+			pods:           []string{failedJobPod},
+			expectedStatus: status.InProgressStatus,
+			expectedMessage: "Job in progress. succeeded: 0, active: 1, failed: 1. " +
+				"1 pods have failed: test-abcde: container main terminated with exit code 2 (Error)",
+		},
+		"complete job": {
+			job:             completeJob,
+			expectedStatus:  status.CurrentStatus,
+			expectedMessage: "Job Completed. succeeded: 1/1",
+		},
+		"failed job": {
+			job:            failedJob,
+			pods:           []string{failedJobPod},
+			expectedStatus: status.FailedStatus,
+			expectedMessage: "Job failed: BackoffLimitExceeded: Job has reached the specified backoff limit. " +
+				"1 pods have failed: test-abcde: container main terminated with exit code 2 (Error)",
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			pods := &unstructured.UnstructuredList{}
+			for _, pod := range tc.pods {
+				pods.Items = append(pods.Items, *testutil.YamlToUnstructured(t, pod))
+			}
+			fakeReader := &fakecr.ClusterReader{
+				ListResources: pods,
+			}
+			fakeMapper := fakemapper.NewFakeRESTMapper(jobGVK, podGVK)
+			statusReader := NewJobStatusReader(fakeMapper, NewGenericStatusReader(fakeMapper, status.Compute))
+
+			rs, err := statusReader.ReadStatusForObject(context.Background(), fakeReader,
+				testutil.YamlToUnstructured(t, tc.job))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, rs.Status)
+			assert.Equal(t, tc.expectedMessage, rs.Message)
+			assert.Len(t, rs.GeneratedResources, len(tc.pods))
+		})
+	}
+}
@@ -6,6 +6,7 @@ package statusreaders
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -51,7 +52,7 @@ func (p *podControllerStatusReader) readStatus(ctx context.Context, reader engin
 		return errResourceToResourceStatus(err, obj)
 	}
 
-	res, err := p.statusFunc(obj)
+	res, err := computeStatus(p.statusFunc, obj)
 	if err != nil {
 		return errResourceToResourceStatus(err, obj, podResourceStatuses...)
 	}
@@ -59,8 +60,9 @@ func (p *podControllerStatusReader) readStatus(ctx context.Context, reader engin
 	// If the status comes back as pending, we take a look at the pods to make sure
 	// none of them are in the failed state. If at least one of them are, then
 	// it is unlikely (but not impossible) that the status of the PodController will become
-	// Current without some kind of intervention.
-	if res.Status == status.InProgressStatus {
+	// Current without some kind of intervention. Unless the controller has
+	// not observed the latest generation yet, which may replace the pods.
+	if res.Status == status.InProgressStatus && !status.IsLatestGenerationNotObserved(res) {
 		var failedPods []*event.ResourceStatus
 		for _, podResourceStatus := range podResourceStatuses {
 			if podResourceStatus.Status == status.FailedStatus {
@@ -72,7 +74,7 @@ func (p *podControllerStatusReader) readStatus(ctx context.Context, reader engin
 				Identifier:         identifier,
 				Status:             status.FailedStatus,
 				Resource:           obj,
-				Message:            fmt.Sprintf("%d pods have failed", len(failedPods)),
+				Message:            failedPodsMessage(failedPods),
 				GeneratedResources: podResourceStatuses,
 			}, nil
 		}
@@ -86,3 +88,20 @@ func (p *podControllerStatusReader) readStatus(ctx context.Context, reader engin
 		GeneratedResources: podResourceStatuses,
 	}, nil
 }
+
+// failedPodsMessage summarizes the failures of the pods, so the messages of
+// the pods are visible without drilling down into the generated resources.
+func failedPodsMessage(failedPods []*event.ResourceStatus) string {
+	messages := make([]string, 0, len(failedPods))
+	for _, pod := range failedPods {
+		if pod.Message == "" {
+			continue
+		}
+		messages = append(messages, fmt.Sprintf("%s: %s", pod.Identifier.Name, pod.Message))
+	}
+	message := fmt.Sprintf("%d pods have failed", len(failedPods))
+	if len(messages) == 0 {
+		return message
+	}
+	return fmt.Sprintf("%s: %s", message, strings.Join(messages, "; "))
+}
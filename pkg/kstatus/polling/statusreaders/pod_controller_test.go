@@ -27,11 +27,14 @@ func TestPodControllerStatusReader(t *testing.T) {
 	namespace := "Bar"
 
 	testCases := map[string]struct {
+		generation          int64
+		observedGeneration  int64
 		computeStatusResult *status.Result
 		computeStatusErr    error
 		genResourceStatuses event.ResourceStatuses
 		expectedIdentifier  object.ObjMetadata
 		expectedStatus      status.Status
+		expectedMessage     string
 	}{
 		"successfully computes status": {
 			computeStatusResult: &status.Result{
@@ -43,7 +46,8 @@ func TestPodControllerStatusReader(t *testing.T) {
 				Name:      name,
 				Namespace: namespace,
 			},
-			expectedStatus: status.InProgressStatus,
+			expectedStatus:  status.InProgressStatus,
+			expectedMessage: "this is a test",
 		},
 		"computing status fails": {
 			computeStatusErr: fmt.Errorf("this error is a test"),
@@ -67,7 +71,13 @@ func TestPodControllerStatusReader(t *testing.T) {
 					Status: status.InProgressStatus,
 				},
 				{
-					Status: status.FailedStatus,
+					Identifier: object.ObjMetadata{Name: "Foo-0"},
+					Status:     status.FailedStatus,
+					Message:    "Containers in CrashLoop state: main",
+				},
+				{
+					Identifier: object.ObjMetadata{Name: "Foo-1"},
+					Status:     status.FailedStatus,
 				},
 			},
 			expectedIdentifier: object.ObjMetadata{
@@ -75,7 +85,30 @@ func TestPodControllerStatusReader(t *testing.T) {
 				Name:      name,
 				Namespace: namespace,
 			},
-			expectedStatus: status.FailedStatus,
+			expectedStatus:  status.FailedStatus,
+			expectedMessage: "2 pods have failed: Foo-0: Containers in CrashLoop state: main",
+		},
+		"latest generation not observed": {
+			generation:         2,
+			observedGeneration: 1,
+			computeStatusResult: &status.Result{
+				Status:  status.CurrentStatus,
+				Message: "this is a test",
+			},
+			genResourceStatuses: event.ResourceStatuses{
+				{
+					Identifier: object.ObjMetadata{Name: "Foo-0"},
+					Status:     status.FailedStatus,
+					Message:    "Containers in CrashLoop state: main",
+				},
+			},
+			expectedIdentifier: object.ObjMetadata{
+				GroupKind: rsGVK.GroupKind(),
+				Name:      name,
+				Namespace: namespace,
+			},
+			expectedStatus:  status.InProgressStatus,
+			expectedMessage: "ReplicaSet generation is 2, but latest observed generation is 1",
 		},
 	}
 
@@ -95,12 +128,20 @@ func TestPodControllerStatusReader(t *testing.T) {
 			rs.SetGroupVersionKind(rsGVK)
 			rs.SetName(name)
 			rs.SetNamespace(namespace)
+			if tc.generation != 0 {
+				rs.SetGeneration(tc.generation)
+				err := unstructured.SetNestedField(rs.Object, tc.observedGeneration, "status", "observedGeneration")
+				assert.NoError(t, err)
+			}
 
 			resourceStatus, err := podControllerStatusReader.readStatus(context.Background(), fakeReader, rs)
 
 			assert.NoError(t, err)
 			assert.Equal(t, tc.expectedIdentifier, resourceStatus.Identifier)
 			assert.Equal(t, tc.expectedStatus, resourceStatus.Status)
+			if tc.expectedMessage != "" {
+				assert.Equal(t, tc.expectedMessage, resourceStatus.Message)
+			}
 		})
 	}
 }
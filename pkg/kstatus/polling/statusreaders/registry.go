@@ -0,0 +1,100 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package statusreaders
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/engine"
+)
+
+// StatusReaderFactory creates the StatusReader of a GroupKind, with the
+// mapper of the StatusPoller or StatusWatcher.
+type StatusReaderFactory func(mapper meta.RESTMapper) engine.StatusReader
+
+// Registry maps GroupKinds, typically of CRDs, to the StatusReaders that
+// compute their status. The StatusReaders of the registry are preferred
+// over the built-in StatusReaders, and over the generic conditions-based
+// StatusReader. A Registry is safe for concurrent use.
+type Registry struct {
+	mx        sync.RWMutex
+	factories map[schema.GroupKind]StatusReaderFactory
+	// order keeps the GroupKinds in registration order, so that the
+	// StatusReaders are always created in the same order.
+	order []schema.GroupKind
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		factories: make(map[schema.GroupKind]StatusReaderFactory),
+	}
+}
+
+// DefaultRegistry is the Registry used by the StatusPoller and the
+// StatusWatcher, unless another Registry is configured.
+var DefaultRegistry = NewRegistry()
+
+// Register registers the StatusReader of the GroupKind in the
+// DefaultRegistry.
+func Register(gk schema.GroupKind, factory StatusReaderFactory) {
+	DefaultRegistry.Register(gk, factory)
+}
+
+// Register registers the StatusReader of the GroupKind, replacing the
+// StatusReader registered before for the same GroupKind, if any. The created
+// StatusReader is only used for the GroupKind, even if it supports others.
+func (r *Registry) Register(gk schema.GroupKind, factory StatusReaderFactory) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+	if _, found := r.factories[gk]; !found {
+		r.order = append(r.order, gk)
+	}
+	r.factories[gk] = factory
+}
+
+// Unregister removes the StatusReader of the GroupKind, if any.
+func (r *Registry) Unregister(gk schema.GroupKind) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+	if _, found := r.factories[gk]; !found {
+		return
+	}
+	delete(r.factories, gk)
+	for i, o := range r.order {
+		if o == gk {
+			r.order = append(r.order[:i], r.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// StatusReaders creates the registered StatusReaders, in registration order.
+func (r *Registry) StatusReaders(mapper meta.RESTMapper) []engine.StatusReader {
+	if r == nil {
+		return nil
+	}
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+	statusReaders := make([]engine.StatusReader, 0, len(r.order))
+	for _, gk := range r.order {
+		statusReaders = append(statusReaders, &groupKindStatusReader{
+			groupKind:    gk,
+			StatusReader: r.factories[gk](mapper),
+		})
+	}
+	return statusReaders
+}
+
+// groupKindStatusReader restricts a StatusReader to a single GroupKind.
+type groupKindStatusReader struct {
+	engine.StatusReader
+	groupKind schema.GroupKind
+}
+
+func (g *groupKindStatusReader) Supports(gk schema.GroupKind) bool {
+	return gk == g.groupKind
+}
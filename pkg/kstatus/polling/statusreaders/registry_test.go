@@ -0,0 +1,68 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package statusreaders
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakecr "sigs.k8s.io/cli-utils/pkg/kstatus/polling/clusterreader/fake"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/engine"
+	fakesr "sigs.k8s.io/cli-utils/pkg/kstatus/polling/statusreaders/fake"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/status"
+	"sigs.k8s.io/cli-utils/pkg/testutil"
+)
+
+var databaseGVK = schema.GroupVersionKind{Group: "db.example.com", Version: "v1", Kind: "Database"}
+
+func newFakeStatusReader(meta.RESTMapper) engine.StatusReader {
+	return &fakesr.StatusReader{}
+}
+
+func TestRegistry(t *testing.T) {
+	mapper := testutil.NewFakeRESTMapper(databaseGVK)
+	registry := NewRegistry()
+	assert.Empty(t, registry.StatusReaders(mapper))
+
+	registry.Register(databaseGVK.GroupKind(), newFakeStatusReader)
+	statusReaders := registry.StatusReaders(mapper)
+	require.Len(t, statusReaders, 1)
+	// The StatusReader only supports the registered GroupKind.
+	assert.True(t, statusReaders[0].Supports(databaseGVK.GroupKind()))
+	assert.False(t, statusReaders[0].Supports(deploymentGVK.GroupKind()))
+
+	// Registering the same GroupKind again replaces the StatusReader.
+	registry.Register(databaseGVK.GroupKind(), newFakeStatusReader)
+	assert.Len(t, registry.StatusReaders(mapper), 1)
+
+	registry.Unregister(databaseGVK.GroupKind())
+	assert.Empty(t, registry.StatusReaders(mapper))
+}
+
+func TestNewStatusReaderPrefersRegisteredStatusReaders(t *testing.T) {
+	mapper := testutil.NewFakeRESTMapper(databaseGVK)
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(databaseGVK)
+	obj.SetNamespace("default")
+	obj.SetName("db")
+
+	// Without a registered StatusReader, the generic StatusReader computes
+	// the status from the conditions.
+	resourceStatus, err := NewStatusReader(mapper).ReadStatusForObject(context.Background(), fakecr.NewNoopClusterReader(), obj)
+	require.NoError(t, err)
+	assert.Equal(t, status.CurrentStatus, resourceStatus.Status)
+
+	Register(databaseGVK.GroupKind(), newFakeStatusReader)
+	defer DefaultRegistry.Unregister(databaseGVK.GroupKind())
+
+	// The fake StatusReader does not compute any status.
+	resourceStatus, err = NewStatusReader(mapper).ReadStatusForObject(context.Background(), fakecr.NewNoopClusterReader(), obj)
+	require.NoError(t, err)
+	assert.Equal(t, status.Status(""), resourceStatus.Status)
+}
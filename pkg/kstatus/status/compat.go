@@ -0,0 +1,189 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package status
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// healthCheckTypes defines the mapping from GroupKind to a function that
+// computes the status of popular custom resources that do not follow the
+// status conventions, like the built-in health checks of Argo CD.
+var healthCheckTypes = map[string]GetConditionsFn{
+	"cert-manager.io/Certificate":   certificateHealth,
+	"cert-manager.io/Issuer":        issuerHealth,
+	"cert-manager.io/ClusterIssuer": issuerHealth,
+	"kafka.strimzi.io/Kafka":        strimziHealth,
+	"kafka.strimzi.io/KafkaTopic":   strimziHealth,
+	"kafka.strimzi.io/KafkaUser":    strimziHealth,
+	"kafka.strimzi.io/KafkaConnect": strimziHealth,
+	"argoproj.io/Rollout":           rolloutHealth,
+}
+
+// ComputeCompatible finds the status of the resource like Compute, but also
+// honors the health conventions of other tools, e.g. Flux and Argo CD, so
+// that mixed toolchains agree on the readiness of resources:
+//   - the health checks of popular custom resources, e.g. of cert-manager,
+//     Strimzi and Argo Rollouts, similar to the ones built into Argo CD
+//   - a Degraded condition that is True means the resource has Failed
+//   - a Healthy condition is honored like a Ready condition, except that a
+//     resource that is not Healthy has Failed
+//
+// These conventions only apply to the resources whose status is not decided
+// by the standard conditions, the status rules or the built-in types.
+func ComputeCompatible(u *unstructured.Unstructured) (*Result, error) {
+	res, err := computeWith(u, checkCompatibleHealth)
+	if err != nil {
+		return nil, err
+	}
+	return checkSuspended(u, res)
+}
+
+// checkCompatibleHealth computes the status of the resource from the health
+// conventions of other tools. Returns nil if none of them apply.
+func checkCompatibleHealth(u *unstructured.Unstructured) (*Result, error) {
+	gk := u.GroupVersionKind().GroupKind()
+	if fn, found := healthCheckTypes[gk.Group+"/"+gk.Kind]; found {
+		return fn(u)
+	}
+
+	objWithConditions, err := GetObjectWithConditions(u.Object)
+	if err != nil {
+		return nil, err
+	}
+	conditions := objWithConditions.Status.Conditions
+	if c, found := getConditionWithStatus(conditions, "Degraded", corev1.ConditionTrue); found {
+		return newFailedStatus(c.Reason, conditionMessage(c, "Resource is degraded")), nil
+	}
+	for _, c := range conditions {
+		if c.Type != "Healthy" {
+			continue
+		}
+		switch c.Status {
+		case corev1.ConditionTrue:
+			return &Result{
+				Status:     CurrentStatus,
+				Message:    "Resource is Healthy",
+				Conditions: []Condition{},
+			}, nil
+		case corev1.ConditionFalse:
+			return newFailedStatus(c.Reason, conditionMessage(c, "Resource is not healthy")), nil
+		case corev1.ConditionUnknown:
+			return newInProgressStatus(c.Reason, c.Message), nil
+		}
+	}
+	return nil, nil
+}
+
+// certificateHealth computes the status of cert-manager Certificates.
+func certificateHealth(u *unstructured.Unstructured) (*Result, error) {
+	objWithConditions, err := GetObjectWithConditions(u.Object)
+	if err != nil {
+		return nil, err
+	}
+	conditions := objWithConditions.Status.Conditions
+	if c, found := getConditionWithStatus(conditions, "Issuing", corev1.ConditionTrue); found {
+		return newInProgressStatus(c.Reason, conditionMessage(c, "Certificate is being issued")), nil
+	}
+	if c, found := getConditionWithStatus(conditions, "Ready", corev1.ConditionTrue); found {
+		return &Result{
+			Status:     CurrentStatus,
+			Message:    conditionMessage(c, "Certificate is Ready"),
+			Conditions: []Condition{},
+		}, nil
+	}
+	if c, found := getConditionWithStatus(conditions, "Ready", corev1.ConditionFalse); found {
+		return newFailedStatus(c.Reason, conditionMessage(c, "Certificate is not Ready")), nil
+	}
+	return newInProgressStatus("Waiting", "Waiting for the certificate"), nil
+}
+
+// issuerHealth computes the status of cert-manager Issuers and
+// ClusterIssuers.
+func issuerHealth(u *unstructured.Unstructured) (*Result, error) {
+	objWithConditions, err := GetObjectWithConditions(u.Object)
+	if err != nil {
+		return nil, err
+	}
+	conditions := objWithConditions.Status.Conditions
+	if c, found := getConditionWithStatus(conditions, "Ready", corev1.ConditionTrue); found {
+		return &Result{
+			Status:     CurrentStatus,
+			Message:    conditionMessage(c, "Issuer is Ready"),
+			Conditions: []Condition{},
+		}, nil
+	}
+	if c, found := getConditionWithStatus(conditions, "Ready", corev1.ConditionFalse); found {
+		return newFailedStatus(c.Reason, conditionMessage(c, "Issuer is not Ready")), nil
+	}
+	return newInProgressStatus("Initializing", "Initializing the issuer"), nil
+}
+
+// strimziHealth computes the status of the Strimzi Kafka resources.
+func strimziHealth(u *unstructured.Unstructured) (*Result, error) {
+	objWithConditions, err := GetObjectWithConditions(u.Object)
+	if err != nil {
+		return nil, err
+	}
+	conditions := objWithConditions.Status.Conditions
+	if c, found := getConditionWithStatus(conditions, "NotReady", corev1.ConditionTrue); found {
+		return newInProgressStatus(c.Reason, conditionMessage(c, "Resource is not Ready")), nil
+	}
+	if c, found := getConditionWithStatus(conditions, "Ready", corev1.ConditionTrue); found {
+		return &Result{
+			Status:     CurrentStatus,
+			Message:    conditionMessage(c, "Resource is Ready"),
+			Conditions: []Condition{},
+		}, nil
+	}
+	if c, found := getConditionWithStatus(conditions, "Ready", corev1.ConditionFalse); found {
+		return newFailedStatus(c.Reason, conditionMessage(c, "Resource is not Ready")), nil
+	}
+	return newInProgressStatus("Waiting", fmt.Sprintf("Waiting for the %s", u.GetKind())), nil
+}
+
+// rolloutHealth computes the status of Argo Rollouts from their phase.
+// Paused Rollouts wait for a promotion, so they are Suspended.
+func rolloutHealth(u *unstructured.Unstructured) (*Result, error) {
+	phase := GetStringField(u.Object, ".status.phase", "")
+	message := GetStringField(u.Object, ".status.message", "")
+	switch phase {
+	case "Healthy":
+		return &Result{
+			Status:     CurrentStatus,
+			Message:    "Rollout is Healthy",
+			Conditions: []Condition{},
+		}, nil
+	case "Degraded":
+		return newFailedStatus("Degraded", appendMessage("Rollout is degraded", message)), nil
+	case "Paused":
+		return &Result{
+			Status:     SuspendedStatus,
+			Message:    appendMessage("Rollout is paused", message),
+			Conditions: []Condition{},
+		}, nil
+	default:
+		return newInProgressStatus("Progressing", appendMessage("Rollout is progressing", message)), nil
+	}
+}
+
+// appendMessage appends the message, if any, to the summary.
+func appendMessage(summary, message string) string {
+	if message == "" {
+		return summary
+	}
+	return fmt.Sprintf("%s: %s", summary, message)
+}
+
+// conditionMessage returns the message of the condition, or the default
+// message if the condition has none.
+func conditionMessage(c BasicCondition, defaultMessage string) string {
+	if c.Message != "" {
+		return c.Message
+	}
+	return defaultMessage
+}
@@ -0,0 +1,249 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package status
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var certificateIssuing = `
+apiVersion: cert-manager.io/v1
+kind: Certificate
+metadata:
+   name: test
+   namespace: qual
+   generation: 1
+status:
+   conditions:
+    - type: Ready
+      status: "False"
+      reason: DoesNotExist
+      message: Issuing certificate as Secret does not exist
+    - type: Issuing
+      status: "True"
+      reason: DoesNotExist
+      message: Issuing certificate as Secret does not exist
+`
+
+var certificateFailed = `
+apiVersion: cert-manager.io/v1
+kind: Certificate
+metadata:
+   name: test
+   namespace: qual
+   generation: 1
+status:
+   conditions:
+    - type: Ready
+      status: "False"
+      reason: Failed
+      message: The certificate request has failed to complete
+`
+
+var certificateReady = `
+apiVersion: cert-manager.io/v1
+kind: Certificate
+metadata:
+   name: test
+   namespace: qual
+   generation: 1
+status:
+   conditions:
+    - type: Ready
+      status: "True"
+      reason: Ready
+      message: Certificate is up to date and has not expired
+`
+
+var kafkaNotReady = `
+apiVersion: kafka.strimzi.io/v1beta2
+kind: Kafka
+metadata:
+   name: test
+   namespace: qual
+   generation: 1
+status:
+   observedGeneration: 1
+   conditions:
+    - type: NotReady
+      status: "True"
+      reason: Creating
+      message: Kafka cluster is being deployed
+`
+
+var rolloutPaused = `
+apiVersion: argoproj.io/v1alpha1
+kind: Rollout
+metadata:
+   name: test
+   namespace: qual
+   generation: 1
+status:
+   phase: Paused
+   message: CanaryPauseStep
+`
+
+var rolloutDegraded = `
+apiVersion: argoproj.io/v1alpha1
+kind: Rollout
+metadata:
+   name: test
+   namespace: qual
+   generation: 1
+status:
+   phase: Degraded
+   message: ProgressDeadlineExceeded
+`
+
+var customResourceDegraded = `
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+   name: test
+   namespace: qual
+   generation: 1
+status:
+   observedGeneration: 1
+   conditions:
+    - type: Degraded
+      status: "True"
+      reason: BackendDown
+      message: The backend is down
+    - type: Ready
+      status: "True"
+`
+
+var customResourceHealthy = `
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+   name: test
+   namespace: qual
+   generation: 1
+status:
+   observedGeneration: 1
+   conditions:
+    - type: Healthy
+      status: "True"
+`
+
+var customResourceUnhealthy = `
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+   name: test
+   namespace: qual
+   generation: 1
+status:
+   observedGeneration: 1
+   conditions:
+    - type: Healthy
+      status: "False"
+      reason: Unhealthy
+      message: 2 replicas are unhealthy
+`
+
+var customResourceReconcilingHealthy = `
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+   name: test
+   namespace: qual
+   generation: 1
+status:
+   observedGeneration: 1
+   conditions:
+    - type: Reconciling
+      status: "True"
+      reason: Progressing
+    - type: Healthy
+      status: "True"
+`
+
+func TestComputeCompatible(t *testing.T) {
+	testCases := map[string]struct {
+		spec            string
+		expectedStatus  Status
+		expectedMessage string
+		// computeStatus is the status computed by Compute, which ignores
+		// the health conventions.
+		computeStatus Status
+	}{
+		"certificateIssuing": {
+			spec:            certificateIssuing,
+			expectedStatus:  InProgressStatus,
+			expectedMessage: "Issuing certificate as Secret does not exist",
+			computeStatus:   InProgressStatus,
+		},
+		"certificateFailed": {
+			spec:            certificateFailed,
+			expectedStatus:  FailedStatus,
+			expectedMessage: "The certificate request has failed to complete",
+			computeStatus:   InProgressStatus,
+		},
+		"certificateReady": {
+			spec:            certificateReady,
+			expectedStatus:  CurrentStatus,
+			expectedMessage: "Certificate is up to date and has not expired",
+			computeStatus:   CurrentStatus,
+		},
+		"kafkaNotReady": {
+			spec:            kafkaNotReady,
+			expectedStatus:  InProgressStatus,
+			expectedMessage: "Kafka cluster is being deployed",
+			computeStatus:   CurrentStatus,
+		},
+		"rolloutPaused": {
+			spec:            rolloutPaused,
+			expectedStatus:  SuspendedStatus,
+			expectedMessage: "Rollout is paused: CanaryPauseStep",
+			computeStatus:   CurrentStatus,
+		},
+		"rolloutDegraded": {
+			spec:            rolloutDegraded,
+			expectedStatus:  FailedStatus,
+			expectedMessage: "Rollout is degraded: ProgressDeadlineExceeded",
+			computeStatus:   CurrentStatus,
+		},
+		"customResourceDegraded": {
+			spec:            customResourceDegraded,
+			expectedStatus:  FailedStatus,
+			expectedMessage: "The backend is down",
+			computeStatus:   CurrentStatus,
+		},
+		"customResourceHealthy": {
+			spec:            customResourceHealthy,
+			expectedStatus:  CurrentStatus,
+			expectedMessage: "Resource is Healthy",
+			computeStatus:   CurrentStatus,
+		},
+		"customResourceUnhealthy": {
+			spec:            customResourceUnhealthy,
+			expectedStatus:  FailedStatus,
+			expectedMessage: "2 replicas are unhealthy",
+			computeStatus:   CurrentStatus,
+		},
+		"customResourceReconcilingHealthy": {
+			spec:           customResourceReconcilingHealthy,
+			expectedStatus: InProgressStatus,
+			computeStatus:  InProgressStatus,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			res, err := ComputeCompatible(y2u(t, tc.spec))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, res.Status)
+			assert.Equal(t, tc.expectedMessage, res.Message)
+
+			res, err = Compute(y2u(t, tc.spec))
+			require.NoError(t, err)
+			assert.Equal(t, tc.computeStatus, res.Status)
+		})
+	}
+}
@@ -20,21 +20,24 @@ type GetConditionsFn func(*unstructured.Unstructured) (*Result, error)
 // legacyTypes defines the mapping from GroupKind to a function that can
 // compute the status for the given resource.
 var legacyTypes = map[string]GetConditionsFn{
-	"Service":                    serviceConditions,
-	"Pod":                        podConditions,
-	"Secret":                     alwaysReady,
-	"PersistentVolumeClaim":      pvcConditions,
-	"apps/StatefulSet":           stsConditions,
-	"apps/DaemonSet":             daemonsetConditions,
-	"extensions/DaemonSet":       daemonsetConditions,
-	"apps/Deployment":            deploymentConditions,
-	"extensions/Deployment":      deploymentConditions,
-	"apps/ReplicaSet":            replicasetConditions,
-	"extensions/ReplicaSet":      replicasetConditions,
-	"policy/PodDisruptionBudget": pdbConditions,
-	"batch/CronJob":              alwaysReady,
-	"ConfigMap":                  alwaysReady,
-	"batch/Job":                  jobConditions,
+	"Service":                             serviceConditions,
+	"Pod":                                 podConditions,
+	"Secret":                              alwaysReady,
+	"PersistentVolumeClaim":               pvcConditions,
+	"apps/StatefulSet":                    stsConditions,
+	"apps/DaemonSet":                      daemonsetConditions,
+	"extensions/DaemonSet":                daemonsetConditions,
+	"apps/Deployment":                     deploymentConditions,
+	"extensions/Deployment":               deploymentConditions,
+	"apps/ReplicaSet":                     replicasetConditions,
+	"extensions/ReplicaSet":               replicasetConditions,
+	"policy/PodDisruptionBudget":          pdbConditions,
+	"batch/CronJob":                       cronJobConditions,
+	"autoscaling/HorizontalPodAutoscaler": hpaConditions,
+	"networking.k8s.io/Ingress":           ingressConditions,
+	"extensions/Ingress":                  ingressConditions,
+	"ConfigMap":                           alwaysReady,
+	"batch/Job":                           jobConditions,
 	"apiextensions.k8s.io/CustomResourceDefinition": crdConditions,
 }
 
@@ -182,9 +185,13 @@ func deploymentConditions(u *unstructured.Unstructured) (*Result, error) {
 		case "Progressing": // appsv1.DeploymentProgressing:
 			// https://github.com/kubernetes/kubernetes/blob/a3ccea9d8743f2ff82e41b6c2af6dc2c41dc7b10/pkg/controller/deployment/progress.go#L52
 			if c.Reason == "ProgressDeadlineExceeded" {
+				message := "Progress deadline exceeded"
+				if c.Message != "" {
+					message = fmt.Sprintf("%s: %s", message, c.Message)
+				}
 				return &Result{
 					Status:     FailedStatus,
-					Message:    "Progress deadline exceeded",
+					Message:    message,
 					Conditions: []Condition{{ConditionStalled, corev1.ConditionTrue, c.Reason, c.Message}},
 				}, nil
 			}
@@ -516,11 +523,138 @@ func getCrashLoopingContainers(obj map[string]interface{}) ([]string, bool, erro
 // computing the AllowedDisruptions fails (and there are many ways
 // it can fail), but there is PR against OSS Kubernetes to address
 // this: https://github.com/kubernetes/kubernetes/pull/86929
-func pdbConditions(_ *unstructured.Unstructured) (*Result, error) {
+// pdbConditions return standardized Conditions for PodDisruptionBudget
+//
+// A PodDisruptionBudget is InProgress while fewer pods are healthy than it
+// requires, since no disruption is allowed until they are.
+func pdbConditions(u *unstructured.Unstructured) (*Result, error) {
+	obj := u.UnstructuredContent()
+
+	currentHealthy := GetIntField(obj, ".status.currentHealthy", 0)
+	desiredHealthy := GetIntField(obj, ".status.desiredHealthy", 0)
+	if currentHealthy < desiredHealthy {
+		message := fmt.Sprintf("Healthy: %d/%d", currentHealthy, desiredHealthy)
+		return newInProgressStatus("InsufficientPods", message), nil
+	}
+
 	// All ok
+	return &Result{
+		Status: CurrentStatus,
+		Message: fmt.Sprintf("AllowedDisruptions has been computed: %d",
+			GetIntField(obj, ".status.disruptionsAllowed", 0)),
+		Conditions: []Condition{},
+	}, nil
+}
+
+// cronJobConditions return standardized Conditions for CronJob
+//
+// A CronJob is Current until its first job is scheduled, since the schedule
+// may not fire for a long time. Once scheduled, it is InProgress until a job
+// succeeded, and Failed if the last scheduled job finished without
+// succeeding.
+func cronJobConditions(u *unstructured.Unstructured) (*Result, error) {
+	obj := u.UnstructuredContent()
+
+	lastScheduleTime := GetStringField(obj, ".status.lastScheduleTime", "")
+	lastSuccessfulTime := GetStringField(obj, ".status.lastSuccessfulTime", "")
+	active, _, err := unstructured.NestedSlice(obj, "status", "active")
+	if err != nil {
+		return nil, fmt.Errorf("looking up status.active from resource: %w", err)
+	}
+
+	if lastScheduleTime == "" {
+		return &Result{
+			Status:     CurrentStatus,
+			Message:    "CronJob has not been scheduled yet",
+			Conditions: []Condition{},
+		}, nil
+	}
+	scheduled, err := time.Parse(time.RFC3339, lastScheduleTime)
+	if err != nil {
+		return nil, fmt.Errorf("parsing status.lastScheduleTime from resource: %w", err)
+	}
+	var succeeded time.Time
+	if lastSuccessfulTime != "" {
+		succeeded, err = time.Parse(time.RFC3339, lastSuccessfulTime)
+		if err != nil {
+			return nil, fmt.Errorf("parsing status.lastSuccessfulTime from resource: %w", err)
+		}
+	}
+	if !succeeded.Before(scheduled) {
+		return &Result{
+			Status:     CurrentStatus,
+			Message:    fmt.Sprintf("Last scheduled job succeeded at %s", lastSuccessfulTime),
+			Conditions: []Condition{},
+		}, nil
+	}
+	if len(active) > 0 {
+		message := fmt.Sprintf("Scheduled job in progress. active: %d", len(active))
+		return newInProgressStatus("JobInProgress", message), nil
+	}
+	return newFailedStatus("LastJobFailed",
+		fmt.Sprintf("Job scheduled at %s did not succeed", lastScheduleTime)), nil
+}
+
+// hpaConditions return standardized Conditions for HorizontalPodAutoscaler
+//
+// A HorizontalPodAutoscaler is Failed if it is not able to scale its target,
+// and InProgress until its metrics can be computed, or if scaling is disabled
+// because the target has zero replicas.
+func hpaConditions(u *unstructured.Unstructured) (*Result, error) {
+	obj := u.UnstructuredContent()
+
+	objc, err := GetObjectWithConditions(obj)
+	if err != nil {
+		return nil, err
+	}
+	scalingActive := false
+	for _, c := range objc.Status.Conditions {
+		switch c.Type {
+		case "AbleToScale":
+			if c.Status == corev1.ConditionFalse {
+				return newFailedStatus(c.Reason, c.Message), nil
+			}
+		case "ScalingActive":
+			if c.Status == corev1.ConditionFalse {
+				if c.Reason == "ScalingDisabled" {
+					return &Result{
+						Status:     CurrentStatus,
+						Message:    c.Message,
+						Conditions: []Condition{},
+					}, nil
+				}
+				return newInProgressStatus(c.Reason, c.Message), nil
+			}
+			scalingActive = c.Status == corev1.ConditionTrue
+		}
+	}
+	if !scalingActive {
+		return newInProgressStatus("ScalingInactive", "Metrics have not been computed yet"), nil
+	}
+	return &Result{
+		Status:     CurrentStatus,
+		Message:    "HorizontalPodAutoscaler is able to scale",
+		Conditions: []Condition{},
+	}, nil
+}
+
+// ingressConditions return standardized Conditions for Ingress
+//
+// An Ingress is InProgress until the ingress controller has assigned it an
+// address.
+func ingressConditions(u *unstructured.Unstructured) (*Result, error) {
+	obj := u.UnstructuredContent()
+
+	addresses, _, err := unstructured.NestedSlice(obj, "status", "loadBalancer", "ingress")
+	if err != nil {
+		return nil, fmt.Errorf("looking up status.loadBalancer.ingress from resource: %w", err)
+	}
+	if len(addresses) == 0 {
+		return newInProgressStatus("NoAddressAssigned", "Ingress has no address assigned"), nil
+	}
 	return &Result{
 		Status:     CurrentStatus,
-		Message:    "AllowedDisruptions has been computed.",
+		Message:    "Ingress has an address assigned",
 		Conditions: []Condition{},
 	}, nil
 }
@@ -5,9 +5,12 @@ package status
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/duration"
 )
 
 // checkGenericProperties looks at the properties that are available on
@@ -30,12 +33,12 @@ func checkGenericProperties(u *unstructured.Unstructured) (*Result, error) {
 	if found && deletionTimestamp != "" {
 		return &Result{
 			Status:     TerminatingStatus,
-			Message:    "Resource scheduled for deletion",
+			Message:    terminatingMessage(u),
 			Conditions: []Condition{},
 		}, nil
 	}
 
-	res, err := checkGeneration(u)
+	res, err := CheckObservedGeneration(u)
 	if res != nil || err != nil {
 		return res, err
 	}
@@ -70,7 +73,19 @@ func checkGenericProperties(u *unstructured.Unstructured) (*Result, error) {
 	return nil, nil
 }
 
-func checkGeneration(u *unstructured.Unstructured) (*Result, error) {
+// LatestGenerationNotObservedReason is the reason of the Reconciling
+// condition of the resources whose controller has not observed their latest
+// generation.
+const LatestGenerationNotObservedReason = "LatestGenerationNotObserved"
+
+// CheckObservedGeneration checks whether the controller of the resource has
+// observed its latest generation. If not, the status of the resource is
+// stale, so the resource is InProgress, with the
+// LatestGenerationNotObservedReason and both generations in the message.
+// Returns nil if the latest generation is observed, or if the resource does
+// not report the observed generation. Compute applies it before the rules of
+// the resource types, and custom StatusFuncs and StatusReaders should too.
+func CheckObservedGeneration(u *unstructured.Unstructured) (*Result, error) {
 	// ensure that the meta generation is observed
 	generation, found, err := unstructured.NestedInt64(u.Object, "metadata", "generation")
 	if err != nil {
@@ -91,9 +106,48 @@ func checkGeneration(u *unstructured.Unstructured) (*Result, error) {
 			return &Result{
 				Status:     InProgressStatus,
 				Message:    message,
-				Conditions: []Condition{newReconcilingCondition("LatestGenerationNotObserved", message)},
+				Conditions: []Condition{newReconcilingCondition(LatestGenerationNotObservedReason, message)},
 			}, nil
 		}
 	}
 	return nil, nil
 }
+
+// IsLatestGenerationNotObserved returns true if the Result is InProgress
+// because the controller of the resource has not observed its latest
+// generation, so that its status is stale.
+func IsLatestGenerationNotObserved(res *Result) bool {
+	if res == nil || res.Status != InProgressStatus {
+		return false
+	}
+	for _, c := range res.Conditions {
+		if c.Type == ConditionReconciling && c.Reason == LatestGenerationNotObservedReason {
+			return true
+		}
+	}
+	return false
+}
+
+// terminatingMessage describes the progress of the deletion of the resource:
+// which finalizers the deletion is waiting for. The message does not tell how
+// long ago the deletion was requested, so that it only changes when the
+// deletion makes progress. See MessageWithDeletionAge.
+func terminatingMessage(u *unstructured.Unstructured) string {
+	message := "Resource scheduled for deletion"
+	if finalizers := u.GetFinalizers(); len(finalizers) > 0 {
+		message = fmt.Sprintf("%s. Waiting for finalizers: %s", message, strings.Join(finalizers, ", "))
+	}
+	return message
+}
+
+// MessageWithDeletionAge returns the message of a resource status, followed
+// by how long ago the deletion of the resource was requested, in the format
+// of the AGE column of kubectl, if the resource is Terminating. The age is
+// left out of the computed status, so printers can add it when printing.
+func MessageWithDeletionAge(status Status, message string, u *unstructured.Unstructured) string {
+	if status != TerminatingStatus || u == nil || u.GetDeletionTimestamp() == nil {
+		return message
+	}
+	age := duration.HumanDuration(time.Since(u.GetDeletionTimestamp().Time))
+	return fmt.Sprintf("%s (deletion requested %s ago)", message, age)
+}
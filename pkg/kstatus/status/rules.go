@@ -0,0 +1,138 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package status
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+const (
+	// CurrentWhenAnnotation is the annotation key of the rules that decide
+	// when a resource is Current, for resources that do not follow the
+	// status conventions, e.g. of CRDs.
+	CurrentWhenAnnotation = "kstatus.cli-utils.sigs.k8s.io/current-when"
+
+	// FailedWhenAnnotation is the annotation key of the rules that decide
+	// when a resource has Failed. They take precedence over the rules of the
+	// CurrentWhenAnnotation.
+	FailedWhenAnnotation = "kstatus.cli-utils.sigs.k8s.io/failed-when"
+)
+
+// checkStatusRules computes the status of the resource from the rules of its
+// CurrentWhenAnnotation and FailedWhenAnnotation annotations, if any. Each
+// line of an annotation is a rule, and all the rules of an annotation must
+// match. A rule is a JSONPath template, as supported by kubectl, optionally
+// compared with a value:
+//
+//	{.status.phase}==Ready
+//	{.status.conditions[?(@.type=="Degraded")].status}!=True
+//	{.status.endpoint}
+//
+// A rule without a comparison matches if the template is not empty, nor
+// "false". If the resource has rules, but the rules of neither annotation
+// match, the resource is InProgress. Returns nil if the resource has no
+// rules, and an error if a rule is invalid.
+func checkStatusRules(u *unstructured.Unstructured) (*Result, error) {
+	annotations := u.GetAnnotations()
+	failedWhen, hasFailed := annotations[FailedWhenAnnotation]
+	currentWhen, hasCurrent := annotations[CurrentWhenAnnotation]
+	if !hasFailed && !hasCurrent {
+		return nil, nil
+	}
+
+	if hasFailed {
+		rule, matched, err := matchStatusRules(u, failedWhen)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s annotation: %w", FailedWhenAnnotation, err)
+		}
+		if matched {
+			message := fmt.Sprintf("Resource matches the failed rule %q", rule)
+			return &Result{
+				Status:     FailedStatus,
+				Message:    message,
+				Conditions: []Condition{newStalledCondition("StatusRule", message)},
+			}, nil
+		}
+	}
+	if hasCurrent {
+		rule, matched, err := matchStatusRules(u, currentWhen)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s annotation: %w", CurrentWhenAnnotation, err)
+		}
+		if !matched {
+			return newInProgressStatus("StatusRule",
+				fmt.Sprintf("Resource does not match the current rule %q", rule)), nil
+		}
+	}
+	return &Result{
+		Status:     CurrentStatus,
+		Message:    "Resource matches the current rules",
+		Conditions: []Condition{},
+	}, nil
+}
+
+// matchStatusRules returns true if all the rules match the resource.
+// Otherwise, it returns the first rule that does not match. If all the rules
+// match, the returned rule is the last one.
+func matchStatusRules(u *unstructured.Unstructured, rules string) (string, bool, error) {
+	var rule string
+	for _, line := range strings.Split(rules, "\n") {
+		rule = strings.TrimSpace(line)
+		if rule == "" {
+			continue
+		}
+		matched, err := matchStatusRule(u, rule)
+		if err != nil {
+			return rule, false, err
+		}
+		if !matched {
+			return rule, false, nil
+		}
+	}
+	return rule, true, nil
+}
+
+// matchStatusRule evaluates a single rule against the resource.
+func matchStatusRule(u *unstructured.Unstructured, rule string) (bool, error) {
+	template, op, expected := splitStatusRule(rule)
+	j := jsonpath.New("rule").AllowMissingKeys(true)
+	if err := j.Parse(template); err != nil {
+		return false, fmt.Errorf("rule %q: %w", rule, err)
+	}
+	var buf bytes.Buffer
+	if err := j.Execute(&buf, u.Object); err != nil {
+		return false, fmt.Errorf("rule %q: %w", rule, err)
+	}
+	value := strings.TrimSpace(buf.String())
+	switch op {
+	case "==":
+		return value == expected, nil
+	case "!=":
+		return value != expected, nil
+	default:
+		return value != "" && value != "false", nil
+	}
+}
+
+// splitStatusRule splits a rule into its JSONPath template, and, if any, its
+// comparison operator and the expected value. The operator is searched after
+// the end of the template, since JSONPath filters contain operators too.
+func splitStatusRule(rule string) (string, string, string) {
+	end := strings.LastIndex(rule, "}")
+	if end < 0 {
+		return rule, "", ""
+	}
+	template, rest := rule[:end+1], strings.TrimSpace(rule[end+1:])
+	for _, op := range []string{"==", "!="} {
+		if strings.HasPrefix(rest, op) {
+			return template, op, strings.TrimSpace(strings.TrimPrefix(rest, op))
+		}
+	}
+	return template, "", ""
+}
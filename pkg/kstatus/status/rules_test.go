@@ -0,0 +1,115 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package status
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var databaseWithRules = `
+apiVersion: db.example.com/v1
+kind: Database
+metadata:
+  name: db
+  namespace: default
+  generation: 1
+  annotations:
+    kstatus.cli-utils.sigs.k8s.io/current-when: |
+      {.status.phase}==Ready
+      {.status.endpoint}
+    kstatus.cli-utils.sigs.k8s.io/failed-when: |
+      {.status.conditions[?(@.type=="Degraded")].status}==True
+status:
+`
+
+func TestStatusRules(t *testing.T) {
+	testCases := map[string]struct {
+		status         map[string]interface{}
+		annotations    map[string]string
+		expectedStatus Status
+		expectedErr    bool
+	}{
+		"no status": {
+			expectedStatus: InProgressStatus,
+		},
+		"all current rules match": {
+			status: map[string]interface{}{
+				"phase":    "Ready",
+				"endpoint": "db.default.svc:5432",
+			},
+			expectedStatus: CurrentStatus,
+		},
+		"some current rules match": {
+			status: map[string]interface{}{
+				"phase": "Ready",
+			},
+			expectedStatus: InProgressStatus,
+		},
+		"failed rule takes precedence": {
+			status: map[string]interface{}{
+				"phase":    "Ready",
+				"endpoint": "db.default.svc:5432",
+				"conditions": []interface{}{
+					map[string]interface{}{
+						"type":   "Degraded",
+						"status": "True",
+					},
+				},
+			},
+			expectedStatus: FailedStatus,
+		},
+		"not equal": {
+			annotations: map[string]string{
+				CurrentWhenAnnotation: "{.status.phase}!=Provisioning",
+			},
+			status: map[string]interface{}{
+				"phase": "Ready",
+			},
+			expectedStatus: CurrentStatus,
+		},
+		"false value does not match": {
+			annotations: map[string]string{
+				CurrentWhenAnnotation: "{.status.ready}",
+			},
+			status: map[string]interface{}{
+				"ready": false,
+			},
+			expectedStatus: InProgressStatus,
+		},
+		"only failed rules": {
+			annotations: map[string]string{
+				FailedWhenAnnotation: "{.status.error}",
+			},
+			status:         map[string]interface{}{},
+			expectedStatus: CurrentStatus,
+		},
+		"invalid rule": {
+			annotations: map[string]string{
+				CurrentWhenAnnotation: "{.status.phase",
+			},
+			expectedErr: true,
+		},
+	}
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			u := y2u(t, databaseWithRules)
+			if tc.annotations != nil {
+				u.SetAnnotations(tc.annotations)
+			}
+			if tc.status != nil {
+				u.Object["status"] = tc.status
+			}
+			res, err := Compute(u)
+			if tc.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, res.Status)
+		})
+	}
+}
@@ -24,12 +24,13 @@ const (
 	FailedStatus      Status = "Failed"
 	CurrentStatus     Status = "Current"
 	TerminatingStatus Status = "Terminating"
+	SuspendedStatus   Status = "Suspended"
 	NotFoundStatus    Status = "NotFound"
 	UnknownStatus     Status = "Unknown"
 )
 
 var (
-	Statuses = []Status{InProgressStatus, FailedStatus, CurrentStatus, TerminatingStatus, UnknownStatus}
+	Statuses = []Status{InProgressStatus, FailedStatus, CurrentStatus, TerminatingStatus, SuspendedStatus, UnknownStatus}
 )
 
 // ConditionType defines the set of condition types allowed inside a Condition struct.
@@ -95,11 +96,23 @@ type Condition struct {
 //   - Current
 //   - Failed
 //   - Terminating
+//   - Suspended
 //
 // It also contains a message that provides more information on why
 // the resource has the given status. Finally, the result also contains
 // a list of standard resources that would belong on the given resource.
 func Compute(u *unstructured.Unstructured) (*Result, error) {
+	res, err := computeWith(u, nil)
+	if err != nil {
+		return nil, err
+	}
+	return checkSuspended(u, res)
+}
+
+// computeWith finds the status of the resource, without checking whether the
+// resource is suspended. The extra function, if any, is tried after the
+// rules of the built-in types, before the Ready condition.
+func computeWith(u *unstructured.Unstructured, extra GetConditionsFn) (*Result, error) {
 	res, err := checkGenericProperties(u)
 	if err != nil {
 		return nil, err
@@ -112,11 +125,25 @@ func Compute(u *unstructured.Unstructured) (*Result, error) {
 		return res, nil
 	}
 
+	// Resources that do not follow the status conventions can declare the
+	// rules that decide their status in annotations.
+	res, err = checkStatusRules(u)
+	if res != nil || err != nil {
+		return res, err
+	}
+
 	fn := GetLegacyConditionsFn(u)
 	if fn != nil {
 		return fn(u)
 	}
 
+	if extra != nil {
+		res, err = extra(u)
+		if res != nil || err != nil {
+			return res, err
+		}
+	}
+
 	// If neither the generic properties of the resource-specific rules
 	// can determine status, we do one last check to see if the resource
 	// does expose a Ready condition. Ready conditions do not adhere
@@ -984,8 +984,57 @@ status:
    observedGeneration: 1
 `
 
+var pdbInsufficientPods = `
+apiVersion: policy/v1
+kind: PodDisruptionBudget
+metadata:
+   generation: 1
+   name: test
+   namespace: qual
+status:
+   observedGeneration: 1
+   currentHealthy: 1
+   desiredHealthy: 2
+   disruptionsAllowed: 0
+`
+
+var pdbHealthy = `
+apiVersion: policy/v1
+kind: PodDisruptionBudget
+metadata:
+   generation: 1
+   name: test
+   namespace: qual
+status:
+   observedGeneration: 1
+   currentHealthy: 3
+   desiredHealthy: 2
+   disruptionsAllowed: 1
+`
+
 func TestPDBStatus(t *testing.T) {
 	testCases := map[string]testSpec{
+		"pdbInsufficientPods": {
+			spec:           pdbInsufficientPods,
+			expectedStatus: InProgressStatus,
+			expectedConditions: []Condition{{
+				Type:   ConditionReconciling,
+				Status: corev1.ConditionTrue,
+				Reason: "InsufficientPods",
+			}},
+			absentConditionTypes: []ConditionType{
+				ConditionStalled,
+			},
+		},
+		"pdbHealthy": {
+			spec:               pdbHealthy,
+			expectedStatus:     CurrentStatus,
+			expectedConditions: []Condition{},
+			absentConditionTypes: []ConditionType{
+				ConditionStalled,
+				ConditionReconciling,
+			},
+		},
 		"pdbNotObserved": {
 			spec:           pdbNotObserved,
 			expectedStatus: InProgressStatus,
@@ -1079,6 +1128,86 @@ status:
       status: "False"
 `
 
+var deploymentTerminating = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+   name: test
+   namespace: qual
+   generation: 1
+   deletionTimestamp: %s
+   finalizers:
+    - example.com/cleanup
+    - example.com/backup
+`
+
+func TestTerminatingStatus(t *testing.T) {
+	testCases := map[string]struct {
+		spec            string
+		expectedMessage string
+	}{
+		"with finalizers": {
+			spec: fmt.Sprintf(deploymentTerminating,
+				time.Now().Add(-3*time.Hour).UTC().Format(time.RFC3339)),
+			expectedMessage: "Resource scheduled for deletion. " +
+				"Waiting for finalizers: example.com/cleanup, example.com/backup",
+		},
+		"without finalizers": {
+			spec: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+   name: test
+   namespace: qual
+   deletionTimestamp: "2019-06-04T01:17:13Z"
+`,
+			expectedMessage: "Resource scheduled for deletion",
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			res, err := Compute(y2u(t, tc.spec))
+			assert.NoError(t, err)
+			assert.Equal(t, TerminatingStatus, res.Status)
+			assert.Equal(t, tc.expectedMessage, res.Message)
+		})
+	}
+}
+
+func TestMessageWithDeletionAge(t *testing.T) {
+	terminating := y2u(t, fmt.Sprintf(deploymentTerminating,
+		time.Now().Add(-3*time.Hour).UTC().Format(time.RFC3339)))
+
+	testCases := map[string]struct {
+		status          Status
+		obj             *unstructured.Unstructured
+		expectedMessage string
+	}{
+		"terminating": {
+			status:          TerminatingStatus,
+			obj:             terminating,
+			expectedMessage: "Resource scheduled for deletion (deletion requested 3h ago)",
+		},
+		"not terminating": {
+			status:          CurrentStatus,
+			obj:             terminating,
+			expectedMessage: "Resource scheduled for deletion",
+		},
+		"without resource": {
+			status:          TerminatingStatus,
+			expectedMessage: "Resource scheduled for deletion",
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			message := MessageWithDeletionAge(tc.status, "Resource scheduled for deletion", tc.obj)
+			assert.Equal(t, tc.expectedMessage, message)
+		})
+	}
+}
+
 func TestCRDGenericStatus(t *testing.T) {
 	testCases := map[string]testSpec{
 		"crdNoStatus": {
@@ -1279,8 +1408,80 @@ metadata:
 status:
 `
 
+var cronjobSucceeded = `
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+   name: test
+   namespace: qual
+   generation: 1
+status:
+   lastScheduleTime: "2024-05-01T12:00:00Z"
+   lastSuccessfulTime: "2024-05-01T12:01:00Z"
+`
+
+var cronjobActive = `
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+   name: test
+   namespace: qual
+   generation: 1
+status:
+   lastScheduleTime: "2024-05-01T12:00:00Z"
+   lastSuccessfulTime: "2024-05-01T11:01:00Z"
+   active:
+   - kind: Job
+     name: test-28573920
+     namespace: qual
+`
+
+var cronjobLastJobFailed = `
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+   name: test
+   namespace: qual
+   generation: 1
+status:
+   lastScheduleTime: "2024-05-01T12:00:00Z"
+`
+
 func TestCronJobStatus(t *testing.T) {
 	testCases := map[string]testSpec{
+		"cronjobSucceeded": {
+			spec:               cronjobSucceeded,
+			expectedStatus:     CurrentStatus,
+			expectedConditions: []Condition{},
+			absentConditionTypes: []ConditionType{
+				ConditionStalled,
+				ConditionReconciling,
+			},
+		},
+		"cronjobActive": {
+			spec:           cronjobActive,
+			expectedStatus: InProgressStatus,
+			expectedConditions: []Condition{{
+				Type:   ConditionReconciling,
+				Status: corev1.ConditionTrue,
+				Reason: "JobInProgress",
+			}},
+			absentConditionTypes: []ConditionType{
+				ConditionStalled,
+			},
+		},
+		"cronjobLastJobFailed": {
+			spec:           cronjobLastJobFailed,
+			expectedStatus: FailedStatus,
+			expectedConditions: []Condition{{
+				Type:   ConditionStalled,
+				Status: corev1.ConditionTrue,
+				Reason: "LastJobFailed",
+			}},
+			absentConditionTypes: []ConditionType{
+				ConditionReconciling,
+			},
+		},
 		"cronjobNoStatus": {
 			spec:               cronjobNoStatus,
 			expectedStatus:     CurrentStatus,
@@ -1510,3 +1711,237 @@ func TestCRDStatus(t *testing.T) {
 		})
 	}
 }
+
+var hpaNoConditions = `
+apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+   name: test
+   namespace: qual
+   generation: 1
+`
+
+var hpaScaling = `
+apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+   name: test
+   namespace: qual
+   generation: 1
+status:
+   conditions:
+   - type: AbleToScale
+     status: "True"
+     reason: ReadyForNewScale
+   - type: ScalingActive
+     status: "True"
+     reason: ValidMetricFound
+`
+
+var hpaMetricsUnavailable = `
+apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+   name: test
+   namespace: qual
+   generation: 1
+status:
+   conditions:
+   - type: AbleToScale
+     status: "True"
+     reason: SucceededGetScale
+   - type: ScalingActive
+     status: "False"
+     reason: FailedGetResourceMetric
+`
+
+var hpaUnableToScale = `
+apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+   name: test
+   namespace: qual
+   generation: 1
+status:
+   conditions:
+   - type: AbleToScale
+     status: "False"
+     reason: FailedGetScale
+`
+
+var hpaScalingDisabled = `
+apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+   name: test
+   namespace: qual
+   generation: 1
+status:
+   conditions:
+   - type: AbleToScale
+     status: "True"
+     reason: SucceededGetScale
+   - type: ScalingActive
+     status: "False"
+     reason: ScalingDisabled
+`
+
+func TestHPAStatus(t *testing.T) {
+	testCases := map[string]testSpec{
+		"hpaNoConditions": {
+			spec:           hpaNoConditions,
+			expectedStatus: InProgressStatus,
+			expectedConditions: []Condition{{
+				Type:   ConditionReconciling,
+				Status: corev1.ConditionTrue,
+				Reason: "ScalingInactive",
+			}},
+		},
+		"hpaScaling": {
+			spec:               hpaScaling,
+			expectedStatus:     CurrentStatus,
+			expectedConditions: []Condition{},
+			absentConditionTypes: []ConditionType{
+				ConditionStalled,
+				ConditionReconciling,
+			},
+		},
+		"hpaMetricsUnavailable": {
+			spec:           hpaMetricsUnavailable,
+			expectedStatus: InProgressStatus,
+			expectedConditions: []Condition{{
+				Type:   ConditionReconciling,
+				Status: corev1.ConditionTrue,
+				Reason: "FailedGetResourceMetric",
+			}},
+		},
+		"hpaUnableToScale": {
+			spec:           hpaUnableToScale,
+			expectedStatus: FailedStatus,
+			expectedConditions: []Condition{{
+				Type:   ConditionStalled,
+				Status: corev1.ConditionTrue,
+				Reason: "FailedGetScale",
+			}},
+		},
+		"hpaScalingDisabled": {
+			spec:               hpaScalingDisabled,
+			expectedStatus:     CurrentStatus,
+			expectedConditions: []Condition{},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			runStatusTest(t, tc)
+		})
+	}
+}
+
+var ingressNoAddress = `
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+   name: test
+   namespace: qual
+   generation: 1
+status:
+   loadBalancer: {}
+`
+
+var ingressWithAddress = `
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+   name: test
+   namespace: qual
+   generation: 1
+status:
+   loadBalancer:
+     ingress:
+     - ip: 1.2.3.4
+`
+
+func TestIngressStatus(t *testing.T) {
+	testCases := map[string]testSpec{
+		"ingressNoAddress": {
+			spec:           ingressNoAddress,
+			expectedStatus: InProgressStatus,
+			expectedConditions: []Condition{{
+				Type:   ConditionReconciling,
+				Status: corev1.ConditionTrue,
+				Reason: "NoAddressAssigned",
+			}},
+		},
+		"ingressWithAddress": {
+			spec:               ingressWithAddress,
+			expectedStatus:     CurrentStatus,
+			expectedConditions: []Condition{},
+			absentConditionTypes: []ConditionType{
+				ConditionStalled,
+				ConditionReconciling,
+			},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			runStatusTest(t, tc)
+		})
+	}
+}
+
+func TestCheckObservedGeneration(t *testing.T) {
+	testCases := map[string]struct {
+		spec            string
+		expectedMessage string
+	}{
+		"generation observed": {
+			spec: `
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+   name: test
+   generation: 2
+status:
+   observedGeneration: 2
+`,
+		},
+		"no observed generation": {
+			spec: `
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+   name: test
+   generation: 2
+`,
+		},
+		"generation not observed": {
+			spec: `
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+   name: test
+   generation: 3
+status:
+   observedGeneration: 2
+`,
+			expectedMessage: "Widget generation is 3, but latest observed generation is 2",
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			res, err := CheckObservedGeneration(y2u(t, tc.spec))
+			assert.NoError(t, err)
+			if tc.expectedMessage == "" {
+				assert.Nil(t, res)
+				assert.False(t, IsLatestGenerationNotObserved(res))
+				return
+			}
+			assert.Equal(t, InProgressStatus, res.Status)
+			assert.Equal(t, tc.expectedMessage, res.Message)
+			assert.True(t, IsLatestGenerationNotObserved(res))
+		})
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package status
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// suspendFields are the fields of the spec that users set to pause the
+// reconciliation of a resource: spec.paused of Deployments, and spec.suspend
+// of Jobs, CronJobs and many custom resources, e.g. of Flux.
+var suspendFields = []string{"paused", "suspend"}
+
+// checkSuspended reports the resources that are InProgress as Suspended, if
+// their reconciliation has been paused. These resources will not become
+// Current until they are resumed, so waiting on them is pointless.
+// Resources with any other status are not affected, e.g. a paused Deployment
+// whose rollout has completed is still Current.
+func checkSuspended(u *unstructured.Unstructured, res *Result) (*Result, error) {
+	if res.Status != InProgressStatus {
+		return res, nil
+	}
+	for _, field := range suspendFields {
+		// Fields of the same name but of another type have another meaning.
+		suspended, found, err := unstructured.NestedBool(u.Object, "spec", field)
+		if err != nil || !found || !suspended {
+			continue
+		}
+		message := "Resource is suspended"
+		if res.Message != "" {
+			message = fmt.Sprintf("%s: %s", message, res.Message)
+		}
+		return &Result{
+			Status:     SuspendedStatus,
+			Message:    message,
+			Conditions: res.Conditions,
+		}, nil
+	}
+	return res, nil
+}
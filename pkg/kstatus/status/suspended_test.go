@@ -0,0 +1,131 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package status
+
+import (
+	"testing"
+)
+
+var deploymentPausedRollout = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+   name: test
+   generation: 2
+   namespace: qual
+spec:
+   paused: true
+   replicas: 2
+status:
+   observedGeneration: 2
+   replicas: 2
+   updatedReplicas: 1
+   readyReplicas: 2
+   availableReplicas: 2
+   conditions:
+    - type: Progressing
+      status: "Unknown"
+      reason: DeploymentPaused
+    - type: Available
+      status: "True"
+      reason: MinimumReplicasAvailable
+`
+
+var deploymentPausedComplete = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+   name: test
+   generation: 1
+   namespace: qual
+spec:
+   paused: true
+   replicas: 1
+status:
+   observedGeneration: 1
+   replicas: 1
+   updatedReplicas: 1
+   readyReplicas: 1
+   availableReplicas: 1
+   conditions:
+    - type: Progressing
+      status: "True"
+      reason: NewReplicaSetAvailable
+    - type: Available
+      status: "True"
+      reason: MinimumReplicasAvailable
+`
+
+var jobSuspended = `
+apiVersion: batch/v1
+kind: Job
+metadata:
+   name: test
+   namespace: qual
+   generation: 1
+spec:
+   suspend: true
+status:
+   conditions:
+    - type: Suspended
+      status: "True"
+      reason: JobSuspended
+`
+
+var customResourceSuspended = `
+apiVersion: source.toolkit.fluxcd.io/v1
+kind: GitRepository
+metadata:
+   name: test
+   namespace: qual
+   generation: 2
+spec:
+   suspend: true
+status:
+   observedGeneration: 1
+`
+
+var customResourceSuspendString = `
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+   name: test
+   namespace: qual
+   generation: 2
+spec:
+   suspend: "yes"
+status:
+   observedGeneration: 1
+`
+
+func TestSuspendedStatus(t *testing.T) {
+	testCases := map[string]testSpec{
+		"deploymentPausedRollout": {
+			spec:           deploymentPausedRollout,
+			expectedStatus: SuspendedStatus,
+		},
+		"deploymentPausedComplete": {
+			spec:           deploymentPausedComplete,
+			expectedStatus: CurrentStatus,
+		},
+		"jobSuspended": {
+			spec:           jobSuspended,
+			expectedStatus: SuspendedStatus,
+		},
+		"customResourceSuspended": {
+			spec:           customResourceSuspended,
+			expectedStatus: SuspendedStatus,
+		},
+		"customResourceSuspendString": {
+			spec:           customResourceSuspendString,
+			expectedStatus: InProgressStatus,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			runStatusTest(t, tc)
+		})
+	}
+}
@@ -6,6 +6,7 @@ package watcher
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -13,6 +14,7 @@ import (
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
+	"sigs.k8s.io/cli-utils/pkg/backoff"
 	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/clusterreader"
 	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/engine"
 	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/event"
@@ -53,9 +55,20 @@ type DefaultStatusWatcher struct {
 
 	// Filters allows filtering the objects being watched.
 	Filters *Filters
+
+	// Backoff is the strategy used to delay retries of transient errors
+	// when starting watches. If nil, backoff.DefaultStrategy is used.
+	Backoff backoff.Strategy
+
+	// lock guards reporters
+	lock sync.Mutex
+
+	// reporters are the running reporters, one per call to Watch.
+	reporters map[*ObjectStatusReporter]struct{}
 }
 
 var _ StatusWatcher = &DefaultStatusWatcher{}
+var _ KindRefresher = &DefaultStatusWatcher{}
 
 // NewDefaultStatusWatcher constructs a DynamicStatusWatcher with defaults
 // chosen for general use. If you need different settings, consider building a
@@ -111,8 +124,46 @@ func (w *DefaultStatusWatcher) Watch(ctx context.Context, ids object.ObjMetadata
 		Targets:       targets,
 		ObjectFilter:  &AllowListObjectFilter{AllowList: ids},
 		RESTScope:     scope,
+		Backoff:       w.Backoff,
+		StatsInterval: opts.StatsInterval,
+	}
+	eventCh := informer.Start(ctx)
+	w.addReporter(informer)
+	go func() {
+		<-informer.funnel.Done()
+		w.removeReporter(informer)
+	}()
+	return eventCh
+}
+
+// RefreshKinds resets the RESTMapper and starts watching the objects whose
+// resource types were not served before, for all the running watches.
+func (w *DefaultStatusWatcher) RefreshKinds() {
+	w.lock.Lock()
+	reporters := make([]*ObjectStatusReporter, 0, len(w.reporters))
+	for reporter := range w.reporters {
+		reporters = append(reporters, reporter)
+	}
+	w.lock.Unlock()
+
+	for _, reporter := range reporters {
+		reporter.RefreshKinds()
+	}
+}
+
+func (w *DefaultStatusWatcher) addReporter(reporter *ObjectStatusReporter) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.reporters == nil {
+		w.reporters = make(map[*ObjectStatusReporter]struct{})
 	}
-	return informer.Start(ctx)
+	w.reporters[reporter] = struct{}{}
+}
+
+func (w *DefaultStatusWatcher) removeReporter(reporter *ObjectStatusReporter) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	delete(w.reporters, reporter)
 }
 
 func handleFatalError(err error) <-chan event.Event {
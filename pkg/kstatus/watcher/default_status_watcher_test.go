@@ -5,7 +5,9 @@ package watcher
 
 import (
 	"context"
+	goruntime "runtime"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -655,6 +657,7 @@ func TestDefaultStatusWatcher(t *testing.T) {
 						Identifier:         deployment1ID,
 						Status:             status.NotFoundStatus,
 						Resource:           nil,
+						ErrorCategory:      event.NotFoundError,
 						Message:            "Resource not found",
 						GeneratedResources: nil,
 					},
@@ -1031,3 +1034,156 @@ func getGVR(t *testing.T, mapper meta.RESTMapper, obj *unstructured.Unstructured
 	require.NoError(t, err)
 	return mapping.Resource
 }
+
+// resetServedRESTMapper fails to map any kind until it is reset.
+type resetServedRESTMapper struct {
+	meta.RESTMapper
+
+	lock   sync.Mutex
+	served bool
+}
+
+func (m *resetServedRESTMapper) RESTMapping(gk schema.GroupKind, versions ...string) (*meta.RESTMapping, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if !m.served {
+		return nil, &meta.NoKindMatchError{GroupKind: gk, SearchedVersions: versions}
+	}
+	return m.RESTMapper.RESTMapping(gk, versions...)
+}
+
+func (m *resetServedRESTMapper) Reset() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.served = true
+}
+
+func TestDefaultStatusWatcher_RefreshKinds(t *testing.T) {
+	pod1 := yamlToUnstructured(t, pod1Yaml)
+	pod1ID := object.UnstructuredToObjMetadata(pod1)
+
+	fakeMapper := testutil.NewFakeRESTMapper(
+		v1.SchemeGroupVersion.WithKind("Pod"),
+	)
+	podGVR := getGVR(t, fakeMapper, pod1)
+	mapper := &resetServedRESTMapper{RESTMapper: fakeMapper}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	fakeClient := dynamicfake.NewSimpleDynamicClient(scheme.Scheme)
+	statusWatcher := NewDefaultStatusWatcher(fakeClient, mapper)
+	eventCh := statusWatcher.Watch(ctx, object.ObjMetadataSet{pod1ID}, Options{})
+
+	// The informer of the unknown kind is stopped, so the watcher syncs.
+	e := <-eventCh
+	require.Equal(t, event.SyncEvent, e.Type)
+
+	// After the refresh, the kind is served and the pod is watched.
+	statusWatcher.RefreshKinds()
+	require.NoError(t, fakeClient.Tracker().Create(podGVR, pod1, pod1.GetNamespace()))
+	e = <-eventCh
+	require.Equal(t, event.ResourceUpdateEvent, e.Type)
+	require.Equal(t, pod1ID, e.Resource.Identifier)
+
+	cancel()
+	for range eventCh {
+		// drain the events until the watcher stops
+	}
+}
+
+func TestDefaultStatusWatcher_Stats(t *testing.T) {
+	deployment1 := yamlToUnstructured(t, deployment1Yaml)
+	deployment1ID := object.UnstructuredToObjMetadata(deployment1)
+	pod1 := yamlToUnstructured(t, pod1Yaml)
+	pod1ID := object.UnstructuredToObjMetadata(pod1)
+
+	fakeMapper := testutil.NewFakeRESTMapper(
+		appsv1.SchemeGroupVersion.WithKind("Deployment"),
+		v1.SchemeGroupVersion.WithKind("Pod"),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	fakeClient := dynamicfake.NewSimpleDynamicClient(scheme.Scheme)
+	statusWatcher := NewDefaultStatusWatcher(fakeClient, fakeMapper)
+	eventCh := statusWatcher.Watch(ctx, object.ObjMetadataSet{deployment1ID, pod1ID}, Options{
+		RESTScopeStrategy: RESTScopeRoot,
+		StatsInterval:     10 * time.Millisecond,
+	})
+
+	var stats []event.WatchStats
+	for e := range eventCh {
+		if e.Type == event.StatsEvent {
+			stats = append(stats, *e.Stats)
+		}
+		if len(stats) == 3 {
+			cancel()
+		}
+	}
+	require.GreaterOrEqual(t, len(stats), 3)
+	for i, s := range stats[:3] {
+		require.Equal(t, 2, s.Informers)
+		require.Equal(t, 0, s.PendingTasks)
+		require.Positive(t, s.Goroutines)
+		require.Positive(t, s.HeapAlloc)
+		require.Positive(t, s.HeapObjects)
+		if i > 0 {
+			require.Greater(t, s.Uptime, stats[i-1].Uptime)
+		}
+	}
+}
+
+// TestDefaultStatusWatcher_Leaks checks that stopped watches do not leak
+// goroutines or reporters, so that long-running processes can watch
+// repeatedly.
+func TestDefaultStatusWatcher_Leaks(t *testing.T) {
+	deployment1 := yamlToUnstructured(t, deployment1Yaml)
+	deployment1ID := object.UnstructuredToObjMetadata(deployment1)
+	pod1 := yamlToUnstructured(t, pod1Yaml)
+	pod1ID := object.UnstructuredToObjMetadata(pod1)
+
+	fakeMapper := testutil.NewFakeRESTMapper(
+		appsv1.SchemeGroupVersion.WithKind("Deployment"),
+		v1.SchemeGroupVersion.WithKind("Pod"),
+	)
+	fakeClient := dynamicfake.NewSimpleDynamicClient(scheme.Scheme, deployment1, pod1)
+	statusWatcher := NewDefaultStatusWatcher(fakeClient, fakeMapper)
+
+	watch := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		eventCh := statusWatcher.Watch(ctx, object.ObjMetadataSet{deployment1ID, pod1ID}, Options{
+			StatsInterval: time.Millisecond,
+		})
+		for e := range eventCh {
+			require.NotEqual(t, event.ErrorEvent, e.Type, "unexpected error: %v", e.Error)
+			if e.Type == event.SyncEvent {
+				cancel()
+			}
+		}
+	}
+	noReporters := func() bool {
+		statusWatcher.lock.Lock()
+		defer statusWatcher.lock.Unlock()
+		return len(statusWatcher.reporters) == 0
+	}
+
+	// Warm up, to start the goroutines that run once per process.
+	watch()
+	require.Eventually(t, noReporters, 5*time.Second, 10*time.Millisecond)
+	baseline := goruntime.NumGoroutine()
+
+	for i := 0; i < 20; i++ {
+		watch()
+	}
+	require.Eventually(t, noReporters, 5*time.Second, 10*time.Millisecond)
+	// Poll from the test goroutine, because Eventually starts a goroutine.
+	goroutines := goruntime.NumGoroutine()
+	for deadline := time.Now().Add(5 * time.Second); goroutines > baseline && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+		goroutines = goruntime.NumGoroutine()
+	}
+	require.LessOrEqual(t, goroutines, baseline, "goroutines leaked")
+}
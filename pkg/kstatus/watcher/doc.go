@@ -36,4 +36,11 @@
 //	     return e.Err
 //	   }
 //	}
+//
+// # Long-Running Watches
+//
+// To monitor watches that run for days, e.g. in controllers, set
+// Options.StatsInterval. A StatsEvent is then sent periodically with the
+// number of running informers and pending status checks of the watch, and
+// the goroutines and heap of the process, to detect leaks.
 package watcher
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"runtime"
 	"sync"
 	"time"
 
@@ -15,10 +16,9 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
-	"k8s.io/utils/clock"
+	"sigs.k8s.io/cli-utils/pkg/backoff"
 	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/engine"
 	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/event"
 	"sigs.k8s.io/cli-utils/pkg/kstatus/status"
@@ -59,12 +59,12 @@ func (gkn GroupKindNamespace) GroupKind() schema.GroupKind {
 //     allowing the caller to optimize for efficiency or least-privilege.
 //   - Gives unschedulable Pods (and objects that generate them) a 15s grace
 //     period before reporting them as Failed.
-//   - Resets the RESTMapper cache automatically when CRDs are modified.
+//   - Resets the RESTMapper cache automatically when CRDs or APIServices are
+//     modified.
 //
 // ObjectStatusReporter is NOT repeatable. It will panic if started more than
 // once. If you need a repeatable factory, use DefaultStatusWatcher.
 //
-// TODO: support detection of added/removed api extensions at runtime
 // TODO: Watch CRDs & Namespaces, even if not in the set of IDs.
 // TODO: Retry with backoff if in namespace-scoped mode, to allow CRDs & namespaces to be created asynchronously
 type ObjectStatusReporter struct {
@@ -96,6 +96,14 @@ type ObjectStatusReporter struct {
 	// namespace scope may require fewer permissions.
 	RESTScope meta.RESTScope
 
+	// Backoff is the strategy used to delay retries when starting informers.
+	// If nil, backoff.DefaultStrategy is used.
+	Backoff backoff.Strategy
+
+	// StatsInterval is how often a StatsEvent is sent with the resource
+	// usage of the reporter. Zero disables the StatsEvents.
+	StatsInterval time.Duration
+
 	// lock guards modification of the subsequent stateful fields
 	lock sync.Mutex
 
@@ -122,6 +130,9 @@ type ObjectStatusReporter struct {
 	// taskManager makes it possible to cancel scheduled tasks.
 	taskManager *taskManager
 
+	// startTime is the time the reporter was started.
+	startTime time.Time
+
 	started bool
 	stopped bool
 }
@@ -135,6 +146,7 @@ func (w *ObjectStatusReporter) Start(ctx context.Context) <-chan event.Event {
 	}
 
 	w.taskManager = &taskManager{}
+	w.startTime = time.Now()
 
 	// Map GroupKinds to sets of GroupKindNamespaces for fast lookups.
 	// This is the only time we modify the map.
@@ -218,9 +230,63 @@ func (w *ObjectStatusReporter) Start(ctx context.Context) <-chan event.Event {
 		}
 	}()
 
+	if w.StatsInterval > 0 {
+		statsEventCh := make(chan event.Event)
+		err := w.funnel.AddInputChannel(statsEventCh)
+		if err != nil {
+			// Reporter already stopped.
+			return handleFatalError(fmt.Errorf("reporter failed to start: %v", err))
+		}
+		go w.sendStats(ctx, statsEventCh)
+	}
+
 	return w.funnel.OutputChannel()
 }
 
+// sendStats sends a StatsEvent every StatsInterval, until the context is
+// cancelled.
+func (w *ObjectStatusReporter) sendStats(ctx context.Context, eventCh chan<- event.Event) {
+	defer close(eventCh)
+	ticker := time.NewTicker(w.StatsInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			stats := w.Stats()
+			select {
+			case <-ctx.Done():
+				return
+			case eventCh <- event.Event{Type: event.StatsEvent, Stats: &stats}:
+			}
+		}
+	}
+}
+
+// Stats returns the resource usage of the reporter, and the goroutines and
+// memory of the process.
+func (w *ObjectStatusReporter) Stats() event.WatchStats {
+	w.lock.Lock()
+	stats := event.WatchStats{
+		Uptime:       time.Since(w.startTime),
+		PendingTasks: w.taskManager.Pending(),
+	}
+	for _, informer := range w.informerRefs {
+		if informer.HasStarted() {
+			stats.Informers++
+		}
+	}
+	w.lock.Unlock()
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	stats.Goroutines = runtime.NumGoroutine()
+	stats.HeapAlloc = mem.HeapAlloc
+	stats.HeapObjects = mem.HeapObjects
+	return stats
+}
+
 // Stop triggers the cancellation of the reporter context, and closure of the
 // event channel without sending an error event.
 func (w *ObjectStatusReporter) Stop() {
@@ -253,6 +319,26 @@ func (w *ObjectStatusReporter) HasSynced() bool {
 	return true
 }
 
+// RefreshKinds resets the RESTMapper and starts the informers that were
+// stopped, because their resource types were not served, e.g. because their
+// CRDs or APIServices were not applied yet. Informers that are already
+// running are not affected.
+func (w *ObjectStatusReporter) RefreshKinds() {
+	w.lock.Lock()
+	running := w.started && !w.stopped
+	w.lock.Unlock()
+	if !running {
+		return
+	}
+
+	klog.V(3).Info("Resetting RESTMapper")
+	meta.MaybeResetRESTMapper(w.Mapper)
+
+	for _, gkn := range w.Targets {
+		w.startInformer(gkn)
+	}
+}
+
 // startInformer adds the specified GroupKindNamespace to the start channel to
 // be started asynchronously.
 func (w *ObjectStatusReporter) startInformer(gkn GroupKindNamespace) {
@@ -271,12 +357,12 @@ func (w *ObjectStatusReporter) stopInformer(gkn GroupKindNamespace) {
 }
 
 func (w *ObjectStatusReporter) startInformerWithRetry(ctx context.Context, gkn GroupKindNamespace) {
-	realClock := &clock.RealClock{}
-	// TODO nolint can be removed once https://github.com/kubernetes/kubernetes/issues/118638 is resolved
-	backoffManager := wait.NewExponentialBackoffManager(800*time.Millisecond, 30*time.Second, 2*time.Minute, 2.0, 1.0, realClock) //nolint:staticcheck
-	retryCtx, retryCancel := context.WithCancel(ctx)
+	strategy := w.Backoff
+	if strategy == nil {
+		strategy = backoff.DefaultStrategy
+	}
 
-	wait.BackoffUntil(func() {
+	backoff.Until(ctx, strategy, func() bool {
 		err := w.startInformerNow(
 			ctx,
 			gkn,
@@ -288,7 +374,11 @@ func (w *ObjectStatusReporter) startInformerWithRetry(ctx context.Context, gkn G
 				klog.V(3).Infof("Watch start error (blocking until CRD is added): %v: %v", gkn, err)
 				// Cancel the parent context, which will stop the retries too.
 				w.stopInformer(gkn)
-				return
+				return true
+			}
+			if backoff.IsTransient(err) {
+				klog.V(3).Infof("Watch start error (retrying): %v: %v", gkn, err)
+				return false
 			}
 
 			// Create a temporary input channel to send the error event.
@@ -299,15 +389,15 @@ func (w *ObjectStatusReporter) startInformerWithRetry(ctx context.Context, gkn G
 				// Reporter already stopped.
 				// This is fine. 🔥
 				klog.V(5).Infof("Informer failed to start: %v", err)
-				return
+				return true
 			}
 			// Send error event and stop the reporter!
 			w.handleFatalError(eventCh, err)
-			return
+			return true
 		}
 		// Success! - Stop retrying
-		retryCancel()
-	}, backoffManager, true, retryCtx.Done())
+		return true
+	})
 }
 
 // startInformerNow starts an informer to watch for changes to a
@@ -416,10 +506,11 @@ func deletedStatus(id object.ObjMetadata) *event.ResourceStatus {
 	}
 
 	return &event.ResourceStatus{
-		Identifier: id,
-		Resource:   nil, // deleted object has no
-		Status:     result.Status,
-		Message:    result.Message,
+		Identifier:    id,
+		Resource:      nil, // deleted object has no
+		Status:        result.Status,
+		Message:       result.Message,
+		ErrorCategory: event.NotFoundError,
 		// If deleted with foreground deletion, a finalizer will have blocked
 		// deletion until all the generated resources are deleted.
 		// TODO: Handle lookup of generated resources when not using foreground deletion.
@@ -468,6 +559,9 @@ func (w *ObjectStatusReporter) eventHandler(
 		} else if object.IsCRD(obj) {
 			klog.V(5).Infof("AddFunc: CRD added: %v", id)
 			w.onCRDAdd(obj)
+		} else if object.IsAPIService(obj) {
+			klog.V(5).Infof("AddFunc: APIService added: %v", id)
+			w.onAPIServiceUpdate(obj)
 		}
 
 		if isObjectUnschedulable(rs) {
@@ -517,6 +611,9 @@ func (w *ObjectStatusReporter) eventHandler(
 		} else if object.IsCRD(obj) {
 			klog.V(5).Infof("UpdateFunc: CRD updated: %v", id)
 			w.onCRDUpdate(obj)
+		} else if object.IsAPIService(obj) {
+			klog.V(5).Infof("UpdateFunc: APIService updated: %v", id)
+			w.onAPIServiceUpdate(obj)
 		}
 
 		if isObjectUnschedulable(rs) {
@@ -640,6 +737,28 @@ func (w *ObjectStatusReporter) onCRDDelete(oldObj *unstructured.Unstructured) {
 	meta.MaybeResetRESTMapper(w.Mapper)
 }
 
+// onAPIServiceUpdate handles creating new informers to watch the resource
+// types of the group served by the added or updated APIService.
+func (w *ObjectStatusReporter) onAPIServiceUpdate(obj *unstructured.Unstructured) {
+	group, found, err := unstructured.NestedString(obj.Object, "spec", "group")
+	if err != nil || !found {
+		id := object.UnstructuredToObjMetadata(obj)
+		klog.Warningf("Invalid APIService updated: missing group: %v", id)
+		return
+	}
+	klog.V(3).Infof("APIService updated for group %q", group)
+
+	klog.V(3).Info("Resetting RESTMapper")
+	// Reset mapper to invalidate cache.
+	meta.MaybeResetRESTMapper(w.Mapper)
+
+	for _, gkn := range w.Targets {
+		if gkn.Group == group {
+			w.startInformer(gkn)
+		}
+	}
+}
+
 // onNamespaceAdd handles creating new informers to watch this namespace.
 func (w *ObjectStatusReporter) onNamespaceAdd(obj *unstructured.Unstructured) {
 	if w.RESTScope == meta.RESTScopeRoot {
@@ -842,6 +961,8 @@ type taskFunc func()
 type taskManager struct {
 	lock        sync.Mutex
 	cancelFuncs map[object.ObjMetadata]context.CancelFunc
+	// pending is the number of scheduled tasks waiting for their delay.
+	pending int
 }
 
 func (tm *taskManager) Schedule(parentCtx context.Context, id object.ObjMetadata, delay time.Duration, task taskFunc) {
@@ -860,8 +981,10 @@ func (tm *taskManager) Schedule(parentCtx context.Context, id object.ObjMetadata
 
 	taskCtx, cancel := context.WithTimeout(context.Background(), delay)
 	tm.cancelFuncs[id] = cancel
+	tm.pending++
 
 	go func() {
+		defer tm.done()
 		klog.V(5).Infof("Task scheduled (%v) for object (%s)", delay, id)
 		select {
 		case <-parentCtx.Done():
@@ -877,6 +1000,23 @@ func (tm *taskManager) Schedule(parentCtx context.Context, id object.ObjMetadata
 	}()
 }
 
+func (tm *taskManager) done() {
+	tm.lock.Lock()
+	defer tm.lock.Unlock()
+	tm.pending--
+}
+
+// Pending returns the number of scheduled tasks that have not run or been
+// cancelled yet.
+func (tm *taskManager) Pending() int {
+	if tm == nil {
+		return 0
+	}
+	tm.lock.Lock()
+	defer tm.lock.Unlock()
+	return tm.pending
+}
+
 func (tm *taskManager) Cancel(id object.ObjMetadata) {
 	tm.lock.Lock()
 	defer tm.lock.Unlock()
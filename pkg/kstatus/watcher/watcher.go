@@ -5,6 +5,7 @@ package watcher
 
 import (
 	"context"
+	"time"
 
 	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/event"
 	"sigs.k8s.io/cli-utils/pkg/object"
@@ -20,12 +21,26 @@ type StatusWatcher interface {
 	Watch(context.Context, object.ObjMetadataSet, Options) <-chan event.Event
 }
 
+// KindRefresher is implemented by StatusWatchers that can re-discover the
+// resource types of the watched objects while watching, e.g. after the CRDs
+// or APIServices serving them were applied.
+type KindRefresher interface {
+	// RefreshKinds resets the RESTMapper and starts watching the objects
+	// whose resource types were not served before.
+	RefreshKinds()
+}
+
 // Options can be provided when creating a new StatusWatcher to customize the
 // behavior.
 type Options struct {
 	// RESTScopeStrategy specifies which strategy to use when listing and
 	// watching resources. By default, the strategy is selected automatically.
 	RESTScopeStrategy RESTScopeStrategy
+
+	// StatsInterval is how often a StatsEvent is sent with the resource usage
+	// of the watch, to monitor long-running watches for leaks. Zero disables
+	// the StatsEvents. Not all StatusWatchers support StatsEvents.
+	StatsInterval time.Duration
 }
 
 //go:generate stringer -type=RESTScopeStrategy -linecomment
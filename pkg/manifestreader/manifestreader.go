@@ -6,6 +6,7 @@ package manifestreader
 import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
 )
 
 // ManifestReader defines the interface for reading a set
@@ -21,4 +22,18 @@ type ReaderOptions struct {
 	Validate         bool
 	Namespace        string
 	EnforceNamespace bool
+
+	// Variables, if set, are substituted for the ${NAME} references in the
+	// manifests. See SubstituteVariables.
+	Variables VariableLookup
+	// StrictVariables makes references to undefined Variables an error.
+	StrictVariables bool
+}
+
+// substituteVariables substitutes the Variables in the nodes, if set.
+func (o ReaderOptions) substituteVariables(nodes []*yaml.RNode) ([]*yaml.RNode, error) {
+	if o.Variables == nil {
+		return nodes, nil
+	}
+	return SubstituteVariables(nodes, o.Variables, o.StrictVariables)
 }
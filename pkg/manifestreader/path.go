@@ -5,6 +5,7 @@ package manifestreader
 
 import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/cli-utils/pkg/common"
 	"sigs.k8s.io/kustomize/kyaml/kio"
 	"sigs.k8s.io/kustomize/kyaml/kio/kioutil"
 )
@@ -14,7 +15,8 @@ var _ ManifestReader = &PathManifestReader{}
 
 // PathManifestReader reads manifests from the provided path
 // and returns them as Info objects. The returned Infos will not have
-// client or mapping set.
+// client or mapping set. The path is normalized with common.NormalizePath,
+// and the path annotations of the objects always use slashes.
 type PathManifestReader struct {
 	Path string
 
@@ -24,12 +26,24 @@ type PathManifestReader struct {
 // Read reads the manifests and returns them as Info objects.
 func (p *PathManifestReader) Read() ([]*unstructured.Unstructured, error) {
 	var objs []*unstructured.Unstructured
+	path, err := common.NormalizePath(p.Path)
+	if err != nil {
+		return objs, err
+	}
 	nodes, err := (&kio.LocalPackageReader{
-		PackagePath: p.Path,
+		PackagePath:    path,
+		MatchFilesGlob: common.ManifestFileGlobs(),
 	}).Read()
 	if err != nil {
 		return objs, err
 	}
+	if err := common.SlashPathAnnotations(nodes); err != nil {
+		return objs, err
+	}
+	nodes, err = p.substituteVariables(nodes)
+	if err != nil {
+		return objs, err
+	}
 
 	for _, n := range nodes {
 		err = RemoveAnnotations(n, kioutil.IndexAnnotation)
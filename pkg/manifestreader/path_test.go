@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
 	"sigs.k8s.io/kustomize/kyaml/kio/kioutil"
 )
@@ -85,3 +86,30 @@ func TestPathManifestReader_Read(t *testing.T) {
 		})
 	}
 }
+
+func TestPathManifestReader_ReadSymlink(t *testing.T) {
+	tf := cmdtesting.NewTestFactory().WithNamespace("test-ns")
+	defer tf.Cleanup()
+
+	mapper, err := tf.ToRESTMapper()
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	pkgDir := filepath.Join(dir, "pkg", "sub")
+	require.NoError(t, os.MkdirAll(pkgDir, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(pkgDir, "dep.yaml"), []byte(depManifest), 0o600))
+	link := filepath.Join(dir, "link")
+	require.NoError(t, os.Symlink(filepath.Join(dir, "pkg"), link))
+
+	objs, err := (&PathManifestReader{
+		Path: link,
+		ReaderOptions: ReaderOptions{
+			Mapper:    mapper,
+			Namespace: "default",
+		},
+	}).Read()
+	require.NoError(t, err)
+	require.Len(t, objs, 1)
+	// The path annotation uses slashes on every platform.
+	assert.Equal(t, "sub/dep.yaml", objs[0].GetAnnotations()[kioutil.PathAnnotation])
+}
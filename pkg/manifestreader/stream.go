@@ -33,6 +33,10 @@ func (r *StreamManifestReader) Read() ([]*unstructured.Unstructured, error) {
 	if err != nil {
 		return objs, err
 	}
+	nodes, err = r.substituteVariables(nodes)
+	if err != nil {
+		return objs, err
+	}
 
 	for _, n := range nodes {
 		err = RemoveAnnotations(n, kioutil.IndexAnnotation)
@@ -0,0 +1,84 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package manifestreader
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// VariableLookup returns the value of the variable with the name, and
+// whether the variable is defined. os.LookupEnv can be used to substitute
+// environment variables.
+type VariableLookup func(name string) (string, bool)
+
+// MapVariables returns a VariableLookup of the variables in the map.
+func MapVariables(vars map[string]string) VariableLookup {
+	return func(name string) (string, bool) {
+		value, found := vars[name]
+		return value, found
+	}
+}
+
+// UnresolvedVariablesError is returned in strict mode, if the manifests
+// reference variables that are not defined.
+type UnresolvedVariablesError struct {
+	Names []string
+}
+
+func (e *UnresolvedVariablesError) Error() string {
+	return fmt.Sprintf("unresolved variables: %s", strings.Join(e.Names, ", "))
+}
+
+// variableReference matches the ${NAME} variable references, and the
+// escaped $${NAME} references that are replaced by a literal ${NAME}.
+var variableReference = regexp.MustCompile(`\$?\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// SubstituteVariables replaces the ${NAME} references in the resources with
+// the value of the variables, before the resources are decoded, so that
+// variables can also be used for non-string values, like
+// `replicas: ${REPLICAS}`. $${NAME} is replaced by a literal ${NAME}.
+// References to undefined variables are left as is, unless strict is true,
+// in which case an UnresolvedVariablesError with all the undefined variables
+// is returned.
+func SubstituteVariables(nodes []*yaml.RNode, lookup VariableLookup, strict bool) ([]*yaml.RNode, error) {
+	unresolved := make(map[string]struct{})
+	substituted := make([]*yaml.RNode, 0, len(nodes))
+	for _, n := range nodes {
+		s, err := n.String()
+		if err != nil {
+			return nil, err
+		}
+		s = variableReference.ReplaceAllStringFunc(s, func(ref string) string {
+			if strings.HasPrefix(ref, "$$") {
+				return ref[1:]
+			}
+			name := variableReference.FindStringSubmatch(ref)[1]
+			value, found := lookup(name)
+			if !found {
+				unresolved[name] = struct{}{}
+				return ref
+			}
+			return value
+		})
+		sn, err := yaml.Parse(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid resource after variable substitution: %w", err)
+		}
+		substituted = append(substituted, sn)
+	}
+	if strict && len(unresolved) > 0 {
+		names := make([]string, 0, len(unresolved))
+		for name := range unresolved {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, &UnresolvedVariablesError{Names: names}
+	}
+	return substituted, nil
+}
@@ -0,0 +1,110 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package manifestreader
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
+)
+
+var templatedManifest = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: ${NAME}
+  annotations:
+    escaped: $${NAME}
+spec:
+  replicas: ${REPLICAS}
+  template:
+    spec:
+      containers:
+      - name: app
+        image: ${IMAGE}
+`
+
+func TestStreamManifestReader_Variables(t *testing.T) {
+	testCases := map[string]struct {
+		variables map[string]string
+		strict    bool
+
+		expectedErr   error
+		expectedName  string
+		expectedImage string
+	}{
+		"all variables defined": {
+			variables: map[string]string{
+				"NAME":     "web",
+				"REPLICAS": "3",
+				"IMAGE":    "app:1",
+			},
+			strict: true,
+
+			expectedName:  "web",
+			expectedImage: "app:1",
+		},
+		"undefined variables are kept": {
+			variables: map[string]string{
+				"NAME":     "web",
+				"REPLICAS": "3",
+			},
+
+			expectedName:  "web",
+			expectedImage: "${IMAGE}",
+		},
+		"undefined variables in strict mode": {
+			variables: map[string]string{},
+			strict:    true,
+
+			expectedErr: &UnresolvedVariablesError{
+				Names: []string{"IMAGE", "NAME", "REPLICAS"},
+			},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			tf := cmdtesting.NewTestFactory().WithNamespace("test-ns")
+			defer tf.Cleanup()
+
+			mapper, err := tf.ToRESTMapper()
+			require.NoError(t, err)
+
+			objs, err := (&StreamManifestReader{
+				ReaderName: "testReader",
+				Reader:     strings.NewReader(templatedManifest),
+				ReaderOptions: ReaderOptions{
+					Mapper:          mapper,
+					Namespace:       "test-ns",
+					Variables:       MapVariables(tc.variables),
+					StrictVariables: tc.strict,
+				},
+			}).Read()
+			if tc.expectedErr != nil {
+				require.EqualError(t, err, tc.expectedErr.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, objs, 1)
+
+			obj := objs[0]
+			assert.Equal(t, tc.expectedName, obj.GetName())
+			assert.Equal(t, "${NAME}", obj.GetAnnotations()["escaped"])
+			// substituted before decoding, so the replicas are a number
+			replicas, found, err := unstructured.NestedFieldNoCopy(obj.Object, "spec", "replicas")
+			require.NoError(t, err)
+			require.True(t, found)
+			assert.Equal(t, float64(3), replicas)
+			containers, _, err := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+			require.NoError(t, err)
+			require.Len(t, containers, 1)
+			assert.Equal(t, tc.expectedImage, containers[0].(map[string]interface{})["image"])
+		})
+	}
+}
@@ -51,15 +51,25 @@ func FormatDependencySet(depSet DependencySet) (string, error) {
 //
 // Object references are separated by ','.
 //
-// Returns the parsed DependencySet or an error if unable to parse.
+// Returns the parsed DependencySet or an error if unable to parse. Parse
+// errors are of type *object.ParseError, identifying the position of the
+// invalid object reference in the passed string.
 func ParseDependencySet(depsStr string) (DependencySet, error) {
 	objs := DependencySet{}
+	offset := 0
 	for i, objStr := range strings.Split(depsStr, annotationSeparator) {
 		obj, err := ParseObjMetadata(objStr)
 		if err != nil {
-			return objs, fmt.Errorf("failed to parse object reference (index: %d): %w", i, err)
+			trimmed := strings.TrimSpace(objStr)
+			return objs, &object.ParseError{
+				Value:  depsStr,
+				Offset: offset + strings.Index(objStr, trimmed),
+				Length: len(trimmed),
+				Cause:  fmt.Errorf("failed to parse object reference (index: %d): %w", i, err),
+			}
 		}
 		objs = append(objs, obj)
+		offset += len(objStr) + len(annotationSeparator)
 	}
 	return objs, nil
 }
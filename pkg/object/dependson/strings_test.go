@@ -5,6 +5,7 @@
 package dependson
 
 import (
+	"errors"
 	"testing"
 
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -89,6 +90,46 @@ func TestParseDependencySet(t *testing.T) {
 	}
 }
 
+func TestParseDependencySetErrorPosition(t *testing.T) {
+	testCases := map[string]struct {
+		annotation      string
+		expectedOffset  int
+		expectedInvalid string
+	}{
+		"first reference invalid": {
+			annotation:      "invalid,test-group/test-kind/cluster-obj",
+			expectedOffset:  0,
+			expectedInvalid: "invalid",
+		},
+		"second reference invalid with whitespace": {
+			annotation:      "test-group/test-kind/cluster-obj,  test-group/kind ",
+			expectedOffset:  35,
+			expectedInvalid: "test-group/kind",
+		},
+		"empty trailing reference": {
+			annotation:      "test-group/test-kind/cluster-obj,",
+			expectedOffset:  33,
+			expectedInvalid: "",
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			_, err := ParseDependencySet(tc.annotation)
+			var parseErr *object.ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("expected ParseError, got: %v", err)
+			}
+			if parseErr.Offset != tc.expectedOffset {
+				t.Errorf("expected offset %d, got %d", tc.expectedOffset, parseErr.Offset)
+			}
+			if parseErr.Invalid() != tc.expectedInvalid {
+				t.Errorf("expected invalid substring %q, got %q", tc.expectedInvalid, parseErr.Invalid())
+			}
+		})
+	}
+}
+
 func TestParseObjMetadata(t *testing.T) {
 	testCases := map[string]struct {
 		metaStr  string
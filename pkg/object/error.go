@@ -22,3 +22,34 @@ func (iae InvalidAnnotationError) Error() string {
 func (iae InvalidAnnotationError) Unwrap() error {
 	return iae.Cause
 }
+
+// ParseError represents a failure to parse an annotation value.
+// Offset and Length identify the invalid substring of Value (in bytes), to
+// allow linters and editors to highlight the exact location of the problem.
+type ParseError struct {
+	// Value is the full annotation value being parsed.
+	Value string
+	// Offset is the byte offset of the invalid substring in Value.
+	Offset int
+	// Length is the byte length of the invalid substring in Value.
+	Length int
+	// Cause describes why the substring is invalid.
+	Cause error
+}
+
+func (pe *ParseError) Error() string {
+	return pe.Cause.Error()
+}
+
+func (pe *ParseError) Unwrap() error {
+	return pe.Cause
+}
+
+// Invalid returns the invalid substring of Value.
+func (pe *ParseError) Invalid() string {
+	end := pe.Offset + pe.Length
+	if pe.Offset < 0 || end > len(pe.Value) || pe.Offset > end {
+		return ""
+	}
+	return pe.Value[pe.Offset:end]
+}
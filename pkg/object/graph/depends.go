@@ -124,6 +124,18 @@ func ReverseSortObjs(objs object.UnstructuredSet) ([]object.UnstructuredSet, err
 	return s, nil
 }
 
+// SortIDs returns the IDs of the objects in apply order. Objects that can
+// not be sorted, because of a dependency cycle, are last, in input order.
+// Invalid dependencies are ignored.
+func SortIDs(objs object.UnstructuredSet) object.ObjMetadataSet {
+	objSetList, _ := SortObjs(objs)
+	var ids object.ObjMetadataSet
+	for _, objSet := range objSetList {
+		ids = append(ids, object.UnstructuredSetToObjMetadataSet(objSet)...)
+	}
+	return ids.Union(object.UnstructuredSetToObjMetadataSet(objs))
+}
+
 // ReverseSetList deep reverses of a list of object lists
 func ReverseSetList(setList []object.UnstructuredSet) {
 	// Reverse the ordering of the object sets using swaps.
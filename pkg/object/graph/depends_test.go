@@ -377,6 +377,55 @@ func TestReverseSortObjs(t *testing.T) {
 	}
 }
 
+func TestSortIDs(t *testing.T) {
+	testCases := map[string]struct {
+		objs     []*unstructured.Unstructured
+		expected object.ObjMetadataSet
+	}{
+		"no objects": {
+			objs:     []*unstructured.Unstructured{},
+			expected: object.ObjMetadataSet{},
+		},
+		"objects are sorted in apply order": {
+			objs: []*unstructured.Unstructured{
+				testutil.Unstructured(t, resources["crontab1"]),
+				testutil.Unstructured(t, resources["deployment"],
+					testutil.AddDependsOn(t, testutil.ToIdentifier(t, resources["secret"]))),
+				testutil.Unstructured(t, resources["secret"]),
+				testutil.Unstructured(t, resources["namespace"]),
+				testutil.Unstructured(t, resources["crd"]),
+			},
+			expected: object.ObjMetadataSet{
+				testutil.ToIdentifier(t, resources["crd"]),
+				testutil.ToIdentifier(t, resources["namespace"]),
+				testutil.ToIdentifier(t, resources["secret"]),
+				testutil.ToIdentifier(t, resources["crontab1"]),
+				testutil.ToIdentifier(t, resources["deployment"]),
+			},
+		},
+		"objects with a dependency cycle are last": {
+			objs: []*unstructured.Unstructured{
+				testutil.Unstructured(t, resources["deployment"],
+					testutil.AddDependsOn(t, testutil.ToIdentifier(t, resources["secret"]))),
+				testutil.Unstructured(t, resources["secret"],
+					testutil.AddDependsOn(t, testutil.ToIdentifier(t, resources["deployment"]))),
+				testutil.Unstructured(t, resources["pod"]),
+			},
+			expected: object.ObjMetadataSet{
+				testutil.ToIdentifier(t, resources["pod"]),
+				testutil.ToIdentifier(t, resources["deployment"]),
+				testutil.ToIdentifier(t, resources["secret"]),
+			},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			testutil.AssertEqual(t, tc.expected, SortIDs(tc.objs))
+		})
+	}
+}
+
 func TestDependencyGraph(t *testing.T) {
 	// Use a custom Asserter to customize the graph options
 	asserter := testutil.NewAsserter(
@@ -0,0 +1,71 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package graph
+
+import (
+	"sort"
+
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+// SortByWave splits the sorted sets of the graph into apply waves. All the
+// sets of a wave come before the sets of the next wave, and the sets of each
+// wave stay in topological order.
+//
+// The waves map contains the requested wave of the objects, and objects that
+// are not in the map are in wave zero. An object is never in an earlier wave
+// than the objects it depends on, so objects depending on an object in a
+// later wave are moved to that wave.
+func SortByWave(g *Graph, idSetList []object.ObjMetadataSet, waves map[object.ObjMetadata]int) []object.ObjMetadataSet {
+	if len(waves) == 0 {
+		return idSetList
+	}
+
+	// The sets are in topological order, so the waves of the dependencies
+	// are known before the waves of their dependents.
+	effective := make(map[object.ObjMetadata]int)
+	waveOf := func(id object.ObjMetadata) int {
+		if wave, found := effective[id]; found {
+			return wave
+		}
+		return waves[id]
+	}
+	distinct := make(map[int]struct{})
+	for _, idSet := range idSetList {
+		for _, id := range idSet {
+			wave := waves[id]
+			for _, dep := range g.Dependencies(id) {
+				if depWave := waveOf(dep); depWave > wave {
+					klog.V(3).Infof("moving %s from wave %d to wave %d of its dependency %s",
+						id, wave, depWave, dep)
+					wave = depWave
+				}
+			}
+			effective[id] = wave
+			distinct[wave] = struct{}{}
+		}
+	}
+	sortedWaves := make([]int, 0, len(distinct))
+	for wave := range distinct {
+		sortedWaves = append(sortedWaves, wave)
+	}
+	sort.Ints(sortedWaves)
+
+	var waveSetList []object.ObjMetadataSet
+	for _, wave := range sortedWaves {
+		for _, idSet := range idSetList {
+			waveSet := object.ObjMetadataSet{}
+			for _, id := range idSet {
+				if effective[id] == wave {
+					waveSet = append(waveSet, id)
+				}
+			}
+			if len(waveSet) > 0 {
+				waveSetList = append(waveSetList, waveSet)
+			}
+		}
+	}
+	return waveSetList
+}
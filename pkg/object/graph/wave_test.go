@@ -0,0 +1,58 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package graph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+func TestSortByWave(t *testing.T) {
+	testCases := map[string]struct {
+		edges     []Edge
+		idSetList []object.ObjMetadataSet
+		waves     map[object.ObjMetadata]int
+		expected  []object.ObjMetadataSet
+	}{
+		"no waves": {
+			edges:     []Edge{e1},
+			idSetList: []object.ObjMetadataSet{{o2, o3}, {o1}},
+			expected:  []object.ObjMetadataSet{{o2, o3}, {o1}},
+		},
+		"independent objects": {
+			idSetList: []object.ObjMetadataSet{{o1, o2, o3, o4}},
+			waves:     map[object.ObjMetadata]int{o1: 2, o2: -1, o4: 2},
+			expected:  []object.ObjMetadataSet{{o2}, {o3}, {o1, o4}},
+		},
+		"dependencies within a wave keep their order": {
+			edges:     []Edge{e1, e4},
+			idSetList: []object.ObjMetadataSet{{o2, o4}, {o1, o3}},
+			waves:     map[object.ObjMetadata]int{o3: 1, o4: 1},
+			expected:  []object.ObjMetadataSet{{o2}, {o1}, {o4}, {o3}},
+		},
+		"dependents are moved to the wave of their dependencies": {
+			edges:     []Edge{e1, e2},
+			idSetList: []object.ObjMetadataSet{{o3}, {o2}, {o1}},
+			waves:     map[object.ObjMetadata]int{o1: 1, o3: 5},
+			expected:  []object.ObjMetadataSet{{o3}, {o2}, {o1}},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			g := New()
+			for _, idSet := range tc.idSetList {
+				for _, id := range idSet {
+					g.AddVertex(id)
+				}
+			}
+			for _, e := range tc.edges {
+				g.AddEdge(e.From, e.To)
+			}
+			assert.Equal(t, tc.expected, SortByWave(g, tc.idSetList, tc.waves))
+		})
+	}
+}
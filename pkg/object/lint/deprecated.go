@@ -0,0 +1,69 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package lint
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// deprecatedAPIs maps deprecated or removed API versions of built-in types
+// to the API version that replaces them.
+var deprecatedAPIs = map[schema.GroupVersionKind]string{
+	{Group: "extensions", Version: "v1beta1", Kind: "DaemonSet"}:                                        "apps/v1",
+	{Group: "extensions", Version: "v1beta1", Kind: "Deployment"}:                                       "apps/v1",
+	{Group: "extensions", Version: "v1beta1", Kind: "ReplicaSet"}:                                       "apps/v1",
+	{Group: "extensions", Version: "v1beta1", Kind: "Ingress"}:                                          "networking.k8s.io/v1",
+	{Group: "extensions", Version: "v1beta1", Kind: "NetworkPolicy"}:                                    "networking.k8s.io/v1",
+	{Group: "apps", Version: "v1beta1", Kind: "Deployment"}:                                             "apps/v1",
+	{Group: "apps", Version: "v1beta1", Kind: "StatefulSet"}:                                            "apps/v1",
+	{Group: "apps", Version: "v1beta2", Kind: "DaemonSet"}:                                              "apps/v1",
+	{Group: "apps", Version: "v1beta2", Kind: "Deployment"}:                                             "apps/v1",
+	{Group: "apps", Version: "v1beta2", Kind: "ReplicaSet"}:                                             "apps/v1",
+	{Group: "apps", Version: "v1beta2", Kind: "StatefulSet"}:                                            "apps/v1",
+	{Group: "networking.k8s.io", Version: "v1beta1", Kind: "Ingress"}:                                   "networking.k8s.io/v1",
+	{Group: "networking.k8s.io", Version: "v1beta1", Kind: "IngressClass"}:                              "networking.k8s.io/v1",
+	{Group: "batch", Version: "v1beta1", Kind: "CronJob"}:                                               "batch/v1",
+	{Group: "policy", Version: "v1beta1", Kind: "PodDisruptionBudget"}:                                  "policy/v1",
+	{Group: "autoscaling", Version: "v2beta1", Kind: "HorizontalPodAutoscaler"}:                         "autoscaling/v2",
+	{Group: "autoscaling", Version: "v2beta2", Kind: "HorizontalPodAutoscaler"}:                         "autoscaling/v2",
+	{Group: "rbac.authorization.k8s.io", Version: "v1beta1", Kind: "ClusterRole"}:                       "rbac.authorization.k8s.io/v1",
+	{Group: "rbac.authorization.k8s.io", Version: "v1beta1", Kind: "ClusterRoleBinding"}:                "rbac.authorization.k8s.io/v1",
+	{Group: "rbac.authorization.k8s.io", Version: "v1beta1", Kind: "Role"}:                              "rbac.authorization.k8s.io/v1",
+	{Group: "rbac.authorization.k8s.io", Version: "v1beta1", Kind: "RoleBinding"}:                       "rbac.authorization.k8s.io/v1",
+	{Group: "apiextensions.k8s.io", Version: "v1beta1", Kind: "CustomResourceDefinition"}:               "apiextensions.k8s.io/v1",
+	{Group: "apiregistration.k8s.io", Version: "v1beta1", Kind: "APIService"}:                           "apiregistration.k8s.io/v1",
+	{Group: "admissionregistration.k8s.io", Version: "v1beta1", Kind: "MutatingWebhookConfiguration"}:   "admissionregistration.k8s.io/v1",
+	{Group: "admissionregistration.k8s.io", Version: "v1beta1", Kind: "ValidatingWebhookConfiguration"}: "admissionregistration.k8s.io/v1",
+	{Group: "scheduling.k8s.io", Version: "v1beta1", Kind: "PriorityClass"}:                             "scheduling.k8s.io/v1",
+	{Group: "storage.k8s.io", Version: "v1beta1", Kind: "CSIDriver"}:                                    "storage.k8s.io/v1",
+	{Group: "storage.k8s.io", Version: "v1beta1", Kind: "CSINode"}:                                      "storage.k8s.io/v1",
+	{Group: "storage.k8s.io", Version: "v1beta1", Kind: "StorageClass"}:                                 "storage.k8s.io/v1",
+	{Group: "storage.k8s.io", Version: "v1beta1", Kind: "VolumeAttachment"}:                             "storage.k8s.io/v1",
+	{Group: "storage.k8s.io", Version: "v1beta1", Kind: "CSIStorageCapacity"}:                           "storage.k8s.io/v1",
+	{Group: "coordination.k8s.io", Version: "v1beta1", Kind: "Lease"}:                                   "coordination.k8s.io/v1",
+	{Group: "discovery.k8s.io", Version: "v1beta1", Kind: "EndpointSlice"}:                              "discovery.k8s.io/v1",
+	{Group: "events.k8s.io", Version: "v1beta1", Kind: "Event"}:                                         "events.k8s.io/v1",
+	{Group: "node.k8s.io", Version: "v1beta1", Kind: "RuntimeClass"}:                                    "node.k8s.io/v1",
+	{Group: "certificates.k8s.io", Version: "v1beta1", Kind: "CertificateSigningRequest"}:               "certificates.k8s.io/v1",
+	{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta1", Kind: "FlowSchema"}:                     "flowcontrol.apiserver.k8s.io/v1",
+	{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta1", Kind: "PriorityLevelConfiguration"}:     "flowcontrol.apiserver.k8s.io/v1",
+	{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta2", Kind: "FlowSchema"}:                     "flowcontrol.apiserver.k8s.io/v1",
+	{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta2", Kind: "PriorityLevelConfiguration"}:     "flowcontrol.apiserver.k8s.io/v1",
+	{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta3", Kind: "FlowSchema"}:                     "flowcontrol.apiserver.k8s.io/v1",
+	{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta3", Kind: "PriorityLevelConfiguration"}:     "flowcontrol.apiserver.k8s.io/v1",
+}
+
+// DeprecatedAPIError indicates that an object uses a deprecated or removed
+// API version.
+type DeprecatedAPIError struct {
+	GroupVersionKind schema.GroupVersionKind
+	Replacement      string
+}
+
+func (e *DeprecatedAPIError) Error() string {
+	return fmt.Sprintf("apiVersion %q of kind %q is deprecated, use %q instead",
+		e.GroupVersionKind.GroupVersion(), e.GroupVersionKind.Kind, e.Replacement)
+}
@@ -0,0 +1,225 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package lint validates a set of objects without applying them, combining
+// the checks performed by the applier with additional checks for likely
+// mistakes. Linting does not require access to a cluster, which makes it
+// suitable for use in CI.
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/cli-utils/pkg/multierror"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/cli-utils/pkg/object/graph"
+	"sigs.k8s.io/cli-utils/pkg/object/validation"
+)
+
+// Finding is a problem found by the linter.
+type Finding struct {
+	Severity Severity
+	// Identifiers of the objects the finding applies to.
+	Identifiers object.ObjMetadataSet
+	// Err describes the problem.
+	Err error
+}
+
+// String returns a string suitable for logging
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %v", f.Severity, validation.NewError(f.Err, f.Identifiers...))
+}
+
+// Findings is a list of linter findings.
+type Findings []Finding
+
+// HasErrors returns true if any of the findings has SeverityError.
+func (fs Findings) HasErrors() bool {
+	for _, f := range fs {
+		if f.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// DuplicateObjectError indicates that the same object is specified more
+// than once.
+type DuplicateObjectError struct {
+	Identifier object.ObjMetadata
+	Count      int
+}
+
+func (e *DuplicateObjectError) Error() string {
+	return fmt.Sprintf("duplicate object: %q is specified %d times", e.Identifier, e.Count)
+}
+
+// Linter validates a set of objects without applying them.
+type Linter struct {
+	// Mapper is used to look up the scope of the object types. If not
+	// specified, only the built-in types and the types defined by CRDs in the
+	// object set are known.
+	Mapper meta.RESTMapper
+}
+
+// Lint validates the objects, without access to a cluster, and returns the
+// problems found, graded by severity.
+func Lint(objs object.UnstructuredSet) Findings {
+	return (&Linter{}).Lint(objs)
+}
+
+// Lint validates the objects and returns the problems found, graded by
+// severity. The checks include duplicate objects, missing or invalid
+// identifiers, namespace scope mismatches, invalid cli-utils annotations,
+// invalid or cyclic dependencies, and deprecated API versions.
+func (l *Linter) Lint(objs object.UnstructuredSet) Findings {
+	mapper := l.Mapper
+	if mapper == nil {
+		mapper = builtinMapper()
+	}
+	var findings Findings
+	findings = append(findings, lintDuplicates(objs)...)
+	findings = append(findings, lintObjects(objs, mapper)...)
+	findings = append(findings, lintAnnotations(objs)...)
+	findings = append(findings, lintDependencies(objs)...)
+	findings = append(findings, lintDeprecatedAPIs(objs)...)
+	return findings
+}
+
+// lintDuplicates finds objects with the same identifier.
+func lintDuplicates(objs object.UnstructuredSet) Findings {
+	counts := make(map[object.ObjMetadata]int, len(objs))
+	var ids object.ObjMetadataSet
+	for _, obj := range objs {
+		id := object.UnstructuredToObjMetadata(obj)
+		if counts[id] == 0 {
+			ids = append(ids, id)
+		}
+		counts[id]++
+	}
+	var findings Findings
+	for _, id := range ids {
+		if counts[id] > 1 {
+			findings = append(findings, Finding{
+				Severity:    SeverityError,
+				Identifiers: object.ObjMetadataSet{id},
+				Err:         &DuplicateObjectError{Identifier: id, Count: counts[id]},
+			})
+		}
+	}
+	return findings
+}
+
+// lintObjects runs the validation performed by the applier. Types that are
+// unknown to the mapper are reported as warnings, because they may be
+// defined in the cluster.
+func lintObjects(objs object.UnstructuredSet, mapper meta.RESTMapper) Findings {
+	collector := &validation.Collector{}
+	validator := &validation.Validator{
+		Mapper:    mapper,
+		Collector: collector,
+	}
+	validator.Validate(objs)
+	var findings Findings
+	for _, err := range collector.Errors {
+		findings = append(findings, findingsFromError(err, func(cause error) Severity {
+			var unknownTypeErr *object.UnknownTypeError
+			if errors.As(cause, &unknownTypeErr) {
+				return SeverityWarning
+			}
+			return SeverityError
+		})...)
+	}
+	return findings
+}
+
+// lintAnnotations validates the cli-utils annotations, like the applier
+// does in strict mode. Invalid values of the annotations that the applier
+// always validates are reported as errors, because the applier rejects them.
+func lintAnnotations(objs object.UnstructuredSet) Findings {
+	var findings Findings
+	for _, obj := range objs {
+		id := object.UnstructuredToObjMetadata(obj)
+		annotations := obj.GetAnnotations()
+		keys := make([]string, 0, len(annotations))
+		for key := range annotations {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			err := validation.ValidateAnnotation(key, annotations[key])
+			if err == nil {
+				continue
+			}
+			severity := SeverityWarning
+			if validation.IsApplierValidatedAnnotation(key) {
+				severity = SeverityError
+			}
+			findings = append(findings, Finding{
+				Severity:    severity,
+				Identifiers: object.ObjMetadataSet{id},
+				Err:         err,
+			})
+		}
+	}
+	return findings
+}
+
+// lintDependencies validates the dependencies between the objects. Objects
+// with invalid dependencies are skipped by the applier, so all problems are
+// reported as errors.
+func lintDependencies(objs object.UnstructuredSet) Findings {
+	severity := func(error) Severity {
+		return SeverityError
+	}
+	var findings Findings
+	g, err := graph.DependencyGraph(objs)
+	if err != nil {
+		findings = append(findings, findingsFromError(err, severity)...)
+	}
+	if _, err := g.Sort(); err != nil {
+		findings = append(findings, findingsFromError(err, severity)...)
+	}
+	return findings
+}
+
+// lintDeprecatedAPIs finds objects that use deprecated API versions.
+func lintDeprecatedAPIs(objs object.UnstructuredSet) Findings {
+	var findings Findings
+	for _, obj := range objs {
+		gvk := obj.GroupVersionKind()
+		if replacement, found := deprecatedAPIs[gvk]; found {
+			findings = append(findings, Finding{
+				Severity:    SeverityWarning,
+				Identifiers: object.ObjMetadataSet{object.UnstructuredToObjMetadata(obj)},
+				Err:         &DeprecatedAPIError{GroupVersionKind: gvk, Replacement: replacement},
+			})
+		}
+	}
+	return findings
+}
+
+// findingsFromError converts the validation errors into findings, with one
+// finding per cause.
+func findingsFromError(err error, severity func(error) Severity) Findings {
+	var findings Findings
+	for _, err := range multierror.Unwrap(err) {
+		var ids object.ObjMetadataSet
+		var vErr *validation.Error
+		if errors.As(err, &vErr) {
+			ids = vErr.Identifiers()
+			err = vErr.Unwrap()
+		}
+		for _, cause := range multierror.Unwrap(err) {
+			findings = append(findings, Finding{
+				Severity:    severity(cause),
+				Identifiers: ids,
+				Err:         cause,
+			})
+		}
+	}
+	return findings
+}
@@ -0,0 +1,176 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package lint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+func newObject(apiVersion, kind, namespace, name string, annotations map[string]string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion(apiVersion)
+	u.SetKind(kind)
+	u.SetNamespace(namespace)
+	u.SetName(name)
+	u.SetAnnotations(annotations)
+	return u
+}
+
+func newCRD(group, kind, scope string) *unstructured.Unstructured {
+	crd := newObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "", kind, nil)
+	crd.Object["spec"] = map[string]interface{}{
+		"group": group,
+		"names": map[string]interface{}{
+			"kind": kind,
+		},
+		"scope": scope,
+		"versions": []interface{}{
+			map[string]interface{}{
+				"name": "v1",
+			},
+		},
+	}
+	return crd
+}
+
+func TestLint(t *testing.T) {
+	testCases := map[string]struct {
+		objs             object.UnstructuredSet
+		expectedFindings []string
+		expectedErrors   bool
+	}{
+		"no objects": {},
+		"valid objects": {
+			objs: object.UnstructuredSet{
+				newObject("v1", "Namespace", "", "foo", nil),
+				newObject("apps/v1", "Deployment", "foo", "bar", map[string]string{
+					"config.kubernetes.io/depends-on": "/namespaces/foo/ConfigMap/baz",
+				}),
+				newObject("v1", "ConfigMap", "foo", "baz", nil),
+				newCRD("example.com", "Widget", "Cluster"),
+				newObject("example.com/v1", "Widget", "", "widget", nil),
+			},
+		},
+		"duplicate objects": {
+			objs: object.UnstructuredSet{
+				newObject("v1", "ConfigMap", "foo", "bar", nil),
+				newObject("v1", "ConfigMap", "foo", "bar", nil),
+			},
+			expectedFindings: []string{
+				`Error: invalid object: "foo_bar__ConfigMap": duplicate object: "foo_bar__ConfigMap" is specified 2 times`,
+			},
+			expectedErrors: true,
+		},
+		"scope mismatches": {
+			objs: object.UnstructuredSet{
+				newObject("v1", "ConfigMap", "", "foo", nil),
+				newObject("rbac.authorization.k8s.io/v1", "ClusterRole", "foo", "bar", nil),
+			},
+			expectedFindings: []string{
+				`Error: invalid object: "_foo__ConfigMap": metadata.namespace: Required value: namespace is required`,
+				`Error: invalid object: "foo_bar_rbac.authorization.k8s.io_ClusterRole": metadata.namespace: Invalid value: "foo": namespace must be empty`,
+			},
+			expectedErrors: true,
+		},
+		"unknown type": {
+			objs: object.UnstructuredSet{
+				newObject("example.com/v1", "Widget", "", "widget", nil),
+			},
+			expectedFindings: []string{
+				`Warning: invalid object: "_widget_example.com_Widget": unknown resource type: "example.com/v1, Kind=Widget"`,
+			},
+		},
+		"invalid annotations": {
+			objs: object.UnstructuredSet{
+				newObject("v1", "ConfigMap", "foo", "bar", map[string]string{
+					"cli-utils.sigs.k8s.io/on-remove": "Keep",
+				}),
+			},
+			expectedFindings: []string{
+				`Warning: invalid object: "foo_bar__ConfigMap": metadata.annotations[cli-utils.sigs.k8s.io/on-remove]: Invalid value: "Keep": unsupported value "Keep": supported values: "delete", "keep", "orphan"`,
+			},
+		},
+		"unsupported apply strategy": {
+			objs: object.UnstructuredSet{
+				newObject("v1", "ConfigMap", "foo", "bar", map[string]string{
+					"cli-utils.sigs.k8s.io/apply-strategy": "merge",
+				}),
+			},
+			expectedFindings: []string{
+				`Error: invalid object: "foo_bar__ConfigMap": metadata.annotations[cli-utils.sigs.k8s.io/apply-strategy]: Invalid value: "merge": unsupported value "merge": supported values: ["server-side" "client-side" "replace" "create-only"]`,
+			},
+			expectedErrors: true,
+		},
+		"invalid apply wave": {
+			objs: object.UnstructuredSet{
+				newObject("v1", "ConfigMap", "foo", "bar", map[string]string{
+					"cli-utils.sigs.k8s.io/apply-wave": "first",
+				}),
+			},
+			expectedFindings: []string{
+				`Error: invalid object: "foo_bar__ConfigMap": metadata.annotations[cli-utils.sigs.k8s.io/apply-wave]: Invalid value: "first": invalid apply wave "first": must be an integer`,
+			},
+			expectedErrors: true,
+		},
+		"invalid apply hook, recreate and reconcile timeout": {
+			objs: object.UnstructuredSet{
+				newObject("v1", "ConfigMap", "foo", "bar", map[string]string{
+					"cli-utils.sigs.k8s.io/apply-hook":        "post-delete",
+					"cli-utils.sigs.k8s.io/recreate":          "sometimes",
+					"cli-utils.sigs.k8s.io/reconcile-timeout": "soon",
+				}),
+			},
+			expectedFindings: []string{
+				`Error: invalid object: "foo_bar__ConfigMap": metadata.annotations[cli-utils.sigs.k8s.io/apply-hook]: Invalid value: "post-delete": unsupported value "post-delete": supported values: ["pre-apply" "post-apply" "post-reconcile" "test"]`,
+				`Error: invalid object: "foo_bar__ConfigMap": metadata.annotations[cli-utils.sigs.k8s.io/reconcile-timeout]: Invalid value: "soon": invalid timeout "soon": time: invalid duration "soon"`,
+				`Error: invalid object: "foo_bar__ConfigMap": metadata.annotations[cli-utils.sigs.k8s.io/recreate]: Invalid value: "sometimes": unsupported value "sometimes": supported values: ["true" "false"]`,
+			},
+			expectedErrors: true,
+		},
+		"invalid dependencies": {
+			objs: object.UnstructuredSet{
+				newObject("v1", "ConfigMap", "foo", "a", map[string]string{
+					"config.kubernetes.io/depends-on": "/namespaces/foo/ConfigMap/b",
+				}),
+				newObject("v1", "ConfigMap", "foo", "b", map[string]string{
+					"config.kubernetes.io/depends-on": "/namespaces/foo/ConfigMap/a",
+				}),
+				newObject("v1", "ConfigMap", "foo", "c", map[string]string{
+					"config.kubernetes.io/depends-on": "/namespaces/foo/ConfigMap/missing",
+				}),
+			},
+			expectedFindings: []string{
+				`Error: invalid object: "foo_c__ConfigMap": invalid "config.kubernetes.io/depends-on" annotation: external dependency: /namespaces/foo/ConfigMap/c -> /namespaces/foo/ConfigMap/missing`,
+				`Error: invalid objects: ["foo_a__ConfigMap", "foo_b__ConfigMap"] cyclic dependency:
+- /namespaces/foo/ConfigMap/a -> /namespaces/foo/ConfigMap/b
+- /namespaces/foo/ConfigMap/b -> /namespaces/foo/ConfigMap/a`,
+			},
+			expectedErrors: true,
+		},
+		"deprecated API version": {
+			objs: object.UnstructuredSet{
+				newObject("networking.k8s.io/v1beta1", "Ingress", "foo", "bar", nil),
+			},
+			expectedFindings: []string{
+				`Warning: invalid object: "foo_bar_networking.k8s.io_Ingress": apiVersion "networking.k8s.io/v1beta1" of kind "Ingress" is deprecated, use "networking.k8s.io/v1" instead`,
+			},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			findings := Lint(tc.objs)
+			var actual []string
+			for _, f := range findings {
+				actual = append(actual, f.String())
+			}
+			assert.Equal(t, tc.expectedFindings, actual)
+			assert.Equal(t, tc.expectedErrors, findings.HasErrors())
+		})
+	}
+}
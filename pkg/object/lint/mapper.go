@@ -0,0 +1,79 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package lint
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/kubectl/pkg/scheme"
+)
+
+// clusterScopedKinds are the built-in types that are not namespaced.
+var clusterScopedKinds = map[schema.GroupKind]struct{}{
+	{Group: "", Kind: "ComponentStatus"}:                                              {},
+	{Group: "", Kind: "Namespace"}:                                                    {},
+	{Group: "", Kind: "Node"}:                                                         {},
+	{Group: "", Kind: "PersistentVolume"}:                                             {},
+	{Group: "admissionregistration.k8s.io", Kind: "MutatingWebhookConfiguration"}:     {},
+	{Group: "admissionregistration.k8s.io", Kind: "ValidatingAdmissionPolicy"}:        {},
+	{Group: "admissionregistration.k8s.io", Kind: "ValidatingAdmissionPolicyBinding"}: {},
+	{Group: "admissionregistration.k8s.io", Kind: "ValidatingWebhookConfiguration"}:   {},
+	{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}:                 {},
+	{Group: "apiregistration.k8s.io", Kind: "APIService"}:                             {},
+	{Group: "certificates.k8s.io", Kind: "CertificateSigningRequest"}:                 {},
+	{Group: "certificates.k8s.io", Kind: "ClusterTrustBundle"}:                        {},
+	{Group: "flowcontrol.apiserver.k8s.io", Kind: "FlowSchema"}:                       {},
+	{Group: "flowcontrol.apiserver.k8s.io", Kind: "PriorityLevelConfiguration"}:       {},
+	{Group: "networking.k8s.io", Kind: "IngressClass"}:                                {},
+	{Group: "networking.k8s.io", Kind: "IPAddress"}:                                   {},
+	{Group: "networking.k8s.io", Kind: "ServiceCIDR"}:                                 {},
+	{Group: "node.k8s.io", Kind: "RuntimeClass"}:                                      {},
+	{Group: "policy", Kind: "PodSecurityPolicy"}:                                      {},
+	{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"}:                         {},
+	{Group: "rbac.authorization.k8s.io", Kind: "ClusterRoleBinding"}:                  {},
+	{Group: "resource.k8s.io", Kind: "DeviceClass"}:                                   {},
+	{Group: "resource.k8s.io", Kind: "ResourceSlice"}:                                 {},
+	{Group: "scheduling.k8s.io", Kind: "PriorityClass"}:                               {},
+	{Group: "storage.k8s.io", Kind: "CSIDriver"}:                                      {},
+	{Group: "storage.k8s.io", Kind: "CSINode"}:                                        {},
+	{Group: "storage.k8s.io", Kind: "StorageClass"}:                                   {},
+	{Group: "storage.k8s.io", Kind: "VolumeAttachment"}:                               {},
+	{Group: "storage.k8s.io", Kind: "VolumeAttributesClass"}:                          {},
+}
+
+// extraKinds are built-in types that are not registered in the kubectl
+// scheme.
+var extraKinds = []schema.GroupVersionKind{
+	{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"},
+	{Group: "apiregistration.k8s.io", Version: "v1", Kind: "APIService"},
+}
+
+// builtinMapper returns a RESTMapper for the built-in Kubernetes types,
+// which can be used without access to a cluster.
+func builtinMapper() meta.RESTMapper {
+	var groupVersions []schema.GroupVersion
+	for _, gvk := range extraKinds {
+		groupVersions = append(groupVersions, gvk.GroupVersion())
+	}
+	groupVersions = append(groupVersions, scheme.Scheme.PrioritizedVersionsAllGroups()...)
+	mapper := meta.NewDefaultRESTMapper(groupVersions)
+	gvks := append([]schema.GroupVersionKind{}, extraKinds...)
+	for gvk := range scheme.Scheme.AllKnownTypes() {
+		if gvk.Version == runtime.APIVersionInternal || strings.HasSuffix(gvk.Kind, "List") {
+			continue
+		}
+		gvks = append(gvks, gvk)
+	}
+	for _, gvk := range gvks {
+		scope := meta.RESTScopeNamespace
+		if _, found := clusterScopedKinds[gvk.GroupKind()]; found {
+			scope = meta.RESTScopeRoot
+		}
+		mapper.Add(gvk, scope)
+	}
+	return mapper
+}
@@ -0,0 +1,19 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package lint
+
+// Severity is the severity of a linter finding.
+//
+//go:generate stringer -type=Severity -linecomment
+type Severity int
+
+const (
+	// SeverityWarning indicates a problem that does not prevent the objects
+	// from being applied, but is likely unintended.
+	SeverityWarning Severity = iota // Warning
+
+	// SeverityError indicates a problem that causes the objects to be
+	// rejected or skipped when applied.
+	SeverityError // Error
+)
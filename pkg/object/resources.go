@@ -0,0 +1,161 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package object
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ResourceRequests are the compute and storage resources requested by a set
+// of objects.
+type ResourceRequests struct {
+	CPU     resource.Quantity
+	Memory  resource.Quantity
+	Storage resource.Quantity
+}
+
+// IsZero returns true if no resources are requested.
+func (r *ResourceRequests) IsZero() bool {
+	return r.CPU.IsZero() && r.Memory.IsZero() && r.Storage.IsZero()
+}
+
+// Add adds the resources requested by the other requests.
+func (r *ResourceRequests) Add(other ResourceRequests) {
+	r.CPU.Add(other.CPU)
+	r.Memory.Add(other.Memory)
+	r.Storage.Add(other.Storage)
+}
+
+// SumResourceRequests returns the resources requested by the objects.
+// See ObjectResourceRequests.
+func SumResourceRequests(objs UnstructuredSet) (ResourceRequests, error) {
+	var sum ResourceRequests
+	for _, obj := range objs {
+		requests, err := ObjectResourceRequests(obj)
+		if err != nil {
+			return ResourceRequests{}, err
+		}
+		sum.Add(requests)
+	}
+	return sum, nil
+}
+
+// ObjectResourceRequests returns the resources requested by the object:
+//   - Pods request the resources of their containers.
+//   - Workloads with a pod template, at spec.template or
+//     spec.jobTemplate.spec.template, request the resources of the pod
+//     template, and the storage of their volume claim templates, once per
+//     replica (spec.replicas, defaulting to one).
+//   - PersistentVolumeClaims request their storage.
+//
+// Other objects do not request resources.
+func ObjectResourceRequests(obj *unstructured.Unstructured) (ResourceRequests, error) {
+	var requests ResourceRequests
+	switch obj.GroupVersionKind().GroupKind() {
+	case schema.GroupKind{Kind: "PersistentVolumeClaim"}:
+		var spec corev1.PersistentVolumeClaimSpec
+		found, err := nestedObject(obj, &spec, "spec")
+		if err != nil || !found {
+			return requests, err
+		}
+		requests.Storage = storageRequest(spec)
+		return requests, nil
+	case schema.GroupKind{Kind: "Pod"}:
+		var spec corev1.PodSpec
+		found, err := nestedObject(obj, &spec, "spec")
+		if err != nil || !found {
+			return requests, err
+		}
+		return podRequests(spec), nil
+	}
+
+	var spec corev1.PodSpec
+	found, err := nestedObject(obj, &spec, "spec", "template", "spec")
+	if err != nil {
+		return requests, err
+	}
+	if !found {
+		found, err = nestedObject(obj, &spec, "spec", "jobTemplate", "spec", "template", "spec")
+		if err != nil || !found {
+			return requests, err
+		}
+	}
+	requests = podRequests(spec)
+	claims, _, err := unstructured.NestedSlice(obj.Object, "spec", "volumeClaimTemplates")
+	if err != nil {
+		return requests, fmt.Errorf("invalid volume claim templates of %s: %w", UnstructuredToObjMetadata(obj), err)
+	}
+	for i := range claims {
+		var spec corev1.PersistentVolumeClaimSpec
+		if _, err := nestedObject(obj, &spec, "spec", "volumeClaimTemplates", i, "spec"); err != nil {
+			return requests, err
+		}
+		requests.Storage.Add(storageRequest(spec))
+	}
+	replicas, found, err := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+	if err != nil {
+		return requests, fmt.Errorf("invalid replicas of %s: %w", UnstructuredToObjMetadata(obj), err)
+	}
+	if found {
+		requests.CPU.Mul(replicas)
+		requests.Memory.Mul(replicas)
+		requests.Storage.Mul(replicas)
+	}
+	return requests, nil
+}
+
+// nestedObject converts the field of the object into the typed value.
+// Returns false if the field is not found.
+func nestedObject(obj *unstructured.Unstructured, value interface{}, path ...interface{}) (bool, error) {
+	field, found, err := NestedField(obj.Object, path...)
+	if err != nil || !found {
+		return found, err
+	}
+	fieldObj, ok := field.(map[string]interface{})
+	if !ok {
+		return false, InvalidType(path, field, "map[string]interface{}")
+	}
+	err = runtime.DefaultUnstructuredConverter.FromUnstructured(fieldObj, value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s of %s: %w", FieldPath(path), UnstructuredToObjMetadata(obj), err)
+	}
+	return true, nil
+}
+
+// podRequests returns the resources requested by a pod: the sum of the
+// requests of the containers, or the largest request of the init containers,
+// whichever is larger. Containers without requests request their limits.
+func podRequests(spec corev1.PodSpec) ResourceRequests {
+	var requests ResourceRequests
+	for _, container := range spec.Containers {
+		requests.CPU.Add(containerRequest(container, corev1.ResourceCPU))
+		requests.Memory.Add(containerRequest(container, corev1.ResourceMemory))
+	}
+	for _, container := range spec.InitContainers {
+		if cpu := containerRequest(container, corev1.ResourceCPU); cpu.Cmp(requests.CPU) > 0 {
+			requests.CPU = cpu
+		}
+		if memory := containerRequest(container, corev1.ResourceMemory); memory.Cmp(requests.Memory) > 0 {
+			requests.Memory = memory
+		}
+	}
+	return requests
+}
+
+func containerRequest(container corev1.Container, name corev1.ResourceName) resource.Quantity {
+	if quantity, found := container.Resources.Requests[name]; found {
+		return quantity
+	}
+	return container.Resources.Limits[name]
+}
+
+func storageRequest(spec corev1.PersistentVolumeClaimSpec) resource.Quantity {
+	return spec.Resources.Requests[corev1.ResourceStorage]
+}
@@ -0,0 +1,185 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package object_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/cli-utils/pkg/testutil"
+)
+
+var deploymentWithRequests = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: default
+spec:
+  replicas: 3
+  template:
+    spec:
+      initContainers:
+      - name: init
+        resources:
+          requests:
+            cpu: 2
+      containers:
+      - name: app
+        resources:
+          requests:
+            cpu: 500m
+            memory: 256Mi
+      - name: sidecar
+        resources:
+          limits:
+            cpu: 100m
+            memory: 64Mi
+`
+
+var statefulSetWithClaims = `
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: db
+  namespace: default
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+      - name: db
+        resources:
+          requests:
+            memory: 1Gi
+  volumeClaimTemplates:
+  - metadata:
+      name: data
+    spec:
+      resources:
+        requests:
+          storage: 10Gi
+`
+
+var cronJobWithRequests = `
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: backup
+  namespace: default
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+          - name: backup
+            resources:
+              requests:
+                cpu: 250m
+`
+
+var pvcWithRequests = `
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: cache
+  namespace: default
+spec:
+  resources:
+    requests:
+      storage: 5Gi
+`
+
+var podWithInvalidRequests = `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: pod
+  namespace: default
+spec:
+  containers:
+  - name: app
+    resources:
+      requests:
+        cpu: lots
+`
+
+func TestObjectResourceRequests(t *testing.T) {
+	testCases := map[string]struct {
+		obj           string
+		expected      object.ResourceRequests
+		expectedError bool
+	}{
+		"deployment": {
+			obj: deploymentWithRequests,
+			expected: object.ResourceRequests{
+				// max(500m + 100m, 2) per replica
+				CPU:    resource.MustParse("6"),
+				Memory: resource.MustParse("960Mi"),
+			},
+		},
+		"statefulset": {
+			obj: statefulSetWithClaims,
+			expected: object.ResourceRequests{
+				Memory:  resource.MustParse("2Gi"),
+				Storage: resource.MustParse("20Gi"),
+			},
+		},
+		"cronjob": {
+			obj: cronJobWithRequests,
+			expected: object.ResourceRequests{
+				CPU: resource.MustParse("250m"),
+			},
+		},
+		"pvc": {
+			obj: pvcWithRequests,
+			expected: object.ResourceRequests{
+				Storage: resource.MustParse("5Gi"),
+			},
+		},
+		"no requests": {
+			obj: rbac,
+		},
+		"invalid requests": {
+			obj:           podWithInvalidRequests,
+			expectedError: true,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			requests, err := object.ObjectResourceRequests(testutil.Unstructured(t, tc.obj))
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assertQuantity(t, tc.expected.CPU, requests.CPU)
+			assertQuantity(t, tc.expected.Memory, requests.Memory)
+			assertQuantity(t, tc.expected.Storage, requests.Storage)
+		})
+	}
+}
+
+func TestSumResourceRequests(t *testing.T) {
+	requests, err := object.SumResourceRequests(object.UnstructuredSet{
+		testutil.Unstructured(t, statefulSetWithClaims),
+		testutil.Unstructured(t, pvcWithRequests),
+		testutil.Unstructured(t, cronJobWithRequests),
+	})
+	require.NoError(t, err)
+	assertQuantity(t, resource.MustParse("250m"), requests.CPU)
+	assertQuantity(t, resource.MustParse("2Gi"), requests.Memory)
+	assertQuantity(t, resource.MustParse("25Gi"), requests.Storage)
+	assert.False(t, requests.IsZero())
+}
+
+func assertQuantity(t *testing.T, expected, actual resource.Quantity) {
+	t.Helper()
+	assert.Zero(t, expected.Cmp(actual), "expected %s, got %s", expected.String(), actual.String())
+}
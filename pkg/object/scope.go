@@ -0,0 +1,66 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package object
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// IsClusterScoped returns true if the RESTMapper reports the GroupKind as
+// cluster-scoped (root scope). Returns false without error if the GroupKind
+// is not (yet) registered with the RESTMapper, because the scope can't be
+// determined.
+func IsClusterScoped(gk schema.GroupKind, mapper meta.RESTMapper) (bool, error) {
+	mapping, err := mapper.RESTMapping(gk)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return mapping.Scope.Name() == meta.RESTScopeNameRoot, nil
+}
+
+// NormalizeNamespace returns a copy of the ObjMetadata with the namespace
+// cleared, if the RESTMapper reports the GroupKind as cluster-scoped.
+// Cluster-scoped objects are always stored and compared with an empty
+// namespace, even if a namespace was defaulted by the client that produced
+// the ObjMetadata. Unknown types are returned unchanged.
+func NormalizeNamespace(id ObjMetadata, mapper meta.RESTMapper) (ObjMetadata, error) {
+	if id.Namespace == "" {
+		return id, nil
+	}
+	clusterScoped, err := IsClusterScoped(id.GroupKind, mapper)
+	if err != nil {
+		return id, err
+	}
+	if clusterScoped {
+		id.Namespace = ""
+	}
+	return id, nil
+}
+
+// NormalizeNamespaces returns a new ObjMetadataSet with the namespace of all
+// cluster-scoped objects cleared. Duplicates created by normalization are
+// removed, preserving the order of first appearance.
+func NormalizeNamespaces(ids ObjMetadataSet, mapper meta.RESTMapper) (ObjMetadataSet, error) {
+	if ids == nil {
+		return nil, nil
+	}
+	normalized := make(ObjMetadataSet, 0, len(ids))
+	seen := make(map[ObjMetadata]struct{}, len(ids))
+	for _, id := range ids {
+		nid, err := NormalizeNamespace(id, mapper)
+		if err != nil {
+			return nil, err
+		}
+		if _, found := seen[nid]; found {
+			continue
+		}
+		seen[nid] = struct{}{}
+		normalized = append(normalized, nid)
+	}
+	return normalized, nil
+}
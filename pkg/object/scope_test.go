@@ -0,0 +1,65 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package object
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta/testrestmapper"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/kubectl/pkg/scheme"
+)
+
+func TestNormalizeNamespaces(t *testing.T) {
+	mapper := testrestmapper.TestOnlyStaticRESTMapper(scheme.Scheme,
+		scheme.Scheme.PrioritizedVersionsAllGroups()...)
+
+	crb := ObjMetadata{
+		GroupKind: schema.GroupKind{Group: "rbac.authorization.k8s.io", Kind: "ClusterRoleBinding"},
+		Name:      "crb",
+	}
+	crbDefaulted := crb
+	crbDefaulted.Namespace = "default"
+	unknown := ObjMetadata{
+		GroupKind: schema.GroupKind{Group: "example.com", Kind: "Unknown"},
+		Name:      "unknown",
+		Namespace: "default",
+	}
+
+	testCases := map[string]struct {
+		ids      ObjMetadataSet
+		expected ObjMetadataSet
+	}{
+		"nil set": {
+			ids:      nil,
+			expected: nil,
+		},
+		"namespaced objects unchanged": {
+			ids:      ObjMetadataSet{objMeta1, objMeta3},
+			expected: ObjMetadataSet{objMeta1, objMeta3},
+		},
+		"cluster-scoped namespace cleared": {
+			ids:      ObjMetadataSet{objMeta1, crbDefaulted},
+			expected: ObjMetadataSet{objMeta1, crb},
+		},
+		"duplicates removed preserving order": {
+			ids:      ObjMetadataSet{crbDefaulted, objMeta1, crb},
+			expected: ObjMetadataSet{crb, objMeta1},
+		},
+		"unknown types unchanged": {
+			ids:      ObjMetadataSet{unknown},
+			expected: ObjMetadataSet{unknown},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			actual, err := NormalizeNamespaces(tc.ids, mapper)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
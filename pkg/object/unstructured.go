@@ -14,8 +14,9 @@ import (
 )
 
 var (
-	namespaceGK = schema.GroupKind{Group: "", Kind: "Namespace"}
-	crdGK       = schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}
+	namespaceGK  = schema.GroupKind{Group: "", Kind: "Namespace"}
+	crdGK        = schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}
+	apiServiceGK = schema.GroupKind{Group: "apiregistration.k8s.io", Kind: "APIService"}
 )
 
 // UnstructuredSetToObjMetadataSet converts a UnstructuredSet to a ObjMetadataSet.
@@ -77,6 +78,15 @@ func IsCRD(u *unstructured.Unstructured) bool {
 	return crdGK == gvk.GroupKind()
 }
 
+// IsAPIService returns true if the passed Unstructured object has
+// GroupKind == apiregistration.k8s.io/APIService; false otherwise.
+func IsAPIService(u *unstructured.Unstructured) bool {
+	if u == nil {
+		return false
+	}
+	return apiServiceGK == u.GroupVersionKind().GroupKind()
+}
+
 // GetCRDGroupKind returns the GroupKind stored in the passed
 // Unstructured CustomResourceDefinition and true if the passed object
 // is a CRD.
@@ -212,3 +222,22 @@ func StripKyamlAnnotations(u *unstructured.Unstructured) {
 	delete(annos, kioutil.LegacyIndexAnnotation) //nolint:staticcheck
 	u.SetAnnotations(annos)
 }
+
+// ServerManagedMetadataFields are the metadata fields set by the server,
+// which are not part of the configuration of an object.
+var ServerManagedMetadataFields = []string{
+	"creationTimestamp",
+	"generation",
+	"managedFields",
+	"resourceVersion",
+	"selfLink",
+	"uid",
+}
+
+// StripServerManagedMetadata removes the ServerManagedMetadataFields from
+// the unstructured resource.
+func StripServerManagedMetadata(u *unstructured.Unstructured) {
+	for _, field := range ServerManagedMetadataFields {
+		unstructured.RemoveNestedField(u.Object, "metadata", field)
+	}
+}
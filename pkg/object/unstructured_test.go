@@ -334,3 +334,30 @@ func TestLookupResourceScope(t *testing.T) {
 		})
 	}
 }
+
+func TestStripServerManagedMetadata(t *testing.T) {
+	u := testutil.Unstructured(t, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+  namespace: default
+  uid: 1e9f4b6c-5c0a-4b8e-9d3f-2a7c1b0e6d5f
+  resourceVersion: "42"
+  generation: 3
+  creationTimestamp: "2024-01-01T00:00:00Z"
+  managedFields:
+  - manager: kubectl
+  labels:
+    app: foo
+data:
+  key: value
+`)
+	object.StripServerManagedMetadata(u)
+	assert.Equal(t, map[string]interface{}{
+		"name":      "cm",
+		"namespace": "default",
+		"labels":    map[string]interface{}{"app": "foo"},
+	}, u.Object["metadata"])
+	assert.Equal(t, map[string]interface{}{"key": "value"}, u.Object["data"])
+}
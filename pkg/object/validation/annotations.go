@@ -0,0 +1,168 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package validation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/cli-utils/pkg/common"
+	"sigs.k8s.io/cli-utils/pkg/inventory"
+	"sigs.k8s.io/cli-utils/pkg/object/dependson"
+	"sigs.k8s.io/cli-utils/pkg/object/mutation"
+)
+
+// cliUtilsAnnotationPrefix is the prefix of annotations reserved for
+// cli-utils. Unknown annotations with this prefix are rejected in strict mode.
+const cliUtilsAnnotationPrefix = "cli-utils.sigs.k8s.io/"
+
+// knownAnnotations are the annotation keys that affect the behavior of
+// cli-utils.
+var knownAnnotations = map[string]struct{}{
+	common.InventoryLabel:                {},
+	common.InventoryHash:                 {},
+	common.OnRemoveAnnotation:            {},
+	common.LifecycleDeleteAnnotation:     {},
+	common.ApplyStrategyAnnotation:       {},
+	common.ApplyWaveAnnotation:           {},
+	common.MinServerVersionAnnotation:    {},
+	common.RequiredAPIVersionsAnnotation: {},
+	common.RequiredAPIGroupsAnnotation:   {},
+	common.RequiredKindsAnnotation:       {},
+	common.RecreateAnnotation:            {},
+	common.ApplyHookAnnotation:           {},
+	common.AppliedHashAnnotation:         {},
+	common.ReconcileTimeoutAnnotation:    {},
+	common.RunIDAnnotation:               {},
+	common.DeletionPropagationAnnotation: {},
+	common.DeletionGracePeriodAnnotation: {},
+	common.ProtectedKey:                  {},
+	common.PruneMarkedAtAnnotation:       {},
+	inventory.OwningInventoryKey:         {},
+	dependson.Annotation:                 {},
+	mutation.Annotation:                  {},
+}
+
+// ApplierValidatedAnnotations are the annotations whose values the applier
+// always validates, even without strict annotation validation. Objects with
+// invalid values of these annotations are not applied.
+var ApplierValidatedAnnotations = []string{
+	common.ApplyStrategyAnnotation,
+	common.ApplyWaveAnnotation,
+	common.ApplyHookAnnotation,
+	common.RecreateAnnotation,
+	common.ReconcileTimeoutAnnotation,
+}
+
+// IsApplierValidatedAnnotation returns true if the applier always validates
+// the value of the annotation. See ApplierValidatedAnnotations.
+func IsApplierValidatedAnnotation(key string) bool {
+	for _, validated := range ApplierValidatedAnnotations {
+		if key == validated {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateAnnotations validates the annotations of the resource that affect
+// the behavior of cli-utils. Unknown keys with the cli-utils prefix and keys
+// that only differ from a known key by case or punctuation (e.g.
+// "dependson" instead of "depends-on") are rejected, as well as unsupported
+// values of lifecycle annotations.
+func ValidateAnnotations(u *unstructured.Unstructured) []error {
+	annotations := u.GetAnnotations()
+	var errs []error
+	for _, key := range sortedKeys(annotations) {
+		if err := ValidateAnnotation(key, annotations[key]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// ValidateAnnotation validates an annotation like ValidateAnnotations.
+// Returns nil if the annotation is valid, or does not affect the behavior of
+// cli-utils.
+func ValidateAnnotation(key, value string) error {
+	path := field.NewPath("metadata", "annotations").Key(key)
+	if _, known := knownAnnotations[key]; known {
+		if err := validateAnnotationValue(key, value); err != nil {
+			return field.Invalid(path, value, err.Error())
+		}
+		return nil
+	}
+	if suggestion, found := similarAnnotation(key); found {
+		return field.Invalid(path, key, fmt.Sprintf("unknown annotation, did you mean %q?", suggestion))
+	}
+	if strings.HasPrefix(key, cliUtilsAnnotationPrefix) {
+		return field.Invalid(path, key, "unknown annotation")
+	}
+	return nil
+}
+
+// sortedKeys returns the keys of the annotations, sorted for deterministic
+// error order.
+func sortedKeys(annotations map[string]string) []string {
+	keys := make([]string, 0, len(annotations))
+	for key := range annotations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// validateAnnotationValue validates the value of a known annotation, if the
+// annotation has a fixed set of supported values.
+func validateAnnotationValue(key, value string) error {
+	if key == common.ApplyStrategyAnnotation {
+		_, err := common.ParseApplyStrategy(value)
+		return err
+	}
+	if key == common.ApplyWaveAnnotation {
+		_, err := common.ParseApplyWave(value)
+		return err
+	}
+	if key == common.ApplyHookAnnotation {
+		_, err := common.ParseApplyHook(value)
+		return err
+	}
+	if key == common.ReconcileTimeoutAnnotation {
+		_, err := common.ParseTimeout(value)
+		return err
+	}
+	if key == common.DeletionPropagationAnnotation {
+		_, err := common.ParseDeletionPropagation(value)
+		return err
+	}
+	if key == common.DeletionGracePeriodAnnotation {
+		_, err := common.ParseGracePeriodSeconds(value)
+		return err
+	}
+	if key == common.RecreateAnnotation {
+		_, err := common.ParseRecreate(value)
+		return err
+	}
+	_, err := common.ParseLifecycleDirective(key, value)
+	return err
+}
+
+// similarAnnotation returns the known annotation key that matches the
+// specified key when ignoring case and punctuation.
+func similarAnnotation(key string) (string, bool) {
+	normalized := normalizeAnnotationKey(key)
+	for known := range knownAnnotations {
+		if normalizeAnnotationKey(known) == normalized {
+			return known, true
+		}
+	}
+	return "", false
+}
+
+func normalizeAnnotationKey(key string) string {
+	return strings.NewReplacer("-", "", "_", "", ".", "").Replace(strings.ToLower(key))
+}
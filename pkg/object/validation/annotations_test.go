@@ -0,0 +1,129 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package validation_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
+	"sigs.k8s.io/cli-utils/pkg/multierror"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/cli-utils/pkg/object/validation"
+)
+
+func annotatedPod(annotations map[string]string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Pod",
+			"metadata": map[string]interface{}{
+				"name":      "foo",
+				"namespace": "default",
+			},
+		},
+	}
+	u.SetAnnotations(annotations)
+	return u
+}
+
+func TestValidateStrictAnnotations(t *testing.T) {
+	path := field.NewPath("metadata", "annotations")
+
+	testCases := map[string]struct {
+		annotations   map[string]string
+		strict        bool
+		expectedError error
+	}{
+		"no annotations": {
+			strict: true,
+		},
+		"known annotations": {
+			annotations: map[string]string{
+				"cli-utils.sigs.k8s.io/on-remove":         "keep",
+				"config.kubernetes.io/depends-on":         "/namespaces/default/Pod/bar",
+				"client.lifecycle.config.k8s.io/deletion": "detach",
+				"example.com/unrelated":                   "value",
+			},
+			strict: true,
+		},
+		"protected annotation": {
+			annotations: map[string]string{
+				"cli-utils.sigs.k8s.io/protected": "true",
+			},
+			strict: true,
+		},
+		"prune marked at annotation": {
+			annotations: map[string]string{
+				"cli-utils.sigs.k8s.io/prune-marked-at": "2024-01-01T00:00:00Z",
+			},
+			strict: true,
+		},
+		"misspelled depends-on": {
+			annotations: map[string]string{
+				"config.kubernetes.io/dependson": "/namespaces/default/Pod/bar",
+			},
+			strict: true,
+			expectedError: field.Invalid(path.Key("config.kubernetes.io/dependson"),
+				"config.kubernetes.io/dependson",
+				`unknown annotation, did you mean "config.kubernetes.io/depends-on"?`),
+		},
+		"unknown cli-utils annotation": {
+			annotations: map[string]string{
+				"cli-utils.sigs.k8s.io/on-delete": "keep",
+			},
+			strict: true,
+			expectedError: field.Invalid(path.Key("cli-utils.sigs.k8s.io/on-delete"),
+				"cli-utils.sigs.k8s.io/on-delete", "unknown annotation"),
+		},
+		"invalid values": {
+			annotations: map[string]string{
+				"cli-utils.sigs.k8s.io/on-remove":         "Keep",
+				"client.lifecycle.config.k8s.io/deletion": "orphan",
+			},
+			strict: true,
+			expectedError: multierror.New(
+				field.Invalid(path.Key("cli-utils.sigs.k8s.io/on-remove"), "Keep",
+					`unsupported value "Keep": supported values: "delete", "keep", "orphan"`),
+				field.Invalid(path.Key("client.lifecycle.config.k8s.io/deletion"), "orphan",
+					`unsupported value "orphan": supported values: "detach"`),
+			),
+		},
+		"not strict": {
+			annotations: map[string]string{
+				"config.kubernetes.io/dependson": "/namespaces/default/Pod/bar",
+			},
+			strict: false,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			tf := cmdtesting.NewTestFactory().WithNamespace("test-ns")
+			defer tf.Cleanup()
+
+			mapper, err := tf.ToRESTMapper()
+			require.NoError(t, err)
+
+			pod := annotatedPod(tc.annotations)
+			vCollector := &validation.Collector{}
+			validator := &validation.Validator{
+				Mapper:            mapper,
+				Collector:         vCollector,
+				StrictAnnotations: tc.strict,
+			}
+			validator.Validate([]*unstructured.Unstructured{pod})
+			err = vCollector.ToError()
+			if tc.expectedError == nil {
+				assert.NoError(t, err)
+				return
+			}
+			expected := validation.NewError(tc.expectedError, object.UnstructuredToObjMetadata(pod))
+			require.EqualError(t, err, expected.Error())
+		})
+	}
+}
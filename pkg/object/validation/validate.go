@@ -4,8 +4,13 @@
 package validation
 
 import (
+	"errors"
+	"fmt"
+
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"sigs.k8s.io/cli-utils/pkg/multierror"
 	"sigs.k8s.io/cli-utils/pkg/object"
@@ -17,6 +22,32 @@ import (
 type Validator struct {
 	Mapper    meta.RESTMapper
 	Collector *Collector
+
+	// StrictAnnotations enables validation of the annotations that affect the
+	// behavior of cli-utils, rejecting unknown keys and unsupported values.
+	StrictAnnotations bool
+
+	// AllowUnknownKinds disables the errors for objects whose kind is
+	// neither served by the cluster nor defined by a CRD in the set. The
+	// namespace of these objects is not validated.
+	AllowUnknownKinds bool
+
+	// NamespaceExists, if set, returns true if the namespace exists in the
+	// cluster. Namespaced objects whose namespace neither exists nor is in
+	// the set are invalid, with a NamespaceNotFoundError per namespace.
+	NamespaceExists func(namespace string) (bool, error)
+
+	// SchemaValidator, if set, validates the objects against the OpenAPI
+	// schema of their kinds before they are applied, like the client-side
+	// validation of kubectl, e.g. rejecting unknown fields and values of the
+	// wrong type. All the violations of an object are reported together.
+	SchemaValidator SchemaValidator
+}
+
+// SchemaValidator validates an object, encoded as JSON, against its schema.
+// Implemented by the schemas of k8s.io/kubectl/pkg/validation.
+type SchemaValidator interface {
+	ValidateBytes(data []byte) error
 }
 
 // Validate validates the provided resources. A RESTMapper will be used
@@ -34,6 +65,12 @@ func (v *Validator) Validate(objs []*unstructured.Unstructured) {
 		if err := v.validateNamespace(obj, crds); err != nil {
 			objErrors = append(objErrors, err)
 		}
+		if v.StrictAnnotations {
+			objErrors = append(objErrors, ValidateAnnotations(obj)...)
+		}
+		if v.SchemaValidator != nil {
+			objErrors = append(objErrors, v.validateSchema(obj)...)
+		}
 		if len(objErrors) > 0 {
 			// one error per object
 			v.Collector.Collect(NewError(
@@ -42,6 +79,9 @@ func (v *Validator) Validate(objs []*unstructured.Unstructured) {
 			))
 		}
 	}
+	if v.NamespaceExists != nil {
+		v.validateNamespacesExist(objs, crds)
+	}
 }
 
 // findCRDs looks through the provided resources and returns a slice with
@@ -80,6 +120,10 @@ func (v *Validator) validateNamespace(u *unstructured.Unstructured, crds []*unst
 	}
 	scope, err := object.LookupResourceScope(u, crds, v.Mapper)
 	if err != nil {
+		var unknownTypeErr *object.UnknownTypeError
+		if v.AllowUnknownKinds && errors.As(err, &unknownTypeErr) {
+			return nil
+		}
 		return err
 	}
 
@@ -92,3 +136,76 @@ func (v *Validator) validateNamespace(u *unstructured.Unstructured, crds []*unst
 	}
 	return nil
 }
+
+// validateSchema validates the object against the schema of its kind, and
+// returns all the violations. Objects of kinds without schema, like the
+// custom resources of CRDs in the set, are not validated.
+func (v *Validator) validateSchema(u *unstructured.Unstructured) []error {
+	// skip schema validation if kind is missing (avoid redundant error)
+	if u.GetKind() == "" {
+		return nil
+	}
+	data, err := u.MarshalJSON()
+	if err != nil {
+		return []error{err}
+	}
+	err = v.SchemaValidator.ValidateBytes(data)
+	if err == nil {
+		return nil
+	}
+	var aggregate utilerrors.Aggregate
+	if errors.As(err, &aggregate) {
+		return aggregate.Errors()
+	}
+	return []error{err}
+}
+
+// validateNamespacesExist validates that the namespaces of the namespaced
+// objects exist, or are in the set.
+func (v *Validator) validateNamespacesExist(objs []*unstructured.Unstructured, crds []*unstructured.Unstructured) {
+	localNamespaces := sets.New[string]()
+	for _, obj := range objs {
+		if object.IsKindNamespace(obj) {
+			localNamespaces.Insert(obj.GetName())
+		}
+	}
+	namespaceIDs := make(map[string]object.ObjMetadataSet)
+	for _, obj := range objs {
+		ns := obj.GetNamespace()
+		if ns == "" || localNamespaces.Has(ns) {
+			continue
+		}
+		// Objects of unknown scope are reported by validateNamespace.
+		scope, err := object.LookupResourceScope(obj, crds, v.Mapper)
+		if err != nil || scope != meta.RESTScopeNamespace {
+			continue
+		}
+		namespaceIDs[ns] = append(namespaceIDs[ns], object.UnstructuredToObjMetadata(obj))
+	}
+	for _, ns := range sets.List(sets.KeySet(namespaceIDs)) {
+		exists, err := v.NamespaceExists(ns)
+		if err != nil {
+			v.Collector.Collect(NewError(
+				fmt.Errorf("failed to check namespace %q: %w", ns, err),
+				namespaceIDs[ns]...,
+			))
+			continue
+		}
+		if !exists {
+			v.Collector.Collect(NewError(
+				&NamespaceNotFoundError{Namespace: ns},
+				namespaceIDs[ns]...,
+			))
+		}
+	}
+}
+
+// NamespaceNotFoundError is the cause of the validation error of namespaced
+// objects whose namespace does not exist, and is not in the set.
+type NamespaceNotFoundError struct {
+	Namespace string
+}
+
+func (e *NamespaceNotFoundError) Error() string {
+	return fmt.Sprintf("namespace not found: %s", e.Namespace)
+}
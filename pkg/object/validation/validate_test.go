@@ -4,6 +4,8 @@
 package validation_test
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -11,6 +13,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
 	"sigs.k8s.io/cli-utils/pkg/multierror"
@@ -21,8 +24,9 @@ import (
 
 func TestValidate(t *testing.T) {
 	testCases := map[string]struct {
-		resources     []*unstructured.Unstructured
-		expectedError error
+		resources         []*unstructured.Unstructured
+		allowUnknownKinds bool
+		expectedError     error
 	}{
 		"missing kind": {
 			resources: []*unstructured.Unstructured{
@@ -244,6 +248,45 @@ metadata:
 				},
 			),
 		},
+		"unknown kind": {
+			resources: []*unstructured.Unstructured{
+				testutil.Unstructured(t, `
+apiVersion: custom.io/v1
+kind: Custom
+metadata:
+  name: foo
+`,
+				),
+			},
+			expectedError: validation.NewError(
+				&object.UnknownTypeError{
+					GroupVersionKind: schema.GroupVersionKind{
+						Group:   "custom.io",
+						Version: "v1",
+						Kind:    "Custom",
+					},
+				},
+				object.ObjMetadata{
+					GroupKind: schema.GroupKind{
+						Group: "custom.io",
+						Kind:  "Custom",
+					},
+					Name: "foo",
+				},
+			),
+		},
+		"unknown kind allowed": {
+			resources: []*unstructured.Unstructured{
+				testutil.Unstructured(t, `
+apiVersion: custom.io/v1
+kind: Custom
+metadata:
+  name: foo
+`,
+				),
+			},
+			allowUnknownKinds: true,
+		},
 	}
 
 	for tn, tc := range testCases {
@@ -260,10 +303,176 @@ metadata:
 				crdGV.WithResource("customresourcedefinition"), meta.RESTScopeRoot)
 			mapper = meta.MultiRESTMapper([]meta.RESTMapper{mapper, crdMapper})
 
+			vCollector := &validation.Collector{}
+			validator := &validation.Validator{
+				Mapper:            mapper,
+				Collector:         vCollector,
+				AllowUnknownKinds: tc.allowUnknownKinds,
+			}
+			validator.Validate(tc.resources)
+			err = vCollector.ToError()
+			if tc.expectedError == nil {
+				assert.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tc.expectedError.Error())
+		})
+	}
+}
+
+func TestValidateNamespacesExist(t *testing.T) {
+	deployment := testutil.Unstructured(t, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: foo
+  namespace: missing
+`)
+	otherDeployment := testutil.Unstructured(t, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: bar
+  namespace: missing
+`)
+	existingDeployment := testutil.Unstructured(t, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: foo
+  namespace: existing
+`)
+	localNamespace := testutil.Unstructured(t, `
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: missing
+`)
+	errLookup := errors.New("lookup failed")
+
+	testCases := map[string]struct {
+		resources     []*unstructured.Unstructured
+		lookupErr     error
+		expectedError error
+	}{
+		"existing namespace": {
+			resources: []*unstructured.Unstructured{existingDeployment},
+		},
+		"missing namespace": {
+			resources: []*unstructured.Unstructured{deployment, otherDeployment, existingDeployment},
+			expectedError: validation.NewError(
+				&validation.NamespaceNotFoundError{Namespace: "missing"},
+				object.UnstructuredToObjMetadata(deployment),
+				object.UnstructuredToObjMetadata(otherDeployment),
+			),
+		},
+		"missing namespace in the set": {
+			resources: []*unstructured.Unstructured{localNamespace, deployment},
+		},
+		"lookup error": {
+			resources: []*unstructured.Unstructured{existingDeployment},
+			lookupErr: errLookup,
+			expectedError: validation.NewError(
+				fmt.Errorf("failed to check namespace %q: %w", "existing", errLookup),
+				object.UnstructuredToObjMetadata(existingDeployment),
+			),
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			tf := cmdtesting.NewTestFactory().WithNamespace("test-ns")
+			defer tf.Cleanup()
+
+			mapper, err := tf.ToRESTMapper()
+			require.NoError(t, err)
+
 			vCollector := &validation.Collector{}
 			validator := &validation.Validator{
 				Mapper:    mapper,
 				Collector: vCollector,
+				NamespaceExists: func(namespace string) (bool, error) {
+					return namespace == "existing", tc.lookupErr
+				},
+			}
+			validator.Validate(tc.resources)
+			err = vCollector.ToError()
+			if tc.expectedError == nil {
+				assert.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tc.expectedError.Error())
+		})
+	}
+}
+
+type fakeSchemaValidator struct {
+	errs map[string][]error
+}
+
+func (f fakeSchemaValidator) ValidateBytes(data []byte) error {
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	return utilerrors.NewAggregate(f.errs[obj.GetName()])
+}
+
+func TestValidateSchema(t *testing.T) {
+	valid := testutil.Unstructured(t, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: valid
+  namespace: default
+`)
+	invalid := testutil.Unstructured(t, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: invalid
+  namespace: default
+spec:
+  replica: 1
+  paused: "yes"
+`)
+	errUnknownField := errors.New(`unknown field "replica" in io.k8s.api.apps.v1.DeploymentSpec`)
+	errInvalidType := errors.New(`invalid type for io.k8s.api.apps.v1.DeploymentSpec.paused: got "string", expected "boolean"`)
+	schemaValidator := fakeSchemaValidator{
+		errs: map[string][]error{
+			"invalid": {errUnknownField, errInvalidType},
+		},
+	}
+
+	testCases := map[string]struct {
+		resources     []*unstructured.Unstructured
+		expectedError error
+	}{
+		"valid object": {
+			resources: []*unstructured.Unstructured{valid},
+		},
+		"all violations of the object": {
+			resources: []*unstructured.Unstructured{valid, invalid},
+			expectedError: validation.NewError(
+				multierror.New(errUnknownField, errInvalidType),
+				object.UnstructuredToObjMetadata(invalid),
+			),
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			tf := cmdtesting.NewTestFactory().WithNamespace("test-ns")
+			defer tf.Cleanup()
+
+			mapper, err := tf.ToRESTMapper()
+			require.NoError(t, err)
+
+			vCollector := &validation.Collector{}
+			validator := &validation.Validator{
+				Mapper:          mapper,
+				Collector:       vCollector,
+				SchemaValidator: schemaValidator,
 			}
 			validator.Validate(tc.resources)
 			err = vCollector.ToError()
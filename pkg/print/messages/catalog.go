@@ -0,0 +1,200 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package messages contains the catalog of the user-facing messages of the
+// printers. Each message is a Go template, executed with the parameters of
+// the message, so that embedding products can re-word or localize the output
+// by overriding the templates, instead of rewriting the printers.
+package messages
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// ID identifies a message of the catalog.
+type ID string
+
+// The IDs of the messages, with the type of their parameters.
+const (
+	// InvalidObject is printed for a validation error of one object.
+	// Parameters: ValidationParams.
+	InvalidObject ID = "InvalidObject"
+	// InvalidObjects is printed for a validation error of multiple objects.
+	// Parameters: ValidationParams.
+	InvalidObjects ID = "InvalidObjects"
+	// ApplyEvent is printed for the apply of an object.
+	// Parameters: ResourceParams.
+	ApplyEvent ID = "ApplyEvent"
+	// PruneEvent is printed for the prune of an object.
+	// Parameters: ResourceParams.
+	PruneEvent ID = "PruneEvent"
+	// DeleteEvent is printed for the deletion of an object.
+	// Parameters: ResourceParams.
+	DeleteEvent ID = "DeleteEvent"
+	// WaitEvent is printed for the reconciliation of an object.
+	// Parameters: ResourceParams.
+	WaitEvent ID = "WaitEvent"
+	// StatusEvent is printed for a status update of an object.
+	// Parameters: ResourceParams.
+	StatusEvent ID = "StatusEvent"
+	// ApplyPhase is printed when the apply phase starts or finishes.
+	// Parameters: PhaseParams.
+	ApplyPhase ID = "ApplyPhase"
+	// PrunePhase is printed when the prune phase starts or finishes.
+	// Parameters: PhaseParams.
+	PrunePhase ID = "PrunePhase"
+	// DeletePhase is printed when the delete phase starts or finishes.
+	// Parameters: PhaseParams.
+	DeletePhase ID = "DeletePhase"
+	// ReconcilePhase is printed when the reconcile phase starts or finishes.
+	// Parameters: PhaseParams.
+	ReconcilePhase ID = "ReconcilePhase"
+	// InventoryUpdate is printed when the inventory update starts or
+	// finishes. Parameters: PhaseParams.
+	InventoryUpdate ID = "InventoryUpdate"
+	// ApplyResult is the summary of the apply phases.
+	// Parameters: ResultParams.
+	ApplyResult ID = "ApplyResult"
+	// PruneResult is the summary of the prune phases.
+	// Parameters: ResultParams.
+	PruneResult ID = "PruneResult"
+	// DeleteResult is the summary of the delete phases.
+	// Parameters: ResultParams.
+	DeleteResult ID = "DeleteResult"
+	// ReconcileResult is the summary of the reconcile phases.
+	// Parameters: ResultParams.
+	ReconcileResult ID = "ReconcileResult"
+	// ResourceRequests is the summary of the resource requests of the
+	// applied objects. Parameters: ResourceRequestsParams.
+	ResourceRequests ID = "ResourceRequests"
+)
+
+// DefaultTemplates are the English templates of the messages.
+var DefaultTemplates = map[ID]string{
+	InvalidObject:    `Invalid object ({{index .Resources 0}}): {{.Error}}`,
+	InvalidObjects:   `Invalid objects ({{join .Resources ", "}}): {{.Error}}`,
+	ApplyEvent:       `{{.Resource}} apply {{.Status}}{{if .Error}}: {{.Error}}{{end}}`,
+	PruneEvent:       `{{.Resource}} prune {{.Status}}{{if .Error}}: {{.Error}}{{end}}`,
+	DeleteEvent:      `{{.Resource}} delete {{.Status}}{{if .Error}}: {{.Error}}{{end}}`,
+	WaitEvent:        `{{.Resource}} reconcile {{.Status}}`,
+	StatusEvent:      `{{.Resource}} is {{.Status}}: {{.Message}}`,
+	ApplyPhase:       `apply phase {{.Status}}`,
+	PrunePhase:       `prune phase {{.Status}}`,
+	DeletePhase:      `delete phase {{.Status}}`,
+	ReconcilePhase:   `reconcile phase {{.Status}}`,
+	InventoryUpdate:  `inventory update {{.Status}}`,
+	ApplyResult:      `apply result: {{.Attempted}} attempted, {{.Successful}} successful, {{.Skipped}} skipped, {{.Failed}} failed`,
+	PruneResult:      `prune result: {{.Attempted}} attempted, {{.Successful}} successful, {{.Skipped}} skipped, {{.Failed}} failed`,
+	DeleteResult:     `delete result: {{.Attempted}} attempted, {{.Successful}} successful, {{.Skipped}} skipped, {{.Failed}} failed`,
+	ReconcileResult:  `reconcile result: {{.Attempted}} attempted, {{.Successful}} successful, {{.Skipped}} skipped, {{.Failed}} failed, {{.TimedOut}} timed out`,
+	ResourceRequests: `resource requests: cpu {{.CPU}}, memory {{.Memory}}, storage {{.Storage}}`,
+}
+
+// ValidationParams are the parameters of the validation messages.
+type ValidationParams struct {
+	// Resources are the invalid objects, as "<kind>.<group>/<name>".
+	Resources []string
+	Error     string
+}
+
+// ResourceParams are the parameters of the messages about one object.
+type ResourceParams struct {
+	// Resource is the object, as "<kind>.<group>/<name>".
+	Resource string
+	// Status is the status of the operation, or the status of the object
+	// for status events.
+	Status string
+	// Error is the error of the operation, if any.
+	Error string
+	// Message is the status message of the object, for status events.
+	Message string
+}
+
+// PhaseParams are the parameters of the phase messages.
+type PhaseParams struct {
+	// Status is "started", "finished" or "skipped".
+	Status string
+}
+
+// ResultParams are the parameters of the summary messages.
+type ResultParams struct {
+	Attempted  int
+	Successful int
+	Skipped    int
+	Failed     int
+	// TimedOut is only set for the reconcile result.
+	TimedOut int
+}
+
+// ResourceRequestsParams are the parameters of the ResourceRequests message.
+type ResourceRequestsParams struct {
+	CPU     string
+	Memory  string
+	Storage string
+}
+
+var funcs = template.FuncMap{
+	"join":  strings.Join,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+}
+
+// Catalog renders the messages from their templates.
+type Catalog struct {
+	templates map[ID]*template.Template
+}
+
+// NewCatalog returns a catalog of the default templates, with the templates
+// of the overrides instead, if any. The templates can use the join, lower
+// and upper functions, in addition to the builtin functions of text/template.
+// Returns an error if a template is invalid or if its ID is unknown.
+func NewCatalog(overrides map[ID]string) (*Catalog, error) {
+	var unknown []string
+	for id := range overrides {
+		if _, found := DefaultTemplates[id]; !found {
+			unknown = append(unknown, string(id))
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("unknown message IDs: %s", strings.Join(unknown, ", "))
+	}
+	c := &Catalog{templates: make(map[ID]*template.Template, len(DefaultTemplates))}
+	for id, text := range DefaultTemplates {
+		if override, found := overrides[id]; found {
+			text = override
+		}
+		tmpl, err := template.New(string(id)).Funcs(funcs).Option("missingkey=error").Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template of message %s: %w", id, err)
+		}
+		c.templates[id] = tmpl
+	}
+	return c, nil
+}
+
+// DefaultCatalog returns the catalog of the default templates.
+func DefaultCatalog() *Catalog {
+	c, err := NewCatalog(nil)
+	if err != nil {
+		// The default templates are tested.
+		panic(err)
+	}
+	return c
+}
+
+// Render executes the template of the message with the parameters.
+func (c *Catalog) Render(id ID, params interface{}) (string, error) {
+	tmpl, found := c.templates[id]
+	if !found {
+		return "", fmt.Errorf("unknown message ID: %s", id)
+	}
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, params); err != nil {
+		return "", fmt.Errorf("failed to render message %s: %w", id, err)
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,81 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package messages
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultCatalog(t *testing.T) {
+	catalog := DefaultCatalog()
+	assert.Len(t, catalog.templates, len(DefaultTemplates))
+
+	msg, err := catalog.Render(InvalidObjects, ValidationParams{
+		Resources: []string{"deployment.apps/a", "deployment.apps/b"},
+		Error:     "invalid",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Invalid objects (deployment.apps/a, deployment.apps/b): invalid", msg)
+
+	msg, err = catalog.Render(ApplyEvent, ResourceParams{Resource: "deployment.apps/a", Status: "successful"})
+	require.NoError(t, err)
+	assert.Equal(t, "deployment.apps/a apply successful", msg)
+}
+
+func TestNewCatalog(t *testing.T) {
+	testCases := map[string]struct {
+		overrides     map[ID]string
+		id            ID
+		params        interface{}
+		expected      string
+		expectedError string
+	}{
+		"override": {
+			overrides: map[ID]string{ApplyPhase: "Phase d'application : {{.Status}}"},
+			id:        ApplyPhase,
+			params:    PhaseParams{Status: "started"},
+			expected:  "Phase d'application : started",
+		},
+		"default is kept without override": {
+			overrides: map[ID]string{ApplyPhase: "Phase d'application : {{.Status}}"},
+			id:        PrunePhase,
+			params:    PhaseParams{Status: "started"},
+			expected:  "prune phase started",
+		},
+		"unknown ID": {
+			overrides:     map[ID]string{"Foo": "foo", "Bar": "bar"},
+			expectedError: "unknown message IDs: Bar, Foo",
+		},
+		"invalid template": {
+			overrides:     map[ID]string{WaitEvent: "{{.Resource"},
+			expectedError: "invalid template of message WaitEvent: template: WaitEvent:1: unclosed action",
+		},
+		"unknown parameter": {
+			overrides:     map[ID]string{WaitEvent: "{{.Foo}}"},
+			id:            WaitEvent,
+			params:        ResourceParams{},
+			expectedError: "failed to render message WaitEvent",
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			catalog, err := NewCatalog(tc.overrides)
+			if err == nil {
+				var msg string
+				msg, err = catalog.Render(tc.id, tc.params)
+				if tc.expectedError == "" {
+					require.NoError(t, err)
+					assert.Equal(t, tc.expected, msg)
+					return
+				}
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedError)
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	"sigs.k8s.io/cli-utils/pkg/apply/event"
+	"sigs.k8s.io/cli-utils/pkg/object"
 )
 
 // Stats captures the summarized numbers from apply/prune/delete and
@@ -17,6 +18,9 @@ type Stats struct {
 	PruneStats  PruneStats
 	DeleteStats DeleteStats
 	WaitStats   WaitStats
+	// ResourceRequests are the resources requested by the objects that were
+	// applied or skipped. See object.ObjectResourceRequests.
+	ResourceRequests object.ResourceRequests
 }
 
 // FailedActuationSum returns the number of resources that failed actuation.
@@ -34,6 +38,7 @@ func (s *Stats) Handle(e event.Event) {
 	switch e.Type {
 	case event.ApplyType:
 		s.ApplyStats.Inc(e.ApplyEvent.Status)
+		s.addResourceRequests(e.ApplyEvent)
 	case event.PruneType:
 		s.PruneStats.Inc(e.PruneEvent.Status)
 	case event.DeleteType:
@@ -43,6 +48,19 @@ func (s *Stats) Handle(e event.Event) {
 	}
 }
 
+// addResourceRequests adds the resources requested by the object of the apply
+// event, unless the apply failed. Objects with invalid requests are ignored.
+func (s *Stats) addResourceRequests(e event.ApplyEvent) {
+	if e.Status == event.ApplyFailed || e.Resource == nil {
+		return
+	}
+	requests, err := object.ObjectResourceRequests(e.Resource)
+	if err != nil {
+		return
+	}
+	s.ResourceRequests.Add(requests)
+}
+
 type ApplyStats struct {
 	Successful int
 	Skipped    int
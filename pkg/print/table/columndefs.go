@@ -10,6 +10,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/utils/integer"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/status"
 	"sigs.k8s.io/cli-utils/pkg/print/common"
 )
 
@@ -231,7 +232,7 @@ var (
 				if rs.Error != nil {
 					message = rs.Error.Error()
 				} else {
-					message = rs.Message
+					message = status.MessageWithDeletionAge(rs.Status, rs.Message, rs.Resource)
 				}
 				if len(message) > width {
 					message = message[:width]
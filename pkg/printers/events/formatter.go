@@ -11,21 +11,32 @@ import (
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 	"sigs.k8s.io/cli-utils/pkg/apply/event"
 	"sigs.k8s.io/cli-utils/pkg/common"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/status"
 	"sigs.k8s.io/cli-utils/pkg/object"
 	"sigs.k8s.io/cli-utils/pkg/object/validation"
 	"sigs.k8s.io/cli-utils/pkg/print/list"
+	"sigs.k8s.io/cli-utils/pkg/print/messages"
 	"sigs.k8s.io/cli-utils/pkg/print/stats"
 )
 
 func NewFormatter(ioStreams genericiooptions.IOStreams,
-	_ common.DryRunStrategy) list.Formatter {
+	previewStrategy common.DryRunStrategy) list.Formatter {
+	return NewFormatterWithCatalog(ioStreams, previewStrategy, messages.DefaultCatalog())
+}
+
+// NewFormatterWithCatalog returns a formatter that renders the messages
+// from the catalog, to re-word or localize the output.
+func NewFormatterWithCatalog(ioStreams genericiooptions.IOStreams,
+	_ common.DryRunStrategy, catalog *messages.Catalog) list.Formatter {
 	return &formatter{
 		ioStreams: ioStreams,
+		catalog:   catalog,
 	}
 }
 
 type formatter struct {
 	ioStreams genericiooptions.IOStreams
+	catalog   *messages.Catalog
 }
 
 func (ef *formatter) FormatValidationEvent(ve event.ValidationEvent) error {
@@ -35,80 +46,47 @@ func (ef *formatter) FormatValidationEvent(ve event.ValidationEvent) error {
 		err = vErr.Unwrap()
 	}
 
-	switch {
-	case len(ve.Identifiers) == 0:
+	if len(ve.Identifiers) == 0 {
 		// no objects, invalid event
 		return fmt.Errorf("invalid validation event: no identifiers: %w", err)
-	case len(ve.Identifiers) == 1:
+	}
+	params := messages.ValidationParams{Error: err.Error()}
+	for _, id := range ve.Identifiers {
+		params.Resources = append(params.Resources, resourceIDToString(id.GroupKind, id.Name))
+	}
+	if len(ve.Identifiers) == 1 {
 		// only 1 object, unwrap for similarity with status event
-		id := ve.Identifiers[0]
-		ef.print("Invalid object (%s): %v",
-			resourceIDToString(id.GroupKind, id.Name), err.Error())
-	default:
-		// more than 1 object, wrap list in brackets
-		var sb strings.Builder
-		id := ve.Identifiers[0]
-		_, _ = fmt.Fprintf(&sb, "Invalid objects (%s", resourceIDToString(id.GroupKind, id.Name))
-		for _, id := range ve.Identifiers[1:] {
-			_, _ = fmt.Fprintf(&sb, ", %s", resourceIDToString(id.GroupKind, id.Name))
-		}
-		_, _ = fmt.Fprintf(&sb, "): %v", err)
-		ef.print(sb.String())
+		return ef.print(messages.InvalidObject, params)
 	}
-	return nil
+	// more than 1 object, wrap list in brackets
+	return ef.print(messages.InvalidObjects, params)
 }
 
 func (ef *formatter) FormatApplyEvent(e event.ApplyEvent) error {
-	gk := e.Identifier.GroupKind
-	name := e.Identifier.Name
-	if e.Error != nil {
-		ef.print("%s apply %s: %s", resourceIDToString(gk, name),
-			strings.ToLower(e.Status.String()), e.Error.Error())
-	} else {
-		ef.print("%s apply %s", resourceIDToString(gk, name),
-			strings.ToLower(e.Status.String()))
-	}
-	return nil
+	return ef.print(messages.ApplyEvent,
+		resourceParams(e.Identifier, strings.ToLower(e.Status.String()), e.Error))
 }
 
 func (ef *formatter) FormatStatusEvent(se event.StatusEvent) error {
-	id := se.Identifier
-	ef.printResourceStatus(id, se)
-	return nil
+	params := resourceParams(se.Identifier, se.PollResourceInfo.Status.String(), nil)
+	params.Message = status.MessageWithDeletionAge(se.PollResourceInfo.Status,
+		se.PollResourceInfo.Message, se.PollResourceInfo.Resource)
+	return ef.print(messages.StatusEvent, params)
 }
 
 func (ef *formatter) FormatPruneEvent(e event.PruneEvent) error {
-	gk := e.Identifier.GroupKind
-	name := e.Identifier.Name
-	if e.Error != nil {
-		ef.print("%s prune %s: %s", resourceIDToString(gk, name),
-			strings.ToLower(e.Status.String()), e.Error.Error())
-	} else {
-		ef.print("%s prune %s", resourceIDToString(gk, name),
-			strings.ToLower(e.Status.String()))
-	}
-	return nil
+	return ef.print(messages.PruneEvent,
+		resourceParams(e.Identifier, strings.ToLower(e.Status.String()), e.Error))
 }
 
 func (ef *formatter) FormatDeleteEvent(e event.DeleteEvent) error {
-	gk := e.Identifier.GroupKind
-	name := e.Identifier.Name
-	if e.Error != nil {
-		ef.print("%s delete %s: %s", resourceIDToString(gk, name),
-			strings.ToLower(e.Status.String()), e.Error.Error())
-	} else {
-		ef.print("%s delete %s", resourceIDToString(gk, name),
-			strings.ToLower(e.Status.String()))
-	}
-	return nil
+	return ef.print(messages.DeleteEvent,
+		resourceParams(e.Identifier, strings.ToLower(e.Status.String()), e.Error))
 }
 
 func (ef *formatter) FormatWaitEvent(e event.WaitEvent) error {
-	gk := e.Identifier.GroupKind
-	name := e.Identifier.Name
-	ef.print("%s reconcile %s", resourceIDToString(gk, name),
-		strings.ToLower(e.Status.String()))
-	return nil
+	return ef.print(messages.WaitEvent,
+		resourceParams(e.Identifier, strings.ToLower(e.Status.String()), nil))
 }
 
 func (ef *formatter) FormatErrorEvent(_ event.ErrorEvent) error {
@@ -121,54 +99,101 @@ func (ef *formatter) FormatActionGroupEvent(
 	s stats.Stats,
 	_ list.Collector,
 ) error {
+	var id messages.ID
 	switch age.Action {
 	case event.ApplyAction:
-		ef.print("apply phase %s", strings.ToLower(age.Status.String()))
+		id = messages.ApplyPhase
 	case event.PruneAction:
-		ef.print("prune phase %s", strings.ToLower(age.Status.String()))
+		id = messages.PrunePhase
 	case event.DeleteAction:
-		ef.print("delete phase %s", strings.ToLower(age.Status.String()))
+		id = messages.DeletePhase
 	case event.WaitAction:
-		ef.print("reconcile phase %s", strings.ToLower(age.Status.String()))
+		id = messages.ReconcilePhase
 	case event.InventoryAction:
-		ef.print("inventory update %s", strings.ToLower(age.Status.String()))
+		id = messages.InventoryUpdate
 	default:
 		return fmt.Errorf("invalid action group action: %+v", age)
 	}
-	return nil
+	return ef.print(id, messages.PhaseParams{Status: strings.ToLower(age.Status.String())})
 }
 
 func (ef *formatter) FormatSummary(s stats.Stats) error {
 	if s.ApplyStats != (stats.ApplyStats{}) {
 		as := s.ApplyStats
-		ef.print("apply result: %d attempted, %d successful, %d skipped, %d failed",
-			as.Sum(), as.Successful, as.Skipped, as.Failed)
+		if err := ef.print(messages.ApplyResult, messages.ResultParams{
+			Attempted:  as.Sum(),
+			Successful: as.Successful,
+			Skipped:    as.Skipped,
+			Failed:     as.Failed,
+		}); err != nil {
+			return err
+		}
 	}
 	if s.PruneStats != (stats.PruneStats{}) {
 		ps := s.PruneStats
-		ef.print("prune result: %d attempted, %d successful, %d skipped, %d failed",
-			ps.Sum(), ps.Successful, ps.Skipped, ps.Failed)
+		if err := ef.print(messages.PruneResult, messages.ResultParams{
+			Attempted:  ps.Sum(),
+			Successful: ps.Successful,
+			Skipped:    ps.Skipped,
+			Failed:     ps.Failed,
+		}); err != nil {
+			return err
+		}
 	}
 	if s.DeleteStats != (stats.DeleteStats{}) {
 		ds := s.DeleteStats
-		ef.print("delete result: %d attempted, %d successful, %d skipped, %d failed",
-			ds.Sum(), ds.Successful, ds.Skipped, ds.Failed)
+		if err := ef.print(messages.DeleteResult, messages.ResultParams{
+			Attempted:  ds.Sum(),
+			Successful: ds.Successful,
+			Skipped:    ds.Skipped,
+			Failed:     ds.Failed,
+		}); err != nil {
+			return err
+		}
 	}
 	if s.WaitStats != (stats.WaitStats{}) {
 		ws := s.WaitStats
-		ef.print("reconcile result: %d attempted, %d successful, %d skipped, %d failed, %d timed out",
-			ws.Sum(), ws.Successful, ws.Skipped, ws.Failed, ws.Timeout)
+		if err := ef.print(messages.ReconcileResult, messages.ResultParams{
+			Attempted:  ws.Sum(),
+			Successful: ws.Successful,
+			Skipped:    ws.Skipped,
+			Failed:     ws.Failed,
+			TimedOut:   ws.Timeout,
+		}); err != nil {
+			return err
+		}
+	}
+	if !s.ResourceRequests.IsZero() {
+		rr := s.ResourceRequests
+		return ef.print(messages.ResourceRequests, messages.ResourceRequestsParams{
+			CPU:     rr.CPU.String(),
+			Memory:  rr.Memory.String(),
+			Storage: rr.Storage.String(),
+		})
 	}
 	return nil
 }
 
-func (ef *formatter) printResourceStatus(id object.ObjMetadata, se event.StatusEvent) {
-	ef.print("%s is %s: %s", resourceIDToString(id.GroupKind, id.Name),
-		se.PollResourceInfo.Status.String(), se.PollResourceInfo.Message)
+// print renders the message from the catalog and prints it on its own line.
+func (ef *formatter) print(id messages.ID, params interface{}) error {
+	msg, err := ef.catalog.Render(id, params)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintln(ef.ioStreams.Out, msg)
+	return nil
 }
 
-func (ef *formatter) print(format string, a ...interface{}) {
-	_, _ = fmt.Fprintf(ef.ioStreams.Out, format+"\n", a...)
+// resourceParams returns the parameters of a message about an object.
+func resourceParams(id object.ObjMetadata, status string, err error) messages.ResourceParams {
+	params := messages.ResourceParams{
+		Resource: resourceIDToString(id.GroupKind, id.Name),
+		Status:   status,
+	}
+	if err != nil {
+		params.Error = err.Error()
+	}
+	return params
 }
 
 // resourceIDToString returns the string representation of a GroupKind and a resource name.
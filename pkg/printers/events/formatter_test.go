@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -22,6 +23,8 @@ import (
 	"sigs.k8s.io/cli-utils/pkg/object/graph"
 	"sigs.k8s.io/cli-utils/pkg/object/validation"
 	"sigs.k8s.io/cli-utils/pkg/print/list"
+	"sigs.k8s.io/cli-utils/pkg/print/messages"
+	"sigs.k8s.io/cli-utils/pkg/print/stats"
 )
 
 func TestFormatter_FormatApplyEvent(t *testing.T) {
@@ -507,6 +510,35 @@ func TestFormatter_FormatValidationEvent(t *testing.T) {
 	}
 }
 
+func TestFormatter_Catalog(t *testing.T) {
+	catalog, err := messages.NewCatalog(map[messages.ID]string{
+		messages.ApplyEvent:  `{{upper .Status}}: {{.Resource}}{{if .Error}} ({{.Error}}){{end}}`,
+		messages.ApplyResult: `{{.Successful}}/{{.Attempted}} objects applied`,
+	})
+	require.NoError(t, err)
+
+	ioStreams, _, out, _ := genericclioptions.NewTestIOStreams() //nolint:dogsled
+	formatter := NewFormatterWithCatalog(ioStreams, common.DryRunNone, catalog)
+	require.NoError(t, formatter.FormatApplyEvent(event.ApplyEvent{
+		Status:     event.ApplyFailed,
+		Identifier: createIdentifier("apps", "Deployment", "default", "my-dep"),
+		Error:      fmt.Errorf("this is a test error"),
+	}))
+	require.NoError(t, formatter.FormatPruneEvent(event.PruneEvent{
+		Status:     event.PruneSuccessful,
+		Identifier: createIdentifier("apps", "Deployment", "default", "my-dep"),
+	}))
+	require.NoError(t, formatter.FormatSummary(stats.Stats{
+		ApplyStats: stats.ApplyStats{Successful: 2, Failed: 1},
+	}))
+
+	expected := `FAILED: deployment.apps/my-dep (this is a test error)
+deployment.apps/my-dep prune successful
+2/3 objects applied
+`
+	assert.Equal(t, expected, out.String())
+}
+
 func createObject(group, kind, namespace, name string) *unstructured.Unstructured {
 	return &unstructured.Unstructured{
 		Object: map[string]interface{}{
@@ -7,13 +7,20 @@ import (
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 	"sigs.k8s.io/cli-utils/pkg/common"
 	"sigs.k8s.io/cli-utils/pkg/print/list"
+	"sigs.k8s.io/cli-utils/pkg/print/messages"
 	"sigs.k8s.io/cli-utils/pkg/printers/printer"
 )
 
 func NewPrinter(ioStreams genericiooptions.IOStreams) printer.Printer {
+	return NewPrinterWithCatalog(ioStreams, messages.DefaultCatalog())
+}
+
+// NewPrinterWithCatalog returns a printer that renders the messages from the
+// catalog, to re-word or localize the output.
+func NewPrinterWithCatalog(ioStreams genericiooptions.IOStreams, catalog *messages.Catalog) printer.Printer {
 	return &list.BaseListPrinter{
 		FormatterFactory: func(previewStrategy common.DryRunStrategy) list.Formatter {
-			return NewFormatter(ioStreams, previewStrategy)
+			return NewFormatterWithCatalog(ioStreams, previewStrategy, catalog)
 		},
 	}
 }
@@ -5,10 +5,12 @@ package json
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
 	"k8s.io/cli-runtime/pkg/genericiooptions"
+	applyerror "sigs.k8s.io/cli-utils/pkg/apply/error"
 	"sigs.k8s.io/cli-utils/pkg/apply/event"
 	"sigs.k8s.io/cli-utils/pkg/common"
 	"sigs.k8s.io/cli-utils/pkg/object"
@@ -54,11 +56,37 @@ func (jf *formatter) FormatApplyEvent(e event.ApplyEvent) error {
 	eventInfo := jf.baseResourceEvent(e.Identifier)
 	if e.Error != nil {
 		eventInfo["error"] = e.Error.Error()
+		var conflictErr *applyerror.ConflictError
+		if errors.As(e.Error, &conflictErr) {
+			eventInfo["conflicts"] = fieldConflicts(conflictErr.Conflicts)
+		}
+	}
+	if len(e.ForcedConflicts) > 0 {
+		eventInfo["forcedConflicts"] = fieldConflicts(e.ForcedConflicts)
+	}
+	if e.Status == event.ApplySkipped {
+		eventInfo["skipReason"] = e.SkipReason.String()
 	}
 	eventInfo["status"] = e.Status.String()
 	return jf.printEvent("apply", eventInfo)
 }
 
+// fieldConflicts returns the field conflicts as maps for printing.
+func fieldConflicts(conflicts []event.FieldConflict) []interface{} {
+	result := make([]interface{}, len(conflicts))
+	for i, conflict := range conflicts {
+		info := map[string]interface{}{
+			"field":   conflict.Field,
+			"manager": conflict.Manager,
+		}
+		if conflict.Value != nil {
+			info["value"] = conflict.Value
+		}
+		result[i] = info
+	}
+	return result
+}
+
 func (jf *formatter) FormatStatusEvent(se event.StatusEvent) error {
 	return jf.printResourceStatus(se)
 }
@@ -75,6 +103,9 @@ func (jf *formatter) FormatPruneEvent(e event.PruneEvent) error {
 	if e.Error != nil {
 		eventInfo["error"] = e.Error.Error()
 	}
+	if e.Status == event.PruneSkipped {
+		eventInfo["skipReason"] = e.SkipReason.String()
+	}
 	eventInfo["status"] = e.Status.String()
 	return jf.printEvent("prune", eventInfo)
 }
@@ -84,6 +115,9 @@ func (jf *formatter) FormatDeleteEvent(e event.DeleteEvent) error {
 	if e.Error != nil {
 		eventInfo["error"] = e.Error.Error()
 	}
+	if e.Status == event.DeleteSkipped {
+		eventInfo["skipReason"] = e.SkipReason.String()
+	}
 	eventInfo["status"] = e.Status.String()
 	return jf.printEvent("delete", eventInfo)
 }
@@ -208,6 +242,17 @@ func (jf *formatter) FormatSummary(s stats.Stats) error {
 			return err
 		}
 	}
+	if !s.ResourceRequests.IsZero() {
+		rr := s.ResourceRequests
+		err := jf.printEvent("resourceRequests", map[string]interface{}{
+			"cpu":     rr.CPU.String(),
+			"memory":  rr.Memory.String(),
+			"storage": rr.Storage.String(),
+		})
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
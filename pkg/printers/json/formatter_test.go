@@ -12,9 +12,11 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	applyerror "sigs.k8s.io/cli-utils/pkg/apply/error"
 	"sigs.k8s.io/cli-utils/pkg/apply/event"
 	"sigs.k8s.io/cli-utils/pkg/common"
 	pollevent "sigs.k8s.io/cli-utils/pkg/kstatus/polling/event"
@@ -107,12 +109,19 @@ func TestFormatter_FormatApplyEvent(t *testing.T) {
 				},
 			},
 		},
-		"resource apply skip error": {
+		"resource apply conflict error": {
 			previewStrategy: common.DryRunNone,
 			event: event.ApplyEvent{
-				Status:     event.ApplySkipped,
+				Status:     event.ApplyFailed,
 				Identifier: createIdentifier("apps", "Deployment", "", "my-dep"),
-				Error:      errors.New("example error"),
+				Error: applyerror.NewConflictError(errors.New("example error"), []event.FieldConflict{
+					{
+						Field:   ".spec.replicas",
+						Message: `conflict with "other-manager"`,
+						Manager: "other-manager",
+						Value:   int64(3),
+					},
+				}),
 			},
 			expected: []map[string]interface{}{
 				{
@@ -120,10 +129,70 @@ func TestFormatter_FormatApplyEvent(t *testing.T) {
 					"kind":      "Deployment",
 					"name":      "my-dep",
 					"namespace": "",
-					"status":    "Skipped",
+					"status":    "Failed",
 					"timestamp": "",
 					"type":      "apply",
 					"error":     "example error",
+					"conflicts": []interface{}{
+						map[string]interface{}{
+							"field":   ".spec.replicas",
+							"manager": "other-manager",
+							"value":   float64(3),
+						},
+					},
+				},
+			},
+		},
+		"resource apply forced conflicts": {
+			previewStrategy: common.DryRunNone,
+			event: event.ApplyEvent{
+				Status:     event.ApplySuccessful,
+				Identifier: createIdentifier("apps", "Deployment", "", "my-dep"),
+				ForcedConflicts: []event.FieldConflict{
+					{
+						Field:   ".spec.replicas",
+						Message: `conflict with "other-manager"`,
+						Manager: "other-manager",
+					},
+				},
+			},
+			expected: []map[string]interface{}{
+				{
+					"group":     "apps",
+					"kind":      "Deployment",
+					"name":      "my-dep",
+					"namespace": "",
+					"status":    "Successful",
+					"timestamp": "",
+					"type":      "apply",
+					"forcedConflicts": []interface{}{
+						map[string]interface{}{
+							"field":   ".spec.replicas",
+							"manager": "other-manager",
+						},
+					},
+				},
+			},
+		},
+		"resource apply skip error": {
+			previewStrategy: common.DryRunNone,
+			event: event.ApplyEvent{
+				Status:     event.ApplySkipped,
+				Identifier: createIdentifier("apps", "Deployment", "", "my-dep"),
+				Error:      errors.New("example error"),
+				SkipReason: event.SkipReasonDependencyPreventedActuation,
+			},
+			expected: []map[string]interface{}{
+				{
+					"group":      "apps",
+					"kind":       "Deployment",
+					"name":       "my-dep",
+					"namespace":  "",
+					"status":     "Skipped",
+					"skipReason": "DependencyPreventedActuation",
+					"timestamp":  "",
+					"type":       "apply",
+					"error":      "example error",
 				},
 			},
 		},
@@ -232,13 +301,14 @@ func TestFormatter_FormatPruneEvent(t *testing.T) {
 				Identifier: createIdentifier("apps", "Deployment", "", "my-dep"),
 			},
 			expected: map[string]interface{}{
-				"group":     "apps",
-				"kind":      "Deployment",
-				"name":      "my-dep",
-				"namespace": "",
-				"status":    "Skipped",
-				"timestamp": "",
-				"type":      "prune",
+				"group":      "apps",
+				"kind":       "Deployment",
+				"name":       "my-dep",
+				"namespace":  "",
+				"status":     "Skipped",
+				"skipReason": "Unspecified",
+				"timestamp":  "",
+				"type":       "prune",
 			},
 		},
 		"resource prune failed": {
@@ -265,16 +335,18 @@ func TestFormatter_FormatPruneEvent(t *testing.T) {
 				Status:     event.PruneSkipped,
 				Identifier: createIdentifier("apps", "Deployment", "", "my-dep"),
 				Error:      errors.New("example error"),
+				SkipReason: event.SkipReasonLocalPolicyPreventedDeletion,
 			},
 			expected: map[string]interface{}{
-				"group":     "apps",
-				"kind":      "Deployment",
-				"name":      "my-dep",
-				"namespace": "",
-				"status":    "Skipped",
-				"timestamp": "",
-				"type":      "prune",
-				"error":     "example error",
+				"group":      "apps",
+				"kind":       "Deployment",
+				"name":       "my-dep",
+				"namespace":  "",
+				"status":     "Skipped",
+				"skipReason": "LocalPolicyPreventedDeletion",
+				"timestamp":  "",
+				"type":       "prune",
+				"error":      "example error",
 			},
 		},
 	}
@@ -321,13 +393,14 @@ func TestFormatter_FormatDeleteEvent(t *testing.T) {
 				Identifier: createIdentifier("apps", "Deployment", "", "my-dep"),
 			},
 			expected: map[string]interface{}{
-				"group":     "apps",
-				"kind":      "Deployment",
-				"name":      "my-dep",
-				"namespace": "",
-				"status":    "Skipped",
-				"timestamp": "",
-				"type":      "delete",
+				"group":      "apps",
+				"kind":       "Deployment",
+				"name":       "my-dep",
+				"namespace":  "",
+				"status":     "Skipped",
+				"skipReason": "Unspecified",
+				"timestamp":  "",
+				"type":       "delete",
 			},
 		},
 		"resource delete failed": {
@@ -354,16 +427,18 @@ func TestFormatter_FormatDeleteEvent(t *testing.T) {
 				Status:     event.DeleteSkipped,
 				Identifier: createIdentifier("apps", "Deployment", "default", "my-dep"),
 				Error:      errors.New("example error"),
+				SkipReason: event.SkipReasonNamespaceInUse,
 			},
 			expected: map[string]interface{}{
-				"group":     "apps",
-				"kind":      "Deployment",
-				"name":      "my-dep",
-				"namespace": "default",
-				"status":    "Skipped",
-				"timestamp": "",
-				"type":      "delete",
-				"error":     "example error",
+				"group":      "apps",
+				"kind":       "Deployment",
+				"name":       "my-dep",
+				"namespace":  "default",
+				"status":     "Skipped",
+				"skipReason": "NamespaceInUse",
+				"timestamp":  "",
+				"type":       "delete",
+				"error":      "example error",
 			},
 		},
 	}
@@ -862,6 +937,35 @@ func TestFormatter_FormatSummary(t *testing.T) {
 				},
 			},
 		},
+		"resource requests": {
+			statsCollector: stats.Stats{
+				ApplyStats: stats.ApplyStats{
+					Successful: 2,
+				},
+				ResourceRequests: object.ResourceRequests{
+					CPU:    resource.MustParse("1500m"),
+					Memory: resource.MustParse("2Gi"),
+				},
+			},
+			expected: []map[string]interface{}{
+				{
+					"action":     "Apply",
+					"count":      float64(2),
+					"successful": float64(2),
+					"skipped":    float64(0),
+					"failed":     float64(0),
+					"timestamp":  nowStr,
+					"type":       "summary",
+				},
+				{
+					"cpu":       "1500m",
+					"memory":    "2Gi",
+					"storage":   "0",
+					"timestamp": nowStr,
+					"type":      "resourceRequests",
+				},
+			},
+		},
 	}
 
 	for tn, tc := range testCases {
@@ -17,15 +17,18 @@ import (
 type ExpEvent struct {
 	EventType event.Type
 
-	InitEvent        *ExpInitEvent
-	ErrorEvent       *ExpErrorEvent
-	ActionGroupEvent *ExpActionGroupEvent
-	ApplyEvent       *ExpApplyEvent
-	StatusEvent      *ExpStatusEvent
-	PruneEvent       *ExpPruneEvent
-	DeleteEvent      *ExpDeleteEvent
-	WaitEvent        *ExpWaitEvent
-	ValidationEvent  *ExpValidationEvent
+	InitEvent          *ExpInitEvent
+	ErrorEvent         *ExpErrorEvent
+	ActionGroupEvent   *ExpActionGroupEvent
+	ApplyEvent         *ExpApplyEvent
+	StatusEvent        *ExpStatusEvent
+	PruneEvent         *ExpPruneEvent
+	DeleteEvent        *ExpDeleteEvent
+	WaitEvent          *ExpWaitEvent
+	ValidationEvent    *ExpValidationEvent
+	RollbackEvent      *ExpRollbackEvent
+	AdoptEvent         *ExpAdoptEvent
+	StuckDeletionEvent *ExpStuckDeletionEvent
 }
 
 type ExpInitEvent struct {
@@ -76,6 +79,26 @@ type ExpWaitEvent struct {
 	Identifier object.ObjMetadata
 }
 
+type ExpRollbackEvent struct {
+	Identifier object.ObjMetadata
+	Status     event.RollbackEventStatus
+	Error      error
+}
+
+type ExpAdoptEvent struct {
+	GroupName     string
+	Identifier    object.ObjMetadata
+	PreviousOwner string
+}
+
+type ExpStuckDeletionEvent struct {
+	GroupName         string
+	Identifier        object.ObjMetadata
+	Finalizers        []string
+	FinalizersRemoved bool
+	Error             error
+}
+
 type ExpValidationEvent struct {
 	Identifiers object.ObjMetadataSet
 	Error       error
@@ -295,6 +318,81 @@ func isMatch(ee ExpEvent, e event.Event) bool {
 		}
 		return ve.Error == nil
 
+	case event.RollbackType:
+		ree := ee.RollbackEvent
+		if ree == nil {
+			return true
+		}
+		re := e.RollbackEvent
+
+		if ree.Identifier != object.NilObjMetadata {
+			if ree.Identifier != re.Identifier {
+				return false
+			}
+		}
+
+		if ree.Status != re.Status {
+			return false
+		}
+
+		if ree.Error != nil {
+			return re.Error != nil
+		}
+		return re.Error == nil
+
+	case event.AdoptType:
+		aee := ee.AdoptEvent
+		if aee == nil {
+			return true
+		}
+		ae := e.AdoptEvent
+
+		if aee.GroupName != "" {
+			if aee.GroupName != ae.GroupName {
+				return false
+			}
+		}
+
+		if aee.Identifier != object.NilObjMetadata {
+			if aee.Identifier != ae.Identifier {
+				return false
+			}
+		}
+
+		return aee.PreviousOwner == ae.PreviousOwner
+
+	case event.StuckDeletionType:
+		see := ee.StuckDeletionEvent
+		if see == nil {
+			return true
+		}
+		se := e.StuckDeletionEvent
+
+		if see.GroupName != "" {
+			if see.GroupName != se.GroupName {
+				return false
+			}
+		}
+
+		if see.Identifier != object.NilObjMetadata {
+			if see.Identifier != se.Identifier {
+				return false
+			}
+		}
+
+		if !cmp.Equal(see.Finalizers, se.Finalizers) {
+			return false
+		}
+
+		if see.FinalizersRemoved != se.FinalizersRemoved {
+			return false
+		}
+
+		if see.Error != nil {
+			return se.Error != nil
+		}
+		return se.Error == nil
+
 	default:
 		return true
 	}
@@ -398,6 +496,38 @@ func EventToExpEvent(e event.Event) ExpEvent {
 				Error:       e.ValidationEvent.Error,
 			},
 		}
+
+	case event.RollbackType:
+		return ExpEvent{
+			EventType: event.RollbackType,
+			RollbackEvent: &ExpRollbackEvent{
+				Identifier: e.RollbackEvent.Identifier,
+				Status:     e.RollbackEvent.Status,
+				Error:      e.RollbackEvent.Error,
+			},
+		}
+
+	case event.AdoptType:
+		return ExpEvent{
+			EventType: event.AdoptType,
+			AdoptEvent: &ExpAdoptEvent{
+				GroupName:     e.AdoptEvent.GroupName,
+				Identifier:    e.AdoptEvent.Identifier,
+				PreviousOwner: e.AdoptEvent.PreviousOwner,
+			},
+		}
+
+	case event.StuckDeletionType:
+		return ExpEvent{
+			EventType: event.StuckDeletionType,
+			StuckDeletionEvent: &ExpStuckDeletionEvent{
+				GroupName:         e.StuckDeletionEvent.GroupName,
+				Identifier:        e.StuckDeletionEvent.Identifier,
+				Finalizers:        e.StuckDeletionEvent.Finalizers,
+				FinalizersRemoved: e.StuckDeletionEvent.FinalizersRemoved,
+				Error:             e.StuckDeletionEvent.Error,
+			},
+		}
 	}
 	return ExpEvent{}
 }
@@ -0,0 +1,155 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package testutil
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+// FaultConfig configures the faults injected by a FaultInjector. Rates are
+// probabilities between 0 and 1.
+type FaultConfig struct {
+	// Seed seeds the random faults, so that tests are deterministic.
+	Seed int64
+	// Latency is added to every request.
+	Latency time.Duration
+	// LatencyJitter is the maximum random latency added to Latency.
+	LatencyJitter time.Duration
+	// ThrottleRate is the rate of requests that fail with 429 Too Many
+	// Requests.
+	ThrottleRate float64
+	// ConflictRate is the rate of write requests (create, update, patch)
+	// that fail with 409 Conflict.
+	ConflictRate float64
+	// NotFoundRate is the rate of read requests (get, list) that fail
+	// with 404 Not Found, even though the object exists.
+	NotFoundRate float64
+}
+
+// FaultInjector injects latency and errors into requests, to test retries,
+// backoff and circuit breaking. The faults are random, but deterministic for
+// a given seed and sequence of requests. It is safe for concurrent use, but
+// concurrent requests make the sequence, and so the faults, nondeterministic.
+type FaultInjector struct {
+	config FaultConfig
+
+	mu     sync.Mutex
+	rand   *rand.Rand
+	counts map[string]int
+}
+
+// NewFaultInjector returns a FaultInjector with the given configuration.
+func NewFaultInjector(config FaultConfig) *FaultInjector {
+	return &FaultInjector{
+		config: config,
+		rand:   rand.New(rand.NewSource(config.Seed)), //nolint:gosec
+		counts: make(map[string]int),
+	}
+}
+
+// Inject waits for the configured latency, and returns the error injected
+// into the request with the given verb, like "get" or "patch", or nil.
+// Returns the context error if the context is done while waiting.
+func (f *FaultInjector) Inject(ctx context.Context, verb string, gr schema.GroupResource, name string) error {
+	latency, err := f.next(verb, gr, name)
+	if latency > 0 {
+		timer := time.NewTimer(latency)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return err
+}
+
+// Count returns the number of injected errors with the reason, like
+// metav1.StatusReasonTooManyRequests.
+func (f *FaultInjector) Count(reason string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.counts[reason]
+}
+
+// Reactor returns a reaction function that injects the faults into the
+// requests of a fake client, like the fake dynamic client:
+//
+//	client.PrependReactor("*", "*", injector.Reactor())
+//
+// Requests without injected errors are handled by the next reactors.
+func (f *FaultInjector) Reactor() clienttesting.ReactionFunc {
+	return func(action clienttesting.Action) (bool, runtime.Object, error) {
+		var name string
+		if named, ok := action.(interface{ GetName() string }); ok {
+			name = named.GetName()
+		}
+		err := f.Inject(context.Background(), action.GetVerb(), action.GetResource().GroupResource(), name)
+		if err != nil {
+			return true, nil, err
+		}
+		return false, nil, nil
+	}
+}
+
+// next returns the latency and error of the next request.
+func (f *FaultInjector) next(verb string, gr schema.GroupResource, name string) (time.Duration, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	latency := f.config.Latency
+	if f.config.LatencyJitter > 0 {
+		latency += time.Duration(f.rand.Int63n(int64(f.config.LatencyJitter)))
+	}
+	// Always draw the same number of random numbers per request, so that
+	// changing one rate does not change the faults of the others.
+	throttle := f.rand.Float64() < f.config.ThrottleRate
+	conflict := f.rand.Float64() < f.config.ConflictRate
+	notFound := f.rand.Float64() < f.config.NotFoundRate
+
+	var err *apierrors.StatusError
+	switch {
+	case throttle:
+		err = apierrors.NewTooManyRequests("injected fault", 1)
+	case conflict && isWriteVerb(verb):
+		err = apierrors.NewConflict(gr, name, errInjected)
+	case notFound && isReadVerb(verb):
+		err = apierrors.NewNotFound(gr, name)
+	default:
+		return latency, nil
+	}
+	f.counts[string(err.ErrStatus.Reason)]++
+	return latency, err
+}
+
+var errInjected = &injectedError{}
+
+type injectedError struct{}
+
+func (*injectedError) Error() string {
+	return "injected fault"
+}
+
+func isWriteVerb(verb string) bool {
+	switch verb {
+	case "create", "update", "patch":
+		return true
+	}
+	return false
+}
+
+func isReadVerb(verb string) bool {
+	switch verb {
+	case "get", "list":
+		return true
+	}
+	return false
+}
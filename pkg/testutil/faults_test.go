@@ -0,0 +1,86 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package testutil
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+var podGR = schema.GroupResource{Resource: "pods"}
+
+func TestFaultInjector_Deterministic(t *testing.T) {
+	config := FaultConfig{
+		Seed:         42,
+		ThrottleRate: 0.2,
+		ConflictRate: 0.3,
+		NotFoundRate: 0.3,
+	}
+	run := func() []string {
+		injector := NewFaultInjector(config)
+		var reasons []string
+		for i := 0; i < 100; i++ {
+			verb := "get"
+			if i%2 == 0 {
+				verb = "patch"
+			}
+			err := injector.Inject(context.Background(), verb, podGR, "foo")
+			reasons = append(reasons, string(apierrors.ReasonForError(err)))
+		}
+		return reasons
+	}
+	first := run()
+	assert.Equal(t, first, run())
+	assert.Contains(t, first, string(metav1.StatusReasonTooManyRequests))
+	assert.Contains(t, first, string(metav1.StatusReasonConflict))
+	assert.Contains(t, first, string(metav1.StatusReasonNotFound))
+	assert.Contains(t, first, string(metav1.StatusReasonUnknown))
+}
+
+func TestFaultInjector_Verbs(t *testing.T) {
+	injector := NewFaultInjector(FaultConfig{
+		ConflictRate: 1,
+		NotFoundRate: 1,
+	})
+	ctx := context.Background()
+
+	assert.True(t, apierrors.IsConflict(injector.Inject(ctx, "patch", podGR, "foo")))
+	assert.True(t, apierrors.IsNotFound(injector.Inject(ctx, "get", podGR, "foo")))
+	assert.NoError(t, injector.Inject(ctx, "delete", podGR, "foo"))
+	assert.Equal(t, 1, injector.Count(string(metav1.StatusReasonConflict)))
+	assert.Equal(t, 1, injector.Count(string(metav1.StatusReasonNotFound)))
+}
+
+func TestFaultInjector_Reactor(t *testing.T) {
+	pod := &unstructured.Unstructured{}
+	pod.SetAPIVersion("v1")
+	pod.SetKind("Pod")
+	pod.SetName("foo")
+	pod.SetNamespace("default")
+	dc := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), pod)
+	injector := NewFaultInjector(FaultConfig{ThrottleRate: 1})
+	dc.PrependReactor("*", "*", injector.Reactor())
+
+	gvr := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	_, err := dc.Resource(gvr).Namespace("default").Get(context.Background(), "foo", metav1.GetOptions{})
+	require.Error(t, err)
+	assert.True(t, apierrors.IsTooManyRequests(err))
+	assert.Equal(t, 1, injector.Count(string(metav1.StatusReasonTooManyRequests)))
+
+	// Without faults, the requests are handled by the fake client.
+	dc = dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), pod)
+	dc.PrependReactor("*", "*", NewFaultInjector(FaultConfig{}).Reactor())
+	obj, err := dc.Resource(gvr).Namespace("default").Get(context.Background(), "foo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "foo", obj.GetName())
+}
@@ -0,0 +1,98 @@
+// Copyright 2024 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package stress
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2" //nolint:revive
+	. "github.com/onsi/gomega"    //nolint:revive
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/event"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/watcher"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/cli-utils/test/e2e/e2eutil"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Ex: ginkgo ./test/stress/... -- -soak-duration=168h
+var soakDuration = flag.Duration("soak-duration", 2*time.Minute,
+	"how long the status watcher soak test watches objects")
+
+// soakWatchTest watches 100 ConfigMaps for the soak duration, updating them
+// every second, and checks that the resource usage reported by the
+// StatusWatcher stays stable: the watch must not leak informers, goroutines
+// or memory.
+func soakWatchTest(ctx context.Context, c client.Client, namespaceName string) {
+	By("Create the ConfigMaps")
+	objectCount := 100
+	configMapObjTemplate := e2eutil.ManifestToUnstructured([]byte(configMapYaml))
+	var ids object.ObjMetadataSet
+	for i := 1; i <= objectCount; i++ {
+		configMapObj := configMapObjTemplate.DeepCopy()
+		configMapObj.SetName(fmt.Sprintf("soak-%d", i))
+		configMapObj.SetNamespace(namespaceName)
+		Expect(c.Create(ctx, configMapObj)).To(Succeed())
+		ids = append(ids, object.UnstructuredToObjMetadata(configMapObj))
+	}
+
+	cfg, err := ctrl.GetConfig()
+	Expect(err).NotTo(HaveOccurred())
+	dynamicClient, err := dynamic.NewForConfig(cfg)
+	Expect(err).NotTo(HaveOccurred())
+	statusWatcher := watcher.NewDefaultStatusWatcher(dynamicClient, c.RESTMapper())
+
+	By(fmt.Sprintf("Watch the ConfigMaps for %v", *soakDuration))
+	watchCtx, cancel := context.WithTimeout(ctx, *soakDuration)
+	defer cancel()
+	eventCh := statusWatcher.Watch(watchCtx, ids, watcher.Options{
+		StatsInterval: 10 * time.Second,
+	})
+
+	// Update a ConfigMap every second, to keep the informer busy.
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for i := 0; ; i++ {
+			select {
+			case <-watchCtx.Done():
+				return
+			case <-ticker.C:
+			}
+			configMapObj := configMapObjTemplate.DeepCopy()
+			configMapObj.SetName(ids[i%objectCount].Name)
+			configMapObj.SetNamespace(namespaceName)
+			configMapObj.Object["data"] = map[string]interface{}{"update": fmt.Sprint(i)}
+			if err := c.Update(watchCtx, configMapObj); err != nil && watchCtx.Err() == nil {
+				klog.Warningf("failed to update ConfigMap: %v", err)
+			}
+		}
+	}()
+
+	var stats []event.WatchStats
+	for e := range eventCh {
+		Expect(e.Type).NotTo(Equal(event.ErrorEvent), "unexpected error: %v", e.Error)
+		if e.Type == event.StatsEvent {
+			klog.V(3).Infof("Watch stats: %+v", *e.Stats)
+			stats = append(stats, *e.Stats)
+		}
+	}
+	Expect(ctx.Err()).To(BeNil(), "test context cancelled or timed out")
+
+	By("Verify the resource usage of the watch is stable")
+	Expect(len(stats)).To(BeNumerically(">=", 2), "not enough stats events")
+	first, last := stats[0], stats[len(stats)-1]
+	for _, s := range stats {
+		// One namespace-scoped informer for the ConfigMaps.
+		Expect(s.Informers).To(Equal(1))
+	}
+	Expect(last.Goroutines).To(BeNumerically("<=", first.Goroutines+10), "goroutines leaked")
+	// Allow for garbage not collected yet.
+	Expect(last.HeapAlloc).To(BeNumerically("<=", 2*first.HeapAlloc+64<<20), "memory leaked")
+}
@@ -89,7 +89,8 @@ var _ = Describe("Stress", func() {
 	var cancel context.CancelFunc
 
 	BeforeEach(func() {
-		ctx, cancel = context.WithTimeout(context.Background(), defaultTestTimeout)
+		// Leave time for the soak test, which can be longer than the timeout.
+		ctx, cancel = context.WithTimeout(context.Background(), defaultTestTimeout+*soakDuration)
 		inventoryName = e2eutil.RandomString("test-inv-")
 		namespace = e2eutil.CreateRandomNamespace(ctx, c)
 	})
@@ -114,4 +115,8 @@ var _ = Describe("Stress", func() {
 	It("ThousandNamespaces", func() {
 		thousandNamespacesTest(ctx, c, invConfig, inventoryName, namespace.GetName())
 	})
+
+	It("SoakWatch", func() {
+		soakWatchTest(ctx, c, namespace.GetName())
+	})
 })